@@ -77,6 +77,7 @@ func (e *ECSEnvironmentManager) populateModel(cluster *ecs.Cluster) (*models.Env
 	ecsEnvironmentID := id.ECSEnvironmentID(*cluster.ClusterName)
 
 	var clusterCount int
+	var minClusterCount int
 	var instanceSize string
 	var amiID string
 
@@ -91,6 +92,7 @@ func (e *ECSEnvironmentManager) populateModel(cluster *ecs.Cluster) (*models.Env
 
 	if asg != nil {
 		clusterCount = len(asg.Instances)
+		minClusterCount = int(pint64(asg.MinSize))
 
 		if asg.LaunchConfigurationName != nil {
 			launchConfig, err := e.AutoScaling.DescribeLaunchConfiguration(*asg.LaunchConfigurationName)
@@ -122,6 +124,7 @@ func (e *ECSEnvironmentManager) populateModel(cluster *ecs.Cluster) (*models.Env
 	model := &models.Environment{
 		EnvironmentID:   ecsEnvironmentID.L0EnvironmentID(),
 		ClusterCount:    clusterCount,
+		MinClusterCount: minClusterCount,
 		InstanceSize:    instanceSize,
 		SecurityGroupID: securityGroupID,
 		AMIID:           amiID,
@@ -11,12 +11,15 @@ import (
 	"github.com/quintilesims/layer0/common/aws/ec2"
 	"github.com/quintilesims/layer0/common/aws/ecs"
 	"github.com/quintilesims/layer0/common/logutils"
+	"github.com/quintilesims/layer0/common/waitutils"
 	"github.com/zpatrick/go-bytesize"
+	"time"
 )
 
 type ECSResourceManager struct {
 	ECS         ecs.Provider
 	Autoscaling autoscaling.Provider
+	Clock       waitutils.Clock
 	logger      *logrus.Logger
 }
 
@@ -24,6 +27,7 @@ func NewECSResourceManager(e ecs.Provider, a autoscaling.Provider) *ECSResourceM
 	return &ECSResourceManager{
 		ECS:         e,
 		Autoscaling: a,
+		Clock:       waitutils.RealClock{},
 		logger:      logutils.NewStandardLogger("ECS Resource Manager").Logger,
 	}
 }
@@ -69,12 +73,16 @@ func (r *ECSResourceManager) getResourceProvider(ecsEnvironmentID id.ECSEnvironm
 	// instance.ReminaingResources, not instance.RegisteredResources
 	var usedPorts []int
 	var availableMemory bytesize.Bytesize
+	var availableCPU int
 	for _, resource := range instance.RemainingResources {
 		switch pstring(resource.Name) {
 		case "MEMORY":
 			v := pint64(resource.IntegerValue)
 			availableMemory = bytesize.MiB * bytesize.Bytesize(v)
 
+		case "CPU":
+			availableCPU = int(pint64(resource.IntegerValue))
+
 		case "PORTS":
 			for _, p := range resource.StringSetValue {
 				port, err := strconv.Atoi(pstring(p))
@@ -89,7 +97,14 @@ func (r *ECSResourceManager) getResourceProvider(ecsEnvironmentID id.ECSEnvironm
 	}
 
 	inUse := pint64(instance.PendingTasksCount)+pint64(instance.RunningTasksCount) > 0
-	provider := resource.NewResourceProvider(instanceID, inUse, availableMemory, usedPorts)
+	provider := resource.NewResourceProvider(instanceID, inUse, availableMemory, availableCPU, usedPorts)
+
+	// the ecs agent registers the instance's availability zone as an attribute
+	for _, attribute := range instance.Attributes {
+		if pstring(attribute.Name) == "ecs.availability-zone" {
+			provider.AvailabilityZone = pstring(attribute.Value)
+		}
+	}
 
 	r.logger.Debugf("Environment '%s' generated provider: %#v\n", ecsEnvironmentID, provider)
 	return provider, true
@@ -112,6 +127,11 @@ func (r *ECSResourceManager) CalculateNewProvider(environmentID string) (*resour
 		return nil, fmt.Errorf("Environment %s is using unknown instance type '%s'", environmentID, pstring(config.InstanceType))
 	}
 
+	cpu, ok := ec2.InstanceCPUs[pstring(config.InstanceType)]
+	if !ok {
+		return nil, fmt.Errorf("Environment %s is using unknown instance type '%s'", environmentID, pstring(config.InstanceType))
+	}
+
 	// these ports are automatically used by the ecs agent
 	defaultPorts := []int{
 		22,
@@ -121,28 +141,45 @@ func (r *ECSResourceManager) CalculateNewProvider(environmentID string) (*resour
 		51679,
 	}
 
-	return resource.NewResourceProvider("<new instance>", false, memory, defaultPorts), nil
+	return resource.NewResourceProvider("<new instance>", false, memory, cpu, defaultPorts), nil
 }
 
-func (r *ECSResourceManager) ScaleTo(environmentID string, scale int, unusedProviders []*resource.ResourceProvider) (int, error) {
+// ScaleTo returns the size of the environment after scaling and the ids of the instances it terminated
+func (r *ECSResourceManager) ScaleTo(environmentID string, scale int, unusedProviders []*resource.ResourceProvider, settings resource.ScalerSettings) (int, []string, error) {
 	ecsEnvironmentID := id.L0EnvironmentID(environmentID).ECSEnvironmentID()
 	asg, err := r.Autoscaling.DescribeAutoScalingGroup(ecsEnvironmentID.String())
 	if err != nil {
-		return 0, err
+		return 0, nil, err
 	}
 
 	currentCapacity := int(pint64(asg.DesiredCapacity))
 
+	if settings.HasMaxClusterCount() && scale > settings.MaxClusterCount {
+		r.logger.Warnf("Scale %d is above the max cluster count of %d. Setting desired capacity to %d.", scale, settings.MaxClusterCount, settings.MaxClusterCount)
+		scale = settings.MaxClusterCount
+	}
+
 	switch {
 	case scale > currentCapacity:
 		r.logger.Debugf("Environment %s is attempting to scale up to size %d", ecsEnvironmentID, scale)
-		return r.scaleUp(ecsEnvironmentID, scale, asg)
+		scale, err := r.scaleUp(ecsEnvironmentID, scale, asg)
+		return scale, nil, err
 	case scale < currentCapacity:
+		remaining, err := r.cooldownRemaining(pstring(asg.AutoScalingGroupName), settings.ScaleDownCooldown)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if remaining > 0 {
+			r.logger.Debugf("Environment %s is in its scale down cooldown for %v. No scaling action taken.", ecsEnvironmentID, remaining)
+			return currentCapacity, nil, nil
+		}
+
 		r.logger.Debugf("Environment %s is attempting to scale down to size %d", ecsEnvironmentID, scale)
 		return r.scaleDown(ecsEnvironmentID, scale, asg, unusedProviders)
 	default:
 		r.logger.Debugf("Environment %s is at desired scale of %d. No scaling action required.", ecsEnvironmentID, scale)
-		return currentCapacity, nil
+		return currentCapacity, nil, nil
 	}
 }
 
@@ -161,7 +198,7 @@ func (r *ECSResourceManager) scaleUp(ecsEnvironmentID id.ECSEnvironmentID, scale
 	return scale, nil
 }
 
-func (r *ECSResourceManager) scaleDown(ecsEnvironmentID id.ECSEnvironmentID, scale int, asg *autoscaling.Group, unusedProviders []*resource.ResourceProvider) (int, error) {
+func (r *ECSResourceManager) scaleDown(ecsEnvironmentID id.ECSEnvironmentID, scale int, asg *autoscaling.Group, unusedProviders []*resource.ResourceProvider) (int, []string, error) {
 	minCapacity := int(pint64(asg.MinSize))
 	if scale < minCapacity {
 		r.logger.Warnf("Scale %d is below the minimum capacity of %d. Setting desired capacity to %d.", scale, minCapacity, minCapacity)
@@ -171,12 +208,12 @@ func (r *ECSResourceManager) scaleDown(ecsEnvironmentID id.ECSEnvironmentID, sca
 	currentCapacity := int(pint64(asg.DesiredCapacity))
 	if scale == currentCapacity {
 		r.logger.Debugf("Environment %s is at desired scale of %d. No scaling action required.", ecsEnvironmentID, scale)
-		return scale, nil
+		return scale, nil, nil
 	}
 
 	if scale < currentCapacity {
 		if err := r.Autoscaling.SetDesiredCapacity(pstring(asg.AutoScalingGroupName), scale); err != nil {
-			return 0, err
+			return 0, nil, err
 		}
 	}
 
@@ -197,14 +234,47 @@ func (r *ECSResourceManager) scaleDown(ecsEnvironmentID id.ECSEnvironmentID, sca
 		return true
 	}
 
+	terminated := []string{}
 	for i := 0; canTerminate(i); i++ {
 		unusedProvider := unusedProviders[i]
 		r.logger.Debugf("Environment %s terminating unused instance '%s'", ecsEnvironmentID, unusedProvider.ID)
 
 		if _, err := r.Autoscaling.TerminateInstanceInAutoScalingGroup(unusedProvider.ID, false); err != nil {
-			return 0, err
+			return 0, terminated, err
 		}
+
+		terminated = append(terminated, unusedProvider.ID)
 	}
 
-	return scale, nil
+	return scale, terminated, nil
+}
+
+func (r *ECSResourceManager) ScaleDownCooldownRemaining(environmentID string, cooldown time.Duration) (time.Duration, error) {
+	ecsEnvironmentID := id.L0EnvironmentID(environmentID).ECSEnvironmentID()
+	return r.cooldownRemaining(ecsEnvironmentID.String(), cooldown)
+}
+
+// cooldownRemaining returns how long the environment must wait before it can be scaled down.
+// The cooldown starts when the group's most recent scaling activity ended, rather than when this
+// replica last scaled the group, so it holds across replicas and restarts of the api
+func (r *ECSResourceManager) cooldownRemaining(autoScalingGroupName string, cooldown time.Duration) (time.Duration, error) {
+	if cooldown <= 0 {
+		return 0, nil
+	}
+
+	activities, err := r.Autoscaling.DescribeScalingActivities(autoScalingGroupName, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(activities) == 0 {
+		return 0, nil
+	}
+
+	// an activity that is still in progress has no end time, so the whole cooldown remains
+	if activities[0].EndTime == nil {
+		return cooldown, nil
+	}
+
+	return cooldown - r.Clock.Since(*activities[0].EndTime), nil
 }
@@ -2,6 +2,7 @@ package ecsbackend
 
 import (
 	"testing"
+	"time"
 
 	awsasg "github.com/aws/aws-sdk-go/service/autoscaling"
 	awsecs "github.com/aws/aws-sdk-go/service/ecs"
@@ -53,11 +54,21 @@ func TestResourceManager_GetProviders(t *testing.T) {
 				AgentConnected:    boolp(true),
 				RunningTasksCount: int64p(1),
 				PendingTasksCount: int64p(1),
+				Attributes: []*awsecs.Attribute{
+					{
+						Name:  stringp("ecs.availability-zone"),
+						Value: stringp("us-west-2a"),
+					},
+				},
 				RemainingResources: []*awsecs.Resource{
 					{
 						Name:         stringp("MEMORY"),
 						IntegerValue: int64p(500),
 					},
+					{
+						Name:         stringp("CPU"),
+						IntegerValue: int64p(512),
+					},
 					{
 						Name: stringp("PORTS"),
 						StringSetValue: []*string{
@@ -79,6 +90,10 @@ func TestResourceManager_GetProviders(t *testing.T) {
 						Name:         stringp("MEMORY"),
 						IntegerValue: int64p(1000),
 					},
+					{
+						Name:         stringp("CPU"),
+						IntegerValue: int64p(2048),
+					},
 					{
 						Name: stringp("PORTS"),
 						StringSetValue: []*string{
@@ -112,10 +127,12 @@ func TestResourceManager_GetProviders(t *testing.T) {
 	}
 
 	expected := []*resource.ResourceProvider{
-		resource.NewResourceProvider("", true, bytesize.MiB*500, []int{80, 8000}),
-		resource.NewResourceProvider("", false, bytesize.MiB*1000, []int{80}),
+		resource.NewResourceProvider("", true, bytesize.MiB*500, 512, []int{80, 8000}),
+		resource.NewResourceProvider("", false, bytesize.MiB*1000, 2048, []int{80}),
 	}
 
+	expected[0].AvailabilityZone = "us-west-2a"
+
 	testutils.AssertEqual(t, expected, providers)
 }
 
@@ -167,7 +184,7 @@ func TestResourceManager_scaleDown(t *testing.T) {
 		},
 	}
 
-	scale, err := rm.ResourceManager().scaleDown(environmentID.ECSEnvironmentID(), 0, asg, nil)
+	scale, _, err := rm.ResourceManager().scaleDown(environmentID.ECSEnvironmentID(), 0, asg, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -194,10 +211,176 @@ func TestResourceManager_scaleDownStayAboveMin(t *testing.T) {
 		},
 	}
 
-	scale, err := rm.ResourceManager().scaleDown(environmentID.ECSEnvironmentID(), 0, asg, nil)
+	scale, _, err := rm.ResourceManager().scaleDown(environmentID.ECSEnvironmentID(), 0, asg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, scale, 1)
+}
+
+func TestResourceManager_scaleDownTerminatesUnusedProviders(t *testing.T) {
+	rm, ctrl := newMockResourceManager(t)
+	defer ctrl.Finish()
+
+	environmentID := id.L0EnvironmentID("eid")
+
+	rm.Autoscaling.EXPECT().
+		SetDesiredCapacity("asg_name", 1).
+		Return(nil)
+
+	rm.Autoscaling.EXPECT().
+		TerminateInstanceInAutoScalingGroup("i1", false).
+		Return(nil, nil)
+
+	rm.Autoscaling.EXPECT().
+		TerminateInstanceInAutoScalingGroup("i2", false).
+		Return(nil, nil)
+
+	asg := &autoscaling.Group{
+		Group: &awsasg.Group{
+			AutoScalingGroupName: stringp("asg_name"),
+			MaxSize:              int64p(3),
+			MinSize:              int64p(0),
+			DesiredCapacity:      int64p(3),
+		},
+	}
+
+	unusedProviders := []*resource.ResourceProvider{
+		resource.NewResourceProvider("i1", false, bytesize.GB, 1024, nil),
+		resource.NewResourceProvider("i2", false, bytesize.GB, 1024, nil),
+		resource.NewResourceProvider("i3", false, bytesize.GB, 1024, nil),
+	}
+
+	scale, terminated, err := rm.ResourceManager().scaleDown(environmentID.ECSEnvironmentID(), 1, asg, unusedProviders)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	testutils.AssertEqual(t, scale, 1)
+	testutils.AssertEqual(t, terminated, []string{"i1", "i2"})
+}
+
+func TestResourceManager_scaleToMaxClusterCount(t *testing.T) {
+	rm, ctrl := newMockResourceManager(t)
+	defer ctrl.Finish()
+
+	environmentID := id.L0EnvironmentID("eid")
+
+	asg := &autoscaling.Group{
+		Group: &awsasg.Group{
+			AutoScalingGroupName: stringp("asg_name"),
+			MaxSize:              int64p(5),
+			MinSize:              int64p(0),
+			DesiredCapacity:      int64p(1),
+		},
+	}
+
+	rm.Autoscaling.EXPECT().
+		DescribeAutoScalingGroup(environmentID.ECSEnvironmentID().String()).
+		Return(asg, nil)
+
+	rm.Autoscaling.EXPECT().
+		SetDesiredCapacity("asg_name", 3).
+		Return(nil)
+
+	settings := resource.ScalerSettings{MaxClusterCount: 3}
+	scale, _, err := rm.ResourceManager().ScaleTo("eid", 5, nil, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, scale, 3)
+}
+
+func TestResourceManager_scaleDownCooldown(t *testing.T) {
+	rm, ctrl := newMockResourceManager(t)
+	defer ctrl.Finish()
+
+	environmentID := id.L0EnvironmentID("eid")
+
+	asg := &autoscaling.Group{
+		Group: &awsasg.Group{
+			AutoScalingGroupName: stringp("asg_name"),
+			MaxSize:              int64p(5),
+			MinSize:              int64p(0),
+			DesiredCapacity:      int64p(3),
+		},
+	}
+
+	rm.Autoscaling.EXPECT().
+		DescribeAutoScalingGroup(environmentID.ECSEnvironmentID().String()).
+		Return(asg, nil).
+		Times(2)
+
+	clock := &testutils.StubClock{Time: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)}
+	endTime := clock.Time.Add(-time.Minute)
+	lastActivity := &autoscaling.Activity{
+		Activity: &awsasg.Activity{EndTime: &endTime},
+	}
+
+	rm.Autoscaling.EXPECT().
+		DescribeScalingActivities("asg_name", 1).
+		Return([]*autoscaling.Activity{lastActivity}, nil).
+		Times(2)
+
+	// the group was last scaled a minute ago, so it is only scaled down once the cooldown has passed
+	rm.Autoscaling.EXPECT().
+		SetDesiredCapacity("asg_name", 1).
+		Return(nil)
+
+	manager := rm.ResourceManager()
+	manager.Clock = clock
+
+	settings := resource.ScalerSettings{ScaleDownCooldown: time.Minute * 5}
+	scale, _, err := manager.ScaleTo("eid", 1, nil, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, scale, 3)
+
+	clock.Sleep(time.Minute * 4)
+	scale, _, err = manager.ScaleTo("eid", 1, nil, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, scale, 1)
+}
+
+func TestResourceManager_scaleDownCooldownRemaining(t *testing.T) {
+	rm, ctrl := newMockResourceManager(t)
+	defer ctrl.Finish()
+
+	asgName := id.L0EnvironmentID("eid").ECSEnvironmentID().String()
+	clock := &testutils.StubClock{Time: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	// an activity that is still in progress hasn't started the cooldown yet
+	gomock.InOrder(
+		rm.Autoscaling.EXPECT().
+			DescribeScalingActivities(asgName, 1).
+			Return([]*autoscaling.Activity{{Activity: &awsasg.Activity{}}}, nil),
+		rm.Autoscaling.EXPECT().
+			DescribeScalingActivities(asgName, 1).
+			Return(nil, nil),
+	)
+
+	manager := rm.ResourceManager()
+	manager.Clock = clock
+
+	remaining, err := manager.ScaleDownCooldownRemaining("eid", time.Minute*5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, remaining, time.Minute*5)
+
+	// a group that has never been scaled has no cooldown
+	remaining, err = manager.ScaleDownCooldownRemaining("eid", time.Minute*5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, remaining, time.Duration(0))
 }
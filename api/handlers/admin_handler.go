@@ -3,7 +3,6 @@ package handlers
 import (
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/emicklei/go-restful"
 	"github.com/quintilesims/layer0/api/logic"
@@ -43,11 +42,71 @@ func (this AdminHandler) Routes() *restful.WebService {
 		Param(id).
 		Doc("Run resource manager on an environment"))
 
+	service.Route(service.POST("/scale/{id}/simulate").
+		Filter(basicAuthenticate).
+		To(this.SimulateEnvironmentScaler).
+		Reads(models.ScalerSimulationRequest{}).
+		Param(id).
+		Doc("Simulate the resource manager on an environment without changing its capacity").
+		Writes(models.ScalerRunInfo{}))
+
 	service.Route(service.GET("/config").
 		To(this.GetConfig).
 		Doc("Returns Configuration of the API Server").
 		Writes(models.APIConfig{}))
 
+	service.Route(service.GET("/backup").
+		Filter(basicAuthenticate).
+		To(this.GetBackup).
+		Doc("Export the tags, jobs and config of the API as a backup").
+		Writes(models.Backup{}))
+
+	service.Route(service.POST("/backup").
+		Filter(basicAuthenticate).
+		To(this.SaveBackup).
+		Doc("Save a backup to the layer0 s3 bucket").
+		Returns(http.StatusCreated, "Created", models.BackupSummary{}))
+
+	service.Route(service.POST("/restore").
+		Filter(basicAuthenticate).
+		To(this.Restore).
+		Reads(models.RestoreRequest{}).
+		Doc("Restore the tags and jobs of a backup and check them against the live AWS resources").
+		Notes("Tags and jobs that already exist are left unchanged, so a backup can be restored more than once. "+
+			"Tags whose value differs from the backup are reported as conflicts, and only overwritten if 'overwrite' is set. "+
+			"The config of the backup is export-only and is not restored").
+		Returns(http.StatusOK, "OK", models.RestoreReport{}).
+		Returns(http.StatusBadRequest, "Invalid backup", models.ServerError{}))
+
+	service.Route(service.GET("/import").
+		Filter(basicAuthenticate).
+		To(this.GetImportPreview).
+		Doc("List the tags that would be written to adopt layer0 resources that are missing tags").
+		Notes("The names of environments, load balancers and services can't be inferred from their ids, so they are listed in the warnings instead").
+		Writes(models.ImportReport{}))
+
+	service.Route(service.POST("/import").
+		Filter(basicAuthenticate).
+		To(this.ImportEntities).
+		Reads(models.ImportRequest{}).
+		Doc("Write the missing tags of layer0 resources, inferring their relationships").
+		Notes("Existing tags are never changed. "+
+			"If 'entities' is set to the entities of a preview, nothing is written unless the import would still write exactly the same tags").
+		Returns(http.StatusOK, "OK", models.ImportReport{}).
+		Returns(http.StatusConflict, "The tags to import changed since the preview", models.ServerError{}))
+
+	service.Route(service.GET("/reconcile").
+		Filter(basicAuthenticate).
+		To(this.GetTagReconciliationReport).
+		Doc("List the tags of entities that no longer exist, without deleting them").
+		Writes(models.TagReconciliationReport{}))
+
+	service.Route(service.POST("/reconcile").
+		Filter(basicAuthenticate).
+		To(this.ReconcileTags).
+		Doc("Delete the tags of entities that no longer exist").
+		Returns(http.StatusOK, "OK", models.TagReconciliationReport{}))
+
 	service.Route(service.POST("/sql").
 		Filter(basicAuthenticate).
 		To(this.UpdateSQL).
@@ -63,24 +122,87 @@ func (this *AdminHandler) GetVersion(request *restful.Request, response *restful
 }
 
 func (this *AdminHandler) GetConfig(request *restful.Request, response *restful.Response) {
-	publicSubnets := []string{}
-	for _, subnet := range strings.Split(config.AWSPublicSubnets(), ",") {
-		publicSubnets = append(publicSubnets, subnet)
+	response.WriteAsJson(this.AdminLogic.GetConfig())
+}
+
+func (this *AdminHandler) GetBackup(request *restful.Request, response *restful.Response) {
+	backup, err := this.AdminLogic.Backup()
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	response.WriteAsJson(backup)
+}
+
+func (this *AdminHandler) SaveBackup(request *restful.Request, response *restful.Response) {
+	summary, err := this.AdminLogic.SaveBackup()
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusCreated)
+	response.WriteAsJson(summary)
+}
+
+func (this *AdminHandler) Restore(request *restful.Request, response *restful.Response) {
+	var req models.RestoreRequest
+	if err := request.ReadEntity(&req); err != nil {
+		BadRequest(response, errors.InvalidJSON, err)
+		return
+	}
+
+	report, err := this.AdminLogic.Restore(req)
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	response.WriteAsJson(report)
+}
+
+func (this *AdminHandler) GetImportPreview(request *restful.Request, response *restful.Response) {
+	this.importEntities(response, models.ImportRequest{DryRun: true})
+}
+
+func (this *AdminHandler) ImportEntities(request *restful.Request, response *restful.Response) {
+	var req models.ImportRequest
+	if err := request.ReadEntity(&req); err != nil {
+		BadRequest(response, errors.InvalidJSON, err)
+		return
 	}
 
-	privateSubnets := []string{}
-	for _, subnet := range strings.Split(config.AWSPrivateSubnets(), ",") {
-		privateSubnets = append(privateSubnets, subnet)
+	req.DryRun = false
+	this.importEntities(response, req)
+}
+
+func (this *AdminHandler) importEntities(response *restful.Response, req models.ImportRequest) {
+	report, err := this.AdminLogic.ImportEntities(req)
+	if err != nil {
+		ReturnError(response, err)
+		return
 	}
 
-	model := models.APIConfig{
-		Prefix:         config.Prefix(),
-		VPCID:          config.AWSVPCID(),
-		PublicSubnets:  publicSubnets,
-		PrivateSubnets: privateSubnets,
+	response.WriteAsJson(report)
+}
+
+func (this *AdminHandler) GetTagReconciliationReport(request *restful.Request, response *restful.Response) {
+	this.reconcileTags(response, true)
+}
+
+func (this *AdminHandler) ReconcileTags(request *restful.Request, response *restful.Response) {
+	this.reconcileTags(response, false)
+}
+
+func (this *AdminHandler) reconcileTags(response *restful.Response, dryRun bool) {
+	report, err := this.AdminLogic.ReconcileTags(dryRun)
+	if err != nil {
+		ReturnError(response, err)
+		return
 	}
 
-	response.WriteAsJson(model)
+	response.WriteAsJson(report)
 }
 
 func (this *AdminHandler) RunEnvironmentScaler(request *restful.Request, response *restful.Response) {
@@ -100,6 +222,29 @@ func (this *AdminHandler) RunEnvironmentScaler(request *restful.Request, respons
 	response.WriteAsJson(info)
 }
 
+func (this *AdminHandler) SimulateEnvironmentScaler(request *restful.Request, response *restful.Response) {
+	id := request.PathParameter("id")
+	if id == "" {
+		err := fmt.Errorf("Parameter 'id' is required")
+		BadRequest(response, errors.MissingParameter, err)
+		return
+	}
+
+	var req models.ScalerSimulationRequest
+	if err := request.ReadEntity(&req); err != nil {
+		BadRequest(response, errors.InvalidJSON, err)
+		return
+	}
+
+	info, err := this.AdminLogic.SimulateEnvironmentScaler(id, req)
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	response.WriteAsJson(info)
+}
+
 func (this *AdminHandler) UpdateSQL(request *restful.Request, response *restful.Response) {
 	if err := this.AdminLogic.UpdateSQL(); err != nil {
 		ReturnError(response, err)
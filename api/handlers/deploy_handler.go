@@ -6,17 +6,20 @@ import (
 
 	"github.com/emicklei/go-restful"
 	"github.com/quintilesims/layer0/api/logic"
+	"github.com/quintilesims/layer0/common/db/tag_store"
 	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
 )
 
 type DeployHandler struct {
 	DeployLogic logic.DeployLogic
+	TagStore    tag_store.TagStore
 }
 
-func NewDeployHandler(deployLogic logic.DeployLogic) *DeployHandler {
+func NewDeployHandler(deployLogic logic.DeployLogic, tagStore tag_store.TagStore) *DeployHandler {
 	return &DeployHandler{
 		DeployLogic: deployLogic,
+		TagStore:    tagStore,
 	}
 }
 
@@ -33,6 +36,7 @@ func (this *DeployHandler) Routes() *restful.WebService {
 		Filter(basicAuthenticate).
 		To(this.ListDeploys).
 		Doc("List all Deploys").
+		Param(service.QueryParameter("selector", SELECTOR_PARAM_DESCRIPTION).DataType("string")).
 		Returns(200, "OK", []models.DeploySummary{}))
 
 	service.Route(service.GET("{id}").
@@ -60,13 +64,26 @@ func (this *DeployHandler) Routes() *restful.WebService {
 }
 
 func (this *DeployHandler) ListDeploys(request *restful.Request, response *restful.Response) {
+	match, err := selectEntities(this.TagStore, request, "deploy")
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
 	deploys, err := this.DeployLogic.ListDeploys()
 	if err != nil {
 		ReturnError(response, err)
 		return
 	}
 
-	response.WriteAsJson(deploys)
+	selected := deploys[:0]
+	for _, summary := range deploys {
+		if match(summary.DeployID) {
+			selected = append(selected, summary)
+		}
+	}
+
+	response.WriteAsJson(selected)
 }
 
 func (this *DeployHandler) GetDeploy(request *restful.Request, response *restful.Response) {
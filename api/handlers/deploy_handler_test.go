@@ -27,7 +27,7 @@ func TestListDeploys(t *testing.T) {
 					ListDeploys().
 					Return(deploys, nil)
 
-				return NewDeployHandler(logicMock)
+				return NewDeployHandler(logicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*DeployHandler)
@@ -48,7 +48,7 @@ func TestListDeploys(t *testing.T) {
 					ListDeploys().
 					Return(nil, errors.Newf(errors.UnexpectedError, "some error"))
 
-				return NewDeployHandler(logicMock)
+				return NewDeployHandler(logicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*DeployHandler)
@@ -82,7 +82,7 @@ func TestGetDeploy(t *testing.T) {
 					GetDeploy("some_id").
 					Return(deploy, nil)
 
-				return NewDeployHandler(logicMock)
+				return NewDeployHandler(logicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*DeployHandler)
@@ -100,7 +100,7 @@ func TestGetDeploy(t *testing.T) {
 					GetDeploy(gomock.Any()).
 					Return(deploy, nil)
 
-				return NewDeployHandler(logicMock)
+				return NewDeployHandler(logicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*DeployHandler)
@@ -117,7 +117,7 @@ func TestGetDeploy(t *testing.T) {
 			Request: &TestRequest{},
 			Setup: func(ctrl *gomock.Controller) interface{} {
 				logicMock := mock_logic.NewMockDeployLogic(ctrl)
-				return NewDeployHandler(logicMock)
+				return NewDeployHandler(logicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*DeployHandler)
@@ -140,7 +140,7 @@ func TestGetDeploy(t *testing.T) {
 					GetDeploy(gomock.Any()).
 					Return(nil, errors.Newf(errors.UnexpectedError, "some error"))
 
-				return NewDeployHandler(logicMock)
+				return NewDeployHandler(logicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*DeployHandler)
@@ -170,7 +170,7 @@ func TestDeleteDeploy(t *testing.T) {
 					DeleteDeploy("some_id").
 					Return(nil)
 
-				return NewDeployHandler(logicMock)
+				return NewDeployHandler(logicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*DeployHandler)
@@ -182,7 +182,7 @@ func TestDeleteDeploy(t *testing.T) {
 			Request: &TestRequest{},
 			Setup: func(ctrl *gomock.Controller) interface{} {
 				logicMock := mock_logic.NewMockDeployLogic(ctrl)
-				return NewDeployHandler(logicMock)
+				return NewDeployHandler(logicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*DeployHandler)
@@ -205,7 +205,7 @@ func TestDeleteDeploy(t *testing.T) {
 					DeleteDeploy(gomock.Any()).
 					Return(errors.Newf(errors.UnexpectedError, "some error"))
 
-				return NewDeployHandler(logicMock)
+				return NewDeployHandler(logicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*DeployHandler)
@@ -241,7 +241,7 @@ func TestCreateDeploy(t *testing.T) {
 					CreateDeploy(request).
 					Return(&models.Deploy{}, nil)
 
-				return NewDeployHandler(mockDeploy)
+				return NewDeployHandler(mockDeploy, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*DeployHandler)
@@ -260,7 +260,7 @@ func TestCreateDeploy(t *testing.T) {
 					CreateDeploy(gomock.Any()).
 					Return(nil, errors.Newf(errors.UnexpectedError, "some error"))
 
-				return NewDeployHandler(mockDeploy)
+				return NewDeployHandler(mockDeploy, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*DeployHandler)
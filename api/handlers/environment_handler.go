@@ -3,23 +3,30 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/emicklei/go-restful"
 	"github.com/quintilesims/layer0/api/logic"
+	"github.com/quintilesims/layer0/common/db/tag_store"
 	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/types"
 )
 
+// DEFAULT_SCALER_HISTORY_LIMIT is the number of scaler runs returned when no limit is specified
+const DEFAULT_SCALER_HISTORY_LIMIT = 20
+
 type EnvironmentHandler struct {
 	EnvironmentLogic logic.EnvironmentLogic
 	JobLogic         logic.JobLogic
+	TagStore         tag_store.TagStore
 }
 
-func NewEnvironmentHandler(environmentLogic logic.EnvironmentLogic, jobLogic logic.JobLogic) *EnvironmentHandler {
+func NewEnvironmentHandler(environmentLogic logic.EnvironmentLogic, jobLogic logic.JobLogic, tagStore tag_store.TagStore) *EnvironmentHandler {
 	return &EnvironmentHandler{
 		EnvironmentLogic: environmentLogic,
 		JobLogic:         jobLogic,
+		TagStore:         tagStore,
 	}
 }
 
@@ -29,6 +36,9 @@ func (e *EnvironmentHandler) Routes() *restful.WebService {
 		Consumes(restful.MIME_JSON).
 		Produces(restful.MIME_JSON)
 
+	ifMatch := service.HeaderParameter("If-Match", "version of the entity the update is based on").
+		DataType("string")
+
 	id := service.PathParameter("id", "identifier of the environment").
 		DataType("string")
 
@@ -36,6 +46,7 @@ func (e *EnvironmentHandler) Routes() *restful.WebService {
 		Filter(basicAuthenticate).
 		To(e.ListEnvironments).
 		Doc("List all Environments").
+		Param(service.QueryParameter("selector", SELECTOR_PARAM_DESCRIPTION).DataType("string")).
 		Returns(200, "OK", []models.Environment{}))
 
 	service.Route(service.GET("{id}").
@@ -48,17 +59,19 @@ func (e *EnvironmentHandler) Routes() *restful.WebService {
 	service.Route(service.POST("/").
 		Filter(basicAuthenticate).
 		To(e.CreateEnvironment).
-		Doc("Create a new Environment").
+		Doc("Create a new Environment. The environment is created by a job").
 		Reads(models.CreateEnvironmentRequest{}).
-		Returns(http.StatusCreated, "Created", models.Environment{}).
-		Writes(models.Environment{}))
+		Returns(http.StatusAccepted, "Accepted", nil).
+		Returns(400, "Invalid request", models.ServerError{}))
 
 	service.Route(service.PUT("{id}").
 		Filter(basicAuthenticate).
 		To(e.UpdateEnvironment).
 		Reads(models.UpdateEnvironmentRequest{}).
 		Param(id).
+		Param(ifMatch).
 		Doc("Update environment").
+		Returns(http.StatusConflict, "Version conflict", models.ServerError{}).
 		Writes(models.Environment{}))
 
 	service.Route(service.DELETE("{id}").
@@ -68,6 +81,14 @@ func (e *EnvironmentHandler) Routes() *restful.WebService {
 		Param(id).
 		Returns(http.StatusNoContent, "Deleted", nil))
 
+	service.Route(service.GET("{id}/scaler/history").
+		Filter(basicAuthenticate).
+		To(e.GetScalerHistory).
+		Doc("List the most recent scaler runs for an Environment, newest first").
+		Param(id).
+		Param(service.QueryParameter("limit", "maximum number of runs to return; 0 returns every run").DataType("string")).
+		Returns(200, "OK", []models.ScalerRun{}))
+
 	service.Route(service.POST("{id}/link").
 		Filter(basicAuthenticate).
 		To(e.CreateEnvironmentLink).
@@ -76,6 +97,16 @@ func (e *EnvironmentHandler) Routes() *restful.WebService {
 		Param(id).
 		Returns(http.StatusNoContent, "Created", nil))
 
+	service.Route(service.POST("{id}/clone").
+		Filter(basicAuthenticate).
+		To(e.CloneEnvironment).
+		Doc("Clone an Environment with its Load Balancers, Services, Links and Tags. The clone is created by a job").
+		Notes("The source environment is specified by the path; the request's source_environment_id is ignored").
+		Reads(models.CloneEnvironmentRequest{}).
+		Param(id).
+		Returns(http.StatusAccepted, "Accepted", nil).
+		Returns(400, "Invalid request", models.ServerError{}))
+
 	sourceID := service.PathParameter("source_id", "identifier of the source environment").
 		DataType("string")
 
@@ -94,13 +125,26 @@ func (e *EnvironmentHandler) Routes() *restful.WebService {
 }
 
 func (e *EnvironmentHandler) ListEnvironments(request *restful.Request, response *restful.Response) {
+	match, err := selectEntities(e.TagStore, request, "environment")
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
 	environments, err := e.EnvironmentLogic.ListEnvironments()
 	if err != nil {
 		ReturnError(response, err)
 		return
 	}
 
-	response.WriteAsJson(environments)
+	selected := environments[:0]
+	for _, summary := range environments {
+		if match(summary.EnvironmentID) {
+			selected = append(selected, summary)
+		}
+	}
+
+	response.WriteAsJson(selected)
 }
 
 func (e *EnvironmentHandler) GetEnvironment(request *restful.Request, response *restful.Response) {
@@ -117,6 +161,7 @@ func (e *EnvironmentHandler) GetEnvironment(request *restful.Request, response *
 		return
 	}
 
+	WriteETagHeader(response, environment.Version)
 	response.WriteAsJson(environment)
 }
 
@@ -156,13 +201,65 @@ func (e *EnvironmentHandler) CreateEnvironment(request *restful.Request, respons
 		return
 	}
 
-	environment, err := e.EnvironmentLogic.CreateEnvironment(req)
+	job, err := e.JobLogic.CreateJob(types.CreateEnvironmentJob, req)
 	if err != nil {
 		ReturnError(response, err)
 		return
 	}
 
-	response.WriteAsJson(environment)
+	WriteJobResponse(response, job.JobID)
+}
+
+func (e *EnvironmentHandler) CloneEnvironment(request *restful.Request, response *restful.Response) {
+	id := request.PathParameter("id")
+	if id == "" {
+		err := fmt.Errorf("Parameter 'id' is required")
+		BadRequest(response, errors.MissingParameter, err)
+		return
+	}
+
+	var req models.CloneEnvironmentRequest
+	if err := request.ReadEntity(&req); err != nil {
+		BadRequest(response, errors.InvalidJSON, err)
+		return
+	}
+
+	if req.EnvironmentName == "" {
+		err := fmt.Errorf("EnvironmentName is required")
+		BadRequest(response, errors.MissingParameter, err)
+		return
+	}
+
+	req.SourceEnvironmentID = id
+	if _, err := e.EnvironmentLogic.GetEnvironment(id); err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	ok, err := e.EnvironmentLogic.CanCreateEnvironment(models.CreateEnvironmentRequest{EnvironmentName: req.EnvironmentName})
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	if !ok {
+		err := fmt.Errorf("Environment with name '%s' already exists", req.EnvironmentName)
+		BadRequest(response, errors.InvalidEnvironmentID, err)
+		return
+	}
+
+	if err := e.EnvironmentLogic.CanCloneEnvironment(req); err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	job, err := e.JobLogic.CreateJob(types.CloneEnvironmentJob, req)
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	WriteJobResponse(response, job.JobID)
 }
 
 func (e *EnvironmentHandler) UpdateEnvironment(request *restful.Request, response *restful.Response) {
@@ -179,15 +276,51 @@ func (e *EnvironmentHandler) UpdateEnvironment(request *restful.Request, respons
 		return
 	}
 
-	environment, err := e.EnvironmentLogic.UpdateEnvironment(id, req.MinClusterCount)
+	version, err := IfMatchVersion(request)
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	environment, err := e.EnvironmentLogic.UpdateEnvironment(id, req, version)
 	if err != nil {
 		ReturnError(response, err)
 		return
 	}
 
+	WriteETagHeader(response, environment.Version)
 	response.WriteAsJson(environment)
 }
 
+func (e *EnvironmentHandler) GetScalerHistory(request *restful.Request, response *restful.Response) {
+	id := request.PathParameter("id")
+	if id == "" {
+		err := fmt.Errorf("Parameter 'id' is required")
+		BadRequest(response, errors.MissingParameter, err)
+		return
+	}
+
+	limit := DEFAULT_SCALER_HISTORY_LIMIT
+	if param := request.QueryParameter("limit"); param != "" {
+		l, err := strconv.Atoi(param)
+		if err != nil || l < 0 {
+			err := fmt.Errorf("Parameter 'limit' must be a non-negative integer")
+			BadRequest(response, errors.InvalidJSON, err)
+			return
+		}
+
+		limit = l
+	}
+
+	runs, err := e.EnvironmentLogic.GetScalerHistory(id, limit)
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	response.WriteAsJson(runs)
+}
+
 func (e *EnvironmentHandler) CreateEnvironmentLink(request *restful.Request, response *restful.Response) {
 	id := request.PathParameter("id")
 	if id == "" {
@@ -7,6 +7,7 @@ import (
 	"github.com/emicklei/go-restful"
 	"github.com/golang/mock/gomock"
 	"github.com/quintilesims/layer0/api/logic/mock_logic"
+	"github.com/quintilesims/layer0/common/db/tag_store"
 	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/testutils"
@@ -35,7 +36,7 @@ func TestListEnvironments(t *testing.T) {
 
 				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
 
-				return NewEnvironmentHandler(envLogicMock, jobLogicMock)
+				return NewEnvironmentHandler(envLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -58,7 +59,7 @@ func TestListEnvironments(t *testing.T) {
 
 				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
 
-				return NewEnvironmentHandler(envLogicMock, jobLogicMock)
+				return NewEnvironmentHandler(envLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -94,7 +95,7 @@ func TestGetEnvironment(t *testing.T) {
 
 				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
 
-				return NewEnvironmentHandler(envLogicMock, jobLogicMock)
+				return NewEnvironmentHandler(envLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -114,7 +115,7 @@ func TestGetEnvironment(t *testing.T) {
 
 				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
 
-				return NewEnvironmentHandler(envLogicMock, jobLogicMock)
+				return NewEnvironmentHandler(envLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -132,7 +133,7 @@ func TestGetEnvironment(t *testing.T) {
 			Setup: func(ctrl *gomock.Controller) interface{} {
 				envLogicMock := mock_logic.NewMockEnvironmentLogic(ctrl)
 				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
-				return NewEnvironmentHandler(envLogicMock, jobLogicMock)
+				return NewEnvironmentHandler(envLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -157,7 +158,7 @@ func TestGetEnvironment(t *testing.T) {
 
 				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
 
-				return NewEnvironmentHandler(envLogicMock, jobLogicMock)
+				return NewEnvironmentHandler(envLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -189,7 +190,7 @@ func TestDeleteEnvironment(t *testing.T) {
 					CreateJob(types.DeleteEnvironmentJob, "some_id").
 					Return(&models.Job{}, nil)
 
-				return NewEnvironmentHandler(envLogicMock, jobLogicMock)
+				return NewEnvironmentHandler(envLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -209,7 +210,7 @@ func TestDeleteEnvironment(t *testing.T) {
 					CreateJob(gomock.Any(), gomock.Any()).
 					Return(&models.Job{JobID: "job_id"}, nil)
 
-				return NewEnvironmentHandler(envLogicMock, jobLogicMock)
+				return NewEnvironmentHandler(envLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -233,7 +234,7 @@ func TestDeleteEnvironment(t *testing.T) {
 					CreateJob(gomock.Any(), gomock.Any()).
 					Return(nil, errors.Newf(errors.UnexpectedError, "some error"))
 
-				return NewEnvironmentHandler(envLogicMock, jobLogicMock)
+				return NewEnvironmentHandler(envLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -252,7 +253,7 @@ func TestDeleteEnvironment(t *testing.T) {
 				envLogicMock := mock_logic.NewMockEnvironmentLogic(ctrl)
 				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
 
-				return NewEnvironmentHandler(envLogicMock, jobLogicMock)
+				return NewEnvironmentHandler(envLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -276,7 +277,7 @@ func TestCreateEnvironment(t *testing.T) {
 
 	testCases := []HandlerTestCase{
 		{
-			Name: "Should call CanCreateEnvironment and CreateEnvironment with correct params",
+			Name: "Should call CanCreateEnvironment and CreateJob with correct params",
 			Request: &TestRequest{
 				Body: request,
 			},
@@ -286,16 +287,19 @@ func TestCreateEnvironment(t *testing.T) {
 					CanCreateEnvironment(request).
 					Return(true, nil)
 
-				mockEnvironment.EXPECT().
-					CreateEnvironment(request).
-					Return(&models.Environment{}, nil)
-
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
-				return NewEnvironmentHandler(mockEnvironment, mockJob)
+				mockJob.EXPECT().
+					CreateJob(types.CreateEnvironmentJob, request).
+					Return(&models.Job{JobID: "job_id"}, nil)
+
+				return NewEnvironmentHandler(mockEnvironment, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
 				handler.CreateEnvironment(req, resp)
+
+				reporter.AssertEqual(resp.StatusCode(), 202)
+				reporter.AssertInSlice("job_id", resp.Header()["X-Jobid"])
 			},
 		},
 		{
@@ -310,7 +314,7 @@ func TestCreateEnvironment(t *testing.T) {
 					Return(false, nil)
 
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
-				return NewEnvironmentHandler(mockEnvironment, mockJob)
+				return NewEnvironmentHandler(mockEnvironment, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -334,7 +338,7 @@ func TestCreateEnvironment(t *testing.T) {
 					Return(false, fmt.Errorf("some error"))
 
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
-				return NewEnvironmentHandler(mockEnvironment, mockJob)
+				return NewEnvironmentHandler(mockEnvironment, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -347,7 +351,7 @@ func TestCreateEnvironment(t *testing.T) {
 			},
 		},
 		{
-			Name: "Should propagate CreateEnvironment error",
+			Name: "Should propagate CreateJob error",
 			Request: &TestRequest{
 				Body: request,
 			},
@@ -357,12 +361,12 @@ func TestCreateEnvironment(t *testing.T) {
 					CanCreateEnvironment(request).
 					Return(true, nil)
 
-				mockEnvironment.EXPECT().
-					CreateEnvironment(gomock.Any()).
+				mockJob := mock_logic.NewMockJobLogic(ctrl)
+				mockJob.EXPECT().
+					CreateJob(gomock.Any(), gomock.Any()).
 					Return(nil, fmt.Errorf("some error"))
 
-				mockJob := mock_logic.NewMockJobLogic(ctrl)
-				return NewEnvironmentHandler(mockEnvironment, mockJob)
+				return NewEnvironmentHandler(mockEnvironment, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -379,9 +383,170 @@ func TestCreateEnvironment(t *testing.T) {
 	RunHandlerTestCases(t, testCases)
 }
 
+func TestCloneEnvironment(t *testing.T) {
+	request := models.CloneEnvironmentRequest{
+		EnvironmentName: "staging",
+		Scale:           map[string]int64{"api": 1},
+	}
+
+	jobRequest := request
+	jobRequest.SourceEnvironmentID = "some_id"
+
+	testCases := []HandlerTestCase{
+		{
+			Name: "Should call CanCreateEnvironment and CreateJob with correct params",
+			Request: &TestRequest{
+				Body:       request,
+				Parameters: map[string]string{"id": "some_id"},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				mockEnvironment := mock_logic.NewMockEnvironmentLogic(ctrl)
+				mockEnvironment.EXPECT().
+					GetEnvironment("some_id").
+					Return(&models.Environment{}, nil)
+
+				mockEnvironment.EXPECT().
+					CanCreateEnvironment(models.CreateEnvironmentRequest{EnvironmentName: "staging"}).
+					Return(true, nil)
+
+				mockEnvironment.EXPECT().
+					CanCloneEnvironment(jobRequest).
+					Return(nil)
+
+				mockJob := mock_logic.NewMockJobLogic(ctrl)
+				mockJob.EXPECT().
+					CreateJob(types.CloneEnvironmentJob, jobRequest).
+					Return(&models.Job{JobID: "job_id"}, nil)
+
+				return NewEnvironmentHandler(mockEnvironment, mockJob, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*EnvironmentHandler)
+				handler.CloneEnvironment(req, resp)
+
+				reporter.AssertEqual(resp.StatusCode(), 202)
+				reporter.AssertInSlice("job_id", resp.Header()["X-Jobid"])
+			},
+		},
+		{
+			Name: "Should return error if EnvironmentName is missing",
+			Request: &TestRequest{
+				Body:       models.CloneEnvironmentRequest{},
+				Parameters: map[string]string{"id": "some_id"},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				mockEnvironment := mock_logic.NewMockEnvironmentLogic(ctrl)
+				mockJob := mock_logic.NewMockJobLogic(ctrl)
+				return NewEnvironmentHandler(mockEnvironment, mockJob, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*EnvironmentHandler)
+				handler.CloneEnvironment(req, resp)
+
+				var response *models.ServerError
+				read(&response)
+
+				reporter.AssertEqual(int64(errors.MissingParameter), response.ErrorCode)
+			},
+		},
+		{
+			Name: "Should propagate GetEnvironment error",
+			Request: &TestRequest{
+				Body:       request,
+				Parameters: map[string]string{"id": "some_id"},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				mockEnvironment := mock_logic.NewMockEnvironmentLogic(ctrl)
+				mockEnvironment.EXPECT().
+					GetEnvironment("some_id").
+					Return(nil, errors.Newf(errors.InvalidEnvironmentID, "some error"))
+
+				mockJob := mock_logic.NewMockJobLogic(ctrl)
+				return NewEnvironmentHandler(mockEnvironment, mockJob, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*EnvironmentHandler)
+				handler.CloneEnvironment(req, resp)
+
+				var response *models.ServerError
+				read(&response)
+
+				reporter.AssertEqual(int64(errors.InvalidEnvironmentID), response.ErrorCode)
+			},
+		},
+		{
+			Name: "Should return bad request if CanCloneEnvironment fails",
+			Request: &TestRequest{
+				Body:       request,
+				Parameters: map[string]string{"id": "some_id"},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				mockEnvironment := mock_logic.NewMockEnvironmentLogic(ctrl)
+				mockEnvironment.EXPECT().
+					GetEnvironment("some_id").
+					Return(&models.Environment{}, nil)
+
+				mockEnvironment.EXPECT().
+					CanCreateEnvironment(gomock.Any()).
+					Return(true, nil)
+
+				mockEnvironment.EXPECT().
+					CanCloneEnvironment(jobRequest).
+					Return(errors.Newf(errors.InvalidServiceName, "some error"))
+
+				mockJob := mock_logic.NewMockJobLogic(ctrl)
+				return NewEnvironmentHandler(mockEnvironment, mockJob, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*EnvironmentHandler)
+				handler.CloneEnvironment(req, resp)
+
+				var response *models.ServerError
+				read(&response)
+
+				reporter.AssertEqual(resp.StatusCode(), 400)
+				reporter.AssertEqual(int64(errors.InvalidServiceName), response.ErrorCode)
+			},
+		},
+		{
+			Name: "Should return error if CanCreateEnvironment returns false",
+			Request: &TestRequest{
+				Body:       request,
+				Parameters: map[string]string{"id": "some_id"},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				mockEnvironment := mock_logic.NewMockEnvironmentLogic(ctrl)
+				mockEnvironment.EXPECT().
+					GetEnvironment("some_id").
+					Return(&models.Environment{}, nil)
+
+				mockEnvironment.EXPECT().
+					CanCreateEnvironment(gomock.Any()).
+					Return(false, nil)
+
+				mockJob := mock_logic.NewMockJobLogic(ctrl)
+				return NewEnvironmentHandler(mockEnvironment, mockJob, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*EnvironmentHandler)
+				handler.CloneEnvironment(req, resp)
+
+				var response *models.ServerError
+				read(&response)
+
+				reporter.AssertEqual(int64(errors.InvalidEnvironmentID), response.ErrorCode)
+			},
+		},
+	}
+
+	RunHandlerTestCases(t, testCases)
+}
+
 func TestUpdateEnvironment(t *testing.T) {
+	minClusterCount := 2
 	request := models.UpdateEnvironmentRequest{
-		MinClusterCount: 2,
+		MinClusterCount:   &minClusterCount,
+		PlacementStrategy: "spread-zone",
 	}
 
 	testCases := []HandlerTestCase{
@@ -395,11 +560,11 @@ func TestUpdateEnvironment(t *testing.T) {
 				mockEnvironment := mock_logic.NewMockEnvironmentLogic(ctrl)
 
 				mockEnvironment.EXPECT().
-					UpdateEnvironment("some_id", 2).
+					UpdateEnvironment("some_id", request, tag_store.AnyVersion).
 					Return(&models.Environment{}, nil)
 
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
-				return NewEnvironmentHandler(mockEnvironment, mockJob)
+				return NewEnvironmentHandler(mockEnvironment, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -416,11 +581,11 @@ func TestUpdateEnvironment(t *testing.T) {
 				mockEnvironment := mock_logic.NewMockEnvironmentLogic(ctrl)
 
 				mockEnvironment.EXPECT().
-					UpdateEnvironment(gomock.Any(), gomock.Any()).
+					UpdateEnvironment(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil, fmt.Errorf("some error"))
 
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
-				return NewEnvironmentHandler(mockEnvironment, mockJob)
+				return NewEnvironmentHandler(mockEnvironment, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -457,7 +622,7 @@ func TestCreateEnvironmentLink(t *testing.T) {
 					Return(nil)
 
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
-				return NewEnvironmentHandler(mockEnvironment, mockJob)
+				return NewEnvironmentHandler(mockEnvironment, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -478,7 +643,7 @@ func TestCreateEnvironmentLink(t *testing.T) {
 					Return(fmt.Errorf("some error"))
 
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
-				return NewEnvironmentHandler(mockEnvironment, mockJob)
+				return NewEnvironmentHandler(mockEnvironment, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -510,7 +675,7 @@ func TestDeleteEnvironmentLink(t *testing.T) {
 					Return(nil)
 
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
-				return NewEnvironmentHandler(mockEnvironment, mockJob)
+				return NewEnvironmentHandler(mockEnvironment, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -530,7 +695,7 @@ func TestDeleteEnvironmentLink(t *testing.T) {
 					Return(fmt.Errorf("some error"))
 
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
-				return NewEnvironmentHandler(mockEnvironment, mockJob)
+				return NewEnvironmentHandler(mockEnvironment, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*EnvironmentHandler)
@@ -546,3 +711,81 @@ func TestDeleteEnvironmentLink(t *testing.T) {
 
 	RunHandlerTestCases(t, testCases)
 }
+
+func TestGetScalerHistory(t *testing.T) {
+	runs := []*models.ScalerRun{
+		{ScalerRunInfo: models.ScalerRunInfo{EnvironmentID: "some_id"}},
+	}
+
+	testCases := []HandlerTestCase{
+		{
+			Name: "Should call GetScalerHistory with the default limit",
+			Request: &TestRequest{
+				Parameters: map[string]string{"id": "some_id"},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				envLogicMock := mock_logic.NewMockEnvironmentLogic(ctrl)
+				envLogicMock.EXPECT().
+					GetScalerHistory("some_id", DEFAULT_SCALER_HISTORY_LIMIT).
+					Return(runs, nil)
+
+				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
+
+				return NewEnvironmentHandler(envLogicMock, jobLogicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*EnvironmentHandler)
+				handler.GetScalerHistory(req, resp)
+
+				var response []*models.ScalerRun
+				read(&response)
+
+				reporter.AssertEqual(response, runs)
+			},
+		},
+		{
+			Name: "Should call GetScalerHistory with the limit parameter",
+			Request: &TestRequest{
+				Parameters: map[string]string{"id": "some_id"},
+				Query:      "limit=5",
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				envLogicMock := mock_logic.NewMockEnvironmentLogic(ctrl)
+				envLogicMock.EXPECT().
+					GetScalerHistory("some_id", 5).
+					Return(runs, nil)
+
+				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
+
+				return NewEnvironmentHandler(envLogicMock, jobLogicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*EnvironmentHandler)
+				handler.GetScalerHistory(req, resp)
+			},
+		},
+		{
+			Name: "Should return InvalidJSON error with a negative limit",
+			Request: &TestRequest{
+				Parameters: map[string]string{"id": "some_id"},
+				Query:      "limit=-1",
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				envLogicMock := mock_logic.NewMockEnvironmentLogic(ctrl)
+				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
+				return NewEnvironmentHandler(envLogicMock, jobLogicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*EnvironmentHandler)
+				handler.GetScalerHistory(req, resp)
+
+				var response *models.ServerError
+				read(&response)
+
+				reporter.AssertEqual(response.ErrorCode, int64(errors.InvalidJSON))
+			},
+		},
+	}
+
+	RunHandlerTestCases(t, testCases)
+}
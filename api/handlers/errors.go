@@ -17,13 +17,17 @@ func ToHttpError(code errors.ErrorCode) int {
 	switch code {
 	case errors.InvalidJSON, errors.MissingParameter, errors.InvalidEntityType,
 		errors.InvalidEnvironmentID, errors.InvalidServiceID, errors.InvalidDeployID,
-		errors.InvalidTagKey, errors.InvalidTagValue, errors.InvalidCertificateID:
+		errors.InvalidTagKey, errors.InvalidTagValue, errors.InvalidCertificateID, errors.InvalidWorkflow,
+		errors.InvalidPlacementStrategy, errors.InvalidScalerSettings, errors.InvalidSelector,
+		errors.InvalidBackup, errors.InvalidLoadBalancerName, errors.InvalidServiceName:
 		ret = http.StatusBadRequest
 	case errors.Throttled:
 		ret = http.StatusServiceUnavailable
 	case errors.DeployDoesNotExist, errors.EnvironmentDoesNotExist, errors.JobDoesNotExist,
 		errors.LoadBalancerDoesNotExist, errors.ServiceDoesNotExist, errors.TaskDoesNotExist:
 		ret = http.StatusNotFound
+	case errors.EntityConflict, errors.JobNotCancellable, errors.JobNotRetryable:
+		ret = http.StatusConflict
 	default:
 		ret = http.StatusInternalServerError
 	}
@@ -50,7 +54,11 @@ func BadRequest(response *restful.Response, code errors.ErrorCode, err error) {
 
 func AWSError(response *restful.Response, err awserr.Error) {
 	logrus.Errorf("AWSError (code: %s): %s", err.Code(), err.Message())
+	status, model := awsErrorModel(err)
+	Errorf(response, status, model)
+}
 
+func awsErrorModel(err awserr.Error) (int, models.ServerError) {
 	var code errors.ErrorCode
 	var status int
 
@@ -69,8 +77,20 @@ func AWSError(response *restful.Response, err awserr.Error) {
 	message = strings.Replace(message, "listener", "port", -1)
 
 	message = fmt.Sprintf("AWS Error: %s (code '%s')", message, err.Code())
-	model := models.ServerError{ErrorCode: int64(code), Message: message}
-	Errorf(response, status, model)
+	return status, models.ServerError{ErrorCode: int64(code), Message: message}
+}
+
+// ErrorModel converts err into the http status and ServerError model
+// that would be returned to the client
+func ErrorModel(err error) (int, models.ServerError) {
+	switch err := err.(type) {
+	case awserr.Error:
+		return awsErrorModel(err)
+	case *errors.ServerError:
+		return ToHttpError(err.Code), err.Model()
+	default:
+		return http.StatusInternalServerError, models.ServerError{ErrorCode: int64(errors.UnexpectedError), Message: err.Error()}
+	}
 }
 
 func NotImplemented(request *restful.Request, response *restful.Response) {
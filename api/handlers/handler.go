@@ -3,8 +3,12 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/emicklei/go-restful"
+	"github.com/quintilesims/layer0/common/db/tag_store"
+	"github.com/quintilesims/layer0/common/errors"
 )
 
 func WriteJobResponse(response *restful.Response, jobID string) {
@@ -13,3 +17,26 @@ func WriteJobResponse(response *restful.Response, jobID string) {
 	response.WriteHeader(http.StatusAccepted)
 	response.WriteAsJson(``)
 }
+
+func WriteETagHeader(response *restful.Response, version int64) {
+	response.AddHeader("ETag", fmt.Sprintf("\"%d\"", version))
+}
+
+// IfMatchVersion returns the entity version sent in the request's If-Match header.
+// If the header is not set, tag_store.AnyVersion is returned.
+func IfMatchVersion(request *restful.Request) (int64, error) {
+	etag := strings.TrimSpace(request.HeaderParameter("If-Match"))
+	if etag == "" || etag == "*" {
+		return tag_store.AnyVersion, nil
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	etag = strings.Trim(etag, "\"")
+
+	version, err := strconv.ParseInt(etag, 10, 64)
+	if err != nil {
+		return 0, errors.Newf(errors.EntityConflict, "If-Match header '%s' does not match the current version", etag)
+	}
+
+	return version, nil
+}
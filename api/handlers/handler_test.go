@@ -28,6 +28,7 @@ type TestRequest struct {
 	Path       string
 	Parameters map[string]string
 	Query      string
+	Headers    map[string]string
 }
 
 func (this *TestRequest) RestfulRequest() (*restful.Request, error) {
@@ -42,6 +43,9 @@ func (this *TestRequest) RestfulRequest() (*restful.Request, error) {
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	for key, val := range this.Headers {
+		req.Header.Set(key, val)
+	}
 
 	restfulRequest := restful.NewRequest(req)
 	for key, val := range this.Parameters {
@@ -3,20 +3,25 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/emicklei/go-restful"
 	"github.com/quintilesims/layer0/api/logic"
+	"github.com/quintilesims/layer0/common/db/tag_store"
 	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/types"
 )
 
 type JobHandler struct {
 	JobLogic logic.JobLogic
+	TagStore tag_store.TagStore
 }
 
-func NewJobHandler(jobLogic logic.JobLogic) *JobHandler {
+func NewJobHandler(jobLogic logic.JobLogic, tagStore tag_store.TagStore) *JobHandler {
 	return &JobHandler{
 		JobLogic: jobLogic,
+		TagStore: tagStore,
 	}
 }
 
@@ -33,6 +38,13 @@ func (j *JobHandler) Routes() *restful.WebService {
 		Filter(basicAuthenticate).
 		To(j.ListJobs).
 		Doc("List all Jobs").
+		Notes("Jobs past their retention are removed from the list and archived").
+		Param(service.QueryParameter("archived", "list archived jobs instead of current jobs").DataType("boolean")).
+		Param(service.QueryParameter("status", "only list jobs with this status, e.g. 'error'").DataType("string")).
+		Param(service.QueryParameter("type", "only list jobs of this type, e.g. 'delete service'").DataType("string")).
+		Param(service.QueryParameter("entity_type", "only list jobs that targeted an entity of this type").DataType("string")).
+		Param(service.QueryParameter("entity_id", "only list jobs that targeted the entity with this id").DataType("string")).
+		Param(service.QueryParameter("selector", SELECTOR_PARAM_DESCRIPTION).DataType("string")).
 		Returns(200, "OK", []models.Job{}))
 
 	service.Route(service.GET("{id}").
@@ -42,6 +54,33 @@ func (j *JobHandler) Routes() *restful.WebService {
 		Param(id).
 		Writes(models.Job{}))
 
+	service.Route(service.GET("/{id}/workflow").
+		Filter(basicAuthenticate).
+		To(j.GetWorkflow).
+		Doc("Return the status of each node in a workflow job").
+		Param(id).
+		Writes(models.Workflow{}))
+
+	service.Route(service.POST("/workflow").
+		Filter(basicAuthenticate).
+		To(j.CreateWorkflow).
+		Doc("Create a workflow job that runs a graph of child jobs").
+		Notes("Each node runs as a child job once the nodes it depends on have completed. "+
+			"Node requests may reference the meta of a dependency as ${node.key}").
+		Reads(models.CreateWorkflowRequest{}).
+		Returns(http.StatusAccepted, "Accepted", nil).
+		Returns(http.StatusBadRequest, "Invalid workflow", models.ServerError{}))
+
+	service.Route(service.GET("/{id}/logs").
+		Filter(basicAuthenticate).
+		To(j.GetJobLogs).
+		Doc("Return the logs of the job's runner").
+		Param(id).
+		Param(service.QueryParameter("tail", "number of lines from the end to return").DataType("string")).
+		Param(service.QueryParameter("start", "The start of the time range to fetch logs (format YYYY-MM-DD HH:MM)").DataType("string")).
+		Param(service.QueryParameter("end", "The end of the time range to fetch logs (format YYYY-MM-DD HH:MM)").DataType("string")).
+		Writes([]models.LogFile{}))
+
 	service.Route(service.DELETE("/{id}").
 		Filter(basicAuthenticate).
 		To(j.Delete).
@@ -49,17 +88,107 @@ func (j *JobHandler) Routes() *restful.WebService {
 		Param(id).
 		Returns(http.StatusNoContent, "Deleted", nil))
 
+	service.Route(service.POST("/{id}/cancel").
+		Filter(basicAuthenticate).
+		To(j.CancelJob).
+		Doc("Cancel a pending or in-progress job and stop its runner").
+		Param(id).
+		Returns(http.StatusOK, "OK", models.Job{}).
+		Returns(http.StatusConflict, "Job has already finished", models.ServerError{}))
+
+	service.Route(service.POST("/{id}/retry").
+		Filter(basicAuthenticate).
+		To(j.RetryJob).
+		Doc("Re-launch a failed or cancelled job, resuming at the first incomplete step").
+		Param(id).
+		Returns(http.StatusOK, "OK", models.Job{}).
+		Returns(http.StatusConflict, "Job has not failed", models.ServerError{}))
+
 	return service
 }
 
 func (j *JobHandler) ListJobs(request *restful.Request, response *restful.Response) {
-	jobs, err := j.JobLogic.ListJobs()
+	listJobs, err := j.selectListJobs(request)
+	if err != nil {
+		BadRequest(response, errors.InvalidJSON, err)
+		return
+	}
+
+	match, err := selectEntities(j.TagStore, request, "job")
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	jobs, err := listJobs()
 	if err != nil {
 		ReturnError(response, err)
 		return
 	}
 
-	response.WriteAsJson(jobs)
+	selected := jobs[:0]
+	for _, job := range jobs {
+		if match(job.JobID) {
+			selected = append(selected, job)
+		}
+	}
+
+	response.WriteAsJson(selected)
+}
+
+// selectListJobs returns the JobLogic function that lists the jobs matching the request's query.
+// At most one of the filters may be used
+func (j *JobHandler) selectListJobs(request *restful.Request) (func() ([]*models.Job, error), error) {
+	archived := request.QueryParameter("archived")
+	status := request.QueryParameter("status")
+	jobType := request.QueryParameter("type")
+	entityType := request.QueryParameter("entity_type")
+	entityID := request.QueryParameter("entity_id")
+
+	count := 0
+	for _, filter := range []string{archived, status, jobType, entityType + entityID} {
+		if filter != "" {
+			count++
+		}
+	}
+
+	if count > 1 {
+		return nil, fmt.Errorf("Only one of 'archived', 'status', 'type', or 'entity_type' and 'entity_id' may be specified")
+	}
+
+	switch {
+	case archived != "":
+		a, err := strconv.ParseBool(archived)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse 'archived': %v", err)
+		}
+
+		if a {
+			return j.JobLogic.ListArchivedJobs, nil
+		}
+	case status != "":
+		s, err := types.ParseJobStatus(status)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() ([]*models.Job, error) { return j.JobLogic.ListJobsByStatus(s) }, nil
+	case jobType != "":
+		t, err := types.ParseJobType(jobType)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() ([]*models.Job, error) { return j.JobLogic.ListJobsByType(t) }, nil
+	case entityType != "" || entityID != "":
+		if entityType == "" || entityID == "" {
+			return nil, fmt.Errorf("Both 'entity_type' and 'entity_id' are required to filter by entity")
+		}
+
+		return func() ([]*models.Job, error) { return j.JobLogic.ListJobsByEntity(entityType, entityID) }, nil
+	}
+
+	return j.JobLogic.ListJobs, nil
 }
 
 func (j *JobHandler) GetJob(request *restful.Request, response *restful.Response) {
@@ -79,6 +208,67 @@ func (j *JobHandler) GetJob(request *restful.Request, response *restful.Response
 	response.WriteAsJson(job)
 }
 
+func (j *JobHandler) GetWorkflow(request *restful.Request, response *restful.Response) {
+	id := request.PathParameter("id")
+	if id == "" {
+		err := fmt.Errorf("Parameter 'id' is required")
+		BadRequest(response, errors.MissingParameter, err)
+		return
+	}
+
+	workflow, err := j.JobLogic.GetWorkflow(id)
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	response.WriteAsJson(workflow)
+}
+
+func (j *JobHandler) CreateWorkflow(request *restful.Request, response *restful.Response) {
+	var req models.CreateWorkflowRequest
+	if err := request.ReadEntity(&req); err != nil {
+		BadRequest(response, errors.InvalidJSON, err)
+		return
+	}
+
+	job, err := j.JobLogic.CreateWorkflow(req)
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	WriteJobResponse(response, job.JobID)
+}
+
+func (j *JobHandler) GetJobLogs(request *restful.Request, response *restful.Response) {
+	id := request.PathParameter("id")
+	if id == "" {
+		err := fmt.Errorf("Parameter 'id' is required")
+		BadRequest(response, errors.MissingParameter, err)
+		return
+	}
+
+	var tail int
+	if param := request.QueryParameter("tail"); param != "" {
+		t, err := strconv.ParseInt(param, 10, 64)
+		if err != nil {
+			BadRequest(response, errors.InvalidJSON, err)
+			return
+		}
+
+		tail = int(t)
+	}
+
+	logs, err := j.JobLogic.GetJobLogs(id, request.QueryParameter("start"), request.QueryParameter("end"), tail)
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	response.WriteAsJson(logs)
+}
+
 func (j *JobHandler) Delete(request *restful.Request, response *restful.Response) {
 	id := request.PathParameter("id")
 	if id == "" {
@@ -94,3 +284,37 @@ func (j *JobHandler) Delete(request *restful.Request, response *restful.Response
 
 	response.WriteAsJson(``)
 }
+
+func (j *JobHandler) CancelJob(request *restful.Request, response *restful.Response) {
+	id := request.PathParameter("id")
+	if id == "" {
+		err := fmt.Errorf("Parameter 'id' is required")
+		BadRequest(response, errors.MissingParameter, err)
+		return
+	}
+
+	job, err := j.JobLogic.CancelJob(id)
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	response.WriteAsJson(job)
+}
+
+func (j *JobHandler) RetryJob(request *restful.Request, response *restful.Response) {
+	id := request.PathParameter("id")
+	if id == "" {
+		err := fmt.Errorf("Parameter 'id' is required")
+		BadRequest(response, errors.MissingParameter, err)
+		return
+	}
+
+	job, err := j.JobLogic.RetryJob(id)
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	response.WriteAsJson(job)
+}
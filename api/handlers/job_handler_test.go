@@ -1,15 +1,18 @@
 package handlers
 
 import (
+	"net/http"
 	"testing"
 	"time"
 
+	"encoding/json"
 	"github.com/emicklei/go-restful"
 	"github.com/golang/mock/gomock"
 	"github.com/quintilesims/layer0/api/logic/mock_logic"
 	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/testutils"
+	"github.com/quintilesims/layer0/common/types"
 )
 
 func TestListJobs(t *testing.T) {
@@ -36,7 +39,7 @@ func TestListJobs(t *testing.T) {
 					ListJobs().
 					Return(jobs, nil)
 
-				return NewJobHandler(logicMock)
+				return NewJobHandler(logicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*JobHandler)
@@ -60,7 +63,7 @@ func TestListJobs(t *testing.T) {
 					ListJobs().
 					Return(nil, errors.Newf(errors.UnexpectedError, "some error"))
 
-				return NewJobHandler(logicMock)
+				return NewJobHandler(logicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*JobHandler)
@@ -72,6 +75,102 @@ func TestListJobs(t *testing.T) {
 				reporter.AssertEqual(response.ErrorCode, int64(errors.UnexpectedError))
 			},
 		},
+		{
+			Name:    "Should return archived jobs when archived is set",
+			Request: &TestRequest{Query: "archived=true"},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				logicMock := mock_logic.NewMockJobLogic(ctrl)
+				logicMock.EXPECT().
+					ListArchivedJobs().
+					Return(jobs, nil)
+
+				return NewJobHandler(logicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*JobHandler)
+				handler.ListJobs(req, resp)
+
+				var response []*models.Job
+				read(&response)
+
+				reporter.AssertEqual(len(response), 2)
+				reporter.AssertEqual(response[0].JobID, jobs[0].JobID)
+			},
+		},
+		{
+			Name:    "Should return jobs for the entity in the query",
+			Request: &TestRequest{Query: "entity_type=service&entity_id=s1"},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				logicMock := mock_logic.NewMockJobLogic(ctrl)
+				logicMock.EXPECT().
+					ListJobsByEntity("service", "s1").
+					Return(jobs, nil)
+
+				return NewJobHandler(logicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*JobHandler)
+				handler.ListJobs(req, resp)
+
+				var response []*models.Job
+				read(&response)
+
+				reporter.AssertEqual(len(response), 2)
+			},
+		},
+		{
+			Name:    "Should return jobs with the status in the query",
+			Request: &TestRequest{Query: "status=in%20progress"},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				logicMock := mock_logic.NewMockJobLogic(ctrl)
+				logicMock.EXPECT().
+					ListJobsByStatus(types.InProgress).
+					Return(jobs, nil)
+
+				return NewJobHandler(logicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*JobHandler)
+				handler.ListJobs(req, resp)
+
+				var response []*models.Job
+				read(&response)
+
+				reporter.AssertEqual(len(response), 2)
+			},
+		},
+		{
+			Name:    "Should return error when multiple filters are specified",
+			Request: &TestRequest{Query: "status=error&type=delete%20service"},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				return NewJobHandler(mock_logic.NewMockJobLogic(ctrl), nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*JobHandler)
+				handler.ListJobs(req, resp)
+
+				var response *models.ServerError
+				read(&response)
+
+				reporter.AssertEqual(response.ErrorCode, int64(errors.InvalidJSON))
+			},
+		},
+		{
+			Name:    "Should return error on invalid archived parameter",
+			Request: &TestRequest{Query: "archived=abc"},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				return NewJobHandler(mock_logic.NewMockJobLogic(ctrl), nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*JobHandler)
+				handler.ListJobs(req, resp)
+
+				var response *models.ServerError
+				read(&response)
+
+				reporter.AssertEqual(response.ErrorCode, int64(errors.InvalidJSON))
+			},
+		},
 	}
 
 	RunHandlerTestCases(t, testCases)
@@ -94,7 +193,7 @@ func TestGetJob(t *testing.T) {
 					GetJob("some_id").
 					Return(job, nil)
 
-				return NewJobHandler(logicMock)
+				return NewJobHandler(logicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*JobHandler)
@@ -112,7 +211,7 @@ func TestGetJob(t *testing.T) {
 					GetJob(gomock.Any()).
 					Return(job, nil)
 
-				return NewJobHandler(logicMock)
+				return NewJobHandler(logicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*JobHandler)
@@ -130,7 +229,7 @@ func TestGetJob(t *testing.T) {
 			Request: &TestRequest{},
 			Setup: func(ctrl *gomock.Controller) interface{} {
 				logicMock := mock_logic.NewMockJobLogic(ctrl)
-				return NewJobHandler(logicMock)
+				return NewJobHandler(logicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*JobHandler)
@@ -153,7 +252,7 @@ func TestGetJob(t *testing.T) {
 					GetJob(gomock.Any()).
 					Return(nil, errors.Newf(errors.UnexpectedError, "some error"))
 
-				return NewJobHandler(logicMock)
+				return NewJobHandler(logicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*JobHandler)
@@ -183,7 +282,7 @@ func TestDelete(t *testing.T) {
 					Delete("some_id").
 					Return(nil)
 
-				return NewJobHandler(logicMock)
+				return NewJobHandler(logicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*JobHandler)
@@ -195,7 +294,7 @@ func TestDelete(t *testing.T) {
 			Request: &TestRequest{},
 			Setup: func(ctrl *gomock.Controller) interface{} {
 				logicMock := mock_logic.NewMockJobLogic(ctrl)
-				return NewJobHandler(logicMock)
+				return NewJobHandler(logicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*JobHandler)
@@ -218,7 +317,7 @@ func TestDelete(t *testing.T) {
 					Delete(gomock.Any()).
 					Return(errors.Newf(errors.UnexpectedError, "some error"))
 
-				return NewJobHandler(logicMock)
+				return NewJobHandler(logicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*JobHandler)
@@ -234,3 +333,267 @@ func TestDelete(t *testing.T) {
 
 	RunHandlerTestCases(t, testCases)
 }
+
+func TestCancelJob(t *testing.T) {
+	testCases := []HandlerTestCase{
+		{
+			Name: "Should call CancelJob with proper params",
+			Request: &TestRequest{
+				Parameters: map[string]string{"id": "some_id"},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				logicMock := mock_logic.NewMockJobLogic(ctrl)
+				logicMock.EXPECT().
+					CancelJob("some_id").
+					Return(&models.Job{JobID: "some_id", JobStatus: int64(types.Cancelled)}, nil)
+
+				return NewJobHandler(logicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*JobHandler)
+				handler.CancelJob(req, resp)
+
+				var response *models.Job
+				read(&response)
+
+				reporter.AssertEqual(response.JobStatus, int64(types.Cancelled))
+			},
+		},
+		{
+			Name: "Should return Conflict for finished jobs",
+			Request: &TestRequest{
+				Parameters: map[string]string{"id": "some_id"},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				logicMock := mock_logic.NewMockJobLogic(ctrl)
+				logicMock.EXPECT().
+					CancelJob(gomock.Any()).
+					Return(nil, errors.Newf(errors.JobNotCancellable, "some error"))
+
+				return NewJobHandler(logicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*JobHandler)
+				handler.CancelJob(req, resp)
+
+				var response *models.ServerError
+				read(&response)
+
+				reporter.AssertEqual(resp.StatusCode(), http.StatusConflict)
+				reporter.AssertEqual(response.ErrorCode, int64(errors.JobNotCancellable))
+			},
+		},
+	}
+
+	RunHandlerTestCases(t, testCases)
+}
+
+func TestRetryJob(t *testing.T) {
+	testCases := []HandlerTestCase{
+		{
+			Name: "Should call RetryJob with proper params",
+			Request: &TestRequest{
+				Parameters: map[string]string{"id": "some_id"},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				logicMock := mock_logic.NewMockJobLogic(ctrl)
+				logicMock.EXPECT().
+					RetryJob("some_id").
+					Return(&models.Job{JobID: "some_id", JobStatus: int64(types.Pending)}, nil)
+
+				return NewJobHandler(logicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*JobHandler)
+				handler.RetryJob(req, resp)
+
+				var response *models.Job
+				read(&response)
+
+				reporter.AssertEqual(response.JobStatus, int64(types.Pending))
+			},
+		},
+		{
+			Name: "Should return Conflict for jobs that have not failed",
+			Request: &TestRequest{
+				Parameters: map[string]string{"id": "some_id"},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				logicMock := mock_logic.NewMockJobLogic(ctrl)
+				logicMock.EXPECT().
+					RetryJob(gomock.Any()).
+					Return(nil, errors.Newf(errors.JobNotRetryable, "some error"))
+
+				return NewJobHandler(logicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*JobHandler)
+				handler.RetryJob(req, resp)
+
+				var response *models.ServerError
+				read(&response)
+
+				reporter.AssertEqual(resp.StatusCode(), http.StatusConflict)
+				reporter.AssertEqual(response.ErrorCode, int64(errors.JobNotRetryable))
+			},
+		},
+	}
+
+	RunHandlerTestCases(t, testCases)
+}
+
+func TestGetJobLogs(t *testing.T) {
+	testCases := []HandlerTestCase{
+		{
+			Name: "Should call GetJobLogs with proper params",
+			Request: &TestRequest{
+				Parameters: map[string]string{"id": "some_id"},
+				Query:      "tail=100&start=2001-01-01 01:01&end=2012-12-12 12:12",
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				logicMock := mock_logic.NewMockJobLogic(ctrl)
+				logicMock.EXPECT().
+					GetJobLogs("some_id", "2001-01-01 01:01", "2012-12-12 12:12", 100).
+					Return([]*models.LogFile{{Name: "l0-job"}}, nil)
+
+				return NewJobHandler(logicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*JobHandler)
+				handler.GetJobLogs(req, resp)
+
+				var response []*models.LogFile
+				read(&response)
+
+				reporter.AssertEqual(len(response), 1)
+			},
+		},
+		{
+			Name: "Should return InvalidJSON error with bad tail",
+			Request: &TestRequest{
+				Parameters: map[string]string{"id": "some_id"},
+				Query:      "tail=abc",
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				logicMock := mock_logic.NewMockJobLogic(ctrl)
+				return NewJobHandler(logicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*JobHandler)
+				handler.GetJobLogs(req, resp)
+
+				var response *models.ServerError
+				read(&response)
+
+				reporter.AssertEqual(response.ErrorCode, int64(errors.InvalidJSON))
+			},
+		},
+	}
+
+	RunHandlerTestCases(t, testCases)
+}
+
+func TestCreateWorkflow(t *testing.T) {
+	request := models.CreateWorkflowRequest{
+		Nodes: []models.WorkflowNode{
+			{Name: "a", JobType: "delete service", Request: json.RawMessage(`"s1"`)},
+		},
+	}
+
+	testCases := []HandlerTestCase{
+		{
+			Name: "Should call CreateWorkflow with correct params",
+			Request: &TestRequest{
+				Body: request,
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				logicMock := mock_logic.NewMockJobLogic(ctrl)
+				logicMock.EXPECT().
+					CreateWorkflow(request).
+					Return(&models.Job{JobID: "job_id"}, nil)
+
+				return NewJobHandler(logicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*JobHandler)
+				handler.CreateWorkflow(req, resp)
+
+				reporter.AssertEqual(resp.StatusCode(), http.StatusAccepted)
+				reporter.AssertInSlice("job_id", resp.Header()["X-Jobid"])
+			},
+		},
+		{
+			Name: "Should return BadRequest for invalid workflows",
+			Request: &TestRequest{
+				Body: request,
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				logicMock := mock_logic.NewMockJobLogic(ctrl)
+				logicMock.EXPECT().
+					CreateWorkflow(gomock.Any()).
+					Return(nil, errors.Newf(errors.InvalidWorkflow, "some error"))
+
+				return NewJobHandler(logicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*JobHandler)
+				handler.CreateWorkflow(req, resp)
+
+				var response *models.ServerError
+				read(&response)
+
+				reporter.AssertEqual(resp.StatusCode(), http.StatusBadRequest)
+				reporter.AssertEqual(response.ErrorCode, int64(errors.InvalidWorkflow))
+			},
+		},
+	}
+
+	RunHandlerTestCases(t, testCases)
+}
+
+func TestGetWorkflow(t *testing.T) {
+	testCases := []HandlerTestCase{
+		{
+			Name: "Should return workflow from logic layer",
+			Request: &TestRequest{
+				Parameters: map[string]string{"id": "some_id"},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				logicMock := mock_logic.NewMockJobLogic(ctrl)
+				logicMock.EXPECT().
+					GetWorkflow("some_id").
+					Return(&models.Workflow{JobID: "some_id", Nodes: []models.WorkflowNodeStatus{{Name: "a"}}}, nil)
+
+				return NewJobHandler(logicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*JobHandler)
+				handler.GetWorkflow(req, resp)
+
+				var response *models.Workflow
+				read(&response)
+
+				reporter.AssertEqual(response.JobID, "some_id")
+				reporter.AssertEqual(response.Nodes[0].Name, "a")
+			},
+		},
+		{
+			Name:    "Should return MissingParameter error with no id",
+			Request: &TestRequest{},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				return NewJobHandler(mock_logic.NewMockJobLogic(ctrl), nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*JobHandler)
+				handler.GetWorkflow(req, resp)
+
+				var response *models.ServerError
+				read(&response)
+
+				reporter.AssertEqual(response.ErrorCode, int64(errors.MissingParameter))
+			},
+		},
+	}
+
+	RunHandlerTestCases(t, testCases)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/emicklei/go-restful"
 	"github.com/quintilesims/layer0/api/logic"
+	"github.com/quintilesims/layer0/common/db/tag_store"
 	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/types"
@@ -14,12 +15,14 @@ import (
 type LoadBalancerHandler struct {
 	LoadBalancerLogic logic.LoadBalancerLogic
 	JobLogic          logic.JobLogic
+	TagStore          tag_store.TagStore
 }
 
-func NewLoadBalancerHandler(loadBalancerLogic logic.LoadBalancerLogic, jobLogic logic.JobLogic) *LoadBalancerHandler {
+func NewLoadBalancerHandler(loadBalancerLogic logic.LoadBalancerLogic, jobLogic logic.JobLogic, tagStore tag_store.TagStore) *LoadBalancerHandler {
 	return &LoadBalancerHandler{
 		LoadBalancerLogic: loadBalancerLogic,
 		JobLogic:          jobLogic,
+		TagStore:          tagStore,
 	}
 }
 
@@ -29,6 +32,9 @@ func (l *LoadBalancerHandler) Routes() *restful.WebService {
 		Consumes(restful.MIME_JSON).
 		Produces(restful.MIME_JSON)
 
+	ifMatch := service.HeaderParameter("If-Match", "version of the entity the update is based on").
+		DataType("string")
+
 	id := service.PathParameter("id", "identifier of the load balancer").
 		DataType("string")
 
@@ -36,6 +42,7 @@ func (l *LoadBalancerHandler) Routes() *restful.WebService {
 		Filter(basicAuthenticate).
 		To(l.ListLoadBalancers).
 		Doc("List all LoadBalancers").
+		Param(service.QueryParameter("selector", SELECTOR_PARAM_DESCRIPTION).DataType("string")).
 		Returns(200, "OK", []models.LoadBalancer{}))
 
 	service.Route(service.GET("{id}").
@@ -48,10 +55,9 @@ func (l *LoadBalancerHandler) Routes() *restful.WebService {
 	service.Route(service.POST("/").
 		Filter(basicAuthenticate).
 		To(l.CreateLoadBalancer).
-		Doc("Create a new LoadBalancer").
+		Doc("Create a new LoadBalancer. The load balancer is created by a job").
 		Reads(models.CreateLoadBalancerRequest{}).
-		Returns(http.StatusCreated, "Created", models.LoadBalancer{}).
-		Writes(models.LoadBalancer{}))
+		Returns(http.StatusAccepted, "Accepted", nil))
 
 	service.Route(service.DELETE("{id}").
 		Filter(basicAuthenticate).
@@ -65,7 +71,9 @@ func (l *LoadBalancerHandler) Routes() *restful.WebService {
 		To(l.UpdateLoadBalancerPorts).
 		Reads(models.UpdateLoadBalancerPortsRequest{}).
 		Param(id).
+		Param(ifMatch).
 		Doc("Update load balancer ports").
+		Returns(http.StatusConflict, "Version conflict", models.ServerError{}).
 		Writes(models.LoadBalancer{}))
 
 	service.Route(service.PUT("{id}/healthcheck").
@@ -73,7 +81,9 @@ func (l *LoadBalancerHandler) Routes() *restful.WebService {
 		To(l.UpdateLoadBalancerHealthCheck).
 		Reads(models.UpdateLoadBalancerHealthCheckRequest{}).
 		Param(id).
+		Param(ifMatch).
 		Doc("Update load balancer health check").
+		Returns(http.StatusConflict, "Version conflict", models.ServerError{}).
 		Writes(models.LoadBalancer{}))
 
 	service.Route(service.PUT("{id}/idletimeout").
@@ -81,7 +91,9 @@ func (l *LoadBalancerHandler) Routes() *restful.WebService {
 		To(l.UpdateLoadBalancerIdleTimeout).
 		Reads(models.UpdateLoadBalancerIdleTimeoutRequest{}).
 		Param(id).
+		Param(ifMatch).
 		Doc("Update load balancer idle timeout").
+		Returns(http.StatusConflict, "Version conflict", models.ServerError{}).
 		Writes(models.LoadBalancer{}))
 
 	service.Route(service.PUT("{id}/crosszone").
@@ -89,20 +101,35 @@ func (l *LoadBalancerHandler) Routes() *restful.WebService {
 		To(l.UpdateLoadBalancerCrossZone).
 		Reads(models.UpdateLoadBalancerCrossZoneRequest{}).
 		Param(id).
+		Param(ifMatch).
 		Doc("Update load balancer cross-zone load balancing").
+		Returns(http.StatusConflict, "Version conflict", models.ServerError{}).
 		Writes(models.LoadBalancer{}))
 
 	return service
 }
 
 func (l *LoadBalancerHandler) ListLoadBalancers(request *restful.Request, response *restful.Response) {
+	match, err := selectEntities(l.TagStore, request, "load_balancer")
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
 	loadbalancers, err := l.LoadBalancerLogic.ListLoadBalancers()
 	if err != nil {
 		ReturnError(response, err)
 		return
 	}
 
-	response.WriteAsJson(loadbalancers)
+	selected := loadbalancers[:0]
+	for _, summary := range loadbalancers {
+		if match(summary.LoadBalancerID) {
+			selected = append(selected, summary)
+		}
+	}
+
+	response.WriteAsJson(selected)
 }
 
 func (l *LoadBalancerHandler) GetLoadBalancer(request *restful.Request, response *restful.Response) {
@@ -119,6 +146,7 @@ func (l *LoadBalancerHandler) GetLoadBalancer(request *restful.Request, response
 		return
 	}
 
+	WriteETagHeader(response, loadbalancer.Version)
 	response.WriteAsJson(loadbalancer)
 }
 
@@ -146,13 +174,13 @@ func (l *LoadBalancerHandler) CreateLoadBalancer(request *restful.Request, respo
 		return
 	}
 
-	loadBalancer, err := l.LoadBalancerLogic.CreateLoadBalancer(req)
+	job, err := l.JobLogic.CreateJob(types.CreateLoadBalancerJob, req)
 	if err != nil {
 		ReturnError(response, err)
 		return
 	}
 
-	response.WriteAsJson(loadBalancer)
+	WriteJobResponse(response, job.JobID)
 }
 
 func (l *LoadBalancerHandler) UpdateLoadBalancerPorts(request *restful.Request, response *restful.Response) {
@@ -169,12 +197,19 @@ func (l *LoadBalancerHandler) UpdateLoadBalancerPorts(request *restful.Request,
 		return
 	}
 
-	loadBalancer, err := l.LoadBalancerLogic.UpdateLoadBalancerPorts(id, req.Ports)
+	version, err := IfMatchVersion(request)
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	loadBalancer, err := l.LoadBalancerLogic.UpdateLoadBalancerPorts(id, req.Ports, version)
 	if err != nil {
 		ReturnError(response, err)
 		return
 	}
 
+	WriteETagHeader(response, loadBalancer.Version)
 	response.WriteAsJson(loadBalancer)
 }
 
@@ -192,12 +227,19 @@ func (l *LoadBalancerHandler) UpdateLoadBalancerHealthCheck(request *restful.Req
 		return
 	}
 
-	loadBalancer, err := l.LoadBalancerLogic.UpdateLoadBalancerHealthCheck(id, req.HealthCheck)
+	version, err := IfMatchVersion(request)
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	loadBalancer, err := l.LoadBalancerLogic.UpdateLoadBalancerHealthCheck(id, req.HealthCheck, version)
 	if err != nil {
 		ReturnError(response, err)
 		return
 	}
 
+	WriteETagHeader(response, loadBalancer.Version)
 	response.WriteAsJson(loadBalancer)
 }
 
@@ -215,12 +257,19 @@ func (l *LoadBalancerHandler) UpdateLoadBalancerIdleTimeout(request *restful.Req
 		return
 	}
 
-	loadBalancer, err := l.LoadBalancerLogic.UpdateLoadBalancerIdleTimeout(id, req.IdleTimeout)
+	version, err := IfMatchVersion(request)
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	loadBalancer, err := l.LoadBalancerLogic.UpdateLoadBalancerIdleTimeout(id, req.IdleTimeout, version)
 	if err != nil {
 		ReturnError(response, err)
 		return
 	}
 
+	WriteETagHeader(response, loadBalancer.Version)
 	response.WriteAsJson(loadBalancer)
 }
 
@@ -238,11 +287,18 @@ func (l *LoadBalancerHandler) UpdateLoadBalancerCrossZone(request *restful.Reque
 		return
 	}
 
-	loadBalancer, err := l.LoadBalancerLogic.UpdateLoadBalancerCrossZone(id, req.CrossZone)
+	version, err := IfMatchVersion(request)
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	loadBalancer, err := l.LoadBalancerLogic.UpdateLoadBalancerCrossZone(id, req.CrossZone, version)
 	if err != nil {
 		ReturnError(response, err)
 		return
 	}
 
+	WriteETagHeader(response, loadBalancer.Version)
 	response.WriteAsJson(loadBalancer)
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/emicklei/go-restful"
 	"github.com/golang/mock/gomock"
 	"github.com/quintilesims/layer0/api/logic/mock_logic"
+	"github.com/quintilesims/layer0/common/db/tag_store"
 	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/testutils"
@@ -33,7 +34,7 @@ func TestListLoadBalancers(t *testing.T) {
 					Return(loadBalancers, nil)
 
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
-				return NewLoadBalancerHandler(logicMock, mockJob)
+				return NewLoadBalancerHandler(logicMock, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*LoadBalancerHandler)
@@ -55,7 +56,7 @@ func TestListLoadBalancers(t *testing.T) {
 					Return(nil, errors.Newf(errors.UnexpectedError, "some error"))
 
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
-				return NewLoadBalancerHandler(logicMock, mockJob)
+				return NewLoadBalancerHandler(logicMock, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*LoadBalancerHandler)
@@ -90,7 +91,7 @@ func TestGetLoadBalancer(t *testing.T) {
 					Return(loadBalancer, nil)
 
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
-				return NewLoadBalancerHandler(logicMock, mockJob)
+				return NewLoadBalancerHandler(logicMock, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*LoadBalancerHandler)
@@ -109,7 +110,7 @@ func TestGetLoadBalancer(t *testing.T) {
 					Return(loadBalancer, nil)
 
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
-				return NewLoadBalancerHandler(logicMock, mockJob)
+				return NewLoadBalancerHandler(logicMock, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*LoadBalancerHandler)
@@ -127,7 +128,7 @@ func TestGetLoadBalancer(t *testing.T) {
 			Setup: func(ctrl *gomock.Controller) interface{} {
 				logicMock := mock_logic.NewMockLoadBalancerLogic(ctrl)
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
-				return NewLoadBalancerHandler(logicMock, mockJob)
+				return NewLoadBalancerHandler(logicMock, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*LoadBalancerHandler)
@@ -151,7 +152,7 @@ func TestGetLoadBalancer(t *testing.T) {
 					Return(nil, errors.Newf(errors.UnexpectedError, "some error"))
 
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
-				return NewLoadBalancerHandler(logicMock, mockJob)
+				return NewLoadBalancerHandler(logicMock, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*LoadBalancerHandler)
@@ -178,37 +179,42 @@ func TestCreateLoadBalancer(t *testing.T) {
 
 	testCases := []HandlerTestCase{
 		{
-			Name: "Should call logic:CreateLoadBalancer with correct params",
+			Name: "Should call CreateJob with correct params",
 			Request: &TestRequest{
 				Body: request,
 			},
 			Setup: func(ctrl *gomock.Controller) interface{} {
 				mockLB := mock_logic.NewMockLoadBalancerLogic(ctrl)
-				mockLB.EXPECT().
-					CreateLoadBalancer(request).
-					Return(nil, nil)
 
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
-				return NewLoadBalancerHandler(mockLB, mockJob)
+				mockJob.EXPECT().
+					CreateJob(types.CreateLoadBalancerJob, request).
+					Return(&models.Job{JobID: "job_id"}, nil)
+
+				return NewLoadBalancerHandler(mockLB, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*LoadBalancerHandler)
 				handler.CreateLoadBalancer(req, resp)
+
+				reporter.AssertEqual(resp.StatusCode(), 202)
+				reporter.AssertInSlice("job_id", resp.Header()["X-Jobid"])
 			},
 		},
 		{
-			Name: "Should propagate CreateLoadBalancer error",
+			Name: "Should propagate CreateJob error",
 			Request: &TestRequest{
 				Body: request,
 			},
 			Setup: func(ctrl *gomock.Controller) interface{} {
 				mockLB := mock_logic.NewMockLoadBalancerLogic(ctrl)
-				mockLB.EXPECT().
-					CreateLoadBalancer(request).
-					Return(nil, errors.Newf(errors.UnexpectedError, "some error"))
 
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
-				return NewLoadBalancerHandler(mockLB, mockJob)
+				mockJob.EXPECT().
+					CreateJob(gomock.Any(), gomock.Any()).
+					Return(nil, errors.Newf(errors.UnexpectedError, "some error"))
+
+				return NewLoadBalancerHandler(mockLB, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*LoadBalancerHandler)
@@ -240,7 +246,7 @@ func TestDeleteLoadBalancer(t *testing.T) {
 					CreateJob(types.DeleteLoadBalancerJob, "some_id").
 					Return(&models.Job{}, nil)
 
-				return NewLoadBalancerHandler(mockLB, MockJob)
+				return NewLoadBalancerHandler(mockLB, MockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*LoadBalancerHandler)
@@ -260,7 +266,7 @@ func TestDeleteLoadBalancer(t *testing.T) {
 					CreateJob(gomock.Any(), gomock.Any()).
 					Return(&models.Job{JobID: "job_id"}, nil)
 
-				return NewLoadBalancerHandler(mockLB, MockJob)
+				return NewLoadBalancerHandler(mockLB, MockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*LoadBalancerHandler)
@@ -284,7 +290,7 @@ func TestDeleteLoadBalancer(t *testing.T) {
 					CreateJob(gomock.Any(), gomock.Any()).
 					Return(nil, errors.Newf(errors.UnexpectedError, "some error"))
 
-				return NewLoadBalancerHandler(mockLB, MockJob)
+				return NewLoadBalancerHandler(mockLB, MockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*LoadBalancerHandler)
@@ -303,7 +309,7 @@ func TestDeleteLoadBalancer(t *testing.T) {
 				mockLB := mock_logic.NewMockLoadBalancerLogic(ctrl)
 				MockJob := mock_logic.NewMockJobLogic(ctrl)
 
-				return NewLoadBalancerHandler(mockLB, MockJob)
+				return NewLoadBalancerHandler(mockLB, MockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*LoadBalancerHandler)
@@ -343,9 +349,10 @@ func TestUpdateLoadBalancerHealthCheck(t *testing.T) {
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
 
 				mockLogic.EXPECT().
-					UpdateLoadBalancerHealthCheck("some_id", request.HealthCheck)
+					UpdateLoadBalancerHealthCheck("some_id", request.HealthCheck, tag_store.AnyVersion).
+					Return(&models.LoadBalancer{}, nil)
 
-				return NewLoadBalancerHandler(mockLogic, mockJob)
+				return NewLoadBalancerHandler(mockLogic, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*LoadBalancerHandler)
@@ -372,9 +379,10 @@ func TestUpdateLoadBalancerIdleTimeout(t *testing.T) {
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
 
 				mockLogic.EXPECT().
-					UpdateLoadBalancerIdleTimeout("some_id", request.IdleTimeout)
+					UpdateLoadBalancerIdleTimeout("some_id", request.IdleTimeout, tag_store.AnyVersion).
+					Return(&models.LoadBalancer{}, nil)
 
-				return NewLoadBalancerHandler(mockLogic, mockJob)
+				return NewLoadBalancerHandler(mockLogic, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*LoadBalancerHandler)
@@ -401,9 +409,10 @@ func TestUpdateLoadBalancerCrossZone(t *testing.T) {
 				mockJob := mock_logic.NewMockJobLogic(ctrl)
 
 				mockLogic.EXPECT().
-					UpdateLoadBalancerCrossZone("some_id", request.CrossZone)
+					UpdateLoadBalancerCrossZone("some_id", request.CrossZone, tag_store.AnyVersion).
+					Return(&models.LoadBalancer{}, nil)
 
-				return NewLoadBalancerHandler(mockLogic, mockJob)
+				return NewLoadBalancerHandler(mockLogic, mockJob, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*LoadBalancerHandler)
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"github.com/emicklei/go-restful"
+	"github.com/quintilesims/layer0/api/openapi"
+)
+
+type OpenAPIHandler struct {
+	Document *openapi.Document
+}
+
+func NewOpenAPIHandler(document *openapi.Document) *OpenAPIHandler {
+	return &OpenAPIHandler{
+		Document: document,
+	}
+}
+
+func (this *OpenAPIHandler) Routes() *restful.WebService {
+	service := new(restful.WebService)
+	service.Path("/openapi.json").
+		Produces(restful.MIME_JSON)
+
+	service.Route(service.GET("").
+		To(this.GetDocument).
+		Doc("Returns the OpenAPI 3 document describing this API"))
+
+	return service
+}
+
+func (this *OpenAPIHandler) GetDocument(request *restful.Request, response *restful.Response) {
+	response.WriteAsJson(this.Document)
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"github.com/emicklei/go-restful"
+	"github.com/quintilesims/layer0/common/db/tag_store"
+	"github.com/quintilesims/layer0/common/selector"
+)
+
+const SELECTOR_PARAM_DESCRIPTION = "only list entities whose tags match the selector, e.g. 'team=payments,tier!=dev,region in (us,eu)'"
+
+// selectEntities evaluates the 'selector' query parameter of the request against the tags
+// of entityType. The returned function reports whether the entity with the given id matches;
+// if the request has no selector, every entity matches.
+func selectEntities(store tag_store.TagStore, request *restful.Request, entityType string) (func(entityID string) bool, error) {
+	expression := request.QueryParameter("selector")
+	if expression == "" {
+		return func(string) bool { return true }, nil
+	}
+
+	sel, err := selector.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := store.SelectBySelector(entityType, sel)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := map[string]bool{}
+	for _, tag := range tags {
+		matches[tag.EntityID] = true
+	}
+
+	return func(entityID string) bool { return matches[entityID] }, nil
+}
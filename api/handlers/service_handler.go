@@ -7,6 +7,7 @@ import (
 
 	"github.com/emicklei/go-restful"
 	"github.com/quintilesims/layer0/api/logic"
+	"github.com/quintilesims/layer0/common/db/tag_store"
 	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/types"
@@ -15,12 +16,14 @@ import (
 type ServiceHandler struct {
 	ServiceLogic logic.ServiceLogic
 	JobLogic     logic.JobLogic
+	TagStore     tag_store.TagStore
 }
 
-func NewServiceHandler(serviceLogic logic.ServiceLogic, jobLogic logic.JobLogic) *ServiceHandler {
+func NewServiceHandler(serviceLogic logic.ServiceLogic, jobLogic logic.JobLogic, tagStore tag_store.TagStore) *ServiceHandler {
 	return &ServiceHandler{
 		ServiceLogic: serviceLogic,
 		JobLogic:     jobLogic,
+		TagStore:     tagStore,
 	}
 }
 
@@ -30,6 +33,9 @@ func (this *ServiceHandler) Routes() *restful.WebService {
 		Consumes(restful.MIME_JSON).
 		Produces(restful.MIME_JSON)
 
+	ifMatch := service.HeaderParameter("If-Match", "version of the entity the update is based on").
+		DataType("string")
+
 	id := service.PathParameter("id", "identifier of the service").
 		DataType("string")
 
@@ -37,6 +43,7 @@ func (this *ServiceHandler) Routes() *restful.WebService {
 		Filter(basicAuthenticate).
 		To(this.ListServices).
 		Doc("List all services").
+		Param(service.QueryParameter("selector", SELECTOR_PARAM_DESCRIPTION).DataType("string")).
 		Returns(200, "OK", []models.Service{}))
 
 	service.Route(service.GET("/{id}").
@@ -65,22 +72,24 @@ func (this *ServiceHandler) Routes() *restful.WebService {
 	service.Route(service.PUT("/{id}/scale").
 		Filter(basicAuthenticate).
 		To(this.ScaleService).
-		Doc("Scale a service").
+		Doc("Scale a service. The service is updated by a job").
 		Reads(models.ScaleServiceRequest{}).
 		Param(id).
-		Returns(http.StatusAccepted, "Scaling", models.Service{}).
+		Param(ifMatch).
+		Returns(http.StatusAccepted, "Accepted", nil).
 		Returns(400, "Invalid request", models.ServerError{}).
-		Writes(models.Service{}))
+		Returns(http.StatusConflict, "Version conflict", models.ServerError{}))
 
 	service.Route(service.PUT("/{id}/deploy").
 		Filter(basicAuthenticate).
 		To(this.UpdateService).
-		Doc("Run a new deploy on a service").
+		Doc("Run a new deploy on a service. The service is updated by a job").
 		Reads(models.UpdateServiceRequest{}).
 		Param(id).
-		Returns(http.StatusAccepted, "Scaling", models.Service{}).
+		Param(ifMatch).
+		Returns(http.StatusAccepted, "Accepted", nil).
 		Returns(400, "Invalid request", models.ServerError{}).
-		Writes(models.Service{}))
+		Returns(http.StatusConflict, "Version conflict", models.ServerError{}))
 
 	service.Route(service.GET("/{id}/logs").
 		Filter(basicAuthenticate).
@@ -96,13 +105,26 @@ func (this *ServiceHandler) Routes() *restful.WebService {
 }
 
 func (this *ServiceHandler) ListServices(request *restful.Request, response *restful.Response) {
+	match, err := selectEntities(this.TagStore, request, "service")
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
 	services, err := this.ServiceLogic.ListServices()
 	if err != nil {
 		ReturnError(response, err)
 		return
 	}
 
-	response.WriteAsJson(services)
+	selected := services[:0]
+	for _, summary := range services {
+		if match(summary.ServiceID) {
+			selected = append(selected, summary)
+		}
+	}
+
+	response.WriteAsJson(selected)
 }
 
 func (this *ServiceHandler) DeleteService(request *restful.Request, response *restful.Response) {
@@ -136,6 +158,7 @@ func (this *ServiceHandler) GetService(request *restful.Request, response *restf
 		return
 	}
 
+	WriteETagHeader(response, service.Version)
 	response.WriteAsJson(service)
 }
 
@@ -169,13 +192,30 @@ func (this *ServiceHandler) ScaleService(request *restful.Request, response *res
 		return
 	}
 
-	service, err := this.ServiceLogic.ScaleService(serviceID, int(req.DesiredCount))
+	version, err := IfMatchVersion(request)
 	if err != nil {
 		ReturnError(response, err)
 		return
 	}
 
-	response.WriteAsJson(service)
+	if err := checkServiceVersion(this.ServiceLogic, serviceID, version); err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	jobRequest := models.ScaleServiceJobRequest{
+		ServiceID:    serviceID,
+		DesiredCount: req.DesiredCount,
+		Version:      version,
+	}
+
+	job, err := this.JobLogic.CreateJob(types.ScaleServiceJob, jobRequest)
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	WriteJobResponse(response, job.JobID)
 }
 
 func (this *ServiceHandler) UpdateService(request *restful.Request, response *restful.Response) {
@@ -192,13 +232,45 @@ func (this *ServiceHandler) UpdateService(request *restful.Request, response *re
 		return
 	}
 
-	service, err := this.ServiceLogic.UpdateService(serviceID, req)
+	version, err := IfMatchVersion(request)
 	if err != nil {
 		ReturnError(response, err)
 		return
 	}
 
-	response.WriteAsJson(service)
+	if err := checkServiceVersion(this.ServiceLogic, serviceID, version); err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	jobRequest := models.UpdateServiceJobRequest{
+		ServiceID: serviceID,
+		DeployID:  req.DeployID,
+		Version:   version,
+	}
+
+	job, err := this.JobLogic.CreateJob(types.UpdateServiceJob, jobRequest)
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
+	WriteJobResponse(response, job.JobID)
+}
+
+// checkServiceVersion fails fast if the service doesn't exist or has been modified.
+// The job checks the version again when it updates the service
+func checkServiceVersion(serviceLogic logic.ServiceLogic, serviceID string, version int64) error {
+	service, err := serviceLogic.GetService(serviceID)
+	if err != nil {
+		return err
+	}
+
+	if version != tag_store.AnyVersion && version != service.Version {
+		return errors.Newf(errors.EntityConflict, "service '%s' was modified by another request", serviceID)
+	}
+
+	return nil
 }
 
 func (this *ServiceHandler) GetServiceLogs(request *restful.Request, response *restful.Response) {
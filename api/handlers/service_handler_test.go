@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/emicklei/go-restful"
 	"github.com/golang/mock/gomock"
 	"github.com/quintilesims/layer0/api/logic/mock_logic"
+	"github.com/quintilesims/layer0/common/db/tag_store"
 	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/testutils"
@@ -34,7 +36,7 @@ func TestListServices(t *testing.T) {
 
 				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
 
-				return NewServiceHandler(svcLogicMock, jobLogicMock)
+				return NewServiceHandler(svcLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*ServiceHandler)
@@ -46,6 +48,58 @@ func TestListServices(t *testing.T) {
 				reporter.AssertEqual(response, services)
 			},
 		},
+		{
+			Name: "Should only return services matching the selector",
+			Request: &TestRequest{
+				Query: "selector=team%3Dpayments",
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				svcLogicMock := mock_logic.NewMockServiceLogic(ctrl)
+				svcLogicMock.EXPECT().
+					ListServices().
+					Return(services, nil)
+
+				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
+
+				tagStore := getTestTagStore(t, models.Tags{
+					{EntityID: "some_id_1", EntityType: "service", Key: "team", Value: "search"},
+					{EntityID: "some_id_2", EntityType: "service", Key: "team", Value: "payments"},
+				})
+
+				return NewServiceHandler(svcLogicMock, jobLogicMock, tagStore)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*ServiceHandler)
+				handler.ListServices(req, resp)
+
+				var response []models.ServiceSummary
+				read(&response)
+
+				reporter.AssertEqual(response, services[1:])
+			},
+		},
+		{
+			Name: "Should return error for invalid selector",
+			Request: &TestRequest{
+				Query: "selector=team+in+payments",
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				svcLogicMock := mock_logic.NewMockServiceLogic(ctrl)
+				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
+
+				return NewServiceHandler(svcLogicMock, jobLogicMock, tag_store.NewMemoryTagStore())
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*ServiceHandler)
+				handler.ListServices(req, resp)
+
+				var response *models.ServerError
+				read(&response)
+
+				reporter.AssertEqual(resp.StatusCode(), http.StatusBadRequest)
+				reporter.AssertEqual(response.ErrorCode, int64(errors.InvalidSelector))
+			},
+		},
 		{
 			Name:    "Should propagate ListServices error",
 			Request: &TestRequest{},
@@ -57,7 +111,7 @@ func TestListServices(t *testing.T) {
 
 				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
 
-				return NewServiceHandler(svcLogicMock, jobLogicMock)
+				return NewServiceHandler(svcLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*ServiceHandler)
@@ -93,7 +147,7 @@ func TestGetService(t *testing.T) {
 
 				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
 
-				return NewServiceHandler(svcLogicMock, jobLogicMock)
+				return NewServiceHandler(svcLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*ServiceHandler)
@@ -113,7 +167,7 @@ func TestGetService(t *testing.T) {
 
 				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
 
-				return NewServiceHandler(svcLogicMock, jobLogicMock)
+				return NewServiceHandler(svcLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*ServiceHandler)
@@ -131,7 +185,7 @@ func TestGetService(t *testing.T) {
 			Setup: func(ctrl *gomock.Controller) interface{} {
 				svcLogicMock := mock_logic.NewMockServiceLogic(ctrl)
 				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
-				return NewServiceHandler(svcLogicMock, jobLogicMock)
+				return NewServiceHandler(svcLogicMock, jobLogicMock, nil)
 
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
@@ -157,7 +211,7 @@ func TestGetService(t *testing.T) {
 
 				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
 
-				return NewServiceHandler(svcLogicMock, jobLogicMock)
+				return NewServiceHandler(svcLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*ServiceHandler)
@@ -189,7 +243,7 @@ func TestDeleteService(t *testing.T) {
 					CreateJob(types.DeleteServiceJob, "some_id").
 					Return(&models.Job{}, nil)
 
-				return NewServiceHandler(svcLogicMock, jobLogicMock)
+				return NewServiceHandler(svcLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*ServiceHandler)
@@ -209,7 +263,7 @@ func TestDeleteService(t *testing.T) {
 					CreateJob(gomock.Any(), gomock.Any()).
 					Return(&models.Job{JobID: "job_id"}, nil)
 
-				return NewServiceHandler(svcLogicMock, jobLogicMock)
+				return NewServiceHandler(svcLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*ServiceHandler)
@@ -233,7 +287,7 @@ func TestDeleteService(t *testing.T) {
 					CreateJob(gomock.Any(), gomock.Any()).
 					Return(nil, errors.Newf(errors.UnexpectedError, "some error"))
 
-				return NewServiceHandler(svcLogicMock, jobLogicMock)
+				return NewServiceHandler(svcLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*ServiceHandler)
@@ -252,7 +306,7 @@ func TestDeleteService(t *testing.T) {
 				svcLogicMock := mock_logic.NewMockServiceLogic(ctrl)
 				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
 
-				return NewServiceHandler(svcLogicMock, jobLogicMock)
+				return NewServiceHandler(svcLogicMock, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*ServiceHandler)
@@ -290,7 +344,7 @@ func TestCreateService(t *testing.T) {
 					Return(&models.Service{}, nil)
 
 				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
-				return NewServiceHandler(mockService, jobLogicMock)
+				return NewServiceHandler(mockService, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*ServiceHandler)
@@ -310,7 +364,7 @@ func TestCreateService(t *testing.T) {
 					Return(nil, errors.Newf(errors.UnexpectedError, "some error"))
 
 				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
-				return NewServiceHandler(mockService, jobLogicMock)
+				return NewServiceHandler(mockService, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*ServiceHandler)
@@ -334,41 +388,109 @@ func TestScaleService(t *testing.T) {
 
 	testCases := []HandlerTestCase{
 		{
-			Name: "Should call ScaleService with correct params",
+			Name: "Should call CreateJob with correct params",
 			Request: &TestRequest{
 				Body:       request,
 				Parameters: map[string]string{"id": "some_id"},
+				Headers:    map[string]string{"If-Match": "\"4\""},
 			},
 			Setup: func(ctrl *gomock.Controller) interface{} {
 				mockService := mock_logic.NewMockServiceLogic(ctrl)
+				mockService.EXPECT().
+					GetService("some_id").
+					Return(&models.Service{ServiceID: "some_id", Version: 4}, nil)
+
+				jobRequest := models.ScaleServiceJobRequest{
+					ServiceID:    "some_id",
+					DesiredCount: 2,
+					Version:      4,
+				}
+
+				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
+				jobLogicMock.EXPECT().
+					CreateJob(types.ScaleServiceJob, jobRequest).
+					Return(&models.Job{JobID: "job_id"}, nil)
 
+				return NewServiceHandler(mockService, jobLogicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*ServiceHandler)
+				handler.ScaleService(req, resp)
+
+				reporter.AssertEqual(resp.StatusCode(), 202)
+				reporter.AssertInSlice("job_id", resp.Header()["X-Jobid"])
+			},
+		},
+		{
+			Name: "Should return conflict on stale version",
+			Request: &TestRequest{
+				Body:       request,
+				Parameters: map[string]string{"id": "some_id"},
+				Headers:    map[string]string{"If-Match": "\"4\""},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				mockService := mock_logic.NewMockServiceLogic(ctrl)
 				mockService.EXPECT().
-					ScaleService("some_id", 2).
-					Return(&models.Service{}, nil)
+					GetService("some_id").
+					Return(&models.Service{ServiceID: "some_id", Version: 5}, nil)
 
 				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
-				return NewServiceHandler(mockService, jobLogicMock)
+				return NewServiceHandler(mockService, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*ServiceHandler)
 				handler.ScaleService(req, resp)
+
+				var response *models.ServerError
+				read(&response)
+
+				reporter.AssertEqual(resp.StatusCode(), 409)
+				reporter.AssertEqual(int64(errors.EntityConflict), response.ErrorCode)
 			},
 		},
 		{
-			Name: "Should propagate ScaleService error",
+			Name: "Should propagate GetService error",
 			Request: &TestRequest{
 				Body:       request,
 				Parameters: map[string]string{"id": "some_id"},
 			},
 			Setup: func(ctrl *gomock.Controller) interface{} {
 				mockService := mock_logic.NewMockServiceLogic(ctrl)
+				mockService.EXPECT().
+					GetService(gomock.Any()).
+					Return(nil, errors.Newf(errors.ServiceDoesNotExist, "some error"))
+
+				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
+				return NewServiceHandler(mockService, jobLogicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*ServiceHandler)
+				handler.ScaleService(req, resp)
+
+				var response *models.ServerError
+				read(&response)
 
+				reporter.AssertEqual(int64(errors.ServiceDoesNotExist), response.ErrorCode)
+			},
+		},
+		{
+			Name: "Should propagate CreateJob error",
+			Request: &TestRequest{
+				Body:       request,
+				Parameters: map[string]string{"id": "some_id"},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				mockService := mock_logic.NewMockServiceLogic(ctrl)
 				mockService.EXPECT().
-					ScaleService(gomock.Any(), gomock.Any()).
-					Return(nil, errors.Newf(errors.UnexpectedError, "some error"))
+					GetService(gomock.Any()).
+					Return(&models.Service{}, nil)
 
 				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
-				return NewServiceHandler(mockService, jobLogicMock)
+				jobLogicMock.EXPECT().
+					CreateJob(gomock.Any(), gomock.Any()).
+					Return(nil, errors.Newf(errors.UnexpectedError, "some error"))
+
+				return NewServiceHandler(mockService, jobLogicMock, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*ServiceHandler)
@@ -384,3 +506,74 @@ func TestScaleService(t *testing.T) {
 
 	RunHandlerTestCases(t, testCases)
 }
+
+func TestUpdateService(t *testing.T) {
+	request := models.UpdateServiceRequest{
+		DeployID: "dply_id",
+	}
+
+	testCases := []HandlerTestCase{
+		{
+			Name: "Should call CreateJob with correct params",
+			Request: &TestRequest{
+				Body:       request,
+				Parameters: map[string]string{"id": "some_id"},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				mockService := mock_logic.NewMockServiceLogic(ctrl)
+				mockService.EXPECT().
+					GetService("some_id").
+					Return(&models.Service{ServiceID: "some_id", Version: 4}, nil)
+
+				jobRequest := models.UpdateServiceJobRequest{
+					ServiceID: "some_id",
+					DeployID:  "dply_id",
+					Version:   tag_store.AnyVersion,
+				}
+
+				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
+				jobLogicMock.EXPECT().
+					CreateJob(types.UpdateServiceJob, jobRequest).
+					Return(&models.Job{JobID: "job_id"}, nil)
+
+				return NewServiceHandler(mockService, jobLogicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*ServiceHandler)
+				handler.UpdateService(req, resp)
+
+				reporter.AssertEqual(resp.StatusCode(), 202)
+				reporter.AssertInSlice("/job/job_id", resp.Header()["Location"])
+			},
+		},
+		{
+			Name: "Should return conflict on stale version",
+			Request: &TestRequest{
+				Body:       request,
+				Parameters: map[string]string{"id": "some_id"},
+				Headers:    map[string]string{"If-Match": "\"4\""},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				mockService := mock_logic.NewMockServiceLogic(ctrl)
+				mockService.EXPECT().
+					GetService("some_id").
+					Return(&models.Service{ServiceID: "some_id", Version: 5}, nil)
+
+				jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
+				return NewServiceHandler(mockService, jobLogicMock, nil)
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*ServiceHandler)
+				handler.UpdateService(req, resp)
+
+				var response *models.ServerError
+				read(&response)
+
+				reporter.AssertEqual(resp.StatusCode(), 409)
+				reporter.AssertEqual(int64(errors.EntityConflict), response.ErrorCode)
+			},
+		},
+	}
+
+	RunHandlerTestCases(t, testCases)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/quintilesims/layer0/common/db/tag_store"
 	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/selector"
 )
 
 type TagHandler struct {
@@ -38,6 +39,7 @@ func (t *TagHandler) Routes() *restful.WebService {
 		Param(service.QueryParameter("fuzz", "Require the prefix of the EntityID field or 'name' tag match the specified parameter").DataType("string")).
 		Param(service.QueryParameter("version", "Require the 'version' tag match the specified parameter. If 'latest' is used, only the latest will be returned").DataType("string")).
 		Param(service.QueryParameter("environment_id", "Require the 'environment_id' tag match the specified parameter").DataType("string")).
+		Param(service.QueryParameter("selector", "Require the entity's tags match the specified selector, e.g. 'team=payments,tier!=dev'").DataType("string")).
 		Returns(200, "OK", []models.EntityWithTags{}))
 
 	service.Route(service.POST("/").
@@ -73,6 +75,7 @@ func (t *TagHandler) FindTags(request *restful.Request, response *restful.Respon
 	var entityID string
 	var fuzz string
 	var latestVersion bool
+	var sel selector.Selector
 
 	// break out special filter params so we don't filter
 	// them by tag.Key and tag.Value
@@ -91,6 +94,17 @@ func (t *TagHandler) FindTags(request *restful.Request, response *restful.Respon
 		delete(params, "fuzz")
 	}
 
+	if val, ok := params["selector"]; ok {
+		parsed, err := selector.Parse(val)
+		if err != nil {
+			ReturnError(response, err)
+			return
+		}
+
+		sel = parsed
+		delete(params, "selector")
+	}
+
 	if val, ok := params["version"]; ok && val == "latest" {
 		latestVersion = true
 		delete(params, "version")
@@ -103,9 +117,19 @@ func (t *TagHandler) FindTags(request *restful.Request, response *restful.Respon
 	}
 
 	var query func() (models.Tags, error)
-	if entityID == "" {
+	switch {
+	case sel != nil:
+		query = func() (models.Tags, error) {
+			tags, err := t.TagStore.SelectBySelector(entityType, sel)
+			if err != nil || entityID == "" {
+				return tags, err
+			}
+
+			return tags.WithID(entityID), nil
+		}
+	case entityID == "":
 		query = func() (models.Tags, error) { return t.TagStore.SelectByType(entityType) }
-	} else {
+	default:
 		query = func() (models.Tags, error) { return t.TagStore.SelectByTypeAndID(entityType, entityID) }
 	}
 
@@ -151,6 +151,37 @@ func TestFindTags(t *testing.T) {
 				r.AssertEqual(tags[0].EntityID, "d2")
 			},
 		},
+		{
+			Name: "type=service&selector=environment_id in (e2,e3)",
+			Request: &TestRequest{
+				Query: "type=service&selector=environment_id+in+%28e2%2Ce3%29",
+			},
+			Run: func(r *testutils.Reporter, _ interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler.FindTags(req, resp)
+
+				var tags []models.EntityWithTags
+				read(&tags)
+
+				r.AssertEqual(len(tags), 1)
+				r.AssertEqual(tags[0].EntityType, "service")
+				r.AssertEqual(tags[0].EntityID, "s2")
+				r.AssertEqual(len(tags[0].Tags), 2)
+			},
+		},
+		{
+			Name: "type=service&id=s1&selector=environment_id=e2",
+			Request: &TestRequest{
+				Query: "type=service&id=s1&selector=environment_id%3De2",
+			},
+			Run: func(r *testutils.Reporter, _ interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler.FindTags(req, resp)
+
+				var tags []models.EntityWithTags
+				read(&tags)
+
+				r.AssertEqual(len(tags), 0)
+			},
+		},
 	}
 
 	RunHandlerTestCases(t, cases)
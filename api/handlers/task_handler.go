@@ -7,6 +7,7 @@ import (
 
 	"github.com/emicklei/go-restful"
 	"github.com/quintilesims/layer0/api/logic"
+	"github.com/quintilesims/layer0/common/db/tag_store"
 	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/types"
@@ -15,12 +16,14 @@ import (
 type TaskHandler struct {
 	TaskLogic logic.TaskLogic
 	JobLogic  logic.JobLogic
+	TagStore  tag_store.TagStore
 }
 
-func NewTaskHandler(taskLogic logic.TaskLogic, jobLogic logic.JobLogic) *TaskHandler {
+func NewTaskHandler(taskLogic logic.TaskLogic, jobLogic logic.JobLogic, tagStore tag_store.TagStore) *TaskHandler {
 	return &TaskHandler{
 		TaskLogic: taskLogic,
 		JobLogic:  jobLogic,
+		TagStore:  tagStore,
 	}
 }
 
@@ -37,6 +40,7 @@ func (this *TaskHandler) Routes() *restful.WebService {
 		Filter(basicAuthenticate).
 		To(this.ListTasks).
 		Doc("List all tasks").
+		Param(service.QueryParameter("selector", SELECTOR_PARAM_DESCRIPTION).DataType("string")).
 		Returns(200, "OK", []models.Task{}))
 
 	service.Route(service.GET("/{id}").
@@ -76,13 +80,26 @@ func (this *TaskHandler) Routes() *restful.WebService {
 }
 
 func (this *TaskHandler) ListTasks(request *restful.Request, response *restful.Response) {
+	match, err := selectEntities(this.TagStore, request, "task")
+	if err != nil {
+		ReturnError(response, err)
+		return
+	}
+
 	tasks, err := this.TaskLogic.ListTasks()
 	if err != nil {
 		ReturnError(response, err)
 		return
 	}
 
-	response.WriteAsJson(tasks)
+	selected := tasks[:0]
+	for _, summary := range tasks {
+		if match(summary.TaskID) {
+			selected = append(selected, summary)
+		}
+	}
+
+	response.WriteAsJson(selected)
 }
 
 func (this *TaskHandler) DeleteTask(request *restful.Request, response *restful.Response) {
@@ -32,7 +32,7 @@ func TestListTasks(t *testing.T) {
 					ListTasks().
 					Return(tasks, nil)
 
-				return NewTaskHandler(logicMock, nil)
+				return NewTaskHandler(logicMock, nil, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*TaskHandler)
@@ -53,7 +53,7 @@ func TestListTasks(t *testing.T) {
 					ListTasks().
 					Return(nil, errors.Newf(errors.UnexpectedError, "some error"))
 
-				return NewTaskHandler(logicMock, nil)
+				return NewTaskHandler(logicMock, nil, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*TaskHandler)
@@ -87,7 +87,7 @@ func TestGetTask(t *testing.T) {
 					GetTask("some_id").
 					Return(task, nil)
 
-				return NewTaskHandler(logicMock, nil)
+				return NewTaskHandler(logicMock, nil, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*TaskHandler)
@@ -105,7 +105,7 @@ func TestGetTask(t *testing.T) {
 					GetTask(gomock.Any()).
 					Return(task, nil)
 
-				return NewTaskHandler(logicMock, nil)
+				return NewTaskHandler(logicMock, nil, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*TaskHandler)
@@ -122,7 +122,7 @@ func TestGetTask(t *testing.T) {
 			Request: &TestRequest{},
 			Setup: func(ctrl *gomock.Controller) interface{} {
 				logicMock := mock_logic.NewMockTaskLogic(ctrl)
-				return NewTaskHandler(logicMock, nil)
+				return NewTaskHandler(logicMock, nil, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*TaskHandler)
@@ -145,7 +145,7 @@ func TestGetTask(t *testing.T) {
 					GetTask(gomock.Any()).
 					Return(nil, errors.Newf(errors.UnexpectedError, "some error"))
 
-				return NewTaskHandler(logicMock, nil)
+				return NewTaskHandler(logicMock, nil, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*TaskHandler)
@@ -175,7 +175,7 @@ func TestDeleteTask(t *testing.T) {
 					DeleteTask("some_id").
 					Return(nil)
 
-				return NewTaskHandler(logicMock, nil)
+				return NewTaskHandler(logicMock, nil, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*TaskHandler)
@@ -187,7 +187,7 @@ func TestDeleteTask(t *testing.T) {
 			Request: &TestRequest{},
 			Setup: func(ctrl *gomock.Controller) interface{} {
 				logicMock := mock_logic.NewMockTaskLogic(ctrl)
-				return NewTaskHandler(logicMock, nil)
+				return NewTaskHandler(logicMock, nil, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*TaskHandler)
@@ -210,7 +210,7 @@ func TestDeleteTask(t *testing.T) {
 					DeleteTask(gomock.Any()).
 					Return(errors.Newf(errors.UnexpectedError, "some error"))
 
-				return NewTaskHandler(logicMock, nil)
+				return NewTaskHandler(logicMock, nil, nil)
 			},
 			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 				handler := target.(*TaskHandler)
@@ -245,7 +245,7 @@ func TestCreateTask(t *testing.T) {
 				CreateJob(types.CreateTaskJob, request).
 				Return(&models.Job{}, nil)
 
-			return NewTaskHandler(nil, jobLogicMock)
+			return NewTaskHandler(nil, jobLogicMock, nil)
 		},
 		Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
 			handler := target.(*TaskHandler)
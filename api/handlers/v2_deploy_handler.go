@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+	"github.com/quintilesims/layer0/common/models"
+)
+
+func (h *V2Handler) ListDeploys(request *restful.Request, response *restful.Response) {
+	match, err := selectEntities(h.TagStore, request, "deploy")
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	deploys, err := h.DeployLogic.ListDeploys()
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	var resources []*models.Resource
+	for _, deploy := range deploys {
+		if !match(deploy.DeployID) {
+			continue
+		}
+
+		resource, err := h.newResource("deploy", deploy.DeployID, deploy.DeployName, deploy)
+		if err != nil {
+			writeV2Error(response, err)
+			return
+		}
+
+		resources = append(resources, resource)
+	}
+
+	h.writeResources(response, resources)
+}
+
+func (h *V2Handler) GetDeploy(request *restful.Request, response *restful.Response) {
+	deploy, err := h.DeployLogic.GetDeploy(request.PathParameter("id"))
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	resource, err := h.newResource("deploy", deploy.DeployID, deploy.DeployName, deploy)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeResource(response, http.StatusOK, resource)
+}
+
+func (h *V2Handler) CreateDeploy(request *restful.Request, response *restful.Response) {
+	var req models.CreateDeployRequest
+	if err := readV2Entity(request, &req); err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	deploy, err := h.DeployLogic.CreateDeploy(req)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	resource, err := h.newResource("deploy", deploy.DeployID, deploy.DeployName, deploy)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeResource(response, http.StatusCreated, resource)
+}
+
+func (h *V2Handler) DeleteDeploy(request *restful.Request, response *restful.Response) {
+	if err := h.DeployLogic.DeleteDeploy(request.PathParameter("id")); err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/types"
+)
+
+func (h *V2Handler) ListEnvironments(request *restful.Request, response *restful.Response) {
+	match, err := selectEntities(h.TagStore, request, "environment")
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	environments, err := h.EnvironmentLogic.ListEnvironments()
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	var resources []*models.Resource
+	for _, environment := range environments {
+		if !match(environment.EnvironmentID) {
+			continue
+		}
+
+		resource, err := h.newResource("environment", environment.EnvironmentID, environment.EnvironmentName, environment)
+		if err != nil {
+			writeV2Error(response, err)
+			return
+		}
+
+		resources = append(resources, resource)
+	}
+
+	h.writeResources(response, resources)
+}
+
+func (h *V2Handler) GetEnvironment(request *restful.Request, response *restful.Response) {
+	environment, err := h.EnvironmentLogic.GetEnvironment(request.PathParameter("id"))
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeEnvironment(response, http.StatusOK, environment)
+}
+
+func (h *V2Handler) CreateEnvironment(request *restful.Request, response *restful.Response) {
+	var req models.CreateEnvironmentRequest
+	if err := readV2Entity(request, &req); err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	ok, err := h.EnvironmentLogic.CanCreateEnvironment(req)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	if !ok {
+		writeV2Error(response, errors.Newf(errors.InvalidEnvironmentID, "Environment with name '%s' already exists", req.EnvironmentName))
+		return
+	}
+
+	job, err := h.JobLogic.CreateJob(types.CreateEnvironmentJob, req)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeJob(response, job)
+}
+
+func (h *V2Handler) UpdateEnvironment(request *restful.Request, response *restful.Response) {
+	var req models.UpdateEnvironmentRequest
+	if err := readV2Entity(request, &req); err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	version, err := IfMatchVersion(request)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	environment, err := h.EnvironmentLogic.UpdateEnvironment(request.PathParameter("id"), req, version)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeEnvironment(response, http.StatusOK, environment)
+}
+
+func (h *V2Handler) DeleteEnvironment(request *restful.Request, response *restful.Response) {
+	job, err := h.JobLogic.CreateJob(types.DeleteEnvironmentJob, request.PathParameter("id"))
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeJob(response, job)
+}
+
+func (h *V2Handler) writeEnvironment(response *restful.Response, status int, environment *models.Environment) {
+	resource, err := h.newResource("environment", environment.EnvironmentID, environment.EnvironmentName, environment)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeResource(response, status, resource)
+}
@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/emicklei/go-restful"
+	"github.com/quintilesims/layer0/api/logic"
+	"github.com/quintilesims/layer0/common/db/tag_store"
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/types"
+)
+
+// V2Handler serves the /v2 routes. Every entity is returned as a models.Resource,
+// every error as a models.ErrorResponse, and async operations return the job resource.
+type V2Handler struct {
+	DeployLogic       logic.DeployLogic
+	EnvironmentLogic  logic.EnvironmentLogic
+	JobLogic          logic.JobLogic
+	LoadBalancerLogic logic.LoadBalancerLogic
+	ServiceLogic      logic.ServiceLogic
+	TaskLogic         logic.TaskLogic
+	TagStore          tag_store.TagStore
+}
+
+func NewV2Handler(
+	deployLogic logic.DeployLogic,
+	environmentLogic logic.EnvironmentLogic,
+	jobLogic logic.JobLogic,
+	loadBalancerLogic logic.LoadBalancerLogic,
+	serviceLogic logic.ServiceLogic,
+	taskLogic logic.TaskLogic,
+	tagStore tag_store.TagStore,
+) *V2Handler {
+	return &V2Handler{
+		DeployLogic:       deployLogic,
+		EnvironmentLogic:  environmentLogic,
+		JobLogic:          jobLogic,
+		LoadBalancerLogic: loadBalancerLogic,
+		ServiceLogic:      serviceLogic,
+		TaskLogic:         taskLogic,
+		TagStore:          tagStore,
+	}
+}
+
+func (h *V2Handler) Routes() *restful.WebService {
+	service := new(restful.WebService)
+	service.Path("/v2").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+
+	id := service.PathParameter("id", "identifier of the resource").
+		DataType("string")
+
+	ifMatch := service.HeaderParameter("If-Match", "version of the resource the update is based on").
+		DataType("string")
+
+	// deploys
+	service.Route(service.GET("/deploys").
+		Filter(basicAuthenticate).
+		To(h.ListDeploys).
+		Doc("List all deploys").
+		Param(service.QueryParameter("selector", SELECTOR_PARAM_DESCRIPTION).DataType("string")).
+		Returns(http.StatusOK, "OK", []models.Resource{}))
+
+	service.Route(service.POST("/deploys").
+		Filter(basicAuthenticate).
+		To(h.CreateDeploy).
+		Doc("Create a deploy").
+		Reads(models.CreateDeployRequest{}).
+		Returns(http.StatusCreated, "Created", models.Resource{}).
+		Returns(http.StatusBadRequest, "Invalid request", models.ErrorResponse{}))
+
+	service.Route(service.GET("/deploys/{id}").
+		Filter(basicAuthenticate).
+		To(h.GetDeploy).
+		Doc("Return a deploy").
+		Param(id).
+		Returns(http.StatusOK, "OK", models.Resource{}).
+		Returns(http.StatusNotFound, "Not found", models.ErrorResponse{}))
+
+	service.Route(service.DELETE("/deploys/{id}").
+		Filter(basicAuthenticate).
+		To(h.DeleteDeploy).
+		Doc("Delete a deploy").
+		Param(id).
+		Returns(http.StatusNoContent, "Deleted", nil))
+
+	// environments
+	service.Route(service.GET("/environments").
+		Filter(basicAuthenticate).
+		To(h.ListEnvironments).
+		Doc("List all environments").
+		Param(service.QueryParameter("selector", SELECTOR_PARAM_DESCRIPTION).DataType("string")).
+		Returns(http.StatusOK, "OK", []models.Resource{}))
+
+	service.Route(service.POST("/environments").
+		Filter(basicAuthenticate).
+		To(h.CreateEnvironment).
+		Doc("Create an environment").
+		Reads(models.CreateEnvironmentRequest{}).
+		Returns(http.StatusAccepted, "Accepted", models.Resource{}).
+		Returns(http.StatusBadRequest, "Invalid request", models.ErrorResponse{}))
+
+	service.Route(service.GET("/environments/{id}").
+		Filter(basicAuthenticate).
+		To(h.GetEnvironment).
+		Doc("Return an environment").
+		Param(id).
+		Returns(http.StatusOK, "OK", models.Resource{}).
+		Returns(http.StatusNotFound, "Not found", models.ErrorResponse{}))
+
+	service.Route(service.PUT("/environments/{id}").
+		Filter(basicAuthenticate).
+		To(h.UpdateEnvironment).
+		Doc("Update an environment").
+		Reads(models.UpdateEnvironmentRequest{}).
+		Param(id).
+		Param(ifMatch).
+		Returns(http.StatusOK, "OK", models.Resource{}).
+		Returns(http.StatusConflict, "Version conflict", models.ErrorResponse{}))
+
+	service.Route(service.DELETE("/environments/{id}").
+		Filter(basicAuthenticate).
+		To(h.DeleteEnvironment).
+		Doc("Delete an environment").
+		Param(id).
+		Returns(http.StatusAccepted, "Accepted", models.Resource{}))
+
+	// jobs
+	service.Route(service.GET("/jobs").
+		Filter(basicAuthenticate).
+		To(h.ListJobs).
+		Doc("List all jobs").
+		Param(service.QueryParameter("selector", SELECTOR_PARAM_DESCRIPTION).DataType("string")).
+		Returns(http.StatusOK, "OK", []models.Resource{}))
+
+	service.Route(service.GET("/jobs/{id}").
+		Filter(basicAuthenticate).
+		To(h.GetJob).
+		Doc("Return a job").
+		Param(id).
+		Returns(http.StatusOK, "OK", models.Resource{}).
+		Returns(http.StatusNotFound, "Not found", models.ErrorResponse{}))
+
+	service.Route(service.DELETE("/jobs/{id}").
+		Filter(basicAuthenticate).
+		To(h.DeleteJob).
+		Doc("Delete a job").
+		Param(id).
+		Returns(http.StatusNoContent, "Deleted", nil))
+
+	// load balancers
+	service.Route(service.GET("/load_balancers").
+		Filter(basicAuthenticate).
+		To(h.ListLoadBalancers).
+		Doc("List all load balancers").
+		Param(service.QueryParameter("selector", SELECTOR_PARAM_DESCRIPTION).DataType("string")).
+		Returns(http.StatusOK, "OK", []models.Resource{}))
+
+	service.Route(service.POST("/load_balancers").
+		Filter(basicAuthenticate).
+		To(h.CreateLoadBalancer).
+		Doc("Create a load balancer").
+		Reads(models.CreateLoadBalancerRequest{}).
+		Returns(http.StatusAccepted, "Accepted", models.Resource{}).
+		Returns(http.StatusBadRequest, "Invalid request", models.ErrorResponse{}))
+
+	service.Route(service.GET("/load_balancers/{id}").
+		Filter(basicAuthenticate).
+		To(h.GetLoadBalancer).
+		Doc("Return a load balancer").
+		Param(id).
+		Returns(http.StatusOK, "OK", models.Resource{}).
+		Returns(http.StatusNotFound, "Not found", models.ErrorResponse{}))
+
+	service.Route(service.PUT("/load_balancers/{id}").
+		Filter(basicAuthenticate).
+		To(h.UpdateLoadBalancer).
+		Doc("Update a load balancer. Only the fields that are set will be updated").
+		Reads(models.UpdateLoadBalancerV2Request{}).
+		Param(id).
+		Param(ifMatch).
+		Returns(http.StatusOK, "OK", models.Resource{}).
+		Returns(http.StatusConflict, "Version conflict", models.ErrorResponse{}))
+
+	service.Route(service.DELETE("/load_balancers/{id}").
+		Filter(basicAuthenticate).
+		To(h.DeleteLoadBalancer).
+		Doc("Delete a load balancer").
+		Param(id).
+		Returns(http.StatusAccepted, "Accepted", models.Resource{}))
+
+	// services
+	service.Route(service.GET("/services").
+		Filter(basicAuthenticate).
+		To(h.ListServices).
+		Doc("List all services").
+		Param(service.QueryParameter("selector", SELECTOR_PARAM_DESCRIPTION).DataType("string")).
+		Returns(http.StatusOK, "OK", []models.Resource{}))
+
+	service.Route(service.POST("/services").
+		Filter(basicAuthenticate).
+		To(h.CreateService).
+		Doc("Create a service").
+		Reads(models.CreateServiceRequest{}).
+		Returns(http.StatusCreated, "Created", models.Resource{}).
+		Returns(http.StatusBadRequest, "Invalid request", models.ErrorResponse{}))
+
+	service.Route(service.GET("/services/{id}").
+		Filter(basicAuthenticate).
+		To(h.GetService).
+		Doc("Return a service").
+		Param(id).
+		Returns(http.StatusOK, "OK", models.Resource{}).
+		Returns(http.StatusNotFound, "Not found", models.ErrorResponse{}))
+
+	service.Route(service.PUT("/services/{id}").
+		Filter(basicAuthenticate).
+		To(h.UpdateService).
+		Doc("Update a service's deploy and/or desired count").
+		Reads(models.UpdateServiceV2Request{}).
+		Param(id).
+		Param(ifMatch).
+		Returns(http.StatusOK, "Nothing to update", models.Resource{}).
+		Returns(http.StatusAccepted, "Accepted", models.Resource{}).
+		Returns(http.StatusConflict, "Version conflict", models.ErrorResponse{}))
+
+	service.Route(service.DELETE("/services/{id}").
+		Filter(basicAuthenticate).
+		To(h.DeleteService).
+		Doc("Delete a service").
+		Param(id).
+		Returns(http.StatusAccepted, "Accepted", models.Resource{}))
+
+	// tasks
+	service.Route(service.GET("/tasks").
+		Filter(basicAuthenticate).
+		To(h.ListTasks).
+		Doc("List all tasks").
+		Param(service.QueryParameter("selector", SELECTOR_PARAM_DESCRIPTION).DataType("string")).
+		Returns(http.StatusOK, "OK", []models.Resource{}))
+
+	service.Route(service.POST("/tasks").
+		Filter(basicAuthenticate).
+		To(h.CreateTask).
+		Doc("Create a task").
+		Reads(models.CreateTaskRequest{}).
+		Returns(http.StatusAccepted, "Accepted", models.Resource{}).
+		Returns(http.StatusBadRequest, "Invalid request", models.ErrorResponse{}))
+
+	service.Route(service.GET("/tasks/{id}").
+		Filter(basicAuthenticate).
+		To(h.GetTask).
+		Doc("Return a task").
+		Param(id).
+		Returns(http.StatusOK, "OK", models.Resource{}).
+		Returns(http.StatusNotFound, "Not found", models.ErrorResponse{}))
+
+	service.Route(service.DELETE("/tasks/{id}").
+		Filter(basicAuthenticate).
+		To(h.DeleteTask).
+		Doc("Delete a task").
+		Param(id).
+		Returns(http.StatusAccepted, "Accepted", models.Resource{}))
+
+	return service
+}
+
+// newResource builds the v2 representation of an entity, including its tags and version
+func (h *V2Handler) newResource(entityType, entityID, name string, attributes interface{}) (*models.Resource, error) {
+	tags, err := h.TagStore.SelectByTypeAndID(entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := h.TagStore.SelectVersion(entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := &models.Resource{
+		ID:         entityID,
+		Type:       entityType,
+		Name:       name,
+		Version:    version,
+		Tags:       []models.ResourceTag{},
+		Attributes: attributes,
+	}
+
+	for _, tag := range tags {
+		resource.Tags = append(resource.Tags, models.ResourceTag{Key: tag.Key, Value: tag.Value})
+	}
+
+	return resource, nil
+}
+
+func (h *V2Handler) newJobResource(job *models.Job) (*models.Resource, error) {
+	return h.newResource("job", job.JobID, types.JobType(job.JobType).String(), job)
+}
+
+func (h *V2Handler) writeResource(response *restful.Response, status int, resource *models.Resource) {
+	WriteETagHeader(response, resource.Version)
+	response.WriteHeader(status)
+	response.WriteAsJson(resource)
+}
+
+func (h *V2Handler) writeResources(response *restful.Response, resources []*models.Resource) {
+	if resources == nil {
+		resources = []*models.Resource{}
+	}
+
+	response.WriteAsJson(resources)
+}
+
+// writeJob returns the job tracking an asynchronous operation
+func (h *V2Handler) writeJob(response *restful.Response, job *models.Job) {
+	resource, err := h.newJobResource(job)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	response.AddHeader("Location", fmt.Sprintf("/v2/jobs/%s", job.JobID))
+	response.AddHeader("X-JobID", job.JobID)
+	h.writeResource(response, http.StatusAccepted, resource)
+}
+
+func writeV2Error(response *restful.Response, err error) {
+	status, model := ErrorModel(err)
+	if status == http.StatusInternalServerError {
+		logrus.Errorf("InternalServerError (code: %d): %s", model.ErrorCode, model.Message)
+	}
+
+	response.WriteHeader(status)
+	response.WriteAsJson(models.ErrorResponse{
+		Error:  model,
+		Status: status,
+	})
+}
+
+func readV2Entity(request *restful.Request, entity interface{}) error {
+	if err := request.ReadEntity(entity); err != nil {
+		return errors.New(errors.InvalidJSON, err)
+	}
+
+	return nil
+}
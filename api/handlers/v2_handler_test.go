@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"github.com/golang/mock/gomock"
+	"github.com/quintilesims/layer0/api/logic/mock_logic"
+	"github.com/quintilesims/layer0/common/db/tag_store"
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
+	"github.com/quintilesims/layer0/common/types"
+)
+
+type v2TestMocks struct {
+	Deploy       *mock_logic.MockDeployLogic
+	Environment  *mock_logic.MockEnvironmentLogic
+	Job          *mock_logic.MockJobLogic
+	LoadBalancer *mock_logic.MockLoadBalancerLogic
+	Service      *mock_logic.MockServiceLogic
+	Task         *mock_logic.MockTaskLogic
+	TagStore     *tag_store.MemoryTagStore
+}
+
+func newV2TestHandler(ctrl *gomock.Controller, setup func(m *v2TestMocks)) *V2Handler {
+	m := &v2TestMocks{
+		Deploy:       mock_logic.NewMockDeployLogic(ctrl),
+		Environment:  mock_logic.NewMockEnvironmentLogic(ctrl),
+		Job:          mock_logic.NewMockJobLogic(ctrl),
+		LoadBalancer: mock_logic.NewMockLoadBalancerLogic(ctrl),
+		Service:      mock_logic.NewMockServiceLogic(ctrl),
+		Task:         mock_logic.NewMockTaskLogic(ctrl),
+		TagStore:     tag_store.NewMemoryTagStore(),
+	}
+
+	setup(m)
+	return NewV2Handler(m.Deploy, m.Environment, m.Job, m.LoadBalancer, m.Service, m.Task, m.TagStore)
+}
+
+func TestV2GetService(t *testing.T) {
+	testCases := []HandlerTestCase{
+		{
+			Name: "Should return service resource with tags",
+			Request: &TestRequest{
+				Parameters: map[string]string{"id": "s1"},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				return newV2TestHandler(ctrl, func(m *v2TestMocks) {
+					m.TagStore.Insert(models.Tag{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc"})
+
+					m.Service.EXPECT().
+						GetService("s1").
+						Return(&models.Service{ServiceID: "s1", ServiceName: "svc"}, nil)
+				})
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*V2Handler)
+				handler.GetService(req, resp)
+
+				var response *models.Resource
+				read(&response)
+
+				reporter.AssertEqual(resp.StatusCode(), http.StatusOK)
+				reporter.AssertEqual(resp.Header().Get("ETag"), "\"1\"")
+				reporter.AssertEqual(response.ID, "s1")
+				reporter.AssertEqual(response.Type, "service")
+				reporter.AssertEqual(response.Name, "svc")
+				reporter.AssertEqual(response.Version, int64(1))
+				reporter.AssertEqual(response.Tags, []models.ResourceTag{{Key: "name", Value: "svc"}})
+			},
+		},
+		{
+			Name: "Should return error envelope",
+			Request: &TestRequest{
+				Parameters: map[string]string{"id": "s1"},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				return newV2TestHandler(ctrl, func(m *v2TestMocks) {
+					m.Service.EXPECT().
+						GetService("s1").
+						Return(nil, errors.Newf(errors.ServiceDoesNotExist, "some error"))
+				})
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*V2Handler)
+				handler.GetService(req, resp)
+
+				var response *models.ErrorResponse
+				read(&response)
+
+				reporter.AssertEqual(resp.StatusCode(), http.StatusNotFound)
+				reporter.AssertEqual(response.Status, http.StatusNotFound)
+				reporter.AssertEqual(response.Error.ErrorCode, int64(errors.ServiceDoesNotExist))
+				reporter.AssertEqual(response.Error.Message, "some error")
+			},
+		},
+	}
+
+	RunHandlerTestCases(t, testCases)
+}
+
+func TestV2ListServicesWithSelector(t *testing.T) {
+	testCases := []HandlerTestCase{
+		{
+			Name: "Should only return services matching the selector",
+			Request: &TestRequest{
+				Query: "selector=%21deprecated",
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				return newV2TestHandler(ctrl, func(m *v2TestMocks) {
+					m.TagStore.Insert(models.Tag{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc1"})
+					m.TagStore.Insert(models.Tag{EntityID: "s2", EntityType: "service", Key: "name", Value: "svc2"})
+					m.TagStore.Insert(models.Tag{EntityID: "s2", EntityType: "service", Key: "deprecated", Value: "true"})
+
+					m.Service.EXPECT().
+						ListServices().
+						Return([]models.ServiceSummary{{ServiceID: "s1", ServiceName: "svc1"}, {ServiceID: "s2", ServiceName: "svc2"}}, nil)
+				})
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*V2Handler)
+				handler.ListServices(req, resp)
+
+				var response []*models.Resource
+				read(&response)
+
+				reporter.AssertEqual(resp.StatusCode(), http.StatusOK)
+				reporter.AssertEqual(len(response), 1)
+				reporter.AssertEqual(response[0].ID, "s1")
+			},
+		},
+		{
+			Name: "Should return error envelope for invalid selector",
+			Request: &TestRequest{
+				Query: "selector=team%3D%3D%3D",
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				return newV2TestHandler(ctrl, func(m *v2TestMocks) {})
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*V2Handler)
+				handler.ListServices(req, resp)
+
+				var response *models.ErrorResponse
+				read(&response)
+
+				reporter.AssertEqual(resp.StatusCode(), http.StatusBadRequest)
+				reporter.AssertEqual(response.Error.ErrorCode, int64(errors.InvalidSelector))
+			},
+		},
+	}
+
+	RunHandlerTestCases(t, testCases)
+}
+
+func TestV2CreateEnvironment(t *testing.T) {
+	req := models.CreateEnvironmentRequest{EnvironmentName: "env"}
+
+	testCases := []HandlerTestCase{
+		{
+			Name: "Should create job and return job resource",
+			Request: &TestRequest{
+				Body: req,
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				return newV2TestHandler(ctrl, func(m *v2TestMocks) {
+					m.Environment.EXPECT().
+						CanCreateEnvironment(req).
+						Return(true, nil)
+
+					m.Job.EXPECT().
+						CreateJob(types.CreateEnvironmentJob, req).
+						Return(&models.Job{JobID: "j1", JobType: int64(types.CreateEnvironmentJob)}, nil)
+				})
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*V2Handler)
+				handler.CreateEnvironment(req, resp)
+
+				var response *models.Resource
+				read(&response)
+
+				reporter.AssertEqual(resp.StatusCode(), http.StatusAccepted)
+				reporter.AssertEqual(resp.Header().Get("Location"), "/v2/jobs/j1")
+				reporter.AssertEqual(response.ID, "j1")
+			},
+		},
+	}
+
+	RunHandlerTestCases(t, testCases)
+}
+
+func TestV2CreateLoadBalancer(t *testing.T) {
+	req := models.CreateLoadBalancerRequest{LoadBalancerName: "lb", EnvironmentID: "e1"}
+
+	testCases := []HandlerTestCase{
+		{
+			Name: "Should create job and return job resource",
+			Request: &TestRequest{
+				Body: req,
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				return newV2TestHandler(ctrl, func(m *v2TestMocks) {
+					m.Job.EXPECT().
+						CreateJob(types.CreateLoadBalancerJob, req).
+						Return(&models.Job{JobID: "j1", JobType: int64(types.CreateLoadBalancerJob)}, nil)
+				})
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*V2Handler)
+				handler.CreateLoadBalancer(req, resp)
+
+				var response *models.Resource
+				read(&response)
+
+				reporter.AssertEqual(resp.StatusCode(), http.StatusAccepted)
+				reporter.AssertEqual(response.ID, "j1")
+			},
+		},
+	}
+
+	RunHandlerTestCases(t, testCases)
+}
+
+func TestV2UpdateService(t *testing.T) {
+	deployID := "d1"
+	desiredCount := int64(3)
+
+	testCases := []HandlerTestCase{
+		{
+			Name: "Should create one update job for the deploy and desired count",
+			Request: &TestRequest{
+				Body:       models.UpdateServiceV2Request{DeployID: &deployID, DesiredCount: &desiredCount},
+				Parameters: map[string]string{"id": "s1"},
+				Headers:    map[string]string{"If-Match": "\"4\""},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				return newV2TestHandler(ctrl, func(m *v2TestMocks) {
+					m.Service.EXPECT().
+						GetService("s1").
+						Return(&models.Service{ServiceID: "s1", Version: 4}, nil)
+
+					jobRequest := models.UpdateServiceJobRequest{
+						ServiceID:    "s1",
+						DeployID:     "d1",
+						DesiredCount: &desiredCount,
+						Version:      4,
+					}
+
+					m.Job.EXPECT().
+						CreateJob(types.UpdateServiceJob, jobRequest).
+						Return(&models.Job{JobID: "j1", JobType: int64(types.UpdateServiceJob)}, nil)
+				})
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*V2Handler)
+				handler.UpdateService(req, resp)
+
+				reporter.AssertEqual(resp.StatusCode(), http.StatusAccepted)
+			},
+		},
+		{
+			Name: "Should create scale job for the desired count",
+			Request: &TestRequest{
+				Body:       models.UpdateServiceV2Request{DesiredCount: &desiredCount},
+				Parameters: map[string]string{"id": "s1"},
+				Headers:    map[string]string{"If-Match": "\"4\""},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				return newV2TestHandler(ctrl, func(m *v2TestMocks) {
+					m.Service.EXPECT().
+						GetService("s1").
+						Return(&models.Service{ServiceID: "s1", Version: 4}, nil)
+
+					jobRequest := models.ScaleServiceJobRequest{
+						ServiceID:    "s1",
+						DesiredCount: 3,
+						Version:      4,
+					}
+
+					m.Job.EXPECT().
+						CreateJob(types.ScaleServiceJob, jobRequest).
+						Return(&models.Job{JobID: "j1", JobType: int64(types.ScaleServiceJob)}, nil)
+				})
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*V2Handler)
+				handler.UpdateService(req, resp)
+
+				reporter.AssertEqual(resp.StatusCode(), http.StatusAccepted)
+			},
+		},
+		{
+			Name: "Should return conflict error envelope",
+			Request: &TestRequest{
+				Body:       models.UpdateServiceV2Request{DesiredCount: &desiredCount},
+				Parameters: map[string]string{"id": "s1"},
+				Headers:    map[string]string{"If-Match": "\"4\""},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				return newV2TestHandler(ctrl, func(m *v2TestMocks) {
+					m.Service.EXPECT().
+						GetService("s1").
+						Return(&models.Service{ServiceID: "s1", Version: 5}, nil)
+				})
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*V2Handler)
+				handler.UpdateService(req, resp)
+
+				var response *models.ErrorResponse
+				read(&response)
+
+				reporter.AssertEqual(resp.StatusCode(), http.StatusConflict)
+				reporter.AssertEqual(response.Error.ErrorCode, int64(errors.EntityConflict))
+			},
+		},
+	}
+
+	RunHandlerTestCases(t, testCases)
+}
+
+func TestV2DeleteService(t *testing.T) {
+	testCases := []HandlerTestCase{
+		{
+			Name: "Should return job resource",
+			Request: &TestRequest{
+				Parameters: map[string]string{"id": "s1"},
+			},
+			Setup: func(ctrl *gomock.Controller) interface{} {
+				return newV2TestHandler(ctrl, func(m *v2TestMocks) {
+					m.Job.EXPECT().
+						CreateJob(types.DeleteServiceJob, "s1").
+						Return(&models.Job{JobID: "j1", JobType: int64(types.DeleteServiceJob)}, nil)
+				})
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}, req *restful.Request, resp *restful.Response, read Readf) {
+				handler := target.(*V2Handler)
+				handler.DeleteService(req, resp)
+
+				var response *models.Resource
+				read(&response)
+
+				reporter.AssertEqual(resp.StatusCode(), http.StatusAccepted)
+				reporter.AssertEqual(resp.Header().Get("Location"), "/v2/jobs/j1")
+				reporter.AssertEqual(response.ID, "j1")
+				reporter.AssertEqual(response.Type, "job")
+				reporter.AssertEqual(response.Name, types.DeleteServiceJob.String())
+			},
+		},
+	}
+
+	RunHandlerTestCases(t, testCases)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+	"github.com/quintilesims/layer0/common/models"
+)
+
+func (h *V2Handler) ListJobs(request *restful.Request, response *restful.Response) {
+	match, err := selectEntities(h.TagStore, request, "job")
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	jobs, err := h.JobLogic.ListJobs()
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	var resources []*models.Resource
+	for _, job := range jobs {
+		if !match(job.JobID) {
+			continue
+		}
+
+		resource, err := h.newJobResource(job)
+		if err != nil {
+			writeV2Error(response, err)
+			return
+		}
+
+		resources = append(resources, resource)
+	}
+
+	h.writeResources(response, resources)
+}
+
+func (h *V2Handler) GetJob(request *restful.Request, response *restful.Response) {
+	job, err := h.JobLogic.GetJob(request.PathParameter("id"))
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	resource, err := h.newJobResource(job)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeResource(response, http.StatusOK, resource)
+}
+
+func (h *V2Handler) DeleteJob(request *restful.Request, response *restful.Response) {
+	if err := h.JobLogic.Delete(request.PathParameter("id")); err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/types"
+)
+
+func (h *V2Handler) ListLoadBalancers(request *restful.Request, response *restful.Response) {
+	match, err := selectEntities(h.TagStore, request, "load_balancer")
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	loadBalancers, err := h.LoadBalancerLogic.ListLoadBalancers()
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	var resources []*models.Resource
+	for _, loadBalancer := range loadBalancers {
+		if !match(loadBalancer.LoadBalancerID) {
+			continue
+		}
+
+		resource, err := h.newResource("load_balancer", loadBalancer.LoadBalancerID, loadBalancer.LoadBalancerName, loadBalancer)
+		if err != nil {
+			writeV2Error(response, err)
+			return
+		}
+
+		resources = append(resources, resource)
+	}
+
+	h.writeResources(response, resources)
+}
+
+func (h *V2Handler) GetLoadBalancer(request *restful.Request, response *restful.Response) {
+	loadBalancer, err := h.LoadBalancerLogic.GetLoadBalancer(request.PathParameter("id"))
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeLoadBalancer(response, http.StatusOK, loadBalancer)
+}
+
+func (h *V2Handler) CreateLoadBalancer(request *restful.Request, response *restful.Response) {
+	var req models.CreateLoadBalancerRequest
+	if err := readV2Entity(request, &req); err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	job, err := h.JobLogic.CreateJob(types.CreateLoadBalancerJob, req)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeJob(response, job)
+}
+
+func (h *V2Handler) UpdateLoadBalancer(request *restful.Request, response *restful.Response) {
+	loadBalancerID := request.PathParameter("id")
+
+	var req models.UpdateLoadBalancerV2Request
+	if err := readV2Entity(request, &req); err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	version, err := IfMatchVersion(request)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	// each update bumps the load balancer's version, so subsequent updates
+	// in this request are based on the version returned by the previous one
+	var loadBalancer *models.LoadBalancer
+
+	if req.Ports != nil {
+		loadBalancer, err = h.LoadBalancerLogic.UpdateLoadBalancerPorts(loadBalancerID, *req.Ports, version)
+		if err != nil {
+			writeV2Error(response, err)
+			return
+		}
+
+		version = loadBalancer.Version
+	}
+
+	if req.HealthCheck != nil {
+		loadBalancer, err = h.LoadBalancerLogic.UpdateLoadBalancerHealthCheck(loadBalancerID, *req.HealthCheck, version)
+		if err != nil {
+			writeV2Error(response, err)
+			return
+		}
+
+		version = loadBalancer.Version
+	}
+
+	if req.IdleTimeout != nil {
+		loadBalancer, err = h.LoadBalancerLogic.UpdateLoadBalancerIdleTimeout(loadBalancerID, *req.IdleTimeout, version)
+		if err != nil {
+			writeV2Error(response, err)
+			return
+		}
+
+		version = loadBalancer.Version
+	}
+
+	if req.CrossZone != nil {
+		loadBalancer, err = h.LoadBalancerLogic.UpdateLoadBalancerCrossZone(loadBalancerID, *req.CrossZone, version)
+		if err != nil {
+			writeV2Error(response, err)
+			return
+		}
+	}
+
+	if loadBalancer == nil {
+		loadBalancer, err = h.LoadBalancerLogic.GetLoadBalancer(loadBalancerID)
+		if err != nil {
+			writeV2Error(response, err)
+			return
+		}
+	}
+
+	h.writeLoadBalancer(response, http.StatusOK, loadBalancer)
+}
+
+func (h *V2Handler) DeleteLoadBalancer(request *restful.Request, response *restful.Response) {
+	job, err := h.JobLogic.CreateJob(types.DeleteLoadBalancerJob, request.PathParameter("id"))
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeJob(response, job)
+}
+
+func (h *V2Handler) writeLoadBalancer(response *restful.Response, status int, loadBalancer *models.LoadBalancer) {
+	resource, err := h.newResource("load_balancer", loadBalancer.LoadBalancerID, loadBalancer.LoadBalancerName, loadBalancer)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeResource(response, status, resource)
+}
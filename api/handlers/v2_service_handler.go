@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/types"
+)
+
+func (h *V2Handler) ListServices(request *restful.Request, response *restful.Response) {
+	match, err := selectEntities(h.TagStore, request, "service")
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	services, err := h.ServiceLogic.ListServices()
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	var resources []*models.Resource
+	for _, service := range services {
+		if !match(service.ServiceID) {
+			continue
+		}
+
+		resource, err := h.newResource("service", service.ServiceID, service.ServiceName, service)
+		if err != nil {
+			writeV2Error(response, err)
+			return
+		}
+
+		resources = append(resources, resource)
+	}
+
+	h.writeResources(response, resources)
+}
+
+func (h *V2Handler) GetService(request *restful.Request, response *restful.Response) {
+	service, err := h.ServiceLogic.GetService(request.PathParameter("id"))
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeService(response, http.StatusOK, service)
+}
+
+func (h *V2Handler) CreateService(request *restful.Request, response *restful.Response) {
+	var req models.CreateServiceRequest
+	if err := readV2Entity(request, &req); err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	service, err := h.ServiceLogic.CreateService(req)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeService(response, http.StatusCreated, service)
+}
+
+func (h *V2Handler) UpdateService(request *restful.Request, response *restful.Response) {
+	serviceID := request.PathParameter("id")
+
+	var req models.UpdateServiceV2Request
+	if err := readV2Entity(request, &req); err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	version, err := IfMatchVersion(request)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	if req.DeployID == nil && req.DesiredCount == nil {
+		service, err := h.ServiceLogic.GetService(serviceID)
+		if err != nil {
+			writeV2Error(response, err)
+			return
+		}
+
+		h.writeService(response, http.StatusOK, service)
+		return
+	}
+
+	if err := checkServiceVersion(h.ServiceLogic, serviceID, version); err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	// a deploy update also scales the service when the desired count is set,
+	// so both changes are made by one job
+	jobType := types.ScaleServiceJob
+	var jobRequest interface{}
+	if req.DeployID != nil {
+		jobType = types.UpdateServiceJob
+		jobRequest = models.UpdateServiceJobRequest{
+			ServiceID:    serviceID,
+			DeployID:     *req.DeployID,
+			DesiredCount: req.DesiredCount,
+			Version:      version,
+		}
+	} else {
+		jobRequest = models.ScaleServiceJobRequest{
+			ServiceID:    serviceID,
+			DesiredCount: *req.DesiredCount,
+			Version:      version,
+		}
+	}
+
+	job, err := h.JobLogic.CreateJob(jobType, jobRequest)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeJob(response, job)
+}
+
+func (h *V2Handler) DeleteService(request *restful.Request, response *restful.Response) {
+	job, err := h.JobLogic.CreateJob(types.DeleteServiceJob, request.PathParameter("id"))
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeJob(response, job)
+}
+
+func (h *V2Handler) writeService(response *restful.Response, status int, service *models.Service) {
+	resource, err := h.newResource("service", service.ServiceID, service.ServiceName, service)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeResource(response, status, resource)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/types"
+)
+
+func (h *V2Handler) ListTasks(request *restful.Request, response *restful.Response) {
+	match, err := selectEntities(h.TagStore, request, "task")
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	tasks, err := h.TaskLogic.ListTasks()
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	var resources []*models.Resource
+	for _, task := range tasks {
+		if !match(task.TaskID) {
+			continue
+		}
+
+		resource, err := h.newResource("task", task.TaskID, task.TaskName, task)
+		if err != nil {
+			writeV2Error(response, err)
+			return
+		}
+
+		resources = append(resources, resource)
+	}
+
+	h.writeResources(response, resources)
+}
+
+func (h *V2Handler) GetTask(request *restful.Request, response *restful.Response) {
+	task, err := h.TaskLogic.GetTask(request.PathParameter("id"))
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	resource, err := h.newResource("task", task.TaskID, task.TaskName, task)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeResource(response, http.StatusOK, resource)
+}
+
+func (h *V2Handler) CreateTask(request *restful.Request, response *restful.Response) {
+	var req models.CreateTaskRequest
+	if err := readV2Entity(request, &req); err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	job, err := h.JobLogic.CreateJob(types.CreateTaskJob, req)
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeJob(response, job)
+}
+
+func (h *V2Handler) DeleteTask(request *restful.Request, response *restful.Response) {
+	job, err := h.JobLogic.CreateJob(types.DeleteTaskJob, request.PathParameter("id"))
+	if err != nil {
+		writeV2Error(response, err)
+		return
+	}
+
+	h.writeJob(response, job)
+}
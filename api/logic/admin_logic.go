@@ -1,29 +1,288 @@
 package logic
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/quintilesims/layer0/common/config"
+	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/types"
+	"github.com/quintilesims/layer0/common/waitutils"
 )
 
+// BACKUP_VERSION is the version of the backup format written by this api;
+// backups with a newer version can't be restored
+const BACKUP_VERSION = 1
+
 type AdminLogic interface {
+	Backup() (*models.Backup, error)
+	GetConfig() models.APIConfig
+	ImportEntities(models.ImportRequest) (*models.ImportReport, error)
+	ReconcileTags(dryRun bool) (*models.TagReconciliationReport, error)
+	Restore(models.RestoreRequest) (*models.RestoreReport, error)
 	RunEnvironmentScaler(string) (*models.ScalerRunInfo, error)
+	SaveBackup() (*models.BackupSummary, error)
+	SimulateEnvironmentScaler(string, models.ScalerSimulationRequest) (*models.ScalerRunInfo, error)
 	UpdateSQL() error
 }
 
 type L0AdminLogic struct {
 	Logic
+	Clock waitutils.Clock
 }
 
 func NewL0AdminLogic(l Logic) *L0AdminLogic {
 	return &L0AdminLogic{
 		Logic: l,
+		Clock: waitutils.RealClock{},
+	}
+}
+
+func (a *L0AdminLogic) GetConfig() models.APIConfig {
+	publicSubnets := []string{}
+	for _, subnet := range strings.Split(config.AWSPublicSubnets(), ",") {
+		publicSubnets = append(publicSubnets, subnet)
+	}
+
+	privateSubnets := []string{}
+	for _, subnet := range strings.Split(config.AWSPrivateSubnets(), ",") {
+		privateSubnets = append(privateSubnets, subnet)
+	}
+
+	return models.APIConfig{
+		Prefix:         config.Prefix(),
+		VPCID:          config.AWSVPCID(),
+		PublicSubnets:  publicSubnets,
+		PrivateSubnets: privateSubnets,
+	}
+}
+
+// Backup exports every tag and current job; archived jobs are already kept in s3
+func (a *L0AdminLogic) Backup() (*models.Backup, error) {
+	backup := &models.Backup{
+		Version:    BACKUP_VERSION,
+		Created:    a.Clock.Now().UTC(),
+		APIVersion: config.APIVersion(),
+		Config:     a.GetConfig(),
+		Tags:       models.Tags{},
+	}
+
+	for _, entityType := range backupEntityTypes() {
+		tags, err := a.TagStore.SelectByType(entityType)
+		if err != nil {
+			return nil, err
+		}
+
+		backup.Tags = append(backup.Tags, tags...)
+	}
+
+	jobs, err := a.JobStore.SelectAll()
+	if err != nil {
+		return nil, err
+	}
+
+	backup.Jobs = jobs
+	return backup, nil
+}
+
+// backupEntityTypes are the types of entity that have tags: the types in types.EntityTypes,
+// along with jobs and tasks, which aren't in types.EntityTypes since they aren't reconciled like the others
+func backupEntityTypes() []string {
+	entityTypes := []string{"job", "task"}
+	for entityType := range types.EntityTypes {
+		entityTypes = append(entityTypes, entityType)
+	}
+
+	sort.Strings(entityTypes)
+	return entityTypes
+}
+
+func (a *L0AdminLogic) SaveBackup() (*models.BackupSummary, error) {
+	backup, err := a.Backup()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := a.BackupStore.Put(backup)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.BackupSummary{
+		Key:      key,
+		Version:  backup.Version,
+		Created:  backup.Created,
+		TagCount: len(backup.Tags),
+		JobCount: len(backup.Jobs),
+	}
+
+	return summary, nil
+}
+
+// Restore imports the tags and jobs of a backup. Missing tags and jobs are inserted and existing
+// ones are left unchanged, so a backup can be restored more than once. Tags whose value differs
+// from the backup are reported as conflicts, and only overwritten if the request allows it.
+// The backup's config isn't restored. The restored tags are then checked against
+// the live AWS resources; a dry run only checks the backup's tags.
+func (a *L0AdminLogic) Restore(req models.RestoreRequest) (*models.RestoreReport, error) {
+	backup, err := a.loadBackup(req)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.RestoreReport{
+		DryRun: req.DryRun,
+	}
+
+	if err := a.restoreTags(backup.Tags, req.DryRun, req.Overwrite, report); err != nil {
+		return nil, err
+	}
+
+	if err := a.restoreJobs(backup.Jobs, req.DryRun, report); err != nil {
+		return nil, err
 	}
+
+	tags := backup.Tags
+	if !req.DryRun {
+		tags = models.Tags{}
+		for _, entityType := range backupEntityTypes() {
+			entityTags, err := a.TagStore.SelectByType(entityType)
+			if err != nil {
+				return nil, err
+			}
+
+			tags = append(tags, entityTags...)
+		}
+	}
+
+	consistency, err := a.ConsistencyChecker.Check(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Consistency = *consistency
+	return report, nil
+}
+
+func (a *L0AdminLogic) loadBackup(req models.RestoreRequest) (*models.Backup, error) {
+	var backup *models.Backup
+	switch {
+	case req.Key != "" && req.Backup != nil:
+		return nil, errors.Newf(errors.InvalidBackup, "Only one of 'key' or 'backup' may be specified")
+	case req.Key != "":
+		b, err := a.BackupStore.Get(req.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		backup = b
+	case req.Backup != nil:
+		backup = req.Backup
+	default:
+		return nil, errors.Newf(errors.MissingParameter, "One of 'key' or 'backup' is required")
+	}
+
+	if backup.Version < 1 || backup.Version > BACKUP_VERSION {
+		return nil, errors.Newf(errors.InvalidBackup, "Backup version %d is not supported (the latest supported version is %d)", backup.Version, BACKUP_VERSION)
+	}
+
+	// entity ids are only unique within a single layer0 instance
+	if prefix := config.Prefix(); backup.Config.Prefix != prefix {
+		return nil, errors.Newf(errors.InvalidBackup, "Backup was taken from layer0 instance '%s', not '%s'", backup.Config.Prefix, prefix)
+	}
+
+	return backup, nil
+}
+
+func (a *L0AdminLogic) restoreTags(tags models.Tags, dryRun, overwrite bool, report *models.RestoreReport) error {
+	report.TagConflicts = []models.TagConflict{}
+	for _, ewt := range tags.GroupByEntity() {
+		current, err := a.TagStore.SelectByTypeAndID(ewt.EntityType, ewt.EntityID)
+		if err != nil {
+			return err
+		}
+
+		for _, tag := range ewt.Tags {
+			if len(current.WithKey(tag.Key).WithValue(tag.Value)) > 0 {
+				report.TagsUnchanged++
+				continue
+			}
+
+			// an entity has one value per key, so a different value for the key is a conflict
+			if existing := current.WithKey(tag.Key); len(existing) > 0 {
+				report.TagConflicts = append(report.TagConflicts, models.TagConflict{
+					EntityType:   tag.EntityType,
+					EntityID:     tag.EntityID,
+					Key:          tag.Key,
+					CurrentValue: existing[0].Value,
+					BackupValue:  tag.Value,
+				})
+
+				if !overwrite {
+					continue
+				}
+
+				report.TagsOverwritten++
+			} else {
+				report.TagsInserted++
+			}
+
+			if !dryRun {
+				if err := a.TagStore.Insert(tag); err != nil {
+					return fmt.Errorf("Failed to restore tag %s/%s '%s': %v", tag.EntityType, tag.EntityID, tag.Key, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *L0AdminLogic) restoreJobs(jobs []*models.Job, dryRun bool, report *models.RestoreReport) error {
+	for _, job := range jobs {
+		_, err := a.JobStore.SelectByID(job.JobID)
+		if err == nil {
+			report.JobsUnchanged++
+			continue
+		}
+
+		if serverErr, ok := err.(*errors.ServerError); !ok || serverErr.Code != errors.JobDoesNotExist {
+			return err
+		}
+
+		if !dryRun {
+			if err := a.JobStore.Insert(job); err != nil {
+				return fmt.Errorf("Failed to restore job '%s': %v", job.JobID, err)
+			}
+		}
+
+		report.JobsInserted++
+	}
+
+	return nil
+}
+
+// ImportEntities writes the missing tags of layer0 resources that aren't fully tagged
+func (a *L0AdminLogic) ImportEntities(req models.ImportRequest) (*models.ImportReport, error) {
+	return NewEntityImporter(a.Backend, a.TagStore).Import(req)
+}
+
+// ReconcileTags runs the tag janitor's reconciliation once
+func (a *L0AdminLogic) ReconcileTags(dryRun bool) (*models.TagReconciliationReport, error) {
+	janitor := NewTagJanitor(NewL0TaskLogic(a.Logic), a.Backend, a.TagStore)
+	return janitor.Reconcile(dryRun)
 }
 
 func (a *L0AdminLogic) RunEnvironmentScaler(environmentID string) (*models.ScalerRunInfo, error) {
 	return a.Logic.Scaler.Scale(environmentID)
 }
 
+func (a *L0AdminLogic) SimulateEnvironmentScaler(environmentID string, req models.ScalerSimulationRequest) (*models.ScalerRunInfo, error) {
+	return a.Logic.Scaler.Simulate(environmentID, req)
+}
+
 func (a *L0AdminLogic) UpdateSQL() error {
 	if err := a.TagStore.Init(); err != nil {
 		return err
@@ -0,0 +1,343 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/quintilesims/layer0/api/logic/mock_logic"
+	"github.com/quintilesims/layer0/common/config"
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+func TestBackup(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	testLogic.AddTags(t, []*models.Tag{
+		{EntityID: "e1", EntityType: "environment", Key: "name", Value: "env"},
+		{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc"},
+		{EntityID: "j1", EntityType: "job", Key: "name", Value: "job"},
+	})
+
+	testLogic.AddJobs(t, []*models.Job{
+		{JobID: "j1"},
+	})
+
+	adminLogic := NewL0AdminLogic(testLogic.Logic())
+	adminLogic.Clock = &testutils.StubClock{}
+
+	backup, err := adminLogic.Backup()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, backup.Version, BACKUP_VERSION)
+	testutils.AssertEqual(t, backup.Config.Prefix, config.Prefix())
+	testutils.AssertEqual(t, len(backup.Tags), 3)
+	testutils.AssertEqual(t, len(backup.Jobs), 1)
+}
+
+func TestSaveBackup(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	mockBackupStore := mock_logic.NewMockBackupStore(ctrl)
+	testLogic.BackupStore = mockBackupStore
+
+	testLogic.AddTags(t, []*models.Tag{
+		{EntityID: "e1", EntityType: "environment", Key: "name", Value: "env"},
+	})
+
+	mockBackupStore.EXPECT().
+		Put(gomock.Any()).
+		Return("backups/key.json", nil)
+
+	adminLogic := NewL0AdminLogic(testLogic.Logic())
+	summary, err := adminLogic.SaveBackup()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, summary.Key, "backups/key.json")
+	testutils.AssertEqual(t, summary.TagCount, 1)
+	testutils.AssertEqual(t, summary.JobCount, 0)
+}
+
+func TestRestore(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	mockChecker := mock_logic.NewMockConsistencyChecker(ctrl)
+	testLogic.ConsistencyChecker = mockChecker
+
+	// e1 already has its name tag, so only its os tag is restored
+	testLogic.AddTags(t, []*models.Tag{
+		{EntityID: "e1", EntityType: "environment", Key: "name", Value: "env"},
+	})
+
+	testLogic.AddJobs(t, []*models.Job{
+		{JobID: "j1"},
+	})
+
+	backup := &models.Backup{
+		Version: BACKUP_VERSION,
+		Config:  models.APIConfig{Prefix: config.Prefix()},
+		Tags: models.Tags{
+			{EntityID: "e1", EntityType: "environment", Key: "name", Value: "env"},
+			{EntityID: "e1", EntityType: "environment", Key: "os", Value: "linux"},
+			{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc"},
+		},
+		Jobs: []*models.Job{
+			{JobID: "j1"},
+			{JobID: "j2"},
+		},
+	}
+
+	mockChecker.EXPECT().
+		Check(gomock.Any()).
+		Return(&models.ConsistencyReport{EntitiesChecked: 2}, nil).
+		Times(2)
+
+	adminLogic := NewL0AdminLogic(testLogic.Logic())
+	report, err := adminLogic.Restore(models.RestoreRequest{Backup: backup})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, report.TagsInserted, 2)
+	testutils.AssertEqual(t, report.TagsUnchanged, 1)
+	testutils.AssertEqual(t, report.JobsInserted, 1)
+	testutils.AssertEqual(t, report.JobsUnchanged, 1)
+	testutils.AssertEqual(t, report.Consistency.EntitiesChecked, 2)
+
+	for _, tag := range backup.Tags {
+		testLogic.AssertTagExists(t, tag)
+	}
+
+	if _, err := testLogic.JobStore.SelectByID("j2"); err != nil {
+		t.Fatal(err)
+	}
+
+	// restoring the same backup again doesn't change anything
+	report, err = adminLogic.Restore(models.RestoreRequest{Backup: backup})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, report.TagsInserted, 0)
+	testutils.AssertEqual(t, report.TagsUnchanged, 3)
+	testutils.AssertEqual(t, report.JobsInserted, 0)
+	testutils.AssertEqual(t, report.JobsUnchanged, 2)
+}
+
+func TestRestoreConflicts(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	mockChecker := mock_logic.NewMockConsistencyChecker(ctrl)
+	testLogic.ConsistencyChecker = mockChecker
+
+	// e1 was renamed after the backup was taken
+	testLogic.AddTags(t, []*models.Tag{
+		{EntityID: "e1", EntityType: "environment", Key: "name", Value: "prod"},
+	})
+
+	backup := &models.Backup{
+		Version: BACKUP_VERSION,
+		Config:  models.APIConfig{Prefix: config.Prefix()},
+		Tags: models.Tags{
+			{EntityID: "e1", EntityType: "environment", Key: "name", Value: "staging"},
+			{EntityID: "e1", EntityType: "environment", Key: "os", Value: "linux"},
+		},
+	}
+
+	mockChecker.EXPECT().
+		Check(gomock.Any()).
+		Return(&models.ConsistencyReport{}, nil).
+		Times(2)
+
+	conflicts := []models.TagConflict{
+		{EntityType: "environment", EntityID: "e1", Key: "name", CurrentValue: "prod", BackupValue: "staging"},
+	}
+
+	adminLogic := NewL0AdminLogic(testLogic.Logic())
+	report, err := adminLogic.Restore(models.RestoreRequest{Backup: backup})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, report.TagsInserted, 1)
+	testutils.AssertEqual(t, report.TagsOverwritten, 0)
+	testutils.AssertEqual(t, report.TagConflicts, conflicts)
+	testLogic.AssertTagExists(t, models.Tag{EntityID: "e1", EntityType: "environment", Key: "name", Value: "prod"})
+
+	report, err = adminLogic.Restore(models.RestoreRequest{Backup: backup, Overwrite: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, report.TagsUnchanged, 1)
+	testutils.AssertEqual(t, report.TagsOverwritten, 1)
+	testutils.AssertEqual(t, report.TagConflicts, conflicts)
+	testLogic.AssertTagExists(t, models.Tag{EntityID: "e1", EntityType: "environment", Key: "name", Value: "staging"})
+}
+
+func TestRestoreChecksEveryBackedUpType(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	mockChecker := mock_logic.NewMockConsistencyChecker(ctrl)
+	testLogic.ConsistencyChecker = mockChecker
+
+	backup := &models.Backup{
+		Version: BACKUP_VERSION,
+		Config:  models.APIConfig{Prefix: config.Prefix()},
+		Tags: models.Tags{
+			{EntityID: "j1", EntityType: "job", Key: "task_id", Value: "t1"},
+			{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc"},
+		},
+	}
+
+	// the restored tags that are checked are the ones a backup would export
+	mockChecker.EXPECT().
+		Check(backup.Tags).
+		Return(&models.ConsistencyReport{}, nil)
+
+	adminLogic := NewL0AdminLogic(testLogic.Logic())
+	if _, err := adminLogic.Restore(models.RestoreRequest{Backup: backup}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRestoreDryRun(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	mockChecker := mock_logic.NewMockConsistencyChecker(ctrl)
+	testLogic.ConsistencyChecker = mockChecker
+
+	backup := &models.Backup{
+		Version: BACKUP_VERSION,
+		Config:  models.APIConfig{Prefix: config.Prefix()},
+		Tags: models.Tags{
+			{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc"},
+		},
+		Jobs: []*models.Job{
+			{JobID: "j1"},
+		},
+	}
+
+	mockChecker.EXPECT().
+		Check(backup.Tags).
+		Return(&models.ConsistencyReport{}, nil)
+
+	adminLogic := NewL0AdminLogic(testLogic.Logic())
+	report, err := adminLogic.Restore(models.RestoreRequest{Backup: backup, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, report.DryRun, true)
+	testutils.AssertEqual(t, report.TagsInserted, 1)
+	testutils.AssertEqual(t, report.JobsInserted, 1)
+
+	tags, err := testLogic.TagStore.SelectByType("service")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(tags), 0)
+
+	jobs, err := testLogic.JobStore.SelectAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(jobs), 0)
+}
+
+func TestRestoreFromKey(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	mockBackupStore := mock_logic.NewMockBackupStore(ctrl)
+	testLogic.BackupStore = mockBackupStore
+
+	mockChecker := mock_logic.NewMockConsistencyChecker(ctrl)
+	testLogic.ConsistencyChecker = mockChecker
+
+	backup := &models.Backup{
+		Version: BACKUP_VERSION,
+		Config:  models.APIConfig{Prefix: config.Prefix()},
+		Tags: models.Tags{
+			{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc"},
+		},
+	}
+
+	mockBackupStore.EXPECT().
+		Get("backups/key.json").
+		Return(backup, nil)
+
+	mockChecker.EXPECT().
+		Check(gomock.Any()).
+		Return(&models.ConsistencyReport{}, nil)
+
+	adminLogic := NewL0AdminLogic(testLogic.Logic())
+	report, err := adminLogic.Restore(models.RestoreRequest{Key: "backups/key.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, report.TagsInserted, 1)
+	testLogic.AssertTagExists(t, backup.Tags[0])
+}
+
+func TestRestoreErrors(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	validBackup := func() *models.Backup {
+		return &models.Backup{
+			Version: BACKUP_VERSION,
+			Config:  models.APIConfig{Prefix: config.Prefix()},
+		}
+	}
+
+	newerVersion := validBackup()
+	newerVersion.Version = BACKUP_VERSION + 1
+
+	otherPrefix := validBackup()
+	otherPrefix.Config.Prefix = "other"
+
+	cases := map[string]struct {
+		Request models.RestoreRequest
+		Code    errors.ErrorCode
+	}{
+		"Missing key and backup": {
+			Request: models.RestoreRequest{},
+			Code:    errors.MissingParameter,
+		},
+		"Key and backup": {
+			Request: models.RestoreRequest{Key: "backups/key.json", Backup: validBackup()},
+			Code:    errors.InvalidBackup,
+		},
+		"Newer version": {
+			Request: models.RestoreRequest{Backup: newerVersion},
+			Code:    errors.InvalidBackup,
+		},
+		"Other prefix": {
+			Request: models.RestoreRequest{Backup: otherPrefix},
+			Code:    errors.InvalidBackup,
+		},
+	}
+
+	adminLogic := NewL0AdminLogic(testLogic.Logic())
+	for name, c := range cases {
+		_, err := adminLogic.Restore(c.Request)
+		if serverErr, ok := err.(*errors.ServerError); !ok || serverErr.Code != c.Code {
+			t.Errorf("%s: expected error code %v, got %v", name, c.Code, err)
+		}
+	}
+}
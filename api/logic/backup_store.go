@@ -0,0 +1,65 @@
+package logic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/quintilesims/layer0/common/aws/s3"
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+)
+
+const BACKUP_PREFIX = "backups/"
+
+// BackupStore keeps backups of the layer0 state outside of the tag and job stores
+type BackupStore interface {
+	Put(backup *models.Backup) (string, error)
+	Get(key string) (*models.Backup, error)
+}
+
+// S3BackupStore writes each backup to its own json object in the layer0 bucket,
+// keyed by the time the backup was created
+type S3BackupStore struct {
+	S3     s3.Provider
+	Bucket string
+}
+
+func NewS3BackupStore(s3Provider s3.Provider, bucket string) *S3BackupStore {
+	return &S3BackupStore{
+		S3:     s3Provider,
+		Bucket: bucket,
+	}
+}
+
+func (this *S3BackupStore) Put(backup *models.Backup) (string, error) {
+	body, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	key := fmt.Sprintf("%s%s.json", BACKUP_PREFIX, backup.Created.UTC().Format("2006/01/02/150405.000000000"))
+	if err := this.S3.PutObject(this.Bucket, key, body); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+func (this *S3BackupStore) Get(key string) (*models.Backup, error) {
+	if !strings.HasPrefix(key, BACKUP_PREFIX) {
+		return nil, errors.Newf(errors.InvalidBackup, "Backup keys must start with '%s'", BACKUP_PREFIX)
+	}
+
+	body, err := this.S3.GetObject(this.Bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var backup *models.Backup
+	if err := json.Unmarshal(body, &backup); err != nil {
+		return nil, errors.Newf(errors.InvalidBackup, "Failed to parse backup '%s': %v", key, err)
+	}
+
+	return backup, nil
+}
@@ -0,0 +1,81 @@
+package logic
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/quintilesims/layer0/common/aws/s3/mock_s3"
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+func TestS3BackupStorePut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockS3 := mock_s3.NewMockProvider(ctrl)
+	store := NewS3BackupStore(mockS3, "bucket")
+
+	var body []byte
+	mockS3.EXPECT().
+		PutObject("bucket", "backups/2018/01/02/030405.000000000.json", gomock.Any()).
+		Do(func(bucket, key string, b []byte) {
+			body = b
+		}).
+		Return(nil)
+
+	backup := &models.Backup{
+		Version: 1,
+		Created: time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	key, err := store.Put(backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, key, "backups/2018/01/02/030405.000000000.json")
+	testutils.AssertEqual(t, strings.Contains(string(body), `"version": 1`), true)
+}
+
+func TestS3BackupStoreGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockS3 := mock_s3.NewMockProvider(ctrl)
+	store := NewS3BackupStore(mockS3, "bucket")
+
+	mockS3.EXPECT().
+		GetObject("bucket", "backups/key.json").
+		Return([]byte(`{"version":1,"tags":[{"entity_id":"e1","entity_type":"environment","key":"name","value":"env"}]}`), nil)
+
+	backup, err := store.Get("backups/key.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, backup.Version, 1)
+	testutils.AssertEqual(t, backup.Tags, models.Tags{{EntityID: "e1", EntityType: "environment", Key: "name", Value: "env"}})
+}
+
+func TestS3BackupStoreGetErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockS3 := mock_s3.NewMockProvider(ctrl)
+	store := NewS3BackupStore(mockS3, "bucket")
+
+	mockS3.EXPECT().
+		GetObject("bucket", "backups/invalid.json").
+		Return([]byte("not json"), nil)
+
+	for _, key := range []string{"job_archive/key.jsonl", "backups/invalid.json"} {
+		_, err := store.Get(key)
+		if serverErr, ok := err.(*errors.ServerError); !ok || serverErr.Code != errors.InvalidBackup {
+			t.Fatalf("%s: expected InvalidBackup error, got %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package logic
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/quintilesims/layer0/api/backend"
+	"github.com/quintilesims/layer0/api/backend/ecs/id"
+	"github.com/quintilesims/layer0/common/aws/autoscaling"
+	"github.com/quintilesims/layer0/common/models"
+)
+
+// ConsistencyChecker compares tagged entities with the AWS resources that back them
+type ConsistencyChecker interface {
+	Check(tags models.Tags) (*models.ConsistencyReport, error)
+}
+
+// L0ConsistencyChecker checks environments against their ECS clusters and autoscaling groups,
+// services against their ECS services, load balancers against their ELBs and deploys against
+// their task definitions. Tasks and jobs are short-lived, so their tags aren't checked.
+type L0ConsistencyChecker struct {
+	Backend     backend.Backend
+	AutoScaling autoscaling.Provider
+}
+
+func NewL0ConsistencyChecker(b backend.Backend, autoScaling autoscaling.Provider) *L0ConsistencyChecker {
+	return &L0ConsistencyChecker{
+		Backend:     b,
+		AutoScaling: autoScaling,
+	}
+}
+
+type consistencyCheck struct {
+	EntityType string
+	Resource   func(entityID string) string
+	ListIDs    func(backend.Backend) ([]string, error)
+}
+
+func (c *L0ConsistencyChecker) Check(tags models.Tags) (*models.ConsistencyReport, error) {
+	report := &models.ConsistencyReport{
+		Issues: []models.ConsistencyIssue{},
+	}
+
+	checks := []consistencyCheck{
+		{
+			EntityType: "environment",
+			Resource: func(entityID string) string {
+				return fmt.Sprintf("ECS cluster '%s'", id.L0EnvironmentID(entityID).ECSEnvironmentID())
+			},
+			ListIDs: listEnvironmentIDs,
+		},
+		{
+			EntityType: "load_balancer",
+			Resource: func(entityID string) string {
+				return fmt.Sprintf("ELB '%s'", id.L0LoadBalancerID(entityID).ECSLoadBalancerID())
+			},
+			ListIDs: listLoadBalancerIDs,
+		},
+		{
+			EntityType: "service",
+			Resource: func(entityID string) string {
+				return fmt.Sprintf("ECS service '%s'", id.L0ServiceID(entityID).ECSServiceID())
+			},
+			ListIDs: listServiceIDs,
+		},
+		{
+			EntityType: "deploy",
+			Resource: func(entityID string) string {
+				return fmt.Sprintf("task definition '%s'", id.L0DeployID(entityID).ECSDeployID().TaskDefinition())
+			},
+			ListIDs: listDeployIDs,
+		},
+	}
+
+	for _, check := range checks {
+		liveIDs, err := check.ListIDs(c.Backend)
+		if err != nil {
+			return nil, err
+		}
+
+		live := map[string]bool{}
+		for _, liveID := range liveIDs {
+			live[liveID] = true
+		}
+
+		tagged := map[string]bool{}
+		for _, ewt := range tags.WithType(check.EntityType).GroupByEntity() {
+			tagged[ewt.EntityID] = true
+			report.EntitiesChecked++
+
+			if !live[ewt.EntityID] {
+				report.Issues = append(report.Issues, models.ConsistencyIssue{
+					EntityType: check.EntityType,
+					EntityID:   ewt.EntityID,
+					Message:    fmt.Sprintf("%s does not exist", check.Resource(ewt.EntityID)),
+				})
+
+				continue
+			}
+
+			if check.EntityType == "environment" {
+				issue, err := c.checkAutoScalingGroup(ewt.EntityID)
+				if err != nil {
+					return nil, err
+				}
+
+				if issue != nil {
+					report.Issues = append(report.Issues, *issue)
+				}
+			}
+		}
+
+		for _, liveID := range liveIDs {
+			if !tagged[liveID] {
+				report.Issues = append(report.Issues, models.ConsistencyIssue{
+					EntityType: check.EntityType,
+					EntityID:   liveID,
+					Message:    fmt.Sprintf("%s has no tags", check.Resource(liveID)),
+				})
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func (c *L0ConsistencyChecker) checkAutoScalingGroup(environmentID string) (*models.ConsistencyIssue, error) {
+	name := id.L0EnvironmentID(environmentID).ECSEnvironmentID().AutoScalingGroupName()
+	if _, err := c.AutoScaling.DescribeAutoScalingGroup(name); err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			return nil, err
+		}
+
+		issue := &models.ConsistencyIssue{
+			EntityType: "environment",
+			EntityID:   environmentID,
+			Message:    fmt.Sprintf("Autoscaling group '%s' does not exist", name),
+		}
+
+		return issue, nil
+	}
+
+	return nil, nil
+}
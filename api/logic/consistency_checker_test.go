@@ -0,0 +1,102 @@
+package logic
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/quintilesims/layer0/api/backend/ecs/id"
+	"github.com/quintilesims/layer0/api/backend/mock_backend"
+	"github.com/quintilesims/layer0/common/aws/autoscaling"
+	"github.com/quintilesims/layer0/common/aws/autoscaling/mock_autoscaling"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+func TestConsistencyCheckerCheck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBackend := mock_backend.NewMockBackend(ctrl)
+	mockAutoScaling := mock_autoscaling.NewMockProvider(ctrl)
+	checker := NewL0ConsistencyChecker(mockBackend, mockAutoScaling)
+
+	mockBackend.EXPECT().
+		ListEnvironments().
+		Return([]id.ECSEnvironmentID{
+			id.L0EnvironmentID("e1").ECSEnvironmentID(),
+			id.L0EnvironmentID("e2").ECSEnvironmentID(),
+		}, nil)
+
+	mockBackend.EXPECT().
+		ListLoadBalancers().
+		Return([]*models.LoadBalancer{{LoadBalancerID: "l1"}, {LoadBalancerID: "l2"}}, nil)
+
+	mockBackend.EXPECT().
+		ListServices().
+		Return([]id.ECSServiceID{id.L0ServiceID("s1").ECSServiceID()}, nil)
+
+	mockBackend.EXPECT().
+		ListDeploys().
+		Return([]*models.Deploy{{DeployID: "d.1"}}, nil)
+
+	mockAutoScaling.EXPECT().
+		DescribeAutoScalingGroup(id.L0EnvironmentID("e1").ECSEnvironmentID().AutoScalingGroupName()).
+		Return(&autoscaling.Group{}, nil)
+
+	mockAutoScaling.EXPECT().
+		DescribeAutoScalingGroup(id.L0EnvironmentID("e2").ECSEnvironmentID().AutoScalingGroupName()).
+		Return(nil, fmt.Errorf("Autoscaling group 'l0-test-e2' not found"))
+
+	tags := models.Tags{
+		{EntityID: "e1", EntityType: "environment", Key: "name", Value: "env1"},
+		{EntityID: "e2", EntityType: "environment", Key: "name", Value: "env2"},
+		{EntityID: "l1", EntityType: "load_balancer", Key: "name", Value: "lb1"},
+		{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc1"},
+		{EntityID: "s2", EntityType: "service", Key: "name", Value: "svc2"},
+		{EntityID: "d.1", EntityType: "deploy", Key: "name", Value: "dpl"},
+		{EntityID: "t1", EntityType: "task", Key: "name", Value: "tsk"},
+	}
+
+	report, err := checker.Check(tags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, report.EntitiesChecked, 6)
+
+	issues := map[string]bool{}
+	for _, issue := range report.Issues {
+		issues[issue.EntityType+"/"+issue.EntityID] = true
+	}
+
+	expected := map[string]bool{
+		"environment/e2":   true,
+		"load_balancer/l2": true,
+		"service/s2":       true,
+	}
+
+	testutils.AssertEqual(t, issues, expected)
+}
+
+func TestConsistencyCheckerCheckAutoScalingError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBackend := mock_backend.NewMockBackend(ctrl)
+	mockAutoScaling := mock_autoscaling.NewMockProvider(ctrl)
+	checker := NewL0ConsistencyChecker(mockBackend, mockAutoScaling)
+
+	mockBackend.EXPECT().
+		ListEnvironments().
+		Return([]id.ECSEnvironmentID{id.L0EnvironmentID("e1").ECSEnvironmentID()}, nil)
+
+	mockAutoScaling.EXPECT().
+		DescribeAutoScalingGroup(gomock.Any()).
+		Return(nil, fmt.Errorf("some error"))
+
+	tags := models.Tags{{EntityID: "e1", EntityType: "environment", Key: "name", Value: "env1"}}
+	if _, err := checker.Check(tags); err == nil {
+		t.Fatal("Error was nil!")
+	}
+}
@@ -0,0 +1,165 @@
+package logic
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/quintilesims/layer0/common/config"
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+)
+
+// ECSJobExecutor runs each job in its own l0-runner task in the api environment
+type ECSJobExecutor struct {
+	TaskLogic   TaskLogic
+	DeployLogic DeployLogic
+}
+
+func NewECSJobExecutor(taskLogic TaskLogic, deployLogic DeployLogic) *ECSJobExecutor {
+	return &ECSJobExecutor{
+		TaskLogic:   taskLogic,
+		DeployLogic: deployLogic,
+	}
+}
+
+func (this *ECSJobExecutor) Execute(jobID string) (string, error) {
+	deploy, err := this.createJobDeploy(jobID)
+	if err != nil {
+		return "", err
+	}
+
+	return this.createJobTask(jobID, deploy.DeployID)
+}
+
+// Stop stops the job's runner task. Stopping the task signals the runner to cancel its in-flight step
+func (this *ECSJobExecutor) Stop(job *models.Job) error {
+	if err := this.TaskLogic.DeleteTask(job.TaskID); err != nil {
+		if se, ok := err.(*errors.ServerError); !ok || se.Code != errors.InvalidTaskID {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (this *ECSJobExecutor) createJobTask(jobID, deployID string) (string, error) {
+	taskRequest := models.CreateTaskRequest{
+		DeployID:      deployID,
+		EnvironmentID: config.API_ENVIRONMENT_ID,
+		TaskName:      jobID,
+	}
+
+	taskID, err := this.TaskLogic.CreateTask(taskRequest)
+	if err != nil {
+		return "", err
+	}
+
+	return taskID, nil
+}
+
+func (this *ECSJobExecutor) createJobDeploy(jobID string) (*models.Deploy, error) {
+	tmpl, err := template.New("").Parse(jobDockerrun)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse template: %v", err)
+	}
+
+	context := struct {
+		RunnerVersionTag string
+		Variables        []struct{ Key, Val string }
+	}{
+		RunnerVersionTag: config.RunnerVersionTag(),
+		Variables: []struct{ Key, Val string }{
+			{
+				Key: config.JOB_ID,
+				Val: jobID,
+			},
+			{
+				Key: config.AWS_DYNAMO_TAG_TABLE,
+				Val: config.DynamoTagTableName(),
+			},
+			{
+				Key: config.AWS_DYNAMO_JOB_TABLE,
+				Val: config.DynamoJobTableName(),
+			},
+			{
+				Key: config.DB_DRIVER,
+				Val: config.DBDriver(),
+			},
+			{
+				Key: config.DB_DATA_SOURCE,
+				Val: config.DBDataSource(),
+			},
+			{
+				Key: config.AWS_ACCESS_KEY_ID,
+				Val: config.AWSAccessKey(),
+			},
+			{
+				Key: config.AWS_SECRET_ACCESS_KEY,
+				Val: config.AWSSecretKey(),
+			},
+			{
+				Key: config.PREFIX,
+				Val: config.Prefix(),
+			},
+			{
+				Key: config.AWS_REGION,
+				Val: config.AWSRegion(),
+			},
+			{
+				Key: config.AWS_VPC_ID,
+				Val: config.AWSVPCID(),
+			},
+			{
+				Key: config.AWS_PUBLIC_SUBNETS,
+				Val: config.AWSPublicSubnets(),
+			},
+			{
+				Key: config.AWS_PRIVATE_SUBNETS,
+				Val: config.AWSPrivateSubnets(),
+			},
+			{
+				Key: config.RUNNER_LOG_LEVEL,
+				Val: config.RunnerLogLevel(),
+			},
+		},
+	}
+
+	var dockerrun bytes.Buffer
+	if err := tmpl.Execute(&dockerrun, context); err != nil {
+		return nil, fmt.Errorf("Failed to write template: %v", err)
+	}
+
+	deployRequest := models.CreateDeployRequest{
+		DeployName: "job",
+		Dockerrun:  dockerrun.Bytes(),
+	}
+
+	deploy, err := this.DeployLogic.CreateDeploy(deployRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return deploy, nil
+}
+
+var jobDockerrun string = `
+{
+    "AWSEBDockerrunVersion": 2,
+    "containerDefinitions": [
+        {
+            "name": "l0-job",
+            "image": "quintilesims/l0-runner:{{ .RunnerVersionTag }}",
+            "essential": true,
+            "memory": 64,
+            "environment": [
+		{{ range $i, $v := .Variables }}{{ if $i }}, {{ end }}
+                {
+                    "name":  "{{ .Key }}",
+                    "value": "{{ .Val }}"
+                }{{ end }}
+            ]
+        }
+    ]
+}
+`
@@ -0,0 +1,72 @@
+package logic
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/quintilesims/layer0/api/logic/mock_logic"
+	"github.com/quintilesims/layer0/common/config"
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+func TestECSJobExecutorExecute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	taskLogic := mock_logic.NewMockTaskLogic(ctrl)
+	deployLogic := mock_logic.NewMockDeployLogic(ctrl)
+	defer ctrl.Finish()
+
+	deployLogic.EXPECT().
+		CreateDeploy(gomock.Any()).
+		Return(&models.Deploy{DeployID: "d1"}, nil)
+
+	taskRequest := models.CreateTaskRequest{
+		DeployID:      "d1",
+		EnvironmentID: config.API_ENVIRONMENT_ID,
+		TaskName:      "j1",
+	}
+
+	taskLogic.EXPECT().
+		CreateTask(taskRequest).
+		Return("t1", nil)
+
+	executor := NewECSJobExecutor(taskLogic, deployLogic)
+	taskID, err := executor.Execute("j1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, taskID, "t1")
+}
+
+func TestECSJobExecutorStop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	taskLogic := mock_logic.NewMockTaskLogic(ctrl)
+	defer ctrl.Finish()
+
+	taskLogic.EXPECT().
+		DeleteTask("t1").
+		Return(errors.Newf(errors.InvalidTaskID, "task has already stopped"))
+
+	executor := NewECSJobExecutor(taskLogic, nil)
+	if err := executor.Stop(&models.Job{JobID: "j1", TaskID: "t1"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestECSJobExecutorStopError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	taskLogic := mock_logic.NewMockTaskLogic(ctrl)
+	defer ctrl.Finish()
+
+	taskLogic.EXPECT().
+		DeleteTask("t1").
+		Return(fmt.Errorf("some error"))
+
+	executor := NewECSJobExecutor(taskLogic, nil)
+	if err := executor.Stop(&models.Job{JobID: "j1", TaskID: "t1"}); err == nil {
+		t.Fatal("Error was nil!")
+	}
+}
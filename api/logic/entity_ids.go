@@ -0,0 +1,70 @@
+package logic
+
+import (
+	"github.com/quintilesims/layer0/api/backend"
+)
+
+// liveEntityIDs lists the ids of the entities of each type in types.EntityTypes
+// that currently exist in the backend
+var liveEntityIDs = map[string]func(backend.Backend) ([]string, error){
+	"deploy":        listDeployIDs,
+	"environment":   listEnvironmentIDs,
+	"load_balancer": listLoadBalancerIDs,
+	"service":       listServiceIDs,
+}
+
+func listEnvironmentIDs(b backend.Backend) ([]string, error) {
+	environmentIDs, err := b.ListEnvironments()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(environmentIDs))
+	for i, environmentID := range environmentIDs {
+		ids[i] = environmentID.L0EnvironmentID()
+	}
+
+	return ids, nil
+}
+
+func listLoadBalancerIDs(b backend.Backend) ([]string, error) {
+	loadBalancers, err := b.ListLoadBalancers()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(loadBalancers))
+	for i, loadBalancer := range loadBalancers {
+		ids[i] = loadBalancer.LoadBalancerID
+	}
+
+	return ids, nil
+}
+
+func listServiceIDs(b backend.Backend) ([]string, error) {
+	serviceIDs, err := b.ListServices()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(serviceIDs))
+	for i, serviceID := range serviceIDs {
+		ids[i] = serviceID.L0ServiceID()
+	}
+
+	return ids, nil
+}
+
+func listDeployIDs(b backend.Backend) ([]string, error) {
+	deploys, err := b.ListDeploys()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(deploys))
+	for i, deploy := range deploys {
+		ids[i] = deploy.DeployID
+	}
+
+	return ids, nil
+}
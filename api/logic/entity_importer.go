@@ -0,0 +1,268 @@
+package logic
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/quintilesims/layer0/api/backend"
+	"github.com/quintilesims/layer0/api/backend/ecs/id"
+	"github.com/quintilesims/layer0/common/config"
+	"github.com/quintilesims/layer0/common/db/tag_store"
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+)
+
+// importedTagKeys are the tag keys that are written when an entity is created
+// and that the EntityImporter infers for each entity type
+var importedTagKeys = map[string][]string{
+	"deploy":        {"name", "version"},
+	"environment":   {"name", "os"},
+	"load_balancer": {"name", "environment_id"},
+	"service":       {"name", "environment_id", "load_balancer_id"},
+}
+
+// EntityImporter adopts the resources that follow the 'l0-<prefix>-' naming conventions
+// of the id package but are missing tags, e.g. after a restore or a partial failure.
+// Only missing tag keys are written; existing tags are never changed.
+// The original names of environments, load balancers and services aren't kept in AWS and can't be
+// told apart from the hash in their ids, so they aren't imported and a warning is reported for each of them.
+type EntityImporter struct {
+	Backend  backend.Backend
+	TagStore tag_store.TagStore
+
+	services map[string]*models.Service
+}
+
+func NewEntityImporter(b backend.Backend, tagStore tag_store.TagStore) *EntityImporter {
+	return &EntityImporter{
+		Backend:  b,
+		TagStore: tagStore,
+	}
+}
+
+// Import writes the missing tags of each entity in the backend;
+// a dry run only reports the tags that would be written
+func (i *EntityImporter) Import(req models.ImportRequest) (*models.ImportReport, error) {
+	report := &models.ImportReport{
+		DryRun:   req.DryRun,
+		Entities: []models.EntityWithTags{},
+		Warnings: []string{},
+	}
+
+	// services are listed again on each import, since they may have changed
+	i.services = nil
+
+	entityTypes := []string{}
+	for entityType := range importedTagKeys {
+		entityTypes = append(entityTypes, entityType)
+	}
+
+	sort.Strings(entityTypes)
+
+	for _, entityType := range entityTypes {
+		entityIDs, err := liveEntityIDs[entityType](i.Backend)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to list %s entities: %v", entityType, err)
+		}
+
+		sort.Strings(entityIDs)
+		for _, entityID := range entityIDs {
+			missing, warnings, err := i.missingTags(entityType, entityID)
+			if err != nil {
+				return nil, err
+			}
+
+			report.Warnings = append(report.Warnings, warnings...)
+			if len(missing) == 0 {
+				continue
+			}
+
+			report.Entities = append(report.Entities, models.EntityWithTags{
+				EntityType: entityType,
+				EntityID:   entityID,
+				Tags:       missing,
+			})
+		}
+	}
+
+	if req.DryRun {
+		return report, nil
+	}
+
+	// the resources may have changed since the import was previewed
+	if req.Entities != nil && !sameImportedTags(req.Entities, report.Entities) {
+		return nil, errors.Newf(errors.EntityConflict, "The tags to import have changed since they were previewed; preview the import again")
+	}
+
+	for _, entity := range report.Entities {
+		for _, tag := range entity.Tags {
+			if err := i.TagStore.Insert(tag); err != nil {
+				return nil, fmt.Errorf("Failed to write tag %s/%s '%s': %v", tag.EntityType, tag.EntityID, tag.Key, err)
+			}
+
+			report.TagsWritten++
+		}
+	}
+
+	return report, nil
+}
+
+// sameImportedTags reports whether a and b have the same tags, regardless of their order
+func sameImportedTags(a, b []models.EntityWithTags) bool {
+	count := map[models.Tag]int{}
+	for _, entity := range a {
+		for _, tag := range entity.Tags {
+			count[tag]++
+		}
+	}
+
+	for _, entity := range b {
+		for _, tag := range entity.Tags {
+			count[tag]--
+		}
+	}
+
+	for _, n := range count {
+		if n != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (i *EntityImporter) missingTags(entityType, entityID string) (models.Tags, []string, error) {
+	current, err := i.TagStore.SelectByTypeAndID(entityType, entityID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	missingKeys := []string{}
+	for _, key := range importedTagKeys[entityType] {
+		if len(current.WithKey(key)) == 0 {
+			missingKeys = append(missingKeys, key)
+		}
+	}
+
+	if len(missingKeys) == 0 {
+		return nil, nil, nil
+	}
+
+	inferred, err := i.inferTags(entityType, entityID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	missing := models.Tags{}
+	warnings := []string{}
+	for _, key := range missingKeys {
+		value, ok := inferred[key]
+		if !ok {
+			switch {
+			case key == "name":
+				warnings = append(warnings, fmt.Sprintf("The name of %s '%s' can't be inferred from its id; add a 'name' tag to it", entityType, entityID))
+			// services don't need a load balancer
+			case entityType != "service" || key != "load_balancer_id":
+				warnings = append(warnings, fmt.Sprintf("Could not infer the %s of %s '%s'", key, entityType, entityID))
+			}
+
+			continue
+		}
+
+		missing = append(missing, models.Tag{
+			EntityID:   entityID,
+			EntityType: entityType,
+			Key:        key,
+			Value:      value,
+		})
+	}
+
+	return missing, warnings, nil
+}
+
+func (i *EntityImporter) inferTags(entityType, entityID string) (map[string]string, error) {
+	switch entityType {
+	case "deploy":
+		ecsDeployID := id.L0DeployID(entityID).ECSDeployID()
+		return map[string]string{
+			"name":    ecsDeployID.FamilyName(),
+			"version": ecsDeployID.Revision(),
+		}, nil
+	case "environment":
+		environment, err := i.Backend.GetEnvironment(entityID)
+		if err != nil {
+			return nil, err
+		}
+
+		// environments use the service ami of their os unless a custom ami was specified
+		os := "linux"
+		if windowsAMI := config.AWSWindowsServiceAMI(); environment.AMIID == windowsAMI && windowsAMI != config.AWSLinuxServiceAMI() {
+			os = "windows"
+		}
+
+		return map[string]string{"os": os}, nil
+	case "load_balancer":
+		services, err := i.listServices()
+		if err != nil {
+			return nil, err
+		}
+
+		// the environment of a load balancer is only known from the services that use it
+		tags := map[string]string{}
+		for _, service := range services {
+			if service.LoadBalancerID == entityID {
+				tags["environment_id"] = service.EnvironmentID
+				break
+			}
+		}
+
+		return tags, nil
+	case "service":
+		services, err := i.listServices()
+		if err != nil {
+			return nil, err
+		}
+
+		tags := map[string]string{}
+		if service, ok := services[entityID]; ok {
+			tags["environment_id"] = service.EnvironmentID
+			if service.LoadBalancerID != "" {
+				tags["load_balancer_id"] = service.LoadBalancerID
+			}
+		}
+
+		return tags, nil
+	default:
+		return nil, fmt.Errorf("Unsupported entity type '%s'", entityType)
+	}
+}
+
+// listServices returns the services of every environment by id;
+// the ecs cluster of a service isn't part of its id
+func (i *EntityImporter) listServices() (map[string]*models.Service, error) {
+	if i.services != nil {
+		return i.services, nil
+	}
+
+	environmentIDs, err := listEnvironmentIDs(i.Backend)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(environmentIDs)
+
+	services := map[string]*models.Service{}
+	for _, environmentID := range environmentIDs {
+		environmentServices, err := i.Backend.GetEnvironmentServices(environmentID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, service := range environmentServices {
+			services[service.ServiceID] = service
+		}
+	}
+
+	i.services = services
+	return services, nil
+}
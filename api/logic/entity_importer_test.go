@@ -0,0 +1,252 @@
+package logic
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/quintilesims/layer0/api/backend/ecs/id"
+	"github.com/quintilesims/layer0/api/backend/mock_backend"
+	"github.com/quintilesims/layer0/common/config"
+	"github.com/quintilesims/layer0/common/db/tag_store"
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+func TestEntityImporterImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	os.Setenv(config.AWS_WINDOWS_SERVICE_AMI, "ami-windows")
+	defer os.Setenv(config.AWS_WINDOWS_SERVICE_AMI, config.TEST_AWS_SERVICE_AMI)
+
+	mockBackend := mock_backend.NewMockBackend(ctrl)
+	tagStore := tag_store.NewMemoryTagStore()
+
+	// e1 is fully tagged, so only e2 is imported
+	insertTestTags(t, tagStore,
+		models.Tag{EntityID: "e1", EntityType: "environment", Key: "name", Value: "env1"},
+		models.Tag{EntityID: "e1", EntityType: "environment", Key: "os", Value: "linux"},
+		models.Tag{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc1"})
+
+	mockBackend.EXPECT().
+		ListDeploys().
+		Return([]*models.Deploy{{DeployID: "dpl.2"}}, nil)
+
+	mockBackend.EXPECT().
+		ListEnvironments().
+		Return([]id.ECSEnvironmentID{
+			id.L0EnvironmentID("e1").ECSEnvironmentID(),
+			id.L0EnvironmentID("e2").ECSEnvironmentID(),
+		}, nil).
+		Times(2)
+
+	mockBackend.EXPECT().
+		GetEnvironment("e2").
+		Return(&models.Environment{EnvironmentID: "e2", AMIID: "ami-windows"}, nil)
+
+	mockBackend.EXPECT().
+		ListLoadBalancers().
+		Return([]*models.LoadBalancer{{LoadBalancerID: "l1"}, {LoadBalancerID: "l2"}}, nil)
+
+	mockBackend.EXPECT().
+		GetEnvironmentServices("e1").
+		Return([]*models.Service{{ServiceID: "s1", EnvironmentID: "e1", LoadBalancerID: "l1"}}, nil)
+
+	mockBackend.EXPECT().
+		GetEnvironmentServices("e2").
+		Return([]*models.Service{}, nil)
+
+	mockBackend.EXPECT().
+		ListServices().
+		Return([]id.ECSServiceID{id.L0ServiceID("s1").ECSServiceID()}, nil)
+
+	importer := NewEntityImporter(mockBackend, tagStore)
+	report, err := importer.Import(models.ImportRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []models.EntityWithTags{
+		{
+			EntityType: "deploy",
+			EntityID:   "dpl.2",
+			Tags: models.Tags{
+				{EntityID: "dpl.2", EntityType: "deploy", Key: "name", Value: "dpl"},
+				{EntityID: "dpl.2", EntityType: "deploy", Key: "version", Value: "2"},
+			},
+		},
+		{
+			EntityType: "environment",
+			EntityID:   "e2",
+			Tags: models.Tags{
+				{EntityID: "e2", EntityType: "environment", Key: "os", Value: "windows"},
+			},
+		},
+		{
+			EntityType: "load_balancer",
+			EntityID:   "l1",
+			Tags: models.Tags{
+				{EntityID: "l1", EntityType: "load_balancer", Key: "environment_id", Value: "e1"},
+			},
+		},
+		{
+			EntityType: "service",
+			EntityID:   "s1",
+			Tags: models.Tags{
+				{EntityID: "s1", EntityType: "service", Key: "environment_id", Value: "e1"},
+				{EntityID: "s1", EntityType: "service", Key: "load_balancer_id", Value: "l1"},
+			},
+		},
+	}
+
+	testutils.AssertEqual(t, report.Entities, expected)
+	testutils.AssertEqual(t, report.Warnings, []string{
+		"The name of environment 'e2' can't be inferred from its id; add a 'name' tag to it",
+		"The name of load_balancer 'l1' can't be inferred from its id; add a 'name' tag to it",
+		"The name of load_balancer 'l2' can't be inferred from its id; add a 'name' tag to it",
+		"Could not infer the environment_id of load_balancer 'l2'",
+	})
+	testutils.AssertEqual(t, report.TagsWritten, 6)
+
+	// the existing name of s1 is kept
+	tags, err := tagStore.SelectByTypeAndID("service", "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, tags.WithKey("name")[0].Value, "svc1")
+
+	for _, entity := range expected {
+		for _, tag := range entity.Tags {
+			tags, err := tagStore.SelectByTypeAndID(tag.EntityType, tag.EntityID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertEqual(t, len(tags.WithKey(tag.Key).WithValue(tag.Value)), 1)
+		}
+	}
+}
+
+func TestEntityImporterImportDryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBackend := mock_backend.NewMockBackend(ctrl)
+	tagStore := tag_store.NewMemoryTagStore()
+
+	mockBackend.EXPECT().
+		ListDeploys().
+		Return([]*models.Deploy{{DeployID: "dpl.1"}}, nil)
+
+	mockBackend.EXPECT().
+		ListEnvironments().
+		Return([]id.ECSEnvironmentID{}, nil)
+
+	mockBackend.EXPECT().
+		ListLoadBalancers().
+		Return([]*models.LoadBalancer{}, nil)
+
+	mockBackend.EXPECT().
+		ListServices().
+		Return([]id.ECSServiceID{}, nil)
+
+	importer := NewEntityImporter(mockBackend, tagStore)
+	report, err := importer.Import(models.ImportRequest{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(report.Entities), 1)
+	testutils.AssertEqual(t, report.TagsWritten, 0)
+
+	tags, err := tagStore.SelectByType("deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(tags), 0)
+}
+
+func TestEntityImporterImportPreviewed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBackend := mock_backend.NewMockBackend(ctrl)
+	tagStore := tag_store.NewMemoryTagStore()
+
+	mockBackend.EXPECT().
+		ListDeploys().
+		Return([]*models.Deploy{{DeployID: "dpl.1"}}, nil).
+		Times(2)
+
+	mockBackend.EXPECT().
+		ListEnvironments().
+		Return([]id.ECSEnvironmentID{}, nil).
+		Times(2)
+
+	mockBackend.EXPECT().
+		ListLoadBalancers().
+		Return([]*models.LoadBalancer{}, nil).
+		Times(2)
+
+	mockBackend.EXPECT().
+		ListServices().
+		Return([]id.ECSServiceID{}, nil).
+		Times(2)
+
+	importer := NewEntityImporter(mockBackend, tagStore)
+
+	// a preview of a different deploy version is rejected without writing any tags
+	changed := []models.EntityWithTags{
+		{
+			EntityType: "deploy",
+			EntityID:   "dpl.1",
+			Tags: models.Tags{
+				{EntityID: "dpl.1", EntityType: "deploy", Key: "name", Value: "dpl"},
+				{EntityID: "dpl.1", EntityType: "deploy", Key: "version", Value: "2"},
+			},
+		},
+	}
+
+	if _, err := importer.Import(models.ImportRequest{Entities: changed}); err == nil {
+		t.Fatal("Error was nil!")
+	} else if serverErr, ok := err.(*errors.ServerError); !ok || serverErr.Code != errors.EntityConflict {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tags, err := tagStore.SelectByType("deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(tags), 0)
+
+	previewed := []models.EntityWithTags{
+		{
+			EntityType: "deploy",
+			EntityID:   "dpl.1",
+			Tags: models.Tags{
+				{EntityID: "dpl.1", EntityType: "deploy", Key: "version", Value: "1"},
+				{EntityID: "dpl.1", EntityType: "deploy", Key: "name", Value: "dpl"},
+			},
+		},
+	}
+
+	report, err := importer.Import(models.ImportRequest{Entities: previewed})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, report.TagsWritten, 2)
+}
+
+func insertTestTags(t *testing.T, store tag_store.TagStore, tags ...models.Tag) {
+	for _, tag := range tags {
+		if err := store.Insert(tag); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -1,7 +1,13 @@
 package logic
 
 import (
+	"fmt"
+	"strconv"
+	"time"
+
 	"github.com/quintilesims/layer0/api/backend/ecs/id"
+	"github.com/quintilesims/layer0/api/scheduler"
+	"github.com/quintilesims/layer0/api/scheduler/resource"
 	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
 )
@@ -11,10 +17,14 @@ type EnvironmentLogic interface {
 	GetEnvironment(id string) (*models.Environment, error)
 	DeleteEnvironment(id string) error
 	CanCreateEnvironment(req models.CreateEnvironmentRequest) (bool, error)
+	CanCloneEnvironment(req models.CloneEnvironmentRequest) error
 	CreateEnvironment(req models.CreateEnvironmentRequest) (*models.Environment, error)
-	UpdateEnvironment(id string, minClusterCount int) (*models.Environment, error)
+	UpdateEnvironment(id string, req models.UpdateEnvironmentRequest, version int64) (*models.Environment, error)
 	CreateEnvironmentLink(sourceEnvironmentID, destEnvironmentID string) error
 	DeleteEnvironmentLink(sourceEnvironmentID, destEnvironmentID string) error
+	GetPlacementStrategy(environmentID string) (string, error)
+	GetScalerSettings(environmentID string) (models.ScalerSettings, error)
+	GetScalerHistory(environmentID string, limit int) ([]*models.ScalerRun, error)
 }
 
 type L0EnvironmentLogic struct {
@@ -73,6 +83,14 @@ func (e *L0EnvironmentLogic) DeleteEnvironment(environmentID string) error {
 }
 
 func (e *L0EnvironmentLogic) CanCreateEnvironment(req models.CreateEnvironmentRequest) (bool, error) {
+	if _, err := scheduler.NewPlacementStrategy(req.PlacementStrategy); err != nil {
+		return false, err
+	}
+
+	if err := validateScalerSettings(req.ScalerSettings, req.MinClusterCount); err != nil {
+		return false, err
+	}
+
 	tags, err := e.TagStore.SelectByType("environment")
 	if err != nil {
 		return false, err
@@ -82,6 +100,82 @@ func (e *L0EnvironmentLogic) CanCreateEnvironment(req models.CreateEnvironmentRe
 	return len(tags) == 0, nil
 }
 
+// CanCloneEnvironment checks that the renames and scale overrides of the request refer to the source's
+// load balancers and services, and that the names of the copies are unique
+func (e *L0EnvironmentLogic) CanCloneEnvironment(req models.CloneEnvironmentRequest) error {
+	loadBalancerSummaries, err := NewL0LoadBalancerLogic(e.Logic).ListLoadBalancers()
+	if err != nil {
+		return err
+	}
+
+	loadBalancerNames := map[string]bool{}
+	copyNames := map[string]bool{}
+	for _, summary := range loadBalancerSummaries {
+		if summary.EnvironmentID != req.SourceEnvironmentID {
+			continue
+		}
+
+		loadBalancerNames[summary.LoadBalancerName] = true
+
+		name := summary.LoadBalancerName
+		if rename, ok := req.LoadBalancerNames[name]; ok {
+			name = rename
+		}
+
+		if copyNames[name] {
+			return errors.Newf(errors.InvalidLoadBalancerName, "More than one load balancer would be named '%s'", name)
+		}
+
+		copyNames[name] = true
+	}
+
+	for name := range req.LoadBalancerNames {
+		if !loadBalancerNames[name] {
+			return errors.Newf(errors.InvalidLoadBalancerName, "Environment '%s' has no load balancer named '%s'", req.SourceEnvironmentID, name)
+		}
+	}
+
+	services, err := NewL0ServiceLogic(e.Logic).GetEnvironmentServices(req.SourceEnvironmentID)
+	if err != nil {
+		return err
+	}
+
+	serviceNames := map[string]bool{}
+	copyNames = map[string]bool{}
+	for _, service := range services {
+		serviceNames[service.ServiceName] = true
+
+		name := service.ServiceName
+		if rename, ok := req.ServiceNames[name]; ok {
+			name = rename
+		}
+
+		if copyNames[name] {
+			return errors.Newf(errors.InvalidServiceName, "More than one service would be named '%s'", name)
+		}
+
+		copyNames[name] = true
+	}
+
+	for name := range req.ServiceNames {
+		if !serviceNames[name] {
+			return errors.Newf(errors.InvalidServiceName, "Environment '%s' has no service named '%s'", req.SourceEnvironmentID, name)
+		}
+	}
+
+	for name, count := range req.Scale {
+		if !serviceNames[name] {
+			return errors.Newf(errors.InvalidServiceName, "Environment '%s' has no service named '%s'", req.SourceEnvironmentID, name)
+		}
+
+		if count < 0 {
+			return errors.Newf(errors.InvalidJSON, "The scale of service '%s' cannot be negative", name)
+		}
+	}
+
+	return nil
+}
+
 func (e *L0EnvironmentLogic) CreateEnvironment(req models.CreateEnvironmentRequest) (*models.Environment, error) {
 	if req.EnvironmentName == "" {
 		return nil, errors.Newf(errors.MissingParameter, "EnvironmentName is required")
@@ -91,6 +185,14 @@ func (e *L0EnvironmentLogic) CreateEnvironment(req models.CreateEnvironmentReque
 		return nil, errors.Newf(errors.MissingParameter, "OperatingSystem is required")
 	}
 
+	if _, err := scheduler.NewPlacementStrategy(req.PlacementStrategy); err != nil {
+		return nil, err
+	}
+
+	if err := validateScalerSettings(req.ScalerSettings, req.MinClusterCount); err != nil {
+		return nil, err
+	}
+
 	environment, err := e.Backend.CreateEnvironment(
 		req.EnvironmentName,
 		req.InstanceSize,
@@ -110,6 +212,16 @@ func (e *L0EnvironmentLogic) CreateEnvironment(req models.CreateEnvironmentReque
 		return nil, err
 	}
 
+	if req.PlacementStrategy != "" {
+		if err := e.TagStore.Insert(models.Tag{EntityID: environment.EnvironmentID, EntityType: "environment", Key: "placement_strategy", Value: req.PlacementStrategy}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := e.setScalerSettings(environment.EnvironmentID, req.ScalerSettings); err != nil {
+		return nil, err
+	}
+
 	if err := e.populateModel(environment); err != nil {
 		return environment, err
 	}
@@ -117,12 +229,60 @@ func (e *L0EnvironmentLogic) CreateEnvironment(req models.CreateEnvironmentReque
 	return environment, nil
 }
 
-func (e *L0EnvironmentLogic) UpdateEnvironment(environmentID string, minClusterCount int) (*models.Environment, error) {
-	environment, err := e.Backend.UpdateEnvironment(environmentID, minClusterCount)
-	if err != nil {
+func (e *L0EnvironmentLogic) UpdateEnvironment(environmentID string, req models.UpdateEnvironmentRequest, version int64) (*models.Environment, error) {
+	if req.MinClusterCount == nil && req.PlacementStrategy == "" && req.ScalerSettings == nil {
+		return nil, errors.Newf(errors.MissingParameter, "MinClusterCount, PlacementStrategy, or ScalerSettings is required")
+	}
+
+	if req.PlacementStrategy != "" {
+		if _, err := scheduler.NewPlacementStrategy(req.PlacementStrategy); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.ScalerSettings != nil {
+		if err := e.validateUpdatedScalerSettings(environmentID, *req.ScalerSettings, req.MinClusterCount); err != nil {
+			return nil, err
+		}
+	}
+
+	var environment *models.Environment
+	if err := e.updateVersioned("environment", environmentID, version, func(mutating func()) error {
+		var err error
+		if req.MinClusterCount != nil {
+			mutating()
+			environment, err = e.Backend.UpdateEnvironment(environmentID, *req.MinClusterCount)
+		} else {
+			environment, err = e.Backend.GetEnvironment(environmentID)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		mutating()
+		if req.PlacementStrategy != "" {
+			if err := e.setPlacementStrategy(environmentID, req.PlacementStrategy); err != nil {
+				return err
+			}
+		}
+
+		if req.ScalerSettings != nil {
+			if err := e.setScalerSettings(environmentID, *req.ScalerSettings); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
 		return nil, err
 	}
 
+	if req.PlacementStrategy != "" || req.ScalerSettings != nil {
+		// the new settings may need a different number of instances
+		e.Scaler.ScheduleRun(environmentID, time.Second*10)
+	}
+
 	if err := e.populateModel(environment); err != nil {
 		return nil, err
 	}
@@ -130,6 +290,173 @@ func (e *L0EnvironmentLogic) UpdateEnvironment(environmentID string, minClusterC
 	return environment, nil
 }
 
+func (e *L0EnvironmentLogic) setPlacementStrategy(environmentID, strategy string) error {
+	if err := e.TagStore.Delete("environment", environmentID, "placement_strategy"); err != nil {
+		return err
+	}
+
+	return e.TagStore.Insert(models.Tag{EntityID: environmentID, EntityType: "environment", Key: "placement_strategy", Value: strategy})
+}
+
+// GetPlacementStrategy returns the name of the strategy the scaler uses to place resources in the environment;
+// environments created without a placement strategy use binpack
+func (e *L0EnvironmentLogic) GetPlacementStrategy(environmentID string) (string, error) {
+	tags, err := e.TagStore.SelectByTypeAndID("environment", environmentID)
+	if err != nil {
+		return "", err
+	}
+
+	if tag, ok := tags.WithKey("placement_strategy").First(); ok {
+		return tag.Value, nil
+	}
+
+	return scheduler.BinpackPlacement, nil
+}
+
+type scalerSettingTag struct {
+	key   string
+	value *int
+}
+
+// scalerSettingsTags pairs each of the scaler settings with the key of the tag it is stored in
+func scalerSettingsTags(settings *models.ScalerSettings) []scalerSettingTag {
+	return []scalerSettingTag{
+		{"scaler_headroom_instances", &settings.HeadroomInstances},
+		{"scaler_headroom_memory", &settings.HeadroomMemory},
+		{"scaler_scale_down_cooldown", &settings.ScaleDownCooldown},
+		{"scaler_max_cluster_count", &settings.MaxClusterCount},
+	}
+}
+
+// validateUpdatedScalerSettings validates settings against the min cluster count of the update, or against the
+// environment's current min cluster count if the update doesn't change it
+func (e *L0EnvironmentLogic) validateUpdatedScalerSettings(environmentID string, settings models.ScalerSettings, minClusterCount *int) error {
+	if minClusterCount != nil {
+		return validateScalerSettings(settings, *minClusterCount)
+	}
+
+	if _, err := resource.NewScalerSettings(settings); err != nil {
+		return err
+	}
+
+	environment, err := e.Backend.GetEnvironment(environmentID)
+	if err != nil {
+		return err
+	}
+
+	return validateScalerSettings(settings, environment.MinClusterCount)
+}
+
+func validateScalerSettings(settings models.ScalerSettings, minClusterCount int) error {
+	if _, err := resource.NewScalerSettings(settings); err != nil {
+		return err
+	}
+
+	if settings.MaxClusterCount > 0 && settings.MaxClusterCount < minClusterCount {
+		return errors.Newf(errors.InvalidScalerSettings, "MaxClusterCount cannot be less than MinClusterCount")
+	}
+
+	return nil
+}
+
+func (e *L0EnvironmentLogic) setScalerSettings(environmentID string, settings models.ScalerSettings) error {
+	for _, setting := range scalerSettingsTags(&settings) {
+		if err := e.TagStore.Delete("environment", environmentID, setting.key); err != nil {
+			return err
+		}
+
+		// settings that aren't stored use their zero value
+		if *setting.value == 0 {
+			continue
+		}
+
+		tag := models.Tag{EntityID: environmentID, EntityType: "environment", Key: setting.key, Value: strconv.Itoa(*setting.value)}
+		if err := e.TagStore.Insert(tag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetScalerSettings returns the settings that limit how the scaler changes the size of the environment
+func (e *L0EnvironmentLogic) GetScalerSettings(environmentID string) (models.ScalerSettings, error) {
+	tags, err := e.TagStore.SelectByTypeAndID("environment", environmentID)
+	if err != nil {
+		return models.ScalerSettings{}, err
+	}
+
+	return scalerSettingsFromTags(tags)
+}
+
+func scalerSettingsFromTags(tags models.Tags) (models.ScalerSettings, error) {
+	var settings models.ScalerSettings
+	for _, setting := range scalerSettingsTags(&settings) {
+		tag, ok := tags.WithKey(setting.key).First()
+		if !ok {
+			continue
+		}
+
+		value, err := strconv.Atoi(tag.Value)
+		if err != nil {
+			return settings, fmt.Errorf("Failed to parse scaler setting '%s': %v", setting.key, err)
+		}
+
+		*setting.value = value
+	}
+
+	return settings, nil
+}
+
+// scheduled scaler runs are stored apart from the environment's tags,
+// so scheduling a run doesn't change the environment's version
+const scalerRunEntityType = "scaler_run"
+
+// ScheduleScalerRun stores the time of the environment's next scaler run, unless an earlier run is already stored
+func (e *L0EnvironmentLogic) ScheduleScalerRun(environmentID string, at time.Time) error {
+	tags, err := e.TagStore.SelectByTypeAndID(scalerRunEntityType, environmentID)
+	if err != nil {
+		return err
+	}
+
+	if tag, ok := tags.WithKey("at").First(); ok {
+		if scheduled, err := strconv.ParseInt(tag.Value, 10, 64); err == nil && scheduled <= at.Unix() {
+			return nil
+		}
+	}
+
+	return e.TagStore.Insert(models.Tag{EntityID: environmentID, EntityType: scalerRunEntityType, Key: "at", Value: strconv.FormatInt(at.Unix(), 10)})
+}
+
+// ListScheduledScalerRuns returns the time of the next scaler run of each environment that has one
+func (e *L0EnvironmentLogic) ListScheduledScalerRuns() (map[string]time.Time, error) {
+	tags, err := e.TagStore.SelectByType(scalerRunEntityType)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := map[string]time.Time{}
+	for _, tag := range tags.WithKey("at") {
+		scheduled, err := strconv.ParseInt(tag.Value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse the scheduled scaler run of environment '%s': %v", tag.EntityID, err)
+		}
+
+		runs[tag.EntityID] = time.Unix(scheduled, 0)
+	}
+
+	return runs, nil
+}
+
+func (e *L0EnvironmentLogic) DeleteScheduledScalerRun(environmentID string) error {
+	return e.TagStore.Delete(scalerRunEntityType, environmentID, "at")
+}
+
+// GetScalerHistory returns the most recent scaler runs for the environment, newest first
+func (e *L0EnvironmentLogic) GetScalerHistory(environmentID string, limit int) ([]*models.ScalerRun, error) {
+	return e.Scaler.History(environmentID, limit)
+}
+
 func (e *L0EnvironmentLogic) CreateEnvironmentLink(sourceEnvironmentID, destEnvironmentID string) error {
 	if err := e.Backend.CreateEnvironmentLink(sourceEnvironmentID, destEnvironmentID); err != nil {
 		return err
@@ -182,6 +509,13 @@ func (e *L0EnvironmentLogic) populateModel(model *models.Environment) error {
 		return err
 	}
 
+	version, err := e.TagStore.SelectVersion("environment", model.EnvironmentID)
+	if err != nil {
+		return err
+	}
+
+	model.Version = version
+
 	if tag, ok := tags.WithKey("name").First(); ok {
 		model.EnvironmentName = tag.Value
 	}
@@ -190,6 +524,18 @@ func (e *L0EnvironmentLogic) populateModel(model *models.Environment) error {
 		model.OperatingSystem = tag.Value
 	}
 
+	model.PlacementStrategy = scheduler.BinpackPlacement
+	if tag, ok := tags.WithKey("placement_strategy").First(); ok {
+		model.PlacementStrategy = tag.Value
+	}
+
+	settings, err := scalerSettingsFromTags(tags)
+	if err != nil {
+		return err
+	}
+
+	model.ScalerSettings = settings
+
 	model.Links = []string{}
 	for _, tag := range tags.WithKey("link") {
 		model.Links = append(model.Links, tag.Value)
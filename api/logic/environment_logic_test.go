@@ -1,9 +1,14 @@
 package logic
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/quintilesims/layer0/api/backend/ecs/id"
+	"github.com/quintilesims/layer0/common/db/tag_store"
+	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/testutils"
 	"github.com/stretchr/testify/assert"
@@ -25,6 +30,7 @@ func TestGetEnvironment(t *testing.T) {
 		{EntityID: "e1", EntityType: "environment", Key: "name", Value: "env"},
 		{EntityID: "e1", EntityType: "environment", Key: "os", Value: "linux"},
 		{EntityID: "e1", EntityType: "environment", Key: "link", Value: "e2"},
+		{EntityID: "e1", EntityType: "environment", Key: "placement_strategy", Value: "spread-zone"},
 		{EntityID: "extra", EntityType: "environment", Key: "name", Value: "extra"},
 	})
 
@@ -35,10 +41,12 @@ func TestGetEnvironment(t *testing.T) {
 	}
 
 	expected := &models.Environment{
-		EnvironmentID:   "e1",
-		EnvironmentName: "env",
-		OperatingSystem: "linux",
-		Links:           []string{"e2"},
+		EnvironmentID:     "e1",
+		EnvironmentName:   "env",
+		OperatingSystem:   "linux",
+		PlacementStrategy: "spread-zone",
+		Links:             []string{"e2"},
+		Version:           4,
 	}
 
 	testutils.AssertEqual(t, received, expected)
@@ -155,6 +163,55 @@ func TestCanCreateEnvironment(t *testing.T) {
 	}
 }
 
+func TestCanCloneEnvironment(t *testing.T) {
+	requests := map[string]models.CloneEnvironmentRequest{
+		"Unknown load balancer":  {LoadBalancerNames: map[string]string{"web": "staging-web"}},
+		"Unknown service":        {ServiceNames: map[string]string{"web": "staging-web"}},
+		"Unknown scaled service": {Scale: map[string]int64{"web": 1}},
+		"Negative scale":         {Scale: map[string]int64{"api": -1}},
+		"Duplicate service name": {ServiceNames: map[string]string{"api": "worker"}},
+		"Valid":                  {ServiceNames: map[string]string{"api": "web"}, Scale: map[string]int64{"worker": 2}},
+	}
+
+	for name, req := range requests {
+		t.Run(name, func(t *testing.T) {
+			testLogic, ctrl := NewTestLogic(t)
+			defer ctrl.Finish()
+
+			testLogic.AddTags(t, []*models.Tag{
+				{EntityID: "l1", EntityType: "load_balancer", Key: "name", Value: "api"},
+				{EntityID: "l1", EntityType: "load_balancer", Key: "environment_id", Value: "e1"},
+				{EntityID: "s1", EntityType: "service", Key: "name", Value: "api"},
+				{EntityID: "s1", EntityType: "service", Key: "environment_id", Value: "e1"},
+				{EntityID: "s2", EntityType: "service", Key: "name", Value: "worker"},
+				{EntityID: "s2", EntityType: "service", Key: "environment_id", Value: "e1"},
+			})
+
+			testLogic.Backend.EXPECT().
+				ListLoadBalancers().
+				Return([]*models.LoadBalancer{{LoadBalancerID: "l1"}}, nil)
+
+			testLogic.Backend.EXPECT().
+				GetEnvironmentServices("e1").
+				Return([]*models.Service{{ServiceID: "s1"}, {ServiceID: "s2"}}, nil).
+				AnyTimes()
+
+			req.SourceEnvironmentID = "e1"
+			req.EnvironmentName = "staging"
+
+			environmentLogic := NewL0EnvironmentLogic(testLogic.Logic())
+			err := environmentLogic.CanCloneEnvironment(req)
+			if name == "Valid" && err != nil {
+				t.Fatal(err)
+			}
+
+			if name != "Valid" && err == nil {
+				t.Fatal("Error was nil!")
+			}
+		})
+	}
+}
+
 func TestCreateEnvironment(t *testing.T) {
 	testLogic, ctrl := NewTestLogic(t)
 	defer ctrl.Finish()
@@ -168,12 +225,14 @@ func TestCreateEnvironment(t *testing.T) {
 		Return(retEnvironment, nil)
 
 	request := models.CreateEnvironmentRequest{
-		EnvironmentName:  "name",
-		InstanceSize:     "m3.medium",
-		OperatingSystem:  "linux",
-		AMIID:            "amiid",
-		MinClusterCount:  2,
-		UserDataTemplate: []byte("user_data"),
+		EnvironmentName:   "name",
+		InstanceSize:      "m3.medium",
+		OperatingSystem:   "linux",
+		AMIID:             "amiid",
+		MinClusterCount:   2,
+		UserDataTemplate:  []byte("user_data"),
+		PlacementStrategy: "spread-instance",
+		ScalerSettings:    models.ScalerSettings{HeadroomInstances: 1, MaxClusterCount: 5},
 	}
 
 	environmentLogic := NewL0EnvironmentLogic(testLogic.Logic())
@@ -183,15 +242,21 @@ func TestCreateEnvironment(t *testing.T) {
 	}
 
 	expected := &models.Environment{
-		EnvironmentID:   "e1",
-		EnvironmentName: "name",
-		OperatingSystem: "linux",
-		Links:           []string{},
+		EnvironmentID:     "e1",
+		EnvironmentName:   "name",
+		OperatingSystem:   "linux",
+		PlacementStrategy: "spread-instance",
+		ScalerSettings:    models.ScalerSettings{HeadroomInstances: 1, MaxClusterCount: 5},
+		Links:             []string{},
+		Version:           5,
 	}
 
 	testutils.AssertEqual(t, received, expected)
 	testLogic.AssertTagExists(t, models.Tag{EntityID: "e1", EntityType: "environment", Key: "name", Value: "name"})
 	testLogic.AssertTagExists(t, models.Tag{EntityID: "e1", EntityType: "environment", Key: "os", Value: "linux"})
+	testLogic.AssertTagExists(t, models.Tag{EntityID: "e1", EntityType: "environment", Key: "placement_strategy", Value: "spread-instance"})
+	testLogic.AssertTagExists(t, models.Tag{EntityID: "e1", EntityType: "environment", Key: "scaler_headroom_instances", Value: "1"})
+	testLogic.AssertTagExists(t, models.Tag{EntityID: "e1", EntityType: "environment", Key: "scaler_max_cluster_count", Value: "5"})
 }
 
 func TestCreateEnvironmentError_missingRequiredParams(t *testing.T) {
@@ -207,6 +272,22 @@ func TestCreateEnvironmentError_missingRequiredParams(t *testing.T) {
 		"Missing OperatingSystem": {
 			EnvironmentName: "name",
 		},
+		"Unknown PlacementStrategy": {
+			EnvironmentName:   "name",
+			OperatingSystem:   "linux",
+			PlacementStrategy: "random",
+		},
+		"Negative ScalerSettings": {
+			EnvironmentName: "name",
+			OperatingSystem: "linux",
+			ScalerSettings:  models.ScalerSettings{HeadroomMemory: -1},
+		},
+		"MaxClusterCount below MinClusterCount": {
+			EnvironmentName: "name",
+			OperatingSystem: "linux",
+			MinClusterCount: 3,
+			ScalerSettings:  models.ScalerSettings{MaxClusterCount: 2},
+		},
 	}
 
 	for name, request := range cases {
@@ -233,21 +314,276 @@ func TestUpdateEnvironment(t *testing.T) {
 		{EntityID: "extra", EntityType: "environment", Key: "name", Value: "extra"},
 	})
 
+	minClusterCount := 2
+	request := models.UpdateEnvironmentRequest{
+		MinClusterCount: &minClusterCount,
+	}
+
 	environmentLogic := NewL0EnvironmentLogic(testLogic.Logic())
-	received, err := environmentLogic.UpdateEnvironment("e1", 2)
+	received, err := environmentLogic.UpdateEnvironment("e1", request, tag_store.AnyVersion)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	expected := &models.Environment{
-		EnvironmentID:   "e1",
-		EnvironmentName: "env",
-		Links:           []string{},
+		EnvironmentID:     "e1",
+		EnvironmentName:   "env",
+		PlacementStrategy: "binpack",
+		Links:             []string{},
+		Version:           2,
 	}
 
 	testutils.AssertEqual(t, received, expected)
 }
 
+func TestUpdateEnvironmentPlacementStrategy(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	testLogic.Backend.EXPECT().
+		GetEnvironment("e1").
+		Return(&models.Environment{EnvironmentID: "e1"}, nil)
+
+	testLogic.Scaler.EXPECT().
+		ScheduleRun("e1", gomock.Any())
+
+	testLogic.AddTags(t, []*models.Tag{
+		{EntityID: "e1", EntityType: "environment", Key: "placement_strategy", Value: "binpack"},
+	})
+
+	request := models.UpdateEnvironmentRequest{
+		PlacementStrategy: "spread-zone",
+	}
+
+	environmentLogic := NewL0EnvironmentLogic(testLogic.Logic())
+	received, err := environmentLogic.UpdateEnvironment("e1", request, tag_store.AnyVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, received.PlacementStrategy, "spread-zone")
+
+	strategy, err := environmentLogic.GetPlacementStrategy("e1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, strategy, "spread-zone")
+}
+
+func TestUpdateEnvironmentScalerSettings(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	testLogic.Backend.EXPECT().
+		GetEnvironment("e1").
+		Return(&models.Environment{EnvironmentID: "e1"}, nil).
+		Times(2)
+
+	testLogic.Scaler.EXPECT().
+		ScheduleRun("e1", gomock.Any())
+
+	testLogic.AddTags(t, []*models.Tag{
+		{EntityID: "e1", EntityType: "environment", Key: "scaler_headroom_instances", Value: "2"},
+		{EntityID: "e1", EntityType: "environment", Key: "scaler_max_cluster_count", Value: "5"},
+	})
+
+	settings := models.ScalerSettings{
+		HeadroomMemory:    1024,
+		ScaleDownCooldown: 300,
+		MaxClusterCount:   10,
+	}
+
+	request := models.UpdateEnvironmentRequest{
+		ScalerSettings: &settings,
+	}
+
+	environmentLogic := NewL0EnvironmentLogic(testLogic.Logic())
+	received, err := environmentLogic.UpdateEnvironment("e1", request, tag_store.AnyVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, received.ScalerSettings, settings)
+
+	stored, err := environmentLogic.GetScalerSettings("e1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, stored, settings)
+}
+
+func TestUpdateEnvironmentError_invalidRequest(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	environmentLogic := NewL0EnvironmentLogic(testLogic.Logic())
+
+	cases := map[string]models.UpdateEnvironmentRequest{
+		"Empty request":             {},
+		"Unknown PlacementStrategy": {PlacementStrategy: "random"},
+		"Negative ScalerSettings":   {ScalerSettings: &models.ScalerSettings{ScaleDownCooldown: -1}},
+	}
+
+	for name, request := range cases {
+		if _, err := environmentLogic.UpdateEnvironment("e1", request, tag_store.AnyVersion); err == nil {
+			t.Errorf("Case %s: error was nil!", name)
+		}
+	}
+}
+
+func TestUpdateEnvironmentScalerSettingsUsesCurrentMinClusterCount(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	testLogic.Backend.EXPECT().
+		GetEnvironment("e1").
+		Return(&models.Environment{EnvironmentID: "e1", MinClusterCount: 3}, nil)
+
+	request := models.UpdateEnvironmentRequest{
+		ScalerSettings: &models.ScalerSettings{MaxClusterCount: 2},
+	}
+
+	environmentLogic := NewL0EnvironmentLogic(testLogic.Logic())
+	if _, err := environmentLogic.UpdateEnvironment("e1", request, tag_store.AnyVersion); err == nil {
+		t.Fatalf("Error was nil!")
+	} else if serr, ok := err.(*errors.ServerError); !ok || serr.Code != errors.InvalidScalerSettings {
+		t.Fatalf("Expected InvalidScalerSettings error, got: %v", err)
+	}
+}
+
+func TestUpdateEnvironmentErrorBeforeChangesRevertsVersion(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	testLogic.AddTags(t, []*models.Tag{
+		{EntityID: "e1", EntityType: "environment", Key: "name", Value: "env"},
+	})
+
+	version, err := testLogic.TagStore.SelectVersion("environment", "e1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testLogic.Backend.EXPECT().
+		GetEnvironment("e1").
+		Return(nil, fmt.Errorf("some error"))
+
+	request := models.UpdateEnvironmentRequest{
+		PlacementStrategy: "spread-zone",
+	}
+
+	environmentLogic := NewL0EnvironmentLogic(testLogic.Logic())
+	if _, err := environmentLogic.UpdateEnvironment("e1", request, version); err == nil {
+		t.Fatalf("Error was nil!")
+	}
+
+	reverted, err := testLogic.TagStore.SelectVersion("environment", "e1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, reverted, version)
+}
+
+func TestUpdateEnvironmentErrorAfterChangesKeepsNewVersion(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	testLogic.AddTags(t, []*models.Tag{
+		{EntityID: "e1", EntityType: "environment", Key: "name", Value: "env"},
+	})
+
+	version, err := testLogic.TagStore.SelectVersion("environment", "e1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testLogic.Backend.EXPECT().
+		UpdateEnvironment("e1", 2).
+		Return(nil, fmt.Errorf("some error"))
+
+	minClusterCount := 2
+	request := models.UpdateEnvironmentRequest{
+		MinClusterCount: &minClusterCount,
+	}
+
+	environmentLogic := NewL0EnvironmentLogic(testLogic.Logic())
+	if _, err := environmentLogic.UpdateEnvironment("e1", request, version); err == nil {
+		t.Fatalf("Error was nil!")
+	}
+
+	// the backend may have changed the auto scaling group before it failed
+	current, err := testLogic.TagStore.SelectVersion("environment", "e1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, current, version+1)
+}
+
+func TestGetPlacementStrategyDefault(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	environmentLogic := NewL0EnvironmentLogic(testLogic.Logic())
+	strategy, err := environmentLogic.GetPlacementStrategy("e1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, strategy, "binpack")
+}
+
+func TestScheduleScalerRunKeepsEarlierRun(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	testLogic.AddTags(t, []*models.Tag{
+		{EntityID: "e1", EntityType: "environment", Key: "name", Value: "env"},
+	})
+
+	version, err := testLogic.TagStore.SelectVersion("environment", "e1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	environmentLogic := NewL0EnvironmentLogic(testLogic.Logic())
+	early := time.Unix(100, 0)
+	for _, at := range []time.Time{time.Unix(200, 0), early, time.Unix(300, 0)} {
+		if err := environmentLogic.ScheduleScalerRun("e1", at); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runs, err := environmentLogic.ListScheduledScalerRuns()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, runs, map[string]time.Time{"e1": early})
+
+	// scheduled runs don't change the environment's version
+	current, err := testLogic.TagStore.SelectVersion("environment", "e1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, current, version)
+
+	if err := environmentLogic.DeleteScheduledScalerRun("e1"); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, err = environmentLogic.ListScheduledScalerRuns()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(runs), 0)
+}
+
 func TestCreateEnvironmentLink(t *testing.T) {
 	testLogic, ctrl := NewTestLogic(t)
 	defer ctrl.Finish()
@@ -291,3 +627,24 @@ func TestDeleteEnvironmentLink(t *testing.T) {
 	// make sure the 'extra' tag is the only one left
 	testutils.AssertEqual(t, len(tags), 1)
 }
+
+func TestGetScalerHistory(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	runs := []*models.ScalerRun{
+		{ScalerRunInfo: models.ScalerRunInfo{EnvironmentID: "e1"}},
+	}
+
+	testLogic.Scaler.EXPECT().
+		History("e1", 10).
+		Return(runs, nil)
+
+	environmentLogic := NewL0EnvironmentLogic(testLogic.Logic())
+	received, err := environmentLogic.GetScalerHistory("e1", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, received, runs)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/quintilesims/layer0/api/backend/ecs"
 	"github.com/quintilesims/layer0/api/scheduler/resource"
+	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/types"
 	"github.com/zpatrick/go-bytesize"
@@ -30,7 +31,7 @@ func NewEnvironmentResourceGetter(s ServiceLogic, t TaskLogic, d DeployLogic, j
 }
 
 func (c *EnvironmentResourceGetter) GetConsumers(environmentID string) ([]resource.ResourceConsumer, error) {
-	serviceResources, err := c.getPendingServiceResources(environmentID)
+	serviceResources, err := c.getPendingServiceResources(environmentID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -50,22 +51,111 @@ func (c *EnvironmentResourceGetter) GetConsumers(environmentID string) ([]resour
 	return totalResources, nil
 }
 
-func (c *EnvironmentResourceGetter) getPendingServiceResources(environmentID string) ([]resource.ResourceConsumer, error) {
+// GetSimulatedConsumers returns the consumers the environment would have if the services in the request
+// were scaled to their desired counts and the tasks in the request were created
+func (c *EnvironmentResourceGetter) GetSimulatedConsumers(environmentID string, req models.ScalerSimulationRequest) ([]resource.ResourceConsumer, error) {
+	simulatedServices := map[string]models.SimulatedService{}
+	newServiceCopies := []map[string]int{}
+	for _, service := range req.Services {
+		if service.DesiredCount < 0 {
+			return nil, errors.Newf(errors.MissingParameter, "DesiredCount must be at least 0")
+		}
+
+		if service.ServiceID != "" {
+			simulatedServices[service.ServiceID] = service
+			continue
+		}
+
+		if service.DeployID == "" {
+			return nil, errors.Newf(errors.MissingParameter, "DeployID is required for services without a ServiceID")
+		}
+
+		newServiceCopies = append(newServiceCopies, map[string]int{service.DeployID: service.DesiredCount})
+	}
+
+	for _, task := range req.Tasks {
+		if task.DeployID == "" {
+			return nil, errors.Newf(errors.MissingParameter, "DeployID is required for tasks")
+		}
+
+		if task.Copies < 1 {
+			return nil, errors.Newf(errors.MissingParameter, "Copies must be at least 1")
+		}
+	}
+
+	serviceResources, err := c.getPendingServiceResources(environmentID, simulatedServices)
+	if err != nil {
+		return nil, err
+	}
+
+	taskResourcesInECS, err := c.getPendingTaskResourcesInECS(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	taskResourcesInJobs, err := c.getPendingTaskResourcesInJobs(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	totalResources := append(serviceResources, taskResourcesInECS...)
+	totalResources = append(totalResources, taskResourcesInJobs...)
+
+	for i, deployIDCopies := range newServiceCopies {
+		group := fmt.Sprintf("Simulated Service: %d", i+1)
+		generateID := func(deployID, containerName string, copy int) string {
+			return fmt.Sprintf("%s, Deploy: %s, Container: %s, Copy: %d", group, deployID, containerName, copy)
+		}
+
+		serviceResourceConsumers, err := c.getResourcesHelper(deployIDCopies, group, generateID)
+		if err != nil {
+			return nil, err
+		}
+
+		totalResources = append(totalResources, serviceResourceConsumers...)
+	}
+
+	for i, task := range req.Tasks {
+		group := fmt.Sprintf("Simulated Task: %d", i+1)
+		generateID := func(deployID, containerName string, copy int) string {
+			return fmt.Sprintf("%s, Deploy: %s, Container: %s, Copy: %d", group, deployID, containerName, copy)
+		}
+
+		taskResourceConsumers, err := c.getResourcesHelper(map[string]int{task.DeployID: task.Copies}, group, generateID)
+		if err != nil {
+			return nil, err
+		}
+
+		totalResources = append(totalResources, taskResourceConsumers...)
+	}
+
+	return totalResources, nil
+}
+
+// getPendingServiceResources returns the consumers of the environment's services that are not on instances yet.
+// Services in simulatedServices are treated as if they had been scaled to the simulated desired count
+func (c *EnvironmentResourceGetter) getPendingServiceResources(environmentID string, simulatedServices map[string]models.SimulatedService) ([]resource.ResourceConsumer, error) {
 	resourceConsumers := []resource.ResourceConsumer{}
 	services, err := c.ServiceLogic.GetEnvironmentServices(environmentID)
 	if err != nil {
 		return nil, err
 	}
 
+	found := map[string]bool{}
 	for _, service := range services {
 		deployIDCopies := map[string]int{}
-		for _, deployment := range service.Deployments {
-			// deployment.RunningCount is the number of containers already running on an instance
-			// deployment.PendingCount is the number of containers that are alraedy on an instance, but are being pulled
-			// we only care about containers that are not on instances yet
-
-			if numPending := deployment.DesiredCount - (deployment.RunningCount + deployment.PendingCount); numPending > 0 {
-				deployIDCopies[deployment.DeployID] = int(numPending)
+		if simulated, ok := simulatedServices[service.ServiceID]; ok {
+			found[service.ServiceID] = true
+			deployIDCopies = simulatedServiceCopies(service, simulated)
+		} else {
+			for _, deployment := range service.Deployments {
+				// deployment.RunningCount is the number of containers already running on an instance
+				// deployment.PendingCount is the number of containers that are alraedy on an instance, but are being pulled
+				// we only care about containers that are not on instances yet
+
+				if numPending := deployment.DesiredCount - (deployment.RunningCount + deployment.PendingCount); numPending > 0 {
+					deployIDCopies[deployment.DeployID] = int(numPending)
+				}
 			}
 		}
 
@@ -78,7 +168,7 @@ func (c *EnvironmentResourceGetter) getPendingServiceResources(environmentID str
 			return fmt.Sprintf("Service: %s, Deploy: %s, Container: %s, Copy: %d", service.ServiceID, deployID, containerName, copy)
 		}
 
-		serviceResourceConsumers, err := c.getResourcesHelper(deployIDCopies, generateID)
+		serviceResourceConsumers, err := c.getResourcesHelper(deployIDCopies, fmt.Sprintf("Service: %s", service.ServiceID), generateID)
 		if err != nil {
 			return nil, err
 		}
@@ -86,9 +176,36 @@ func (c *EnvironmentResourceGetter) getPendingServiceResources(environmentID str
 		resourceConsumers = append(resourceConsumers, serviceResourceConsumers...)
 	}
 
+	for serviceID := range simulatedServices {
+		if !found[serviceID] {
+			return nil, errors.Newf(errors.InvalidServiceID, "Service '%s' is not in environment '%s'", serviceID, environmentID)
+		}
+	}
+
 	return resourceConsumers, nil
 }
 
+// simulatedServiceCopies returns the copies of the simulated deploy that the service would need to place.
+// Copies that are already on instances are kept, so only the difference is pending
+func simulatedServiceCopies(service *models.Service, simulated models.SimulatedService) map[string]int {
+	deployID := simulated.DeployID
+	if deployID == "" && len(service.Deployments) > 0 {
+		deployID = service.Deployments[0].DeployID
+	}
+
+	var placed int64
+	for _, deployment := range service.Deployments {
+		placed += deployment.RunningCount + deployment.PendingCount
+	}
+
+	deployIDCopies := map[string]int{}
+	if numPending := int64(simulated.DesiredCount) - placed; numPending > 0 && deployID != "" {
+		deployIDCopies[deployID] = int(numPending)
+	}
+
+	return deployIDCopies
+}
+
 func (c *EnvironmentResourceGetter) getPendingTaskResourcesInECS(environmentID string) ([]resource.ResourceConsumer, error) {
 	tasks, err := c.TaskLogic.GetEnvironmentTasks(environmentID)
 	if err != nil {
@@ -110,7 +227,7 @@ func (c *EnvironmentResourceGetter) getPendingTaskResourcesInECS(environmentID s
 			return fmt.Sprintf("Task: %s, Deploy: %s, Container: %s, Copy: %d", task.TaskID, deployID, containerName, copy)
 		}
 
-		taskResourceConsumers, err := c.getResourcesHelper(deployIDCopies, generateID)
+		taskResourceConsumers, err := c.getResourcesHelper(deployIDCopies, fmt.Sprintf("Task: %s", task.TaskID), generateID)
 		if err != nil {
 			return nil, err
 		}
@@ -122,7 +239,8 @@ func (c *EnvironmentResourceGetter) getPendingTaskResourcesInECS(environmentID s
 }
 
 func (c *EnvironmentResourceGetter) getPendingTaskResourcesInJobs(environmentID string) ([]resource.ResourceConsumer, error) {
-	jobs, err := c.JobLogic.ListJobs()
+	// jobs that create tasks target the environment the tasks run in
+	jobs, err := c.JobLogic.ListJobsByEntity("environment", environmentID)
 	if err != nil {
 		return nil, err
 	}
@@ -136,24 +254,22 @@ func (c *EnvironmentResourceGetter) getPendingTaskResourcesInJobs(environmentID
 					return nil, err
 				}
 
-				if req.EnvironmentID == environmentID {
-					// note that this isn't exact if the job has started some, but not all of the tasks
-					deployIDCopies := map[string]int{
-						req.DeployID: 1,
-					}
-
-					// resource consumer ids are just used for debugging purposes
-					generateID := func(deployID, containerName string, copy int) string {
-						return fmt.Sprintf("Task: %s, Deploy: %s, Container: %s, Copy: %d", req.TaskName, deployID, containerName, copy)
-					}
+				// note that this isn't exact if the job has started some, but not all of the tasks
+				deployIDCopies := map[string]int{
+					req.DeployID: 1,
+				}
 
-					taskResourceConsumers, err := c.getResourcesHelper(deployIDCopies, generateID)
-					if err != nil {
-						return nil, err
-					}
+				// resource consumer ids are just used for debugging purposes
+				generateID := func(deployID, containerName string, copy int) string {
+					return fmt.Sprintf("Task: %s, Deploy: %s, Container: %s, Copy: %d", req.TaskName, deployID, containerName, copy)
+				}
 
-					resourceConsumers = append(resourceConsumers, taskResourceConsumers...)
+				taskResourceConsumers, err := c.getResourcesHelper(deployIDCopies, fmt.Sprintf("Task: %s", req.TaskName), generateID)
+				if err != nil {
+					return nil, err
 				}
+
+				resourceConsumers = append(resourceConsumers, taskResourceConsumers...)
 			}
 		}
 	}
@@ -161,7 +277,9 @@ func (c *EnvironmentResourceGetter) getPendingTaskResourcesInJobs(environmentID
 	return resourceConsumers, nil
 }
 
-func (c *EnvironmentResourceGetter) getResourcesHelper(deployIDCopies map[string]int, generateID func(string, string, int) string) ([]resource.ResourceConsumer, error) {
+// getResourcesHelper creates a consumer for each copy of each container in the deploys.
+// Copies of the same container are placed in the same group, e.g. "Service: s1, Container: api"
+func (c *EnvironmentResourceGetter) getResourcesHelper(deployIDCopies map[string]int, group string, generateID func(string, string, int) string) ([]resource.ResourceConsumer, error) {
 	resourceConsumers := []resource.ResourceConsumer{}
 	for deployID, copies := range deployIDCopies {
 		containerResources, err := c.getContainerResourcesFromDeploy(deployID)
@@ -172,7 +290,8 @@ func (c *EnvironmentResourceGetter) getResourcesHelper(deployIDCopies map[string
 		for i := 0; i < copies; i++ {
 			for _, containerResource := range containerResources {
 				id := generateID(deployID, containerResource.ID, i+1)
-				consumer := resource.NewResourceConsumer(id, containerResource.Memory, containerResource.Ports)
+				consumer := resource.NewResourceConsumer(id, containerResource.Memory, containerResource.CPU, containerResource.Ports)
+				consumer.Group = fmt.Sprintf("%s, Container: %s", group, containerResource.ID)
 				resourceConsumers = append(resourceConsumers, consumer)
 			}
 		}
@@ -208,6 +327,11 @@ func (c *EnvironmentResourceGetter) getContainerResourcesFromDeploy(deployID str
 			memory = bytesize.MiB * bytesize.Bytesize(*container.Memory)
 		}
 
+		var cpu int
+		if container.Cpu != nil {
+			cpu = int(*container.Cpu)
+		}
+
 		ports := []int{}
 		for _, p := range container.PortMappings {
 			if p.HostPort != nil && *p.HostPort != 0 {
@@ -215,7 +339,7 @@ func (c *EnvironmentResourceGetter) getContainerResourcesFromDeploy(deployID str
 			}
 		}
 
-		consumers[i] = resource.NewResourceConsumer(*container.Name, memory, ports)
+		consumers[i] = resource.NewResourceConsumer(*container.Name, memory, cpu, ports)
 	}
 
 	c.deployCache[deployID] = consumers
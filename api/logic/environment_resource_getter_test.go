@@ -84,6 +84,7 @@ var deployWithTwoContainers []byte = []byte(`
     {
       "name": "two",
       "memory": 1000,
+      "cpu": 256,
       "portMappings": [
         {
           "hostPort": 8000,
@@ -124,9 +125,11 @@ func TestGetPendingTaskResourcesInJobs(t *testing.T) {
 		{
 			JobID:     "j3",
 			JobType:   int64(types.CreateTaskJob),
-			JobStatus: int64(types.InProgress),
+			JobStatus: int64(types.Completed),
 			Request: requestToString(t, models.CreateTaskRequest{
-				EnvironmentID: "e3",
+				TaskName:      "t3",
+				DeployID:      "d3",
+				EnvironmentID: "e1",
 			}),
 		},
 		{
@@ -137,7 +140,7 @@ func TestGetPendingTaskResourcesInJobs(t *testing.T) {
 	}
 
 	crg.JobLogic.EXPECT().
-		ListJobs().
+		ListJobsByEntity("environment", "e1").
 		Return(jobs, nil)
 
 	crg.DeployLogic.EXPECT().
@@ -215,10 +218,12 @@ func TestGetPendingTaskResourcesInECS(t *testing.T) {
 	// task2, deploy2, container1, copy1
 	testutils.AssertEqual(t, resources[2].Ports, []int{80})
 	testutils.AssertEqual(t, resources[2].Memory, bytesize.MiB*500)
+	testutils.AssertEqual(t, resources[2].CPU, 0)
 
 	// task2, deploy2, container2, copy1
 	testutils.AssertEqual(t, resources[3].Ports, []int{8000})
 	testutils.AssertEqual(t, resources[3].Memory, bytesize.MiB*1000)
+	testutils.AssertEqual(t, resources[3].CPU, 256)
 }
 
 func TestGetPendingServiceResources(t *testing.T) {
@@ -273,7 +278,7 @@ func TestGetPendingServiceResources(t *testing.T) {
 		GetDeploy("d2").
 		Return(&models.Deploy{Dockerrun: deployWithTwoContainers}, nil)
 
-	resources, err := crg.EnvironmentResourceGetter().getPendingServiceResources("e1")
+	resources, err := crg.EnvironmentResourceGetter().getPendingServiceResources("e1", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -291,8 +296,116 @@ func TestGetPendingServiceResources(t *testing.T) {
 	// service2, deploy2, container1, copy1
 	testutils.AssertEqual(t, resources[2].Ports, []int{80})
 	testutils.AssertEqual(t, resources[2].Memory, bytesize.MiB*500)
+	testutils.AssertEqual(t, resources[2].CPU, 0)
 
 	// service2, deploy2, container2, copy1
 	testutils.AssertEqual(t, resources[3].Ports, []int{8000})
 	testutils.AssertEqual(t, resources[3].Memory, bytesize.MiB*1000)
+	testutils.AssertEqual(t, resources[3].CPU, 256)
+}
+
+func TestGetSimulatedConsumers(t *testing.T) {
+	crg, ctrl := newTestEnvironmentResourceGetter(t)
+	defer ctrl.Finish()
+
+	services := []*models.Service{
+		{
+			ServiceID:     "s1",
+			EnvironmentID: "e1",
+			Deployments: []models.Deployment{
+				{
+					DeployID:     "d1",
+					DesiredCount: 1,
+					RunningCount: 1,
+				},
+			},
+		},
+	}
+
+	crg.ServiceLogic.EXPECT().
+		GetEnvironmentServices("e1").
+		Return(services, nil)
+
+	crg.TaskLogic.EXPECT().
+		GetEnvironmentTasks("e1").
+		Return([]*models.Task{}, nil)
+
+	crg.JobLogic.EXPECT().
+		ListJobsByEntity("environment", "e1").
+		Return([]*models.Job{}, nil)
+
+	crg.DeployLogic.EXPECT().
+		GetDeploy("d1").
+		Return(&models.Deploy{Dockerrun: deployWithOneContainer}, nil).
+		AnyTimes()
+
+	crg.DeployLogic.EXPECT().
+		GetDeploy("d2").
+		Return(&models.Deploy{Dockerrun: deployWithTwoContainers}, nil).
+		AnyTimes()
+
+	req := models.ScalerSimulationRequest{
+		Services: []models.SimulatedService{
+			{ServiceID: "s1", DesiredCount: 3},
+			{DeployID: "d2", DesiredCount: 1},
+		},
+		Tasks: []models.SimulatedTask{
+			{DeployID: "d1", Copies: 1},
+		},
+	}
+
+	resources, err := crg.EnvironmentResourceGetter().GetSimulatedConsumers("e1", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(resources), 5)
+
+	// service1 has 1 copy running, so 2 more copies of deploy1 are pending
+	testutils.AssertEqual(t, resources[0].Ports, []int{80, 22})
+	testutils.AssertEqual(t, resources[0].Group, "Service: s1, Container: one")
+	testutils.AssertEqual(t, resources[1].Ports, []int{80, 22})
+
+	// the new service needs 1 copy of deploy2, which has 2 containers
+	testutils.AssertEqual(t, resources[2].Group, "Simulated Service: 1, Container: one")
+	testutils.AssertEqual(t, resources[3].Group, "Simulated Service: 1, Container: two")
+	testutils.AssertEqual(t, resources[3].CPU, 256)
+
+	// the new task needs 1 copy of deploy1
+	testutils.AssertEqual(t, resources[4].Group, "Simulated Task: 1, Container: one")
+	testutils.AssertEqual(t, resources[4].Memory, bytesize.MiB*500)
+}
+
+func TestGetSimulatedConsumers_errors(t *testing.T) {
+	crg, ctrl := newTestEnvironmentResourceGetter(t)
+	defer ctrl.Finish()
+
+	crg.ServiceLogic.EXPECT().
+		GetEnvironmentServices("e1").
+		Return([]*models.Service{}, nil).
+		AnyTimes()
+
+	requests := map[string]models.ScalerSimulationRequest{
+		"Negative service count": {
+			Services: []models.SimulatedService{{ServiceID: "s1", DesiredCount: -1}},
+		},
+		"New service without deploy": {
+			Services: []models.SimulatedService{{DesiredCount: 1}},
+		},
+		"Task without deploy": {
+			Tasks: []models.SimulatedTask{{Copies: 1}},
+		},
+		"Task without copies": {
+			Tasks: []models.SimulatedTask{{DeployID: "d1"}},
+		},
+		"Service not in environment": {
+			Services: []models.SimulatedService{{ServiceID: "s1", DesiredCount: 1}},
+		},
+	}
+
+	for name, req := range requests {
+		if _, err := crg.EnvironmentResourceGetter().GetSimulatedConsumers("e1", req); err == nil {
+			t.Fatalf("%s: error was nil!", name)
+		}
+	}
 }
@@ -0,0 +1,87 @@
+package logic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/quintilesims/layer0/common/aws/s3"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/waitutils"
+)
+
+const JOB_ARCHIVE_PREFIX = "job_archive/"
+
+// JobArchive keeps the record of jobs after they are removed from the job store
+type JobArchive interface {
+	Archive(jobs []*models.Job) error
+	List() ([]*models.Job, error)
+}
+
+// S3JobArchive writes each batch of archived jobs to its own object in the layer0 bucket,
+// one json-encoded job per line
+type S3JobArchive struct {
+	S3     s3.Provider
+	Bucket string
+	Clock  waitutils.Clock
+}
+
+func NewS3JobArchive(s3Provider s3.Provider, bucket string) *S3JobArchive {
+	return &S3JobArchive{
+		S3:     s3Provider,
+		Bucket: bucket,
+		Clock:  waitutils.RealClock{},
+	}
+}
+
+func (this *S3JobArchive) Archive(jobs []*models.Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	for _, job := range jobs {
+		if err := encoder.Encode(job); err != nil {
+			return err
+		}
+	}
+
+	// keys sort by the time they were written
+	key := fmt.Sprintf("%s%s.jsonl", JOB_ARCHIVE_PREFIX, this.Clock.Now().UTC().Format("2006/01/02/150405.000000000"))
+	return this.S3.PutObject(this.Bucket, key, buffer.Bytes())
+}
+
+// List returns every archived job, oldest first
+func (this *S3JobArchive) List() ([]*models.Job, error) {
+	keys, err := this.S3.ListObjects(this.Bucket, JOB_ARCHIVE_PREFIX)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(keys)
+
+	jobs := []*models.Job{}
+	for _, key := range keys {
+		body, err := this.S3.GetObject(this.Bucket, key)
+		if err != nil {
+			return nil, err
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		for {
+			var job *models.Job
+			if err := decoder.Decode(&job); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("Failed to parse archived jobs in '%s': %v", key, err)
+			}
+
+			jobs = append(jobs, job)
+		}
+	}
+
+	return jobs, nil
+}
@@ -0,0 +1,86 @@
+package logic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/quintilesims/layer0/common/aws/s3/mock_s3"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+func TestS3JobArchiveArchive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockS3 := mock_s3.NewMockProvider(ctrl)
+	archive := NewS3JobArchive(mockS3, "bucket")
+
+	var body []byte
+	mockS3.EXPECT().
+		PutObject("bucket", gomock.Any(), gomock.Any()).
+		Do(func(bucket, key string, b []byte) {
+			if !strings.HasPrefix(key, JOB_ARCHIVE_PREFIX) || !strings.HasSuffix(key, ".jsonl") {
+				t.Errorf("Unexpected key '%s'", key)
+			}
+
+			body = b
+		}).
+		Return(nil)
+
+	jobs := []*models.Job{
+		{JobID: "j1"},
+		{JobID: "j2"},
+	}
+
+	if err := archive.Archive(jobs); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	testutils.AssertEqual(t, len(lines), 2)
+	testutils.AssertEqual(t, strings.Contains(lines[0], `"job_id":"j1"`), true)
+	testutils.AssertEqual(t, strings.Contains(lines[1], `"job_id":"j2"`), true)
+}
+
+func TestS3JobArchiveArchiveEmpty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// no object should be written
+	archive := NewS3JobArchive(mock_s3.NewMockProvider(ctrl), "bucket")
+	if err := archive.Archive(nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestS3JobArchiveList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockS3 := mock_s3.NewMockProvider(ctrl)
+	archive := NewS3JobArchive(mockS3, "bucket")
+
+	mockS3.EXPECT().
+		ListObjects("bucket", JOB_ARCHIVE_PREFIX).
+		Return([]string{"job_archive/2018/01/02/000000.000000000.jsonl", "job_archive/2018/01/01/000000.000000000.jsonl"}, nil)
+
+	mockS3.EXPECT().
+		GetObject("bucket", "job_archive/2018/01/01/000000.000000000.jsonl").
+		Return([]byte("{\"job_id\":\"j1\"}\n{\"job_id\":\"j2\"}\n"), nil)
+
+	mockS3.EXPECT().
+		GetObject("bucket", "job_archive/2018/01/02/000000.000000000.jsonl").
+		Return([]byte("{\"job_id\":\"j3\"}\n"), nil)
+
+	jobs, err := archive.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(jobs), 3)
+	testutils.AssertEqual(t, jobs[0].JobID, "j1")
+	testutils.AssertEqual(t, jobs[1].JobID, "j2")
+	testutils.AssertEqual(t, jobs[2].JobID, "j3")
+}
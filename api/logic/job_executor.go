@@ -0,0 +1,15 @@
+package logic
+
+import (
+	"github.com/quintilesims/layer0/common/models"
+)
+
+// JobExecutor runs the runner for a job. The executor is selected at startup
+// with the LAYER0_JOB_EXECUTOR variable; see startup.GetJobExecutor
+type JobExecutor interface {
+	// Execute starts running the job and returns the id of the task it runs in.
+	// Executors that don't run jobs in tasks return an empty task id
+	Execute(jobID string) (string, error)
+	// Stop stops the job's runner, if it is running
+	Stop(job *models.Job) error
+}
@@ -3,61 +3,125 @@ package logic
 import (
 	"time"
 
+	"github.com/quintilesims/layer0/common/config"
 	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/logutils"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/types"
 	"github.com/quintilesims/layer0/common/waitutils"
 )
 
 const (
-	JOB_LIFETIME           = time.Hour * 1
 	JANITOR_SLEEP_DURATION = time.Minute * 10
 )
 
 var jobLogger = logutils.NewStackTraceLogger("Job Janitor")
 
+// JobRetention is how long jobs are kept in the job store, by status.
+// Statuses without an entry are kept for Unfinished
+type JobRetention struct {
+	ByStatus   map[types.JobStatus]time.Duration
+	Unfinished time.Duration
+}
+
+func NewJobRetentionFromConfig() JobRetention {
+	return JobRetention{
+		ByStatus: map[types.JobStatus]time.Duration{
+			types.Completed: config.JobRetentionCompleted(),
+			types.Error:     config.JobRetentionError(),
+			types.Cancelled: config.JobRetentionCancelled(),
+		},
+		Unfinished: config.JobRetentionUnfinished(),
+	}
+}
+
+func (r JobRetention) For(status types.JobStatus) time.Duration {
+	if retention, ok := r.ByStatus[status]; ok {
+		return retention
+	}
+
+	return r.Unfinished
+}
+
+// JobJanitor archives and removes jobs that are past their retention
 type JobJanitor struct {
-	jobLogic JobLogic
-	Clock    waitutils.Clock
+	jobLogic   JobLogic
+	jobArchive JobArchive
+	Retention  JobRetention
+	Leader     Leader
+	Clock      waitutils.Clock
 }
 
-func NewJobJanitor(jobLogic JobLogic) *JobJanitor {
+func NewJobJanitor(jobLogic JobLogic, jobArchive JobArchive, retention JobRetention) *JobJanitor {
 	return &JobJanitor{
-		jobLogic: jobLogic,
-		Clock:    waitutils.RealClock{},
+		jobLogic:   jobLogic,
+		jobArchive: jobArchive,
+		Retention:  retention,
+		Leader:     AlwaysLeader{},
+		Clock:      waitutils.RealClock{},
 	}
 }
 
 func (this *JobJanitor) Run() {
-	go func() {
-		for {
-			jobLogger.Info("Starting cleanup")
-			this.pulse()
-			jobLogger.Infof("Finished cleanup")
-			this.Clock.Sleep(JANITOR_SLEEP_DURATION)
-		}
-	}()
+	// only the leader cleans up, so replicas don't race to archive the same jobs
+	go RunAsLeader(this.Leader, this.Clock, JANITOR_SLEEP_DURATION, func() {
+		jobLogger.Info("Starting cleanup")
+		this.pulse()
+		jobLogger.Infof("Finished cleanup")
+	})
+}
+
+var jobStatuses = []types.JobStatus{
+	types.Pending,
+	types.InProgress,
+	types.Completed,
+	types.Error,
+	types.Cancelled,
 }
 
 func (this *JobJanitor) pulse() error {
-	jobs, err := this.jobLogic.ListJobs()
-	if err != nil {
-		jobLogger.Errorf("Failed to list jobs: %v", err)
+	expired := []*models.Job{}
+	for _, status := range jobStatuses {
+		jobs, err := this.jobLogic.ListJobsByStatus(status)
+		if err != nil {
+			jobLogger.Errorf("Failed to list %s jobs: %v", status, err)
+			return err
+		}
+
+		retention := this.Retention.For(status)
+		for _, job := range jobs {
+			if this.Clock.Since(job.TimeCreated) > retention {
+				expired = append(expired, job)
+			}
+		}
+	}
+
+	if len(expired) == 0 {
+		return nil
+	}
+
+	// jobs are only removed once their record is safely in the archive
+	jobLogger.Infof("Archiving %d jobs", len(expired))
+	if err := this.jobArchive.Archive(expired); err != nil {
+		jobLogger.Errorf("Failed to archive jobs: %v", err)
 		return err
 	}
 
 	errs := []error{}
-	for _, job := range jobs {
-		timeSinceCreated := this.Clock.Since(job.TimeCreated)
+	for _, job := range expired {
+		// a replica that lost the lease stops; the new leader deletes the jobs that are left
+		if !this.Leader.IsLeader() {
+			jobLogger.Infof("Stopped deleting jobs: this replica is no longer the leader")
+			break
+		}
 
-		if timeSinceCreated > JOB_LIFETIME {
-			jobLogger.Infof("Deleting job '%s'", job.JobID)
+		jobLogger.Infof("Deleting job '%s'", job.JobID)
 
-			if err := this.jobLogic.Delete(job.JobID); err != nil {
-				jobLogger.Errorf("Failed to delete job '%s': %v", job.JobID, err)
-				errs = append(errs, err)
-			} else {
-				jobLogger.Infof("Finished deleting job '%s'", job.JobID)
-			}
+		if err := this.jobLogic.Delete(job.JobID); err != nil {
+			jobLogger.Errorf("Failed to delete job '%s': %v", job.JobID, err)
+			errs = append(errs, err)
+		} else {
+			jobLogger.Infof("Finished deleting job '%s'", job.JobID)
 		}
 	}
 
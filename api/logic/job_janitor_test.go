@@ -1,40 +1,150 @@
 package logic
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/quintilesims/layer0/api/logic/mock_logic"
 	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/types"
 )
 
+func expectListJobsByStatus(jobLogicMock *mock_logic.MockJobLogic, jobs []*models.Job) {
+	for _, status := range jobStatuses {
+		matching := []*models.Job{}
+		for _, job := range jobs {
+			if types.JobStatus(job.JobStatus) == status {
+				matching = append(matching, job)
+			}
+		}
+
+		jobLogicMock.EXPECT().
+			ListJobsByStatus(status).
+			Return(matching, nil)
+	}
+}
+
 func TestJobJanitorPulse(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
+	jobArchiveMock := mock_logic.NewMockJobArchive(ctrl)
+
+	retention := JobRetention{
+		ByStatus: map[types.JobStatus]time.Duration{
+			types.Completed: time.Hour,
+			types.Error:     time.Hour * 24,
+		},
+		Unfinished: time.Hour * 48,
+	}
+
+	oldCompleted := &models.Job{
+		JobID:       "old_completed",
+		JobStatus:   int64(types.Completed),
+		TimeCreated: time.Now().Add(-time.Hour * 2),
+	}
+
+	jobs := []*models.Job{
+		oldCompleted,
+		{
+			JobID:       "young_completed",
+			JobStatus:   int64(types.Completed),
+			TimeCreated: time.Now(),
+		},
+		{
+			JobID:       "error_within_retention",
+			JobStatus:   int64(types.Error),
+			TimeCreated: time.Now().Add(-time.Hour * 2),
+		},
+		{
+			JobID:       "in_progress",
+			JobStatus:   int64(types.InProgress),
+			TimeCreated: time.Now().Add(-time.Hour * 24),
+		},
+	}
+
+	expectListJobsByStatus(jobLogicMock, jobs)
+
+	gomock.InOrder(
+		jobArchiveMock.EXPECT().
+			Archive([]*models.Job{oldCompleted}).
+			Return(nil),
+
+		jobLogicMock.EXPECT().
+			Delete("old_completed").
+			Return(nil),
+	)
+
+	janitor := NewJobJanitor(jobLogicMock, jobArchiveMock, retention)
+	if err := janitor.pulse(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJobJanitorPulseArchiveError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
+	jobArchiveMock := mock_logic.NewMockJobArchive(ctrl)
 
 	jobs := []*models.Job{
 		{
 			JobID:       "old_job",
-			TimeCreated: time.Now().Add(-(JOB_LIFETIME * 2)),
+			JobStatus:   int64(types.Completed),
+			TimeCreated: time.Now().Add(-time.Hour * 2),
 		},
+	}
+
+	expectListJobsByStatus(jobLogicMock, jobs)
+
+	jobArchiveMock.EXPECT().
+		Archive(gomock.Any()).
+		Return(fmt.Errorf("some error"))
+
+	// jobs that could not be archived must not be deleted
+	janitor := NewJobJanitor(jobLogicMock, jobArchiveMock, JobRetention{Unfinished: time.Hour})
+	if err := janitor.pulse(); err == nil {
+		t.Fatal("Error was nil!")
+	}
+}
+
+func TestJobJanitorPulseStopsWhenNotLeader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	jobLogicMock := mock_logic.NewMockJobLogic(ctrl)
+	jobArchiveMock := mock_logic.NewMockJobArchive(ctrl)
+
+	jobs := []*models.Job{
 		{
-			JobID:       "young_job",
-			TimeCreated: time.Now(),
+			JobID:       "job1",
+			JobStatus:   int64(types.Completed),
+			TimeCreated: time.Now().Add(-time.Hour * 2),
+		},
+		{
+			JobID:       "job2",
+			JobStatus:   int64(types.Completed),
+			TimeCreated: time.Now().Add(-time.Hour * 2),
 		},
 	}
 
-	jobLogicMock.EXPECT().
-		ListJobs().
-		Return(jobs, nil)
+	expectListJobsByStatus(jobLogicMock, jobs)
+
+	jobArchiveMock.EXPECT().
+		Archive(gomock.Any()).
+		Return(nil)
 
+	// the replica loses the lease after deleting the first job
 	jobLogicMock.EXPECT().
-		Delete("old_job").
+		Delete("job1").
 		Return(nil)
 
-	janitor := NewJobJanitor(jobLogicMock)
+	janitor := NewJobJanitor(jobLogicMock, jobArchiveMock, JobRetention{Unfinished: time.Hour})
+	janitor.Leader = &toggleLeader{results: []bool{true, false}}
 	if err := janitor.pulse(); err != nil {
 		t.Fatal(err)
 	}
@@ -1,15 +1,12 @@
 package logic
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"strings"
-	"text/template"
 	"time"
 
 	"github.com/quintilesims/layer0/api/backend/ecs/id"
-	"github.com/quintilesims/layer0/common/config"
 	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/types"
@@ -17,22 +14,31 @@ import (
 
 type JobLogic interface {
 	ListJobs() ([]*models.Job, error)
+	ListArchivedJobs() ([]*models.Job, error)
+	ListJobsByStatus(types.JobStatus) ([]*models.Job, error)
+	ListJobsByType(types.JobType) ([]*models.Job, error)
+	ListJobsByEntity(entityType, entityID string) ([]*models.Job, error)
 	GetJob(string) (*models.Job, error)
+	GetJobLogs(string, string, string, int) ([]*models.LogFile, error)
 	CreateJob(types.JobType, interface{}) (*models.Job, error)
+	CreateChildJob(parentJobID string, jobType types.JobType, request interface{}) (*models.Job, error)
+	CreateWorkflow(models.CreateWorkflowRequest) (*models.Job, error)
+	GetWorkflow(string) (*models.Workflow, error)
+	CancelJob(string) (*models.Job, error)
+	RetryJob(string) (*models.Job, error)
 	Delete(string) error
 }
 
+// L0JobLogic manages jobs. The job runners are started and stopped by Logic.JobExecutor
 type L0JobLogic struct {
 	Logic
-	TaskLogic   TaskLogic
-	DeployLogic DeployLogic
+	TaskLogic TaskLogic
 }
 
-func NewL0JobLogic(logic Logic, taskLogic TaskLogic, deployLogic DeployLogic) *L0JobLogic {
+func NewL0JobLogic(logic Logic, taskLogic TaskLogic) *L0JobLogic {
 	return &L0JobLogic{
-		Logic:       logic,
-		TaskLogic:   taskLogic,
-		DeployLogic: deployLogic,
+		Logic:     logic,
+		TaskLogic: taskLogic,
 	}
 }
 
@@ -45,6 +51,23 @@ func (this *L0JobLogic) ListJobs() ([]*models.Job, error) {
 	return jobs, nil
 }
 
+func (this *L0JobLogic) ListArchivedJobs() ([]*models.Job, error) {
+	return this.JobArchive.List()
+}
+
+func (this *L0JobLogic) ListJobsByStatus(status types.JobStatus) ([]*models.Job, error) {
+	return this.JobStore.SelectByStatus(status)
+}
+
+func (this *L0JobLogic) ListJobsByType(jobType types.JobType) ([]*models.Job, error) {
+	return this.JobStore.SelectByType(jobType)
+}
+
+// ListJobsByEntity returns the jobs that targeted the specified entity
+func (this *L0JobLogic) ListJobsByEntity(entityType, entityID string) ([]*models.Job, error) {
+	return this.JobStore.SelectByEntity(entityType, entityID)
+}
+
 func (this *L0JobLogic) GetJob(jobID string) (*models.Job, error) {
 	job, err := this.JobStore.SelectByID(jobID)
 	if err != nil {
@@ -54,16 +77,29 @@ func (this *L0JobLogic) GetJob(jobID string) (*models.Job, error) {
 	return job, nil
 }
 
+// GetJobLogs returns the logs of the job's runner task.
+// Jobs that ran in-process have no task, and their logs are part of the api's logs
+func (this *L0JobLogic) GetJobLogs(jobID, start, end string, tail int) ([]*models.LogFile, error) {
+	job, err := this.GetJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.TaskID == "" {
+		return []*models.LogFile{}, nil
+	}
+
+	return this.TaskLogic.GetTaskLogs(job.TaskID, start, end, tail)
+}
+
 func (this *L0JobLogic) Delete(jobID string) error {
 	job, err := this.GetJob(jobID)
 	if err != nil {
 		return err
 	}
 
-	if err := this.TaskLogic.DeleteTask(job.TaskID); err != nil {
-		if err, ok := err.(*errors.ServerError); ok && err.Code != errors.InvalidTaskID {
-			return err
-		}
+	if err := this.stop(job); err != nil {
+		return err
 	}
 
 	if err := this.JobStore.Delete(jobID); err != nil {
@@ -77,167 +113,290 @@ func (this *L0JobLogic) Delete(jobID string) error {
 	return nil
 }
 
+// CancelJob marks the job as cancelled and stops its runner.
+// The runner closes the quit channel of its in-flight step when it is stopped
+func (this *L0JobLogic) CancelJob(jobID string) (*models.Job, error) {
+	job, err := this.GetJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch status := types.JobStatus(job.JobStatus); status {
+	case types.Completed, types.Error, types.Cancelled:
+		return nil, errors.Newf(errors.JobNotCancellable, "Job '%s' cannot be cancelled: status is '%s'", jobID, status.String())
+	}
+
+	// mark the job before stopping the runner so the runner doesn't report the stop as an error
+	if err := this.JobStore.UpdateJobStatus(jobID, types.Cancelled); err != nil {
+		return nil, err
+	}
+
+	if err := this.stop(job); err != nil {
+		return nil, err
+	}
+
+	job.JobStatus = int64(types.Cancelled)
+	return job, nil
+}
+
+// RetryJob re-launches the runner for a failed or cancelled job.
+// The runner skips the steps that were completed by the previous run
+func (this *L0JobLogic) RetryJob(jobID string) (*models.Job, error) {
+	job, err := this.GetJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.ParentJobID != "" {
+		return nil, errors.Newf(errors.JobNotRetryable, "Job '%s' is part of workflow '%s': retry the workflow instead", jobID, job.ParentJobID)
+	}
+
+	switch status := types.JobStatus(job.JobStatus); status {
+	case types.Error, types.Cancelled:
+	default:
+		return nil, errors.Newf(errors.JobNotRetryable, "Job '%s' cannot be retried: status is '%s'", jobID, status.String())
+	}
+
+	if err := this.JobStore.UpdateJobStatus(jobID, types.Pending); err != nil {
+		return nil, err
+	}
+
+	taskID, err := this.execute(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	job.TaskID = taskID
+	job.JobStatus = int64(types.Pending)
+	return job, nil
+}
+
 func (this *L0JobLogic) CreateJob(jobType types.JobType, request interface{}) (*models.Job, error) {
-	bytes, err := json.Marshal(request)
+	job, err := this.insertJob(jobType, request, "")
 	if err != nil {
 		return nil, err
 	}
 
-	// remove extra "" added by json marshalling
-	reqStr := string(bytes)
-	reqStr = strings.TrimPrefix(reqStr, "\"")
-	reqStr = strings.TrimSuffix(reqStr, "\"")
+	taskID, err := this.execute(job.JobID)
+	if err != nil {
+		if err := this.JobStore.Delete(job.JobID); err != nil {
+			return nil, err
+		}
 
-	jobID := id.GenerateHashedEntityID(string(jobType))
+		return nil, err
+	}
+
+	job.TaskID = taskID
+
+	if err := this.scheduleScalerRun(jobType, request); err != nil {
+		return nil, err
+	}
 
-	deploy, err := this.createJobDeploy(jobID)
+	return job, nil
+}
+
+// CreateChildJob stores a job that is part of the specified parent job.
+// Child jobs are not executed; the parent's runner runs them
+func (this *L0JobLogic) CreateChildJob(parentJobID string, jobType types.JobType, request interface{}) (*models.Job, error) {
+	job, err := this.insertJob(jobType, request, parentJobID)
 	if err != nil {
 		return nil, err
 	}
 
-	taskID, err := this.createJobTask(jobID, deploy.DeployID)
+	if err := this.scheduleScalerRun(jobType, request); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// CreateWorkflow validates the workflow before creating the job that runs it
+func (this *L0JobLogic) CreateWorkflow(req models.CreateWorkflowRequest) (*models.Job, error) {
+	if err := ValidateWorkflow(req); err != nil {
+		return nil, err
+	}
+
+	return this.CreateJob(types.WorkflowJob, req)
+}
+
+// GetWorkflow returns the status of each node in the workflow job.
+// The workflow's runner records the job it created for each node in the workflow job's meta
+func (this *L0JobLogic) GetWorkflow(jobID string) (*models.Workflow, error) {
+	job, err := this.GetJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if types.JobType(job.JobType) != types.WorkflowJob {
+		return nil, errors.Newf(errors.InvalidWorkflow, "Job '%s' is not a workflow", jobID)
+	}
+
+	var req models.CreateWorkflowRequest
+	if err := json.Unmarshal([]byte(job.Request), &req); err != nil {
+		return nil, err
+	}
+
+	policy, err := WorkflowFailurePolicy(req)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow := &models.Workflow{
+		JobID:     job.JobID,
+		JobStatus: job.JobStatus,
+		OnFailure: string(policy),
+		Nodes:     make([]models.WorkflowNodeStatus, len(req.Nodes)),
+	}
+
+	for i, node := range req.Nodes {
+		status := models.WorkflowNodeStatus{
+			Name:      node.Name,
+			JobType:   node.JobType,
+			DependsOn: node.DependsOn,
+		}
+
+		// nodes without a job haven't started yet, or were skipped once the workflow finished
+		switch childID := job.Meta[node.Name]; {
+		case childID != "":
+			child, err := this.GetJob(childID)
+			if err != nil {
+				return nil, err
+			}
+
+			status.JobID = child.JobID
+			status.Status = types.JobStatus(child.JobStatus).String()
+			for _, step := range child.Steps {
+				if step.Error != "" {
+					status.Error = step.Error
+				}
+			}
+		case types.JobStatus(job.JobStatus) == types.Pending, types.JobStatus(job.JobStatus) == types.InProgress:
+			status.Status = types.Pending.String()
+		default:
+			status.Status = "skipped"
+		}
+
+		workflow.Nodes[i] = status
+	}
+
+	return workflow, nil
+}
+
+// insertJob stores a new pending job for the request
+func (this *L0JobLogic) insertJob(jobType types.JobType, request interface{}, parentJobID string) (*models.Job, error) {
+	bytes, err := json.Marshal(request)
 	if err != nil {
 		return nil, err
 	}
 
+	// remove extra "" added by json marshalling
+	reqStr := string(bytes)
+	reqStr = strings.TrimPrefix(reqStr, "\"")
+	reqStr = strings.TrimSuffix(reqStr, "\"")
+
+	jobID := id.GenerateHashedEntityID(string(jobType))
+	entityType, entityID := jobEntity(jobType, request)
+
 	job := &models.Job{
 		JobID:       jobID,
-		TaskID:      taskID,
 		JobStatus:   int64(types.Pending),
 		JobType:     int64(jobType),
 		Request:     reqStr,
 		TimeCreated: time.Now(),
+		EntityType:  entityType,
+		EntityID:    entityID,
+		ParentJobID: parentJobID,
 	}
 
+	// the job is stored before it is executed so in-process runners can load it immediately
 	if err := this.JobStore.Insert(job); err != nil {
 		return nil, err
 	}
 
-	if err := this.TagStore.Insert(models.Tag{EntityID: jobID, EntityType: "job", Key: "task_id", Value: taskID}); err != nil {
-		return nil, err
-	}
+	return job, nil
+}
 
-	if jobType == types.CreateTaskJob {
-		req, ok := request.(models.CreateTaskRequest)
-		if !ok {
-			return nil, fmt.Errorf("Unexpected request type for 'CreateTask' job type!")
-		}
+// scheduleScalerRun makes room for the tasks created by CreateTask jobs
+func (this *L0JobLogic) scheduleScalerRun(jobType types.JobType, request interface{}) error {
+	if jobType != types.CreateTaskJob {
+		return nil
+	}
 
-		this.Logic.Scaler.ScheduleRun(req.EnvironmentID, time.Second*10)
+	req, ok := request.(models.CreateTaskRequest)
+	if !ok {
+		return fmt.Errorf("Unexpected request type for 'CreateTask' job type!")
 	}
 
-	return job, nil
+	this.Logic.Scaler.ScheduleRun(req.EnvironmentID, time.Second*10)
+	return nil
 }
 
-func (this *L0JobLogic) createJobTask(jobID, deployID string) (string, error) {
-	taskRequest := models.CreateTaskRequest{
-		DeployID:      deployID,
-		EnvironmentID: config.API_ENVIRONMENT_ID,
-		TaskName:      jobID,
+// stop stops the job's runner. Child jobs are run by their parent's runner,
+// which cancels them once they are marked Cancelled
+func (this *L0JobLogic) stop(job *models.Job) error {
+	if job.ParentJobID != "" {
+		return nil
 	}
 
-	taskID, err := this.TaskLogic.CreateTask(taskRequest)
+	return this.JobExecutor.Stop(job)
+}
+
+// execute starts the job's runner and records the task it runs in, if any
+func (this *L0JobLogic) execute(jobID string) (string, error) {
+	taskID, err := this.JobExecutor.Execute(jobID)
 	if err != nil {
 		return "", err
 	}
 
-	return taskID, nil
-}
+	if taskID == "" {
+		return "", nil
+	}
 
-func (this *L0JobLogic) createJobDeploy(jobID string) (*models.Deploy, error) {
-	tmpl, err := template.New("").Parse(jobDockerrun)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to parse template: %v", err)
-	}
-
-	context := struct {
-		RunnerVersionTag string
-		Variables        []struct{ Key, Val string }
-	}{
-		RunnerVersionTag: config.RunnerVersionTag(),
-		Variables: []struct{ Key, Val string }{
-			{
-				Key: config.JOB_ID,
-				Val: jobID,
-			},
-			{
-				Key: config.AWS_DYNAMO_TAG_TABLE,
-				Val: config.DynamoTagTableName(),
-			},
-			{
-				Key: config.AWS_DYNAMO_JOB_TABLE,
-				Val: config.DynamoJobTableName(),
-			},
-			{
-				Key: config.AWS_ACCESS_KEY_ID,
-				Val: config.AWSAccessKey(),
-			},
-			{
-				Key: config.AWS_SECRET_ACCESS_KEY,
-				Val: config.AWSSecretKey(),
-			},
-			{
-				Key: config.PREFIX,
-				Val: config.Prefix(),
-			},
-			{
-				Key: config.AWS_REGION,
-				Val: config.AWSRegion(),
-			},
-			{
-				Key: config.AWS_VPC_ID,
-				Val: config.AWSVPCID(),
-			},
-			{
-				Key: config.AWS_PUBLIC_SUBNETS,
-				Val: config.AWSPublicSubnets(),
-			},
-			{
-				Key: config.AWS_PRIVATE_SUBNETS,
-				Val: config.AWSPrivateSubnets(),
-			},
-			{
-				Key: config.RUNNER_LOG_LEVEL,
-				Val: config.RunnerLogLevel(),
-			},
-		},
-	}
-
-	var dockerrun bytes.Buffer
-	if err := tmpl.Execute(&dockerrun, context); err != nil {
-		return nil, fmt.Errorf("Failed to write template: %v", err)
-	}
-
-	deployRequest := models.CreateDeployRequest{
-		DeployName: "job",
-		Dockerrun:  dockerrun.Bytes(),
-	}
-
-	deploy, err := this.DeployLogic.CreateDeploy(deployRequest)
-	if err != nil {
-		return nil, err
+	if err := this.JobStore.SetJobTaskID(jobID, taskID); err != nil {
+		return "", err
+	}
+
+	if err := this.TagStore.Delete("job", jobID, "task_id"); err != nil {
+		return "", err
 	}
 
-	return deploy, nil
+	if err := this.TagStore.Insert(models.Tag{EntityID: jobID, EntityType: "job", Key: "task_id", Value: taskID}); err != nil {
+		return "", err
+	}
+
+	return taskID, nil
 }
 
-var jobDockerrun string = `
-{
-    "AWSEBDockerrunVersion": 2,
-    "containerDefinitions": [
-        {
-            "name": "l0-job",
-            "image": "quintilesims/l0-runner:{{ .RunnerVersionTag }}",
-            "essential": true,
-            "memory": 64,
-            "environment": [
-		{{ range $i, $v := .Variables }}{{ if $i }}, {{ end }}
-                {
-                    "name":  "{{ .Key }}",
-                    "value": "{{ .Val }}"
-                }{{ end }}
-            ]
-        }
-    ]
+// jobEntity returns the entity targeted by a job with the specified request.
+// Jobs that create tasks target the environment the tasks run in.
+// Jobs that create environments, load balancers or services only know the entity's id once it exists;
+// their runners record it with JobStore.SetJobEntity
+func jobEntity(jobType types.JobType, request interface{}) (string, string) {
+	switch req := request.(type) {
+	case string:
+		switch jobType {
+		case types.DeleteEnvironmentJob:
+			return "environment", req
+		case types.DeleteServiceJob:
+			return "service", req
+		case types.DeleteLoadBalancerJob:
+			return "load_balancer", req
+		case types.DeleteTaskJob:
+			return "task", req
+		}
+	case models.CreateTaskRequest:
+		return "environment", req.EnvironmentID
+	case models.CreateEnvironmentRequest:
+		return "environment", ""
+	case models.CreateLoadBalancerRequest:
+		return "load_balancer", ""
+	case models.CreateServiceRequest:
+		return "service", ""
+	case models.UpdateServiceJobRequest:
+		return "service", req.ServiceID
+	case models.ScaleServiceJobRequest:
+		return "service", req.ServiceID
+	}
+
+	return "", ""
 }
-`
@@ -6,6 +6,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/quintilesims/layer0/api/backend/ecs/id"
 	"github.com/quintilesims/layer0/api/logic/mock_logic"
+	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/testutils"
 	"github.com/quintilesims/layer0/common/types"
@@ -19,7 +20,7 @@ func TestJobSelectByID(t *testing.T) {
 		{JobID: "j1"},
 	})
 
-	jobLogic := NewL0JobLogic(testLogic.Logic(), nil, nil)
+	jobLogic := NewL0JobLogic(testLogic.Logic(), nil)
 	job, err := jobLogic.GetJob("j1")
 	if err != nil {
 		t.Fatal(err)
@@ -37,7 +38,7 @@ func TestJobSelectAll(t *testing.T) {
 		{JobID: "j2"},
 	})
 
-	jobLogic := NewL0JobLogic(testLogic.Logic(), nil, nil)
+	jobLogic := NewL0JobLogic(testLogic.Logic(), nil)
 	jobs, err := jobLogic.ListJobs()
 	if err != nil {
 		t.Fatal(err)
@@ -48,10 +49,55 @@ func TestJobSelectAll(t *testing.T) {
 	testutils.AssertEqual(t, jobs[1].JobID, "j2")
 }
 
+func TestListJobsByEntity(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	testLogic.AddJobs(t, []*models.Job{
+		{JobID: "j1", EntityType: "service", EntityID: "s1"},
+		{JobID: "j2", EntityType: "service", EntityID: "s2"},
+		{JobID: "j3", EntityType: "environment", EntityID: "s1"},
+		{JobID: "j4", EntityType: "service", EntityID: "s1"},
+	})
+
+	jobLogic := NewL0JobLogic(testLogic.Logic(), nil)
+	jobs, err := jobLogic.ListJobsByEntity("service", "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(jobs), 2)
+	testutils.AssertEqual(t, jobs[0].JobID, "j1")
+	testutils.AssertEqual(t, jobs[1].JobID, "j4")
+}
+
+func TestJobEntity(t *testing.T) {
+	cases := []struct {
+		JobType    types.JobType
+		Request    interface{}
+		EntityType string
+		EntityID   string
+	}{
+		{types.DeleteServiceJob, "s1", "service", "s1"},
+		{types.DeleteLoadBalancerJob, "l1", "load_balancer", "l1"},
+		{types.DeleteTaskJob, "t1", "task", "t1"},
+		{types.CreateTaskJob, models.CreateTaskRequest{EnvironmentID: "e1"}, "environment", "e1"},
+		{types.CreateEnvironmentJob, models.CreateEnvironmentRequest{}, "environment", ""},
+		{types.UpdateServiceJob, models.UpdateServiceJobRequest{ServiceID: "s1"}, "service", "s1"},
+		{types.ScaleServiceJob, models.ScaleServiceJobRequest{ServiceID: "s1"}, "service", "s1"},
+	}
+
+	for _, c := range cases {
+		entityType, entityID := jobEntity(c.JobType, c.Request)
+		testutils.AssertEqual(t, entityType, c.EntityType)
+		testutils.AssertEqual(t, entityID, c.EntityID)
+	}
+}
+
 func TestJobDelete(t *testing.T) {
 	testLogic, ctrl := NewTestLogic(t)
-	taskLogic := mock_logic.NewMockTaskLogic(ctrl)
-	deployLogic := mock_logic.NewMockDeployLogic(ctrl)
+	jobExecutor := mock_logic.NewMockJobExecutor(ctrl)
+	testLogic.JobExecutor = jobExecutor
 	defer ctrl.Finish()
 
 	testLogic.AddJobs(t, []*models.Job{
@@ -64,11 +110,11 @@ func TestJobDelete(t *testing.T) {
 		{EntityID: "extra", EntityType: "job", Key: "name", Value: "extra"},
 	})
 
-	taskLogic.EXPECT().
-		DeleteTask("t1").
+	jobExecutor.EXPECT().
+		Stop(&models.Job{JobID: "j1", TaskID: "t1"}).
 		Return(nil)
 
-	jobLogic := NewL0JobLogic(testLogic.Logic(), taskLogic, deployLogic)
+	jobLogic := NewL0JobLogic(testLogic.Logic(), nil)
 	if err := jobLogic.Delete("j1"); err != nil {
 		t.Fatal(err)
 	}
@@ -90,34 +136,291 @@ func TestJobDelete(t *testing.T) {
 	testutils.AssertEqual(t, len(jobs), 1)
 }
 
+func TestGetJobLogs(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	taskLogic := mock_logic.NewMockTaskLogic(ctrl)
+	defer ctrl.Finish()
+
+	testLogic.AddJobs(t, []*models.Job{
+		{JobID: "j1", TaskID: "t1"},
+	})
+
+	logs := []*models.LogFile{{Name: "l0-job", Lines: []string{"line"}}}
+	taskLogic.EXPECT().
+		GetTaskLogs("t1", "start", "end", 10).
+		Return(logs, nil)
+
+	jobLogic := NewL0JobLogic(testLogic.Logic(), taskLogic)
+	result, err := jobLogic.GetJobLogs("j1", "start", "end", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, result, logs)
+}
+
+func TestCancelJob(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	jobExecutor := mock_logic.NewMockJobExecutor(ctrl)
+	testLogic.JobExecutor = jobExecutor
+	defer ctrl.Finish()
+
+	testLogic.AddJobs(t, []*models.Job{
+		{JobID: "j1", TaskID: "t1", JobStatus: int64(types.InProgress)},
+	})
+
+	jobExecutor.EXPECT().
+		Stop(gomock.Any()).
+		Return(nil)
+
+	jobLogic := NewL0JobLogic(testLogic.Logic(), nil)
+	job, err := jobLogic.CancelJob("j1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, job.JobStatus, int64(types.Cancelled))
+
+	stored, err := testLogic.JobStore.SelectByID("j1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, stored.JobStatus, int64(types.Cancelled))
+}
+
+func TestCancelJobFinished(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	testLogic.JobExecutor = mock_logic.NewMockJobExecutor(ctrl)
+	defer ctrl.Finish()
+
+	testLogic.AddJobs(t, []*models.Job{
+		{JobID: "j1", TaskID: "t1", JobStatus: int64(types.Completed)},
+	})
+
+	jobLogic := NewL0JobLogic(testLogic.Logic(), nil)
+	_, err := jobLogic.CancelJob("j1")
+	if err, ok := err.(*errors.ServerError); !ok || err.Code != errors.JobNotCancellable {
+		t.Fatalf("Expected JobNotCancellable error, got %v", err)
+	}
+}
+
+func TestRetryJob(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	jobExecutor := mock_logic.NewMockJobExecutor(ctrl)
+	testLogic.JobExecutor = jobExecutor
+	defer ctrl.Finish()
+
+	testLogic.AddJobs(t, []*models.Job{
+		{JobID: "j1", TaskID: "t1", JobStatus: int64(types.Error)},
+	})
+
+	testLogic.AddTags(t, []*models.Tag{
+		{EntityID: "j1", EntityType: "job", Key: "task_id", Value: "t1"},
+	})
+
+	jobExecutor.EXPECT().
+		Execute("j1").
+		Return("t2", nil)
+
+	jobLogic := NewL0JobLogic(testLogic.Logic(), nil)
+	job, err := jobLogic.RetryJob("j1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, job.TaskID, "t2")
+	testutils.AssertEqual(t, job.JobStatus, int64(types.Pending))
+	testLogic.AssertTagExists(t, models.Tag{EntityID: "j1", EntityType: "job", Key: "task_id", Value: "t2"})
+
+	stored, err := testLogic.JobStore.SelectByID("j1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, stored.TaskID, "t2")
+	testutils.AssertEqual(t, stored.JobStatus, int64(types.Pending))
+}
+
+func TestRetryJobNotFailed(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	testLogic.AddJobs(t, []*models.Job{
+		{JobID: "j1", TaskID: "t1", JobStatus: int64(types.InProgress)},
+	})
+
+	jobLogic := NewL0JobLogic(testLogic.Logic(), nil)
+	_, err := jobLogic.RetryJob("j1")
+	if err, ok := err.(*errors.ServerError); !ok || err.Code != errors.JobNotRetryable {
+		t.Fatalf("Expected JobNotRetryable error, got %v", err)
+	}
+}
+
 func TestCreateJob(t *testing.T) {
 	tmp := id.GenerateHashedEntityID
 	id.GenerateHashedEntityID = func(name string) string { return "j1" }
 	defer func() { id.GenerateHashedEntityID = tmp }()
 
 	testLogic, ctrl := NewTestLogic(t)
-	taskLogic := mock_logic.NewMockTaskLogic(ctrl)
-	deployLogic := mock_logic.NewMockDeployLogic(ctrl)
+	jobExecutor := mock_logic.NewMockJobExecutor(ctrl)
+	testLogic.JobExecutor = jobExecutor
 	defer ctrl.Finish()
 
-	deployLogic.EXPECT().
-		CreateDeploy(gomock.Any()).
-		Return(&models.Deploy{DeployID: "d1"}, nil)
-
-	taskLogic.EXPECT().
-		CreateTask(gomock.Any()).
+	jobExecutor.EXPECT().
+		Execute("j1").
 		Return("t1", nil)
 
-	jobLogic := NewL0JobLogic(testLogic.Logic(), taskLogic, deployLogic)
+	jobLogic := NewL0JobLogic(testLogic.Logic(), nil)
 	job, err := jobLogic.CreateJob(types.DeleteEnvironmentJob, "e1")
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	testutils.AssertEqual(t, job.TaskID, "t1")
+	testutils.AssertEqual(t, job.EntityType, "environment")
+	testutils.AssertEqual(t, job.EntityID, "e1")
 	testLogic.AssertTagExists(t, models.Tag{EntityID: "j1", EntityType: "job", Key: "task_id", Value: "t1"})
 
 	if _, err := testLogic.JobStore.SelectByID("j1"); err != nil {
 		t.Fatal(err)
 	}
 }
+
+func TestCreateJobExecuteError(t *testing.T) {
+	tmp := id.GenerateHashedEntityID
+	id.GenerateHashedEntityID = func(name string) string { return "j1" }
+	defer func() { id.GenerateHashedEntityID = tmp }()
+
+	testLogic, ctrl := NewTestLogic(t)
+	jobExecutor := mock_logic.NewMockJobExecutor(ctrl)
+	testLogic.JobExecutor = jobExecutor
+	defer ctrl.Finish()
+
+	jobExecutor.EXPECT().
+		Execute("j1").
+		Return("", errors.Newf(errors.Throttled, "queue is full"))
+
+	jobLogic := NewL0JobLogic(testLogic.Logic(), nil)
+	if _, err := jobLogic.CreateJob(types.DeleteEnvironmentJob, "e1"); err == nil {
+		t.Fatalf("Error was nil!")
+	}
+
+	// make sure the job was removed
+	jobs, err := testLogic.JobStore.SelectAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(jobs), 0)
+}
+
+func TestCreateChildJob(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	// child jobs are not executed, so there are no JobExecutor calls
+	jobLogic := NewL0JobLogic(testLogic.Logic(), nil)
+	job, err := jobLogic.CreateChildJob("w1", types.DeleteServiceJob, "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := testLogic.JobStore.SelectByID(job.JobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, stored.ParentJobID, "w1")
+	testutils.AssertEqual(t, stored.EntityID, "s1")
+	testutils.AssertEqual(t, stored.JobStatus, int64(types.Pending))
+}
+
+func TestCancelChildJob(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	testLogic.JobExecutor = mock_logic.NewMockJobExecutor(ctrl)
+	defer ctrl.Finish()
+
+	testLogic.AddJobs(t, []*models.Job{
+		{JobID: "j1", ParentJobID: "w1", JobStatus: int64(types.InProgress)},
+	})
+
+	// the parent's runner stops the child, so the executor is not called
+	jobLogic := NewL0JobLogic(testLogic.Logic(), nil)
+	if _, err := jobLogic.CancelJob("j1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := jobLogic.RetryJob("j1"); err == nil {
+		t.Fatalf("Error was nil!")
+	}
+}
+
+func TestCreateWorkflowInvalid(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	jobLogic := NewL0JobLogic(testLogic.Logic(), nil)
+	_, err := jobLogic.CreateWorkflow(models.CreateWorkflowRequest{})
+	if err, ok := err.(*errors.ServerError); !ok || err.Code != errors.InvalidWorkflow {
+		t.Fatalf("Expected InvalidWorkflow error, got %v", err)
+	}
+}
+
+func TestGetWorkflow(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	request := `{"on_failure":"continue","nodes":[` +
+		`{"name":"a","job_type":"delete service","request":"s1"},` +
+		`{"name":"b","job_type":"delete service","request":"s2"},` +
+		`{"name":"c","job_type":"delete environment","request":"e1","depends_on":["a","b"]}]}`
+
+	testLogic.AddJobs(t, []*models.Job{
+		{
+			JobID:     "w1",
+			JobType:   int64(types.WorkflowJob),
+			JobStatus: int64(types.Error),
+			Request:   request,
+			Meta:      map[string]string{"a": "j1", "b": "j2"},
+		},
+		{JobID: "j1", ParentJobID: "w1", JobStatus: int64(types.Completed)},
+		{
+			JobID:       "j2",
+			ParentJobID: "w1",
+			JobStatus:   int64(types.Error),
+			Steps:       []models.JobStep{{Name: "Delete Service", Error: "some error"}},
+		},
+	})
+
+	jobLogic := NewL0JobLogic(testLogic.Logic(), nil)
+	workflow, err := jobLogic.GetWorkflow("w1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, workflow.OnFailure, "continue")
+	testutils.AssertEqual(t, len(workflow.Nodes), 3)
+	testutils.AssertEqual(t, workflow.Nodes[0].JobID, "j1")
+	testutils.AssertEqual(t, workflow.Nodes[0].Status, "completed")
+	testutils.AssertEqual(t, workflow.Nodes[1].Status, "error")
+	testutils.AssertEqual(t, workflow.Nodes[1].Error, "some error")
+	testutils.AssertEqual(t, workflow.Nodes[2].JobID, "")
+	testutils.AssertEqual(t, workflow.Nodes[2].Status, "skipped")
+	testutils.AssertEqual(t, workflow.Nodes[2].DependsOn, []string{"a", "b"})
+}
+
+func TestGetWorkflowNotWorkflow(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	testLogic.AddJobs(t, []*models.Job{
+		{JobID: "j1", JobType: int64(types.DeleteServiceJob)},
+	})
+
+	jobLogic := NewL0JobLogic(testLogic.Logic(), nil)
+	_, err := jobLogic.GetWorkflow("j1")
+	if err, ok := err.(*errors.ServerError); !ok || err.Code != errors.InvalidWorkflow {
+		t.Fatalf("Expected InvalidWorkflow error, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package logic
+
+import (
+	"sync"
+	"time"
+
+	"github.com/quintilesims/layer0/common/db/lease_store"
+	"github.com/quintilesims/layer0/common/logutils"
+	"github.com/quintilesims/layer0/common/waitutils"
+)
+
+const (
+	LEADER_LEASE_NAME     = "api_leader"
+	LEADER_LEASE_DURATION = time.Second * 30
+	LEADER_RENEW_INTERVAL = time.Second * 10
+)
+
+var leaderLogger = logutils.NewStandardLogger("Leader Elector")
+
+// Leader reports whether this replica of the api should run the singleton background workers:
+// the job janitor, the tag janitor and the environment scaler loop
+type Leader interface {
+	IsLeader() bool
+}
+
+// AlwaysLeader is the Leader for an api that runs as a single replica
+type AlwaysLeader struct{}
+
+func (AlwaysLeader) IsLeader() bool {
+	return true
+}
+
+// LeaderElector makes this replica the leader while it holds a lease in the lease store.
+// Leadership lapses if the lease can't be renewed before it expires,
+// so at most one replica considers itself the leader at a time
+type LeaderElector struct {
+	Store   lease_store.LeaseStore
+	Name    string
+	Holder  string
+	Clock   waitutils.Clock
+	expires time.Time
+	mutex   sync.Mutex
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func NewLeaderElector(store lease_store.LeaseStore, holder string) *LeaderElector {
+	return &LeaderElector{
+		Store:  store,
+		Name:   LEADER_LEASE_NAME,
+		Holder: holder,
+		Clock:  waitutils.RealClock{},
+		stop:   make(chan struct{}),
+	}
+}
+
+// Run makes the first attempt to take the lease before it returns, so workers started afterwards
+// don't wait a renew interval to learn that this replica is the leader.
+// It then renews the lease in the background until Resign is called
+func (l *LeaderElector) Run() {
+	l.done = make(chan struct{})
+	l.renew(false)
+
+	go func() {
+		defer close(l.done)
+
+		for {
+			select {
+			case <-l.stop:
+				return
+			case <-time.After(LEADER_RENEW_INTERVAL):
+			}
+
+			l.renew(l.IsLeader())
+		}
+	}()
+}
+
+func (l *LeaderElector) renew(wasLeader bool) {
+	if err := l.Elect(); err != nil {
+		leaderLogger.Errorf("Failed to renew lease '%s': %v", l.Name, err)
+	}
+
+	if isLeader := l.IsLeader(); isLeader != wasLeader {
+		leaderLogger.Infof("Holder '%s' is leader: %t", l.Holder, isLeader)
+	}
+}
+
+// Elect makes a single attempt to take or renew the lease
+func (l *LeaderElector) Elect() error {
+	// the local expiry is measured from before the request,
+	// so it never ends after the expiry in the lease store
+	now := l.Clock.Now()
+	acquired, err := l.Store.Acquire(l.Name, l.Holder, LEADER_LEASE_DURATION)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	switch {
+	case err != nil:
+		// keep the current expiry; leadership lapses if the lease isn't renewed in time
+		return err
+	case !acquired:
+		l.expires = time.Time{}
+	default:
+		l.expires = now.Add(LEADER_LEASE_DURATION)
+	}
+
+	return nil
+}
+
+func (l *LeaderElector) IsLeader() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.Clock.Now().Before(l.expires)
+}
+
+// Resign stops renewing the lease and releases it, so another replica can take over right away.
+// It should be called once, when the api shuts down
+func (l *LeaderElector) Resign() error {
+	close(l.stop)
+	if l.done != nil {
+		<-l.done
+	}
+
+	l.mutex.Lock()
+	l.expires = time.Time{}
+	l.mutex.Unlock()
+
+	return l.Store.Release(l.Name, l.Holder)
+}
+
+// RunAsLeader calls work every interval while this replica is the leader. Leadership is checked
+// every LEADER_RENEW_INTERVAL, so a replica that takes over starts working within one renewal
+// instead of a full interval. RunAsLeader never returns
+func RunAsLeader(leader Leader, clock waitutils.Clock, interval time.Duration, work func()) {
+	for {
+		if !leader.IsLeader() {
+			clock.Sleep(LEADER_RENEW_INTERVAL)
+			continue
+		}
+
+		work()
+		clock.Sleep(interval)
+	}
+}
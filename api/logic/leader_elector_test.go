@@ -0,0 +1,157 @@
+package logic
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/quintilesims/layer0/common/db/lease_store"
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+func newTestLeaderElectors(holders ...string) ([]*LeaderElector, *testutils.StubClock) {
+	clock := &testutils.StubClock{}
+	store := lease_store.NewMemoryLeaseStore()
+	store.Clock = clock
+
+	electors := make([]*LeaderElector, len(holders))
+	for i, holder := range holders {
+		electors[i] = NewLeaderElector(store, holder)
+		electors[i].Clock = clock
+	}
+
+	return electors, clock
+}
+
+func elect(t *testing.T, electors ...*LeaderElector) {
+	for _, elector := range electors {
+		if err := elector.Elect(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLeaderElectorElect(t *testing.T) {
+	electors, clock := newTestLeaderElectors("h1", "h2")
+	elect(t, electors...)
+
+	testutils.AssertEqual(t, electors[0].IsLeader(), true)
+	testutils.AssertEqual(t, electors[1].IsLeader(), false)
+
+	// the leader keeps its lease while it renews it
+	clock.Sleep(LEADER_RENEW_INTERVAL)
+	elect(t, electors...)
+
+	testutils.AssertEqual(t, electors[0].IsLeader(), true)
+	testutils.AssertEqual(t, electors[1].IsLeader(), false)
+}
+
+func TestLeaderElectorLeaseExpires(t *testing.T) {
+	electors, clock := newTestLeaderElectors("h1", "h2")
+	elect(t, electors...)
+
+	// the leader stops renewing its lease, e.g. because its replica is unhealthy
+	clock.Sleep(LEADER_LEASE_DURATION)
+	testutils.AssertEqual(t, electors[0].IsLeader(), false)
+
+	elect(t, electors[1])
+	testutils.AssertEqual(t, electors[1].IsLeader(), true)
+
+	elect(t, electors[0])
+	testutils.AssertEqual(t, electors[0].IsLeader(), false)
+}
+
+func TestLeaderElectorResign(t *testing.T) {
+	electors, _ := newTestLeaderElectors("h1", "h2")
+	elect(t, electors...)
+
+	if err := electors[0].Resign(); err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, electors[0].IsLeader(), false)
+
+	// the other replica takes over without waiting for the lease to expire
+	elect(t, electors[1])
+	testutils.AssertEqual(t, electors[1].IsLeader(), true)
+}
+
+type errLeaseStore struct {
+	lease_store.LeaseStore
+}
+
+func (errLeaseStore) Acquire(name, holder string, duration time.Duration) (bool, error) {
+	return false, fmt.Errorf("some error")
+}
+
+func TestLeaderElectorStoreError(t *testing.T) {
+	electors, clock := newTestLeaderElectors("h1")
+	elect(t, electors...)
+
+	// leadership lasts until the lease expires, even if it can't be renewed
+	electors[0].Store = errLeaseStore{}
+	if err := electors[0].Elect(); err == nil {
+		t.Fatal("Error was nil!")
+	}
+
+	testutils.AssertEqual(t, electors[0].IsLeader(), true)
+
+	clock.Sleep(LEADER_LEASE_DURATION)
+	testutils.AssertEqual(t, electors[0].IsLeader(), false)
+}
+
+// sleepRecorder records the durations slept and stops the calling goroutine after the last one
+type sleepRecorder struct {
+	testutils.StubClock
+	sleeps []time.Duration
+	max    int
+}
+
+func (s *sleepRecorder) Sleep(d time.Duration) {
+	s.sleeps = append(s.sleeps, d)
+	if len(s.sleeps) == s.max {
+		runtime.Goexit()
+	}
+}
+
+type toggleLeader struct {
+	results []bool
+}
+
+func (l *toggleLeader) IsLeader() bool {
+	isLeader := l.results[0]
+	l.results = l.results[1:]
+	return isLeader
+}
+
+func TestRunAsLeader(t *testing.T) {
+	leader := &toggleLeader{results: []bool{false, false, true, false}}
+	clock := &sleepRecorder{max: 4}
+
+	var runs int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		RunAsLeader(leader, clock, time.Hour, func() { runs++ })
+	}()
+
+	<-done
+
+	testutils.AssertEqual(t, runs, 1)
+	testutils.AssertEqual(t, clock.sleeps, []time.Duration{
+		LEADER_RENEW_INTERVAL,
+		LEADER_RENEW_INTERVAL,
+		time.Hour,
+		LEADER_RENEW_INTERVAL,
+	})
+}
+
+func TestLeaderElectorRunElectsBeforeReturning(t *testing.T) {
+	electors, _ := newTestLeaderElectors("h1")
+
+	electors[0].Run()
+	defer electors[0].Resign()
+
+	testutils.AssertEqual(t, electors[0].IsLeader(), true)
+}
@@ -12,10 +12,10 @@ type LoadBalancerLogic interface {
 	GetLoadBalancer(loadBalancerID string) (*models.LoadBalancer, error)
 	DeleteLoadBalancer(loadBalancerID string) error
 	CreateLoadBalancer(req models.CreateLoadBalancerRequest) (*models.LoadBalancer, error)
-	UpdateLoadBalancerPorts(loadBalancerID string, ports []models.Port) (*models.LoadBalancer, error)
-	UpdateLoadBalancerHealthCheck(loadBalancerID string, healthCheck models.HealthCheck) (*models.LoadBalancer, error)
-	UpdateLoadBalancerIdleTimeout(loadBalancerID string, idleTimeout int) (*models.LoadBalancer, error)
-	UpdateLoadBalancerCrossZone(loadBalancerID string, crossZone bool) (*models.LoadBalancer, error)
+	UpdateLoadBalancerPorts(loadBalancerID string, ports []models.Port, version int64) (*models.LoadBalancer, error)
+	UpdateLoadBalancerHealthCheck(loadBalancerID string, healthCheck models.HealthCheck, version int64) (*models.LoadBalancer, error)
+	UpdateLoadBalancerIdleTimeout(loadBalancerID string, idleTimeout int, version int64) (*models.LoadBalancer, error)
+	UpdateLoadBalancerCrossZone(loadBalancerID string, crossZone bool, version int64) (*models.LoadBalancer, error)
 }
 
 type L0LoadBalancerLogic struct {
@@ -126,9 +126,14 @@ func (l *L0LoadBalancerLogic) CreateLoadBalancer(req models.CreateLoadBalancerRe
 	return loadBalancer, nil
 }
 
-func (l *L0LoadBalancerLogic) UpdateLoadBalancerPorts(loadBalancerID string, ports []models.Port) (*models.LoadBalancer, error) {
-	loadBalancer, err := l.Backend.UpdateLoadBalancerPorts(loadBalancerID, ports)
-	if err != nil {
+func (l *L0LoadBalancerLogic) UpdateLoadBalancerPorts(loadBalancerID string, ports []models.Port, version int64) (*models.LoadBalancer, error) {
+	var loadBalancer *models.LoadBalancer
+	if err := l.updateVersioned("load_balancer", loadBalancerID, version, func(mutating func()) error {
+		var err error
+		mutating()
+		loadBalancer, err = l.Backend.UpdateLoadBalancerPorts(loadBalancerID, ports)
+		return err
+	}); err != nil {
 		return nil, err
 	}
 
@@ -139,9 +144,14 @@ func (l *L0LoadBalancerLogic) UpdateLoadBalancerPorts(loadBalancerID string, por
 	return loadBalancer, nil
 }
 
-func (l *L0LoadBalancerLogic) UpdateLoadBalancerHealthCheck(loadBalancerID string, healthCheck models.HealthCheck) (*models.LoadBalancer, error) {
-	loadBalancer, err := l.Backend.UpdateLoadBalancerHealthCheck(loadBalancerID, healthCheck)
-	if err != nil {
+func (l *L0LoadBalancerLogic) UpdateLoadBalancerHealthCheck(loadBalancerID string, healthCheck models.HealthCheck, version int64) (*models.LoadBalancer, error) {
+	var loadBalancer *models.LoadBalancer
+	if err := l.updateVersioned("load_balancer", loadBalancerID, version, func(mutating func()) error {
+		var err error
+		mutating()
+		loadBalancer, err = l.Backend.UpdateLoadBalancerHealthCheck(loadBalancerID, healthCheck)
+		return err
+	}); err != nil {
 		return nil, err
 	}
 
@@ -152,9 +162,14 @@ func (l *L0LoadBalancerLogic) UpdateLoadBalancerHealthCheck(loadBalancerID strin
 	return loadBalancer, nil
 }
 
-func (l *L0LoadBalancerLogic) UpdateLoadBalancerIdleTimeout(loadBalancerID string, idleTimeout int) (*models.LoadBalancer, error) {
-	loadBalancer, err := l.Backend.UpdateLoadBalancerIdleTimeout(loadBalancerID, idleTimeout)
-	if err != nil {
+func (l *L0LoadBalancerLogic) UpdateLoadBalancerIdleTimeout(loadBalancerID string, idleTimeout int, version int64) (*models.LoadBalancer, error) {
+	var loadBalancer *models.LoadBalancer
+	if err := l.updateVersioned("load_balancer", loadBalancerID, version, func(mutating func()) error {
+		var err error
+		mutating()
+		loadBalancer, err = l.Backend.UpdateLoadBalancerIdleTimeout(loadBalancerID, idleTimeout)
+		return err
+	}); err != nil {
 		return nil, err
 	}
 
@@ -165,9 +180,14 @@ func (l *L0LoadBalancerLogic) UpdateLoadBalancerIdleTimeout(loadBalancerID strin
 	return loadBalancer, nil
 }
 
-func (l *L0LoadBalancerLogic) UpdateLoadBalancerCrossZone(loadBalancerID string, crossZone bool) (*models.LoadBalancer, error) {
-	loadBalancer, err := l.Backend.UpdateLoadBalancerCrossZone(loadBalancerID, crossZone)
-	if err != nil {
+func (l *L0LoadBalancerLogic) UpdateLoadBalancerCrossZone(loadBalancerID string, crossZone bool, version int64) (*models.LoadBalancer, error) {
+	var loadBalancer *models.LoadBalancer
+	if err := l.updateVersioned("load_balancer", loadBalancerID, version, func(mutating func()) error {
+		var err error
+		mutating()
+		loadBalancer, err = l.Backend.UpdateLoadBalancerCrossZone(loadBalancerID, crossZone)
+		return err
+	}); err != nil {
 		return nil, err
 	}
 
@@ -199,6 +219,13 @@ func (l *L0LoadBalancerLogic) populateModel(model *models.LoadBalancer) error {
 		return err
 	}
 
+	version, err := l.TagStore.SelectVersion("load_balancer", model.LoadBalancerID)
+	if err != nil {
+		return err
+	}
+
+	model.Version = version
+
 	if tag, ok := tags.WithKey("environment_id").First(); ok {
 		model.EnvironmentID = tag.Value
 	}
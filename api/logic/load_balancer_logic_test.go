@@ -3,6 +3,7 @@ package logic
 import (
 	"testing"
 
+	"github.com/quintilesims/layer0/common/db/tag_store"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/testutils"
 )
@@ -33,6 +34,7 @@ func TestGetLoadBalancer(t *testing.T) {
 		LoadBalancerID:   "l1",
 		LoadBalancerName: "lb",
 		EnvironmentID:    "e1",
+		Version:          2,
 	}
 
 	testutils.AssertEqual(t, received, expected)
@@ -158,6 +160,7 @@ func TestCreateLoadBalancer(t *testing.T) {
 		Ports:            []models.Port{},
 		HealthCheck:      healthCheck,
 		IdleTimeout:      60,
+		Version:          2,
 	}
 
 	testutils.AssertEqual(t, received, expected)
@@ -228,7 +231,7 @@ func TestUpdateLoadBalancerPorts(t *testing.T) {
 	})
 
 	loadBalancerLogic := NewL0LoadBalancerLogic(testLogic.Logic())
-	received, err := loadBalancerLogic.UpdateLoadBalancerPorts("l1", []models.Port{})
+	received, err := loadBalancerLogic.UpdateLoadBalancerPorts("l1", []models.Port{}, tag_store.AnyVersion)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -238,6 +241,7 @@ func TestUpdateLoadBalancerPorts(t *testing.T) {
 		LoadBalancerName: "lb",
 		EnvironmentID:    "e1",
 		Ports:            []models.Port{},
+		Version:          3,
 	}
 
 	testutils.AssertEqual(t, received, expected)
@@ -260,7 +264,7 @@ func TestUpdateLoadBalancerHealthCheck(t *testing.T) {
 		Return(&models.LoadBalancer{HealthCheck: healthCheck}, nil)
 
 	loadBalancerLogic := NewL0LoadBalancerLogic(testLogic.Logic())
-	received, err := loadBalancerLogic.UpdateLoadBalancerHealthCheck("lb_id", healthCheck)
+	received, err := loadBalancerLogic.UpdateLoadBalancerHealthCheck("lb_id", healthCheck, tag_store.AnyVersion)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -279,7 +283,7 @@ func TestUpdateLoadBalancerIdleTimeout(t *testing.T) {
 		Return(&models.LoadBalancer{IdleTimeout: idleTimeout}, nil)
 
 	loadBalancerLogic := NewL0LoadBalancerLogic(testLogic.Logic())
-	received, err := loadBalancerLogic.UpdateLoadBalancerIdleTimeout("lb_id", idleTimeout)
+	received, err := loadBalancerLogic.UpdateLoadBalancerIdleTimeout("lb_id", idleTimeout, tag_store.AnyVersion)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -298,7 +302,7 @@ func TestUpdateLoadBalancerCrossZone(t *testing.T) {
 		Return(&models.LoadBalancer{CrossZone: crossZone}, nil)
 
 	loadBalancerLogic := NewL0LoadBalancerLogic(testLogic.Logic())
-	received, err := loadBalancerLogic.UpdateLoadBalancerCrossZone("lb_id", crossZone)
+	received, err := loadBalancerLogic.UpdateLoadBalancerCrossZone("lb_id", crossZone, tag_store.AnyVersion)
 	if err != nil {
 		t.Fatal(err)
 	}
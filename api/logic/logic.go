@@ -1,6 +1,8 @@
 package logic
 
 import (
+	"fmt"
+
 	"github.com/quintilesims/layer0/api/backend"
 	"github.com/quintilesims/layer0/api/scheduler"
 	"github.com/quintilesims/layer0/common/db/job_store"
@@ -8,10 +10,14 @@ import (
 )
 
 type Logic struct {
-	Backend  backend.Backend
-	TagStore tag_store.TagStore
-	JobStore job_store.JobStore
-	Scaler   scheduler.EnvironmentScaler
+	Backend            backend.Backend
+	TagStore           tag_store.TagStore
+	JobStore           job_store.JobStore
+	Scaler             scheduler.EnvironmentScaler
+	JobExecutor        JobExecutor
+	JobArchive         JobArchive
+	BackupStore        BackupStore
+	ConsistencyChecker ConsistencyChecker
 }
 
 func NewLogic(
@@ -42,3 +48,29 @@ func (this *Logic) deleteEntityTags(entityType, entityID string) error {
 
 	return nil
 }
+
+// updateVersioned increments the version of an entity, which fails if another request changed it since expected,
+// and then runs update. update calls mutating before it starts changing the entity. If update fails before that,
+// the increment is reverted so the client can retry with the same version. Once the entity may have changed,
+// the new version is kept so a client holding the old one has to re-read the entity before updating it again
+func (this *Logic) updateVersioned(entityType, entityID string, expected int64, update func(mutating func()) error) error {
+	version, err := this.TagStore.IncrementVersion(entityType, entityID, expected)
+	if err != nil {
+		return err
+	}
+
+	var mutated bool
+	if err := update(func() { mutated = true }); err != nil {
+		if mutated {
+			return err
+		}
+
+		if revertErr := this.TagStore.RevertVersion(entityType, entityID, version); revertErr != nil {
+			return fmt.Errorf("%v (failed to revert the version of %s '%s': %v)", err, entityType, entityID, revertErr)
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -24,10 +24,14 @@ func TestMain(m *testing.M) {
 }
 
 type TestLogic struct {
-	Backend  *mock_backend.MockBackend
-	JobStore *job_store.MemoryJobStore
-	TagStore *tag_store.MemoryTagStore
-	Scaler   *mock_scheduler.MockEnvironmentScaler
+	Backend            *mock_backend.MockBackend
+	JobStore           *job_store.MemoryJobStore
+	TagStore           *tag_store.MemoryTagStore
+	Scaler             *mock_scheduler.MockEnvironmentScaler
+	JobExecutor        JobExecutor
+	JobArchive         JobArchive
+	BackupStore        BackupStore
+	ConsistencyChecker ConsistencyChecker
 }
 
 func NewTestLogic(t *testing.T) (*TestLogic, *gomock.Controller) {
@@ -75,5 +79,10 @@ func (l *TestLogic) AssertTagExists(t *testing.T, tag models.Tag) {
 }
 
 func (l *TestLogic) Logic() Logic {
-	return *NewLogic(l.TagStore, l.JobStore, l.Backend, l.Scaler)
+	logic := NewLogic(l.TagStore, l.JobStore, l.Backend, l.Scaler)
+	logic.JobExecutor = l.JobExecutor
+	logic.JobArchive = l.JobArchive
+	logic.BackupStore = l.BackupStore
+	logic.ConsistencyChecker = l.ConsistencyChecker
+	return *logic
 }
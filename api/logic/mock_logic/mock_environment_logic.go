@@ -33,6 +33,18 @@ func (m *MockEnvironmentLogic) EXPECT() *MockEnvironmentLogicMockRecorder {
 	return m.recorder
 }
 
+// CanCloneEnvironment mocks base method
+func (m *MockEnvironmentLogic) CanCloneEnvironment(arg0 models.CloneEnvironmentRequest) error {
+	ret := m.ctrl.Call(m, "CanCloneEnvironment", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CanCloneEnvironment indicates an expected call of CanCloneEnvironment
+func (mr *MockEnvironmentLogicMockRecorder) CanCloneEnvironment(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanCloneEnvironment", reflect.TypeOf((*MockEnvironmentLogic)(nil).CanCloneEnvironment), arg0)
+}
+
 // CanCreateEnvironment mocks base method
 func (m *MockEnvironmentLogic) CanCreateEnvironment(arg0 models.CreateEnvironmentRequest) (bool, error) {
 	ret := m.ctrl.Call(m, "CanCreateEnvironment", arg0)
@@ -108,6 +120,45 @@ func (mr *MockEnvironmentLogicMockRecorder) GetEnvironment(arg0 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnvironment", reflect.TypeOf((*MockEnvironmentLogic)(nil).GetEnvironment), arg0)
 }
 
+// GetPlacementStrategy mocks base method
+func (m *MockEnvironmentLogic) GetPlacementStrategy(arg0 string) (string, error) {
+	ret := m.ctrl.Call(m, "GetPlacementStrategy", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlacementStrategy indicates an expected call of GetPlacementStrategy
+func (mr *MockEnvironmentLogicMockRecorder) GetPlacementStrategy(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlacementStrategy", reflect.TypeOf((*MockEnvironmentLogic)(nil).GetPlacementStrategy), arg0)
+}
+
+// GetScalerHistory mocks base method
+func (m *MockEnvironmentLogic) GetScalerHistory(arg0 string, arg1 int) ([]*models.ScalerRun, error) {
+	ret := m.ctrl.Call(m, "GetScalerHistory", arg0, arg1)
+	ret0, _ := ret[0].([]*models.ScalerRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScalerHistory indicates an expected call of GetScalerHistory
+func (mr *MockEnvironmentLogicMockRecorder) GetScalerHistory(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScalerHistory", reflect.TypeOf((*MockEnvironmentLogic)(nil).GetScalerHistory), arg0, arg1)
+}
+
+// GetScalerSettings mocks base method
+func (m *MockEnvironmentLogic) GetScalerSettings(arg0 string) (models.ScalerSettings, error) {
+	ret := m.ctrl.Call(m, "GetScalerSettings", arg0)
+	ret0, _ := ret[0].(models.ScalerSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScalerSettings indicates an expected call of GetScalerSettings
+func (mr *MockEnvironmentLogicMockRecorder) GetScalerSettings(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScalerSettings", reflect.TypeOf((*MockEnvironmentLogic)(nil).GetScalerSettings), arg0)
+}
+
 // ListEnvironments mocks base method
 func (m *MockEnvironmentLogic) ListEnvironments() ([]models.EnvironmentSummary, error) {
 	ret := m.ctrl.Call(m, "ListEnvironments")
@@ -122,14 +173,14 @@ func (mr *MockEnvironmentLogicMockRecorder) ListEnvironments() *gomock.Call {
 }
 
 // UpdateEnvironment mocks base method
-func (m *MockEnvironmentLogic) UpdateEnvironment(arg0 string, arg1 int) (*models.Environment, error) {
-	ret := m.ctrl.Call(m, "UpdateEnvironment", arg0, arg1)
+func (m *MockEnvironmentLogic) UpdateEnvironment(arg0 string, arg1 models.UpdateEnvironmentRequest, arg2 int64) (*models.Environment, error) {
+	ret := m.ctrl.Call(m, "UpdateEnvironment", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*models.Environment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateEnvironment indicates an expected call of UpdateEnvironment
-func (mr *MockEnvironmentLogicMockRecorder) UpdateEnvironment(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEnvironment", reflect.TypeOf((*MockEnvironmentLogic)(nil).UpdateEnvironment), arg0, arg1)
+func (mr *MockEnvironmentLogicMockRecorder) UpdateEnvironment(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEnvironment", reflect.TypeOf((*MockEnvironmentLogic)(nil).UpdateEnvironment), arg0, arg1, arg2)
 }
@@ -34,6 +34,32 @@ func (m *MockJobLogic) EXPECT() *MockJobLogicMockRecorder {
 	return m.recorder
 }
 
+// CancelJob mocks base method
+func (m *MockJobLogic) CancelJob(arg0 string) (*models.Job, error) {
+	ret := m.ctrl.Call(m, "CancelJob", arg0)
+	ret0, _ := ret[0].(*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelJob indicates an expected call of CancelJob
+func (mr *MockJobLogicMockRecorder) CancelJob(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelJob", reflect.TypeOf((*MockJobLogic)(nil).CancelJob), arg0)
+}
+
+// CreateChildJob mocks base method
+func (m *MockJobLogic) CreateChildJob(arg0 string, arg1 types.JobType, arg2 interface{}) (*models.Job, error) {
+	ret := m.ctrl.Call(m, "CreateChildJob", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChildJob indicates an expected call of CreateChildJob
+func (mr *MockJobLogicMockRecorder) CreateChildJob(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChildJob", reflect.TypeOf((*MockJobLogic)(nil).CreateChildJob), arg0, arg1, arg2)
+}
+
 // CreateJob mocks base method
 func (m *MockJobLogic) CreateJob(arg0 types.JobType, arg1 interface{}) (*models.Job, error) {
 	ret := m.ctrl.Call(m, "CreateJob", arg0, arg1)
@@ -47,6 +73,19 @@ func (mr *MockJobLogicMockRecorder) CreateJob(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJob", reflect.TypeOf((*MockJobLogic)(nil).CreateJob), arg0, arg1)
 }
 
+// CreateWorkflow mocks base method
+func (m *MockJobLogic) CreateWorkflow(arg0 models.CreateWorkflowRequest) (*models.Job, error) {
+	ret := m.ctrl.Call(m, "CreateWorkflow", arg0)
+	ret0, _ := ret[0].(*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkflow indicates an expected call of CreateWorkflow
+func (mr *MockJobLogicMockRecorder) CreateWorkflow(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkflow", reflect.TypeOf((*MockJobLogic)(nil).CreateWorkflow), arg0)
+}
+
 // Delete mocks base method
 func (m *MockJobLogic) Delete(arg0 string) error {
 	ret := m.ctrl.Call(m, "Delete", arg0)
@@ -72,6 +111,45 @@ func (mr *MockJobLogicMockRecorder) GetJob(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJob", reflect.TypeOf((*MockJobLogic)(nil).GetJob), arg0)
 }
 
+// GetJobLogs mocks base method
+func (m *MockJobLogic) GetJobLogs(arg0, arg1, arg2 string, arg3 int) ([]*models.LogFile, error) {
+	ret := m.ctrl.Call(m, "GetJobLogs", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*models.LogFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobLogs indicates an expected call of GetJobLogs
+func (mr *MockJobLogicMockRecorder) GetJobLogs(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobLogs", reflect.TypeOf((*MockJobLogic)(nil).GetJobLogs), arg0, arg1, arg2, arg3)
+}
+
+// GetWorkflow mocks base method
+func (m *MockJobLogic) GetWorkflow(arg0 string) (*models.Workflow, error) {
+	ret := m.ctrl.Call(m, "GetWorkflow", arg0)
+	ret0, _ := ret[0].(*models.Workflow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkflow indicates an expected call of GetWorkflow
+func (mr *MockJobLogicMockRecorder) GetWorkflow(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkflow", reflect.TypeOf((*MockJobLogic)(nil).GetWorkflow), arg0)
+}
+
+// ListArchivedJobs mocks base method
+func (m *MockJobLogic) ListArchivedJobs() ([]*models.Job, error) {
+	ret := m.ctrl.Call(m, "ListArchivedJobs")
+	ret0, _ := ret[0].([]*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListArchivedJobs indicates an expected call of ListArchivedJobs
+func (mr *MockJobLogicMockRecorder) ListArchivedJobs() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListArchivedJobs", reflect.TypeOf((*MockJobLogic)(nil).ListArchivedJobs))
+}
+
 // ListJobs mocks base method
 func (m *MockJobLogic) ListJobs() ([]*models.Job, error) {
 	ret := m.ctrl.Call(m, "ListJobs")
@@ -84,3 +162,55 @@ func (m *MockJobLogic) ListJobs() ([]*models.Job, error) {
 func (mr *MockJobLogicMockRecorder) ListJobs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobs", reflect.TypeOf((*MockJobLogic)(nil).ListJobs))
 }
+
+// ListJobsByEntity mocks base method
+func (m *MockJobLogic) ListJobsByEntity(arg0, arg1 string) ([]*models.Job, error) {
+	ret := m.ctrl.Call(m, "ListJobsByEntity", arg0, arg1)
+	ret0, _ := ret[0].([]*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJobsByEntity indicates an expected call of ListJobsByEntity
+func (mr *MockJobLogicMockRecorder) ListJobsByEntity(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobsByEntity", reflect.TypeOf((*MockJobLogic)(nil).ListJobsByEntity), arg0, arg1)
+}
+
+// ListJobsByStatus mocks base method
+func (m *MockJobLogic) ListJobsByStatus(arg0 types.JobStatus) ([]*models.Job, error) {
+	ret := m.ctrl.Call(m, "ListJobsByStatus", arg0)
+	ret0, _ := ret[0].([]*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJobsByStatus indicates an expected call of ListJobsByStatus
+func (mr *MockJobLogicMockRecorder) ListJobsByStatus(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobsByStatus", reflect.TypeOf((*MockJobLogic)(nil).ListJobsByStatus), arg0)
+}
+
+// ListJobsByType mocks base method
+func (m *MockJobLogic) ListJobsByType(arg0 types.JobType) ([]*models.Job, error) {
+	ret := m.ctrl.Call(m, "ListJobsByType", arg0)
+	ret0, _ := ret[0].([]*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJobsByType indicates an expected call of ListJobsByType
+func (mr *MockJobLogicMockRecorder) ListJobsByType(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobsByType", reflect.TypeOf((*MockJobLogic)(nil).ListJobsByType), arg0)
+}
+
+// RetryJob mocks base method
+func (m *MockJobLogic) RetryJob(arg0 string) (*models.Job, error) {
+	ret := m.ctrl.Call(m, "RetryJob", arg0)
+	ret0, _ := ret[0].(*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetryJob indicates an expected call of RetryJob
+func (mr *MockJobLogicMockRecorder) RetryJob(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryJob", reflect.TypeOf((*MockJobLogic)(nil).RetryJob), arg0)
+}
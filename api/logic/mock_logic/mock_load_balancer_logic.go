@@ -85,53 +85,53 @@ func (mr *MockLoadBalancerLogicMockRecorder) ListLoadBalancers() *gomock.Call {
 }
 
 // UpdateLoadBalancerCrossZone mocks base method
-func (m *MockLoadBalancerLogic) UpdateLoadBalancerCrossZone(arg0 string, arg1 bool) (*models.LoadBalancer, error) {
-	ret := m.ctrl.Call(m, "UpdateLoadBalancerCrossZone", arg0, arg1)
+func (m *MockLoadBalancerLogic) UpdateLoadBalancerCrossZone(arg0 string, arg1 bool, arg2 int64) (*models.LoadBalancer, error) {
+	ret := m.ctrl.Call(m, "UpdateLoadBalancerCrossZone", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*models.LoadBalancer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateLoadBalancerCrossZone indicates an expected call of UpdateLoadBalancerCrossZone
-func (mr *MockLoadBalancerLogicMockRecorder) UpdateLoadBalancerCrossZone(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerCrossZone", reflect.TypeOf((*MockLoadBalancerLogic)(nil).UpdateLoadBalancerCrossZone), arg0, arg1)
+func (mr *MockLoadBalancerLogicMockRecorder) UpdateLoadBalancerCrossZone(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerCrossZone", reflect.TypeOf((*MockLoadBalancerLogic)(nil).UpdateLoadBalancerCrossZone), arg0, arg1, arg2)
 }
 
 // UpdateLoadBalancerHealthCheck mocks base method
-func (m *MockLoadBalancerLogic) UpdateLoadBalancerHealthCheck(arg0 string, arg1 models.HealthCheck) (*models.LoadBalancer, error) {
-	ret := m.ctrl.Call(m, "UpdateLoadBalancerHealthCheck", arg0, arg1)
+func (m *MockLoadBalancerLogic) UpdateLoadBalancerHealthCheck(arg0 string, arg1 models.HealthCheck, arg2 int64) (*models.LoadBalancer, error) {
+	ret := m.ctrl.Call(m, "UpdateLoadBalancerHealthCheck", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*models.LoadBalancer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateLoadBalancerHealthCheck indicates an expected call of UpdateLoadBalancerHealthCheck
-func (mr *MockLoadBalancerLogicMockRecorder) UpdateLoadBalancerHealthCheck(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerHealthCheck", reflect.TypeOf((*MockLoadBalancerLogic)(nil).UpdateLoadBalancerHealthCheck), arg0, arg1)
+func (mr *MockLoadBalancerLogicMockRecorder) UpdateLoadBalancerHealthCheck(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerHealthCheck", reflect.TypeOf((*MockLoadBalancerLogic)(nil).UpdateLoadBalancerHealthCheck), arg0, arg1, arg2)
 }
 
 // UpdateLoadBalancerIdleTimeout mocks base method
-func (m *MockLoadBalancerLogic) UpdateLoadBalancerIdleTimeout(arg0 string, arg1 int) (*models.LoadBalancer, error) {
-	ret := m.ctrl.Call(m, "UpdateLoadBalancerIdleTimeout", arg0, arg1)
+func (m *MockLoadBalancerLogic) UpdateLoadBalancerIdleTimeout(arg0 string, arg1 int, arg2 int64) (*models.LoadBalancer, error) {
+	ret := m.ctrl.Call(m, "UpdateLoadBalancerIdleTimeout", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*models.LoadBalancer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateLoadBalancerIdleTimeout indicates an expected call of UpdateLoadBalancerIdleTimeout
-func (mr *MockLoadBalancerLogicMockRecorder) UpdateLoadBalancerIdleTimeout(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerIdleTimeout", reflect.TypeOf((*MockLoadBalancerLogic)(nil).UpdateLoadBalancerIdleTimeout), arg0, arg1)
+func (mr *MockLoadBalancerLogicMockRecorder) UpdateLoadBalancerIdleTimeout(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerIdleTimeout", reflect.TypeOf((*MockLoadBalancerLogic)(nil).UpdateLoadBalancerIdleTimeout), arg0, arg1, arg2)
 }
 
 // UpdateLoadBalancerPorts mocks base method
-func (m *MockLoadBalancerLogic) UpdateLoadBalancerPorts(arg0 string, arg1 []models.Port) (*models.LoadBalancer, error) {
-	ret := m.ctrl.Call(m, "UpdateLoadBalancerPorts", arg0, arg1)
+func (m *MockLoadBalancerLogic) UpdateLoadBalancerPorts(arg0 string, arg1 []models.Port, arg2 int64) (*models.LoadBalancer, error) {
+	ret := m.ctrl.Call(m, "UpdateLoadBalancerPorts", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*models.LoadBalancer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateLoadBalancerPorts indicates an expected call of UpdateLoadBalancerPorts
-func (mr *MockLoadBalancerLogicMockRecorder) UpdateLoadBalancerPorts(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerPorts", reflect.TypeOf((*MockLoadBalancerLogic)(nil).UpdateLoadBalancerPorts), arg0, arg1)
+func (mr *MockLoadBalancerLogicMockRecorder) UpdateLoadBalancerPorts(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerPorts", reflect.TypeOf((*MockLoadBalancerLogic)(nil).UpdateLoadBalancerPorts), arg0, arg1, arg2)
 }
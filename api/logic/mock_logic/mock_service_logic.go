@@ -111,27 +111,27 @@ func (mr *MockServiceLogicMockRecorder) ListServices() *gomock.Call {
 }
 
 // ScaleService mocks base method
-func (m *MockServiceLogic) ScaleService(arg0 string, arg1 int) (*models.Service, error) {
-	ret := m.ctrl.Call(m, "ScaleService", arg0, arg1)
+func (m *MockServiceLogic) ScaleService(arg0 string, arg1 int, arg2 int64) (*models.Service, error) {
+	ret := m.ctrl.Call(m, "ScaleService", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*models.Service)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ScaleService indicates an expected call of ScaleService
-func (mr *MockServiceLogicMockRecorder) ScaleService(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScaleService", reflect.TypeOf((*MockServiceLogic)(nil).ScaleService), arg0, arg1)
+func (mr *MockServiceLogicMockRecorder) ScaleService(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScaleService", reflect.TypeOf((*MockServiceLogic)(nil).ScaleService), arg0, arg1, arg2)
 }
 
 // UpdateService mocks base method
-func (m *MockServiceLogic) UpdateService(arg0 string, arg1 models.UpdateServiceRequest) (*models.Service, error) {
-	ret := m.ctrl.Call(m, "UpdateService", arg0, arg1)
+func (m *MockServiceLogic) UpdateService(arg0 string, arg1 models.UpdateServiceRequest, arg2 int64) (*models.Service, error) {
+	ret := m.ctrl.Call(m, "UpdateService", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*models.Service)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateService indicates an expected call of UpdateService
-func (mr *MockServiceLogicMockRecorder) UpdateService(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateService", reflect.TypeOf((*MockServiceLogic)(nil).UpdateService), arg0, arg1)
+func (mr *MockServiceLogicMockRecorder) UpdateService(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateService", reflect.TypeOf((*MockServiceLogic)(nil).UpdateService), arg0, arg1, arg2)
 }
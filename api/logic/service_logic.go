@@ -15,8 +15,8 @@ type ServiceLogic interface {
 	GetEnvironmentServices(environmentID string) ([]*models.Service, error)
 	CreateService(req models.CreateServiceRequest) (*models.Service, error)
 	DeleteService(serviceID string) error
-	UpdateService(serviceID string, req models.UpdateServiceRequest) (*models.Service, error)
-	ScaleService(serviceID string, size int) (*models.Service, error)
+	UpdateService(serviceID string, req models.UpdateServiceRequest, version int64) (*models.Service, error)
+	ScaleService(serviceID string, size int, version int64) (*models.Service, error)
 	GetServiceLogs(serviceID, start, end string, tail int) ([]*models.LogFile, error)
 }
 
@@ -89,14 +89,18 @@ func (this *L0ServiceLogic) DeleteService(serviceID string) error {
 	return nil
 }
 
-func (this *L0ServiceLogic) ScaleService(serviceID string, size int) (*models.Service, error) {
+func (this *L0ServiceLogic) ScaleService(serviceID string, size int, version int64) (*models.Service, error) {
 	environmentID, err := this.getEnvironmentID(serviceID)
 	if err != nil {
 		return nil, err
 	}
 
-	service, err := this.Backend.ScaleService(environmentID, serviceID, size)
-	if err != nil {
+	var service *models.Service
+	if err := this.updateVersioned("service", serviceID, version, func(mutating func()) error {
+		mutating()
+		service, err = this.Backend.ScaleService(environmentID, serviceID, size)
+		return err
+	}); err != nil {
 		return nil, err
 	}
 
@@ -108,14 +112,18 @@ func (this *L0ServiceLogic) ScaleService(serviceID string, size int) (*models.Se
 	return service, nil
 }
 
-func (this *L0ServiceLogic) UpdateService(serviceID string, req models.UpdateServiceRequest) (*models.Service, error) {
+func (this *L0ServiceLogic) UpdateService(serviceID string, req models.UpdateServiceRequest, version int64) (*models.Service, error) {
 	environmentID, err := this.getEnvironmentID(serviceID)
 	if err != nil {
 		return nil, err
 	}
 
-	service, err := this.Backend.UpdateService(environmentID, serviceID, req.DeployID)
-	if err != nil {
+	var service *models.Service
+	if err := this.updateVersioned("service", serviceID, version, func(mutating func()) error {
+		mutating()
+		service, err = this.Backend.UpdateService(environmentID, serviceID, req.DeployID)
+		return err
+	}); err != nil {
 		return nil, err
 	}
 
@@ -244,6 +252,13 @@ func (this *L0ServiceLogic) populateModel(model *models.Service) error {
 		return err
 	}
 
+	version, err := this.TagStore.SelectVersion("service", model.ServiceID)
+	if err != nil {
+		return err
+	}
+
+	model.Version = version
+
 	if tag, ok := tags.WithKey("environment_id").First(); ok {
 		model.EnvironmentID = tag.Value
 	}
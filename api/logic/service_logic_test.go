@@ -1,10 +1,13 @@
 package logic
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/quintilesims/layer0/api/backend/ecs/id"
+	"github.com/quintilesims/layer0/common/db/tag_store"
+	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/testutils"
 	"github.com/stretchr/testify/assert"
@@ -246,7 +249,7 @@ func TestUpdateService(t *testing.T) {
 	}
 
 	serviceLogic := NewL0ServiceLogic(testLogic.Logic())
-	service, err := serviceLogic.UpdateService("s1", request)
+	service, err := serviceLogic.UpdateService("s1", request, tag_store.AnyVersion)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -271,7 +274,7 @@ func TestScaleService(t *testing.T) {
 	})
 
 	serviceLogic := NewL0ServiceLogic(testLogic.Logic())
-	service, err := serviceLogic.ScaleService("s1", 2)
+	service, err := serviceLogic.ScaleService("s1", 2, tag_store.AnyVersion)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -280,6 +283,57 @@ func TestScaleService(t *testing.T) {
 	testutils.AssertEqual(t, service.EnvironmentID, "e1")
 }
 
+func TestScaleServiceVersionConflict(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	testLogic.AddTags(t, []*models.Tag{
+		{EntityID: "s1", EntityType: "service", Key: "environment_id", Value: "e1"},
+	})
+
+	version, err := testLogic.TagStore.SelectVersion("service", "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serviceLogic := NewL0ServiceLogic(testLogic.Logic())
+	if _, err := serviceLogic.ScaleService("s1", 2, version-1); err == nil {
+		t.Fatalf("Error was nil!")
+	} else if serr, ok := err.(*errors.ServerError); !ok || serr.Code != errors.EntityConflict {
+		t.Fatalf("Expected EntityConflict error, got: %v", err)
+	}
+}
+
+func TestScaleServiceBackendErrorKeepsNewVersion(t *testing.T) {
+	testLogic, ctrl := NewTestLogic(t)
+	defer ctrl.Finish()
+
+	testLogic.AddTags(t, []*models.Tag{
+		{EntityID: "s1", EntityType: "service", Key: "environment_id", Value: "e1"},
+	})
+
+	version, err := testLogic.TagStore.SelectVersion("service", "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testLogic.Backend.EXPECT().
+		ScaleService("e1", "s1", 2).
+		Return(nil, fmt.Errorf("some error"))
+
+	serviceLogic := NewL0ServiceLogic(testLogic.Logic())
+	if _, err := serviceLogic.ScaleService("s1", 2, version); err == nil {
+		t.Fatalf("Error was nil!")
+	}
+
+	// the failed scale may have changed the service, so a retry has to re-read it first
+	if _, err := serviceLogic.ScaleService("s1", 2, version); err == nil {
+		t.Fatalf("Error was nil!")
+	} else if serr, ok := err.(*errors.ServerError); !ok || serr.Code != errors.EntityConflict {
+		t.Fatalf("Expected EntityConflict error, got: %v", err)
+	}
+}
+
 func TestGetServiceLogs(t *testing.T) {
 	testLogic, ctrl := NewTestLogic(t)
 	defer ctrl.Finish()
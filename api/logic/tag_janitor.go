@@ -1,11 +1,16 @@
 package logic
 
 import (
+	"fmt"
+	"sort"
 	"time"
 
+	"github.com/quintilesims/layer0/api/backend"
 	"github.com/quintilesims/layer0/common/db/tag_store"
 	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/logutils"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/types"
 	"github.com/quintilesims/layer0/common/waitutils"
 )
 
@@ -15,64 +20,141 @@ const (
 
 var tagLogger = logutils.NewStackTraceLogger("Tags Janitor")
 
+// TagJanitor removes the tags of entities that no longer exist, so deleted
+// entities don't cause "multiple matches" errors when resolving names.
+// In dry run mode, orphaned tags are only logged.
 type TagJanitor struct {
 	TaskLogic TaskLogic
+	Backend   backend.Backend
 	TagStore  tag_store.TagStore
+	DryRun    bool
+	Leader    Leader
 	Clock     waitutils.Clock
 }
 
-func NewTagJanitor(taskLogic TaskLogic, tagStore tag_store.TagStore) *TagJanitor {
+func NewTagJanitor(taskLogic TaskLogic, b backend.Backend, tagStore tag_store.TagStore) *TagJanitor {
 	return &TagJanitor{
 		TaskLogic: taskLogic,
+		Backend:   b,
 		TagStore:  tagStore,
+		Leader:    AlwaysLeader{},
 		Clock:     waitutils.RealClock{},
 	}
 }
 
 func (t *TagJanitor) Run() {
-	go func() {
-		for {
-			tagLogger.Info("Starting cleanup")
-			t.pulse()
-			tagLogger.Infof("Finished cleanup")
-			t.Clock.Sleep(taskJanitorSleepDuration)
-		}
-	}()
+	go RunAsLeader(t.Leader, t.Clock, taskJanitorSleepDuration, func() {
+		tagLogger.Info("Starting cleanup")
+		t.pulse()
+		tagLogger.Infof("Finished cleanup")
+	})
 }
 
 func (t *TagJanitor) pulse() error {
-	tasks, err := t.TaskLogic.ListTasks()
+	report, err := t.Reconcile(t.DryRun)
 	if err != nil {
-		tagLogger.Errorf("Failed to list tasks: %v", err)
-		return err
+		tagLogger.Errorf("Failed to reconcile tags: %v", err)
 	}
 
-	taskExists := func(id string) bool {
-		for _, t := range tasks {
-			if t.TaskID == id {
-				return true
+	if report != nil {
+		for _, orphan := range report.Orphans {
+			if report.DryRun {
+				tagLogger.Infof("Found %d tags for %s '%s', which does not exist", len(orphan.Tags), orphan.EntityType, orphan.EntityID)
+				continue
 			}
+
+			tagLogger.Infof("Deleted tags for %s '%s', which does not exist", orphan.EntityType, orphan.EntityID)
 		}
+	}
 
-		return false
+	return err
+}
+
+// Reconcile checks the tagged entities of each type in types.EntityTypes, and tasks,
+// against the backend and deletes the tags of the entities that no longer exist.
+// Tags that can't be deleted are still listed in the report.
+func (t *TagJanitor) Reconcile(dryRun bool) (*models.TagReconciliationReport, error) {
+	report := &models.TagReconciliationReport{
+		DryRun:  dryRun,
+		Orphans: []models.EntityWithTags{},
 	}
 
-	tags, err := t.TagStore.SelectByType("task")
-	if err != nil {
-		tagLogger.Errorln("Could not query for tag store for task entity type - ", err.Error())
+	entityTypes := []string{"task"}
+	for entityType := range types.EntityTypes {
+		entityTypes = append(entityTypes, entityType)
 	}
 
+	sort.Strings(entityTypes)
+
 	errs := []error{}
-	for _, tag := range tags {
-		if !taskExists(tag.EntityID) {
-			if err := t.TagStore.Delete(tag.EntityType, tag.EntityID, tag.Key); err != nil {
-				tagLogger.Errorf("Could not delete tag (%#v) -  %s\n", tag, err.Error())
+	for _, entityType := range entityTypes {
+		// a replica that lost the lease stops, so it doesn't delete tags alongside the new leader
+		if !t.Leader.IsLeader() {
+			errs = append(errs, fmt.Errorf("Stopped before checking %s tags: this replica is no longer the leader", entityType))
+			break
+		}
+
+		// the tags are selected before the live entities are listed, since the tags
+		// of a new entity are only written after the entity has been created
+		tags, err := t.TagStore.SelectByType(entityType)
+		if err != nil {
+			return nil, err
+		}
+
+		liveIDs, err := t.listEntityIDs(entityType)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to list %s entities: %v", entityType, err)
+		}
+
+		live := map[string]bool{}
+		for _, liveID := range liveIDs {
+			live[liveID] = true
+		}
+
+		for _, ewt := range tags.GroupByEntity() {
+			report.EntitiesChecked++
+			if live[ewt.EntityID] {
 				continue
 			}
 
-			tagLogger.Infof("Tag for task (%s) has been deleted\n", tag.EntityID)
+			report.Orphans = append(report.Orphans, *ewt)
+			if dryRun {
+				continue
+			}
+
+			for _, tag := range ewt.Tags {
+				if err := t.TagStore.Delete(tag.EntityType, tag.EntityID, tag.Key); err != nil {
+					errs = append(errs, fmt.Errorf("Failed to delete tag %s/%s '%s': %v", tag.EntityType, tag.EntityID, tag.Key, err))
+					continue
+				}
+
+				report.TagsDeleted++
+			}
 		}
 	}
 
-	return errors.MultiError(errs)
+	return report, errors.MultiError(errs)
+}
+
+func (t *TagJanitor) listEntityIDs(entityType string) ([]string, error) {
+	if entityType == "task" {
+		tasks, err := t.TaskLogic.ListTasks()
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]string, len(tasks))
+		for i, task := range tasks {
+			ids[i] = task.TaskID
+		}
+
+		return ids, nil
+	}
+
+	listIDs, ok := liveEntityIDs[entityType]
+	if !ok {
+		return nil, fmt.Errorf("Unsupported entity type '%s'", entityType)
+	}
+
+	return listIDs(t.Backend)
 }
@@ -1,9 +1,12 @@
 package logic
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/quintilesims/layer0/api/backend/ecs/id"
+	"github.com/quintilesims/layer0/api/backend/mock_backend"
 	"github.com/quintilesims/layer0/api/logic/mock_logic"
 	"github.com/quintilesims/layer0/common/db/tag_store"
 	"github.com/quintilesims/layer0/common/models"
@@ -36,7 +39,10 @@ func TestTagJanitorPulse(t *testing.T) {
 		ListTasks().
 		Return(tasks, nil)
 
-	janitor := NewTagJanitor(taskLogicMock, tagStore)
+	mockBackend := mock_backend.NewMockBackend(ctrl)
+	expectNoLiveEntities(mockBackend)
+
+	janitor := NewTagJanitor(taskLogicMock, mockBackend, tagStore)
 	if err := janitor.pulse(); err != nil {
 		t.Fatal(err)
 	}
@@ -51,6 +57,173 @@ func TestTagJanitorPulse(t *testing.T) {
 	testutils.AssertEqual(t, "t2", tags[0].EntityID)
 }
 
+func TestTagJanitorReconcile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	taskLogicMock := mock_logic.NewMockTaskLogic(ctrl)
+	mockBackend := mock_backend.NewMockBackend(ctrl)
+
+	tagStore, _ := getTagStore([]models.Tag{
+		{EntityID: "d.1", EntityType: "deploy", Key: "name", Value: "dpl"},
+		{EntityID: "d.2", EntityType: "deploy", Key: "name", Value: "dpl"},
+		{EntityID: "e1", EntityType: "environment", Key: "name", Value: "env1"},
+		{EntityID: "e2", EntityType: "environment", Key: "name", Value: "env2"},
+		{EntityID: "l1", EntityType: "load_balancer", Key: "name", Value: "lb1"},
+		{EntityID: "l1", EntityType: "load_balancer", Key: "environment_id", Value: "e1"},
+		{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc1"},
+		{EntityID: "s2", EntityType: "service", Key: "name", Value: "svc2"},
+		{EntityID: "t1", EntityType: "task", Key: "name", Value: "tsk1"},
+	})
+
+	taskLogicMock.EXPECT().
+		ListTasks().
+		Return([]*models.TaskSummary{{TaskID: "t1"}}, nil)
+
+	mockBackend.EXPECT().
+		ListDeploys().
+		Return([]*models.Deploy{{DeployID: "d.1"}}, nil)
+
+	mockBackend.EXPECT().
+		ListEnvironments().
+		Return([]id.ECSEnvironmentID{id.L0EnvironmentID("e1").ECSEnvironmentID()}, nil)
+
+	mockBackend.EXPECT().
+		ListLoadBalancers().
+		Return([]*models.LoadBalancer{}, nil)
+
+	mockBackend.EXPECT().
+		ListServices().
+		Return([]id.ECSServiceID{id.L0ServiceID("s1").ECSServiceID()}, nil)
+
+	janitor := NewTagJanitor(taskLogicMock, mockBackend, tagStore)
+	report, err := janitor.Reconcile(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, report.EntitiesChecked, 8)
+	testutils.AssertEqual(t, report.TagsDeleted, 5)
+
+	orphans := map[string]bool{}
+	for _, orphan := range report.Orphans {
+		orphans[orphan.EntityType+"/"+orphan.EntityID] = true
+	}
+
+	expected := map[string]bool{
+		"deploy/d.2":       true,
+		"environment/e2":   true,
+		"load_balancer/l1": true,
+		"service/s2":       true,
+	}
+
+	testutils.AssertEqual(t, orphans, expected)
+
+	for _, entityType := range []string{"deploy", "environment", "load_balancer", "service", "task"} {
+		tags, err := tagStore.SelectByType(entityType)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, tag := range tags {
+			if orphans[tag.EntityType+"/"+tag.EntityID] {
+				t.Errorf("Tag %#v was not deleted", tag)
+			}
+		}
+	}
+}
+
+func TestTagJanitorReconcileDryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	taskLogicMock := mock_logic.NewMockTaskLogic(ctrl)
+	mockBackend := mock_backend.NewMockBackend(ctrl)
+	tagStore, tagsAdded := getTagStore(taskTags)
+
+	taskLogicMock.EXPECT().
+		ListTasks().
+		Return([]*models.TaskSummary{}, nil)
+
+	expectNoLiveEntities(mockBackend)
+
+	janitor := NewTagJanitor(taskLogicMock, mockBackend, tagStore)
+	report, err := janitor.Reconcile(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(report.Orphans), 2)
+	testutils.AssertEqual(t, report.TagsDeleted, 0)
+
+	tags, err := tagStore.SelectByType("task")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(tags), tagsAdded)
+}
+
+func TestTagJanitorReconcileListError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	taskLogicMock := mock_logic.NewMockTaskLogic(ctrl)
+	mockBackend := mock_backend.NewMockBackend(ctrl)
+	tagStore, _ := getTagStore([]models.Tag{
+		{EntityID: "d.1", EntityType: "deploy", Key: "name", Value: "dpl"},
+	})
+
+	mockBackend.EXPECT().
+		ListDeploys().
+		Return(nil, fmt.Errorf("some error"))
+
+	// no tags should be deleted when the live entities can't be listed
+	janitor := NewTagJanitor(taskLogicMock, mockBackend, tagStore)
+	if _, err := janitor.Reconcile(false); err == nil {
+		t.Fatal("Error was nil!")
+	}
+
+	tags, err := tagStore.SelectByType("deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(tags), 1)
+}
+
+func TestTagJanitorReconcileStopsWhenNotLeader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	taskLogicMock := mock_logic.NewMockTaskLogic(ctrl)
+	mockBackend := mock_backend.NewMockBackend(ctrl)
+	tagStore, _ := getTagStore([]models.Tag{
+		{EntityID: "d.1", EntityType: "deploy", Key: "name", Value: "dpl"},
+	})
+
+	// the replica isn't the leader anymore, so no tags are checked or deleted
+	janitor := NewTagJanitor(taskLogicMock, mockBackend, tagStore)
+	janitor.Leader = &toggleLeader{results: []bool{false}}
+	if _, err := janitor.Reconcile(false); err == nil {
+		t.Fatal("Error was nil!")
+	}
+
+	tags, err := tagStore.SelectByType("deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(tags), 1)
+}
+
+func expectNoLiveEntities(mockBackend *mock_backend.MockBackend) {
+	mockBackend.EXPECT().ListDeploys().Return([]*models.Deploy{}, nil)
+	mockBackend.EXPECT().ListEnvironments().Return([]id.ECSEnvironmentID{}, nil)
+	mockBackend.EXPECT().ListLoadBalancers().Return([]*models.LoadBalancer{}, nil)
+	mockBackend.EXPECT().ListServices().Return([]id.ECSServiceID{}, nil)
+}
+
 func getTagStore(tags []models.Tag) (tag_store.TagStore, int) {
 	store := tag_store.NewMemoryTagStore()
 	tagsAdded := 0
@@ -0,0 +1,87 @@
+package logic
+
+import (
+	"sync"
+
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/logutils"
+	"github.com/quintilesims/layer0/common/models"
+)
+
+var workerPoolLogger = logutils.NewStandardLogger("Job Worker Pool")
+
+// JobRunFunc runs the job's steps in-process. The quit channel is closed when the job is stopped
+type JobRunFunc func(jobID string, quit chan bool) error
+
+// WorkerPoolJobExecutor runs jobs inside the api using a bounded number of workers.
+// Jobs wait in a queue until a worker is free; Execute fails once the queue is full
+type WorkerPoolJobExecutor struct {
+	run   JobRunFunc
+	queue chan string
+	quit  map[string]chan bool
+	mutex sync.Mutex
+}
+
+func NewWorkerPoolJobExecutor(workers, queueSize int, run JobRunFunc) *WorkerPoolJobExecutor {
+	executor := &WorkerPoolJobExecutor{
+		run:   run,
+		queue: make(chan string, queueSize),
+		quit:  map[string]chan bool{},
+	}
+
+	for i := 0; i < workers; i++ {
+		go executor.work()
+	}
+
+	return executor
+}
+
+func (this *WorkerPoolJobExecutor) Execute(jobID string) (string, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	quit := make(chan bool)
+	select {
+	case this.queue <- jobID:
+		this.quit[jobID] = quit
+		return "", nil
+	default:
+		return "", errors.Newf(errors.Throttled, "The job queue is full, try again later")
+	}
+}
+
+func (this *WorkerPoolJobExecutor) Stop(job *models.Job) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if quit, ok := this.quit[job.JobID]; ok {
+		close(quit)
+		delete(this.quit, job.JobID)
+	}
+
+	return nil
+}
+
+func (this *WorkerPoolJobExecutor) work() {
+	for jobID := range this.queue {
+		this.mutex.Lock()
+		quit, ok := this.quit[jobID]
+		this.mutex.Unlock()
+
+		// the job was stopped before a worker picked it up
+		if !ok {
+			continue
+		}
+
+		workerPoolLogger.Infof("Running job %s", jobID)
+		if err := this.run(jobID, quit); err != nil {
+			workerPoolLogger.Errorf("Job %s failed: %v", jobID, err)
+		}
+
+		this.mutex.Lock()
+		if current, ok := this.quit[jobID]; ok && current == quit {
+			delete(this.quit, jobID)
+		}
+		this.mutex.Unlock()
+	}
+}
@@ -0,0 +1,73 @@
+package logic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+func TestWorkerPoolJobExecutorRunsJobs(t *testing.T) {
+	ran := make(chan string)
+	run := func(jobID string, quit chan bool) error {
+		ran <- jobID
+		return nil
+	}
+
+	executor := NewWorkerPoolJobExecutor(1, 2, run)
+	taskID, err := executor.Execute("j1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, taskID, "")
+
+	select {
+	case jobID := <-ran:
+		testutils.AssertEqual(t, jobID, "j1")
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for the job to run")
+	}
+}
+
+func TestWorkerPoolJobExecutorStop(t *testing.T) {
+	started := make(chan bool)
+	stopped := make(chan bool)
+	run := func(jobID string, quit chan bool) error {
+		close(started)
+		<-quit
+		close(stopped)
+		return nil
+	}
+
+	executor := NewWorkerPoolJobExecutor(1, 1, run)
+	if _, err := executor.Execute("j1"); err != nil {
+		t.Fatal(err)
+	}
+
+	<-started
+	if err := executor.Stop(&models.Job{JobID: "j1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for the job to stop")
+	}
+}
+
+func TestWorkerPoolJobExecutorQueueFull(t *testing.T) {
+	// no workers are started, so jobs stay in the queue
+	executor := NewWorkerPoolJobExecutor(0, 1, nil)
+	if _, err := executor.Execute("j1"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := executor.Execute("j2")
+	if err, ok := err.(*errors.ServerError); !ok || err.Code != errors.Throttled {
+		t.Fatalf("Expected Throttled error, got %v", err)
+	}
+}
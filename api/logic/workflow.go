@@ -0,0 +1,219 @@
+package logic
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/types"
+)
+
+var (
+	workflowNodeNameExpr  = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	workflowReferenceExpr = regexp.MustCompile(`\$\{([A-Za-z0-9_-]+)\.([A-Za-z0-9_]+)\}`)
+)
+
+// WorkflowFailurePolicy returns the request's failure policy; "halt" if it is not set
+func WorkflowFailurePolicy(req models.CreateWorkflowRequest) (types.WorkflowFailurePolicy, error) {
+	switch policy := types.WorkflowFailurePolicy(strings.ToLower(req.OnFailure)); policy {
+	case "":
+		return types.WorkflowHalt, nil
+	case types.WorkflowHalt, types.WorkflowContinue:
+		return policy, nil
+	default:
+		return "", errors.Newf(errors.InvalidWorkflow, "Unknown failure policy '%s': must be 'halt' or 'continue'", req.OnFailure)
+	}
+}
+
+// ValidateWorkflow checks that the workflow's nodes have unique names and valid requests,
+// that each node only depends on and references nodes that exist, and that there are no cycles
+func ValidateWorkflow(req models.CreateWorkflowRequest) error {
+	if _, err := WorkflowFailurePolicy(req); err != nil {
+		return err
+	}
+
+	if len(req.Nodes) == 0 {
+		return errors.Newf(errors.InvalidWorkflow, "Workflow has no nodes")
+	}
+
+	nodes := map[string]models.WorkflowNode{}
+	for _, node := range req.Nodes {
+		if !workflowNodeNameExpr.MatchString(node.Name) {
+			return errors.Newf(errors.InvalidWorkflow, "Node name '%s' must only contain letters, numbers, '-' and '_'", node.Name)
+		}
+
+		if _, ok := nodes[node.Name]; ok {
+			return errors.Newf(errors.InvalidWorkflow, "Node '%s' is defined more than once", node.Name)
+		}
+
+		nodes[node.Name] = node
+	}
+
+	for _, node := range req.Nodes {
+		jobType, err := types.ParseJobType(node.JobType)
+		if err != nil {
+			return errors.Newf(errors.InvalidWorkflow, "Node '%s': %v", node.Name, err)
+		}
+
+		if jobType == types.WorkflowJob {
+			return errors.Newf(errors.InvalidWorkflow, "Node '%s': workflows cannot be nested", node.Name)
+		}
+
+		if _, err := DecodeJobRequest(jobType, node.Request); err != nil {
+			return errors.Newf(errors.InvalidWorkflow, "Node '%s': invalid request: %v", node.Name, err)
+		}
+
+		dependsOn := map[string]bool{}
+		for _, dependency := range node.DependsOn {
+			if _, ok := nodes[dependency]; !ok {
+				return errors.Newf(errors.InvalidWorkflow, "Node '%s' depends on unknown node '%s'", node.Name, dependency)
+			}
+
+			dependsOn[dependency] = true
+		}
+
+		for _, match := range workflowReferenceExpr.FindAllStringSubmatch(string(node.Request), -1) {
+			if !dependsOn[match[1]] {
+				return errors.Newf(errors.InvalidWorkflow, "Node '%s' references '%s' but does not depend on '%s'", node.Name, match[0], match[1])
+			}
+		}
+	}
+
+	if cycle := findWorkflowCycle(req.Nodes); cycle != nil {
+		return errors.Newf(errors.InvalidWorkflow, "Workflow has a dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	return nil
+}
+
+// findWorkflowCycle returns the names of the nodes in a dependency cycle, or nil if there is none
+func findWorkflowCycle(nodes []models.WorkflowNode) []string {
+	dependencies := map[string][]string{}
+	for _, node := range nodes {
+		dependencies[node.Name] = node.DependsOn
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := map[string]int{}
+	path := []string{}
+
+	var visit func(string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case visiting:
+			for i, n := range path {
+				if n == name {
+					return append(append([]string{}, path[i:]...), name)
+				}
+			}
+		case visited:
+			return nil
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, dependency := range dependencies[name] {
+			if cycle := visit(dependency); cycle != nil {
+				return cycle
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+
+	for _, node := range nodes {
+		if cycle := visit(node.Name); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
+// ResolveWorkflowReferences replaces each ${node.key} in the request
+// with the value of key in the meta of the specified node's job
+func ResolveWorkflowReferences(request json.RawMessage, meta map[string]map[string]string) (json.RawMessage, error) {
+	var err error
+	resolved := workflowReferenceExpr.ReplaceAllStringFunc(string(request), func(reference string) string {
+		match := workflowReferenceExpr.FindStringSubmatch(reference)
+		value, ok := meta[match[1]][match[2]]
+		if !ok {
+			err = fmt.Errorf("Node '%s' did not set '%s'", match[1], match[2])
+			return reference
+		}
+
+		// the reference is inside a json string, so the value must be escaped
+		escaped, _ := json.Marshal(value)
+		return strings.Trim(string(escaped), "\"")
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(resolved), nil
+}
+
+// DecodeJobRequest parses the json request of a job with the specified type
+// into the request type CreateJob expects for it
+func DecodeJobRequest(jobType types.JobType, data []byte) (interface{}, error) {
+	var err error
+	var request interface{}
+
+	switch jobType {
+	case types.DeleteEnvironmentJob, types.DeleteServiceJob, types.DeleteLoadBalancerJob, types.DeleteTaskJob:
+		var req string
+		err = json.Unmarshal(data, &req)
+		request = req
+	case types.CreateTaskJob:
+		var req models.CreateTaskRequest
+		err = json.Unmarshal(data, &req)
+		request = req
+	case types.CreateEnvironmentJob:
+		var req models.CreateEnvironmentRequest
+		err = json.Unmarshal(data, &req)
+		request = req
+	case types.CreateLoadBalancerJob:
+		var req models.CreateLoadBalancerRequest
+		err = json.Unmarshal(data, &req)
+		request = req
+	case types.CreateServiceJob:
+		var req models.CreateServiceRequest
+		err = json.Unmarshal(data, &req)
+		request = req
+	case types.UpdateServiceJob:
+		var req models.UpdateServiceJobRequest
+		err = json.Unmarshal(data, &req)
+		request = req
+	case types.ScaleServiceJob:
+		var req models.ScaleServiceJobRequest
+		err = json.Unmarshal(data, &req)
+		request = req
+	case types.WorkflowJob:
+		var req models.CreateWorkflowRequest
+		err = json.Unmarshal(data, &req)
+		request = req
+	case types.CloneEnvironmentJob:
+		var req models.CloneEnvironmentRequest
+		err = json.Unmarshal(data, &req)
+		request = req
+	default:
+		return nil, fmt.Errorf("Unknown job type '%v'", jobType)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
@@ -0,0 +1,122 @@
+package logic
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
+	"github.com/quintilesims/layer0/common/types"
+)
+
+func TestValidateWorkflow(t *testing.T) {
+	req := models.CreateWorkflowRequest{
+		Nodes: []models.WorkflowNode{
+			{
+				Name:    "env",
+				JobType: "create environment",
+				Request: json.RawMessage(`{"environment_name":"prod"}`),
+			},
+			{
+				Name:      "svc",
+				JobType:   "create service",
+				Request:   json.RawMessage(`{"environment_id":"${env.environment_id}","deploy_id":"d1","service_name":"api"}`),
+				DependsOn: []string{"env"},
+			},
+		},
+	}
+
+	if err := ValidateWorkflow(req); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateWorkflowErrors(t *testing.T) {
+	node := func(name, jobType, request string, dependsOn ...string) models.WorkflowNode {
+		return models.WorkflowNode{
+			Name:      name,
+			JobType:   jobType,
+			Request:   json.RawMessage(request),
+			DependsOn: dependsOn,
+		}
+	}
+
+	cases := map[string]models.CreateWorkflowRequest{
+		"no nodes":       {},
+		"unknown policy": {OnFailure: "retry", Nodes: []models.WorkflowNode{node("a", "delete task", `"t1"`)}},
+		"invalid name":   {Nodes: []models.WorkflowNode{node("a.b", "delete task", `"t1"`)}},
+		"duplicate name": {Nodes: []models.WorkflowNode{node("a", "delete task", `"t1"`), node("a", "delete task", `"t2"`)}},
+		"unknown type":   {Nodes: []models.WorkflowNode{node("a", "make coffee", `"t1"`)}},
+		"nested":         {Nodes: []models.WorkflowNode{node("a", "workflow", `{}`)}},
+		"bad request":    {Nodes: []models.WorkflowNode{node("a", "delete task", `{}`)}},
+		"unknown dep":    {Nodes: []models.WorkflowNode{node("a", "delete task", `"t1"`, "b")}},
+		"bad reference":  {Nodes: []models.WorkflowNode{node("a", "delete task", `"t1"`), node("b", "delete task", `"${a.task_id}"`)}},
+		"cycle": {Nodes: []models.WorkflowNode{
+			node("a", "delete task", `"t1"`, "c"),
+			node("b", "delete task", `"t2"`, "a"),
+			node("c", "delete task", `"t3"`, "b"),
+		}},
+	}
+
+	for name, req := range cases {
+		err := ValidateWorkflow(req)
+		if err, ok := err.(*errors.ServerError); !ok || err.Code != errors.InvalidWorkflow {
+			t.Errorf("%s: expected InvalidWorkflow error, got %v", name, err)
+		}
+	}
+}
+
+func TestFindWorkflowCycle(t *testing.T) {
+	nodes := []models.WorkflowNode{
+		{Name: "a"},
+		{Name: "b", DependsOn: []string{"a", "d"}},
+		{Name: "c", DependsOn: []string{"b"}},
+		{Name: "d", DependsOn: []string{"c"}},
+	}
+
+	testutils.AssertEqual(t, strings.Join(findWorkflowCycle(nodes), " -> "), "b -> d -> c -> b")
+	testutils.AssertEqual(t, findWorkflowCycle(nodes[:3]) == nil, true)
+}
+
+func TestResolveWorkflowReferences(t *testing.T) {
+	meta := map[string]map[string]string{
+		"env": {"environment_id": "e1"},
+		"lb":  {"load_balancer_id": "l\"1"},
+	}
+
+	request := json.RawMessage(`{"environment_id":"${env.environment_id}","load_balancer_id":"${lb.load_balancer_id}"}`)
+	resolved, err := ResolveWorkflowReferences(request, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var req models.CreateServiceRequest
+	if err := json.Unmarshal(resolved, &req); err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, req.EnvironmentID, "e1")
+	testutils.AssertEqual(t, req.LoadBalancerID, "l\"1")
+
+	if _, err := ResolveWorkflowReferences(json.RawMessage(`"${env.service_id}"`), meta); err == nil {
+		t.Fatal("Error was nil!")
+	}
+}
+
+func TestDecodeJobRequest(t *testing.T) {
+	request, err := DecodeJobRequest(types.DeleteServiceJob, []byte(`"s1"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, request, "s1")
+
+	request, err = DecodeJobRequest(types.ScaleServiceJob, []byte(`{"service_id":"s1","desired_count":2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, request, models.ScaleServiceJobRequest{ServiceID: "s1", DesiredCount: 2})
+}
@@ -3,7 +3,10 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/Sirupsen/logrus"
@@ -11,17 +14,22 @@ import (
 	"github.com/emicklei/go-restful/swagger"
 	"github.com/quintilesims/layer0/api/handlers"
 	"github.com/quintilesims/layer0/api/logic"
+	"github.com/quintilesims/layer0/api/openapi"
 	"github.com/quintilesims/layer0/common/aws/provider"
 	"github.com/quintilesims/layer0/common/config"
 	"github.com/quintilesims/layer0/common/logutils"
 	"github.com/quintilesims/layer0/common/startup"
+	"github.com/quintilesims/layer0/common/waitutils"
 )
 
 const (
-	SCALER_SLEEP_DURATION = time.Hour
+	SCALER_SLEEP_DURATION    = time.Hour
+	SCALER_SCHEDULE_INTERVAL = time.Second * 10
+	OPENAPI_DOCUMENT_VERSION = "2.0.0"
 )
 
-func setupRestful(lgc logic.Logic) {
+// webServices returns the routes served by the api, in registration order
+func webServices(lgc logic.Logic) []*restful.WebService {
 	adminLogic := logic.NewL0AdminLogic(lgc)
 	deployLogic := logic.NewL0DeployLogic(lgc)
 	environmentLogic := logic.NewL0EnvironmentLogic(lgc)
@@ -29,28 +37,58 @@ func setupRestful(lgc logic.Logic) {
 	loadBalancerLogic := logic.NewL0LoadBalancerLogic(lgc)
 	serviceLogic := logic.NewL0ServiceLogic(lgc)
 	taskLogic := logic.NewL0TaskLogic(lgc)
-	jobLogic := logic.NewL0JobLogic(lgc, taskLogic, deployLogic)
+	jobLogic := logic.NewL0JobLogic(lgc, taskLogic)
 
 	adminHandler := handlers.NewAdminHandler(adminLogic)
-	deployHandler := handlers.NewDeployHandler(deployLogic)
-	environmentHandler := handlers.NewEnvironmentHandler(environmentLogic, jobLogic)
+	// the list endpoints only return entities whose tags in the tag store match the 'selector' query parameter
+	deployHandler := handlers.NewDeployHandler(deployLogic, lgc.TagStore)
+	environmentHandler := handlers.NewEnvironmentHandler(environmentLogic, jobLogic, lgc.TagStore)
 	healthHandler := handlers.NewHealthHandler(healthLogic)
-	jobHandler := handlers.NewJobHandler(jobLogic)
-	loadBalancerHandler := handlers.NewLoadBalancerHandler(loadBalancerLogic, jobLogic)
-	serviceHandler := handlers.NewServiceHandler(serviceLogic, jobLogic)
+	jobHandler := handlers.NewJobHandler(jobLogic, lgc.TagStore)
+	loadBalancerHandler := handlers.NewLoadBalancerHandler(loadBalancerLogic, jobLogic, lgc.TagStore)
+	serviceHandler := handlers.NewServiceHandler(serviceLogic, jobLogic, lgc.TagStore)
 	tagHandler := handlers.NewTagHandler(lgc.TagStore)
-	taskHandler := handlers.NewTaskHandler(taskLogic, jobLogic)
+	taskHandler := handlers.NewTaskHandler(taskLogic, jobLogic, lgc.TagStore)
+
+	v2Handler := handlers.NewV2Handler(
+		deployLogic,
+		environmentLogic,
+		jobLogic,
+		loadBalancerLogic,
+		serviceLogic,
+		taskLogic,
+		lgc.TagStore)
+
+	return []*restful.WebService{
+		deployHandler.Routes(),
+		serviceHandler.Routes(),
+		environmentHandler.Routes(),
+		healthHandler.Routes(),
+		tagHandler.Routes(),
+		adminHandler.Routes(),
+		loadBalancerHandler.Routes(),
+		taskHandler.Routes(),
+		jobHandler.Routes(),
+		v2Handler.Routes(),
+	}
+}
+
+// openAPIDocument generates the OpenAPI 3 document for the specified routes.
+// The checked-in copy at api/openapi.json is compared against this in tests
+func openAPIDocument(services []*restful.WebService) *openapi.Document {
+	return openapi.Generate("Layer0 API", OPENAPI_DOCUMENT_VERSION, services...)
+}
+
+func setupRestful(lgc logic.Logic) {
+	services := webServices(lgc)
+	openAPIHandler := handlers.NewOpenAPIHandler(openAPIDocument(services))
 
 	restful.SetLogger(logutils.SilentLogger{})
-	restful.Add(deployHandler.Routes())
-	restful.Add(serviceHandler.Routes())
-	restful.Add(environmentHandler.Routes())
-	restful.Add(healthHandler.Routes())
-	restful.Add(tagHandler.Routes())
-	restful.Add(adminHandler.Routes())
-	restful.Add(loadBalancerHandler.Routes())
-	restful.Add(taskHandler.Routes())
-	restful.Add(jobHandler.Routes())
+	for _, service := range services {
+		restful.Add(service)
+	}
+
+	restful.Add(openAPIHandler.Routes())
 
 	restful.Filter(handlers.LogRequest)
 	restful.Filter(handlers.AddVersionHeader)
@@ -151,8 +189,7 @@ func main() {
 	setupRestful(*lgc)
 
 	taskLogic := logic.NewL0TaskLogic(*lgc)
-	deployLogic := logic.NewL0DeployLogic(*lgc)
-	jobLogic := logic.NewL0JobLogic(*lgc, taskLogic, deployLogic)
+	jobLogic := logic.NewL0JobLogic(*lgc, taskLogic)
 	environmentLogic := logic.NewL0EnvironmentLogic(*lgc)
 	adminLogic := logic.NewL0AdminLogic(*lgc)
 
@@ -160,9 +197,25 @@ func main() {
 		logrus.Errorf("Failed to update sql: %v", err)
 	}
 
-	jobJanitor := logic.NewJobJanitor(jobLogic)
-	tagJanitor := logic.NewTagJanitor(taskLogic, lgc.TagStore)
-	go runEnvironmentScaler(environmentLogic)
+	// the janitors and the scaler loop only run in the replica that holds the leader lease
+	leader, err := startup.GetLeaderElector()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	// the first election finishes before the workers below start, so the leader starts working right away
+	leader.Run()
+	go resignOnShutdown(leader)
+
+	jobJanitor := logic.NewJobJanitor(jobLogic, lgc.JobArchive, logic.NewJobRetentionFromConfig())
+	jobJanitor.Leader = leader
+
+	tagJanitor := logic.NewTagJanitor(taskLogic, lgc.Backend, lgc.TagStore)
+	tagJanitor.DryRun = config.TagJanitorDryRun()
+	tagJanitor.Leader = leader
+
+	go runEnvironmentScaler(environmentLogic, leader)
+	go runScheduledScalerRuns(environmentLogic, leader)
 
 	logrus.Infof("Starting Job Janitor")
 	jobJanitor.Run()
@@ -174,17 +227,37 @@ func main() {
 	logrus.Fatal(http.ListenAndServe(port, nil))
 }
 
-func runEnvironmentScaler(environmentLogic *logic.L0EnvironmentLogic) {
+// resignOnShutdown releases the leader lease when the api is stopped,
+// so another replica can take over the background workers right away
+func resignOnShutdown(leader *logic.LeaderElector) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	<-signals
+
+	logrus.Infof("Shutting down")
+	if err := leader.Resign(); err != nil {
+		logrus.Errorf("Failed to release the leader lease: %v", err)
+	}
+
+	os.Exit(0)
+}
+
+func runEnvironmentScaler(environmentLogic *logic.L0EnvironmentLogic, leader logic.Leader) {
 	logger := logutils.NewStandardLogger("AUTO Environment Scaler")
 
-	for {
+	logic.RunAsLeader(leader, waitutils.RealClock{}, SCALER_SLEEP_DURATION, func() {
 		environments, err := environmentLogic.ListEnvironments()
 		if err != nil {
 			logger.Errorf("Failed to list environments: %v", err)
-			continue
+			return
 		}
 
 		for _, environment := range environments {
+			// a replica that lost the lease stops, so it doesn't scale alongside the new leader
+			if !leader.IsLeader() {
+				return
+			}
+
 			logger.Infof("Scaling Environment %s", environment.EnvironmentID)
 
 			if _, err := environmentLogic.Scaler.Scale(environment.EnvironmentID); err != nil {
@@ -194,7 +267,16 @@ func runEnvironmentScaler(environmentLogic *logic.L0EnvironmentLogic) {
 
 			logger.Infof("Finished scaling environment %s", environment.EnvironmentID)
 		}
+	})
+}
 
-		time.Sleep(SCALER_SLEEP_DURATION)
-	}
+// runScheduledScalerRuns makes the runs scheduled by every replica and runner, e.g. after a service is scaled
+func runScheduledScalerRuns(environmentLogic *logic.L0EnvironmentLogic, leader logic.Leader) {
+	logger := logutils.NewStandardLogger("Scheduled Environment Scaler")
+
+	logic.RunAsLeader(leader, waitutils.RealClock{}, SCALER_SCHEDULE_INTERVAL, func() {
+		if err := environmentLogic.Scaler.RunScheduled(leader.IsLeader); err != nil {
+			logger.Errorf("Failed to run scheduled scaler runs: %v", err)
+		}
+	})
 }
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -11,9 +15,12 @@ import (
 	"github.com/emicklei/go-restful"
 	"github.com/quintilesims/layer0/api/backend/ecs"
 	"github.com/quintilesims/layer0/api/logic"
+	"github.com/quintilesims/layer0/api/openapi"
 	"github.com/quintilesims/layer0/common/config"
 )
 
+var updateOpenAPI = flag.Bool("update", false, "regenerate openapi.json")
+
 // Main test entrypoint
 func TestMain(m *testing.M) {
 	config.SetTestConfig()
@@ -42,4 +49,51 @@ func TestAPIDocs(t *testing.T) {
 			t.Errorf("Apidoc should list path %s", e)
 		}
 	}
+
+	httpRequest, _ = http.NewRequest("GET", "/openapi.json", nil)
+	httpWriter = httptest.NewRecorder()
+
+	restful.DefaultContainer.ServeHTTP(httpWriter, httpRequest)
+
+	if httpWriter.Code != 200 {
+		t.Errorf("Expected Return Code 200 from openapi.json")
+	}
+
+	var document openapi.Document
+	if err := json.Unmarshal(httpWriter.Body.Bytes(), &document); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := document.Paths["/v2/services/{id}"]; !ok {
+		t.Errorf("openapi.json should list path /v2/services/{id}")
+	}
+}
+
+// TestOpenAPIDocument fails if openapi.json is out of date with the routes.
+// Run 'make openapi' to regenerate it
+func TestOpenAPIDocument(t *testing.T) {
+	logic := logic.NewLogic(nil, nil, &ecsbackend.ECSBackend{}, nil)
+	document := openAPIDocument(webServices(*logic))
+
+	generated, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generated = append(generated, '\n')
+
+	if *updateOpenAPI {
+		if err := ioutil.WriteFile("openapi.json", generated, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	current, err := ioutil.ReadFile("openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(current, generated) {
+		t.Errorf("openapi.json is out of date, run 'make openapi' to regenerate it")
+	}
 }
@@ -0,0 +1,253 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful"
+)
+
+const MIME_JSON = "application/json"
+
+// Generate builds an OpenAPI 3 document from the routes registered on the specified web services.
+// Request and response models are taken from each route's Reads and Returns samples.
+func Generate(title, version string, services ...*restful.WebService) *Document {
+	g := &generator{
+		document: &Document{
+			OpenAPI: VERSION,
+			Info: Info{
+				Title:   title,
+				Version: version,
+			},
+			Paths: map[string]PathItem{},
+			Components: Components{
+				Schemas: map[string]*Schema{},
+				SecuritySchemes: map[string]SecurityScheme{
+					"basicAuth": {Type: "http", Scheme: "basic"},
+				},
+			},
+			Security: []map[string][]string{
+				{"basicAuth": {}},
+			},
+		},
+		operationIDs: map[string]bool{},
+	}
+
+	for _, service := range services {
+		for _, route := range service.Routes() {
+			g.addRoute(service.RootPath(), route)
+		}
+	}
+
+	return g.document
+}
+
+type generator struct {
+	document     *Document
+	operationIDs map[string]bool
+}
+
+func (g *generator) addRoute(rootPath string, route restful.Route) {
+	path := route.Path
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+
+	item, ok := g.document.Paths[path]
+	if !ok {
+		item = PathItem{}
+		g.document.Paths[path] = item
+	}
+
+	operation := &Operation{
+		OperationID: g.operationID(rootPath, route.Operation),
+		Summary:     route.Doc,
+		Description: route.Notes,
+		Tags:        []string{strings.TrimPrefix(rootPath, "/")},
+		Responses:   map[string]Response{},
+	}
+
+	for _, param := range route.ParameterDocs {
+		data := param.Data()
+
+		var in string
+		switch data.Kind {
+		case restful.PathParameterKind:
+			in = "path"
+		case restful.QueryParameterKind:
+			in = "query"
+		case restful.HeaderParameterKind:
+			in = "header"
+		default:
+			// body parameters are described by the request body
+			continue
+		}
+
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:        data.Name,
+			In:          in,
+			Description: data.Description,
+			Required:    data.Required || in == "path",
+			Schema:      parameterSchema(data.DataType),
+		})
+	}
+
+	if route.ReadSample != nil {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				MIME_JSON: {Schema: g.schemaFor(reflect.TypeOf(route.ReadSample))},
+			},
+		}
+	}
+
+	hasSuccess := false
+	for code, responseError := range route.ResponseErrors {
+		if code >= 200 && code < 300 {
+			hasSuccess = true
+		}
+
+		operation.Responses[strconv.Itoa(code)] = g.response(responseError.Message, responseError.Model)
+	}
+
+	if !hasSuccess {
+		operation.Responses["200"] = g.response("OK", route.WriteSample)
+	}
+
+	item[strings.ToLower(route.Method)] = operation
+}
+
+// operationID returns a unique id for the operation. Operations that share
+// a name with an earlier route are prefixed with their web service's root path, e.g. "V2GetService"
+func (g *generator) operationID(rootPath, operation string) string {
+	id := operation
+	if g.operationIDs[id] {
+		prefix := strings.Replace(strings.Title(strings.Replace(rootPath, "_", " ", -1)), " ", "", -1)
+		id = strings.Replace(prefix, "/", "", -1) + operation
+	}
+
+	g.operationIDs[id] = true
+	return id
+}
+
+func (g *generator) response(description string, model interface{}) Response {
+	response := Response{Description: description}
+	if model != nil {
+		response.Content = map[string]MediaType{
+			MIME_JSON: {Schema: g.schemaFor(reflect.TypeOf(model))},
+		}
+	}
+
+	return response
+}
+
+func parameterSchema(dataType string) *Schema {
+	switch dataType {
+	case "int", "integer", "int64":
+		return &Schema{Type: "integer"}
+	case "bool", "boolean":
+		return &Schema{Type: "boolean"}
+	default:
+		return &Schema{Type: "string"}
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaFor returns the schema for t. Named structs are added to the
+// document's components and referenced by name
+func (g *generator) schemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+
+		name := t.Name()
+		if _, ok := g.document.Components.Schemas[name]; !ok {
+			// reserve the name before recursing in case the struct references itself
+			g.document.Components.Schemas[name] = &Schema{}
+			g.document.Components.Schemas[name] = g.structSchema(t)
+		}
+
+		return &Schema{Ref: fmt.Sprintf("#/components/schemas/%s", name)}
+	default:
+		// interface{} and other dynamic types accept any value
+		return &Schema{}
+	}
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{},
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		name := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			tagName := strings.Split(tag, ",")[0]
+			if tagName == "-" {
+				continue
+			}
+
+			if tagName != "" {
+				name = tagName
+			}
+		} else if field.Anonymous {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				for key, property := range g.structSchema(embedded).Properties {
+					schema.Properties[key] = property
+				}
+
+				continue
+			}
+		}
+
+		schema.Properties[name] = g.schemaFor(field.Type)
+	}
+
+	return schema
+}
@@ -0,0 +1,110 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+type testModel struct {
+	ID       string            `json:"id"`
+	Count    int64             `json:"count"`
+	Created  time.Time         `json:"created"`
+	Children []testChild       `json:"children"`
+	Labels   map[string]string `json:"labels"`
+	Any      interface{}       `json:"any"`
+	Ignored  string            `json:"-"`
+	hidden   string
+}
+
+type testChild struct {
+	Name *string `json:"name,omitempty"`
+}
+
+func noop(request *restful.Request, response *restful.Response) {}
+
+func testServices() []*restful.WebService {
+	v1 := new(restful.WebService)
+	v1.Path("/model")
+	v1.Route(v1.GET("{id}").
+		To(noop).
+		Operation("GetModel").
+		Doc("Return a model").
+		Param(v1.PathParameter("id", "identifier of the model")).
+		Writes(testModel{}))
+
+	v1.Route(v1.PUT("{id}").
+		To(noop).
+		Operation("UpdateModel").
+		Param(v1.PathParameter("id", "identifier of the model")).
+		Param(v1.HeaderParameter("If-Match", "version").DataType("string")).
+		Reads(testChild{}).
+		Returns(http.StatusOK, "OK", testModel{}).
+		Returns(http.StatusConflict, "Version conflict", nil))
+
+	v2 := new(restful.WebService)
+	v2.Path("/v2")
+	v2.Route(v2.GET("/models/{id}").
+		To(noop).
+		Operation("GetModel").
+		Param(v2.PathParameter("id", "identifier of the model")).
+		Returns(http.StatusOK, "OK", []testModel{}))
+
+	return []*restful.WebService{v1, v2}
+}
+
+func TestGeneratePaths(t *testing.T) {
+	reporter := testutils.NewReporter(t, "TestGeneratePaths")
+	document := Generate("test", "1", testServices()...)
+
+	get := document.Paths["/model/{id}"]["get"]
+	reporter.AssertEqual(get.OperationID, "GetModel")
+	reporter.AssertEqual(get.Summary, "Return a model")
+	reporter.AssertEqual(get.Parameters, []Parameter{
+		{Name: "id", In: "path", Description: "identifier of the model", Required: true, Schema: &Schema{Type: "string"}},
+	})
+	reporter.AssertEqual(get.Responses["200"].Content[MIME_JSON].Schema, &Schema{Ref: "#/components/schemas/testModel"})
+
+	put := document.Paths["/model/{id}"]["put"]
+	reporter.AssertEqual(len(put.Parameters), 2)
+	reporter.AssertEqual(put.Parameters[1].In, "header")
+	reporter.AssertEqual(put.RequestBody.Content[MIME_JSON].Schema, &Schema{Ref: "#/components/schemas/testChild"})
+	reporter.AssertEqual(put.Responses["409"], Response{Description: "Version conflict"})
+
+	v2 := document.Paths["/v2/models/{id}"]["get"]
+	reporter.AssertEqual(v2.OperationID, "V2GetModel")
+	reporter.AssertEqual(v2.Responses["200"].Content[MIME_JSON].Schema, &Schema{
+		Type:  "array",
+		Items: &Schema{Ref: "#/components/schemas/testModel"},
+	})
+}
+
+func TestGenerateSchemas(t *testing.T) {
+	reporter := testutils.NewReporter(t, "TestGenerateSchemas")
+	document := Generate("test", "1", testServices()...)
+
+	expected := map[string]*Schema{
+		"testModel": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"id":       {Type: "string"},
+				"count":    {Type: "integer", Format: "int64"},
+				"created":  {Type: "string", Format: "date-time"},
+				"children": {Type: "array", Items: &Schema{Ref: "#/components/schemas/testChild"}},
+				"labels":   {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+				"any":      {},
+			},
+		},
+		"testChild": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"name": {Type: "string"},
+			},
+		},
+	}
+
+	reporter.AssertEqual(document.Components.Schemas, expected)
+}
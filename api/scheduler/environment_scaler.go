@@ -9,65 +9,129 @@ import (
 	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/logutils"
 	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/waitutils"
+	"github.com/zpatrick/go-bytesize"
 )
 
 type EnvironmentScaler interface {
+	History(environmentID string, limit int) ([]*models.ScalerRun, error)
 	Scale(environmentID string) (*models.ScalerRunInfo, error)
 	ScheduleRun(environmentID string, delay time.Duration)
+	RunScheduled(keepRunning func() bool) error
+	Simulate(environmentID string, req models.ScalerSimulationRequest) (*models.ScalerRunInfo, error)
+}
+
+type ScalerSettingsGetter interface {
+	GetScalerSettings(environmentID string) (models.ScalerSettings, error)
+}
+
+// ScheduledRunStore keeps the time of each environment's next scheduled run.
+// Runs are stored rather than kept in timers, so a run scheduled by any replica of the api,
+// or by a runner, is made by the replica that calls RunScheduled: the leader
+type ScheduledRunStore interface {
+	// ScheduleScalerRun stores the time of the environment's next run, unless an earlier run is already stored
+	ScheduleScalerRun(environmentID string, at time.Time) error
+	ListScheduledScalerRuns() (map[string]time.Time, error)
+	DeleteScheduledScalerRun(environmentID string) error
 }
 
 type L0EnvironmentScaler struct {
 	consumerGetter  resource.ConsumerGetter
 	providerManager resource.ProviderManager
-	scheduledRuns   map[string]chan time.Duration
+	strategyGetter  PlacementStrategyGetter
+	settingsGetter  ScalerSettingsGetter
+	runStore        ScheduledRunStore
+	history         ScalerHistory
+	clock           waitutils.Clock
 	logger          *logrus.Logger
 }
 
-func NewL0EnvironmentScaler(c resource.ConsumerGetter, p resource.ProviderManager) *L0EnvironmentScaler {
+func NewL0EnvironmentScaler(c resource.ConsumerGetter, p resource.ProviderManager, s PlacementStrategyGetter, g ScalerSettingsGetter, rs ScheduledRunStore, h ScalerHistory) *L0EnvironmentScaler {
 	return &L0EnvironmentScaler{
 		consumerGetter:  c,
 		providerManager: p,
-		scheduledRuns:   map[string]chan time.Duration{},
+		strategyGetter:  s,
+		settingsGetter:  g,
+		runStore:        rs,
+		history:         h,
+		clock:           waitutils.RealClock{},
 		logger:          logutils.NewStandardLogger("Environment Scaler").Logger,
 	}
 }
 
+// ScheduleRun schedules a run of the scaler for the environment after the delay.
+// If an earlier run is already scheduled for the environment, that run is kept instead.
+// The run is made by the next call to RunScheduled after it is due
 func (r *L0EnvironmentScaler) ScheduleRun(environmentID string, delay time.Duration) {
-	if c, ok := r.scheduledRuns[environmentID]; ok {
-		c <- delay
-		return
-	}
+	r.logger.Debugf("Scaling environment '%s' in %v", environmentID, delay)
 
-	c := r.newScheduledRun(environmentID, delay)
-	r.scheduledRuns[environmentID] = c
+	if err := r.runStore.ScheduleScalerRun(environmentID, r.clock.Now().Add(delay)); err != nil {
+		r.logger.Errorf("Failed to schedule a run for environment %s: %v", environmentID, err)
+	}
 }
 
-func (r *L0EnvironmentScaler) newScheduledRun(environmentID string, delay time.Duration) chan time.Duration {
-	c := make(chan time.Duration)
+// RunScheduled scales each environment whose scheduled run is due.
+// It stops before the next environment once keepRunning returns false
+func (r *L0EnvironmentScaler) RunScheduled(keepRunning func() bool) error {
+	runs, err := r.runStore.ListScheduledScalerRuns()
+	if err != nil {
+		return err
+	}
 
-	go func() {
-		for shouldContinue := true; shouldContinue; {
-			r.logger.Debugf("Scaling environment '%s' in %v", environmentID, delay)
-			select {
-			case delay = <-c:
-				r.logger.Debugf("New delay set for environment '%s'", environmentID)
-			case <-time.After(delay):
-				delete(r.scheduledRuns, environmentID)
-				close(c)
-				shouldContinue = false
-			}
+	now := r.clock.Now()
+	for environmentID, at := range runs {
+		if at.After(now) {
+			continue
+		}
+
+		if !keepRunning() {
+			return nil
+		}
+
+		// the run is deleted first, so a run scheduled while this one is made isn't lost
+		if err := r.runStore.DeleteScheduledScalerRun(environmentID); err != nil {
+			r.logger.Errorf("Failed to delete the scheduled run for environment %s: %v", environmentID, err)
+			continue
 		}
 
 		r.logger.Debugf("Scaling environment '%s' now", environmentID)
 		if _, err := r.Scale(environmentID); err != nil {
 			r.logger.Errorf("There was an error scaling environment %s: %v", environmentID, err)
 		}
-	}()
+	}
 
-	return c
+	return nil
 }
 
+// Scale runs the scaler for the environment and records the run in the scaler history
 func (r *L0EnvironmentScaler) Scale(environmentID string) (*models.ScalerRunInfo, error) {
+	started := r.clock.Now()
+	info, err := r.scale(environmentID)
+
+	run := &models.ScalerRun{Time: started}
+	if info != nil {
+		run.ScalerRunInfo = *info
+	}
+
+	// runs that fail before the scaler has any info are still recorded
+	run.EnvironmentID = environmentID
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	// failing to record a run shouldn't fail the run itself
+	if err := r.history.Record(run); err != nil {
+		r.logger.Errorf("Failed to record scaler run for environment %s: %v", environmentID, err)
+	}
+
+	return info, err
+}
+
+func (r *L0EnvironmentScaler) History(environmentID string, limit int) ([]*models.ScalerRun, error) {
+	return r.history.List(environmentID, limit)
+}
+
+func (r *L0EnvironmentScaler) scale(environmentID string) (*models.ScalerRunInfo, error) {
 	resourceProviders, err := r.providerManager.GetProviders(environmentID)
 	if err != nil {
 		return nil, err
@@ -78,7 +142,82 @@ func (r *L0EnvironmentScaler) Scale(environmentID string) (*models.ScalerRunInfo
 		return nil, err
 	}
 
-	return RunBasicScaler(environmentID, resourceProviders, resourceConsumers, r.providerManager)
+	strategy, err := r.placementStrategy(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := r.scalerSettings(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := RunBasicScaler(environmentID, resourceProviders, resourceConsumers, r.providerManager, strategy, settings)
+	if err != nil {
+		return info, err
+	}
+
+	// the provider manager doesn't scale down during the cooldown,
+	// so check again once the rest of it has passed
+	if info.ActualScaleAfterRun > info.DesiredScaleAfterRun && settings.ScaleDownCooldown > 0 {
+		remaining, err := r.providerManager.ScaleDownCooldownRemaining(environmentID, settings.ScaleDownCooldown)
+		if err != nil {
+			r.logger.Errorf("Failed to get the scale down cooldown of environment %s: %v", environmentID, err)
+			remaining = settings.ScaleDownCooldown
+		}
+
+		if remaining > 0 {
+			r.ScheduleRun(environmentID, remaining)
+		}
+	}
+
+	return info, nil
+}
+
+// Simulate runs the scaler as if the changes in the request had been made to the environment.
+// The environment is not scaled; the returned run info shows the size it would be scaled to
+func (r *L0EnvironmentScaler) Simulate(environmentID string, req models.ScalerSimulationRequest) (*models.ScalerRunInfo, error) {
+	providerManager := resource.NewNoopProviderManager(r.providerManager)
+
+	resourceProviders, err := providerManager.GetProviders(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceConsumers, err := r.consumerGetter.GetSimulatedConsumers(environmentID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	strategy, err := r.placementStrategy(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := r.scalerSettings(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return RunBasicScaler(environmentID, resourceProviders, resourceConsumers, providerManager, strategy, settings)
+}
+
+func (r *L0EnvironmentScaler) placementStrategy(environmentID string) (PlacementStrategy, error) {
+	name, err := r.strategyGetter.GetPlacementStrategy(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPlacementStrategy(name)
+}
+
+func (r *L0EnvironmentScaler) scalerSettings(environmentID string) (resource.ScalerSettings, error) {
+	settings, err := r.settingsGetter.GetScalerSettings(environmentID)
+	if err != nil {
+		return resource.ScalerSettings{}, err
+	}
+
+	return resource.NewScalerSettings(settings)
 }
 
 func RunBasicScaler(
@@ -86,72 +225,112 @@ func RunBasicScaler(
 	providers []*resource.ResourceProvider,
 	consumers []resource.ResourceConsumer,
 	providerManager resource.ProviderManager,
+	strategy PlacementStrategy,
+	settings resource.ScalerSettings,
 ) (*models.ScalerRunInfo, error) {
 
 	scaleBeforeRun := len(providers)
+	initialProviders := resourceProviderModels(providers)
+	newProviders := 0
 	var errs []error
 
 	// check if we need to scale up
 	for _, consumer := range consumers {
-		hasRoom := false
+		if provider := strategy.Place(consumer, providers); provider != nil {
+			provider.SubtractResourcesFor(consumer)
+			continue
+		}
 
-		// first, sort by memory so we pack tasks by memory as tightly as possible
-		resource.SortProvidersByMemory(providers)
+		// none of the providers have room for the consumer, so we need to scale up
+		newProvider, err := providerManager.CalculateNewProvider(environmentID)
+		if err != nil {
+			return nil, err
+		}
 
-		// next, place any unused providers in the back of the list
-		// that way, we can can delete them if we avoid placing any tasks in them
-		resource.SortProvidersByUsage(providers)
+		if !newProvider.HasResourcesFor(consumer) {
+			text := fmt.Sprintf("Resource '%s' cannot fit into an empty provider!", consumer.ID)
+			text += "\nThe instance size in your environment is too small to run this resource."
+			text += "\nPlease increase the instance size for your environment"
+			err := fmt.Errorf(text)
+			errs = append(errs, err)
+			continue
+		}
 
-		for _, provider := range providers {
-			if provider.HasResourcesFor(consumer) {
-				hasRoom = true
-				provider.SubtractResourcesFor(consumer)
-				break
-			}
+		newProvider.SubtractResourcesFor(consumer)
+		providers = append(providers, newProvider)
+		newProviders++
+	}
+
+	// check if we need to scale down
+	unusedProviders := []*resource.ResourceProvider{}
+	for i := 0; i < len(providers); i++ {
+		if !providers[i].IsInUse() {
+			unusedProviders = append(unusedProviders, providers[i])
 		}
+	}
 
-		if !hasRoom {
+	desiredScale := len(providers) - len(unusedProviders)
+
+	// keep unused providers, or add new ones, until the environment has the desired headroom
+	spareMemory := bytesize.Bytesize(0)
+	for _, provider := range providers {
+		if provider.IsInUse() {
+			spareMemory += provider.AvailableMemory()
+		}
+	}
+
+	for headroom := 0; headroom < settings.HeadroomInstances || spareMemory < settings.HeadroomMemory; headroom++ {
+		if settings.HasMaxClusterCount() && desiredScale >= settings.MaxClusterCount {
+			break
+		}
+
+		var provider *resource.ResourceProvider
+		if len(unusedProviders) > 0 {
+			provider, unusedProviders = unusedProviders[0], unusedProviders[1:]
+		} else {
 			newProvider, err := providerManager.CalculateNewProvider(environmentID)
 			if err != nil {
 				return nil, err
 			}
 
-			if !newProvider.HasResourcesFor(consumer) {
-				text := fmt.Sprintf("Resource '%s' cannot fit into an empty provider!", consumer.ID)
-				text += "\nThe instance size in your environment is too small to run this resource."
-				text += "\nPlease increase the instance size for your environment"
-				err := fmt.Errorf(text)
-				errs = append(errs, err)
-				continue
-			}
+			provider = newProvider
+			providers = append(providers, provider)
+			newProviders++
+		}
 
-			newProvider.SubtractResourcesFor(consumer)
-			providers = append(providers, newProvider)
+		spareMemory += provider.AvailableMemory()
+		desiredScale++
+
+		// a provider without memory can't add memory headroom
+		if headroom >= settings.HeadroomInstances && provider.AvailableMemory() <= 0 {
+			break
 		}
 	}
 
-	// check if we need to scale down
-	unusedProviders := []*resource.ResourceProvider{}
-	for i := 0; i < len(providers); i++ {
-		if !providers[i].IsInUse() {
-			unusedProviders = append(unusedProviders, providers[i])
-		}
+	if settings.HasMaxClusterCount() && desiredScale > settings.MaxClusterCount {
+		err := fmt.Errorf("Environment '%s' needs %d instances, but its max cluster count is %d", environmentID, desiredScale, settings.MaxClusterCount)
+		errs = append(errs, err)
+		desiredScale = settings.MaxClusterCount
 	}
 
-	desiredScale := len(providers) - len(unusedProviders)
-	actualScale, err := providerManager.ScaleTo(environmentID, desiredScale, unusedProviders)
+	actualScale, terminatedProviders, err := providerManager.ScaleTo(environmentID, desiredScale, unusedProviders, settings)
 	if err != nil {
 		errs = append(errs, err)
 	}
 
 	info := &models.ScalerRunInfo{
-		EnvironmentID:           environmentID,
-		PendingResources:        resourceConsumerModels(consumers),
-		ResourceProviders:       resourceProviderModels(providers),
-		ScaleBeforeRun:          scaleBeforeRun,
-		DesiredScaleAfterRun:    desiredScale,
-		ActualScaleAfterRun:     actualScale,
-		UnusedResourceProviders: len(unusedProviders),
+		EnvironmentID:               environmentID,
+		PlacementStrategy:           strategy.Name(),
+		ScalerSettings:              settings.ToModel(),
+		PendingResources:            resourceConsumerModels(consumers),
+		InitialResourceProviders:    initialProviders,
+		ResourceProviders:           resourceProviderModels(providers),
+		ScaleBeforeRun:              scaleBeforeRun,
+		DesiredScaleAfterRun:        desiredScale,
+		ActualScaleAfterRun:         actualScale,
+		UnusedResourceProviders:     len(unusedProviders),
+		NewResourceProviders:        newProviders,
+		TerminatedResourceProviders: terminatedProviders,
 	}
 
 	return info, errors.MultiError(errs)
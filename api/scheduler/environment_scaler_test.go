@@ -1,26 +1,36 @@
 package scheduler
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/quintilesims/layer0/api/scheduler/mock_scheduler"
 	"github.com/quintilesims/layer0/api/scheduler/resource"
 	"github.com/quintilesims/layer0/api/scheduler/resource/mock_resource"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
 	"github.com/zpatrick/go-bytesize"
 )
 
 type MockProviderManager struct {
 	*mock_resource.MockProviderManager
 	MemoryPerProvider bytesize.Bytesize
+	CPUPerProvider    int
 }
 
 func (m *MockProviderManager) CalculateNewProvider(environmentID string) (*resource.ResourceProvider, error) {
-	return resource.NewResourceProvider("", false, m.MemoryPerProvider, nil), nil
+	return resource.NewResourceProvider("", false, m.MemoryPerProvider, m.CPUPerProvider, nil), nil
 }
 
 type EnvironmentScalerUnitTest struct {
 	ExpectedScale     int
+	ExpectError       bool
 	MemoryPerProvider bytesize.Bytesize
+	CPUPerProvider    int
+	PlacementStrategy string
+	ScalerSettings    models.ScalerSettings
 	ResourceProviders []*resource.ResourceProvider
 	ResourceConsumers []resource.ResourceConsumer
 }
@@ -37,6 +47,7 @@ func (e *EnvironmentScalerUnitTest) Run(t *testing.T) {
 	mockProvider := &MockProviderManager{
 		mock_resource.NewMockProviderManager(ctrl),
 		e.MemoryPerProvider,
+		e.CPUPerProvider,
 	}
 
 	mockProvider.EXPECT().
@@ -44,14 +55,49 @@ func (e *EnvironmentScalerUnitTest) Run(t *testing.T) {
 		Return(e.ResourceProviders, nil)
 
 	mockProvider.EXPECT().
-		ScaleTo("eid", e.ExpectedScale, gomock.Any()).
-		Return(0, nil)
+		ScaleTo("eid", e.ExpectedScale, gomock.Any(), gomock.Any()).
+		Return(0, nil, nil)
 
-	environmentScaler := NewL0EnvironmentScaler(mockGetter, mockProvider)
+	mockStrategyGetter := mock_scheduler.NewMockPlacementStrategyGetter(ctrl)
+	mockStrategyGetter.EXPECT().
+		GetPlacementStrategy("eid").
+		Return(e.PlacementStrategy, nil)
 
-	if _, err := environmentScaler.Scale("eid"); err != nil {
+	mockSettingsGetter := mock_scheduler.NewMockScalerSettingsGetter(ctrl)
+	mockSettingsGetter.EXPECT().
+		GetScalerSettings("eid").
+		Return(e.ScalerSettings, nil)
+
+	mockHistory := mock_scheduler.NewMockScalerHistory(ctrl)
+	mockHistory.EXPECT().
+		Record(gomock.Any()).
+		Return(nil)
+
+	// runs that can't scale down during the cooldown schedule another run
+	mockRunStore := mock_scheduler.NewMockScheduledRunStore(ctrl)
+	mockRunStore.EXPECT().
+		ScheduleScalerRun("eid", gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	environmentScaler := NewL0EnvironmentScaler(mockGetter, mockProvider, mockStrategyGetter, mockSettingsGetter, mockRunStore, mockHistory)
+
+	runInfo, err := environmentScaler.Scale("eid")
+	if e.ExpectError {
+		if err == nil {
+			t.Fatal("Error was nil!")
+		}
+
+		return
+	}
+
+	if err != nil {
 		t.Fatal(err)
 	}
+
+	if e.PlacementStrategy != "" && runInfo.PlacementStrategy != e.PlacementStrategy {
+		t.Fatalf("Placement strategy was '%s', expected '%s'", runInfo.PlacementStrategy, e.PlacementStrategy)
+	}
 }
 
 func TestResourceManagerScaleUp_noProviders(t *testing.T) {
@@ -78,7 +124,7 @@ func TestResourceManagerScaleUp_notEnoughPorts(t *testing.T) {
 		ExpectedScale:     2,
 		MemoryPerProvider: bytesize.MB,
 		ResourceProviders: []*resource.ResourceProvider{
-			resource.NewResourceProvider("", true, bytesize.MB, []int{80}),
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, []int{80}),
 		},
 		ResourceConsumers: []resource.ResourceConsumer{
 			{Ports: []int{80}},
@@ -98,11 +144,11 @@ func TestResourceManagerScaleUp_notEnoughPortsComplex(t *testing.T) {
 		ExpectedScale:     6,
 		MemoryPerProvider: bytesize.MB,
 		ResourceProviders: []*resource.ResourceProvider{
-			resource.NewResourceProvider("", true, bytesize.MB, []int{8000, 8001, 8002}),
-			resource.NewResourceProvider("", true, bytesize.MB, []int{8000, 8001, 8002}),
-			resource.NewResourceProvider("", true, bytesize.MB, []int{8000, 8001, 8002}),
-			resource.NewResourceProvider("", true, bytesize.MB, []int{8000, 8001}),
-			resource.NewResourceProvider("", true, bytesize.MB, []int{8000}),
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, []int{8000, 8001, 8002}),
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, []int{8000, 8001, 8002}),
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, []int{8000, 8001, 8002}),
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, []int{8000, 8001}),
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, []int{8000}),
 		},
 		ResourceConsumers: []resource.ResourceConsumer{
 			// these 3 consumers can be placed in the current cluster
@@ -132,7 +178,7 @@ func TestResourceManagerScaleUp_notEnoughMemory(t *testing.T) {
 		ExpectedScale:     2,
 		MemoryPerProvider: bytesize.MB * 4,
 		ResourceProviders: []*resource.ResourceProvider{
-			resource.NewResourceProvider("", true, bytesize.MB, nil),
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, nil),
 		},
 		ResourceConsumers: []resource.ResourceConsumer{
 			{Memory: bytesize.MB * 2},
@@ -149,8 +195,8 @@ func TestResourceManagerScaleUp_notEnoughMemoryOnASingleProvider(t *testing.T) {
 		ExpectedScale:     3,
 		MemoryPerProvider: bytesize.MB * 4,
 		ResourceProviders: []*resource.ResourceProvider{
-			resource.NewResourceProvider("", true, bytesize.MB*1, nil),
-			resource.NewResourceProvider("", true, bytesize.MB*2, nil),
+			resource.NewResourceProvider("", true, bytesize.MB*1, 1024, nil),
+			resource.NewResourceProvider("", true, bytesize.MB*2, 1024, nil),
 		},
 		ResourceConsumers: []resource.ResourceConsumer{
 			{Memory: bytesize.MB * 3},
@@ -169,11 +215,11 @@ func TestResourceManagerScaleUp_notEnoughMemoryComplex(t *testing.T) {
 		ExpectedScale:     6,
 		MemoryPerProvider: bytesize.MB * 4,
 		ResourceProviders: []*resource.ResourceProvider{
-			resource.NewResourceProvider("", true, bytesize.MB, nil),
-			resource.NewResourceProvider("", true, bytesize.MB, nil),
-			resource.NewResourceProvider("", true, bytesize.MB, nil),
-			resource.NewResourceProvider("", true, bytesize.MB*2, nil),
-			resource.NewResourceProvider("", true, bytesize.MB*3, nil),
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, nil),
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, nil),
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, nil),
+			resource.NewResourceProvider("", true, bytesize.MB*2, 1024, nil),
+			resource.NewResourceProvider("", true, bytesize.MB*3, 1024, nil),
 		},
 		ResourceConsumers: []resource.ResourceConsumer{
 			// these 4 consumers can be placed in the current cluster
@@ -203,8 +249,8 @@ func TestResourceManagerScaleUp_notEnoughPortsOrMemory(t *testing.T) {
 		ExpectedScale:     4,
 		MemoryPerProvider: bytesize.MB * 2,
 		ResourceProviders: []*resource.ResourceProvider{
-			resource.NewResourceProvider("", true, bytesize.MB, []int{80}),
-			resource.NewResourceProvider("", true, bytesize.MB, []int{80}),
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, []int{80}),
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, []int{80}),
 		},
 		ResourceConsumers: []resource.ResourceConsumer{
 			// this consumer will require a new provider for ports
@@ -229,8 +275,8 @@ func TestResourceManagerNoScale_noResourceConsumers(t *testing.T) {
 		ExpectedScale:     2,
 		MemoryPerProvider: bytesize.MB,
 		ResourceProviders: []*resource.ResourceProvider{
-			resource.NewResourceProvider("", true, bytesize.MB, []int{80}),
-			resource.NewResourceProvider("", true, bytesize.MB*0.5, nil),
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, []int{80}),
+			resource.NewResourceProvider("", true, bytesize.MB*0.5, 1024, nil),
 		},
 		ResourceConsumers: []resource.ResourceConsumer{},
 	}
@@ -245,9 +291,9 @@ func TestResourceManagerNoScale_enoughPorts(t *testing.T) {
 		ExpectedScale:     3,
 		MemoryPerProvider: bytesize.MB,
 		ResourceProviders: []*resource.ResourceProvider{
-			resource.NewResourceProvider("", true, bytesize.MB, []int{8000, 8001, 8002}),
-			resource.NewResourceProvider("", true, bytesize.MB, []int{8000, 8001}),
-			resource.NewResourceProvider("", true, bytesize.MB, []int{8000}),
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, []int{8000, 8001, 8002}),
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, []int{8000, 8001}),
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, []int{8000}),
 		},
 		ResourceConsumers: []resource.ResourceConsumer{
 			{Ports: []int{8001}},
@@ -268,9 +314,9 @@ func TestResourceManagerNoScale_enoughMemory(t *testing.T) {
 		ExpectedScale:     3,
 		MemoryPerProvider: bytesize.MB * 4,
 		ResourceProviders: []*resource.ResourceProvider{
-			resource.NewResourceProvider("", true, bytesize.MB, nil),
-			resource.NewResourceProvider("", true, bytesize.MB*2, nil),
-			resource.NewResourceProvider("", true, bytesize.MB*3, nil),
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, nil),
+			resource.NewResourceProvider("", true, bytesize.MB*2, 1024, nil),
+			resource.NewResourceProvider("", true, bytesize.MB*3, 1024, nil),
 		},
 		ResourceConsumers: []resource.ResourceConsumer{
 			{Memory: bytesize.MB},
@@ -291,9 +337,9 @@ func TestResourceManagerNoScale_enoughMemoryAndPorts(t *testing.T) {
 		ExpectedScale:     3,
 		MemoryPerProvider: bytesize.MB * 4,
 		ResourceProviders: []*resource.ResourceProvider{
-			resource.NewResourceProvider("", true, bytesize.MB*1, []int{8000, 8001, 8002}),
-			resource.NewResourceProvider("", true, bytesize.MB*3, []int{8000, 8001}),
-			resource.NewResourceProvider("", true, bytesize.MB*2, []int{8000}),
+			resource.NewResourceProvider("", true, bytesize.MB*1, 1024, []int{8000, 8001, 8002}),
+			resource.NewResourceProvider("", true, bytesize.MB*3, 1024, []int{8000, 8001}),
+			resource.NewResourceProvider("", true, bytesize.MB*2, 1024, []int{8000}),
 		},
 		ResourceConsumers: []resource.ResourceConsumer{
 			// note that if we place this consumer in the 2nd provider, we would fail
@@ -307,6 +353,69 @@ func TestResourceManagerNoScale_enoughMemoryAndPorts(t *testing.T) {
 
 	test.Run(t)
 }
+func TestResourceManagerScaleUp_notEnoughCPU(t *testing.T) {
+	// there is 1 provider in the cluster that has plenty of memory but only 512 cpu units left
+	// there are 2 consumers that each need 512 cpu units
+	// we should scale up to size 2
+	test := EnvironmentScalerUnitTest{
+		ExpectedScale:     2,
+		MemoryPerProvider: bytesize.GB,
+		CPUPerProvider:    1024,
+		ResourceProviders: []*resource.ResourceProvider{
+			resource.NewResourceProvider("", true, bytesize.GB, 512, nil),
+		},
+		ResourceConsumers: []resource.ResourceConsumer{
+			{Memory: bytesize.MB, CPU: 512},
+			{Memory: bytesize.MB, CPU: 512},
+		},
+	}
+
+	test.Run(t)
+}
+
+func TestResourceManagerScaleUp_packsByCPU(t *testing.T) {
+	// there are 2 providers in the cluster with the same memory
+	// the first has 1024 cpu units left, the second has 512
+	// there are 2 consumers: the first needs 512 cpu units, the second needs 1024
+	// the first consumer should be packed into the second provider, so no scaling is needed
+	test := EnvironmentScalerUnitTest{
+		ExpectedScale:     2,
+		MemoryPerProvider: bytesize.GB,
+		CPUPerProvider:    1024,
+		ResourceProviders: []*resource.ResourceProvider{
+			resource.NewResourceProvider("", true, bytesize.GB, 1024, nil),
+			resource.NewResourceProvider("", true, bytesize.GB, 512, nil),
+		},
+		ResourceConsumers: []resource.ResourceConsumer{
+			{Memory: bytesize.MB, CPU: 512},
+			{Memory: bytesize.MB, CPU: 1024},
+		},
+	}
+
+	test.Run(t)
+}
+
+func TestResourceManagerScaleUp_spreadInstance(t *testing.T) {
+	// there are 2 providers in the cluster: one is in use, the other isn't
+	// there are 2 copies of the same container, which both fit into the used provider
+	// the spread-instance strategy places the second copy in the unused provider, so we stay at size 2
+	test := EnvironmentScalerUnitTest{
+		ExpectedScale:     2,
+		MemoryPerProvider: bytesize.GB,
+		PlacementStrategy: SpreadInstancePlacement,
+		ResourceProviders: []*resource.ResourceProvider{
+			resource.NewResourceProvider("", true, bytesize.GB, 1024, nil),
+			resource.NewResourceProvider("", false, bytesize.GB, 1024, nil),
+		},
+		ResourceConsumers: []resource.ResourceConsumer{
+			{Group: "svc", Memory: bytesize.MB},
+			{Group: "svc", Memory: bytesize.MB},
+		},
+	}
+
+	test.Run(t)
+}
+
 func TestResourceManagerScaledown_noConsumers(t *testing.T) {
 	// there is 1 provider in the cluster that isn't in use
 	// there are 0 consumers
@@ -315,7 +424,7 @@ func TestResourceManagerScaledown_noConsumers(t *testing.T) {
 		ExpectedScale:     0,
 		MemoryPerProvider: bytesize.MB,
 		ResourceProviders: []*resource.ResourceProvider{
-			resource.NewResourceProvider("", false, bytesize.MB, nil),
+			resource.NewResourceProvider("", false, bytesize.MB, 1024, nil),
 		},
 		ResourceConsumers: []resource.ResourceConsumer{},
 	}
@@ -331,11 +440,11 @@ func TestResourceManagerScaledown_complex(t *testing.T) {
 		ExpectedScale:     3,
 		MemoryPerProvider: bytesize.MB * 4,
 		ResourceProviders: []*resource.ResourceProvider{
-			resource.NewResourceProvider("", false, bytesize.MB*4, nil),
-			resource.NewResourceProvider("", false, bytesize.MB*4, nil),
-			resource.NewResourceProvider("", true, bytesize.MB*2, []int{8000}),
-			resource.NewResourceProvider("", true, bytesize.MB*2, []int{8001}),
-			resource.NewResourceProvider("", true, bytesize.MB*2, []int{8002}),
+			resource.NewResourceProvider("", false, bytesize.MB*4, 1024, nil),
+			resource.NewResourceProvider("", false, bytesize.MB*4, 1024, nil),
+			resource.NewResourceProvider("", true, bytesize.MB*2, 1024, []int{8000}),
+			resource.NewResourceProvider("", true, bytesize.MB*2, 1024, []int{8001}),
+			resource.NewResourceProvider("", true, bytesize.MB*2, 1024, []int{8002}),
 		},
 		ResourceConsumers: []resource.ResourceConsumer{
 			{Memory: bytesize.MB, Ports: []int{8000}},
@@ -346,3 +455,346 @@ func TestResourceManagerScaledown_complex(t *testing.T) {
 
 	test.Run(t)
 }
+
+func TestResourceManagerHeadroom_instances(t *testing.T) {
+	// there are 3 providers in the cluster
+	// 2 are not in use
+	// the environment should keep 1 empty provider
+	// we should scale to size 2
+	test := EnvironmentScalerUnitTest{
+		ExpectedScale:     2,
+		MemoryPerProvider: bytesize.MB,
+		ScalerSettings:    models.ScalerSettings{HeadroomInstances: 1},
+		ResourceProviders: []*resource.ResourceProvider{
+			resource.NewResourceProvider("", false, bytesize.MB, 1024, nil),
+			resource.NewResourceProvider("", false, bytesize.MB, 1024, nil),
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, nil),
+		},
+		ResourceConsumers: []resource.ResourceConsumer{},
+	}
+
+	test.Run(t)
+}
+
+func TestResourceManagerHeadroom_memory(t *testing.T) {
+	// there is 1 provider in the cluster with 1MiB of memory left
+	// the environment should keep 3MiB of memory unused
+	// we should scale up to size 2
+	test := EnvironmentScalerUnitTest{
+		ExpectedScale:     2,
+		MemoryPerProvider: bytesize.MiB * 2,
+		ScalerSettings:    models.ScalerSettings{HeadroomMemory: 3},
+		ResourceProviders: []*resource.ResourceProvider{
+			resource.NewResourceProvider("", true, bytesize.MiB, 1024, nil),
+		},
+		ResourceConsumers: []resource.ResourceConsumer{},
+	}
+
+	test.Run(t)
+}
+
+func TestResourceManagerHeadroom_maxClusterCount(t *testing.T) {
+	// there is 1 provider in the cluster
+	// the environment should keep 2 empty providers, but has a max cluster count of 2
+	// we should scale up to size 2
+	test := EnvironmentScalerUnitTest{
+		ExpectedScale:     2,
+		MemoryPerProvider: bytesize.MB,
+		ScalerSettings:    models.ScalerSettings{HeadroomInstances: 2, MaxClusterCount: 2},
+		ResourceProviders: []*resource.ResourceProvider{
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, nil),
+		},
+		ResourceConsumers: []resource.ResourceConsumer{},
+	}
+
+	test.Run(t)
+}
+
+func TestResourceManagerScaleUp_maxClusterCount(t *testing.T) {
+	// there are 0 providers in the cluster
+	// there are 3 consumers that each need their own provider
+	// the environment has a max cluster count of 2
+	// we should scale up to size 2 and return an error
+	test := EnvironmentScalerUnitTest{
+		ExpectedScale:     2,
+		ExpectError:       true,
+		MemoryPerProvider: bytesize.MB,
+		ScalerSettings:    models.ScalerSettings{MaxClusterCount: 2},
+		ResourceProviders: []*resource.ResourceProvider{},
+		ResourceConsumers: []resource.ResourceConsumer{
+			{Memory: bytesize.MB},
+			{Memory: bytesize.MB},
+			{Memory: bytesize.MB},
+		},
+	}
+
+	test.Run(t)
+}
+
+func TestEnvironmentScalerSimulate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	req := models.ScalerSimulationRequest{
+		Tasks: []models.SimulatedTask{{DeployID: "did", Copies: 2}},
+	}
+
+	// there is 1 provider in the cluster with room for 1 of the simulated consumers
+	// we should simulate scaling up to size 2 without calling ScaleTo
+	mockGetter := mock_resource.NewMockConsumerGetter(ctrl)
+	mockGetter.EXPECT().
+		GetSimulatedConsumers("eid", req).
+		Return([]resource.ResourceConsumer{{Memory: bytesize.MB}, {Memory: bytesize.MB}}, nil)
+
+	mockProvider := &MockProviderManager{
+		mock_resource.NewMockProviderManager(ctrl),
+		bytesize.MB,
+		1024,
+	}
+
+	mockProvider.EXPECT().
+		GetProviders("eid").
+		Return([]*resource.ResourceProvider{
+			resource.NewResourceProvider("", true, bytesize.MB, 1024, nil),
+		}, nil)
+
+	mockStrategyGetter := mock_scheduler.NewMockPlacementStrategyGetter(ctrl)
+	mockStrategyGetter.EXPECT().
+		GetPlacementStrategy("eid").
+		Return("", nil)
+
+	mockSettingsGetter := mock_scheduler.NewMockScalerSettingsGetter(ctrl)
+	mockSettingsGetter.EXPECT().
+		GetScalerSettings("eid").
+		Return(models.ScalerSettings{}, nil)
+
+	// simulated runs aren't recorded
+	mockHistory := mock_scheduler.NewMockScalerHistory(ctrl)
+
+	environmentScaler := NewL0EnvironmentScaler(mockGetter, mockProvider, mockStrategyGetter, mockSettingsGetter, nil, mockHistory)
+
+	runInfo, err := environmentScaler.Simulate("eid", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, runInfo.ScaleBeforeRun, 1)
+	testutils.AssertEqual(t, runInfo.DesiredScaleAfterRun, 2)
+	testutils.AssertEqual(t, runInfo.ActualScaleAfterRun, 2)
+}
+
+func TestEnvironmentScalerScaleRecordsRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// there is 1 used provider with no room left and 1 unused provider
+	// there is 1 consumer, so a new provider is needed and the unused provider is terminated
+	mockGetter := mock_resource.NewMockConsumerGetter(ctrl)
+	mockGetter.EXPECT().
+		GetConsumers("eid").
+		Return([]resource.ResourceConsumer{{Memory: bytesize.GB}}, nil)
+
+	mockProvider := &MockProviderManager{
+		mock_resource.NewMockProviderManager(ctrl),
+		bytesize.GB * 2,
+		1024,
+	}
+
+	mockProvider.EXPECT().
+		GetProviders("eid").
+		Return([]*resource.ResourceProvider{
+			resource.NewResourceProvider("i1", true, 0, 1024, nil),
+			resource.NewResourceProvider("i2", false, bytesize.MB, 1024, nil),
+		}, nil)
+
+	mockProvider.EXPECT().
+		ScaleTo("eid", 2, gomock.Any(), gomock.Any()).
+		Return(2, []string{"i2"}, nil)
+
+	mockStrategyGetter := mock_scheduler.NewMockPlacementStrategyGetter(ctrl)
+	mockStrategyGetter.EXPECT().
+		GetPlacementStrategy("eid").
+		Return("", nil)
+
+	mockSettingsGetter := mock_scheduler.NewMockScalerSettingsGetter(ctrl)
+	mockSettingsGetter.EXPECT().
+		GetScalerSettings("eid").
+		Return(models.ScalerSettings{}, nil)
+
+	var run *models.ScalerRun
+	mockHistory := mock_scheduler.NewMockScalerHistory(ctrl)
+	mockHistory.EXPECT().
+		Record(gomock.Any()).
+		Do(func(r *models.ScalerRun) { run = r }).
+		Return(nil)
+
+	environmentScaler := NewL0EnvironmentScaler(mockGetter, mockProvider, mockStrategyGetter, mockSettingsGetter, nil, mockHistory)
+	clock := &testutils.StubClock{Time: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)}
+	environmentScaler.clock = clock
+
+	if _, err := environmentScaler.Scale("eid"); err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, run.EnvironmentID, "eid")
+	testutils.AssertEqual(t, run.Time, clock.Time)
+	testutils.AssertEqual(t, run.Error, "")
+	testutils.AssertEqual(t, len(run.InitialResourceProviders), 2)
+	testutils.AssertEqual(t, run.NewResourceProviders, 1)
+	testutils.AssertEqual(t, run.TerminatedResourceProviders, []string{"i2"})
+	testutils.AssertEqual(t, run.ActualScaleAfterRun, 2)
+}
+
+func TestEnvironmentScalerScaleSchedulesRemainingCooldown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// there is 1 unused provider, but the environment is in its scale down cooldown
+	mockGetter := mock_resource.NewMockConsumerGetter(ctrl)
+	mockGetter.EXPECT().
+		GetConsumers("eid").
+		Return([]resource.ResourceConsumer{}, nil)
+
+	mockProvider := mock_resource.NewMockProviderManager(ctrl)
+	mockProvider.EXPECT().
+		GetProviders("eid").
+		Return([]*resource.ResourceProvider{
+			resource.NewResourceProvider("i1", false, bytesize.GB, 1024, nil),
+		}, nil)
+
+	mockProvider.EXPECT().
+		ScaleTo("eid", 0, gomock.Any(), gomock.Any()).
+		Return(1, nil, nil)
+
+	mockProvider.EXPECT().
+		ScaleDownCooldownRemaining("eid", time.Minute*5).
+		Return(time.Minute*2, nil)
+
+	mockStrategyGetter := mock_scheduler.NewMockPlacementStrategyGetter(ctrl)
+	mockStrategyGetter.EXPECT().
+		GetPlacementStrategy("eid").
+		Return("", nil)
+
+	mockSettingsGetter := mock_scheduler.NewMockScalerSettingsGetter(ctrl)
+	mockSettingsGetter.EXPECT().
+		GetScalerSettings("eid").
+		Return(models.ScalerSettings{ScaleDownCooldown: 300}, nil)
+
+	mockHistory := mock_scheduler.NewMockScalerHistory(ctrl)
+	mockHistory.EXPECT().
+		Record(gomock.Any()).
+		Return(nil)
+
+	// the next run is scheduled for when the rest of the cooldown has passed, not a whole cooldown later
+	mockRunStore := mock_scheduler.NewMockScheduledRunStore(ctrl)
+	mockRunStore.EXPECT().
+		ScheduleScalerRun("eid", gomock.Any()).
+		Do(func(environmentID string, at time.Time) {
+			if delay := at.Sub(time.Now()); delay > time.Minute*2 || delay < time.Minute {
+				t.Errorf("Run was scheduled in %v, expected 2m", delay)
+			}
+		}).
+		Return(nil)
+
+	environmentScaler := NewL0EnvironmentScaler(mockGetter, mockProvider, mockStrategyGetter, mockSettingsGetter, mockRunStore, mockHistory)
+	if _, err := environmentScaler.Scale("eid"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnvironmentScalerScaleRecordsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProvider := mock_resource.NewMockProviderManager(ctrl)
+	mockProvider.EXPECT().
+		GetProviders("eid").
+		Return(nil, fmt.Errorf("some error"))
+
+	var run *models.ScalerRun
+	mockHistory := mock_scheduler.NewMockScalerHistory(ctrl)
+	mockHistory.EXPECT().
+		Record(gomock.Any()).
+		Do(func(r *models.ScalerRun) { run = r }).
+		Return(nil)
+
+	environmentScaler := NewL0EnvironmentScaler(nil, mockProvider, nil, nil, nil, mockHistory)
+	if _, err := environmentScaler.Scale("eid"); err == nil {
+		t.Fatal("Error was nil!")
+	}
+
+	testutils.AssertEqual(t, run.EnvironmentID, "eid")
+	testutils.AssertEqual(t, run.Error, "some error")
+}
+
+func TestEnvironmentScalerScheduleRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the stub clock moves forward 20ms each time it is read
+	clock := &testutils.StubClock{Time: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)}
+	mockRunStore := mock_scheduler.NewMockScheduledRunStore(ctrl)
+	mockRunStore.EXPECT().
+		ScheduleScalerRun("eid", clock.Time.Add(time.Second*10+time.Millisecond*20)).
+		Return(nil)
+
+	environmentScaler := NewL0EnvironmentScaler(nil, nil, nil, nil, mockRunStore, nil)
+	environmentScaler.clock = clock
+
+	environmentScaler.ScheduleRun("eid", time.Second*10)
+}
+
+func TestEnvironmentScalerRunScheduled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clock := &testutils.StubClock{Time: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)}
+	mockRunStore := mock_scheduler.NewMockScheduledRunStore(ctrl)
+	mockRunStore.EXPECT().
+		ListScheduledScalerRuns().
+		Return(map[string]time.Time{
+			"due":    clock.Time.Add(-time.Second),
+			"future": clock.Time.Add(time.Second),
+		}, nil)
+
+	// only the due run is made, and it is deleted before the environment is scaled
+	mockProvider := mock_resource.NewMockProviderManager(ctrl)
+	mockHistory := mock_scheduler.NewMockScalerHistory(ctrl)
+	gomock.InOrder(
+		mockRunStore.EXPECT().
+			DeleteScheduledScalerRun("due").
+			Return(nil),
+		mockProvider.EXPECT().
+			GetProviders("due").
+			Return(nil, fmt.Errorf("some error")),
+		mockHistory.EXPECT().
+			Record(gomock.Any()).
+			Return(nil),
+	)
+
+	environmentScaler := NewL0EnvironmentScaler(nil, mockProvider, nil, nil, mockRunStore, mockHistory)
+	environmentScaler.clock = clock
+
+	if err := environmentScaler.RunScheduled(func() bool { return true }); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnvironmentScalerRunScheduledStopsWhenNotLeader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clock := &testutils.StubClock{Time: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)}
+	mockRunStore := mock_scheduler.NewMockScheduledRunStore(ctrl)
+	mockRunStore.EXPECT().
+		ListScheduledScalerRuns().
+		Return(map[string]time.Time{"due": clock.Time}, nil)
+
+	// the run is left for the new leader
+	environmentScaler := NewL0EnvironmentScaler(nil, nil, nil, nil, mockRunStore, nil)
+	environmentScaler.clock = clock
+
+	if err := environmentScaler.RunScheduled(func() bool { return false }); err != nil {
+		t.Fatal(err)
+	}
+}
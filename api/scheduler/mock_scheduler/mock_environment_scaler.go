@@ -34,6 +34,19 @@ func (m *MockEnvironmentScaler) EXPECT() *MockEnvironmentScalerMockRecorder {
 	return m.recorder
 }
 
+// History mocks base method
+func (m *MockEnvironmentScaler) History(arg0 string, arg1 int) ([]*models.ScalerRun, error) {
+	ret := m.ctrl.Call(m, "History", arg0, arg1)
+	ret0, _ := ret[0].([]*models.ScalerRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History
+func (mr *MockEnvironmentScalerMockRecorder) History(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockEnvironmentScaler)(nil).History), arg0, arg1)
+}
+
 // Scale mocks base method
 func (m *MockEnvironmentScaler) Scale(arg0 string) (*models.ScalerRunInfo, error) {
 	ret := m.ctrl.Call(m, "Scale", arg0)
@@ -47,6 +60,18 @@ func (mr *MockEnvironmentScalerMockRecorder) Scale(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scale", reflect.TypeOf((*MockEnvironmentScaler)(nil).Scale), arg0)
 }
 
+// RunScheduled mocks base method
+func (m *MockEnvironmentScaler) RunScheduled(arg0 func() bool) error {
+	ret := m.ctrl.Call(m, "RunScheduled", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunScheduled indicates an expected call of RunScheduled
+func (mr *MockEnvironmentScalerMockRecorder) RunScheduled(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunScheduled", reflect.TypeOf((*MockEnvironmentScaler)(nil).RunScheduled), arg0)
+}
+
 // ScheduleRun mocks base method
 func (m *MockEnvironmentScaler) ScheduleRun(arg0 string, arg1 time.Duration) {
 	m.ctrl.Call(m, "ScheduleRun", arg0, arg1)
@@ -56,3 +81,16 @@ func (m *MockEnvironmentScaler) ScheduleRun(arg0 string, arg1 time.Duration) {
 func (mr *MockEnvironmentScalerMockRecorder) ScheduleRun(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleRun", reflect.TypeOf((*MockEnvironmentScaler)(nil).ScheduleRun), arg0, arg1)
 }
+
+// Simulate mocks base method
+func (m *MockEnvironmentScaler) Simulate(arg0 string, arg1 models.ScalerSimulationRequest) (*models.ScalerRunInfo, error) {
+	ret := m.ctrl.Call(m, "Simulate", arg0, arg1)
+	ret0, _ := ret[0].(*models.ScalerRunInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Simulate indicates an expected call of Simulate
+func (mr *MockEnvironmentScalerMockRecorder) Simulate(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Simulate", reflect.TypeOf((*MockEnvironmentScaler)(nil).Simulate), arg0, arg1)
+}
@@ -0,0 +1,142 @@
+package scheduler
+
+import (
+	"github.com/quintilesims/layer0/api/scheduler/resource"
+	"github.com/quintilesims/layer0/common/errors"
+)
+
+const (
+	BinpackPlacement        = "binpack"
+	SpreadInstancePlacement = "spread-instance"
+	SpreadZonePlacement     = "spread-zone"
+)
+
+// PlacementStrategies holds the names of the valid placement strategies
+var PlacementStrategies = []string{
+	BinpackPlacement,
+	SpreadInstancePlacement,
+	SpreadZonePlacement,
+}
+
+// PlacementStrategy decides which provider each consumer is placed in during a scaler run
+type PlacementStrategy interface {
+	Name() string
+	// Place returns the provider the consumer should be placed in,
+	// or nil if none of the providers have resources for it
+	Place(consumer resource.ResourceConsumer, providers []*resource.ResourceProvider) *resource.ResourceProvider
+}
+
+type PlacementStrategyGetter interface {
+	// GetPlacementStrategy returns the name of the environment's placement strategy;
+	// an empty name means the default, binpack
+	GetPlacementStrategy(environmentID string) (string, error)
+}
+
+// NewPlacementStrategy returns the placement strategy with the specified name
+func NewPlacementStrategy(name string) (PlacementStrategy, error) {
+	switch name {
+	case "", BinpackPlacement:
+		return BinpackStrategy{}, nil
+	case SpreadInstancePlacement:
+		return SpreadInstanceStrategy{}, nil
+	case SpreadZonePlacement:
+		return SpreadZoneStrategy{}, nil
+	default:
+		return nil, errors.Newf(errors.InvalidPlacementStrategy, "Unknown placement strategy '%s': must be one of %v", name, PlacementStrategies)
+	}
+}
+
+// BinpackStrategy packs consumers into as few providers as possible
+type BinpackStrategy struct{}
+
+func (BinpackStrategy) Name() string {
+	return BinpackPlacement
+}
+
+func (BinpackStrategy) Place(consumer resource.ResourceConsumer, providers []*resource.ResourceProvider) *resource.ResourceProvider {
+	sortProvidersForBinpack(providers)
+
+	for _, provider := range providers {
+		if provider.HasResourcesFor(consumer) {
+			return provider
+		}
+	}
+
+	return nil
+}
+
+// SpreadInstanceStrategy places each consumer in the provider with the fewest consumers of the same group.
+// Ties are broken the same way as the binpack strategy
+type SpreadInstanceStrategy struct{}
+
+func (SpreadInstanceStrategy) Name() string {
+	return SpreadInstancePlacement
+}
+
+func (SpreadInstanceStrategy) Place(consumer resource.ResourceConsumer, providers []*resource.ResourceProvider) *resource.ResourceProvider {
+	return placeWithFewest(consumer, providers, func(provider *resource.ResourceProvider) []int {
+		return []int{provider.Placed(consumer.Group)}
+	})
+}
+
+// SpreadZoneStrategy places each consumer in the availability zone with the fewest consumers of the same group,
+// and then in the provider within that zone with the fewest consumers of the same group.
+// Providers that haven't been created yet have an unknown availability zone, which is treated as a zone of its own
+type SpreadZoneStrategy struct{}
+
+func (SpreadZoneStrategy) Name() string {
+	return SpreadZonePlacement
+}
+
+func (SpreadZoneStrategy) Place(consumer resource.ResourceConsumer, providers []*resource.ResourceProvider) *resource.ResourceProvider {
+	placedInZone := map[string]int{}
+	for _, provider := range providers {
+		placedInZone[provider.AvailabilityZone] += provider.Placed(consumer.Group)
+	}
+
+	return placeWithFewest(consumer, providers, func(provider *resource.ResourceProvider) []int {
+		return []int{placedInZone[provider.AvailabilityZone], provider.Placed(consumer.Group)}
+	})
+}
+
+// placeWithFewest returns the provider with resources for the consumer that has the lowest counts,
+// compared in order; providers with equal counts are chosen in binpack order
+func placeWithFewest(consumer resource.ResourceConsumer, providers []*resource.ResourceProvider, counts func(*resource.ResourceProvider) []int) *resource.ResourceProvider {
+	sortProvidersForBinpack(providers)
+
+	var best *resource.ResourceProvider
+	var bestCounts []int
+	for _, provider := range providers {
+		if !provider.HasResourcesFor(consumer) {
+			continue
+		}
+
+		if c := counts(provider); best == nil || lessCounts(c, bestCounts) {
+			best = provider
+			bestCounts = c
+		}
+	}
+
+	return best
+}
+
+func lessCounts(a, b []int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+
+	return false
+}
+
+func sortProvidersForBinpack(providers []*resource.ResourceProvider) {
+	// first, sort by cpu and then by memory so we pack tasks as tightly as possible;
+	// the sorts are stable, so providers with the same memory stay ordered by cpu
+	resource.SortProvidersByCPU(providers)
+	resource.SortProvidersByMemory(providers)
+
+	// next, place any unused providers in the back of the list
+	// that way, we can can delete them if we avoid placing any tasks in them
+	resource.SortProvidersByUsage(providers)
+}
@@ -0,0 +1,129 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/quintilesims/layer0/api/scheduler/resource"
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/testutils"
+	"github.com/zpatrick/go-bytesize"
+)
+
+func newZoneProvider(id, zone string, inUse bool) *resource.ResourceProvider {
+	provider := resource.NewResourceProvider(id, inUse, bytesize.GB, 1024, nil)
+	provider.AvailabilityZone = zone
+	return provider
+}
+
+func placeAll(strategy PlacementStrategy, consumers []resource.ResourceConsumer, providers []*resource.ResourceProvider) []string {
+	placed := make([]string, len(consumers))
+	for i, consumer := range consumers {
+		provider := strategy.Place(consumer, providers)
+		if provider == nil {
+			continue
+		}
+
+		provider.SubtractResourcesFor(consumer)
+		placed[i] = provider.ID
+	}
+
+	return placed
+}
+
+func TestNewPlacementStrategy(t *testing.T) {
+	for name, expected := range map[string]string{
+		"":                "binpack",
+		"binpack":         "binpack",
+		"spread-instance": "spread-instance",
+		"spread-zone":     "spread-zone",
+	} {
+		strategy, err := NewPlacementStrategy(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		testutils.AssertEqual(t, strategy.Name(), expected)
+	}
+
+	_, err := NewPlacementStrategy("random")
+	if err, ok := err.(*errors.ServerError); !ok || err.Code != errors.InvalidPlacementStrategy {
+		t.Fatalf("Expected InvalidPlacementStrategy error, got %v", err)
+	}
+}
+
+func TestBinpackStrategy(t *testing.T) {
+	providers := []*resource.ResourceProvider{
+		newZoneProvider("p1", "a", true),
+		newZoneProvider("p2", "b", true),
+	}
+
+	consumers := []resource.ResourceConsumer{
+		{Group: "svc", Memory: bytesize.MB},
+		{Group: "svc", Memory: bytesize.MB},
+		{Group: "svc", Memory: bytesize.GB},
+	}
+
+	// the first two copies are packed into the same provider; the last one doesn't fit there anymore
+	placed := placeAll(BinpackStrategy{}, consumers, providers)
+	testutils.AssertEqual(t, placed[0], placed[1])
+	testutils.AssertEqual(t, placed[2] != placed[0], true)
+}
+
+func TestSpreadInstanceStrategy(t *testing.T) {
+	providers := []*resource.ResourceProvider{
+		newZoneProvider("p1", "a", true),
+		newZoneProvider("p2", "a", true),
+		newZoneProvider("p3", "b", false),
+	}
+
+	consumers := []resource.ResourceConsumer{
+		{Group: "svc", Memory: bytesize.MB},
+		{Group: "svc", Memory: bytesize.MB},
+		{Group: "svc", Memory: bytesize.MB},
+		{Group: "other", Memory: bytesize.MB},
+	}
+
+	placed := placeAll(SpreadInstanceStrategy{}, consumers, providers)
+
+	// each copy of svc is placed in a different provider
+	testutils.AssertEqual(t, len(map[string]bool{placed[0]: true, placed[1]: true, placed[2]: true}), 3)
+
+	// copies of other groups are still packed into used providers
+	testutils.AssertEqual(t, placed[3] != "p3", true)
+}
+
+func TestSpreadZoneStrategy(t *testing.T) {
+	providers := []*resource.ResourceProvider{
+		newZoneProvider("p1", "a", true),
+		newZoneProvider("p2", "a", true),
+		newZoneProvider("p3", "b", false),
+	}
+
+	consumers := []resource.ResourceConsumer{
+		{Group: "svc", Memory: bytesize.MB},
+		{Group: "svc", Memory: bytesize.MB},
+		{Group: "svc", Memory: bytesize.MB},
+	}
+
+	placed := placeAll(SpreadZoneStrategy{}, consumers, providers)
+
+	zones := map[string]string{"p1": "a", "p2": "a", "p3": "b"}
+	testutils.AssertEqual(t, zones[placed[0]] != zones[placed[1]], true)
+
+	// the third copy goes back to zone a, but in the provider that doesn't have a copy yet
+	testutils.AssertEqual(t, zones[placed[2]], "a")
+	testutils.AssertEqual(t, len(map[string]bool{placed[0]: true, placed[1]: true, placed[2]: true}), 3)
+}
+
+func TestSpreadStrategyNoRoom(t *testing.T) {
+	providers := []*resource.ResourceProvider{
+		newZoneProvider("p1", "a", true),
+	}
+
+	consumer := resource.ResourceConsumer{Memory: bytesize.GB * 2}
+	for _, strategy := range []PlacementStrategy{BinpackStrategy{}, SpreadInstanceStrategy{}, SpreadZoneStrategy{}} {
+		if provider := strategy.Place(consumer, providers); provider != nil {
+			t.Errorf("%s: expected no provider, got %s", strategy.Name(), provider.ID)
+		}
+	}
+}
@@ -7,18 +7,25 @@ import (
 
 type ConsumerGetter interface {
 	GetConsumers(environmentID string) ([]ResourceConsumer, error)
+	// GetSimulatedConsumers returns the consumers the environment would have after the changes in the request
+	GetSimulatedConsumers(environmentID string, req models.ScalerSimulationRequest) ([]ResourceConsumer, error)
 }
 
 type ResourceConsumer struct {
-	ID     string
+	ID string
+	// Group identifies copies of the same container, e.g. the copies of a service's container
+	Group  string
 	Memory bytesize.Bytesize
-	Ports  []int
+	// CPU is the number of ECS CPU units the consumer reserves; 1024 units is one vCPU
+	CPU   int
+	Ports []int
 }
 
-func NewResourceConsumer(id string, memory bytesize.Bytesize, ports []int) ResourceConsumer {
+func NewResourceConsumer(id string, memory bytesize.Bytesize, cpu int, ports []int) ResourceConsumer {
 	return ResourceConsumer{
 		ID:     id,
 		Memory: memory,
+		CPU:    cpu,
 		Ports:  ports,
 	}
 }
@@ -26,7 +33,9 @@ func NewResourceConsumer(id string, memory bytesize.Bytesize, ports []int) Resou
 func (r ResourceConsumer) ToModel() models.ResourceConsumer {
 	return models.ResourceConsumer{
 		ID:     r.ID,
+		Group:  r.Group,
 		Memory: r.Memory.Format("mib"),
+		CPU:    r.CPU,
 		Ports:  r.Ports,
 	}
 }
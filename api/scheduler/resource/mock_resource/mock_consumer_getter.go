@@ -7,6 +7,7 @@ package mock_resource
 import (
 	gomock "github.com/golang/mock/gomock"
 	resource "github.com/quintilesims/layer0/api/scheduler/resource"
+	models "github.com/quintilesims/layer0/common/models"
 	reflect "reflect"
 )
 
@@ -45,3 +46,16 @@ func (m *MockConsumerGetter) GetConsumers(arg0 string) ([]resource.ResourceConsu
 func (mr *MockConsumerGetterMockRecorder) GetConsumers(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConsumers", reflect.TypeOf((*MockConsumerGetter)(nil).GetConsumers), arg0)
 }
+
+// GetSimulatedConsumers mocks base method
+func (m *MockConsumerGetter) GetSimulatedConsumers(arg0 string, arg1 models.ScalerSimulationRequest) ([]resource.ResourceConsumer, error) {
+	ret := m.ctrl.Call(m, "GetSimulatedConsumers", arg0, arg1)
+	ret0, _ := ret[0].([]resource.ResourceConsumer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSimulatedConsumers indicates an expected call of GetSimulatedConsumers
+func (mr *MockConsumerGetterMockRecorder) GetSimulatedConsumers(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSimulatedConsumers", reflect.TypeOf((*MockConsumerGetter)(nil).GetSimulatedConsumers), arg0, arg1)
+}
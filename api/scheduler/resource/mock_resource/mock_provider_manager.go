@@ -8,6 +8,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	resource "github.com/quintilesims/layer0/api/scheduler/resource"
 	reflect "reflect"
+	time "time"
 )
 
 // MockProviderManager is a mock of ProviderManager interface
@@ -59,15 +60,29 @@ func (mr *MockProviderManagerMockRecorder) GetProviders(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProviders", reflect.TypeOf((*MockProviderManager)(nil).GetProviders), arg0)
 }
 
-// ScaleTo mocks base method
-func (m *MockProviderManager) ScaleTo(arg0 string, arg1 int, arg2 []*resource.ResourceProvider) (int, error) {
-	ret := m.ctrl.Call(m, "ScaleTo", arg0, arg1, arg2)
-	ret0, _ := ret[0].(int)
+// ScaleDownCooldownRemaining mocks base method
+func (m *MockProviderManager) ScaleDownCooldownRemaining(arg0 string, arg1 time.Duration) (time.Duration, error) {
+	ret := m.ctrl.Call(m, "ScaleDownCooldownRemaining", arg0, arg1)
+	ret0, _ := ret[0].(time.Duration)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// ScaleDownCooldownRemaining indicates an expected call of ScaleDownCooldownRemaining
+func (mr *MockProviderManagerMockRecorder) ScaleDownCooldownRemaining(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScaleDownCooldownRemaining", reflect.TypeOf((*MockProviderManager)(nil).ScaleDownCooldownRemaining), arg0, arg1)
+}
+
+// ScaleTo mocks base method
+func (m *MockProviderManager) ScaleTo(arg0 string, arg1 int, arg2 []*resource.ResourceProvider, arg3 resource.ScalerSettings) (int, []string, error) {
+	ret := m.ctrl.Call(m, "ScaleTo", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // ScaleTo indicates an expected call of ScaleTo
-func (mr *MockProviderManagerMockRecorder) ScaleTo(arg0, arg1, arg2 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScaleTo", reflect.TypeOf((*MockProviderManager)(nil).ScaleTo), arg0, arg1, arg2)
+func (mr *MockProviderManagerMockRecorder) ScaleTo(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScaleTo", reflect.TypeOf((*MockProviderManager)(nil).ScaleTo), arg0, arg1, arg2, arg3)
 }
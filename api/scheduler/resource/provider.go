@@ -3,6 +3,7 @@ package resource
 import (
 	"errors"
 	"sort"
+	"time"
 
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/zpatrick/go-bytesize"
@@ -11,17 +12,42 @@ import (
 type ProviderManager interface {
 	CalculateNewProvider(environmentID string) (*ResourceProvider, error)
 	GetProviders(environmentID string) ([]*ResourceProvider, error)
-	ScaleTo(environmentID string, size int, unusedProviders []*ResourceProvider) (int, error)
+	// ScaleTo returns the size of the environment after scaling,
+	// and the ids of the unused providers that were terminated
+	ScaleTo(environmentID string, size int, unusedProviders []*ResourceProvider, settings ScalerSettings) (int, []string, error)
+	// ScaleDownCooldownRemaining returns how long ScaleTo won't scale the environment down for
+	ScaleDownCooldownRemaining(environmentID string, cooldown time.Duration) (time.Duration, error)
+}
+
+// NoopProviderManager gets providers from the wrapped manager, but never changes the size of an environment.
+// It is used to simulate scaler runs
+type NoopProviderManager struct {
+	ProviderManager
+}
+
+func NewNoopProviderManager(p ProviderManager) *NoopProviderManager {
+	return &NoopProviderManager{
+		ProviderManager: p,
+	}
+}
+
+// ScaleTo returns the specified size as if the environment had been scaled to it
+func (n *NoopProviderManager) ScaleTo(environmentID string, size int, unusedProviders []*ResourceProvider, settings ScalerSettings) (int, []string, error) {
+	return size, nil, nil
 }
 
 type ResourceProvider struct {
-	ID              string
-	inUse           bool
-	usedPorts       []int
-	availableMemory bytesize.Bytesize
+	ID               string
+	AvailabilityZone string
+	inUse            bool
+	usedPorts        []int
+	availableMemory  bytesize.Bytesize
+	availableCPU     int
+	// placed counts the consumers of each group that have been placed in the provider
+	placed map[string]int
 }
 
-func NewResourceProvider(id string, inUse bool, availableMemory bytesize.Bytesize, usedPorts []int) *ResourceProvider {
+func NewResourceProvider(id string, inUse bool, availableMemory bytesize.Bytesize, availableCPU int, usedPorts []int) *ResourceProvider {
 	if usedPorts == nil {
 		usedPorts = []int{}
 	}
@@ -31,6 +57,8 @@ func NewResourceProvider(id string, inUse bool, availableMemory bytesize.Bytesiz
 		inUse:           inUse,
 		usedPorts:       usedPorts,
 		availableMemory: availableMemory,
+		availableCPU:    availableCPU,
+		placed:          map[string]int{},
 	}
 }
 
@@ -43,7 +71,7 @@ func (r *ResourceProvider) HasResourcesFor(consumer ResourceConsumer) bool {
 		}
 	}
 
-	return consumer.Memory <= r.availableMemory
+	return consumer.Memory <= r.availableMemory && consumer.CPU <= r.availableCPU
 }
 
 func (r *ResourceProvider) SubtractResourcesFor(consumer ResourceConsumer) error {
@@ -53,6 +81,8 @@ func (r *ResourceProvider) SubtractResourcesFor(consumer ResourceConsumer) error
 
 	r.usedPorts = append(r.usedPorts, consumer.Ports...)
 	r.availableMemory -= consumer.Memory
+	r.availableCPU -= consumer.CPU
+	r.placed[consumer.Group]++
 	r.inUse = true
 
 	return nil
@@ -62,12 +92,23 @@ func (r *ResourceProvider) IsInUse() bool {
 	return r.inUse
 }
 
+func (r *ResourceProvider) AvailableMemory() bytesize.Bytesize {
+	return r.availableMemory
+}
+
+// Placed returns the number of consumers in the specified group that have been placed in the provider
+func (r *ResourceProvider) Placed(group string) int {
+	return r.placed[group]
+}
+
 func (r ResourceProvider) ToModel() models.ResourceProvider {
 	return models.ResourceProvider{
-		ID:              r.ID,
-		InUse:           r.inUse,
-		UsedPorts:       r.usedPorts,
-		AvailableMemory: r.availableMemory.Format("mib"),
+		ID:               r.ID,
+		AvailabilityZone: r.AvailabilityZone,
+		InUse:            r.inUse,
+		UsedPorts:        r.usedPorts,
+		AvailableMemory:  r.availableMemory.Format("mib"),
+		AvailableCPU:     r.availableCPU,
 	}
 }
 
@@ -79,7 +120,18 @@ func SortProvidersByMemory(p []*ResourceProvider) {
 		},
 	}
 
-	sort.Sort(sorter)
+	sort.Stable(sorter)
+}
+
+func SortProvidersByCPU(p []*ResourceProvider) {
+	sorter := &ResourceProviderSorter{
+		Providers: p,
+		lessThan: func(i *ResourceProvider, j *ResourceProvider) bool {
+			return i.availableCPU < j.availableCPU
+		},
+	}
+
+	sort.Stable(sorter)
 }
 
 func SortProvidersByUsage(p []*ResourceProvider) {
@@ -90,7 +142,7 @@ func SortProvidersByUsage(p []*ResourceProvider) {
 		},
 	}
 
-	sort.Sort(sorter)
+	sort.Stable(sorter)
 }
 
 type ResourceProviderSorter struct {
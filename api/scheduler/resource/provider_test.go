@@ -38,6 +38,11 @@ func TestResourceProviderHasMemoryFor(t *testing.T) {
 			ResourceConsumer: ResourceConsumer{Ports: []int{80, 8000}, Memory: bytesize.GB * 2},
 			Expected:         false,
 		},
+		{
+			Name:             "Task requires too much cpu",
+			ResourceConsumer: ResourceConsumer{Memory: bytesize.MB, CPU: 2048},
+			Expected:         false,
+		},
 		{
 			Name:             "Task requires no resources",
 			ResourceConsumer: ResourceConsumer{},
@@ -58,9 +63,14 @@ func TestResourceProviderHasMemoryFor(t *testing.T) {
 			ResourceConsumer: ResourceConsumer{Ports: []int{8080}, Memory: bytesize.GB},
 			Expected:         true,
 		},
+		{
+			Name:             "Task requires exact amount of available cpu",
+			ResourceConsumer: ResourceConsumer{Memory: bytesize.MB, CPU: 1024},
+			Expected:         true,
+		},
 	}
 
-	provider := NewResourceProvider("", true, bytesize.GB, []int{80, 8000})
+	provider := NewResourceProvider("", true, bytesize.GB, 1024, []int{80, 8000})
 	for _, c := range cases {
 		if output := provider.HasResourcesFor(c.ResourceConsumer); output != c.Expected {
 			t.Errorf("%s: output was %t, expected %t", c.Name, output, c.Expected)
@@ -69,7 +79,7 @@ func TestResourceProviderHasMemoryFor(t *testing.T) {
 }
 
 func TestResourceProviderSubtractResourcesFor(t *testing.T) {
-	provider := NewResourceProvider("", false, bytesize.GB, nil)
+	provider := NewResourceProvider("", false, bytesize.GB, 1024, nil)
 
 	resource := ResourceConsumer{Ports: []int{80}}
 	if err := provider.SubtractResourcesFor(resource); err != nil {
@@ -81,13 +91,14 @@ func TestResourceProviderSubtractResourcesFor(t *testing.T) {
 		t.Error(err)
 	}
 
-	resource = ResourceConsumer{Ports: []int{8000, 9090}, Memory: bytesize.MB}
+	resource = ResourceConsumer{Ports: []int{8000, 9090}, Memory: bytesize.MB, CPU: 256}
 	if err := provider.SubtractResourcesFor(resource); err != nil {
 		t.Error(err)
 	}
 
 	testutils.AssertEqual(t, []int{80, 8000, 9090}, provider.usedPorts)
 	testutils.AssertEqual(t, bytesize.GB-(bytesize.MB*2), provider.availableMemory)
+	testutils.AssertEqual(t, 768, provider.availableCPU)
 	testutils.AssertEqual(t, true, provider.IsInUse())
 }
 
@@ -108,10 +119,14 @@ func TestResourceProviderSubtractResourcesForError(t *testing.T) {
 			Name:             "Too much memory",
 			ResourceConsumer: ResourceConsumer{Memory: bytesize.GB * 2},
 		},
+		{
+			Name:             "Too much cpu",
+			ResourceConsumer: ResourceConsumer{CPU: 2048},
+		},
 	}
 
 	for _, c := range cases {
-		provider := NewResourceProvider("", true, bytesize.GB, []int{80, 8000})
+		provider := NewResourceProvider("", true, bytesize.GB, 1024, []int{80, 8000})
 		if err := provider.SubtractResourcesFor(c.ResourceConsumer); err == nil {
 			t.Fatalf("%s: Error was nil!", c.Name)
 		}
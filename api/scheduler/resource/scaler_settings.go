@@ -0,0 +1,60 @@
+package resource
+
+import (
+	"time"
+
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/zpatrick/go-bytesize"
+)
+
+// ScalerSettings limit how the scaler changes the size of an environment
+type ScalerSettings struct {
+	HeadroomInstances int
+	HeadroomMemory    bytesize.Bytesize
+	ScaleDownCooldown time.Duration
+	// MaxClusterCount of 0 means the environment has no max cluster count
+	MaxClusterCount int
+}
+
+// NewScalerSettings converts and validates the scaler settings of an environment
+func NewScalerSettings(m models.ScalerSettings) (ScalerSettings, error) {
+	fields := []struct {
+		name  string
+		value int
+	}{
+		{"HeadroomInstances", m.HeadroomInstances},
+		{"HeadroomMemory", m.HeadroomMemory},
+		{"ScaleDownCooldown", m.ScaleDownCooldown},
+		{"MaxClusterCount", m.MaxClusterCount},
+	}
+
+	for _, field := range fields {
+		if field.value < 0 {
+			return ScalerSettings{}, errors.Newf(errors.InvalidScalerSettings, "%s must be at least 0", field.name)
+		}
+	}
+
+	settings := ScalerSettings{
+		HeadroomInstances: m.HeadroomInstances,
+		HeadroomMemory:    bytesize.MiB * bytesize.Bytesize(m.HeadroomMemory),
+		ScaleDownCooldown: time.Second * time.Duration(m.ScaleDownCooldown),
+		MaxClusterCount:   m.MaxClusterCount,
+	}
+
+	return settings, nil
+}
+
+// HasMaxClusterCount returns true if the environment's size is limited
+func (s ScalerSettings) HasMaxClusterCount() bool {
+	return s.MaxClusterCount > 0
+}
+
+func (s ScalerSettings) ToModel() models.ScalerSettings {
+	return models.ScalerSettings{
+		HeadroomInstances: s.HeadroomInstances,
+		HeadroomMemory:    int(s.HeadroomMemory.Mebibytes()),
+		ScaleDownCooldown: int(s.ScaleDownCooldown.Seconds()),
+		MaxClusterCount:   s.MaxClusterCount,
+	}
+}
@@ -0,0 +1,49 @@
+package resource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
+	"github.com/zpatrick/go-bytesize"
+)
+
+func TestNewScalerSettings(t *testing.T) {
+	model := models.ScalerSettings{
+		HeadroomInstances: 1,
+		HeadroomMemory:    512,
+		ScaleDownCooldown: 60,
+		MaxClusterCount:   10,
+	}
+
+	settings, err := NewScalerSettings(model)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := ScalerSettings{
+		HeadroomInstances: 1,
+		HeadroomMemory:    bytesize.MiB * 512,
+		ScaleDownCooldown: time.Minute,
+		MaxClusterCount:   10,
+	}
+
+	testutils.AssertEqual(t, settings, expected)
+	testutils.AssertEqual(t, settings.ToModel(), model)
+}
+
+func TestNewScalerSettings_negative(t *testing.T) {
+	cases := map[string]models.ScalerSettings{
+		"HeadroomInstances": {HeadroomInstances: -1},
+		"HeadroomMemory":    {HeadroomMemory: -1},
+		"ScaleDownCooldown": {ScaleDownCooldown: -1},
+		"MaxClusterCount":   {MaxClusterCount: -1},
+	}
+
+	for name, model := range cases {
+		if _, err := NewScalerSettings(model); err == nil {
+			t.Errorf("Case %s: error was nil!", name)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/quintilesims/layer0/common/aws/s3"
+	"github.com/quintilesims/layer0/common/models"
+)
+
+const SCALER_HISTORY_PREFIX = "scaler_history/"
+
+// ScalerHistory keeps the record of each environment scaler run
+type ScalerHistory interface {
+	Record(run *models.ScalerRun) error
+	// List returns the most recent runs for the environment, newest first.
+	// A limit of 0 returns every run
+	List(environmentID string, limit int) ([]*models.ScalerRun, error)
+}
+
+// S3ScalerHistory writes each scaler run to its own object in the layer0 bucket,
+// grouped by environment
+type S3ScalerHistory struct {
+	S3     s3.Provider
+	Bucket string
+}
+
+func NewS3ScalerHistory(s3Provider s3.Provider, bucket string) *S3ScalerHistory {
+	return &S3ScalerHistory{
+		S3:     s3Provider,
+		Bucket: bucket,
+	}
+}
+
+func (this *S3ScalerHistory) Record(run *models.ScalerRun) error {
+	body, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+
+	// keys sort by the time of the run
+	key := fmt.Sprintf("%s%s.json", scalerHistoryPrefix(run.EnvironmentID), run.Time.UTC().Format("2006/01/02/150405.000000000"))
+	return this.S3.PutObject(this.Bucket, key, body)
+}
+
+// List reads the keys of every run of the environment, which can be more than the 1000 keys
+// returned by a single S3 listing, so that the newest runs are found wherever they sort
+func (this *S3ScalerHistory) List(environmentID string, limit int) ([]*models.ScalerRun, error) {
+	keys, err := this.S3.ListObjects(this.Bucket, scalerHistoryPrefix(environmentID))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	if limit > 0 && len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	runs := make([]*models.ScalerRun, len(keys))
+	for i, key := range keys {
+		body, err := this.S3.GetObject(this.Bucket, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(body, &runs[i]); err != nil {
+			return nil, fmt.Errorf("Failed to parse scaler run in '%s': %v", key, err)
+		}
+	}
+
+	return runs, nil
+}
+
+func scalerHistoryPrefix(environmentID string) string {
+	return fmt.Sprintf("%s%s/", SCALER_HISTORY_PREFIX, environmentID)
+}
@@ -0,0 +1,112 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/quintilesims/layer0/common/aws/s3/mock_s3"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+func TestS3ScalerHistoryRecord(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockS3 := mock_s3.NewMockProvider(ctrl)
+	history := NewS3ScalerHistory(mockS3, "bucket")
+
+	run := &models.ScalerRun{
+		ScalerRunInfo: models.ScalerRunInfo{
+			EnvironmentID:               "e1",
+			TerminatedResourceProviders: []string{"i1"},
+		},
+		Time:  time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC),
+		Error: "some error",
+	}
+
+	var body []byte
+	mockS3.EXPECT().
+		PutObject("bucket", "scaler_history/e1/2018/01/02/030405.000000000.json", gomock.Any()).
+		Do(func(bucket, key string, b []byte) {
+			body = b
+		}).
+		Return(nil)
+
+	if err := history.Record(run); err != nil {
+		t.Fatal(err)
+	}
+
+	var result *models.ScalerRun
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, result, run)
+}
+
+func TestS3ScalerHistoryList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockS3 := mock_s3.NewMockProvider(ctrl)
+	history := NewS3ScalerHistory(mockS3, "bucket")
+
+	mockS3.EXPECT().
+		ListObjects("bucket", "scaler_history/e1/").
+		Return([]string{
+			"scaler_history/e1/2018/01/01/000000.000000000.json",
+			"scaler_history/e1/2018/01/03/000000.000000000.json",
+			"scaler_history/e1/2018/01/02/000000.000000000.json",
+		}, nil)
+
+	mockS3.EXPECT().
+		GetObject("bucket", "scaler_history/e1/2018/01/03/000000.000000000.json").
+		Return([]byte(`{"environment_id":"e1","actual_scale_after_run":3}`), nil)
+
+	mockS3.EXPECT().
+		GetObject("bucket", "scaler_history/e1/2018/01/02/000000.000000000.json").
+		Return([]byte(`{"environment_id":"e1","actual_scale_after_run":2}`), nil)
+
+	runs, err := history.List("e1", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(runs), 2)
+	testutils.AssertEqual(t, runs[0].ActualScaleAfterRun, 3)
+	testutils.AssertEqual(t, runs[1].ActualScaleAfterRun, 2)
+}
+
+func TestS3ScalerHistoryListMoreThanOnePage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockS3 := mock_s3.NewMockProvider(ctrl)
+	history := NewS3ScalerHistory(mockS3, "bucket")
+
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	keys := []string{}
+	for i := 0; i < 1500; i++ {
+		keys = append(keys, fmt.Sprintf("scaler_history/e1/%s.json", start.Add(time.Minute*time.Duration(i)).Format("2006/01/02/150405.000000000")))
+	}
+
+	mockS3.EXPECT().
+		ListObjects("bucket", "scaler_history/e1/").
+		Return(keys, nil)
+
+	mockS3.EXPECT().
+		GetObject("bucket", keys[1499]).
+		Return([]byte(`{"environment_id":"e1","actual_scale_after_run":1499}`), nil)
+
+	runs, err := history.List("e1", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(runs), 1)
+	testutils.AssertEqual(t, runs[0].ActualScaleAfterRun, 1499)
+}
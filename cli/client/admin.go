@@ -42,3 +42,71 @@ func (c *APIClient) RunScaler(environmentID string) (*models.ScalerRunInfo, erro
 
 	return output, nil
 }
+
+func (c *APIClient) SimulateScaler(environmentID string, req models.ScalerSimulationRequest) (*models.ScalerRunInfo, error) {
+	var output *models.ScalerRunInfo
+	if err := c.Execute(c.Sling("admin/").Post("scale/"+environmentID+"/simulate").BodyJSON(req), &output); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+func (c *APIClient) GetBackup() (*models.Backup, error) {
+	var backup *models.Backup
+	if err := c.Execute(c.Sling("admin/").Get("backup"), &backup); err != nil {
+		return nil, err
+	}
+
+	return backup, nil
+}
+
+func (c *APIClient) SaveBackup() (*models.BackupSummary, error) {
+	var summary *models.BackupSummary
+	if err := c.Execute(c.Sling("admin/").Post("backup").BodyJSON(""), &summary); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+func (c *APIClient) Restore(req models.RestoreRequest) (*models.RestoreReport, error) {
+	var report *models.RestoreReport
+	if err := c.Execute(c.Sling("admin/").Post("restore").BodyJSON(req), &report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// ReconcileTags deletes the tags of entities that no longer exist;
+// a dry run only lists them
+func (c *APIClient) ReconcileTags(dryRun bool) (*models.TagReconciliationReport, error) {
+	sling := c.Sling("admin/").Post("reconcile").BodyJSON("")
+	if dryRun {
+		sling = c.Sling("admin/").Get("reconcile")
+	}
+
+	var report *models.TagReconciliationReport
+	if err := c.Execute(sling, &report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// ImportEntities writes the missing tags of layer0 resources;
+// a dry run only lists the tags that would be written
+func (c *APIClient) ImportEntities(req models.ImportRequest) (*models.ImportReport, error) {
+	sling := c.Sling("admin/").Post("import").BodyJSON(req)
+	if req.DryRun {
+		sling = c.Sling("admin/").Get("import")
+	}
+
+	var report *models.ImportReport
+	if err := c.Execute(sling, &report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
@@ -85,3 +85,178 @@ func TestRunScaler(t *testing.T) {
 
 	testutils.AssertEqual(t, output.EnvironmentID, "id")
 }
+
+func TestSimulateScaler(t *testing.T) {
+	req := models.ScalerSimulationRequest{
+		Services: []models.SimulatedService{{ServiceID: "sid", DesiredCount: 2}},
+		Tasks:    []models.SimulatedTask{{DeployID: "did", Copies: 1}},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "POST")
+		testutils.AssertEqual(t, r.URL.Path, "/admin/scale/id/simulate")
+
+		var body models.ScalerSimulationRequest
+		Unmarshal(t, r, &body)
+
+		testutils.AssertEqual(t, body, req)
+
+		MarshalAndWrite(t, w, models.ScalerRunInfo{EnvironmentID: "id"}, 200)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	output, err := client.SimulateScaler("id", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, output.EnvironmentID, "id")
+}
+
+func TestGetBackup(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "GET")
+		testutils.AssertEqual(t, r.URL.Path, "/admin/backup")
+
+		MarshalAndWrite(t, w, models.Backup{Version: 1}, 200)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	backup, err := client.GetBackup()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, backup.Version, 1)
+}
+
+func TestSaveBackup(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "POST")
+		testutils.AssertEqual(t, r.URL.Path, "/admin/backup")
+
+		MarshalAndWrite(t, w, models.BackupSummary{Key: "backups/key.json"}, 201)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	summary, err := client.SaveBackup()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, summary.Key, "backups/key.json")
+}
+
+func TestRestore(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "POST")
+		testutils.AssertEqual(t, r.URL.Path, "/admin/restore")
+
+		var req models.RestoreRequest
+		Unmarshal(t, r, &req)
+
+		testutils.AssertEqual(t, req.Key, "backups/key.json")
+		testutils.AssertEqual(t, req.DryRun, true)
+
+		MarshalAndWrite(t, w, models.RestoreReport{DryRun: true, TagsInserted: 2}, 200)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	report, err := client.Restore(models.RestoreRequest{Key: "backups/key.json", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, report.TagsInserted, 2)
+}
+
+func TestReconcileTags(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "POST")
+		testutils.AssertEqual(t, r.URL.Path, "/admin/reconcile")
+
+		MarshalAndWrite(t, w, models.TagReconciliationReport{TagsDeleted: 2}, 200)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	report, err := client.ReconcileTags(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, report.TagsDeleted, 2)
+}
+
+func TestReconcileTagsDryRun(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "GET")
+		testutils.AssertEqual(t, r.URL.Path, "/admin/reconcile")
+
+		MarshalAndWrite(t, w, models.TagReconciliationReport{DryRun: true}, 200)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	report, err := client.ReconcileTags(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, report.DryRun, true)
+}
+
+func TestImportEntities(t *testing.T) {
+	entities := []models.EntityWithTags{{EntityType: "deploy", EntityID: "dpl.1"}}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "POST")
+		testutils.AssertEqual(t, r.URL.Path, "/admin/import")
+
+		var req models.ImportRequest
+		Unmarshal(t, r, &req)
+
+		testutils.AssertEqual(t, req.Entities, entities)
+
+		MarshalAndWrite(t, w, models.ImportReport{TagsWritten: 2}, 200)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	report, err := client.ImportEntities(models.ImportRequest{Entities: entities})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, report.TagsWritten, 2)
+}
+
+func TestImportEntitiesDryRun(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "GET")
+		testutils.AssertEqual(t, r.URL.Path, "/admin/import")
+
+		MarshalAndWrite(t, w, models.ImportReport{DryRun: true}, 200)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	report, err := client.ImportEntities(models.ImportRequest{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, report.DryRun, true)
+}
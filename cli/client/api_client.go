@@ -107,6 +107,17 @@ func (c *APIClient) Sling(path string) *sling.Sling {
 		Doer(logSling(c.httpClient))
 }
 
+// IfMatch sets the If-Match header so the request fails if the entity
+// has been modified since the specified version was read.
+// Passing AnyVersion will not set the header.
+func (c *APIClient) IfMatch(sling *sling.Sling, version int64) *sling.Sling {
+	if version == AnyVersion {
+		return sling
+	}
+
+	return sling.Set("If-Match", fmt.Sprintf("\"%d\"", version))
+}
+
 func (c *APIClient) Execute(sling *sling.Sling, receive interface{}) error {
 	if _, err := c.execute(sling, receive); err != nil {
 		return err
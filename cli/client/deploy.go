@@ -1,6 +1,8 @@
 package client
 
 import (
+	"net/url"
+
 	"github.com/quintilesims/layer0/common/models"
 )
 
@@ -44,3 +46,16 @@ func (c *APIClient) ListDeploys() ([]*models.DeploySummary, error) {
 
 	return deploys, nil
 }
+
+// ListDeploysBySelector returns the deploys whose tags match the selector, e.g. 'team=payments'
+func (c *APIClient) ListDeploysBySelector(selector string) ([]*models.DeploySummary, error) {
+	query := url.Values{}
+	query.Set("selector", selector)
+
+	var deploys []*models.DeploySummary
+	if err := c.Execute(c.Sling("deploy/").Get("?"+query.Encode()), &deploys); err != nil {
+		return nil, err
+	}
+
+	return deploys, nil
+}
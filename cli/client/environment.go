@@ -1,25 +1,40 @@
 package client
 
 import (
+	"fmt"
+	"net/url"
+
 	"github.com/quintilesims/layer0/common/models"
 )
 
-func (c *APIClient) CreateEnvironment(name, instanceSize string, minCount int, userData []byte, os, amiID string) (*models.Environment, error) {
+// CloneEnvironment creates a copy of the environment, with its load balancers, services, links and tags
+func (c *APIClient) CloneEnvironment(id string, req models.CloneEnvironmentRequest) (string, error) {
+	jobID, err := c.ExecuteWithJob(c.Sling("environment/").Post(fmt.Sprintf("%s/clone", id)).BodyJSON(req))
+	if err != nil {
+		return "", err
+	}
+
+	return jobID, nil
+}
+
+func (c *APIClient) CreateEnvironment(name, instanceSize string, minCount int, userData []byte, os, amiID, placementStrategy string, scalerSettings models.ScalerSettings) (string, error) {
 	req := models.CreateEnvironmentRequest{
-		EnvironmentName:  name,
-		InstanceSize:     instanceSize,
-		MinClusterCount:  minCount,
-		UserDataTemplate: userData,
-		OperatingSystem:  os,
-		AMIID:            amiID,
+		EnvironmentName:   name,
+		InstanceSize:      instanceSize,
+		MinClusterCount:   minCount,
+		UserDataTemplate:  userData,
+		OperatingSystem:   os,
+		AMIID:             amiID,
+		PlacementStrategy: placementStrategy,
+		ScalerSettings:    scalerSettings,
 	}
 
-	var environment *models.Environment
-	if err := c.Execute(c.Sling("environment/").Post("").BodyJSON(req), &environment); err != nil {
-		return nil, err
+	jobID, err := c.ExecuteWithJob(c.Sling("environment/").Post("").BodyJSON(req))
+	if err != nil {
+		return "", err
 	}
 
-	return environment, nil
+	return jobID, nil
 }
 
 func (c *APIClient) DeleteEnvironment(id string) (string, error) {
@@ -49,13 +64,63 @@ func (c *APIClient) ListEnvironments() ([]*models.EnvironmentSummary, error) {
 	return environments, nil
 }
 
-func (c *APIClient) UpdateEnvironment(id string, minCount int) (*models.Environment, error) {
+// ListEnvironmentsBySelector returns the environments whose tags match the selector, e.g. 'team=payments'
+func (c *APIClient) ListEnvironmentsBySelector(selector string) ([]*models.EnvironmentSummary, error) {
+	query := url.Values{}
+	query.Set("selector", selector)
+
+	var environments []*models.EnvironmentSummary
+	if err := c.Execute(c.Sling("environment/").Get("?"+query.Encode()), &environments); err != nil {
+		return nil, err
+	}
+
+	return environments, nil
+}
+
+func (c *APIClient) ListScalerHistory(id string, limit int) ([]*models.ScalerRun, error) {
+	url := fmt.Sprintf("%s/scaler/history?limit=%d", id, limit)
+
+	var runs []*models.ScalerRun
+	if err := c.Execute(c.Sling("environment/").Get(url), &runs); err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+func (c *APIClient) UpdateEnvironment(id string, minCount int, version int64) (*models.Environment, error) {
+	req := models.UpdateEnvironmentRequest{
+		MinClusterCount: &minCount,
+	}
+
+	var environment *models.Environment
+	if err := c.Execute(c.IfMatch(c.Sling("environment/"), version).Put(id).BodyJSON(req), &environment); err != nil {
+		return nil, err
+	}
+
+	return environment, nil
+}
+
+func (c *APIClient) UpdateEnvironmentPlacementStrategy(id, placementStrategy string, version int64) (*models.Environment, error) {
+	req := models.UpdateEnvironmentRequest{
+		PlacementStrategy: placementStrategy,
+	}
+
+	var environment *models.Environment
+	if err := c.Execute(c.IfMatch(c.Sling("environment/"), version).Put(id).BodyJSON(req), &environment); err != nil {
+		return nil, err
+	}
+
+	return environment, nil
+}
+
+func (c *APIClient) UpdateEnvironmentScalerSettings(id string, settings models.ScalerSettings, version int64) (*models.Environment, error) {
 	req := models.UpdateEnvironmentRequest{
-		MinClusterCount: minCount,
+		ScalerSettings: &settings,
 	}
 
 	var environment *models.Environment
-	if err := c.Execute(c.Sling("environment/").Put(id).BodyJSON(req), &environment); err != nil {
+	if err := c.Execute(c.IfMatch(c.Sling("environment/"), version).Put(id).BodyJSON(req), &environment); err != nil {
 		return nil, err
 	}
 
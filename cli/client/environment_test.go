@@ -22,19 +22,61 @@ func TestCreateEnvironment(t *testing.T) {
 		testutils.AssertEqual(t, req.UserDataTemplate, []byte("user_data"))
 		testutils.AssertEqual(t, req.OperatingSystem, "linux")
 		testutils.AssertEqual(t, req.AMIID, "ami")
+		testutils.AssertEqual(t, req.PlacementStrategy, "spread-zone")
+		testutils.AssertEqual(t, req.ScalerSettings, models.ScalerSettings{MaxClusterCount: 4})
 
-		MarshalAndWrite(t, w, models.Environment{EnvironmentID: "id"}, 200)
+		headers := map[string]string{
+			"Location": "/job/jobid",
+			"X-JobID":  "jobid",
+		}
+
+		MarshalAndWriteHeader(t, w, "", headers, 202)
 	}
 
 	client, server := newClientAndServer(handler)
 	defer server.Close()
 
-	environment, err := client.CreateEnvironment("name", "m3.medium", 2, []byte("user_data"), "linux", "ami")
+	jobID, err := client.CreateEnvironment("name", "m3.medium", 2, []byte("user_data"), "linux", "ami", "spread-zone", models.ScalerSettings{MaxClusterCount: 4})
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	testutils.AssertEqual(t, environment.EnvironmentID, "id")
+	testutils.AssertEqual(t, jobID, "jobid")
+}
+
+func TestCloneEnvironment(t *testing.T) {
+	req := models.CloneEnvironmentRequest{
+		EnvironmentName:   "staging",
+		LoadBalancerNames: map[string]string{"api": "staging-api"},
+		Scale:             map[string]int64{"api": 1},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "POST")
+		testutils.AssertEqual(t, r.URL.Path, "/environment/id/clone")
+
+		var body models.CloneEnvironmentRequest
+		Unmarshal(t, r, &body)
+
+		testutils.AssertEqual(t, body, req)
+
+		headers := map[string]string{
+			"Location": "/job/jobid",
+			"X-JobID":  "jobid",
+		}
+
+		MarshalAndWriteHeader(t, w, "", headers, 202)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	jobID, err := client.CloneEnvironment("id", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, jobID, "jobid")
 }
 
 func TestDeleteEnvironment(t *testing.T) {
@@ -106,6 +148,31 @@ func TestListEnvironments(t *testing.T) {
 	testutils.AssertEqual(t, environments[1].EnvironmentID, "id2")
 }
 
+func TestListScalerHistory(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "GET")
+		testutils.AssertEqual(t, r.URL.Path, "/environment/id/scaler/history")
+		testutils.AssertEqual(t, r.URL.Query().Get("limit"), "10")
+
+		runs := []models.ScalerRun{
+			{ScalerRunInfo: models.ScalerRunInfo{EnvironmentID: "id"}},
+		}
+
+		MarshalAndWrite(t, w, runs, 200)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	runs, err := client.ListScalerHistory("id", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(runs), 1)
+	testutils.AssertEqual(t, runs[0].EnvironmentID, "id")
+}
+
 func TestUpdateEnvironment(t *testing.T) {
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		testutils.AssertEqual(t, r.Method, "PUT")
@@ -114,7 +181,63 @@ func TestUpdateEnvironment(t *testing.T) {
 		var req models.UpdateEnvironmentRequest
 		Unmarshal(t, r, &req)
 
-		testutils.AssertEqual(t, req.MinClusterCount, 2)
+		testutils.AssertEqual(t, *req.MinClusterCount, 2)
+		testutils.AssertEqual(t, req.PlacementStrategy, "")
+
+		MarshalAndWrite(t, w, models.Environment{EnvironmentID: "id"}, 200)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	environment, err := client.UpdateEnvironment("id", 2, AnyVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, environment.EnvironmentID, "id")
+}
+
+func TestUpdateEnvironmentPlacementStrategy(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "PUT")
+		testutils.AssertEqual(t, r.URL.Path, "/environment/id")
+
+		var req models.UpdateEnvironmentRequest
+		Unmarshal(t, r, &req)
+
+		testutils.AssertEqual(t, req.MinClusterCount == nil, true)
+		testutils.AssertEqual(t, req.PlacementStrategy, "spread-zone")
+
+		MarshalAndWrite(t, w, models.Environment{EnvironmentID: "id"}, 200)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	environment, err := client.UpdateEnvironmentPlacementStrategy("id", "spread-zone", AnyVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, environment.EnvironmentID, "id")
+}
+
+func TestUpdateEnvironmentScalerSettings(t *testing.T) {
+	settings := models.ScalerSettings{
+		HeadroomInstances: 1,
+		ScaleDownCooldown: 300,
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "PUT")
+		testutils.AssertEqual(t, r.URL.Path, "/environment/id")
+
+		var req models.UpdateEnvironmentRequest
+		Unmarshal(t, r, &req)
+
+		testutils.AssertEqual(t, req.MinClusterCount == nil, true)
+		testutils.AssertEqual(t, *req.ScalerSettings, settings)
 
 		MarshalAndWrite(t, w, models.Environment{EnvironmentID: "id"}, 200)
 	}
@@ -122,7 +245,7 @@ func TestUpdateEnvironment(t *testing.T) {
 	client, server := newClientAndServer(handler)
 	defer server.Close()
 
-	environment, err := client.UpdateEnvironment("id", 2)
+	environment, err := client.UpdateEnvironmentScalerSettings("id", settings, AnyVersion)
 	if err != nil {
 		t.Fatal(err)
 	}
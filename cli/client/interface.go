@@ -6,41 +6,59 @@ import (
 	"github.com/quintilesims/layer0/common/models"
 )
 
+// AnyVersion can be passed to update methods to skip the If-Match version check
+const AnyVersion int64 = -1
+
 type Client interface {
 	CreateDeploy(name string, content []byte) (*models.Deploy, error)
 	DeleteDeploy(id string) error
 	GetDeploy(id string) (*models.Deploy, error)
 	ListDeploys() ([]*models.DeploySummary, error)
+	ListDeploysBySelector(selector string) ([]*models.DeploySummary, error)
 
-	CreateEnvironment(name, instanceSize string, minCount int, userData []byte, os, amiID string) (*models.Environment, error)
+	CloneEnvironment(id string, req models.CloneEnvironmentRequest) (string, error)
+	CreateEnvironment(name, instanceSize string, minCount int, userData []byte, os, amiID, placementStrategy string, scalerSettings models.ScalerSettings) (string, error)
 	DeleteEnvironment(id string) (string, error)
 	GetEnvironment(id string) (*models.Environment, error)
 	ListEnvironments() ([]*models.EnvironmentSummary, error)
-	UpdateEnvironment(id string, minCount int) (*models.Environment, error)
+	ListEnvironmentsBySelector(selector string) ([]*models.EnvironmentSummary, error)
+	ListScalerHistory(id string, limit int) ([]*models.ScalerRun, error)
+	UpdateEnvironment(id string, minCount int, version int64) (*models.Environment, error)
+	UpdateEnvironmentPlacementStrategy(id, placementStrategy string, version int64) (*models.Environment, error)
+	UpdateEnvironmentScalerSettings(id string, settings models.ScalerSettings, version int64) (*models.Environment, error)
 	CreateLink(sourceID string, destinationID string) error
 	DeleteLink(sourceID string, destinationID string) error
 
+	CancelJob(id string) (*models.Job, error)
+	CreateWorkflow(req models.CreateWorkflowRequest) (string, error)
 	Delete(id string) error
 	GetJob(id string) (*models.Job, error)
+	GetJobLogs(id, start, end string, tail int) ([]*models.LogFile, error)
+	GetWorkflow(id string) (*models.Workflow, error)
+	ListArchivedJobs() ([]*models.Job, error)
 	ListJobs() ([]*models.Job, error)
+	ListJobsByEntity(entityType, entityID string) ([]*models.Job, error)
+	RetryJob(id string) (*models.Job, error)
 	WaitForJob(jobID string, timeout time.Duration) error
 
-	CreateLoadBalancer(name, environmentID string, healthCheck models.HealthCheck, ports []models.Port, isPublic bool, idleTimeout int, crossZone bool) (*models.LoadBalancer, error)
+	CreateLoadBalancer(name, environmentID string, healthCheck models.HealthCheck, ports []models.Port, isPublic bool, idleTimeout int, crossZone bool) (string, error)
 	DeleteLoadBalancer(id string) (string, error)
 	GetLoadBalancer(id string) (*models.LoadBalancer, error)
 	ListLoadBalancers() ([]*models.LoadBalancerSummary, error)
-	UpdateLoadBalancerHealthCheck(id string, healthCheck models.HealthCheck) (*models.LoadBalancer, error)
-	UpdateLoadBalancerPorts(id string, ports []models.Port) (*models.LoadBalancer, error)
-	UpdateLoadBalancerIdleTimeout(id string, idleTimeout int) (*models.LoadBalancer, error)
-	UpdateLoadBalancerCrossZone(id string, crossZone bool) (*models.LoadBalancer, error)
+	ListLoadBalancersBySelector(selector string) ([]*models.LoadBalancerSummary, error)
+	UpdateLoadBalancerHealthCheck(id string, healthCheck models.HealthCheck, version int64) (*models.LoadBalancer, error)
+	UpdateLoadBalancerPorts(id string, ports []models.Port, version int64) (*models.LoadBalancer, error)
+	UpdateLoadBalancerIdleTimeout(id string, idleTimeout int, version int64) (*models.LoadBalancer, error)
+	UpdateLoadBalancerCrossZone(id string, crossZone bool, version int64) (*models.LoadBalancer, error)
 
 	CreateService(name, environmentID, deployID, loadBalancerID string) (*models.Service, error)
 	DeleteService(id string) (string, error)
-	UpdateService(serviceID, deployID string) (*models.Service, error)
+	UpdateService(serviceID, deployID string, version int64) (string, error)
 	GetService(id string) (*models.Service, error)
 	GetServiceLogs(id, start, end string, tail int) ([]*models.LogFile, error)
 	ListServices() ([]*models.ServiceSummary, error)
-	ScaleService(id string, scale int) (*models.Service, error)
+	ListServicesBySelector(selector string) ([]*models.ServiceSummary, error)
+	ScaleService(id string, scale int, version int64) (string, error)
 	WaitForDeployment(serviceID string, timeout time.Duration) (*models.Service, error)
 
 	CreateTask(name, environmentID, deployID string, overrides []models.ContainerOverride) (string, error)
@@ -48,10 +66,19 @@ type Client interface {
 	GetTask(id string) (*models.Task, error)
 	GetTaskLogs(id, start, end string, tail int) ([]*models.LogFile, error)
 	ListTasks() ([]*models.TaskSummary, error)
+	ListTasksBySelector(selector string) ([]*models.TaskSummary, error)
 
+	CreateTag(tag models.Tag) error
+	DeleteTag(tag models.Tag) error
 	SelectByQuery(params map[string]string) ([]*models.EntityWithTags, error)
 	GetVersion() (string, error)
 	GetConfig() (*models.APIConfig, error)
 	UpdateSQL() error
 	RunScaler(environmentID string) (*models.ScalerRunInfo, error)
+	SimulateScaler(environmentID string, req models.ScalerSimulationRequest) (*models.ScalerRunInfo, error)
+	GetBackup() (*models.Backup, error)
+	SaveBackup() (*models.BackupSummary, error)
+	Restore(req models.RestoreRequest) (*models.RestoreReport, error)
+	ReconcileTags(dryRun bool) (*models.TagReconciliationReport, error)
+	ImportEntities(req models.ImportRequest) (*models.ImportReport, error)
 }
@@ -2,6 +2,8 @@ package client
 
 import (
 	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/quintilesims/layer0/common/models"
@@ -9,6 +11,24 @@ import (
 	"github.com/quintilesims/layer0/common/waitutils"
 )
 
+func (c *APIClient) CancelJob(id string) (*models.Job, error) {
+	var job *models.Job
+	if err := c.Execute(c.Sling("job/").Post(id+"/cancel"), &job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (c *APIClient) CreateWorkflow(req models.CreateWorkflowRequest) (string, error) {
+	jobID, err := c.ExecuteWithJob(c.Sling("job/").Post("workflow").BodyJSON(req))
+	if err != nil {
+		return "", err
+	}
+
+	return jobID, nil
+}
+
 func (c *APIClient) Delete(id string) error {
 	var response *string
 	if err := c.Execute(c.Sling("job/").Delete(id), &response); err != nil {
@@ -27,6 +47,48 @@ func (c *APIClient) GetJob(id string) (*models.Job, error) {
 	return job, nil
 }
 
+func (c *APIClient) GetJobLogs(id, start, end string, tail int) ([]*models.LogFile, error) {
+	query := url.Values{}
+	if tail > 0 {
+		query.Set("tail", strconv.Itoa(tail))
+	}
+
+	if start != "" {
+		query.Set("start", start)
+	}
+
+	if end != "" {
+		query.Set("end", end)
+	}
+
+	url := fmt.Sprintf("%s/logs?%s", id, query.Encode())
+
+	var logFiles []*models.LogFile
+	if err := c.Execute(c.Sling("job/").Get(url), &logFiles); err != nil {
+		return nil, err
+	}
+
+	return logFiles, nil
+}
+
+func (c *APIClient) GetWorkflow(id string) (*models.Workflow, error) {
+	var workflow *models.Workflow
+	if err := c.Execute(c.Sling("job/").Get(id+"/workflow"), &workflow); err != nil {
+		return nil, err
+	}
+
+	return workflow, nil
+}
+
+func (c *APIClient) ListArchivedJobs() ([]*models.Job, error) {
+	var jobs []*models.Job
+	if err := c.Execute(c.Sling("job/").Get("?archived=true"), &jobs); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
 func (c *APIClient) ListJobs() ([]*models.Job, error) {
 	var jobs []*models.Job
 	if err := c.Execute(c.Sling("job/").Get(""), &jobs); err != nil {
@@ -36,6 +98,29 @@ func (c *APIClient) ListJobs() ([]*models.Job, error) {
 	return jobs, nil
 }
 
+// ListJobsByEntity returns the jobs that targeted the specified entity
+func (c *APIClient) ListJobsByEntity(entityType, entityID string) ([]*models.Job, error) {
+	query := url.Values{}
+	query.Set("entity_type", entityType)
+	query.Set("entity_id", entityID)
+
+	var jobs []*models.Job
+	if err := c.Execute(c.Sling("job/").Get("?"+query.Encode()), &jobs); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (c *APIClient) RetryJob(id string) (*models.Job, error) {
+	var job *models.Job
+	if err := c.Execute(c.Sling("job/").Post(id+"/retry"), &job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
 func (c *APIClient) WaitForJob(jobID string, timeout time.Duration) error {
 	waiter := waitutils.Waiter{
 		Name:    "WaitForJob",
@@ -54,6 +139,10 @@ func (c *APIClient) WaitForJob(jobID string, timeout time.Duration) error {
 				return false, fmt.Errorf(text)
 			}
 
+			if types.JobStatus(job.JobStatus) == types.Cancelled {
+				return false, fmt.Errorf("Job '%s' was cancelled", job.JobID)
+			}
+
 			if types.JobStatus(job.JobStatus) == types.Completed {
 				return true, nil
 			}
@@ -25,6 +25,44 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestCancelJob(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "POST")
+		testutils.AssertEqual(t, r.URL.Path, "/job/id/cancel")
+
+		MarshalAndWrite(t, w, models.Job{JobID: "id", JobStatus: int64(types.Cancelled)}, 200)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	job, err := client.CancelJob("id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, job.JobStatus, int64(types.Cancelled))
+}
+
+func TestRetryJob(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "POST")
+		testutils.AssertEqual(t, r.URL.Path, "/job/id/retry")
+
+		MarshalAndWrite(t, w, models.Job{JobID: "id", TaskID: "t2"}, 200)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	job, err := client.RetryJob("id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, job.TaskID, "t2")
+}
+
 func TestSelectByID(t *testing.T) {
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		testutils.AssertEqual(t, r.Method, "GET")
@@ -44,6 +82,33 @@ func TestSelectByID(t *testing.T) {
 	testutils.AssertEqual(t, job.JobID, "id")
 }
 
+func TestGetJobLogs(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "GET")
+		testutils.AssertEqual(t, r.URL.Path, "/job/id/logs")
+		testutils.AssertEqual(t, r.URL.Query().Get("tail"), "100")
+		testutils.AssertEqual(t, r.URL.Query().Get("start"), "2001-01-01 01:01")
+		testutils.AssertEqual(t, r.URL.Query().Get("end"), "2012-12-12 12:12")
+
+		logs := []models.LogFile{
+			{Name: "name1"},
+		}
+
+		MarshalAndWrite(t, w, logs, 200)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	logs, err := client.GetJobLogs("id", "2001-01-01 01:01", "2012-12-12 12:12", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(logs), 1)
+	testutils.AssertEqual(t, logs[0].Name, "name1")
+}
+
 func TestSelectAll(t *testing.T) {
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		testutils.AssertEqual(t, r.Method, "GET")
@@ -70,6 +135,57 @@ func TestSelectAll(t *testing.T) {
 	testutils.AssertEqual(t, jobs[1].JobID, "id2")
 }
 
+func TestListArchivedJobs(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "GET")
+		testutils.AssertEqual(t, r.URL.Path, "/job/")
+		testutils.AssertEqual(t, r.URL.Query().Get("archived"), "true")
+
+		jobs := []models.Job{
+			{JobID: "id1"},
+		}
+
+		MarshalAndWrite(t, w, jobs, 200)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	jobs, err := client.ListArchivedJobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(jobs), 1)
+	testutils.AssertEqual(t, jobs[0].JobID, "id1")
+}
+
+func TestListJobsByEntity(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "GET")
+		testutils.AssertEqual(t, r.URL.Path, "/job/")
+		testutils.AssertEqual(t, r.URL.Query().Get("entity_type"), "service")
+		testutils.AssertEqual(t, r.URL.Query().Get("entity_id"), "sid")
+
+		jobs := []models.Job{
+			{JobID: "id1"},
+		}
+
+		MarshalAndWrite(t, w, jobs, 200)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	jobs, err := client.ListJobsByEntity("service", "sid")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(jobs), 1)
+	testutils.AssertEqual(t, jobs[0].JobID, "id1")
+}
+
 func TestWaitForJob(t *testing.T) {
 	count := 0
 	handler := func(w http.ResponseWriter, r *http.Request) {
@@ -114,3 +230,58 @@ func TestWaitForJobError(t *testing.T) {
 		t.Fatalf("Error was nil!")
 	}
 }
+
+func TestCreateWorkflow(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "POST")
+		testutils.AssertEqual(t, r.URL.Path, "/job/workflow")
+
+		var req models.CreateWorkflowRequest
+		Unmarshal(t, r, &req)
+
+		testutils.AssertEqual(t, req.OnFailure, "continue")
+		testutils.AssertEqual(t, req.Nodes[0].Name, "a")
+
+		headers := map[string]string{
+			"Location": "/job/jobid",
+			"X-JobID":  "jobid",
+		}
+
+		MarshalAndWriteHeader(t, w, "", headers, 202)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	req := models.CreateWorkflowRequest{
+		OnFailure: "continue",
+		Nodes:     []models.WorkflowNode{{Name: "a", JobType: "delete service", Request: []byte(`"s1"`)}},
+	}
+
+	jobID, err := client.CreateWorkflow(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, jobID, "jobid")
+}
+
+func TestGetWorkflow(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "GET")
+		testutils.AssertEqual(t, r.URL.Path, "/job/id/workflow")
+
+		MarshalAndWrite(t, w, models.Workflow{JobID: "id", Nodes: []models.WorkflowNodeStatus{{Name: "a"}}}, 200)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	workflow, err := client.GetWorkflow("id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, workflow.JobID, "id")
+	testutils.AssertEqual(t, workflow.Nodes[0].Name, "a")
+}
@@ -1,10 +1,12 @@
 package client
 
 import (
+	"net/url"
+
 	"github.com/quintilesims/layer0/common/models"
 )
 
-func (c *APIClient) CreateLoadBalancer(name, environmentID string, healthCheck models.HealthCheck, ports []models.Port, isPublic bool, idleTimeout int, crossZone bool) (*models.LoadBalancer, error) {
+func (c *APIClient) CreateLoadBalancer(name, environmentID string, healthCheck models.HealthCheck, ports []models.Port, isPublic bool, idleTimeout int, crossZone bool) (string, error) {
 	req := models.CreateLoadBalancerRequest{
 		LoadBalancerName: name,
 		EnvironmentID:    environmentID,
@@ -15,12 +17,12 @@ func (c *APIClient) CreateLoadBalancer(name, environmentID string, healthCheck m
 		CrossZone:        crossZone,
 	}
 
-	var loadBalancer *models.LoadBalancer
-	if err := c.Execute(c.Sling("loadbalancer/").Post("").BodyJSON(req), &loadBalancer); err != nil {
-		return nil, err
+	jobID, err := c.ExecuteWithJob(c.Sling("loadbalancer/").Post("").BodyJSON(req))
+	if err != nil {
+		return "", err
 	}
 
-	return loadBalancer, nil
+	return jobID, nil
 }
 
 func (c *APIClient) DeleteLoadBalancer(id string) (string, error) {
@@ -50,52 +52,65 @@ func (c *APIClient) ListLoadBalancers() ([]*models.LoadBalancerSummary, error) {
 	return loadBalancers, nil
 }
 
-func (c *APIClient) UpdateLoadBalancerHealthCheck(id string, healthCheck models.HealthCheck) (*models.LoadBalancer, error) {
+// ListLoadBalancersBySelector returns the load balancers whose tags match the selector, e.g. 'team=payments'
+func (c *APIClient) ListLoadBalancersBySelector(selector string) ([]*models.LoadBalancerSummary, error) {
+	query := url.Values{}
+	query.Set("selector", selector)
+
+	var loadBalancers []*models.LoadBalancerSummary
+	if err := c.Execute(c.Sling("loadbalancer/").Get("?"+query.Encode()), &loadBalancers); err != nil {
+		return nil, err
+	}
+
+	return loadBalancers, nil
+}
+
+func (c *APIClient) UpdateLoadBalancerHealthCheck(id string, healthCheck models.HealthCheck, version int64) (*models.LoadBalancer, error) {
 	req := models.UpdateLoadBalancerHealthCheckRequest{
 		HealthCheck: healthCheck,
 	}
 
 	var loadBalancer *models.LoadBalancer
-	if err := c.Execute(c.Sling("loadbalancer/").Put(id+"/healthcheck").BodyJSON(req), &loadBalancer); err != nil {
+	if err := c.Execute(c.IfMatch(c.Sling("loadbalancer/"), version).Put(id+"/healthcheck").BodyJSON(req), &loadBalancer); err != nil {
 		return nil, err
 	}
 
 	return loadBalancer, nil
 }
 
-func (c *APIClient) UpdateLoadBalancerPorts(id string, ports []models.Port) (*models.LoadBalancer, error) {
+func (c *APIClient) UpdateLoadBalancerPorts(id string, ports []models.Port, version int64) (*models.LoadBalancer, error) {
 	req := models.UpdateLoadBalancerPortsRequest{
 		Ports: ports,
 	}
 
 	var loadBalancer *models.LoadBalancer
-	if err := c.Execute(c.Sling("loadbalancer/").Put(id+"/ports").BodyJSON(req), &loadBalancer); err != nil {
+	if err := c.Execute(c.IfMatch(c.Sling("loadbalancer/"), version).Put(id+"/ports").BodyJSON(req), &loadBalancer); err != nil {
 		return nil, err
 	}
 
 	return loadBalancer, nil
 }
 
-func (c *APIClient) UpdateLoadBalancerIdleTimeout(id string, idleTimeout int) (*models.LoadBalancer, error) {
+func (c *APIClient) UpdateLoadBalancerIdleTimeout(id string, idleTimeout int, version int64) (*models.LoadBalancer, error) {
 	req := models.UpdateLoadBalancerIdleTimeoutRequest{
 		IdleTimeout: idleTimeout,
 	}
 
 	var loadBalancer *models.LoadBalancer
-	if err := c.Execute(c.Sling("loadbalancer/").Put(id+"/idletimeout").BodyJSON(req), &loadBalancer); err != nil {
+	if err := c.Execute(c.IfMatch(c.Sling("loadbalancer/"), version).Put(id+"/idletimeout").BodyJSON(req), &loadBalancer); err != nil {
 		return nil, err
 	}
 
 	return loadBalancer, nil
 }
 
-func (c *APIClient) UpdateLoadBalancerCrossZone(id string, crossZone bool) (*models.LoadBalancer, error) {
+func (c *APIClient) UpdateLoadBalancerCrossZone(id string, crossZone bool, version int64) (*models.LoadBalancer, error) {
 	req := models.UpdateLoadBalancerCrossZoneRequest{
 		CrossZone: crossZone,
 	}
 
 	var loadBalancer *models.LoadBalancer
-	if err := c.Execute(c.Sling("loadbalancer/").Put(id+"/crosszone").BodyJSON(req), &loadBalancer); err != nil {
+	if err := c.Execute(c.IfMatch(c.Sling("loadbalancer/"), version).Put(id+"/crosszone").BodyJSON(req), &loadBalancer); err != nil {
 		return nil, err
 	}
 
@@ -46,18 +46,23 @@ func TestCreateLoadBalancer(t *testing.T) {
 		testutils.AssertEqual(t, req.Ports, ports)
 		testutils.AssertEqual(t, req.IdleTimeout, 60)
 
-		MarshalAndWrite(t, w, models.LoadBalancer{LoadBalancerID: "id"}, 200)
+		headers := map[string]string{
+			"Location": "/job/jobid",
+			"X-JobID":  "jobid",
+		}
+
+		MarshalAndWriteHeader(t, w, "", headers, 202)
 	}
 
 	client, server := newClientAndServer(handler)
 	defer server.Close()
 
-	loadBalancer, err := client.CreateLoadBalancer("name", "environmentID", healthCheck, ports, true, 60, true)
+	jobID, err := client.CreateLoadBalancer("name", "environmentID", healthCheck, ports, true, 60, true)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	testutils.AssertEqual(t, loadBalancer.LoadBalancerID, "id")
+	testutils.AssertEqual(t, jobID, "jobid")
 }
 
 func TestDeleteLoadBalancer(t *testing.T) {
@@ -153,7 +158,7 @@ func TestUpdateLoadBalancerHealthCheck(t *testing.T) {
 	client, server := newClientAndServer(handler)
 	defer server.Close()
 
-	loadBalancer, err := client.UpdateLoadBalancerHealthCheck("id", healthCheck)
+	loadBalancer, err := client.UpdateLoadBalancerHealthCheck("id", healthCheck, AnyVersion)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -192,7 +197,7 @@ func TestUpdateLoadBalancerPorts(t *testing.T) {
 	client, server := newClientAndServer(handler)
 	defer server.Close()
 
-	loadBalancer, err := client.UpdateLoadBalancerPorts("id", ports)
+	loadBalancer, err := client.UpdateLoadBalancerPorts("id", ports, AnyVersion)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -218,7 +223,7 @@ func TestUpdateLoadBalancerIdleTimeout(t *testing.T) {
 	client, server := newClientAndServer(handler)
 	defer server.Close()
 
-	loadBalancer, err := client.UpdateLoadBalancerIdleTimeout("id", idleTimeout)
+	loadBalancer, err := client.UpdateLoadBalancerIdleTimeout("id", idleTimeout, AnyVersion)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -244,7 +249,7 @@ func TestUpdateLoadBalancerCrossZone(t *testing.T) {
 	client, server := newClientAndServer(handler)
 	defer server.Close()
 
-	loadBalancer, err := client.UpdateLoadBalancerCrossZone("id", crossZone)
+	loadBalancer, err := client.UpdateLoadBalancerCrossZone("id", crossZone, AnyVersion)
 	if err != nil {
 		t.Fatal(err)
 	}
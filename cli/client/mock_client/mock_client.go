@@ -34,6 +34,32 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// CancelJob mocks base method
+func (m *MockClient) CancelJob(arg0 string) (*models.Job, error) {
+	ret := m.ctrl.Call(m, "CancelJob", arg0)
+	ret0, _ := ret[0].(*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelJob indicates an expected call of CancelJob
+func (mr *MockClientMockRecorder) CancelJob(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelJob", reflect.TypeOf((*MockClient)(nil).CancelJob), arg0)
+}
+
+// CloneEnvironment mocks base method
+func (m *MockClient) CloneEnvironment(arg0 string, arg1 models.CloneEnvironmentRequest) (string, error) {
+	ret := m.ctrl.Call(m, "CloneEnvironment", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloneEnvironment indicates an expected call of CloneEnvironment
+func (mr *MockClientMockRecorder) CloneEnvironment(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloneEnvironment", reflect.TypeOf((*MockClient)(nil).CloneEnvironment), arg0, arg1)
+}
+
 // CreateDeploy mocks base method
 func (m *MockClient) CreateDeploy(arg0 string, arg1 []byte) (*models.Deploy, error) {
 	ret := m.ctrl.Call(m, "CreateDeploy", arg0, arg1)
@@ -48,16 +74,16 @@ func (mr *MockClientMockRecorder) CreateDeploy(arg0, arg1 interface{}) *gomock.C
 }
 
 // CreateEnvironment mocks base method
-func (m *MockClient) CreateEnvironment(arg0, arg1 string, arg2 int, arg3 []byte, arg4, arg5 string) (*models.Environment, error) {
-	ret := m.ctrl.Call(m, "CreateEnvironment", arg0, arg1, arg2, arg3, arg4, arg5)
-	ret0, _ := ret[0].(*models.Environment)
+func (m *MockClient) CreateEnvironment(arg0, arg1 string, arg2 int, arg3 []byte, arg4, arg5, arg6 string, arg7 models.ScalerSettings) (string, error) {
+	ret := m.ctrl.Call(m, "CreateEnvironment", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateEnvironment indicates an expected call of CreateEnvironment
-func (mr *MockClientMockRecorder) CreateEnvironment(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEnvironment", reflect.TypeOf((*MockClient)(nil).CreateEnvironment), arg0, arg1, arg2, arg3, arg4, arg5)
+func (mr *MockClientMockRecorder) CreateEnvironment(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEnvironment", reflect.TypeOf((*MockClient)(nil).CreateEnvironment), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 }
 
 // CreateLink mocks base method
@@ -73,9 +99,9 @@ func (mr *MockClientMockRecorder) CreateLink(arg0, arg1 interface{}) *gomock.Cal
 }
 
 // CreateLoadBalancer mocks base method
-func (m *MockClient) CreateLoadBalancer(arg0, arg1 string, arg2 models.HealthCheck, arg3 []models.Port, arg4 bool, arg5 int, arg6 bool) (*models.LoadBalancer, error) {
+func (m *MockClient) CreateLoadBalancer(arg0, arg1 string, arg2 models.HealthCheck, arg3 []models.Port, arg4 bool, arg5 int, arg6 bool) (string, error) {
 	ret := m.ctrl.Call(m, "CreateLoadBalancer", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
-	ret0, _ := ret[0].(*models.LoadBalancer)
+	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -98,6 +124,18 @@ func (mr *MockClientMockRecorder) CreateService(arg0, arg1, arg2, arg3 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateService", reflect.TypeOf((*MockClient)(nil).CreateService), arg0, arg1, arg2, arg3)
 }
 
+// CreateTag mocks base method
+func (m *MockClient) CreateTag(arg0 models.Tag) error {
+	ret := m.ctrl.Call(m, "CreateTag", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTag indicates an expected call of CreateTag
+func (mr *MockClientMockRecorder) CreateTag(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTag", reflect.TypeOf((*MockClient)(nil).CreateTag), arg0)
+}
+
 // CreateTask mocks base method
 func (m *MockClient) CreateTask(arg0, arg1, arg2 string, arg3 []models.ContainerOverride) (string, error) {
 	ret := m.ctrl.Call(m, "CreateTask", arg0, arg1, arg2, arg3)
@@ -111,6 +149,19 @@ func (mr *MockClientMockRecorder) CreateTask(arg0, arg1, arg2, arg3 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTask", reflect.TypeOf((*MockClient)(nil).CreateTask), arg0, arg1, arg2, arg3)
 }
 
+// CreateWorkflow mocks base method
+func (m *MockClient) CreateWorkflow(arg0 models.CreateWorkflowRequest) (string, error) {
+	ret := m.ctrl.Call(m, "CreateWorkflow", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkflow indicates an expected call of CreateWorkflow
+func (mr *MockClientMockRecorder) CreateWorkflow(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkflow", reflect.TypeOf((*MockClient)(nil).CreateWorkflow), arg0)
+}
+
 // Delete mocks base method
 func (m *MockClient) Delete(arg0 string) error {
 	ret := m.ctrl.Call(m, "Delete", arg0)
@@ -186,6 +237,18 @@ func (mr *MockClientMockRecorder) DeleteService(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteService", reflect.TypeOf((*MockClient)(nil).DeleteService), arg0)
 }
 
+// DeleteTag mocks base method
+func (m *MockClient) DeleteTag(arg0 models.Tag) error {
+	ret := m.ctrl.Call(m, "DeleteTag", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTag indicates an expected call of DeleteTag
+func (mr *MockClientMockRecorder) DeleteTag(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockClient)(nil).DeleteTag), arg0)
+}
+
 // DeleteTask mocks base method
 func (m *MockClient) DeleteTask(arg0 string) error {
 	ret := m.ctrl.Call(m, "DeleteTask", arg0)
@@ -198,6 +261,19 @@ func (mr *MockClientMockRecorder) DeleteTask(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockClient)(nil).DeleteTask), arg0)
 }
 
+// GetBackup mocks base method
+func (m *MockClient) GetBackup() (*models.Backup, error) {
+	ret := m.ctrl.Call(m, "GetBackup")
+	ret0, _ := ret[0].(*models.Backup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBackup indicates an expected call of GetBackup
+func (mr *MockClientMockRecorder) GetBackup() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBackup", reflect.TypeOf((*MockClient)(nil).GetBackup))
+}
+
 // GetConfig mocks base method
 func (m *MockClient) GetConfig() (*models.APIConfig, error) {
 	ret := m.ctrl.Call(m, "GetConfig")
@@ -250,6 +326,19 @@ func (mr *MockClientMockRecorder) GetJob(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJob", reflect.TypeOf((*MockClient)(nil).GetJob), arg0)
 }
 
+// GetJobLogs mocks base method
+func (m *MockClient) GetJobLogs(arg0, arg1, arg2 string, arg3 int) ([]*models.LogFile, error) {
+	ret := m.ctrl.Call(m, "GetJobLogs", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*models.LogFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobLogs indicates an expected call of GetJobLogs
+func (mr *MockClientMockRecorder) GetJobLogs(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobLogs", reflect.TypeOf((*MockClient)(nil).GetJobLogs), arg0, arg1, arg2, arg3)
+}
+
 // GetLoadBalancer mocks base method
 func (m *MockClient) GetLoadBalancer(arg0 string) (*models.LoadBalancer, error) {
 	ret := m.ctrl.Call(m, "GetLoadBalancer", arg0)
@@ -328,6 +417,45 @@ func (mr *MockClientMockRecorder) GetVersion() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*MockClient)(nil).GetVersion))
 }
 
+// GetWorkflow mocks base method
+func (m *MockClient) GetWorkflow(arg0 string) (*models.Workflow, error) {
+	ret := m.ctrl.Call(m, "GetWorkflow", arg0)
+	ret0, _ := ret[0].(*models.Workflow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkflow indicates an expected call of GetWorkflow
+func (mr *MockClientMockRecorder) GetWorkflow(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkflow", reflect.TypeOf((*MockClient)(nil).GetWorkflow), arg0)
+}
+
+// ImportEntities mocks base method
+func (m *MockClient) ImportEntities(arg0 models.ImportRequest) (*models.ImportReport, error) {
+	ret := m.ctrl.Call(m, "ImportEntities", arg0)
+	ret0, _ := ret[0].(*models.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportEntities indicates an expected call of ImportEntities
+func (mr *MockClientMockRecorder) ImportEntities(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportEntities", reflect.TypeOf((*MockClient)(nil).ImportEntities), arg0)
+}
+
+// ListArchivedJobs mocks base method
+func (m *MockClient) ListArchivedJobs() ([]*models.Job, error) {
+	ret := m.ctrl.Call(m, "ListArchivedJobs")
+	ret0, _ := ret[0].([]*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListArchivedJobs indicates an expected call of ListArchivedJobs
+func (mr *MockClientMockRecorder) ListArchivedJobs() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListArchivedJobs", reflect.TypeOf((*MockClient)(nil).ListArchivedJobs))
+}
+
 // ListDeploys mocks base method
 func (m *MockClient) ListDeploys() ([]*models.DeploySummary, error) {
 	ret := m.ctrl.Call(m, "ListDeploys")
@@ -341,6 +469,19 @@ func (mr *MockClientMockRecorder) ListDeploys() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeploys", reflect.TypeOf((*MockClient)(nil).ListDeploys))
 }
 
+// ListDeploysBySelector mocks base method
+func (m *MockClient) ListDeploysBySelector(arg0 string) ([]*models.DeploySummary, error) {
+	ret := m.ctrl.Call(m, "ListDeploysBySelector", arg0)
+	ret0, _ := ret[0].([]*models.DeploySummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeploysBySelector indicates an expected call of ListDeploysBySelector
+func (mr *MockClientMockRecorder) ListDeploysBySelector(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeploysBySelector", reflect.TypeOf((*MockClient)(nil).ListDeploysBySelector), arg0)
+}
+
 // ListEnvironments mocks base method
 func (m *MockClient) ListEnvironments() ([]*models.EnvironmentSummary, error) {
 	ret := m.ctrl.Call(m, "ListEnvironments")
@@ -354,6 +495,19 @@ func (mr *MockClientMockRecorder) ListEnvironments() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEnvironments", reflect.TypeOf((*MockClient)(nil).ListEnvironments))
 }
 
+// ListEnvironmentsBySelector mocks base method
+func (m *MockClient) ListEnvironmentsBySelector(arg0 string) ([]*models.EnvironmentSummary, error) {
+	ret := m.ctrl.Call(m, "ListEnvironmentsBySelector", arg0)
+	ret0, _ := ret[0].([]*models.EnvironmentSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEnvironmentsBySelector indicates an expected call of ListEnvironmentsBySelector
+func (mr *MockClientMockRecorder) ListEnvironmentsBySelector(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEnvironmentsBySelector", reflect.TypeOf((*MockClient)(nil).ListEnvironmentsBySelector), arg0)
+}
+
 // ListJobs mocks base method
 func (m *MockClient) ListJobs() ([]*models.Job, error) {
 	ret := m.ctrl.Call(m, "ListJobs")
@@ -367,6 +521,19 @@ func (mr *MockClientMockRecorder) ListJobs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobs", reflect.TypeOf((*MockClient)(nil).ListJobs))
 }
 
+// ListJobsByEntity mocks base method
+func (m *MockClient) ListJobsByEntity(arg0, arg1 string) ([]*models.Job, error) {
+	ret := m.ctrl.Call(m, "ListJobsByEntity", arg0, arg1)
+	ret0, _ := ret[0].([]*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJobsByEntity indicates an expected call of ListJobsByEntity
+func (mr *MockClientMockRecorder) ListJobsByEntity(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobsByEntity", reflect.TypeOf((*MockClient)(nil).ListJobsByEntity), arg0, arg1)
+}
+
 // ListLoadBalancers mocks base method
 func (m *MockClient) ListLoadBalancers() ([]*models.LoadBalancerSummary, error) {
 	ret := m.ctrl.Call(m, "ListLoadBalancers")
@@ -380,6 +547,32 @@ func (mr *MockClientMockRecorder) ListLoadBalancers() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoadBalancers", reflect.TypeOf((*MockClient)(nil).ListLoadBalancers))
 }
 
+// ListLoadBalancersBySelector mocks base method
+func (m *MockClient) ListLoadBalancersBySelector(arg0 string) ([]*models.LoadBalancerSummary, error) {
+	ret := m.ctrl.Call(m, "ListLoadBalancersBySelector", arg0)
+	ret0, _ := ret[0].([]*models.LoadBalancerSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLoadBalancersBySelector indicates an expected call of ListLoadBalancersBySelector
+func (mr *MockClientMockRecorder) ListLoadBalancersBySelector(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoadBalancersBySelector", reflect.TypeOf((*MockClient)(nil).ListLoadBalancersBySelector), arg0)
+}
+
+// ListScalerHistory mocks base method
+func (m *MockClient) ListScalerHistory(arg0 string, arg1 int) ([]*models.ScalerRun, error) {
+	ret := m.ctrl.Call(m, "ListScalerHistory", arg0, arg1)
+	ret0, _ := ret[0].([]*models.ScalerRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScalerHistory indicates an expected call of ListScalerHistory
+func (mr *MockClientMockRecorder) ListScalerHistory(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScalerHistory", reflect.TypeOf((*MockClient)(nil).ListScalerHistory), arg0, arg1)
+}
+
 // ListServices mocks base method
 func (m *MockClient) ListServices() ([]*models.ServiceSummary, error) {
 	ret := m.ctrl.Call(m, "ListServices")
@@ -393,6 +586,19 @@ func (mr *MockClientMockRecorder) ListServices() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServices", reflect.TypeOf((*MockClient)(nil).ListServices))
 }
 
+// ListServicesBySelector mocks base method
+func (m *MockClient) ListServicesBySelector(arg0 string) ([]*models.ServiceSummary, error) {
+	ret := m.ctrl.Call(m, "ListServicesBySelector", arg0)
+	ret0, _ := ret[0].([]*models.ServiceSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServicesBySelector indicates an expected call of ListServicesBySelector
+func (mr *MockClientMockRecorder) ListServicesBySelector(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServicesBySelector", reflect.TypeOf((*MockClient)(nil).ListServicesBySelector), arg0)
+}
+
 // ListTasks mocks base method
 func (m *MockClient) ListTasks() ([]*models.TaskSummary, error) {
 	ret := m.ctrl.Call(m, "ListTasks")
@@ -406,6 +612,58 @@ func (mr *MockClientMockRecorder) ListTasks() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockClient)(nil).ListTasks))
 }
 
+// ListTasksBySelector mocks base method
+func (m *MockClient) ListTasksBySelector(arg0 string) ([]*models.TaskSummary, error) {
+	ret := m.ctrl.Call(m, "ListTasksBySelector", arg0)
+	ret0, _ := ret[0].([]*models.TaskSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTasksBySelector indicates an expected call of ListTasksBySelector
+func (mr *MockClientMockRecorder) ListTasksBySelector(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasksBySelector", reflect.TypeOf((*MockClient)(nil).ListTasksBySelector), arg0)
+}
+
+// ReconcileTags mocks base method
+func (m *MockClient) ReconcileTags(arg0 bool) (*models.TagReconciliationReport, error) {
+	ret := m.ctrl.Call(m, "ReconcileTags", arg0)
+	ret0, _ := ret[0].(*models.TagReconciliationReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileTags indicates an expected call of ReconcileTags
+func (mr *MockClientMockRecorder) ReconcileTags(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileTags", reflect.TypeOf((*MockClient)(nil).ReconcileTags), arg0)
+}
+
+// Restore mocks base method
+func (m *MockClient) Restore(arg0 models.RestoreRequest) (*models.RestoreReport, error) {
+	ret := m.ctrl.Call(m, "Restore", arg0)
+	ret0, _ := ret[0].(*models.RestoreReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore
+func (mr *MockClientMockRecorder) Restore(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockClient)(nil).Restore), arg0)
+}
+
+// RetryJob mocks base method
+func (m *MockClient) RetryJob(arg0 string) (*models.Job, error) {
+	ret := m.ctrl.Call(m, "RetryJob", arg0)
+	ret0, _ := ret[0].(*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetryJob indicates an expected call of RetryJob
+func (mr *MockClientMockRecorder) RetryJob(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryJob", reflect.TypeOf((*MockClient)(nil).RetryJob), arg0)
+}
+
 // RunScaler mocks base method
 func (m *MockClient) RunScaler(arg0 string) (*models.ScalerRunInfo, error) {
 	ret := m.ctrl.Call(m, "RunScaler", arg0)
@@ -419,17 +677,30 @@ func (mr *MockClientMockRecorder) RunScaler(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunScaler", reflect.TypeOf((*MockClient)(nil).RunScaler), arg0)
 }
 
+// SaveBackup mocks base method
+func (m *MockClient) SaveBackup() (*models.BackupSummary, error) {
+	ret := m.ctrl.Call(m, "SaveBackup")
+	ret0, _ := ret[0].(*models.BackupSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveBackup indicates an expected call of SaveBackup
+func (mr *MockClientMockRecorder) SaveBackup() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBackup", reflect.TypeOf((*MockClient)(nil).SaveBackup))
+}
+
 // ScaleService mocks base method
-func (m *MockClient) ScaleService(arg0 string, arg1 int) (*models.Service, error) {
-	ret := m.ctrl.Call(m, "ScaleService", arg0, arg1)
-	ret0, _ := ret[0].(*models.Service)
+func (m *MockClient) ScaleService(arg0 string, arg1 int, arg2 int64) (string, error) {
+	ret := m.ctrl.Call(m, "ScaleService", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ScaleService indicates an expected call of ScaleService
-func (mr *MockClientMockRecorder) ScaleService(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScaleService", reflect.TypeOf((*MockClient)(nil).ScaleService), arg0, arg1)
+func (mr *MockClientMockRecorder) ScaleService(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScaleService", reflect.TypeOf((*MockClient)(nil).ScaleService), arg0, arg1, arg2)
 }
 
 // SelectByQuery mocks base method
@@ -445,69 +716,108 @@ func (mr *MockClientMockRecorder) SelectByQuery(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectByQuery", reflect.TypeOf((*MockClient)(nil).SelectByQuery), arg0)
 }
 
+// SimulateScaler mocks base method
+func (m *MockClient) SimulateScaler(arg0 string, arg1 models.ScalerSimulationRequest) (*models.ScalerRunInfo, error) {
+	ret := m.ctrl.Call(m, "SimulateScaler", arg0, arg1)
+	ret0, _ := ret[0].(*models.ScalerRunInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SimulateScaler indicates an expected call of SimulateScaler
+func (mr *MockClientMockRecorder) SimulateScaler(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimulateScaler", reflect.TypeOf((*MockClient)(nil).SimulateScaler), arg0, arg1)
+}
+
 // UpdateEnvironment mocks base method
-func (m *MockClient) UpdateEnvironment(arg0 string, arg1 int) (*models.Environment, error) {
-	ret := m.ctrl.Call(m, "UpdateEnvironment", arg0, arg1)
+func (m *MockClient) UpdateEnvironment(arg0 string, arg1 int, arg2 int64) (*models.Environment, error) {
+	ret := m.ctrl.Call(m, "UpdateEnvironment", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*models.Environment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateEnvironment indicates an expected call of UpdateEnvironment
-func (mr *MockClientMockRecorder) UpdateEnvironment(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEnvironment", reflect.TypeOf((*MockClient)(nil).UpdateEnvironment), arg0, arg1)
+func (mr *MockClientMockRecorder) UpdateEnvironment(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEnvironment", reflect.TypeOf((*MockClient)(nil).UpdateEnvironment), arg0, arg1, arg2)
+}
+
+// UpdateEnvironmentPlacementStrategy mocks base method
+func (m *MockClient) UpdateEnvironmentPlacementStrategy(arg0, arg1 string, arg2 int64) (*models.Environment, error) {
+	ret := m.ctrl.Call(m, "UpdateEnvironmentPlacementStrategy", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Environment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEnvironmentPlacementStrategy indicates an expected call of UpdateEnvironmentPlacementStrategy
+func (mr *MockClientMockRecorder) UpdateEnvironmentPlacementStrategy(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEnvironmentPlacementStrategy", reflect.TypeOf((*MockClient)(nil).UpdateEnvironmentPlacementStrategy), arg0, arg1, arg2)
+}
+
+// UpdateEnvironmentScalerSettings mocks base method
+func (m *MockClient) UpdateEnvironmentScalerSettings(arg0 string, arg1 models.ScalerSettings, arg2 int64) (*models.Environment, error) {
+	ret := m.ctrl.Call(m, "UpdateEnvironmentScalerSettings", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Environment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEnvironmentScalerSettings indicates an expected call of UpdateEnvironmentScalerSettings
+func (mr *MockClientMockRecorder) UpdateEnvironmentScalerSettings(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEnvironmentScalerSettings", reflect.TypeOf((*MockClient)(nil).UpdateEnvironmentScalerSettings), arg0, arg1, arg2)
 }
 
 // UpdateLoadBalancerCrossZone mocks base method
-func (m *MockClient) UpdateLoadBalancerCrossZone(arg0 string, arg1 bool) (*models.LoadBalancer, error) {
-	ret := m.ctrl.Call(m, "UpdateLoadBalancerCrossZone", arg0, arg1)
+func (m *MockClient) UpdateLoadBalancerCrossZone(arg0 string, arg1 bool, arg2 int64) (*models.LoadBalancer, error) {
+	ret := m.ctrl.Call(m, "UpdateLoadBalancerCrossZone", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*models.LoadBalancer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateLoadBalancerCrossZone indicates an expected call of UpdateLoadBalancerCrossZone
-func (mr *MockClientMockRecorder) UpdateLoadBalancerCrossZone(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerCrossZone", reflect.TypeOf((*MockClient)(nil).UpdateLoadBalancerCrossZone), arg0, arg1)
+func (mr *MockClientMockRecorder) UpdateLoadBalancerCrossZone(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerCrossZone", reflect.TypeOf((*MockClient)(nil).UpdateLoadBalancerCrossZone), arg0, arg1, arg2)
 }
 
 // UpdateLoadBalancerHealthCheck mocks base method
-func (m *MockClient) UpdateLoadBalancerHealthCheck(arg0 string, arg1 models.HealthCheck) (*models.LoadBalancer, error) {
-	ret := m.ctrl.Call(m, "UpdateLoadBalancerHealthCheck", arg0, arg1)
+func (m *MockClient) UpdateLoadBalancerHealthCheck(arg0 string, arg1 models.HealthCheck, arg2 int64) (*models.LoadBalancer, error) {
+	ret := m.ctrl.Call(m, "UpdateLoadBalancerHealthCheck", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*models.LoadBalancer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateLoadBalancerHealthCheck indicates an expected call of UpdateLoadBalancerHealthCheck
-func (mr *MockClientMockRecorder) UpdateLoadBalancerHealthCheck(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerHealthCheck", reflect.TypeOf((*MockClient)(nil).UpdateLoadBalancerHealthCheck), arg0, arg1)
+func (mr *MockClientMockRecorder) UpdateLoadBalancerHealthCheck(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerHealthCheck", reflect.TypeOf((*MockClient)(nil).UpdateLoadBalancerHealthCheck), arg0, arg1, arg2)
 }
 
 // UpdateLoadBalancerIdleTimeout mocks base method
-func (m *MockClient) UpdateLoadBalancerIdleTimeout(arg0 string, arg1 int) (*models.LoadBalancer, error) {
-	ret := m.ctrl.Call(m, "UpdateLoadBalancerIdleTimeout", arg0, arg1)
+func (m *MockClient) UpdateLoadBalancerIdleTimeout(arg0 string, arg1 int, arg2 int64) (*models.LoadBalancer, error) {
+	ret := m.ctrl.Call(m, "UpdateLoadBalancerIdleTimeout", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*models.LoadBalancer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateLoadBalancerIdleTimeout indicates an expected call of UpdateLoadBalancerIdleTimeout
-func (mr *MockClientMockRecorder) UpdateLoadBalancerIdleTimeout(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerIdleTimeout", reflect.TypeOf((*MockClient)(nil).UpdateLoadBalancerIdleTimeout), arg0, arg1)
+func (mr *MockClientMockRecorder) UpdateLoadBalancerIdleTimeout(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerIdleTimeout", reflect.TypeOf((*MockClient)(nil).UpdateLoadBalancerIdleTimeout), arg0, arg1, arg2)
 }
 
 // UpdateLoadBalancerPorts mocks base method
-func (m *MockClient) UpdateLoadBalancerPorts(arg0 string, arg1 []models.Port) (*models.LoadBalancer, error) {
-	ret := m.ctrl.Call(m, "UpdateLoadBalancerPorts", arg0, arg1)
+func (m *MockClient) UpdateLoadBalancerPorts(arg0 string, arg1 []models.Port, arg2 int64) (*models.LoadBalancer, error) {
+	ret := m.ctrl.Call(m, "UpdateLoadBalancerPorts", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*models.LoadBalancer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateLoadBalancerPorts indicates an expected call of UpdateLoadBalancerPorts
-func (mr *MockClientMockRecorder) UpdateLoadBalancerPorts(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerPorts", reflect.TypeOf((*MockClient)(nil).UpdateLoadBalancerPorts), arg0, arg1)
+func (mr *MockClientMockRecorder) UpdateLoadBalancerPorts(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerPorts", reflect.TypeOf((*MockClient)(nil).UpdateLoadBalancerPorts), arg0, arg1, arg2)
 }
 
 // UpdateSQL mocks base method
@@ -523,16 +833,16 @@ func (mr *MockClientMockRecorder) UpdateSQL() *gomock.Call {
 }
 
 // UpdateService mocks base method
-func (m *MockClient) UpdateService(arg0, arg1 string) (*models.Service, error) {
-	ret := m.ctrl.Call(m, "UpdateService", arg0, arg1)
-	ret0, _ := ret[0].(*models.Service)
+func (m *MockClient) UpdateService(arg0, arg1 string, arg2 int64) (string, error) {
+	ret := m.ctrl.Call(m, "UpdateService", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateService indicates an expected call of UpdateService
-func (mr *MockClientMockRecorder) UpdateService(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateService", reflect.TypeOf((*MockClient)(nil).UpdateService), arg0, arg1)
+func (mr *MockClientMockRecorder) UpdateService(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateService", reflect.TypeOf((*MockClient)(nil).UpdateService), arg0, arg1, arg2)
 }
 
 // WaitForDeployment mocks base method
@@ -0,0 +1,239 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/quintilesims/layer0/common/models"
+)
+
+// openAPIDocument is the subset of api/openapi.json the client is checked against
+type openAPIDocument struct {
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components struct {
+		Schemas map[string]openAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+type openAPIOperation struct {
+	RequestBody *struct {
+		Content map[string]struct {
+			Schema openAPISchema `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+}
+
+type openAPISchema struct {
+	Ref        string                   `json:"$ref"`
+	Properties map[string]openAPISchema `json:"properties"`
+}
+
+type recordedRequest struct {
+	Method string
+	Path   string
+	Body   []byte
+}
+
+// these methods only poll endpoints that are already covered
+var openAPIExcludedMethods = map[string]bool{
+	"WaitForDeployment": true,
+	"WaitForJob":        true,
+}
+
+func openAPIClientCalls() map[string]func(c *APIClient) {
+	return map[string]func(c *APIClient){
+		"CreateDeploy":          func(c *APIClient) { c.CreateDeploy("name", []byte("{}")) },
+		"DeleteDeploy":          func(c *APIClient) { c.DeleteDeploy("id") },
+		"GetDeploy":             func(c *APIClient) { c.GetDeploy("id") },
+		"ListDeploys":           func(c *APIClient) { c.ListDeploys() },
+		"ListDeploysBySelector": func(c *APIClient) { c.ListDeploysBySelector("team=payments") },
+		"CloneEnvironment": func(c *APIClient) {
+			c.CloneEnvironment("id", models.CloneEnvironmentRequest{EnvironmentName: "staging"})
+		},
+		"CreateEnvironment": func(c *APIClient) {
+			c.CreateEnvironment("name", "m3.medium", 1, []byte("user_data"), "linux", "ami", "binpack", models.ScalerSettings{})
+		},
+		"UpdateEnvironmentPlacementStrategy": func(c *APIClient) {
+			c.UpdateEnvironmentPlacementStrategy("id", "spread-zone", 1)
+		},
+		"UpdateEnvironmentScalerSettings": func(c *APIClient) {
+			c.UpdateEnvironmentScalerSettings("id", models.ScalerSettings{MaxClusterCount: 5}, 1)
+		},
+		"DeleteEnvironment":          func(c *APIClient) { c.DeleteEnvironment("id") },
+		"GetEnvironment":             func(c *APIClient) { c.GetEnvironment("id") },
+		"ListEnvironments":           func(c *APIClient) { c.ListEnvironments() },
+		"ListEnvironmentsBySelector": func(c *APIClient) { c.ListEnvironmentsBySelector("team=payments") },
+		"ListScalerHistory":          func(c *APIClient) { c.ListScalerHistory("id", 10) },
+		"UpdateEnvironment":          func(c *APIClient) { c.UpdateEnvironment("id", 1, 1) },
+		"CreateLink":                 func(c *APIClient) { c.CreateLink("id1", "id2") },
+		"DeleteLink":                 func(c *APIClient) { c.DeleteLink("id1", "id2") },
+		"CancelJob":                  func(c *APIClient) { c.CancelJob("id") },
+		"CreateWorkflow":             func(c *APIClient) { c.CreateWorkflow(models.CreateWorkflowRequest{}) },
+		"Delete":                     func(c *APIClient) { c.Delete("id") },
+		"GetJob":                     func(c *APIClient) { c.GetJob("id") },
+		"GetJobLogs":                 func(c *APIClient) { c.GetJobLogs("id", "start", "end", 100) },
+		"GetWorkflow":                func(c *APIClient) { c.GetWorkflow("id") },
+		"ListArchivedJobs":           func(c *APIClient) { c.ListArchivedJobs() },
+		"ListJobs":                   func(c *APIClient) { c.ListJobs() },
+		"ListJobsByEntity":           func(c *APIClient) { c.ListJobsByEntity("service", "id") },
+		"RetryJob":                   func(c *APIClient) { c.RetryJob("id") },
+		"CreateLoadBalancer": func(c *APIClient) {
+			c.CreateLoadBalancer("name", "id", models.HealthCheck{}, []models.Port{{}}, true, 60, true)
+		},
+		"DeleteLoadBalancer":          func(c *APIClient) { c.DeleteLoadBalancer("id") },
+		"GetLoadBalancer":             func(c *APIClient) { c.GetLoadBalancer("id") },
+		"ListLoadBalancers":           func(c *APIClient) { c.ListLoadBalancers() },
+		"ListLoadBalancersBySelector": func(c *APIClient) { c.ListLoadBalancersBySelector("team=payments") },
+		"UpdateLoadBalancerHealthCheck": func(c *APIClient) {
+			c.UpdateLoadBalancerHealthCheck("id", models.HealthCheck{}, 1)
+		},
+		"UpdateLoadBalancerPorts":       func(c *APIClient) { c.UpdateLoadBalancerPorts("id", []models.Port{{}}, 1) },
+		"UpdateLoadBalancerIdleTimeout": func(c *APIClient) { c.UpdateLoadBalancerIdleTimeout("id", 60, 1) },
+		"UpdateLoadBalancerCrossZone":   func(c *APIClient) { c.UpdateLoadBalancerCrossZone("id", true, 1) },
+		"CreateService":                 func(c *APIClient) { c.CreateService("name", "id", "id", "id") },
+		"DeleteService":                 func(c *APIClient) { c.DeleteService("id") },
+		"UpdateService":                 func(c *APIClient) { c.UpdateService("id", "id", 1) },
+		"GetService":                    func(c *APIClient) { c.GetService("id") },
+		"GetServiceLogs":                func(c *APIClient) { c.GetServiceLogs("id", "start", "end", 100) },
+		"ListServices":                  func(c *APIClient) { c.ListServices() },
+		"ListServicesBySelector":        func(c *APIClient) { c.ListServicesBySelector("team=payments") },
+		"ScaleService":                  func(c *APIClient) { c.ScaleService("id", 2, 1) },
+		"CreateTask": func(c *APIClient) {
+			c.CreateTask("name", "id", "id", []models.ContainerOverride{{}})
+		},
+		"SimulateScaler": func(c *APIClient) {
+			c.SimulateScaler("id", models.ScalerSimulationRequest{Tasks: []models.SimulatedTask{{DeployID: "id", Copies: 1}}})
+		},
+		"DeleteTask":          func(c *APIClient) { c.DeleteTask("id") },
+		"GetTask":             func(c *APIClient) { c.GetTask("id") },
+		"GetTaskLogs":         func(c *APIClient) { c.GetTaskLogs("id", "start", "end", 100) },
+		"ListTasks":           func(c *APIClient) { c.ListTasks() },
+		"ListTasksBySelector": func(c *APIClient) { c.ListTasksBySelector("team=payments") },
+		"CreateTag": func(c *APIClient) {
+			c.CreateTag(models.Tag{EntityType: "service", EntityID: "id", Key: "team", Value: "payments"})
+		},
+		"DeleteTag":      func(c *APIClient) { c.DeleteTag(models.Tag{EntityType: "service", EntityID: "id", Key: "team"}) },
+		"SelectByQuery":  func(c *APIClient) { c.SelectByQuery(map[string]string{"type": "service"}) },
+		"GetVersion":     func(c *APIClient) { c.GetVersion() },
+		"GetConfig":      func(c *APIClient) { c.GetConfig() },
+		"UpdateSQL":      func(c *APIClient) { c.UpdateSQL() },
+		"RunScaler":      func(c *APIClient) { c.RunScaler("id") },
+		"GetBackup":      func(c *APIClient) { c.GetBackup() },
+		"SaveBackup":     func(c *APIClient) { c.SaveBackup() },
+		"Restore":        func(c *APIClient) { c.Restore(models.RestoreRequest{Key: "backups/key.json"}) },
+		"ReconcileTags":  func(c *APIClient) { c.ReconcileTags(false) },
+		"ImportEntities": func(c *APIClient) { c.ImportEntities(models.ImportRequest{}) },
+	}
+}
+
+func loadOpenAPIDocument(t *testing.T) *openAPIDocument {
+	data, err := ioutil.ReadFile("../../api/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var document *openAPIDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		t.Fatal(err)
+	}
+
+	return document
+}
+
+// matchOperation returns the operation in the document that serves the specified request
+func (d *openAPIDocument) matchOperation(method, path string) (*openAPIOperation, bool) {
+	parameter := regexp.MustCompile(`\{[^/]+\}`)
+	for template, operations := range d.Paths {
+		parts := parameter.Split(template, -1)
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+
+		expr := "^" + strings.Join(parts, "[^/]+") + "/?$"
+		if !regexp.MustCompile(expr).MatchString(path) {
+			continue
+		}
+
+		if operation, ok := operations[strings.ToLower(method)]; ok {
+			return &operation, true
+		}
+	}
+
+	return nil, false
+}
+
+func (d *openAPIDocument) resolve(schema openAPISchema) openAPISchema {
+	if schema.Ref != "" {
+		return d.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+
+	return schema
+}
+
+func TestClientMatchesOpenAPIDocument(t *testing.T) {
+	document := loadOpenAPIDocument(t)
+	calls := openAPIClientCalls()
+
+	clientType := reflect.TypeOf((*Client)(nil)).Elem()
+	for i := 0; i < clientType.NumMethod(); i++ {
+		name := clientType.Method(i).Name
+		if _, ok := calls[name]; !ok && !openAPIExcludedMethods[name] {
+			t.Errorf("Client method %s is not checked against openapi.json", name)
+		}
+	}
+
+	for name, call := range calls {
+		var requests []recordedRequest
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			requests = append(requests, recordedRequest{Method: r.Method, Path: r.URL.Path, Body: body})
+			w.Header().Set("X-JobID", "id")
+			w.WriteHeader(http.StatusAccepted)
+			fmt.Fprint(w, "{}")
+		}
+
+		client, server := newClientAndServer(handler)
+		call(client)
+		server.Close()
+
+		if len(requests) == 0 {
+			t.Errorf("%s: no request was made", name)
+		}
+
+		for _, request := range requests {
+			operation, ok := document.matchOperation(request.Method, request.Path)
+			if !ok {
+				t.Errorf("%s: %s %s is not in openapi.json", name, request.Method, request.Path)
+				continue
+			}
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(request.Body, &body); err != nil {
+				// only object bodies are checked against the request schema
+				continue
+			}
+
+			if operation.RequestBody == nil {
+				t.Errorf("%s: %s %s sends a body but openapi.json declares none", name, request.Method, request.Path)
+				continue
+			}
+
+			schema := document.resolve(operation.RequestBody.Content["application/json"].Schema)
+			for key := range body {
+				if _, ok := schema.Properties[key]; !ok {
+					t.Errorf("%s: field '%s' is not in the request schema for %s %s", name, key, request.Method, request.Path)
+				}
+			}
+		}
+	}
+}
@@ -37,17 +37,17 @@ func (c *APIClient) DeleteService(id string) (string, error) {
 	return jobID, nil
 }
 
-func (c *APIClient) UpdateService(serviceID, deployID string) (*models.Service, error) {
+func (c *APIClient) UpdateService(serviceID, deployID string, version int64) (string, error) {
 	request := models.UpdateServiceRequest{
 		DeployID: deployID,
 	}
 
-	var service *models.Service
-	if err := c.Execute(c.Sling("service/").Put(serviceID+"/deploy").BodyJSON(request), &service); err != nil {
-		return nil, err
+	jobID, err := c.ExecuteWithJob(c.IfMatch(c.Sling("service/"), version).Put(serviceID + "/deploy").BodyJSON(request))
+	if err != nil {
+		return "", err
 	}
 
-	return service, nil
+	return jobID, nil
 }
 
 func (c *APIClient) GetService(id string) (*models.Service, error) {
@@ -92,17 +92,30 @@ func (c *APIClient) ListServices() ([]*models.ServiceSummary, error) {
 	return services, nil
 }
 
-func (c *APIClient) ScaleService(id string, count int) (*models.Service, error) {
+// ListServicesBySelector returns the services whose tags match the selector, e.g. 'team=payments'
+func (c *APIClient) ListServicesBySelector(selector string) ([]*models.ServiceSummary, error) {
+	query := url.Values{}
+	query.Set("selector", selector)
+
+	var services []*models.ServiceSummary
+	if err := c.Execute(c.Sling("service/").Get("?"+query.Encode()), &services); err != nil {
+		return nil, err
+	}
+
+	return services, nil
+}
+
+func (c *APIClient) ScaleService(id string, count int, version int64) (string, error) {
 	request := models.ScaleServiceRequest{
 		DesiredCount: int64(count),
 	}
 
-	var service *models.Service
-	if err := c.Execute(c.Sling("service/").Put(id+"/scale").BodyJSON(request), &service); err != nil {
-		return nil, err
+	jobID, err := c.ExecuteWithJob(c.IfMatch(c.Sling("service/"), version).Put(id + "/scale").BodyJSON(request))
+	if err != nil {
+		return "", err
 	}
 
-	return service, nil
+	return jobID, nil
 }
 
 func (c *APIClient) WaitForDeployment(serviceID string, timeout time.Duration) (*models.Service, error) {
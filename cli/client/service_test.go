@@ -134,28 +134,59 @@ func TestListServices(t *testing.T) {
 	testutils.AssertEqual(t, services[1].ServiceID, "id2")
 }
 
+func TestListServicesBySelector(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "GET")
+		testutils.AssertEqual(t, r.URL.Path, "/service/")
+		testutils.AssertEqual(t, r.URL.Query().Get("selector"), "team=payments,tier!=dev")
+
+		services := []models.ServiceSummary{
+			{ServiceID: "id1"},
+		}
+
+		MarshalAndWrite(t, w, services, 200)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	services, err := client.ListServicesBySelector("team=payments,tier!=dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(services), 1)
+	testutils.AssertEqual(t, services[0].ServiceID, "id1")
+}
+
 func TestScaleService(t *testing.T) {
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		testutils.AssertEqual(t, r.Method, "PUT")
 		testutils.AssertEqual(t, r.URL.Path, "/service/id/scale")
+		testutils.AssertEqual(t, r.Header.Get("If-Match"), "\"3\"")
 
 		var req models.ScaleServiceRequest
 		Unmarshal(t, r, &req)
 
 		testutils.AssertEqual(t, req.DesiredCount, int64(2))
 
-		MarshalAndWrite(t, w, models.Service{ServiceID: "id"}, 200)
+		headers := map[string]string{
+			"Location": "/job/jobid",
+			"X-JobID":  "jobid",
+		}
+
+		MarshalAndWriteHeader(t, w, "", headers, 202)
 	}
 
 	client, server := newClientAndServer(handler)
 	defer server.Close()
 
-	service, err := client.ScaleService("id", 2)
+	jobID, err := client.ScaleService("id", 2, 3)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	testutils.AssertEqual(t, service.ServiceID, "id")
+	testutils.AssertEqual(t, jobID, "jobid")
 }
 
 func TestUpdateService(t *testing.T) {
@@ -168,18 +199,23 @@ func TestUpdateService(t *testing.T) {
 
 		testutils.AssertEqual(t, req.DeployID, "deployID")
 
-		MarshalAndWrite(t, w, models.Service{ServiceID: "id"}, 200)
+		headers := map[string]string{
+			"Location": "/job/jobid",
+			"X-JobID":  "jobid",
+		}
+
+		MarshalAndWriteHeader(t, w, "", headers, 202)
 	}
 
 	client, server := newClientAndServer(handler)
 	defer server.Close()
 
-	service, err := client.UpdateService("id", "deployID")
+	jobID, err := client.UpdateService("id", "deployID", AnyVersion)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	testutils.AssertEqual(t, service.ServiceID, "id")
+	testutils.AssertEqual(t, jobID, "jobid")
 }
 
 func TestWaitForDeployment(t *testing.T) {
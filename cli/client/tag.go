@@ -1,21 +1,37 @@
 package client
 
 import (
-	"fmt"
+	"net/url"
 
 	"github.com/quintilesims/layer0/common/models"
 )
 
 func (c *APIClient) SelectByQuery(params map[string]string) ([]*models.EntityWithTags, error) {
-	query := "?"
+	query := url.Values{}
 	for k, v := range params {
-		query += fmt.Sprintf("&%s=%s", k, v)
+		query.Set(k, v)
 	}
 
 	var response []*models.EntityWithTags
-	if err := c.Execute(c.Sling("/tag").Get(query), &response); err != nil {
+	if err := c.Execute(c.Sling("/tag").Get("?"+query.Encode()), &response); err != nil {
 		return nil, err
 	}
 
 	return response, nil
 }
+
+func (c *APIClient) CreateTag(tag models.Tag) error {
+	if err := c.Execute(c.Sling("tag/").Post("").BodyJSON(tag), nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *APIClient) DeleteTag(tag models.Tag) error {
+	if err := c.Execute(c.Sling("tag/").Delete("").BodyJSON(tag), nil); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -18,6 +18,7 @@ func TestSelectByQuery(t *testing.T) {
 		testutils.AssertEqual(t, query.Get("fuzz"), "some_fuzz")
 		testutils.AssertEqual(t, query.Get("version"), "some_version")
 		testutils.AssertEqual(t, query.Get("key"), "val")
+		testutils.AssertEqual(t, query.Get("selector"), "team in (a,b),tier!=dev")
 
 		tags := []models.EntityWithTags{
 			{EntityID: "id1"},
@@ -31,10 +32,11 @@ func TestSelectByQuery(t *testing.T) {
 	defer server.Close()
 
 	params := map[string]string{
-		"type":    "some_type",
-		"fuzz":    "some_fuzz",
-		"version": "some_version",
-		"key":     "val",
+		"type":     "some_type",
+		"fuzz":     "some_fuzz",
+		"version":  "some_version",
+		"key":      "val",
+		"selector": "team in (a,b),tier!=dev",
 	}
 
 	tags, err := client.SelectByQuery(params)
@@ -46,3 +48,43 @@ func TestSelectByQuery(t *testing.T) {
 	testutils.AssertEqual(t, tags[0].EntityID, "id1")
 	testutils.AssertEqual(t, tags[1].EntityID, "id2")
 }
+
+func TestCreateTag(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "POST")
+		testutils.AssertEqual(t, r.URL.Path, "/tag/")
+
+		var req models.Tag
+		Unmarshal(t, r, &req)
+
+		testutils.AssertEqual(t, req, models.Tag{EntityType: "service", EntityID: "id", Key: "team", Value: "payments"})
+		w.WriteHeader(http.StatusCreated)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	if err := client.CreateTag(models.Tag{EntityType: "service", EntityID: "id", Key: "team", Value: "payments"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteTag(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		testutils.AssertEqual(t, r.Method, "DELETE")
+		testutils.AssertEqual(t, r.URL.Path, "/tag/")
+
+		var req models.Tag
+		Unmarshal(t, r, &req)
+
+		testutils.AssertEqual(t, req, models.Tag{EntityType: "service", EntityID: "id", Key: "team"})
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client, server := newClientAndServer(handler)
+	defer server.Close()
+
+	if err := client.DeleteTag(models.Tag{EntityType: "service", EntityID: "id", Key: "team"}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -79,3 +79,16 @@ func (c *APIClient) ListTasks() ([]*models.TaskSummary, error) {
 
 	return tasks, nil
 }
+
+// ListTasksBySelector returns the tasks whose tags match the selector, e.g. 'team=payments'
+func (c *APIClient) ListTasksBySelector(selector string) ([]*models.TaskSummary, error) {
+	query := url.Values{}
+	query.Set("selector", selector)
+
+	var tasks []*models.TaskSummary
+	if err := c.Execute(c.Sling("task/").Get("?"+query.Encode()), &tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
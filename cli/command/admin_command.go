@@ -1,16 +1,30 @@
 package command
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
 	"github.com/quintilesims/layer0/common/config"
+	"github.com/quintilesims/layer0/common/models"
 	"github.com/urfave/cli"
 )
 
 type AdminCommand struct {
 	*Command
+	// Input is where the answers to confirmation prompts are read from
+	Input io.Reader
 }
 
 func NewAdminCommand(command *Command) *AdminCommand {
-	return &AdminCommand{command}
+	return &AdminCommand{
+		Command: command,
+		Input:   os.Stdin,
+	}
 }
 
 func (a *AdminCommand) GetCommand() cli.Command {
@@ -19,12 +33,72 @@ func (a *AdminCommand) GetCommand() cli.Command {
 		Usage:       "manage the layer0 api",
 		Description: "manage the Layer0 API",
 		Subcommands: []cli.Command{
+			{
+				Name:      "backup",
+				Usage:     "export tags, jobs and api config to a backup file",
+				Action:    wrapAction(a.Command, a.Backup),
+				ArgsUsage: "[PATH]",
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "s3",
+						Usage: "save the backup in the api's s3 bucket instead of writing it to PATH",
+					},
+				},
+			},
 			{
 				Name:      "debug",
 				Usage:     "generate debug information",
 				Action:    wrapAction(a.Command, a.Debug),
 				ArgsUsage: " ",
 			},
+			{
+				Name:      "import",
+				Usage:     "adopt layer0 resources in aws that are missing tags",
+				Action:    wrapAction(a.Command, a.Import),
+				ArgsUsage: " ",
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "show the tags that would be written without writing them",
+					},
+					cli.BoolFlag{
+						Name:  "yes, y",
+						Usage: "write the tags without asking for confirmation",
+					},
+				},
+			},
+			{
+				Name:      "reconcile",
+				Usage:     "delete the tags of entities that no longer exist",
+				Action:    wrapAction(a.Command, a.Reconcile),
+				ArgsUsage: " ",
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "list the orphaned tags without deleting them",
+					},
+				},
+			},
+			{
+				Name:      "restore",
+				Usage:     "import tags and jobs from a backup",
+				Action:    wrapAction(a.Command, a.Restore),
+				ArgsUsage: "[PATH]",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "key",
+						Usage: "restore the backup with the specified key from the api's s3 bucket instead of reading it from PATH",
+					},
+					cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "show what would be restored without changing anything",
+					},
+					cli.BoolFlag{
+						Name:  "overwrite",
+						Usage: "overwrite the tags whose current value differs from the backup instead of only reporting them",
+					},
+				},
+			},
 			{
 				Name:      "sql",
 				Usage:     "initialize sql settings on the layer0 api",
@@ -42,11 +116,63 @@ func (a *AdminCommand) GetCommand() cli.Command {
 				Usage:     "Run the scaler on an environment",
 				Action:    wrapAction(a.Command, a.Scale),
 				ArgsUsage: "ENVIRONMENT",
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "simulate",
+						Usage: "show the projected scaler run without changing the environment's capacity",
+					},
+					cli.StringSliceFlag{
+						Name:  "service",
+						Usage: "simulated service count in format 'SERVICE:COUNT[:DEPLOY]'; leave SERVICE empty for a new service (can be specified multiple times)",
+					},
+					cli.StringSliceFlag{
+						Name:  "task",
+						Usage: "simulated task in format 'DEPLOY:COPIES' (can be specified multiple times)",
+					},
+				},
 			},
 		},
 	}
 }
 
+func (a *AdminCommand) Backup(c *cli.Context) error {
+	if c.Bool("s3") {
+		if c.NArg() > 0 {
+			return NewUsageError("PATH cannot be used with --s3")
+		}
+
+		summary, err := a.Client.SaveBackup()
+		if err != nil {
+			return err
+		}
+
+		a.Printer.Printf("Saved backup '%s' (%d tags, %d jobs)\n", summary.Key, summary.TagCount, summary.JobCount)
+		return nil
+	}
+
+	args, err := extractArgs(c.Args(), "PATH")
+	if err != nil {
+		return err
+	}
+
+	backup, err := a.Client.GetBackup()
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(args["PATH"], content, 0600); err != nil {
+		return err
+	}
+
+	a.Printer.Printf("Wrote backup to '%s' (%d tags, %d jobs)\n", args["PATH"], len(backup.Tags), len(backup.Jobs))
+	return nil
+}
+
 func (a *AdminCommand) Debug(c *cli.Context) error {
 	apiEndpoint := config.APIEndpoint()
 	cliAuth := config.AuthToken()
@@ -79,6 +205,89 @@ func (a *AdminCommand) Debug(c *cli.Context) error {
 	return nil
 }
 
+func (a *AdminCommand) Import(c *cli.Context) error {
+	preview, err := a.Client.ImportEntities(models.ImportRequest{DryRun: true})
+	if err != nil {
+		return err
+	}
+
+	if err := a.Printer.PrintImportReport(preview); err != nil {
+		return err
+	}
+
+	if c.Bool("dry-run") || len(preview.Entities) == 0 {
+		return nil
+	}
+
+	if !c.Bool("yes") {
+		confirmed, err := confirm(a.Input, a.Printer, "Write these tags?")
+		if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			a.Printer.Printf("Import cancelled\n")
+			return nil
+		}
+	}
+
+	// the api only writes the tags if they are still the ones that were previewed
+	report, err := a.Client.ImportEntities(models.ImportRequest{Entities: preview.Entities})
+	if err != nil {
+		return err
+	}
+
+	a.Printer.Printf("Wrote %d tags for %d entities\n", report.TagsWritten, len(report.Entities))
+	return nil
+}
+
+func (a *AdminCommand) Reconcile(c *cli.Context) error {
+	report, err := a.Client.ReconcileTags(c.Bool("dry-run"))
+	if err != nil {
+		return err
+	}
+
+	return a.Printer.PrintTagReconciliationReport(report)
+}
+
+func (a *AdminCommand) Restore(c *cli.Context) error {
+	req := models.RestoreRequest{
+		Key:       c.String("key"),
+		DryRun:    c.Bool("dry-run"),
+		Overwrite: c.Bool("overwrite"),
+	}
+
+	if req.Key != "" {
+		if c.NArg() > 0 {
+			return NewUsageError("PATH cannot be used with --key")
+		}
+	} else {
+		args, err := extractArgs(c.Args(), "PATH")
+		if err != nil {
+			return err
+		}
+
+		content, err := ioutil.ReadFile(args["PATH"])
+		if err != nil {
+			return err
+		}
+
+		var backup models.Backup
+		if err := json.Unmarshal(content, &backup); err != nil {
+			return fmt.Errorf("Failed to parse backup '%s': %v", args["PATH"], err)
+		}
+
+		req.Backup = &backup
+	}
+
+	report, err := a.Client.Restore(req)
+	if err != nil {
+		return err
+	}
+
+	return a.Printer.PrintRestoreReport(report)
+}
+
 func (a *AdminCommand) SQL(c *cli.Context) error {
 	if err := a.Client.UpdateSQL(); err != nil {
 		return err
@@ -110,10 +319,94 @@ func (a *AdminCommand) Scale(c *cli.Context) error {
 		return err
 	}
 
-	runInfo, err := a.Client.RunScaler(environmentID)
+	if !c.Bool("simulate") {
+		if len(c.StringSlice("service")) > 0 || len(c.StringSlice("task")) > 0 {
+			return NewUsageError("The --service and --task flags require --simulate")
+		}
+
+		runInfo, err := a.Client.RunScaler(environmentID)
+		if err != nil {
+			return err
+		}
+
+		return a.Printer.PrintScalerRunInfo(runInfo)
+	}
+
+	req, err := a.parseSimulationRequest(c.StringSlice("service"), c.StringSlice("task"))
+	if err != nil {
+		return err
+	}
+
+	runInfo, err := a.Client.SimulateScaler(environmentID, req)
 	if err != nil {
 		return err
 	}
 
 	return a.Printer.PrintScalerRunInfo(runInfo)
 }
+
+func (a *AdminCommand) parseSimulationRequest(services, tasks []string) (models.ScalerSimulationRequest, error) {
+	req := models.ScalerSimulationRequest{
+		Services: []models.SimulatedService{},
+		Tasks:    []models.SimulatedTask{},
+	}
+
+	for _, service := range services {
+		split := strings.SplitN(service, ":", 3)
+		if len(split) < 2 {
+			return req, NewUsageError("Service '%s' is not in format SERVICE:COUNT[:DEPLOY]", service)
+		}
+
+		count, err := strconv.Atoi(split[1])
+		if err != nil {
+			return req, NewUsageError("'%s' is not a valid integer", split[1])
+		}
+
+		simulated := models.SimulatedService{DesiredCount: count}
+		if split[0] != "" {
+			serviceID, err := a.resolveSingleID("service", split[0])
+			if err != nil {
+				return req, err
+			}
+
+			simulated.ServiceID = serviceID
+		}
+
+		if len(split) == 3 && split[2] != "" {
+			deployID, err := a.resolveSingleID("deploy", split[2])
+			if err != nil {
+				return req, err
+			}
+
+			simulated.DeployID = deployID
+		}
+
+		if simulated.ServiceID == "" && simulated.DeployID == "" {
+			return req, NewUsageError("Service '%s' must specify a SERVICE, a DEPLOY, or both", service)
+		}
+
+		req.Services = append(req.Services, simulated)
+	}
+
+	for _, task := range tasks {
+		// deploy names may contain colons, so split on the last one
+		i := strings.LastIndex(task, ":")
+		if i <= 0 {
+			return req, NewUsageError("Task '%s' is not in format DEPLOY:COPIES", task)
+		}
+
+		copies, err := strconv.Atoi(task[i+1:])
+		if err != nil {
+			return req, NewUsageError("'%s' is not a valid integer", task[i+1:])
+		}
+
+		deployID, err := a.resolveSingleID("deploy", task[:i])
+		if err != nil {
+			return req, err
+		}
+
+		req.Tasks = append(req.Tasks, models.SimulatedTask{DeployID: deployID, Copies: copies})
+	}
+
+	return req, nil
+}
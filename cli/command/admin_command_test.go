@@ -1,10 +1,15 @@
 package command
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
 	"testing"
 
+	"github.com/golang/mock/gomock"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/testutils"
+	"github.com/urfave/cli"
 )
 
 func TestAdminDebug(t *testing.T) {
@@ -70,3 +75,300 @@ func TestAdminScale(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestAdminScaleSimulate(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewAdminCommand(tc.Command())
+
+	tc.Resolver.EXPECT().
+		Resolve("environment", "env").
+		Return([]string{"id"}, nil)
+
+	tc.Resolver.EXPECT().
+		Resolve("service", "svc").
+		Return([]string{"svcid"}, nil)
+
+	tc.Resolver.EXPECT().
+		Resolve("service", "svc2").
+		Return([]string{"svcid2"}, nil)
+
+	tc.Resolver.EXPECT().
+		Resolve("deploy", "dpl:2").
+		Return([]string{"dplid"}, nil).
+		Times(2)
+
+	tc.Resolver.EXPECT().
+		Resolve("deploy", "dpl:1").
+		Return([]string{"taskdplid"}, nil)
+
+	req := models.ScalerSimulationRequest{
+		Services: []models.SimulatedService{
+			{ServiceID: "svcid", DesiredCount: 3},
+			{ServiceID: "svcid2", DeployID: "dplid", DesiredCount: 2},
+			{DeployID: "dplid", DesiredCount: 1},
+		},
+		Tasks: []models.SimulatedTask{
+			{DeployID: "taskdplid", Copies: 4},
+		},
+	}
+
+	tc.Client.EXPECT().
+		SimulateScaler("id", req).
+		Return(&models.ScalerRunInfo{}, nil)
+
+	flags := map[string]interface{}{
+		"simulate": true,
+		"service":  []string{"svc:3", "svc2:2:dpl:2", ":1:dpl:2"},
+		"task":     []string{"dpl:1:4"},
+	}
+
+	c := testutils.GetCLIContext(t, []string{"env"}, flags)
+	if err := command.Scale(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAdminScale_userInputErrors(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewAdminCommand(tc.Command())
+
+	tc.Resolver.EXPECT().
+		Resolve(gomock.Any(), gomock.Any()).
+		Return([]string{"id"}, nil).
+		AnyTimes()
+
+	contexts := map[string]*cli.Context{
+		"Missing ENVIRONMENT arg": testutils.GetCLIContext(t, nil, nil),
+		"Service without simulate": testutils.GetCLIContext(t, []string{"env"}, map[string]interface{}{
+			"service": []string{"svc:1"},
+		}),
+		"Task without simulate": testutils.GetCLIContext(t, []string{"env"}, map[string]interface{}{
+			"task": []string{"dpl:1"},
+		}),
+		"Service without COUNT": testutils.GetCLIContext(t, []string{"env"}, map[string]interface{}{
+			"simulate": true,
+			"service":  []string{"svc"},
+		}),
+		"Non-integer service COUNT": testutils.GetCLIContext(t, []string{"env"}, map[string]interface{}{
+			"simulate": true,
+			"service":  []string{"svc:2w"},
+		}),
+		"Service without SERVICE or DEPLOY": testutils.GetCLIContext(t, []string{"env"}, map[string]interface{}{
+			"simulate": true,
+			"service":  []string{":1"},
+		}),
+		"Task without COPIES": testutils.GetCLIContext(t, []string{"env"}, map[string]interface{}{
+			"simulate": true,
+			"task":     []string{"dpl"},
+		}),
+		"Non-integer task COPIES": testutils.GetCLIContext(t, []string{"env"}, map[string]interface{}{
+			"simulate": true,
+			"task":     []string{"dpl:2w"},
+		}),
+	}
+
+	for name, c := range contexts {
+		if err := command.Scale(c); err == nil {
+			t.Fatalf("%s: error was nil!", name)
+		}
+	}
+}
+
+func TestAdminBackup(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewAdminCommand(tc.Command())
+
+	file, close := tempFile(t, "")
+	defer close()
+
+	backup := &models.Backup{
+		Version: 1,
+		Tags:    models.Tags{{EntityType: "service", EntityID: "sid", Key: "name", Value: "svc"}},
+	}
+
+	tc.Client.EXPECT().
+		GetBackup().
+		Return(backup, nil)
+
+	c := testutils.GetCLIContext(t, []string{file.Name()}, nil)
+	if err := command.Backup(c); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var written models.Backup
+	if err := json.Unmarshal(content, &written); err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, written.Version, 1)
+	testutils.AssertEqual(t, written.Tags, backup.Tags)
+}
+
+func TestAdminBackupS3(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewAdminCommand(tc.Command())
+
+	tc.Client.EXPECT().
+		SaveBackup().
+		Return(&models.BackupSummary{Key: "backups/key.json"}, nil)
+
+	c := testutils.GetCLIContext(t, nil, map[string]interface{}{"s3": true})
+	if err := command.Backup(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAdminBackup_userInputErrors(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewAdminCommand(tc.Command())
+
+	contexts := map[string]*cli.Context{
+		"Missing PATH arg": testutils.GetCLIContext(t, nil, nil),
+		"PATH with s3":     testutils.GetCLIContext(t, []string{"path"}, map[string]interface{}{"s3": true}),
+	}
+
+	for name, c := range contexts {
+		if err := command.Backup(c); err == nil {
+			t.Fatalf("%s: error was nil!", name)
+		}
+	}
+}
+
+func TestAdminImport(t *testing.T) {
+	preview := &models.ImportReport{
+		DryRun:   true,
+		Entities: []models.EntityWithTags{{EntityType: "deploy", EntityID: "dpl.1"}},
+	}
+
+	cases := map[string]struct {
+		Input  string
+		Flags  map[string]interface{}
+		Import bool
+	}{
+		"Confirmed":    {Input: "y\n", Import: true},
+		"Cancelled":    {Input: "n\n", Import: false},
+		"No answer":    {Input: "", Import: false},
+		"Yes flag":     {Flags: map[string]interface{}{"yes": true}, Import: true},
+		"Dry run flag": {Flags: map[string]interface{}{"dry-run": true}, Import: false},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc, ctrl := newTestCommand(t)
+			defer ctrl.Finish()
+			command := NewAdminCommand(tc.Command())
+			command.Input = strings.NewReader(c.Input)
+
+			tc.Client.EXPECT().
+				ImportEntities(models.ImportRequest{DryRun: true}).
+				Return(preview, nil)
+
+			if c.Import {
+				tc.Client.EXPECT().
+					ImportEntities(models.ImportRequest{Entities: preview.Entities}).
+					Return(&models.ImportReport{TagsWritten: 1}, nil)
+			}
+
+			if err := command.Import(testutils.GetCLIContext(t, nil, c.Flags)); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestAdminImportNothingToImport(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewAdminCommand(tc.Command())
+
+	tc.Client.EXPECT().
+		ImportEntities(models.ImportRequest{DryRun: true}).
+		Return(&models.ImportReport{DryRun: true}, nil)
+
+	c := testutils.GetCLIContext(t, nil, map[string]interface{}{"yes": true})
+	if err := command.Import(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAdminReconcile(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewAdminCommand(tc.Command())
+
+	tc.Client.EXPECT().
+		ReconcileTags(true).
+		Return(&models.TagReconciliationReport{DryRun: true}, nil)
+
+	c := testutils.GetCLIContext(t, nil, map[string]interface{}{"dry-run": true})
+	if err := command.Reconcile(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAdminRestore(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewAdminCommand(tc.Command())
+
+	file, close := tempFile(t, `{"version": 1, "tags": [{"entity_type": "service", "entity_id": "sid", "key": "name", "value": "svc"}]}`)
+	defer close()
+
+	tc.Client.EXPECT().
+		Restore(gomock.Any()).
+		Do(func(req models.RestoreRequest) {
+			testutils.AssertEqual(t, req.Key, "")
+			testutils.AssertEqual(t, req.DryRun, true)
+			testutils.AssertEqual(t, req.Overwrite, true)
+			testutils.AssertEqual(t, req.Backup.Version, 1)
+			testutils.AssertEqual(t, len(req.Backup.Tags), 1)
+		}).
+		Return(&models.RestoreReport{}, nil)
+
+	c := testutils.GetCLIContext(t, []string{file.Name()}, map[string]interface{}{"dry-run": true, "overwrite": true})
+	if err := command.Restore(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAdminRestoreKey(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewAdminCommand(tc.Command())
+
+	tc.Client.EXPECT().
+		Restore(models.RestoreRequest{Key: "backups/key.json"}).
+		Return(&models.RestoreReport{}, nil)
+
+	c := testutils.GetCLIContext(t, nil, map[string]interface{}{"key": "backups/key.json"})
+	if err := command.Restore(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAdminRestore_userInputErrors(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewAdminCommand(tc.Command())
+
+	contexts := map[string]*cli.Context{
+		"Missing PATH arg": testutils.GetCLIContext(t, nil, nil),
+		"PATH with key":    testutils.GetCLIContext(t, []string{"path"}, map[string]interface{}{"key": "backups/key.json"}),
+	}
+
+	for name, c := range contexts {
+		if err := command.Restore(c); err == nil {
+			t.Fatalf("%s: error was nil!", name)
+		}
+	}
+}
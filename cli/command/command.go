@@ -58,23 +58,30 @@ func (cm *Command) deleteWithJob(c *cli.Context, entityType string, deleteEntity
 			return err
 		}
 
-		if !c.Bool("wait") {
-			cm.Printer.Printf("This operation is running as a job. Run `l0 job get %s` to see progress\n", jobID)
-			return nil
-		}
+		_, err = cm.waitForJob(c, jobID, "Deleting")
+		return err
+	})
+}
 
-		timeout, err := getTimeout(c)
-		if err != nil {
-			return err
-		}
+// waitForJob will run the Client.WaitForJob() function if the 'wait' flag is specified.
+// Otherwise, it prints how to follow the job's progress and returns false
+func (cm *Command) waitForJob(c *cli.Context, jobID, spinnerPrefix string) (bool, error) {
+	if !c.Bool("wait") {
+		cm.Printer.Printf("This operation is running as a job. Run `l0 job get %s` to see progress\n", jobID)
+		return false, nil
+	}
 
-		cm.Printer.StartSpinner("Deleting")
-		if err := cm.Client.WaitForJob(jobID, timeout); err != nil {
-			return err
-		}
+	timeout, err := getTimeout(c)
+	if err != nil {
+		return false, err
+	}
 
-		return nil
-	})
+	cm.Printer.StartSpinner(spinnerPrefix)
+	if err := cm.Client.WaitForJob(jobID, timeout); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
 // delete will fetch the NAME arg and use it to resolve the entity id of the specified type
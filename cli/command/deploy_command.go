@@ -45,6 +45,7 @@ func (d *DeployCommand) GetCommand() cli.Command {
 				Action:    wrapAction(d.Command, d.List),
 				ArgsUsage: " ",
 				Flags: []cli.Flag{
+					selectorFlag("deploys"),
 					cli.BoolFlag{
 						Name:  "all",
 						Usage: "list all versions of all deploys",
@@ -98,7 +99,12 @@ func (d *DeployCommand) Get(c *cli.Context) error {
 }
 
 func (d *DeployCommand) List(c *cli.Context) error {
-	deploySummaries, err := d.Client.ListDeploys()
+	listDeploys := d.Client.ListDeploys
+	if selector := c.String("selector"); selector != "" {
+		listDeploys = func() ([]*models.DeploySummary, error) { return d.Client.ListDeploysBySelector(selector) }
+	}
+
+	deploySummaries, err := listDeploys()
 	if err != nil {
 		return err
 	}
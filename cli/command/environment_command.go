@@ -3,7 +3,9 @@ package command
 import (
 	"io/ioutil"
 	"strconv"
+	"strings"
 
+	"github.com/quintilesims/layer0/cli/client"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/urfave/cli"
 )
@@ -26,7 +28,7 @@ func (e *EnvironmentCommand) GetCommand() cli.Command {
 				Usage:     "create a new environment",
 				Action:    wrapAction(e.Command, e.Create),
 				ArgsUsage: "NAME",
-				Flags: []cli.Flag{
+				Flags: append([]cli.Flag{
 					cli.StringFlag{
 						Name:  "size",
 						Value: "m3.medium",
@@ -50,6 +52,39 @@ func (e *EnvironmentCommand) GetCommand() cli.Command {
 						Name:  "ami",
 						Usage: "specifies a custom AMI ID to use in the environment",
 					},
+					cli.StringFlag{
+						Name:  "placement-strategy",
+						Value: "binpack",
+						Usage: "how the scaler places resources on instances: 'binpack', 'spread-instance' or 'spread-zone'",
+					},
+					cli.BoolFlag{
+						Name:  "wait",
+						Usage: "wait for the job to complete before returning",
+					},
+				}, scalerSettingsFlags()...),
+			},
+			{
+				Name:      "clone",
+				Usage:     "create a copy of an environment with its load balancers, services, links and tags",
+				Action:    wrapAction(e.Command, e.Clone),
+				ArgsUsage: "SOURCE NAME",
+				Flags: []cli.Flag{
+					cli.StringSliceFlag{
+						Name:  "load-balancer",
+						Usage: "name of a load balancer's copy in format 'OLD:NEW' (can be specified multiple times)",
+					},
+					cli.StringSliceFlag{
+						Name:  "service",
+						Usage: "name of a service's copy in format 'OLD:NEW' (can be specified multiple times)",
+					},
+					cli.StringSliceFlag{
+						Name:  "scale",
+						Usage: "desired count of a service's copy in format 'SERVICE:COUNT' (can be specified multiple times)",
+					},
+					cli.BoolFlag{
+						Name:  "wait",
+						Usage: "wait for the job to complete before returning",
+					},
 				},
 			},
 			{
@@ -75,6 +110,9 @@ func (e *EnvironmentCommand) GetCommand() cli.Command {
 				Usage:     "list all environments",
 				Action:    wrapAction(e.Command, e.List),
 				ArgsUsage: " ",
+				Flags: []cli.Flag{
+					selectorFlag("environments"),
+				},
 			},
 			{
 				Name:      "setmincount",
@@ -82,6 +120,32 @@ func (e *EnvironmentCommand) GetCommand() cli.Command {
 				Action:    wrapAction(e.Command, e.SetMinCount),
 				ArgsUsage: "NAME COUNT",
 			},
+			{
+				Name:      "setplacement",
+				Usage:     "set how the scaler places resources on the instances of an environment cluster",
+				Action:    wrapAction(e.Command, e.SetPlacementStrategy),
+				ArgsUsage: "NAME STRATEGY",
+			},
+			{
+				Name:      "setscaler",
+				Usage:     "set the headroom, scale down cooldown and max instance count the scaler uses for an environment cluster",
+				Action:    wrapAction(e.Command, e.SetScalerSettings),
+				ArgsUsage: "NAME",
+				Flags:     scalerSettingsFlags(),
+			},
+			{
+				Name:      "scaler-history",
+				Usage:     "list the most recent scaler runs for an environment",
+				Action:    wrapAction(e.Command, e.ScalerHistory),
+				ArgsUsage: "NAME",
+				Flags: []cli.Flag{
+					cli.IntFlag{
+						Name:  "limit",
+						Value: 20,
+						Usage: "maximum number of runs to list (0 lists every run)",
+					},
+				},
+			},
 			{
 				Name:      "link",
 				Usage:     "links two environments together",
@@ -114,7 +178,34 @@ func (e *EnvironmentCommand) Create(c *cli.Context) error {
 		userData = content
 	}
 
-	environment, err := e.Client.CreateEnvironment(args["NAME"], c.String("size"), c.Int("min-count"), userData, c.String("os"), c.String("ami"))
+	jobID, err := e.Client.CreateEnvironment(
+		args["NAME"],
+		c.String("size"),
+		c.Int("min-count"),
+		userData,
+		c.String("os"),
+		c.String("ami"),
+		c.String("placement-strategy"),
+		models.ScalerSettings{
+			HeadroomInstances: c.Int("headroom-instances"),
+			HeadroomMemory:    c.Int("headroom-memory"),
+			ScaleDownCooldown: c.Int("scale-down-cooldown"),
+			MaxClusterCount:   c.Int("max-count"),
+		})
+	if err != nil {
+		return err
+	}
+
+	if waited, err := e.waitForJob(c, jobID, "Creating"); err != nil || !waited {
+		return err
+	}
+
+	job, err := e.Client.GetJob(jobID)
+	if err != nil {
+		return err
+	}
+
+	environment, err := e.Client.GetEnvironment(job.Meta["environment_id"])
 	if err != nil {
 		return err
 	}
@@ -122,6 +213,86 @@ func (e *EnvironmentCommand) Create(c *cli.Context) error {
 	return e.Printer.PrintEnvironments(environment)
 }
 
+func (e *EnvironmentCommand) Clone(c *cli.Context) error {
+	args, err := extractArgs(c.Args(), "SOURCE", "NAME")
+	if err != nil {
+		return err
+	}
+
+	loadBalancerNames, err := parsePairs(c.StringSlice("load-balancer"), "OLD:NEW")
+	if err != nil {
+		return err
+	}
+
+	serviceNames, err := parsePairs(c.StringSlice("service"), "OLD:NEW")
+	if err != nil {
+		return err
+	}
+
+	counts, err := parsePairs(c.StringSlice("scale"), "SERVICE:COUNT")
+	if err != nil {
+		return err
+	}
+
+	scale := map[string]int64{}
+	for service, count := range counts {
+		n, err := strconv.ParseInt(count, 10, 64)
+		if err != nil || n < 0 {
+			return NewUsageError("'%s' is not a valid count for service '%s'", count, service)
+		}
+
+		scale[service] = n
+	}
+
+	id, err := e.resolveSingleID("environment", args["SOURCE"])
+	if err != nil {
+		return err
+	}
+
+	req := models.CloneEnvironmentRequest{
+		EnvironmentName:   args["NAME"],
+		LoadBalancerNames: loadBalancerNames,
+		ServiceNames:      serviceNames,
+		Scale:             scale,
+	}
+
+	jobID, err := e.Client.CloneEnvironment(id, req)
+	if err != nil {
+		return err
+	}
+
+	if waited, err := e.waitForJob(c, jobID, "Cloning"); err != nil || !waited {
+		return err
+	}
+
+	job, err := e.Client.GetJob(jobID)
+	if err != nil {
+		return err
+	}
+
+	environment, err := e.Client.GetEnvironment(job.Meta["environment_id"])
+	if err != nil {
+		return err
+	}
+
+	return e.Printer.PrintEnvironments(environment)
+}
+
+// parsePairs parses values in format 'KEY:VALUE'; format describes the expected format in errors
+func parsePairs(values []string, format string) (map[string]string, error) {
+	pairs := map[string]string{}
+	for _, v := range values {
+		split := strings.SplitN(v, ":", 2)
+		if len(split) != 2 || split[0] == "" || split[1] == "" {
+			return nil, NewUsageError("'%s' is not in format '%s'", v, format)
+		}
+
+		pairs[split[0]] = split[1]
+	}
+
+	return pairs, nil
+}
+
 func (e *EnvironmentCommand) Delete(c *cli.Context) error {
 	return e.deleteWithJob(c, "environment", e.Client.DeleteEnvironment)
 }
@@ -146,7 +317,12 @@ func (e *EnvironmentCommand) Get(c *cli.Context) error {
 }
 
 func (e *EnvironmentCommand) List(c *cli.Context) error {
-	environmentSummaries, err := e.Client.ListEnvironments()
+	listEnvironments := e.Client.ListEnvironments
+	if selector := c.String("selector"); selector != "" {
+		listEnvironments = func() ([]*models.EnvironmentSummary, error) { return e.Client.ListEnvironmentsBySelector(selector) }
+	}
+
+	environmentSummaries, err := listEnvironments()
 	if err != nil {
 		return err
 	}
@@ -170,7 +346,7 @@ func (e *EnvironmentCommand) SetMinCount(c *cli.Context) error {
 		return err
 	}
 
-	environment, err := e.Client.UpdateEnvironment(id, int(count))
+	environment, err := e.Client.UpdateEnvironment(id, int(count), client.AnyVersion)
 	if err != nil {
 		return err
 	}
@@ -178,6 +354,114 @@ func (e *EnvironmentCommand) SetMinCount(c *cli.Context) error {
 	return e.Printer.PrintEnvironments(environment)
 }
 
+func (e *EnvironmentCommand) SetPlacementStrategy(c *cli.Context) error {
+	args, err := extractArgs(c.Args(), "NAME", "STRATEGY")
+	if err != nil {
+		return err
+	}
+
+	id, err := e.resolveSingleID("environment", args["NAME"])
+	if err != nil {
+		return err
+	}
+
+	environment, err := e.Client.UpdateEnvironmentPlacementStrategy(id, args["STRATEGY"], client.AnyVersion)
+	if err != nil {
+		return err
+	}
+
+	return e.Printer.PrintEnvironments(environment)
+}
+
+func (e *EnvironmentCommand) SetScalerSettings(c *cli.Context) error {
+	args, err := extractArgs(c.Args(), "NAME")
+	if err != nil {
+		return err
+	}
+
+	id, err := e.resolveSingleID("environment", args["NAME"])
+	if err != nil {
+		return err
+	}
+
+	environment, err := e.Client.GetEnvironment(id)
+	if err != nil {
+		return err
+	}
+
+	// only change the settings that were specified
+	settings := environment.ScalerSettings
+	flags := map[string]*int{
+		"headroom-instances":  &settings.HeadroomInstances,
+		"headroom-memory":     &settings.HeadroomMemory,
+		"scale-down-cooldown": &settings.ScaleDownCooldown,
+		"max-count":           &settings.MaxClusterCount,
+	}
+
+	var isSet bool
+	for name, setting := range flags {
+		if c.IsSet(name) {
+			*setting = c.Int(name)
+			isSet = true
+		}
+	}
+
+	if !isSet {
+		return NewUsageError("At least one of --headroom-instances, --headroom-memory, --scale-down-cooldown or --max-count is required")
+	}
+
+	environment, err = e.Client.UpdateEnvironmentScalerSettings(id, settings, environment.Version)
+	if err != nil {
+		return err
+	}
+
+	return e.Printer.PrintEnvironments(environment)
+}
+
+func (e *EnvironmentCommand) ScalerHistory(c *cli.Context) error {
+	args, err := extractArgs(c.Args(), "NAME")
+	if err != nil {
+		return err
+	}
+
+	if c.Int("limit") < 0 {
+		return NewUsageError("--limit cannot be negative")
+	}
+
+	id, err := e.resolveSingleID("environment", args["NAME"])
+	if err != nil {
+		return err
+	}
+
+	runs, err := e.Client.ListScalerHistory(id, c.Int("limit"))
+	if err != nil {
+		return err
+	}
+
+	return e.Printer.PrintScalerRuns(runs...)
+}
+
+func scalerSettingsFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.IntFlag{
+			Name:  "headroom-instances",
+			Usage: "number of empty instances the scaler keeps in the environment cluster",
+		},
+		cli.IntFlag{
+			Name:  "headroom-memory",
+			Usage: "amount of unused memory, in MiB, the scaler keeps across the environment cluster",
+		},
+		cli.IntFlag{
+			Name:  "scale-down-cooldown",
+			Usage: "number of seconds the scaler waits after scaling the environment cluster before scaling it down",
+		},
+		cli.IntFlag{
+			Name:  "max-count",
+			Usage: "maximum number of instances the scaler runs in the environment cluster (0 means no limit)",
+		},
+	}
+}
+
 func (e *EnvironmentCommand) Link(c *cli.Context) error {
 	args, err := extractArgs(c.Args(), "SOURCE", "DESTINATION")
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/quintilesims/layer0/cli/client"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/testutils"
 	"github.com/urfave/cli"
@@ -18,15 +19,51 @@ func TestCreateEnvironment(t *testing.T) {
 	defer close()
 
 	tc.Client.EXPECT().
-		CreateEnvironment("name", "m3.large", 2, []byte("user_data"), "linux", "ami").
+		CreateEnvironment("name", "m3.large", 2, []byte("user_data"), "linux", "ami", "spread-zone", models.ScalerSettings{HeadroomInstances: 1, MaxClusterCount: 4}).
+		Return("jobid", nil)
+
+	flags := map[string]interface{}{
+		"size":               "m3.large",
+		"min-count":          2,
+		"user-data":          file.Name(),
+		"os":                 "linux",
+		"ami":                "ami",
+		"placement-strategy": "spread-zone",
+		"headroom-instances": 1,
+		"max-count":          4,
+	}
+
+	c := testutils.GetCLIContext(t, []string{"name"}, flags)
+	if err := command.Create(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateEnvironmentWait(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewEnvironmentCommand(tc.Command())
+
+	tc.Client.EXPECT().
+		CreateEnvironment("name", "m3.medium", 0, nil, "linux", "", "", models.ScalerSettings{}).
+		Return("jobid", nil)
+
+	tc.Client.EXPECT().
+		WaitForJob("jobid", testutils.TEST_TIMEOUT).
+		Return(nil)
+
+	tc.Client.EXPECT().
+		GetJob("jobid").
+		Return(&models.Job{Meta: map[string]string{"environment_id": "id"}}, nil)
+
+	tc.Client.EXPECT().
+		GetEnvironment("id").
 		Return(&models.Environment{}, nil)
 
 	flags := map[string]interface{}{
-		"size":      "m3.large",
-		"min-count": 2,
-		"user-data": file.Name(),
-		"os":        "linux",
-		"ami":       "ami",
+		"size": "m3.medium",
+		"os":   "linux",
+		"wait": true,
 	}
 
 	c := testutils.GetCLIContext(t, []string{"name"}, flags)
@@ -51,6 +88,71 @@ func TestCreateEnvironment_userInputErrors(t *testing.T) {
 	}
 }
 
+func TestCloneEnvironment(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewEnvironmentCommand(tc.Command())
+
+	tc.Resolver.EXPECT().
+		Resolve("environment", "prod").
+		Return([]string{"id"}, nil)
+
+	req := models.CloneEnvironmentRequest{
+		EnvironmentName:   "staging",
+		LoadBalancerNames: map[string]string{"api": "staging-api"},
+		ServiceNames:      map[string]string{},
+		Scale:             map[string]int64{"api": 1, "worker": 0},
+	}
+
+	tc.Client.EXPECT().
+		CloneEnvironment("id", req).
+		Return("jobid", nil)
+
+	tc.Client.EXPECT().
+		WaitForJob("jobid", testutils.TEST_TIMEOUT).
+		Return(nil)
+
+	tc.Client.EXPECT().
+		GetJob("jobid").
+		Return(&models.Job{Meta: map[string]string{"environment_id": "staging_id"}}, nil)
+
+	tc.Client.EXPECT().
+		GetEnvironment("staging_id").
+		Return(&models.Environment{}, nil)
+
+	flags := map[string]interface{}{
+		"load-balancer": []string{"api:staging-api"},
+		"scale":         []string{"api:1", "worker:0"},
+		"wait":          true,
+	}
+
+	c := testutils.GetCLIContext(t, []string{"prod", "staging"}, flags)
+	if err := command.Clone(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCloneEnvironment_userInputErrors(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewEnvironmentCommand(tc.Command())
+
+	contexts := map[string]*cli.Context{
+		"Missing SOURCE arg":   testutils.GetCLIContext(t, nil, nil),
+		"Missing NAME arg":     testutils.GetCLIContext(t, []string{"prod"}, nil),
+		"Malformed rename":     testutils.GetCLIContext(t, []string{"prod", "staging"}, map[string]interface{}{"service": []string{"api"}}),
+		"Missing rename value": testutils.GetCLIContext(t, []string{"prod", "staging"}, map[string]interface{}{"load-balancer": []string{"api:"}}),
+		"Non-integer scale":    testutils.GetCLIContext(t, []string{"prod", "staging"}, map[string]interface{}{"scale": []string{"api:two"}}),
+		"Negative scale":       testutils.GetCLIContext(t, []string{"prod", "staging"}, map[string]interface{}{"scale": []string{"api:-1"}}),
+	}
+
+	for name, c := range contexts {
+		if err := command.Clone(c); err == nil {
+			t.Fatalf("%s: error was nil!", name)
+		}
+	}
+}
+
 func TestDeleteEnvironment(t *testing.T) {
 	tc, ctrl := newTestCommand(t)
 	defer ctrl.Finish()
@@ -169,7 +271,7 @@ func TestEnvironmentSetMinCount(t *testing.T) {
 		Return([]string{"id"}, nil)
 
 	tc.Client.EXPECT().
-		UpdateEnvironment("id", 2).
+		UpdateEnvironment("id", 2, client.AnyVersion).
 		Return(&models.Environment{}, nil)
 
 	c := testutils.GetCLIContext(t, []string{"name", "2"}, nil)
@@ -196,6 +298,142 @@ func TestEnvironmentSetMinCount_userInputErrors(t *testing.T) {
 	}
 }
 
+func TestEnvironmentSetPlacementStrategy(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewEnvironmentCommand(tc.Command())
+
+	tc.Resolver.EXPECT().
+		Resolve("environment", "name").
+		Return([]string{"id"}, nil)
+
+	tc.Client.EXPECT().
+		UpdateEnvironmentPlacementStrategy("id", "spread-instance", client.AnyVersion).
+		Return(&models.Environment{}, nil)
+
+	c := testutils.GetCLIContext(t, []string{"name", "spread-instance"}, nil)
+	if err := command.SetPlacementStrategy(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnvironmentSetPlacementStrategy_userInputErrors(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewEnvironmentCommand(tc.Command())
+
+	contexts := map[string]*cli.Context{
+		"Missing NAME arg":     testutils.GetCLIContext(t, nil, nil),
+		"Missing STRATEGY arg": testutils.GetCLIContext(t, []string{"name"}, nil),
+	}
+
+	for name, c := range contexts {
+		if err := command.SetPlacementStrategy(c); err == nil {
+			t.Fatalf("%s: error was nil!", name)
+		}
+	}
+}
+
+func TestEnvironmentSetScalerSettings(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewEnvironmentCommand(tc.Command())
+
+	tc.Resolver.EXPECT().
+		Resolve("environment", "name").
+		Return([]string{"id"}, nil)
+
+	environment := &models.Environment{
+		ScalerSettings: models.ScalerSettings{HeadroomInstances: 1, MaxClusterCount: 4},
+		Version:        2,
+	}
+
+	tc.Client.EXPECT().
+		GetEnvironment("id").
+		Return(environment, nil)
+
+	tc.Client.EXPECT().
+		UpdateEnvironmentScalerSettings("id", models.ScalerSettings{HeadroomInstances: 1, ScaleDownCooldown: 300, MaxClusterCount: 8}, int64(2)).
+		Return(&models.Environment{}, nil)
+
+	flags := map[string]interface{}{
+		"headroom-instances":  0,
+		"headroom-memory":     0,
+		"scale-down-cooldown": 0,
+		"max-count":           0,
+	}
+
+	c := testutils.GetCLIContext(t, []string{"--scale-down-cooldown", "300", "--max-count", "8", "name"}, flags)
+	if err := command.SetScalerSettings(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnvironmentSetScalerSettings_userInputErrors(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewEnvironmentCommand(tc.Command())
+
+	tc.Resolver.EXPECT().
+		Resolve("environment", "name").
+		Return([]string{"id"}, nil)
+
+	tc.Client.EXPECT().
+		GetEnvironment("id").
+		Return(&models.Environment{}, nil)
+
+	contexts := map[string]*cli.Context{
+		"Missing NAME arg": testutils.GetCLIContext(t, nil, nil),
+		"Missing settings": testutils.GetCLIContext(t, []string{"name"}, nil),
+	}
+
+	for name, c := range contexts {
+		if err := command.SetScalerSettings(c); err == nil {
+			t.Fatalf("%s: error was nil!", name)
+		}
+	}
+}
+
+func TestEnvironmentScalerHistory(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewEnvironmentCommand(tc.Command())
+
+	tc.Resolver.EXPECT().
+		Resolve("environment", "name").
+		Return([]string{"id"}, nil)
+
+	tc.Client.EXPECT().
+		ListScalerHistory("id", 5).
+		Return([]*models.ScalerRun{}, nil)
+
+	flags := map[string]interface{}{
+		"limit": 5,
+	}
+
+	c := testutils.GetCLIContext(t, []string{"name"}, flags)
+	if err := command.ScalerHistory(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnvironmentScalerHistory_userInputErrors(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewEnvironmentCommand(tc.Command())
+
+	contexts := map[string]*cli.Context{
+		"Missing NAME arg": testutils.GetCLIContext(t, nil, nil),
+		"Negative limit":   testutils.GetCLIContext(t, []string{"name"}, map[string]interface{}{"limit": -1}),
+	}
+
+	for name, c := range contexts {
+		if err := command.ScalerHistory(c); err == nil {
+			t.Fatalf("%s: error was nil!", name)
+		}
+	}
+}
+
 func TestEnvironmentLink(t *testing.T) {
 	tc, ctrl := newTestCommand(t)
 	defer ctrl.Finish()
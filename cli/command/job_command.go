@@ -1,10 +1,21 @@
 package command
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"time"
+
 	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/types"
 	"github.com/urfave/cli"
 )
 
+// jobWatchInterval is the time between polls in 'l0 job watch'
+var jobWatchInterval = time.Second * 5
+
 type JobCommand struct {
 	*Command
 }
@@ -19,6 +30,12 @@ func (j *JobCommand) GetCommand() cli.Command {
 		Usage:    "manage layer0 jobs",
 		HideHelp: true,
 		Subcommands: []cli.Command{
+			{
+				Name:      "cancel",
+				Usage:     "cancel a pending or in-progress job",
+				Action:    wrapAction(j.Command, j.Cancel),
+				ArgsUsage: "NAME",
+			},
 			{
 				Name:      "delete",
 				Usage:     "delete a job",
@@ -36,6 +53,16 @@ func (j *JobCommand) GetCommand() cli.Command {
 				Usage:     "list all jobs",
 				Action:    wrapAction(j.Command, j.List),
 				ArgsUsage: " ",
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "archived",
+						Usage: "list jobs that were archived after their retention period",
+					},
+					cli.StringFlag{
+						Name:  "entity",
+						Usage: "only list jobs that targeted the specified entity (format: TYPE:NAME, e.g. service:api)",
+					},
+				},
 			},
 			{
 				Name:      "logs",
@@ -57,10 +84,53 @@ func (j *JobCommand) GetCommand() cli.Command {
 					},
 				},
 			},
+			{
+				Name:      "watch",
+				Usage:     "follow a job's progress until it finishes",
+				Action:    wrapAction(j.Command, j.Watch),
+				ArgsUsage: "NAME",
+			},
+			{
+				Name:      "retry",
+				Usage:     "re-run a failed job, resuming at the step that failed",
+				Action:    wrapAction(j.Command, j.Retry),
+				ArgsUsage: "NAME",
+			},
+			{
+				Name:      "workflow",
+				Usage:     "run a workflow of dependent jobs defined in a json file",
+				Action:    wrapAction(j.Command, j.Workflow),
+				ArgsUsage: "PATH",
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "wait",
+						Usage: "wait for the workflow to complete before returning",
+					},
+				},
+			},
 		},
 	}
 }
 
+func (j *JobCommand) Cancel(c *cli.Context) error {
+	args, err := extractArgs(c.Args(), "NAME")
+	if err != nil {
+		return err
+	}
+
+	id, err := j.resolveSingleID("job", args["NAME"])
+	if err != nil {
+		return err
+	}
+
+	job, err := j.Client.CancelJob(id)
+	if err != nil {
+		return err
+	}
+
+	return j.Printer.PrintJobs(job)
+}
+
 func (j *JobCommand) Delete(c *cli.Context) error {
 	return j.delete(c, "job", j.Client.Delete)
 }
@@ -81,11 +151,47 @@ func (j *JobCommand) Get(c *cli.Context) error {
 		return err
 	}
 
+	// workflows are described by the status of each of their nodes
+	if len(jobs) == 1 && types.JobType(jobs[0].JobType) == types.WorkflowJob {
+		return j.printWorkflow(jobs[0].JobID)
+	}
+
 	return j.Printer.PrintJobs(jobs...)
 }
 
+func (j *JobCommand) printWorkflow(jobID string) error {
+	workflow, err := j.Client.GetWorkflow(jobID)
+	if err != nil {
+		return err
+	}
+
+	return j.Printer.PrintWorkflow(workflow)
+}
+
 func (j *JobCommand) List(c *cli.Context) error {
-	jobs, err := j.Client.ListJobs()
+	listJobs := j.Client.ListJobs
+	if c.Bool("archived") {
+		listJobs = j.Client.ListArchivedJobs
+	}
+
+	if entity := c.String("entity"); entity != "" {
+		split := strings.SplitN(entity, ":", 2)
+		if len(split) != 2 {
+			return fmt.Errorf("Entity '%s' is not in format TYPE:NAME", entity)
+		}
+
+		entityType := split[0]
+		entityID, err := j.resolveSingleID(entityType, split[1])
+		if err != nil {
+			return err
+		}
+
+		listJobs = func() ([]*models.Job, error) {
+			return j.Client.ListJobsByEntity(entityType, entityID)
+		}
+	}
+
+	jobs, err := listJobs()
 	if err != nil {
 		return err
 	}
@@ -104,15 +210,96 @@ func (j *JobCommand) Logs(c *cli.Context) error {
 		return err
 	}
 
-	job, err := j.Client.GetJob(id)
+	logs, err := j.Client.GetJobLogs(id, c.String("start"), c.String("end"), c.Int("tail"))
 	if err != nil {
 		return err
 	}
 
-	logs, err := j.Client.GetTaskLogs(job.TaskID, c.String("start"), c.String("end"), c.Int("tail"))
+	return j.Printer.PrintLogs(logs...)
+}
+
+func (j *JobCommand) Retry(c *cli.Context) error {
+	args, err := extractArgs(c.Args(), "NAME")
 	if err != nil {
 		return err
 	}
 
-	return j.Printer.PrintLogs(logs...)
+	id, err := j.resolveSingleID("job", args["NAME"])
+	if err != nil {
+		return err
+	}
+
+	job, err := j.Client.RetryJob(id)
+	if err != nil {
+		return err
+	}
+
+	return j.Printer.PrintJobs(job)
+}
+
+func (j *JobCommand) Workflow(c *cli.Context) error {
+	args, err := extractArgs(c.Args(), "PATH")
+	if err != nil {
+		return err
+	}
+
+	content, err := ioutil.ReadFile(args["PATH"])
+	if err != nil {
+		return err
+	}
+
+	var req models.CreateWorkflowRequest
+	if err := json.Unmarshal(content, &req); err != nil {
+		return fmt.Errorf("Failed to parse workflow '%s': %v", args["PATH"], err)
+	}
+
+	jobID, err := j.Client.CreateWorkflow(req)
+	if err != nil {
+		return err
+	}
+
+	if waited, err := j.waitForJob(c, jobID, "Running workflow"); err != nil || !waited {
+		return err
+	}
+
+	return j.printWorkflow(jobID)
+}
+
+// Watch prints the job each time its status or steps change, until the job finishes
+func (j *JobCommand) Watch(c *cli.Context) error {
+	args, err := extractArgs(c.Args(), "NAME")
+	if err != nil {
+		return err
+	}
+
+	id, err := j.resolveSingleID("job", args["NAME"])
+	if err != nil {
+		return err
+	}
+
+	var previous *models.Job
+	for {
+		job, err := j.Client.GetJob(id)
+		if err != nil {
+			return err
+		}
+
+		if previous == nil || previous.JobStatus != job.JobStatus || !reflect.DeepEqual(previous.Steps, job.Steps) {
+			if err := j.Printer.PrintJobs(job); err != nil {
+				return err
+			}
+		}
+
+		switch types.JobStatus(job.JobStatus) {
+		case types.Completed:
+			return nil
+		case types.Error:
+			return fmt.Errorf("Job '%s' failed. Use 'l0 job logs %s' for more information", job.JobID, job.JobID)
+		case types.Cancelled:
+			return fmt.Errorf("Job '%s' was cancelled", job.JobID)
+		}
+
+		previous = job
+		time.Sleep(jobWatchInterval)
+	}
 }
@@ -3,11 +3,48 @@ package command
 import (
 	"testing"
 
+	"github.com/golang/mock/gomock"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/testutils"
+	"github.com/quintilesims/layer0/common/types"
 	"github.com/urfave/cli"
 )
 
+func TestCancelJob(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewJobCommand(tc.Command())
+
+	tc.Resolver.EXPECT().
+		Resolve("job", "name").
+		Return([]string{"id"}, nil)
+
+	tc.Client.EXPECT().
+		CancelJob("id").
+		Return(&models.Job{}, nil)
+
+	c := testutils.GetCLIContext(t, []string{"name"}, nil)
+	if err := command.Cancel(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCancelJob_userInputErrors(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewJobCommand(tc.Command())
+
+	contexts := map[string]*cli.Context{
+		"Missing NAME arg": testutils.GetCLIContext(t, nil, nil),
+	}
+
+	for name, c := range contexts {
+		if err := command.Cancel(c); err == nil {
+			t.Fatalf("%s: error was nil!", name)
+		}
+	}
+}
+
 func TestDelete(t *testing.T) {
 	tc, ctrl := newTestCommand(t)
 	defer ctrl.Finish()
@@ -62,6 +99,29 @@ func TestGetJob(t *testing.T) {
 	}
 }
 
+func TestGetJobWorkflow(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewJobCommand(tc.Command())
+
+	tc.Resolver.EXPECT().
+		Resolve("job", "name").
+		Return([]string{"id"}, nil)
+
+	tc.Client.EXPECT().
+		GetJob("id").
+		Return(&models.Job{JobID: "id", JobType: int64(types.WorkflowJob)}, nil)
+
+	tc.Client.EXPECT().
+		GetWorkflow("id").
+		Return(&models.Workflow{}, nil)
+
+	c := testutils.GetCLIContext(t, []string{"name"}, nil)
+	if err := command.Get(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestGetJob_userInputErrors(t *testing.T) {
 	tc, ctrl := newTestCommand(t)
 	defer ctrl.Finish()
@@ -93,21 +153,59 @@ func TestListJobs(t *testing.T) {
 	}
 }
 
-func TestGetJobLogs(t *testing.T) {
+func TestListJobsArchived(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewJobCommand(tc.Command())
+
+	tc.Client.EXPECT().
+		ListArchivedJobs().
+		Return([]*models.Job{}, nil)
+
+	flags := map[string]interface{}{
+		"archived": true,
+	}
+
+	c := testutils.GetCLIContext(t, nil, flags)
+	if err := command.List(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListJobsEntity(t *testing.T) {
 	tc, ctrl := newTestCommand(t)
 	defer ctrl.Finish()
 	command := NewJobCommand(tc.Command())
 
 	tc.Resolver.EXPECT().
-		Resolve("job", "name").
+		Resolve("service", "name").
 		Return([]string{"id"}, nil)
 
 	tc.Client.EXPECT().
-		GetJob("id").
-		Return(&models.Job{TaskID: "task-id"}, nil)
+		ListJobsByEntity("service", "id").
+		Return([]*models.Job{}, nil)
+
+	flags := map[string]interface{}{
+		"entity": "service:name",
+	}
+
+	c := testutils.GetCLIContext(t, nil, flags)
+	if err := command.List(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetJobLogs(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewJobCommand(tc.Command())
+
+	tc.Resolver.EXPECT().
+		Resolve("job", "name").
+		Return([]string{"id"}, nil)
 
 	tc.Client.EXPECT().
-		GetTaskLogs("task-id", "start", "end", 100).
+		GetJobLogs("id", "start", "end", 100).
 		Return([]*models.LogFile{}, nil)
 
 	flags := map[string]interface{}{
@@ -137,3 +235,136 @@ func TestGetJobLogs_userInputErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestRetryJob(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewJobCommand(tc.Command())
+
+	tc.Resolver.EXPECT().
+		Resolve("job", "name").
+		Return([]string{"id"}, nil)
+
+	tc.Client.EXPECT().
+		RetryJob("id").
+		Return(&models.Job{}, nil)
+
+	c := testutils.GetCLIContext(t, []string{"name"}, nil)
+	if err := command.Retry(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRetryJob_userInputErrors(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewJobCommand(tc.Command())
+
+	contexts := map[string]*cli.Context{
+		"Missing NAME arg": testutils.GetCLIContext(t, nil, nil),
+	}
+
+	for name, c := range contexts {
+		if err := command.Retry(c); err == nil {
+			t.Fatalf("%s: error was nil!", name)
+		}
+	}
+}
+
+func TestWatchJob(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewJobCommand(tc.Command())
+
+	tmp := jobWatchInterval
+	jobWatchInterval = 0
+	defer func() { jobWatchInterval = tmp }()
+
+	tc.Resolver.EXPECT().
+		Resolve("job", "name").
+		Return([]string{"id"}, nil)
+
+	gomock.InOrder(
+		tc.Client.EXPECT().
+			GetJob("id").
+			Return(&models.Job{JobStatus: int64(types.InProgress)}, nil),
+		tc.Client.EXPECT().
+			GetJob("id").
+			Return(&models.Job{JobStatus: int64(types.Completed)}, nil),
+	)
+
+	c := testutils.GetCLIContext(t, []string{"name"}, nil)
+	if err := command.Watch(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchJob_failed(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewJobCommand(tc.Command())
+
+	tc.Resolver.EXPECT().
+		Resolve("job", "name").
+		Return([]string{"id"}, nil)
+
+	tc.Client.EXPECT().
+		GetJob("id").
+		Return(&models.Job{JobStatus: int64(types.Error)}, nil)
+
+	c := testutils.GetCLIContext(t, []string{"name"}, nil)
+	if err := command.Watch(c); err == nil {
+		t.Fatal("Error was nil!")
+	}
+}
+
+func TestWorkflow(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewJobCommand(tc.Command())
+
+	file, close := tempFile(t, `{"on_failure":"continue","nodes":[{"name":"a","job_type":"delete service","request":"s1"}]}`)
+	defer close()
+
+	tc.Client.EXPECT().
+		CreateWorkflow(gomock.Any()).
+		Do(func(req models.CreateWorkflowRequest) {
+			testutils.AssertEqual(t, req.OnFailure, "continue")
+			testutils.AssertEqual(t, req.Nodes[0].Name, "a")
+			testutils.AssertEqual(t, string(req.Nodes[0].Request), `"s1"`)
+		}).
+		Return("jobid", nil)
+
+	tc.Client.EXPECT().
+		WaitForJob("jobid", testutils.TEST_TIMEOUT).
+		Return(nil)
+
+	tc.Client.EXPECT().
+		GetWorkflow("jobid").
+		Return(&models.Workflow{}, nil)
+
+	c := testutils.GetCLIContext(t, []string{file.Name()}, map[string]interface{}{"wait": true})
+	if err := command.Workflow(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWorkflow_userInputErrors(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewJobCommand(tc.Command())
+
+	file, close := tempFile(t, "not json")
+	defer close()
+
+	contexts := map[string]*cli.Context{
+		"Missing PATH arg": testutils.GetCLIContext(t, nil, nil),
+		"Invalid json":     testutils.GetCLIContext(t, []string{file.Name()}, nil),
+	}
+
+	for name, c := range contexts {
+		if err := command.Workflow(c); err == nil {
+			t.Fatalf("%s: error was nil!", name)
+		}
+	}
+}
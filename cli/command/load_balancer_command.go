@@ -86,6 +86,10 @@ func (l *LoadBalancerCommand) GetCommand() cli.Command {
 						Name:  "disable-cross-zone",
 						Usage: "if specified, disables cross-zone load balancing (default is enabled)",
 					},
+					cli.BoolFlag{
+						Name:  "wait",
+						Usage: "wait for the job to complete before returning",
+					},
 				},
 			},
 			{
@@ -167,6 +171,9 @@ func (l *LoadBalancerCommand) GetCommand() cli.Command {
 				Usage:     "list all load balancers",
 				Action:    wrapAction(l.Command, l.List),
 				ArgsUsage: " ",
+				Flags: []cli.Flag{
+					selectorFlag("load balancers"),
+				},
 			},
 		},
 	}
@@ -194,7 +201,7 @@ func (l *LoadBalancerCommand) AddPort(c *cli.Context) error {
 	}
 
 	loadBalancer.Ports = append(loadBalancer.Ports, *port)
-	loadBalancer, err = l.Client.UpdateLoadBalancerPorts(id, loadBalancer.Ports)
+	loadBalancer, err = l.Client.UpdateLoadBalancerPorts(id, loadBalancer.Ports, loadBalancer.Version)
 	if err != nil {
 		return err
 	}
@@ -243,7 +250,21 @@ func (l *LoadBalancerCommand) Create(c *cli.Context) error {
 
 	idleTimeout := c.Int("idle-timeout")
 	crossZone := !c.Bool("disable-cross-zone")
-	loadBalancer, err := l.Client.CreateLoadBalancer(args["NAME"], environmentID, healthCheck, ports, !c.Bool("private"), idleTimeout, crossZone)
+	jobID, err := l.Client.CreateLoadBalancer(args["NAME"], environmentID, healthCheck, ports, !c.Bool("private"), idleTimeout, crossZone)
+	if err != nil {
+		return err
+	}
+
+	if waited, err := l.waitForJob(c, jobID, "Creating"); err != nil || !waited {
+		return err
+	}
+
+	job, err := l.Client.GetJob(jobID)
+	if err != nil {
+		return err
+	}
+
+	loadBalancer, err := l.Client.GetLoadBalancer(job.Meta["load_balancer_id"])
 	if err != nil {
 		return err
 	}
@@ -275,14 +296,14 @@ func (l *LoadBalancerCommand) CrossZone(c *cli.Context) error {
 	}
 
 	if enableCrossZone {
-		loadBalancer, err = l.Client.UpdateLoadBalancerCrossZone(id, true)
+		loadBalancer, err = l.Client.UpdateLoadBalancerCrossZone(id, true, loadBalancer.Version)
 		if err != nil {
 			return err
 		}
 	}
 
 	if disableCrossZone {
-		loadBalancer, err = l.Client.UpdateLoadBalancerCrossZone(id, false)
+		loadBalancer, err = l.Client.UpdateLoadBalancerCrossZone(id, false, loadBalancer.Version)
 		if err != nil {
 			return err
 		}
@@ -328,7 +349,7 @@ func (l *LoadBalancerCommand) DropPort(c *cli.Context) error {
 		return fmt.Errorf("Host port '%v' doesn't exist on this Load Balancer", port)
 	}
 
-	loadBalancer, err = l.Client.UpdateLoadBalancerPorts(id, loadBalancer.Ports)
+	loadBalancer, err = l.Client.UpdateLoadBalancerPorts(id, loadBalancer.Ports, loadBalancer.Version)
 	if err != nil {
 		return err
 	}
@@ -440,7 +461,7 @@ func (l *LoadBalancerCommand) HealthCheck(c *cli.Context) error {
 			loadBalancer.HealthCheck.UnhealthyThreshold = healthCheck.UnhealthyThreshold
 		}
 
-		loadBalancer, err = l.Client.UpdateLoadBalancerHealthCheck(id, loadBalancer.HealthCheck)
+		loadBalancer, err = l.Client.UpdateLoadBalancerHealthCheck(id, loadBalancer.HealthCheck, loadBalancer.Version)
 		if err != nil {
 			return err
 		}
@@ -470,7 +491,7 @@ func (l *LoadBalancerCommand) IdleTimeout(c *cli.Context) error {
 		return err
 	}
 
-	loadBalancer, err = l.Client.UpdateLoadBalancerIdleTimeout(id, int(idleTimeout))
+	loadBalancer, err = l.Client.UpdateLoadBalancerIdleTimeout(id, int(idleTimeout), loadBalancer.Version)
 	if err != nil {
 		return err
 	}
@@ -479,7 +500,12 @@ func (l *LoadBalancerCommand) IdleTimeout(c *cli.Context) error {
 }
 
 func (l *LoadBalancerCommand) List(c *cli.Context) error {
-	loadBalancerSummaries, err := l.Client.ListLoadBalancers()
+	listLoadBalancers := l.Client.ListLoadBalancers
+	if selector := c.String("selector"); selector != "" {
+		listLoadBalancers = func() ([]*models.LoadBalancerSummary, error) { return l.Client.ListLoadBalancersBySelector(selector) }
+	}
+
+	loadBalancerSummaries, err := listLoadBalancers()
 	if err != nil {
 		return err
 	}
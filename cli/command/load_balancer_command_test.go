@@ -107,7 +107,7 @@ func TestLoadBalancerAddPort(t *testing.T) {
 	}
 
 	tc.Client.EXPECT().
-		UpdateLoadBalancerPorts("id", []models.Port{port}).
+		UpdateLoadBalancerPorts("id", []models.Port{port}, int64(0)).
 		Return(&models.LoadBalancer{}, nil)
 
 	flags := map[string]interface{}{"certificate": "cert_name"}
@@ -168,7 +168,7 @@ func TestCreateLoadBalancer(t *testing.T) {
 
 	tc.Client.EXPECT().
 		CreateLoadBalancer("name", "environmentID", healthCheck, ports, false, 60, true).
-		Return(&models.LoadBalancer{}, nil)
+		Return("jobid", nil)
 
 	flags := map[string]interface{}{
 		"port":                            []string{"443:80/https", "8000:8000/http"},
@@ -365,7 +365,7 @@ func TestHealthCheck_partialUpdateRequired(t *testing.T) {
 	}
 
 	tc.Client.EXPECT().
-		UpdateLoadBalancerHealthCheck("id", expectedHealthCheck)
+		UpdateLoadBalancerHealthCheck("id", expectedHealthCheck, int64(0))
 
 	flags := map[string]interface{}{
 		"set-target":   "TCP:88",
@@ -412,7 +412,7 @@ func TestLoadBalancerIdleTimeout(t *testing.T) {
 		Return(&models.LoadBalancer{}, nil)
 
 	tc.Client.EXPECT().
-		UpdateLoadBalancerIdleTimeout("id", 75)
+		UpdateLoadBalancerIdleTimeout("id", 75, int64(0))
 
 	c := testutils.GetCLIContext(t, []string{"lb_name", "75"}, nil)
 	if err := command.IdleTimeout(c); err != nil {
@@ -470,7 +470,7 @@ func TestLoadBalancerCrossZone_enableCrossZone(t *testing.T) {
 		Return(&models.LoadBalancer{}, nil)
 
 	tc.Client.EXPECT().
-		UpdateLoadBalancerCrossZone("id", true).
+		UpdateLoadBalancerCrossZone("id", true, int64(0)).
 		Return(&models.LoadBalancer{}, nil)
 
 	c := testutils.GetCLIContext(t, []string{"env", "name"}, map[string]interface{}{"enable": true})
@@ -493,7 +493,7 @@ func TestLoadBalancerCrossZone_disableCrossZone(t *testing.T) {
 		Return(&models.LoadBalancer{}, nil)
 
 	tc.Client.EXPECT().
-		UpdateLoadBalancerCrossZone("id", false).
+		UpdateLoadBalancerCrossZone("id", false, int64(0)).
 		Return(&models.LoadBalancer{}, nil)
 
 	c := testutils.GetCLIContext(t, []string{"env", "name"}, map[string]interface{}{"disable": true})
@@ -0,0 +1,323 @@
+package command
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/quintilesims/layer0/common/models"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// manifestDigestTag holds the sha256 digest of the file a deploy was created from.
+	// The api re-renders the content of deploys, so the digest is compared instead
+	manifestDigestTag = "manifest_digest"
+	// manifestEnvironmentTag holds the name of the environment whose manifest created a deploy
+	manifestEnvironmentTag = "manifest_environment"
+)
+
+// manifestReservedTagKeys are written by layer0 itself;
+// manifests can't set them and pruning never deletes them
+var manifestReservedTagKeys = map[string]bool{
+	"arn":                  true,
+	"deploy_id":            true,
+	"environment_id":       true,
+	"link":                 true,
+	"load_balancer_id":     true,
+	"name":                 true,
+	"os":                   true,
+	"placement_strategy":   true,
+	"task_id":              true,
+	"version":              true,
+	manifestDigestTag:      true,
+	manifestEnvironmentTag: true,
+}
+
+// manifest describes an environment with its deploys, load balancers, services and tags
+type manifest struct {
+	Environment   manifestEnvironment    `yaml:"environment"`
+	Deploys       []manifestDeploy       `yaml:"deploys"`
+	LoadBalancers []manifestLoadBalancer `yaml:"load_balancers"`
+	Services      []manifestService      `yaml:"services"`
+}
+
+// manifestEnvironment describes an environment.
+// The size, os, ami and min count can't be changed once the environment exists
+type manifestEnvironment struct {
+	Name              string            `yaml:"name"`
+	Size              string            `yaml:"size"`
+	MinCount          int               `yaml:"min_count"`
+	OS                string            `yaml:"os"`
+	AMI               string            `yaml:"ami"`
+	PlacementStrategy string            `yaml:"placement_strategy"`
+	Scaler            *manifestScaler   `yaml:"scaler"`
+	Tags              map[string]string `yaml:"tags"`
+}
+
+// size and os return the values an environment is created with; when the manifest leaves them empty,
+// the size and os of an existing environment aren't checked
+func (e manifestEnvironment) size() string {
+	if e.Size == "" {
+		return "m3.medium"
+	}
+
+	return e.Size
+}
+
+func (e manifestEnvironment) os() string {
+	if e.OS == "" {
+		return "linux"
+	}
+
+	return e.OS
+}
+
+type manifestScaler struct {
+	HeadroomInstances int `yaml:"headroom_instances"`
+	HeadroomMemory    int `yaml:"headroom_memory"`
+	ScaleDownCooldown int `yaml:"scale_down_cooldown"`
+	MaxCount          int `yaml:"max_count"`
+}
+
+func (m *manifestScaler) settings() models.ScalerSettings {
+	if m == nil {
+		return models.ScalerSettings{}
+	}
+
+	return models.ScalerSettings{
+		HeadroomInstances: m.HeadroomInstances,
+		HeadroomMemory:    m.HeadroomMemory,
+		ScaleDownCooldown: m.ScaleDownCooldown,
+		MaxClusterCount:   m.MaxCount,
+	}
+}
+
+// manifestDeploy describes a deploy family; its File is relative to the manifest
+type manifestDeploy struct {
+	Name    string            `yaml:"name"`
+	File    string            `yaml:"file"`
+	Tags    map[string]string `yaml:"tags"`
+	content []byte
+	digest  string
+}
+
+type manifestLoadBalancer struct {
+	Name        string               `yaml:"name"`
+	Private     bool                 `yaml:"private"`
+	Ports       []string             `yaml:"ports"`
+	Certificate string               `yaml:"certificate"`
+	HealthCheck *manifestHealthCheck `yaml:"health_check"`
+	IdleTimeout int                  `yaml:"idle_timeout"`
+	CrossZone   *bool                `yaml:"cross_zone"`
+	Tags        map[string]string    `yaml:"tags"`
+	ports       []models.Port
+}
+
+type manifestHealthCheck struct {
+	Target             string `yaml:"target"`
+	Interval           int    `yaml:"interval"`
+	Timeout            int    `yaml:"timeout"`
+	HealthyThreshold   int    `yaml:"healthy_threshold"`
+	UnhealthyThreshold int    `yaml:"unhealthy_threshold"`
+}
+
+// healthCheck returns the health check of the load balancer,
+// using the defaults of 'l0 loadbalancer create' for the fields that aren't set
+func (l manifestLoadBalancer) healthCheck() models.HealthCheck {
+	healthCheck := models.HealthCheck{
+		Target:             "TCP:80",
+		Interval:           30,
+		Timeout:            5,
+		HealthyThreshold:   2,
+		UnhealthyThreshold: 2,
+	}
+
+	if h := l.HealthCheck; h != nil {
+		if h.Target != "" {
+			healthCheck.Target = h.Target
+		}
+
+		if h.Interval != 0 {
+			healthCheck.Interval = h.Interval
+		}
+
+		if h.Timeout != 0 {
+			healthCheck.Timeout = h.Timeout
+		}
+
+		if h.HealthyThreshold != 0 {
+			healthCheck.HealthyThreshold = h.HealthyThreshold
+		}
+
+		if h.UnhealthyThreshold != 0 {
+			healthCheck.UnhealthyThreshold = h.UnhealthyThreshold
+		}
+	}
+
+	return healthCheck
+}
+
+func (l manifestLoadBalancer) crossZone() bool {
+	return l.CrossZone == nil || *l.CrossZone
+}
+
+// manifestService describes a service. Its Deploy is the name of a deploy in the manifest,
+// or the name or id of an existing deploy; names use the latest version
+type manifestService struct {
+	Name         string            `yaml:"name"`
+	Deploy       string            `yaml:"deploy"`
+	LoadBalancer string            `yaml:"load_balancer"`
+	Scale        *int              `yaml:"scale"`
+	Tags         map[string]string `yaml:"tags"`
+}
+
+func (s manifestService) scale() int {
+	if s.Scale == nil {
+		return 1
+	}
+
+	return *s.Scale
+}
+
+// loadManifest reads and validates the manifest at path, along with the files of its deploys
+func loadManifest(path string) (*manifest, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var m manifest
+	if err := yaml.Unmarshal(content, &m); err != nil {
+		return nil, fmt.Errorf("Failed to parse manifest '%s': %v", path, err)
+	}
+
+	if err := m.validate(filepath.Dir(path)); err != nil {
+		return nil, fmt.Errorf("Invalid manifest '%s': %v", path, err)
+	}
+
+	return &m, nil
+}
+
+func (m *manifest) validate(dir string) error {
+	if m.Environment.Name == "" {
+		return fmt.Errorf("environment.name is required")
+	}
+
+	if err := validateManifestTags("environment", m.Environment.Name, m.Environment.Tags); err != nil {
+		return err
+	}
+
+	deploys := map[string]bool{}
+	for i := range m.Deploys {
+		d := &m.Deploys[i]
+		if d.Name == "" || d.File == "" {
+			return fmt.Errorf("deploys[%d] requires a name and a file", i)
+		}
+
+		if deploys[d.Name] {
+			return fmt.Errorf("deploy '%s' is specified more than once", d.Name)
+		}
+
+		deploys[d.Name] = true
+
+		path := d.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("deploy '%s': %v", d.Name, err)
+		}
+
+		d.content = content
+		d.digest = fmt.Sprintf("%x", sha256.Sum256(content))
+
+		if err := validateManifestTags("deploy", d.Name, d.Tags); err != nil {
+			return err
+		}
+	}
+
+	loadBalancers := map[string]bool{}
+	for i := range m.LoadBalancers {
+		l := &m.LoadBalancers[i]
+		if l.Name == "" {
+			return fmt.Errorf("load_balancers[%d] requires a name", i)
+		}
+
+		if loadBalancers[l.Name] {
+			return fmt.Errorf("load balancer '%s' is specified more than once", l.Name)
+		}
+
+		loadBalancers[l.Name] = true
+
+		if len(l.Ports) == 0 {
+			l.Ports = []string{"80:80/tcp"}
+		}
+
+		for _, p := range l.Ports {
+			port, err := parsePort(p, l.Certificate)
+			if err != nil {
+				return fmt.Errorf("load balancer '%s': %v", l.Name, err)
+			}
+
+			l.ports = append(l.ports, *port)
+		}
+
+		if l.IdleTimeout == 0 {
+			l.IdleTimeout = 60
+		}
+
+		if err := validateManifestTags("load balancer", l.Name, l.Tags); err != nil {
+			return err
+		}
+	}
+
+	services := map[string]bool{}
+	usedLoadBalancers := map[string]string{}
+	for i, s := range m.Services {
+		if s.Name == "" || s.Deploy == "" {
+			return fmt.Errorf("services[%d] requires a name and a deploy", i)
+		}
+
+		if services[s.Name] {
+			return fmt.Errorf("service '%s' is specified more than once", s.Name)
+		}
+
+		services[s.Name] = true
+
+		if s.LoadBalancer != "" {
+			if !loadBalancers[s.LoadBalancer] {
+				return fmt.Errorf("service '%s' uses load balancer '%s', which isn't in the manifest", s.Name, s.LoadBalancer)
+			}
+
+			if other, ok := usedLoadBalancers[s.LoadBalancer]; ok {
+				return fmt.Errorf("services '%s' and '%s' can't both use load balancer '%s'", other, s.Name, s.LoadBalancer)
+			}
+
+			usedLoadBalancers[s.LoadBalancer] = s.Name
+		}
+
+		if s.scale() < 0 {
+			return fmt.Errorf("service '%s' has a negative scale", s.Name)
+		}
+
+		if err := validateManifestTags("service", s.Name, s.Tags); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateManifestTags(entityType, name string, tags map[string]string) error {
+	for key := range tags {
+		if key == "" || manifestReservedTagKeys[key] {
+			return fmt.Errorf("%s '%s' can't set the tag '%s'", entityType, name, key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package command
+
+import (
+	"io"
+	"os"
+
+	"github.com/urfave/cli"
+)
+
+type PlanCommand struct {
+	*Command
+}
+
+func NewPlanCommand(command *Command) *PlanCommand {
+	return &PlanCommand{command}
+}
+
+func (p *PlanCommand) GetCommand() cli.Command {
+	return cli.Command{
+		Name:      "plan",
+		Usage:     "show the changes that would make an environment match a manifest",
+		Action:    wrapAction(p.Command, p.Plan),
+		ArgsUsage: " ",
+		Flags:     manifestFlags(),
+	}
+}
+
+func (p *PlanCommand) Plan(c *cli.Context) error {
+	planner, err := planManifest(p.Command, c)
+	if err != nil {
+		return err
+	}
+
+	return p.Printer.PrintManifestPlan(planner.model())
+}
+
+type ApplyCommand struct {
+	*Command
+	// Input is where the answers to confirmation prompts are read from
+	Input io.Reader
+}
+
+func NewApplyCommand(command *Command) *ApplyCommand {
+	return &ApplyCommand{
+		Command: command,
+		Input:   os.Stdin,
+	}
+}
+
+func (a *ApplyCommand) GetCommand() cli.Command {
+	return cli.Command{
+		Name:      "apply",
+		Usage:     "make an environment match a manifest",
+		Action:    wrapAction(a.Command, a.Apply),
+		ArgsUsage: " ",
+		Flags: append(manifestFlags(),
+			cli.BoolFlag{
+				Name:  "yes, y",
+				Usage: "apply the changes without asking for confirmation",
+			},
+		),
+	}
+}
+
+func (a *ApplyCommand) Apply(c *cli.Context) error {
+	planner, err := planManifest(a.Command, c)
+	if err != nil {
+		return err
+	}
+
+	plan := planner.model()
+	if err := a.Printer.PrintManifestPlan(plan); err != nil {
+		return err
+	}
+
+	if len(plan.Changes) == 0 {
+		return nil
+	}
+
+	if !c.Bool("yes") {
+		confirmed, err := confirm(a.Input, a.Printer, "Apply these changes?")
+		if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			a.Printer.Printf("Apply cancelled\n")
+			return nil
+		}
+	}
+
+	if err := planner.apply(); err != nil {
+		return err
+	}
+
+	a.Printer.Printf("Applied %d changes to environment '%s'\n", len(plan.Changes), plan.EnvironmentName)
+	return nil
+}
+
+func manifestFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:  "file, f",
+			Usage: "path to the manifest",
+		},
+		cli.BoolFlag{
+			Name:  "prune",
+			Usage: "delete the services, load balancers, deploys and tags of the environment that aren't in the manifest",
+		},
+	}
+}
+
+// planManifest loads the manifest in the 'file' flag and diffs it against the live state of its environment
+func planManifest(command *Command, c *cli.Context) (*manifestPlanner, error) {
+	path := c.String("file")
+	if path == "" {
+		return nil, NewUsageError("The --file flag is required")
+	}
+
+	timeout, err := getTimeout(c)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := loadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	planner := newManifestPlanner(command, m, c.Bool("prune"))
+	planner.timeout = timeout
+	if err := planner.plan(); err != nil {
+		return nil, err
+	}
+
+	return planner, nil
+}
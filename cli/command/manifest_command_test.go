@@ -0,0 +1,586 @@
+package command
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/quintilesims/layer0/cli/client"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+const testDockerrun = `{"AWSEBDockerrunVersion": 2, "containerDefinitions": []}`
+
+const testManifest = `
+environment:
+  name: prod
+  tags:
+    team: payments
+deploys:
+  - name: api
+    file: api.json
+load_balancers:
+  - name: api
+    ports: ["80:80/http"]
+services:
+  - name: api
+    deploy: api
+    load_balancer: api
+    scale: 3
+    tags:
+      tier: web
+`
+
+// writeManifest writes the manifest and the dockerrun of its 'api' deploy to a temporary directory
+func writeManifest(t *testing.T, content string) (string, func()) {
+	dir, err := ioutil.TempDir(os.TempDir(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"app.yaml": content,
+		"api.json": testDockerrun,
+	}
+
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return filepath.Join(dir, "app.yaml"), func() { os.RemoveAll(dir) }
+}
+
+func testDockerrunDigest() string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(testDockerrun)))
+}
+
+func entityWithTags(entityType, entityID string, tags map[string]string) *models.EntityWithTags {
+	entity := &models.EntityWithTags{EntityType: entityType, EntityID: entityID}
+	for _, key := range sortedKeys(tags) {
+		tag := models.Tag{EntityType: entityType, EntityID: entityID, Key: key, Value: tags[key]}
+		entity.Tags = append(entity.Tags, tag)
+	}
+
+	return entity
+}
+
+// expectLiveEnvironment expects the reads of the environment 'prod' (e1), which has the load balancer 'api' (l1)
+// used by the service 'api' (s1), and the service 'old' (s2). The service 'api' runs 'api.2',
+// the latest version of the deploy 'api', which was created from the manifest's dockerrun
+func expectLiveEnvironment(tc *TestCommand, extraDeploys []*models.EntityWithTags) {
+	tc.Client.EXPECT().
+		ListEnvironments().
+		Return([]*models.EnvironmentSummary{
+			{EnvironmentID: "e1", EnvironmentName: "prod"},
+			{EnvironmentID: "e2", EnvironmentName: "staging"},
+		}, nil)
+
+	tc.Client.EXPECT().
+		GetEnvironment("e1").
+		Return(&models.Environment{
+			EnvironmentID:     "e1",
+			InstanceSize:      "m3.medium",
+			OperatingSystem:   "linux",
+			PlacementStrategy: "binpack",
+			Version:           3,
+		}, nil)
+
+	tc.Client.EXPECT().
+		SelectByQuery(map[string]string{"type": "environment"}).
+		Return([]*models.EntityWithTags{
+			entityWithTags("environment", "e1", map[string]string{"name": "prod", "team": "payments"}),
+		}, nil)
+
+	deploys := []*models.DeploySummary{
+		{DeployID: "api.1", DeployName: "api", Version: "1"},
+		{DeployID: "api.2", DeployName: "api", Version: "2"},
+	}
+
+	deployTags := []*models.EntityWithTags{
+		entityWithTags("deploy", "api.2", map[string]string{
+			"name":                 "api",
+			"version":              "2",
+			manifestDigestTag:      testDockerrunDigest(),
+			manifestEnvironmentTag: "prod",
+		}),
+	}
+
+	for _, entity := range extraDeploys {
+		tags := map[string]string{}
+		for _, tag := range entity.Tags {
+			tags[tag.Key] = tag.Value
+		}
+
+		deploys = append(deploys, &models.DeploySummary{DeployID: entity.EntityID, DeployName: tags["name"], Version: tags["version"]})
+		deployTags = append(deployTags, entity)
+	}
+
+	tc.Client.EXPECT().
+		ListDeploys().
+		Return(deploys, nil)
+
+	tc.Client.EXPECT().
+		SelectByQuery(map[string]string{"type": "deploy"}).
+		Return(deployTags, nil)
+
+	tc.Client.EXPECT().
+		ListLoadBalancers().
+		Return([]*models.LoadBalancerSummary{
+			{LoadBalancerID: "l1", LoadBalancerName: "api", EnvironmentID: "e1"},
+			{LoadBalancerID: "l2", LoadBalancerName: "api", EnvironmentID: "e2"},
+		}, nil)
+
+	tc.Client.EXPECT().
+		GetLoadBalancer("l1").
+		Return(&models.LoadBalancer{
+			LoadBalancerID: "l1",
+			IsPublic:       true,
+			Ports:          []models.Port{{HostPort: 80, ContainerPort: 80, Protocol: "HTTP"}},
+			HealthCheck:    manifestLoadBalancer{}.healthCheck(),
+			IdleTimeout:    60,
+			CrossZone:      true,
+			Version:        4,
+		}, nil)
+
+	tc.Client.EXPECT().
+		SelectByQuery(map[string]string{"type": "load_balancer"}).
+		Return([]*models.EntityWithTags{
+			entityWithTags("load_balancer", "l1", map[string]string{"name": "api", "environment_id": "e1"}),
+		}, nil)
+
+	tc.Client.EXPECT().
+		ListServices().
+		Return([]*models.ServiceSummary{
+			{ServiceID: "s1", ServiceName: "api", EnvironmentID: "e1"},
+			{ServiceID: "s2", ServiceName: "old", EnvironmentID: "e1"},
+			{ServiceID: "s3", ServiceName: "api", EnvironmentID: "e2"},
+		}, nil)
+
+	tc.Client.EXPECT().
+		GetService("s1").
+		Return(&models.Service{
+			ServiceID:      "s1",
+			LoadBalancerID: "l1",
+			DesiredCount:   1,
+			Deployments: []models.Deployment{
+				{DeployID: "api.1", Status: "ACTIVE"},
+				{DeployID: "api.2", Status: "PRIMARY"},
+			},
+			Version: 5,
+		}, nil)
+
+	tc.Client.EXPECT().
+		SelectByQuery(map[string]string{"type": "service"}).
+		Return([]*models.EntityWithTags{
+			entityWithTags("service", "s1", map[string]string{"name": "api", "owner": "bob"}),
+		}, nil)
+}
+
+// expectOtherServices expects the reads of the services that aren't in the manifest, which pruning
+// checks for the deploys they run: 'old' (s2) runs 'api.1', and 'api' in 'staging' (s3) runs deployID
+func expectOtherServices(tc *TestCommand, deployID string) {
+	tc.Client.EXPECT().
+		GetService("s2").
+		Return(&models.Service{ServiceID: "s2", Deployments: []models.Deployment{{DeployID: "api.1", Status: "PRIMARY"}}}, nil)
+
+	tc.Client.EXPECT().
+		GetService("s3").
+		Return(&models.Service{ServiceID: "s3", Deployments: []models.Deployment{{DeployID: deployID, Status: "PRIMARY"}}}, nil)
+}
+
+func TestPlanCreatesEnvironment(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+
+	path, cleanup := writeManifest(t, testManifest)
+	defer cleanup()
+
+	tc.Client.EXPECT().
+		ListEnvironments().
+		Return([]*models.EnvironmentSummary{}, nil)
+
+	tc.Client.EXPECT().
+		ListDeploys().
+		Return([]*models.DeploySummary{}, nil)
+
+	c := testutils.GetCLIContext(t, nil, map[string]interface{}{"file": path})
+	planner, err := planManifest(tc.Command(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []models.ManifestChange{
+		{Action: "create", EntityType: "environment", EntityName: "prod", Details: []string{"size: m3.medium", "os: linux", "tag team=payments"}},
+		{Action: "create", EntityType: "deploy", EntityName: "api", Details: []string{"file: api.json"}},
+		{Action: "create", EntityType: "load_balancer", EntityName: "api", Details: []string{"ports: 80:80/http", "public: true"}},
+		{Action: "create", EntityType: "service", EntityName: "api", Details: []string{"deploy: api", "load balancer: api", "scale: 3", "tag tier=web"}},
+	}
+
+	testutils.AssertEqual(t, planner.model().Changes, expected)
+}
+
+func TestApplyCreatesEnvironment(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewApplyCommand(tc.Command())
+
+	path, cleanup := writeManifest(t, testManifest)
+	defer cleanup()
+
+	tc.Client.EXPECT().
+		ListEnvironments().
+		Return([]*models.EnvironmentSummary{}, nil)
+
+	tc.Client.EXPECT().
+		ListDeploys().
+		Return([]*models.DeploySummary{}, nil)
+
+	gomock.InOrder(
+		tc.Client.EXPECT().
+			CreateEnvironment("prod", "m3.medium", 0, []byte(nil), "linux", "", "", models.ScalerSettings{}).
+			Return("j1", nil),
+		tc.Client.EXPECT().
+			WaitForJob("j1", testutils.TEST_TIMEOUT).
+			Return(nil),
+		tc.Client.EXPECT().
+			GetJob("j1").
+			Return(&models.Job{Meta: map[string]string{"environment_id": "e1"}}, nil),
+		tc.Client.EXPECT().
+			CreateTag(models.Tag{EntityType: "environment", EntityID: "e1", Key: "team", Value: "payments"}).
+			Return(nil),
+		tc.Client.EXPECT().
+			CreateDeploy("api", []byte(testDockerrun)).
+			Return(&models.Deploy{DeployID: "api.1"}, nil),
+		tc.Client.EXPECT().
+			CreateTag(models.Tag{EntityType: "deploy", EntityID: "api.1", Key: manifestDigestTag, Value: testDockerrunDigest()}).
+			Return(nil),
+		tc.Client.EXPECT().
+			CreateTag(models.Tag{EntityType: "deploy", EntityID: "api.1", Key: manifestEnvironmentTag, Value: "prod"}).
+			Return(nil),
+		tc.Client.EXPECT().
+			CreateLoadBalancer("api", "e1", manifestLoadBalancer{}.healthCheck(), []models.Port{{HostPort: 80, ContainerPort: 80, Protocol: "http"}}, true, 60, true).
+			Return("j2", nil),
+		tc.Client.EXPECT().
+			WaitForJob("j2", testutils.TEST_TIMEOUT).
+			Return(nil),
+		tc.Client.EXPECT().
+			GetJob("j2").
+			Return(&models.Job{Meta: map[string]string{"load_balancer_id": "l1"}}, nil),
+		tc.Client.EXPECT().
+			CreateService("api", "e1", "api.1", "l1").
+			Return(&models.Service{ServiceID: "s1", DesiredCount: 1}, nil),
+		tc.Client.EXPECT().
+			ScaleService("s1", 3, client.AnyVersion).
+			Return("j3", nil),
+		tc.Client.EXPECT().
+			WaitForJob("j3", testutils.TEST_TIMEOUT).
+			Return(nil),
+		tc.Client.EXPECT().
+			CreateTag(models.Tag{EntityType: "service", EntityID: "s1", Key: "tier", Value: "web"}).
+			Return(nil),
+	)
+
+	c := testutils.GetCLIContext(t, nil, map[string]interface{}{"file": path, "yes": true})
+	if err := command.Apply(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApplyUpdatesEnvironment(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewApplyCommand(tc.Command())
+	command.Input = strings.NewReader("y\n")
+
+	path, cleanup := writeManifest(t, testManifest)
+	defer cleanup()
+
+	expectLiveEnvironment(tc, nil)
+
+	// the service 'old' and the tag 'owner' aren't in the manifest, but are kept without --prune
+	gomock.InOrder(
+		tc.Client.EXPECT().
+			ScaleService("s1", 3, int64(5)).
+			Return("j1", nil),
+		tc.Client.EXPECT().
+			WaitForJob("j1", testutils.TEST_TIMEOUT).
+			Return(nil),
+		tc.Client.EXPECT().
+			CreateTag(models.Tag{EntityType: "service", EntityID: "s1", Key: "tier", Value: "web"}).
+			Return(nil),
+	)
+
+	c := testutils.GetCLIContext(t, nil, map[string]interface{}{"file": path})
+	if err := command.Apply(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApplyPrune(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewApplyCommand(tc.Command())
+
+	path, cleanup := writeManifest(t, testManifest)
+	defer cleanup()
+
+	// only the deploys created by the manifest of 'prod' are pruned
+	expectLiveEnvironment(tc, []*models.EntityWithTags{
+		entityWithTags("deploy", "worker.1", map[string]string{"name": "worker", "version": "1", manifestEnvironmentTag: "prod"}),
+		entityWithTags("deploy", "shared.1", map[string]string{"name": "shared", "version": "1"}),
+	})
+
+	expectOtherServices(tc, "api.2")
+
+	gomock.InOrder(
+		tc.Client.EXPECT().
+			ScaleService("s1", 3, int64(5)).
+			Return("j1", nil),
+		tc.Client.EXPECT().
+			WaitForJob("j1", testutils.TEST_TIMEOUT).
+			Return(nil),
+		tc.Client.EXPECT().
+			CreateTag(models.Tag{EntityType: "service", EntityID: "s1", Key: "tier", Value: "web"}).
+			Return(nil),
+		tc.Client.EXPECT().
+			DeleteTag(models.Tag{EntityType: "service", EntityID: "s1", Key: "owner"}).
+			Return(nil),
+		tc.Client.EXPECT().
+			DeleteService("s2").
+			Return("j2", nil),
+		tc.Client.EXPECT().
+			WaitForJob("j2", testutils.TEST_TIMEOUT).
+			Return(nil),
+		tc.Client.EXPECT().
+			DeleteDeploy("worker.1").
+			Return(nil),
+	)
+
+	c := testutils.GetCLIContext(t, nil, map[string]interface{}{"file": path, "prune": true, "yes": true})
+	if err := command.Apply(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlanPruneKeepsDeploysInUse(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+
+	// the service 'api' moves to the deploy 'web' but still runs 'api.2' until it is updated,
+	// and the service 'worker' refers to the deploy 'worker', which isn't in the manifest
+	manifest := strings.Replace(testManifest, "  - name: api\n    file: api.json", "  - name: web\n    file: api.json", 1)
+	manifest = strings.Replace(manifest, "    deploy: api\n", "    deploy: web\n", 1)
+	manifest += "  - name: worker\n    deploy: worker\n"
+
+	path, cleanup := writeManifest(t, manifest)
+	defer cleanup()
+
+	expectLiveEnvironment(tc, []*models.EntityWithTags{
+		entityWithTags("deploy", "worker.1", map[string]string{"name": "worker", "version": "1", manifestEnvironmentTag: "prod"}),
+	})
+
+	c := testutils.GetCLIContext(t, nil, map[string]interface{}{"file": path, "prune": true})
+	planner, err := planManifest(tc.Command(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, change := range planner.model().Changes {
+		if change.EntityType == "deploy" && change.Action == "delete" {
+			t.Fatalf("Deploy '%s' is in use but was pruned", change.EntityID)
+		}
+	}
+}
+
+func TestPlanPruneKeepsDeploysRunningInOtherEnvironments(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+
+	path, cleanup := writeManifest(t, testManifest)
+	defer cleanup()
+
+	// 'worker.1' was created by the manifest of 'prod', but the service 'api' in 'staging' runs it
+	expectLiveEnvironment(tc, []*models.EntityWithTags{
+		entityWithTags("deploy", "worker.1", map[string]string{"name": "worker", "version": "1", manifestEnvironmentTag: "prod"}),
+	})
+
+	expectOtherServices(tc, "worker.1")
+
+	c := testutils.GetCLIContext(t, nil, map[string]interface{}{"file": path, "prune": true})
+	planner, err := planManifest(tc.Command(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, change := range planner.model().Changes {
+		if change.EntityType == "deploy" && change.Action == "delete" {
+			t.Fatalf("Deploy '%s' is running in 'staging' but was pruned", change.EntityID)
+		}
+	}
+}
+
+func TestApplyCancelled(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewApplyCommand(tc.Command())
+	command.Input = strings.NewReader("n\n")
+
+	path, cleanup := writeManifest(t, testManifest)
+	defer cleanup()
+
+	// no changes are made since the prompt is declined
+	expectLiveEnvironment(tc, nil)
+
+	c := testutils.GetCLIContext(t, nil, map[string]interface{}{"file": path})
+	if err := command.Apply(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlanNewDeployVersion(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+
+	path, cleanup := writeManifest(t, strings.Replace(testManifest, "scale: 3", "scale: 1", 1))
+	defer cleanup()
+
+	if err := ioutil.WriteFile(filepath.Join(filepath.Dir(path), "api.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	expectLiveEnvironment(tc, nil)
+
+	c := testutils.GetCLIContext(t, nil, map[string]interface{}{"file": path})
+	planner, err := planManifest(tc.Command(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []models.ManifestChange{
+		{Action: "create", EntityType: "deploy", EntityName: "api", Details: []string{"file: api.json", "replaces version: 2"}},
+		{Action: "update", EntityType: "service", EntityName: "api", EntityID: "s1", Details: []string{"deploy: api.2 => api (new version)", "tag tier=web"}},
+	}
+
+	testutils.AssertEqual(t, planner.model().Changes, expected)
+}
+
+func TestPlanEnvironmentSizeAndOS(t *testing.T) {
+	// the size and os of an existing environment are only checked when the manifest sets them
+	cases := map[string]struct {
+		Environment manifestEnvironment
+		ExpectError bool
+	}{
+		"Empty size and os": {Environment: manifestEnvironment{Name: "prod"}},
+		"Same size and os":  {Environment: manifestEnvironment{Name: "prod", Size: "t2.small", OS: "windows"}},
+		"Different size":    {Environment: manifestEnvironment{Name: "prod", Size: "m3.medium"}, ExpectError: true},
+		"Different os":      {Environment: manifestEnvironment{Name: "prod", OS: "linux"}, ExpectError: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc, ctrl := newTestCommand(t)
+			defer ctrl.Finish()
+
+			tc.Client.EXPECT().
+				ListEnvironments().
+				Return([]*models.EnvironmentSummary{{EnvironmentID: "e1", EnvironmentName: "prod"}}, nil)
+
+			tc.Client.EXPECT().
+				GetEnvironment("e1").
+				Return(&models.Environment{EnvironmentID: "e1", InstanceSize: "t2.small", OperatingSystem: "windows"}, nil)
+
+			if !c.ExpectError {
+				tc.Client.EXPECT().
+					SelectByQuery(map[string]string{"type": "environment"}).
+					Return([]*models.EntityWithTags{}, nil)
+			}
+
+			planner := newManifestPlanner(tc.Command(), &manifest{Environment: c.Environment}, false)
+			err := planner.planEnvironment()
+			if c.ExpectError && err == nil {
+				t.Fatal("Error was nil!")
+			}
+
+			if !c.ExpectError && err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertEqual(t, len(planner.changes), 0)
+		})
+	}
+}
+
+func TestPlanLoadBalancerChanges(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+
+	manifest := strings.Replace(testManifest, `ports: ["80:80/http"]`, `ports: ["80:80/http", "443:80/https"]
+    certificate: cert
+    idle_timeout: 120`, 1)
+
+	path, cleanup := writeManifest(t, manifest)
+	defer cleanup()
+
+	expectLiveEnvironment(tc, nil)
+
+	c := testutils.GetCLIContext(t, nil, map[string]interface{}{"file": path})
+	planner, err := planManifest(tc.Command(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := planner.model().Changes
+	testutils.AssertEqual(t, len(changes), 2)
+	testutils.AssertEqual(t, changes[0].Details, []string{
+		"ports: 80:80/http => 443:80/https (cert), 80:80/http",
+		"idle timeout: 60 => 120",
+	})
+}
+
+func TestPlan_userInputErrors(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewPlanCommand(tc.Command())
+
+	c := testutils.GetCLIContext(t, nil, nil)
+	if err := command.Plan(c); err == nil {
+		t.Fatal("Missing --file: error was nil!")
+	}
+}
+
+func TestLoadManifest_errors(t *testing.T) {
+	manifests := map[string]string{
+		"Missing environment name":     "environment: {}",
+		"Invalid yaml":                 "environment: [",
+		"Missing deploy file":          "environment: {name: prod}\ndeploys: [{name: api, file: missing.json}]",
+		"Duplicate deploy":             "environment: {name: prod}\ndeploys: [{name: api, file: api.json}, {name: api, file: api.json}]",
+		"Invalid port":                 "environment: {name: prod}\nload_balancers: [{name: api, ports: [80]}]",
+		"Unknown load balancer":        "environment: {name: prod}\nservices: [{name: api, deploy: api, load_balancer: web}]",
+		"Shared load balancer":         "environment: {name: prod}\nload_balancers: [{name: api}]\nservices: [{name: a, deploy: api, load_balancer: api}, {name: b, deploy: api, load_balancer: api}]",
+		"Missing service deploy":       "environment: {name: prod}\nservices: [{name: api}]",
+		"Negative scale":               "environment: {name: prod}\nservices: [{name: api, deploy: api, scale: -1}]",
+		"Reserved tag":                 "environment: {name: prod, tags: {environment_id: e2}}",
+		"Reserved manifest tag":        "environment: {name: prod}\ndeploys: [{name: api, file: api.json, tags: {manifest_digest: abc}}]",
+		"Duplicate service":            "environment: {name: prod}\nservices: [{name: api, deploy: api}, {name: api, deploy: api}]",
+		"Duplicate load balancer":      "environment: {name: prod}\nload_balancers: [{name: api}, {name: api}]",
+		"Missing load balancer name":   "environment: {name: prod}\nload_balancers: [{ports: ['80:80/tcp']}]",
+		"Missing deploy name and file": "environment: {name: prod}\ndeploys: [{}]",
+	}
+
+	for name, content := range manifests {
+		path, cleanup := writeManifest(t, content)
+		if _, err := loadManifest(path); err == nil {
+			t.Errorf("%s: error was nil!", name)
+		}
+
+		cleanup()
+	}
+}
@@ -0,0 +1,988 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quintilesims/layer0/cli/client"
+	"github.com/quintilesims/layer0/common/models"
+)
+
+// manifestChange is a change of a manifestPlan along with the api calls that make it
+type manifestChange struct {
+	models.ManifestChange
+	apply func() error
+}
+
+// manifestPlanner diffs a manifest against the live state of its environment.
+// The ids of live entities are collected while planning, and apply adds the ids of the entities it creates,
+// so later changes can refer to entities that don't exist yet
+type manifestPlanner struct {
+	*Command
+	manifest *manifest
+	// prune deletes the entities and tags that aren't in the manifest
+	prune   bool
+	timeout time.Duration
+	changes []manifestChange
+	deletes []manifestChange
+
+	environmentID string
+	deploys       []*models.DeploySummary
+	deployIDs     map[string]string
+	// serviceSummaries and services are the live services of every environment; they are only read once
+	serviceSummaries []*models.ServiceSummary
+	services         map[string]*models.Service
+	loadBalancerIDs  map[string]string
+	serviceIDs       map[string]string
+	// tags maps the entity types to the tags of their entities, by entity id
+	tags map[string]map[string]map[string]string
+}
+
+func newManifestPlanner(command *Command, m *manifest, prune bool) *manifestPlanner {
+	return &manifestPlanner{
+		Command:         command,
+		manifest:        m,
+		prune:           prune,
+		deployIDs:       map[string]string{},
+		services:        map[string]*models.Service{},
+		loadBalancerIDs: map[string]string{},
+		serviceIDs:      map[string]string{},
+		tags:            map[string]map[string]map[string]string{},
+	}
+}
+
+// plan computes the changes in the order they are applied: the environment, deploys, load balancers
+// and services are created or updated first, then the services, load balancers and deploys are pruned
+func (p *manifestPlanner) plan() error {
+	steps := []func() error{
+		p.planEnvironment,
+		p.planDeploys,
+		p.planLoadBalancers,
+		p.planServices,
+		p.pruneDeploys,
+	}
+
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+
+	// services are deleted before the load balancers they use, and deploys are deleted last
+	order := map[string]int{"service": 0, "load_balancer": 1, "deploy": 2}
+	sort.SliceStable(p.deletes, func(i, j int) bool {
+		return order[p.deletes[i].EntityType] < order[p.deletes[j].EntityType]
+	})
+
+	p.changes = append(p.changes, p.deletes...)
+	return nil
+}
+
+func (p *manifestPlanner) model() *models.ManifestPlan {
+	plan := &models.ManifestPlan{
+		EnvironmentName: p.manifest.Environment.Name,
+		Changes:         make([]models.ManifestChange, len(p.changes)),
+	}
+
+	for i, change := range p.changes {
+		plan.Changes[i] = change.ManifestChange
+	}
+
+	return plan
+}
+
+func (p *manifestPlanner) apply() error {
+	actions := map[string]string{
+		"create": "Creating",
+		"update": "Updating",
+		"delete": "Deleting",
+	}
+
+	for _, change := range p.changes {
+		entityType := strings.ToLower(entityTitle(change.EntityType))
+		p.Printer.Printf("%s %s '%s'\n", actions[change.Action], entityType, change.EntityName)
+		if err := change.apply(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *manifestPlanner) addChange(action, entityType, name, id string, details []string, apply func() error) {
+	change := manifestChange{
+		ManifestChange: models.ManifestChange{
+			Action:     action,
+			EntityType: entityType,
+			EntityName: name,
+			EntityID:   id,
+			Details:    details,
+		},
+		apply: apply,
+	}
+
+	if action == "delete" {
+		p.deletes = append(p.deletes, change)
+		return
+	}
+
+	p.changes = append(p.changes, change)
+}
+
+func (p *manifestPlanner) planEnvironment() error {
+	desired := p.manifest.Environment
+	summaries, err := p.Client.ListEnvironments()
+	if err != nil {
+		return err
+	}
+
+	ids := []string{}
+	for _, summary := range summaries {
+		if summary.EnvironmentName == desired.Name {
+			ids = append(ids, summary.EnvironmentID)
+		}
+	}
+
+	if len(ids) > 1 {
+		return multipleMatchesError("environment", desired.Name, ids)
+	}
+
+	if len(ids) == 0 {
+		details := []string{
+			fmt.Sprintf("size: %s", desired.size()),
+			fmt.Sprintf("os: %s", desired.os()),
+		}
+
+		p.addChange("create", "environment", desired.Name, "", append(details, createTagDetails(desired.Tags)...), func() error {
+			jobID, err := p.Client.CreateEnvironment(
+				desired.Name,
+				desired.size(),
+				desired.MinCount,
+				nil,
+				desired.os(),
+				desired.AMI,
+				desired.PlacementStrategy,
+				desired.Scaler.settings())
+			if err != nil {
+				return err
+			}
+
+			id, err := p.waitForJob(jobID, "environment_id")
+			if err != nil {
+				return err
+			}
+
+			p.environmentID = id
+			return p.writeTags("environment", id, desired.Tags, nil)
+		})
+
+		return nil
+	}
+
+	p.environmentID = ids[0]
+	environment, err := p.Client.GetEnvironment(p.environmentID)
+	if err != nil {
+		return err
+	}
+
+	if desired.Size != "" && environment.InstanceSize != desired.Size {
+		return fmt.Errorf("Environment '%s' has instance size '%s'; the size of an existing environment can't be changed", desired.Name, environment.InstanceSize)
+	}
+
+	if desired.OS != "" && environment.OperatingSystem != desired.OS {
+		return fmt.Errorf("Environment '%s' runs '%s'; the os of an existing environment can't be changed", desired.Name, environment.OperatingSystem)
+	}
+
+	details := []string{}
+	updatePlacementStrategy := desired.PlacementStrategy != "" && desired.PlacementStrategy != environment.PlacementStrategy
+	if updatePlacementStrategy {
+		details = append(details, fmt.Sprintf("placement strategy: %s => %s", environment.PlacementStrategy, desired.PlacementStrategy))
+	}
+
+	settings := desired.Scaler.settings()
+	updateScalerSettings := desired.Scaler != nil && settings != environment.ScalerSettings
+	if updateScalerSettings {
+		details = append(details, fmt.Sprintf("scaler: %s => %s", formatScalerSettings(environment.ScalerSettings), formatScalerSettings(settings)))
+	}
+
+	liveTags, err := p.entityTags("environment", p.environmentID)
+	if err != nil {
+		return err
+	}
+
+	set, remove := p.diffTags(liveTags, desired.Tags)
+	details = append(details, updateTagDetails(liveTags, set, remove)...)
+	if len(details) == 0 {
+		return nil
+	}
+
+	p.addChange("update", "environment", desired.Name, p.environmentID, details, func() error {
+		version := environment.Version
+		if updatePlacementStrategy {
+			updated, err := p.Client.UpdateEnvironmentPlacementStrategy(p.environmentID, desired.PlacementStrategy, version)
+			if err != nil {
+				return err
+			}
+
+			version = updated.Version
+		}
+
+		if updateScalerSettings {
+			if _, err := p.Client.UpdateEnvironmentScalerSettings(p.environmentID, settings, version); err != nil {
+				return err
+			}
+		}
+
+		return p.writeTags("environment", p.environmentID, set, remove)
+	})
+
+	return nil
+}
+
+func (p *manifestPlanner) planDeploys() error {
+	summaries, err := p.Client.ListDeploys()
+	if err != nil {
+		return err
+	}
+
+	latest := map[string]*models.DeploySummary{}
+	for _, summary := range summaries {
+		if current, ok := latest[summary.DeployName]; !ok || deployVersion(summary) > deployVersion(current) {
+			latest[summary.DeployName] = summary
+		}
+	}
+
+	for _, d := range p.manifest.Deploys {
+		desired := d
+
+		if current, ok := latest[desired.Name]; ok {
+			liveTags, err := p.entityTags("deploy", current.DeployID)
+			if err != nil {
+				return err
+			}
+
+			// the latest version was created from the same file, so only its tags can change
+			if liveTags[manifestDigestTag] == desired.digest {
+				p.deployIDs[desired.Name] = current.DeployID
+
+				set, remove := p.diffTags(liveTags, desired.Tags)
+				details := updateTagDetails(liveTags, set, remove)
+				if len(details) > 0 {
+					p.addChange("update", "deploy", desired.Name, current.DeployID, details, func() error {
+						return p.writeTags("deploy", current.DeployID, set, remove)
+					})
+				}
+
+				continue
+			}
+		}
+
+		details := []string{fmt.Sprintf("file: %s", desired.File)}
+		if current, ok := latest[desired.Name]; ok {
+			details = append(details, fmt.Sprintf("replaces version: %s", current.Version))
+		}
+
+		p.addChange("create", "deploy", desired.Name, "", append(details, createTagDetails(desired.Tags)...), func() error {
+			deploy, err := p.Client.CreateDeploy(desired.Name, desired.content)
+			if err != nil {
+				return err
+			}
+
+			p.deployIDs[desired.Name] = deploy.DeployID
+
+			tags := map[string]string{
+				manifestDigestTag:      desired.digest,
+				manifestEnvironmentTag: p.manifest.Environment.Name,
+			}
+
+			for key, value := range desired.Tags {
+				tags[key] = value
+			}
+
+			return p.writeTags("deploy", deploy.DeployID, tags, nil)
+		})
+	}
+
+	p.deploys = summaries
+	return p.resolveServiceDeploys(summaries, latest)
+}
+
+// pruneDeploys runs after the services are planned, so the deploys that the services in the manifest
+// refer to aren't pruned. Only the deploys that were created by this environment's manifest are pruned,
+// and since deploy families aren't scoped to an environment, deploys that a service in any environment
+// still runs are kept
+func (p *manifestPlanner) pruneDeploys() error {
+	if !p.prune {
+		return nil
+	}
+
+	desiredNames := map[string]bool{}
+	for _, d := range p.manifest.Deploys {
+		desiredNames[d.Name] = true
+	}
+
+	usedIDs := map[string]bool{}
+	for _, id := range p.deployIDs {
+		usedIDs[id] = true
+	}
+
+	for _, summary := range p.deploys {
+		if desiredNames[summary.DeployName] || usedIDs[summary.DeployID] {
+			continue
+		}
+
+		liveTags, err := p.entityTags("deploy", summary.DeployID)
+		if err != nil {
+			return err
+		}
+
+		if liveTags[manifestEnvironmentTag] != p.manifest.Environment.Name {
+			continue
+		}
+
+		running, err := p.isDeployRunning(summary.DeployID)
+		if err != nil {
+			return err
+		}
+
+		if running {
+			continue
+		}
+
+		deployID := summary.DeployID
+		p.addChange("delete", "deploy", summary.DeployName, deployID, []string{fmt.Sprintf("version: %s", summary.Version)}, func() error {
+			return p.Client.DeleteDeploy(deployID)
+		})
+	}
+
+	return nil
+}
+
+// isDeployRunning reports whether the deploy is the primary deployment of a service in any environment
+func (p *manifestPlanner) isDeployRunning(deployID string) (bool, error) {
+	summaries, err := p.listServices()
+	if err != nil {
+		return false, err
+	}
+
+	for _, summary := range summaries {
+		service, err := p.getService(summary.ServiceID)
+		if err != nil {
+			return false, err
+		}
+
+		for _, deployment := range service.Deployments {
+			if deployment.Status == "PRIMARY" && deployment.DeployID == deployID {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// resolveServiceDeploys records the ids of the existing deploys that services
+// refer to by name or id, rather than by the name of a deploy in the manifest
+func (p *manifestPlanner) resolveServiceDeploys(summaries []*models.DeploySummary, latest map[string]*models.DeploySummary) error {
+	inManifest := map[string]bool{}
+	for _, d := range p.manifest.Deploys {
+		inManifest[d.Name] = true
+	}
+
+	for _, s := range p.manifest.Services {
+		if inManifest[s.Deploy] {
+			continue
+		}
+
+		if current, ok := latest[s.Deploy]; ok {
+			p.deployIDs[s.Deploy] = current.DeployID
+			continue
+		}
+
+		var exists bool
+		for _, summary := range summaries {
+			if summary.DeployID == s.Deploy {
+				p.deployIDs[s.Deploy] = summary.DeployID
+				exists = true
+			}
+		}
+
+		if !exists {
+			return fmt.Errorf("Service '%s' uses deploy '%s', which doesn't exist", s.Name, s.Deploy)
+		}
+	}
+
+	return nil
+}
+
+func (p *manifestPlanner) planLoadBalancers() error {
+	live, err := p.liveLoadBalancers()
+	if err != nil {
+		return err
+	}
+
+	for _, l := range p.manifest.LoadBalancers {
+		desired := l
+		healthCheck := desired.healthCheck()
+
+		id, ok := live[desired.Name]
+		if !ok {
+			details := []string{
+				fmt.Sprintf("ports: %s", formatPorts(desired.ports)),
+				fmt.Sprintf("public: %t", !desired.Private),
+			}
+
+			p.addChange("create", "load_balancer", desired.Name, "", append(details, createTagDetails(desired.Tags)...), func() error {
+				jobID, err := p.Client.CreateLoadBalancer(
+					desired.Name,
+					p.environmentID,
+					healthCheck,
+					desired.ports,
+					!desired.Private,
+					desired.IdleTimeout,
+					desired.crossZone())
+				if err != nil {
+					return err
+				}
+
+				id, err := p.waitForJob(jobID, "load_balancer_id")
+				if err != nil {
+					return err
+				}
+
+				p.loadBalancerIDs[desired.Name] = id
+				return p.writeTags("load_balancer", id, desired.Tags, nil)
+			})
+
+			continue
+		}
+
+		p.loadBalancerIDs[desired.Name] = id
+		loadBalancer, err := p.Client.GetLoadBalancer(id)
+		if err != nil {
+			return err
+		}
+
+		if loadBalancer.IsPublic == desired.Private {
+			return fmt.Errorf("Load balancer '%s' has public: %t; existing load balancers can't be made public or private", desired.Name, loadBalancer.IsPublic)
+		}
+
+		details := []string{}
+		updatePorts := !portsMatch(loadBalancer.Ports, desired.ports)
+		if updatePorts {
+			details = append(details, fmt.Sprintf("ports: %s => %s", formatPorts(loadBalancer.Ports), formatPorts(desired.ports)))
+		}
+
+		updateHealthCheck := loadBalancer.HealthCheck != healthCheck
+		if updateHealthCheck {
+			details = append(details, fmt.Sprintf("health check: %s => %s", formatHealthCheck(loadBalancer.HealthCheck), formatHealthCheck(healthCheck)))
+		}
+
+		updateIdleTimeout := loadBalancer.IdleTimeout != desired.IdleTimeout
+		if updateIdleTimeout {
+			details = append(details, fmt.Sprintf("idle timeout: %d => %d", loadBalancer.IdleTimeout, desired.IdleTimeout))
+		}
+
+		updateCrossZone := loadBalancer.CrossZone != desired.crossZone()
+		if updateCrossZone {
+			details = append(details, fmt.Sprintf("cross zone: %t => %t", loadBalancer.CrossZone, desired.crossZone()))
+		}
+
+		liveTags, err := p.entityTags("load_balancer", id)
+		if err != nil {
+			return err
+		}
+
+		set, remove := p.diffTags(liveTags, desired.Tags)
+		details = append(details, updateTagDetails(liveTags, set, remove)...)
+		if len(details) == 0 {
+			continue
+		}
+
+		p.addChange("update", "load_balancer", desired.Name, id, details, func() error {
+			version := loadBalancer.Version
+			if updatePorts {
+				updated, err := p.Client.UpdateLoadBalancerPorts(id, desired.ports, version)
+				if err != nil {
+					return err
+				}
+
+				version = updated.Version
+			}
+
+			if updateHealthCheck {
+				updated, err := p.Client.UpdateLoadBalancerHealthCheck(id, healthCheck, version)
+				if err != nil {
+					return err
+				}
+
+				version = updated.Version
+			}
+
+			if updateIdleTimeout {
+				updated, err := p.Client.UpdateLoadBalancerIdleTimeout(id, desired.IdleTimeout, version)
+				if err != nil {
+					return err
+				}
+
+				version = updated.Version
+			}
+
+			if updateCrossZone {
+				if _, err := p.Client.UpdateLoadBalancerCrossZone(id, desired.crossZone(), version); err != nil {
+					return err
+				}
+			}
+
+			return p.writeTags("load_balancer", id, set, remove)
+		})
+	}
+
+	if !p.prune {
+		return nil
+	}
+
+	for _, name := range sortedKeys(live) {
+		if p.loadBalancerIDs[name] != "" {
+			continue
+		}
+
+		id := live[name]
+		p.addChange("delete", "load_balancer", name, id, nil, func() error {
+			jobID, err := p.Client.DeleteLoadBalancer(id)
+			if err != nil {
+				return err
+			}
+
+			_, err = p.waitForJob(jobID, "")
+			return err
+		})
+	}
+
+	return nil
+}
+
+func (p *manifestPlanner) planServices() error {
+	live, err := p.liveServices()
+	if err != nil {
+		return err
+	}
+
+	for _, s := range p.manifest.Services {
+		desired := s
+
+		id, ok := live[desired.Name]
+		if !ok {
+			details := []string{fmt.Sprintf("deploy: %s", desired.Deploy)}
+			if desired.LoadBalancer != "" {
+				details = append(details, fmt.Sprintf("load balancer: %s", desired.LoadBalancer))
+			}
+
+			details = append(details, fmt.Sprintf("scale: %d", desired.scale()))
+			p.addChange("create", "service", desired.Name, "", append(details, createTagDetails(desired.Tags)...), func() error {
+				service, err := p.Client.CreateService(
+					desired.Name,
+					p.environmentID,
+					p.deployIDs[desired.Deploy],
+					p.loadBalancerIDs[desired.LoadBalancer])
+				if err != nil {
+					return err
+				}
+
+				p.serviceIDs[desired.Name] = service.ServiceID
+				if service.DesiredCount != int64(desired.scale()) {
+					if err := p.scaleService(service.ServiceID, desired.scale(), client.AnyVersion); err != nil {
+						return err
+					}
+				}
+
+				return p.writeTags("service", service.ServiceID, desired.Tags, nil)
+			})
+
+			continue
+		}
+
+		p.serviceIDs[desired.Name] = id
+		service, err := p.getService(id)
+		if err != nil {
+			return err
+		}
+
+		// the load balancer of a new service isn't known until it is created
+		if loadBalancerID := p.loadBalancerIDs[desired.LoadBalancer]; service.LoadBalancerID != loadBalancerID || (desired.LoadBalancer != "" && loadBalancerID == "") {
+			return fmt.Errorf("Service '%s' can't change its load balancer; delete the service and apply the manifest again", desired.Name)
+		}
+
+		var currentDeployID string
+		for _, deployment := range service.Deployments {
+			if deployment.Status == "PRIMARY" {
+				currentDeployID = deployment.DeployID
+			}
+		}
+
+		details := []string{}
+		desiredDeployID := p.deployIDs[desired.Deploy]
+		updateDeploy := desiredDeployID != currentDeployID
+		if updateDeploy {
+			next := desiredDeployID
+			if next == "" {
+				next = fmt.Sprintf("%s (new version)", desired.Deploy)
+			}
+
+			details = append(details, fmt.Sprintf("deploy: %s => %s", currentDeployID, next))
+		}
+
+		updateScale := service.DesiredCount != int64(desired.scale())
+		if updateScale {
+			details = append(details, fmt.Sprintf("scale: %d => %d", service.DesiredCount, desired.scale()))
+		}
+
+		liveTags, err := p.entityTags("service", id)
+		if err != nil {
+			return err
+		}
+
+		set, remove := p.diffTags(liveTags, desired.Tags)
+		details = append(details, updateTagDetails(liveTags, set, remove)...)
+		if len(details) == 0 {
+			continue
+		}
+
+		p.addChange("update", "service", desired.Name, id, details, func() error {
+			// the version is only checked by the first update, since the update job changes it
+			version := service.Version
+			if updateDeploy {
+				jobID, err := p.Client.UpdateService(id, p.deployIDs[desired.Deploy], version)
+				if err != nil {
+					return err
+				}
+
+				if _, err := p.waitForJob(jobID, ""); err != nil {
+					return err
+				}
+
+				version = client.AnyVersion
+			}
+
+			if updateScale {
+				if err := p.scaleService(id, desired.scale(), version); err != nil {
+					return err
+				}
+			}
+
+			return p.writeTags("service", id, set, remove)
+		})
+	}
+
+	if !p.prune {
+		return nil
+	}
+
+	for _, name := range sortedKeys(live) {
+		if p.serviceIDs[name] != "" {
+			continue
+		}
+
+		id := live[name]
+		p.addChange("delete", "service", name, id, nil, func() error {
+			jobID, err := p.Client.DeleteService(id)
+			if err != nil {
+				return err
+			}
+
+			_, err = p.waitForJob(jobID, "")
+			return err
+		})
+	}
+
+	return nil
+}
+
+// liveLoadBalancers returns the ids of the load balancers in the environment, by name
+func (p *manifestPlanner) liveLoadBalancers() (map[string]string, error) {
+	live := map[string]string{}
+	if p.environmentID == "" {
+		return live, nil
+	}
+
+	summaries, err := p.Client.ListLoadBalancers()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, summary := range summaries {
+		if summary.EnvironmentID == p.environmentID {
+			live[summary.LoadBalancerName] = summary.LoadBalancerID
+		}
+	}
+
+	return live, nil
+}
+
+// liveServices returns the ids of the services in the environment, by name
+func (p *manifestPlanner) liveServices() (map[string]string, error) {
+	live := map[string]string{}
+	if p.environmentID == "" {
+		return live, nil
+	}
+
+	summaries, err := p.listServices()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, summary := range summaries {
+		if summary.EnvironmentID == p.environmentID {
+			live[summary.ServiceName] = summary.ServiceID
+		}
+	}
+
+	return live, nil
+}
+
+func (p *manifestPlanner) listServices() ([]*models.ServiceSummary, error) {
+	if p.serviceSummaries == nil {
+		summaries, err := p.Client.ListServices()
+		if err != nil {
+			return nil, err
+		}
+
+		p.serviceSummaries = summaries
+	}
+
+	return p.serviceSummaries, nil
+}
+
+func (p *manifestPlanner) getService(serviceID string) (*models.Service, error) {
+	if _, ok := p.services[serviceID]; !ok {
+		service, err := p.Client.GetService(serviceID)
+		if err != nil {
+			return nil, err
+		}
+
+		p.services[serviceID] = service
+	}
+
+	return p.services[serviceID], nil
+}
+
+// entityTags returns the tags of an entity; the tags of each entity type are only selected once
+func (p *manifestPlanner) entityTags(entityType, entityID string) (map[string]string, error) {
+	if _, ok := p.tags[entityType]; !ok {
+		entities, err := p.Client.SelectByQuery(map[string]string{"type": entityType})
+		if err != nil {
+			return nil, err
+		}
+
+		tags := map[string]map[string]string{}
+		for _, entity := range entities {
+			tags[entity.EntityID] = map[string]string{}
+			for _, tag := range entity.Tags {
+				tags[entity.EntityID][tag.Key] = tag.Value
+			}
+		}
+
+		p.tags[entityType] = tags
+	}
+
+	if tags, ok := p.tags[entityType][entityID]; ok {
+		return tags, nil
+	}
+
+	return map[string]string{}, nil
+}
+
+// diffTags returns the tags to set and, when pruning, the keys of the tags to delete;
+// reserved tags are never deleted
+func (p *manifestPlanner) diffTags(live, desired map[string]string) (map[string]string, []string) {
+	set := map[string]string{}
+	for key, value := range desired {
+		if current, ok := live[key]; !ok || current != value {
+			set[key] = value
+		}
+	}
+
+	remove := []string{}
+	if p.prune {
+		for key := range live {
+			if _, ok := desired[key]; !ok && !manifestReservedTagKeys[key] {
+				remove = append(remove, key)
+			}
+		}
+	}
+
+	sort.Strings(remove)
+	return set, remove
+}
+
+func (p *manifestPlanner) writeTags(entityType, entityID string, set map[string]string, remove []string) error {
+	for _, key := range sortedKeys(set) {
+		tag := models.Tag{EntityType: entityType, EntityID: entityID, Key: key, Value: set[key]}
+		if err := p.Client.CreateTag(tag); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range remove {
+		tag := models.Tag{EntityType: entityType, EntityID: entityID, Key: key}
+		if err := p.Client.DeleteTag(tag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *manifestPlanner) scaleService(id string, scale int, version int64) error {
+	jobID, err := p.Client.ScaleService(id, scale, version)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.waitForJob(jobID, "")
+	return err
+}
+
+// waitForJob waits for a job to complete and returns the value of metaKey in the job's meta
+func (p *manifestPlanner) waitForJob(jobID, metaKey string) (string, error) {
+	if err := p.Client.WaitForJob(jobID, p.timeout); err != nil {
+		return "", err
+	}
+
+	if metaKey == "" {
+		return "", nil
+	}
+
+	job, err := p.Client.GetJob(jobID)
+	if err != nil {
+		return "", err
+	}
+
+	return job.Meta[metaKey], nil
+}
+
+func createTagDetails(tags map[string]string) []string {
+	details := []string{}
+	for _, key := range sortedKeys(tags) {
+		details = append(details, fmt.Sprintf("tag %s=%s", key, tags[key]))
+	}
+
+	return details
+}
+
+func updateTagDetails(live, set map[string]string, remove []string) []string {
+	details := []string{}
+	for _, key := range sortedKeys(set) {
+		if current, ok := live[key]; ok {
+			details = append(details, fmt.Sprintf("tag %s: %s => %s", key, current, set[key]))
+			continue
+		}
+
+		details = append(details, fmt.Sprintf("tag %s=%s", key, set[key]))
+	}
+
+	for _, key := range remove {
+		details = append(details, fmt.Sprintf("untag %s", key))
+	}
+
+	return details
+}
+
+func deployVersion(summary *models.DeploySummary) int {
+	version, err := strconv.Atoi(summary.Version)
+	if err != nil {
+		return 0
+	}
+
+	return version
+}
+
+func formatPort(port models.Port) string {
+	text := fmt.Sprintf("%d:%d/%s", port.HostPort, port.ContainerPort, strings.ToLower(port.Protocol))
+	if certificate := port.CertificateName; certificate != "" {
+		return fmt.Sprintf("%s (%s)", text, certificate)
+	}
+
+	if certificate := port.CertificateARN; certificate != "" {
+		return fmt.Sprintf("%s (%s)", text, certificate)
+	}
+
+	return text
+}
+
+func formatPorts(ports []models.Port) string {
+	formatted := make([]string, len(ports))
+	for i, port := range ports {
+		formatted[i] = formatPort(port)
+	}
+
+	sort.Strings(formatted)
+	return strings.Join(formatted, ", ")
+}
+
+// portsMatch compares the ports of a load balancer with the ports of a manifest, in any order.
+// The api returns both the name and arn of certificates, so only the one in the manifest is compared
+func portsMatch(live, desired []models.Port) bool {
+	if len(live) != len(desired) {
+		return false
+	}
+
+	remaining := append([]models.Port{}, live...)
+	for _, d := range desired {
+		match := -1
+		for i, l := range remaining {
+			if l.HostPort != d.HostPort || l.ContainerPort != d.ContainerPort || !strings.EqualFold(l.Protocol, d.Protocol) {
+				continue
+			}
+
+			if d.CertificateName != "" && l.CertificateName != d.CertificateName {
+				continue
+			}
+
+			if d.CertificateARN != "" && l.CertificateARN != d.CertificateARN {
+				continue
+			}
+
+			match = i
+			break
+		}
+
+		if match < 0 {
+			return false
+		}
+
+		remaining = append(remaining[:match], remaining[match+1:]...)
+	}
+
+	return true
+}
+
+func formatHealthCheck(h models.HealthCheck) string {
+	return fmt.Sprintf("%s every %ds (timeout %ds, healthy %d, unhealthy %d)",
+		h.Target,
+		h.Interval,
+		h.Timeout,
+		h.HealthyThreshold,
+		h.UnhealthyThreshold)
+}
+
+func formatScalerSettings(s models.ScalerSettings) string {
+	return fmt.Sprintf("headroom %d instances/%d MiB, cooldown %ds, max %d",
+		s.HeadroomInstances,
+		s.HeadroomMemory,
+		s.ScaleDownCooldown,
+		s.MaxClusterCount)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/quintilesims/layer0/cli/client"
 	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/selector"
 )
 
 type Resolver interface {
@@ -36,6 +37,11 @@ func (r *TagResolver) Resolve(entityType, target string) ([]string, error) {
 		return nil, fmt.Errorf("Unrecognized entity type '%s'", entityType)
 	}
 
+	// a target such as 'team=payments' resolves to every entity whose tags match
+	if selector.IsSelector(target) {
+		resolveFunc = r.resolveSelector
+	}
+
 	ids, err := resolveFunc(entityType, target)
 	if err != nil {
 		return nil, err
@@ -91,6 +97,20 @@ func (r *TagResolver) resolveDeploy(entityType, target string) ([]string, error)
 	return r.query(entityType, targets[0], extraParams)
 }
 
+func (r *TagResolver) resolveSelector(entityType, target string) ([]string, error) {
+	params := map[string]string{
+		"type":     entityType,
+		"selector": target,
+	}
+
+	tags, err := r.client.SelectByQuery(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return extractIDs(tags, matchAnything()), nil
+}
+
 func (r *TagResolver) query(entityType string, target string, extraParams map[string]string) ([]string, error) {
 	requireExactMatches := !strings.Contains(target, "*")
 	if err := cleanTarget(&target); err != nil {
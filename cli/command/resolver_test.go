@@ -144,3 +144,25 @@ func TestResolveDeploy(t *testing.T) {
 	testutils.AssertEqual(t, len(ids), 1)
 	testutils.AssertEqual(t, ids[0], "id")
 }
+
+func TestResolveSelector(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	resolver := NewTagResolver(tc.Command().Client)
+
+	params := map[string]string{
+		"type":     "service",
+		"selector": "team=payments",
+	}
+
+	tc.Client.EXPECT().
+		SelectByQuery(params).
+		Return(tagsWithIDs("id1", "id2"), nil)
+
+	ids, err := resolver.Resolve("service", "team=payments")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, ids, []string{"id1", "id2"})
+}
@@ -56,7 +56,7 @@ func (s *ServiceCommand) GetCommand() cli.Command {
 				Flags: []cli.Flag{
 					cli.BoolFlag{
 						Name:  "wait",
-						Usage: "wait until the deployment completes before returning",
+						Usage: "wait until the job and the deployment complete before returning",
 					},
 				},
 			},
@@ -71,6 +71,9 @@ func (s *ServiceCommand) GetCommand() cli.Command {
 				Usage:     "list all services",
 				Action:    wrapAction(s.Command, s.List),
 				ArgsUsage: " ",
+				Flags: []cli.Flag{
+					selectorFlag("services"),
+				},
 			},
 			{
 				Name:      "logs",
@@ -100,7 +103,7 @@ func (s *ServiceCommand) GetCommand() cli.Command {
 				Flags: []cli.Flag{
 					cli.BoolFlag{
 						Name:  "wait",
-						Usage: "wait until the deployment completes before returning",
+						Usage: "wait until the job and the deployment complete before returning",
 					},
 				},
 			},
@@ -177,13 +180,18 @@ func (s *ServiceCommand) Update(c *cli.Context) error {
 		return err
 	}
 
-	service, err := s.Client.UpdateService(serviceID, deployID)
+	service, err := s.Client.GetService(serviceID)
 	if err != nil {
 		return err
 	}
 
-	if !c.Bool("wait") {
-		return s.Printer.PrintServices(service)
+	jobID, err := s.Client.UpdateService(serviceID, deployID, service.Version)
+	if err != nil {
+		return err
+	}
+
+	if waited, err := s.waitForJob(c, jobID, "Updating"); err != nil || !waited {
+		return err
 	}
 
 	timeout, err := getTimeout(c)
@@ -220,7 +228,12 @@ func (s *ServiceCommand) Get(c *cli.Context) error {
 }
 
 func (s *ServiceCommand) List(c *cli.Context) error {
-	serviceSummaries, err := s.Client.ListServices()
+	listServices := s.Client.ListServices
+	if selector := c.String("selector"); selector != "" {
+		listServices = func() ([]*models.ServiceSummary, error) { return s.Client.ListServicesBySelector(selector) }
+	}
+
+	serviceSummaries, err := listServices()
 	if err != nil {
 		return err
 	}
@@ -263,13 +276,18 @@ func (s *ServiceCommand) Scale(c *cli.Context) error {
 		return err
 	}
 
-	service, err := s.Client.ScaleService(id, int(count))
+	service, err := s.Client.GetService(id)
 	if err != nil {
 		return err
 	}
 
-	if !c.Bool("wait") {
-		return s.Printer.PrintServices(service)
+	jobID, err := s.Client.ScaleService(id, int(count), service.Version)
+	if err != nil {
+		return err
+	}
+
+	if waited, err := s.waitForJob(c, jobID, "Scaling"); err != nil || !waited {
+		return err
 	}
 
 	timeout, err := getTimeout(c)
@@ -165,8 +165,12 @@ func TestUpdateService(t *testing.T) {
 		Return([]string{"deployID"}, nil)
 
 	tc.Client.EXPECT().
-		UpdateService("serviceID", "deployID").
-		Return(&models.Service{}, nil)
+		GetService("serviceID").
+		Return(&models.Service{Version: 3}, nil)
+
+	tc.Client.EXPECT().
+		UpdateService("serviceID", "deployID", int64(3)).
+		Return("jobid", nil)
 
 	c := testutils.GetCLIContext(t, []string{"service", "deploy"}, nil)
 	if err := command.Update(c); err != nil {
@@ -188,8 +192,16 @@ func TestUpdateServiceWait(t *testing.T) {
 		Return([]string{"deployID"}, nil)
 
 	tc.Client.EXPECT().
-		UpdateService("serviceID", "deployID").
-		Return(&models.Service{}, nil)
+		GetService("serviceID").
+		Return(&models.Service{Version: 3}, nil)
+
+	tc.Client.EXPECT().
+		UpdateService("serviceID", "deployID", int64(3)).
+		Return("jobid", nil)
+
+	tc.Client.EXPECT().
+		WaitForJob("jobid", testutils.TEST_TIMEOUT).
+		Return(nil)
 
 	tc.Client.EXPECT().
 		WaitForDeployment("serviceID", testutils.TEST_TIMEOUT).
@@ -268,6 +280,21 @@ func TestListServices(t *testing.T) {
 	}
 }
 
+func TestListServicesBySelector(t *testing.T) {
+	tc, ctrl := newTestCommand(t)
+	defer ctrl.Finish()
+	command := NewServiceCommand(tc.Command())
+
+	tc.Client.EXPECT().
+		ListServicesBySelector("team=payments").
+		Return([]*models.ServiceSummary{}, nil)
+
+	c := testutils.GetCLIContext(t, nil, map[string]interface{}{"selector": "team=payments"})
+	if err := command.List(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestGetServiceLogs(t *testing.T) {
 	tc, ctrl := newTestCommand(t)
 	defer ctrl.Finish()
@@ -318,8 +345,12 @@ func TestScaleService(t *testing.T) {
 		Return([]string{"id"}, nil)
 
 	tc.Client.EXPECT().
-		ScaleService("id", 2).
-		Return(&models.Service{}, nil)
+		GetService("id").
+		Return(&models.Service{Version: 3}, nil)
+
+	tc.Client.EXPECT().
+		ScaleService("id", 2, int64(3)).
+		Return("jobid", nil)
 
 	c := testutils.GetCLIContext(t, []string{"name", "2"}, nil)
 	if err := command.Scale(c); err != nil {
@@ -337,8 +368,16 @@ func TestScaleServiceWait(t *testing.T) {
 		Return([]string{"id"}, nil)
 
 	tc.Client.EXPECT().
-		ScaleService("id", 2).
-		Return(&models.Service{}, nil)
+		GetService("id").
+		Return(&models.Service{Version: 3}, nil)
+
+	tc.Client.EXPECT().
+		ScaleService("id", 2, int64(3)).
+		Return("jobid", nil)
+
+	tc.Client.EXPECT().
+		WaitForJob("jobid", testutils.TEST_TIMEOUT).
+		Return(nil)
 
 	tc.Client.EXPECT().
 		WaitForDeployment("id", testutils.TEST_TIMEOUT).
@@ -61,6 +61,7 @@ func (t *TaskCommand) GetCommand() cli.Command {
 				Action:    wrapAction(t.Command, t.List),
 				ArgsUsage: " ",
 				Flags: []cli.Flag{
+					selectorFlag("tasks"),
 					cli.BoolFlag{
 						Name:  "all",
 						Usage: "included deleted tasks",
@@ -175,7 +176,12 @@ func (t *TaskCommand) Delete(c *cli.Context) error {
 }
 
 func (t *TaskCommand) Get(c *cli.Context) error {
-	taskSummaries, err := t.Client.ListTasks()
+	listTasks := t.Client.ListTasks
+	if selector := c.String("selector"); selector != "" {
+		listTasks = func() ([]*models.TaskSummary, error) { return t.Client.ListTasksBySelector(selector) }
+	}
+
+	taskSummaries, err := listTasks()
 	if err != nil {
 		return err
 	}
@@ -1,9 +1,12 @@
 package command
 
 import (
+	"bufio"
+	"io"
 	"strings"
 	"time"
 
+	"github.com/quintilesims/layer0/cli/printer"
 	"github.com/urfave/cli"
 )
 
@@ -19,6 +22,19 @@ func assertSingleID(entityType, target string, ids []string) (string, error) {
 	return ids[0], nil
 }
 
+// confirm prints a y/N prompt and reads the answer from input;
+// anything other than 'y' or 'yes', including no answer, declines
+func confirm(input io.Reader, p printer.Printer, prompt string) (bool, error) {
+	p.Printf("%s [y/N]: ", prompt)
+	answer, err := bufio.NewReader(input).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
 func entityTitle(entityType string) string {
 	title := strings.Replace(entityType, "_", " ", -1)
 	return strings.Title(title)
@@ -48,3 +64,11 @@ func extractArgs(received []string, names ...string) (map[string]string, error)
 func getTimeout(c *cli.Context) (time.Duration, error) {
 	return time.ParseDuration(c.GlobalString("timeout"))
 }
+
+// selectorFlag is the '--selector' flag of the list commands
+func selectorFlag(entities string) cli.StringFlag {
+	return cli.StringFlag{
+		Name:  "selector, l",
+		Usage: "only list " + entities + " whose tags match the selector, e.g. 'team=payments,tier!=dev'",
+	}
+}
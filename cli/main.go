@@ -124,10 +124,12 @@ func getCommands(client *client.APIClient) []command.CommandGroup {
 
 	return []command.CommandGroup{
 		command.NewAdminCommand(cmd),
+		command.NewApplyCommand(cmd),
 		command.NewDeployCommand(cmd),
 		command.NewEnvironmentCommand(cmd),
 		command.NewJobCommand(cmd),
 		command.NewLoadBalancerCommand(cmd),
+		command.NewPlanCommand(cmd),
 		command.NewServiceCommand(cmd),
 		command.NewTaskCommand(cmd),
 	}
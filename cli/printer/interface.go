@@ -11,18 +11,24 @@ type Printer interface {
 	PrintDeploySummaries(deploys ...*models.DeploySummary) error
 	PrintEnvironments(environments ...*models.Environment) error
 	PrintEnvironmentSummaries(environments ...*models.EnvironmentSummary) error
+	PrintImportReport(report *models.ImportReport) error
 	PrintJobs(jobs ...*models.Job) error
 	PrintLoadBalancers(loadBalancers ...*models.LoadBalancer) error
 	PrintLoadBalancerSummaries(loadBalancers ...*models.LoadBalancerSummary) error
 	PrintLoadBalancerHealthCheck(loadBalancer *models.LoadBalancer) error
 	PrintLoadBalancerIdleTimeout(loadBalancer *models.LoadBalancer) error
 	PrintLoadBalancerCrossZone(loadBalancer *models.LoadBalancer) error
+	PrintManifestPlan(plan *models.ManifestPlan) error
 	PrintLogs(logs ...*models.LogFile) error
+	PrintRestoreReport(report *models.RestoreReport) error
 	PrintScalerRunInfo(*models.ScalerRunInfo) error
+	PrintScalerRuns(runs ...*models.ScalerRun) error
 	PrintServices(services ...*models.Service) error
 	PrintServiceSummaries(services ...*models.ServiceSummary) error
+	PrintTagReconciliationReport(report *models.TagReconciliationReport) error
 	PrintTasks(tasks ...*models.Task) error
 	PrintTaskSummaries(tasks ...*models.TaskSummary) error
+	PrintWorkflow(workflow *models.Workflow) error
 	Printf(format string, tokens ...interface{})
 	Fatalf(code int64, format string, tokens ...interface{})
 }
@@ -67,6 +67,10 @@ func (j *JSONPrinter) PrintEnvironmentSummaries(environments ...*models.Environm
 	return j.print(environments)
 }
 
+func (j *JSONPrinter) PrintImportReport(report *models.ImportReport) error {
+	return j.print(report)
+}
+
 func (j *JSONPrinter) PrintJobs(jobs ...*models.Job) error {
 	return j.print(jobs)
 }
@@ -91,14 +95,26 @@ func (j *JSONPrinter) PrintLoadBalancerCrossZone(loadBalancer *models.LoadBalanc
 	return j.print(loadBalancer)
 }
 
+func (j *JSONPrinter) PrintManifestPlan(plan *models.ManifestPlan) error {
+	return j.print(plan)
+}
+
 func (j *JSONPrinter) PrintLogs(logs ...*models.LogFile) error {
 	return j.print(logs)
 }
 
+func (j *JSONPrinter) PrintRestoreReport(report *models.RestoreReport) error {
+	return j.print(report)
+}
+
 func (j *JSONPrinter) PrintScalerRunInfo(runInfo *models.ScalerRunInfo) error {
 	return j.print(runInfo)
 }
 
+func (j *JSONPrinter) PrintScalerRuns(runs ...*models.ScalerRun) error {
+	return j.print(runs)
+}
+
 func (j *JSONPrinter) PrintServices(services ...*models.Service) error {
 	return j.print(services)
 }
@@ -107,6 +123,10 @@ func (j *JSONPrinter) PrintServiceSummaries(services ...*models.ServiceSummary)
 	return j.print(services)
 }
 
+func (j *JSONPrinter) PrintTagReconciliationReport(report *models.TagReconciliationReport) error {
+	return j.print(report)
+}
+
 func (j *JSONPrinter) PrintTasks(tasks ...*models.Task) error {
 	return j.print(tasks)
 }
@@ -114,3 +134,7 @@ func (j *JSONPrinter) PrintTasks(tasks ...*models.Task) error {
 func (j *JSONPrinter) PrintTaskSummaries(tasks ...*models.TaskSummary) error {
 	return j.print(tasks)
 }
+
+func (j *JSONPrinter) PrintWorkflow(workflow *models.Workflow) error {
+	return j.print(workflow)
+}
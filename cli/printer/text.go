@@ -111,6 +111,25 @@ func (t *TextPrinter) PrintEnvironmentSummaries(environments ...*models.Environm
 	return nil
 }
 
+func (t *TextPrinter) PrintImportReport(report *models.ImportReport) error {
+	rows := []string{"ENTITY TYPE | ENTITY ID | TAGS"}
+	for _, entity := range report.Entities {
+		tags := make([]string, len(entity.Tags))
+		for i, tag := range entity.Tags {
+			tags[i] = fmt.Sprintf("%s=%s", tag.Key, tag.Value)
+		}
+
+		rows = append(rows, fmt.Sprintf("%s | %s | %s", entity.EntityType, entity.EntityID, strings.Join(tags, ", ")))
+	}
+
+	fmt.Println(columnize.SimpleFormat(rows))
+	for _, warning := range report.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+
+	return nil
+}
+
 func (t *TextPrinter) PrintJobs(jobs ...*models.Job) error {
 	getType := func(j *models.Job) string {
 		jobType := types.JobType(j.JobType).String()
@@ -135,9 +154,41 @@ func (t *TextPrinter) PrintJobs(jobs ...*models.Job) error {
 	}
 
 	fmt.Println(columnize.SimpleFormat(rows))
+
+	// show the progress of each step when describing a single job
+	if len(jobs) == 1 && len(jobs[0].Steps) > 0 {
+		fmt.Println()
+		t.printJobSteps(jobs[0].Steps)
+	}
+
 	return nil
 }
 
+func (t *TextPrinter) printJobSteps(steps []models.JobStep) {
+	formatTime := func(value time.Time) string {
+		if value.IsZero() {
+			return "-"
+		}
+
+		return value.Format(TIME_FORMAT)
+	}
+
+	rows := []string{"STEP | STATUS | ATTEMPTS | STARTED | FINISHED | ERROR"}
+	for _, s := range steps {
+		row := fmt.Sprintf("%s | %s | %d | %s | %s | %s",
+			s.Name,
+			strings.Title(types.JobStatus(s.Status).String()),
+			s.Attempts,
+			formatTime(s.TimeStarted),
+			formatTime(s.TimeFinished),
+			s.Error)
+
+		rows = append(rows, row)
+	}
+
+	fmt.Println(columnize.SimpleFormat(rows))
+}
+
 func (t *TextPrinter) PrintLoadBalancers(loadBalancers ...*models.LoadBalancer) error {
 	getEnvironment := func(l *models.LoadBalancer) string {
 		if l.EnvironmentName != "" {
@@ -282,6 +333,43 @@ func (t *TextPrinter) PrintLoadBalancerCrossZone(loadBalancer *models.LoadBalanc
 	return nil
 }
 
+func (t *TextPrinter) PrintManifestPlan(plan *models.ManifestPlan) error {
+	if len(plan.Changes) == 0 {
+		fmt.Printf("No changes; environment '%s' matches the manifest\n", plan.EnvironmentName)
+		return nil
+	}
+
+	getDetail := func(c models.ManifestChange, i int) string {
+		if i > len(c.Details)-1 {
+			return ""
+		}
+
+		return c.Details[i]
+	}
+
+	counts := map[string]int{}
+	rows := []string{"ACTION | TYPE | NAME | CHANGES"}
+	for _, c := range plan.Changes {
+		counts[c.Action]++
+		row := fmt.Sprintf("%s | %s | %s | %s",
+			c.Action,
+			c.EntityType,
+			c.EntityName,
+			getDetail(c, 0))
+
+		rows = append(rows, row)
+
+		// add the extra detail rows
+		for i := 1; i < len(c.Details); i++ {
+			rows = append(rows, fmt.Sprintf(" | | | %s", getDetail(c, i)))
+		}
+	}
+
+	fmt.Println(columnize.SimpleFormat(rows))
+	fmt.Printf("Plan: %d to create, %d to update, %d to delete\n", counts["create"], counts["update"], counts["delete"])
+	return nil
+}
+
 func (t *TextPrinter) PrintLogs(logs ...*models.LogFile) error {
 	for _, l := range logs {
 		fmt.Println(l.Name)
@@ -299,10 +387,74 @@ func (t *TextPrinter) PrintLogs(logs ...*models.LogFile) error {
 	return nil
 }
 
+func (t *TextPrinter) PrintRestoreReport(report *models.RestoreReport) error {
+	rows := []string{
+		"DRY RUN | TAGS INSERTED | TAGS UNCHANGED | TAGS OVERWRITTEN | TAG CONFLICTS | JOBS INSERTED | JOBS UNCHANGED | ENTITIES CHECKED",
+		fmt.Sprintf("%t | %d | %d | %d | %d | %d | %d | %d",
+			report.DryRun,
+			report.TagsInserted,
+			report.TagsUnchanged,
+			report.TagsOverwritten,
+			len(report.TagConflicts),
+			report.JobsInserted,
+			report.JobsUnchanged,
+			report.Consistency.EntitiesChecked),
+	}
+
+	fmt.Println(columnize.SimpleFormat(rows))
+
+	if len(report.TagConflicts) > 0 {
+		fmt.Println()
+		rows = []string{"ENTITY TYPE | ENTITY ID | KEY | CURRENT VALUE | BACKUP VALUE"}
+		for _, conflict := range report.TagConflicts {
+			rows = append(rows, fmt.Sprintf("%s | %s | %s | %s | %s",
+				conflict.EntityType,
+				conflict.EntityID,
+				conflict.Key,
+				conflict.CurrentValue,
+				conflict.BackupValue))
+		}
+
+		fmt.Println(columnize.SimpleFormat(rows))
+	}
+
+	if len(report.Consistency.Issues) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	rows = []string{"ENTITY TYPE | ENTITY ID | ISSUE"}
+	for _, issue := range report.Consistency.Issues {
+		rows = append(rows, fmt.Sprintf("%s | %s | %s", issue.EntityType, issue.EntityID, issue.Message))
+	}
+
+	fmt.Println(columnize.SimpleFormat(rows))
+	return nil
+}
+
 func (t *TextPrinter) PrintScalerRunInfo(runInfo *models.ScalerRunInfo) error {
 	rows := []string{
-		"ENVIRONMENT | CURRENT SCALE | DESIRED SCALE",
-		fmt.Sprintf("%s | %d | %d", runInfo.EnvironmentID, runInfo.ScaleBeforeRun, runInfo.ActualScaleAfterRun),
+		"ENVIRONMENT | CURRENT SCALE | DESIRED SCALE | PLACEMENT STRATEGY",
+		fmt.Sprintf("%s | %d | %d | %s", runInfo.EnvironmentID, runInfo.ScaleBeforeRun, runInfo.ActualScaleAfterRun, runInfo.PlacementStrategy),
+	}
+
+	fmt.Println(columnize.SimpleFormat(rows))
+	return nil
+}
+
+func (t *TextPrinter) PrintScalerRuns(runs ...*models.ScalerRun) error {
+	rows := []string{"TIME | SCALE | DESIRED SCALE | NEW | ERROR | TERMINATED"}
+	for _, r := range runs {
+		row := fmt.Sprintf("%s | %d -> %d | %d | %d | %s | %s",
+			r.Time.Format(TIME_FORMAT),
+			r.ScaleBeforeRun,
+			r.ActualScaleAfterRun,
+			r.DesiredScaleAfterRun,
+			r.NewResourceProviders,
+			strings.Join(strings.Fields(r.Error), " "),
+			strings.Join(r.TerminatedResourceProviders, ", "))
+
+		rows = append(rows, row)
 	}
 
 	fmt.Println(columnize.SimpleFormat(rows))
@@ -399,6 +551,32 @@ func (t *TextPrinter) PrintServiceSummaries(services ...*models.ServiceSummary)
 	return nil
 }
 
+func (t *TextPrinter) PrintTagReconciliationReport(report *models.TagReconciliationReport) error {
+	rows := []string{
+		"DRY RUN | ENTITIES CHECKED | ORPHANED ENTITIES | TAGS DELETED",
+		fmt.Sprintf("%t | %d | %d | %d", report.DryRun, report.EntitiesChecked, len(report.Orphans), report.TagsDeleted),
+	}
+
+	fmt.Println(columnize.SimpleFormat(rows))
+	if len(report.Orphans) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	rows = []string{"ENTITY TYPE | ENTITY ID | TAGS"}
+	for _, orphan := range report.Orphans {
+		keys := make([]string, len(orphan.Tags))
+		for i, tag := range orphan.Tags {
+			keys[i] = fmt.Sprintf("%s=%s", tag.Key, tag.Value)
+		}
+
+		rows = append(rows, fmt.Sprintf("%s | %s | %s", orphan.EntityType, orphan.EntityID, strings.Join(keys, ", ")))
+	}
+
+	fmt.Println(columnize.SimpleFormat(rows))
+	return nil
+}
+
 func (t *TextPrinter) PrintTasks(tasks ...*models.Task) error {
 	getEnvironment := func(t *models.Task) string {
 		if t.EnvironmentName != "" {
@@ -463,3 +641,54 @@ func (t *TextPrinter) PrintTaskSummaries(tasks ...*models.TaskSummary) error {
 	fmt.Println(columnize.SimpleFormat(rows))
 	return nil
 }
+
+func (t *TextPrinter) PrintWorkflow(workflow *models.Workflow) error {
+	rows := []string{"WORKFLOW ID | STATUS | ON FAILURE"}
+	rows = append(rows, fmt.Sprintf("%s | %s | %s",
+		workflow.JobID,
+		strings.Title(types.JobStatus(workflow.JobStatus).String()),
+		workflow.OnFailure))
+
+	fmt.Println(columnize.SimpleFormat(rows))
+	fmt.Println()
+
+	getJobID := func(n models.WorkflowNodeStatus) string {
+		if n.JobID == "" {
+			return "-"
+		}
+
+		return n.JobID
+	}
+
+	getDependsOn := func(n models.WorkflowNodeStatus) string {
+		if len(n.DependsOn) == 0 {
+			return "-"
+		}
+
+		return strings.Join(n.DependsOn, ", ")
+	}
+
+	getError := func(n models.WorkflowNodeStatus) string {
+		if n.Error == "" {
+			return "-"
+		}
+
+		return n.Error
+	}
+
+	rows = []string{"NODE | JOB ID | TYPE | STATUS | DEPENDS ON | ERROR"}
+	for _, n := range workflow.Nodes {
+		row := fmt.Sprintf("%s | %s | %s | %s | %s | %s",
+			n.Name,
+			getJobID(n),
+			strings.Title(n.JobType),
+			strings.Title(n.Status),
+			getDependsOn(n),
+			getError(n))
+
+		rows = append(rows, row)
+	}
+
+	fmt.Println(columnize.SimpleFormat(rows))
+	return nil
+}
@@ -80,6 +80,60 @@ func ExampleTextPrintEnvironmentSummaries() {
 	// id2             name2             windows
 }
 
+func ExampleTextPrinter_PrintImportReport() {
+	printer := &TextPrinter{}
+	report := &models.ImportReport{
+		DryRun: true,
+		Entities: []models.EntityWithTags{
+			{
+				EntityType: "deploy",
+				EntityID:   "dpl.2",
+				Tags: models.Tags{
+					{EntityType: "deploy", EntityID: "dpl.2", Key: "name", Value: "dpl"},
+					{EntityType: "deploy", EntityID: "dpl.2", Key: "version", Value: "2"},
+				},
+			},
+		},
+		Warnings: []string{"Could not infer the environment_id of load_balancer 'lbid1'"},
+	}
+
+	printer.PrintImportReport(report)
+	// Output:
+	//ENTITY TYPE  ENTITY ID  TAGS
+	//deploy       dpl.2      name=dpl, version=2
+	//Warning: Could not infer the environment_id of load_balancer 'lbid1'
+}
+
+func ExampleTextPrinter_PrintManifestPlan() {
+	printer := &TextPrinter{}
+	plan := &models.ManifestPlan{
+		EnvironmentName: "prod",
+		Changes: []models.ManifestChange{
+			{
+				Action:     "create",
+				EntityType: "load_balancer",
+				EntityName: "api",
+				Details:    []string{"ports: 80:80/http"},
+			},
+			{
+				Action:     "update",
+				EntityType: "service",
+				EntityName: "api",
+				EntityID:   "sid1",
+				Details:    []string{"scale: 1 => 3", "tag team: payments => billing"},
+			},
+		},
+	}
+
+	printer.PrintManifestPlan(plan)
+	// Output:
+	//ACTION  TYPE           NAME  CHANGES
+	//create  load_balancer  api   ports: 80:80/http
+	//update  service        api   scale: 1 => 3
+	//                              tag team: payments => billing
+	//Plan: 1 to create, 1 to update, 0 to delete
+}
+
 func ExampleTextPrintJobs() {
 	printer := &TextPrinter{}
 	jobs := []*models.Job{
@@ -332,18 +386,79 @@ func ExampleTextPrintLogs() {
 	//lineC
 }
 
+func ExampleTextPrinter_PrintRestoreReport() {
+	printer := &TextPrinter{}
+	report := &models.RestoreReport{
+		TagsInserted:  3,
+		TagsUnchanged: 1,
+		TagConflicts: []models.TagConflict{
+			{EntityType: "environment", EntityID: "eid1", Key: "name", CurrentValue: "prod", BackupValue: "staging"},
+		},
+		JobsInserted: 2,
+		Consistency: models.ConsistencyReport{
+			EntitiesChecked: 2,
+			Issues: []models.ConsistencyIssue{
+				{EntityType: "service", EntityID: "sid1", Message: "ECS service 'l0-test-sid1' does not exist"},
+			},
+		},
+	}
+
+	printer.PrintRestoreReport(report)
+	// Output:
+	//DRY RUN  TAGS INSERTED  TAGS UNCHANGED  TAGS OVERWRITTEN  TAG CONFLICTS  JOBS INSERTED  JOBS UNCHANGED  ENTITIES CHECKED
+	//false    3              1               0                 1              2              0               2
+	//
+	//ENTITY TYPE  ENTITY ID  KEY   CURRENT VALUE  BACKUP VALUE
+	//environment  eid1       name  prod           staging
+	//
+	//ENTITY TYPE  ENTITY ID  ISSUE
+	//service      sid1       ECS service 'l0-test-sid1' does not exist
+}
+
 func ExampleTextPrintScalerRunInfo() {
 	printer := &TextPrinter{}
 	runInfo := &models.ScalerRunInfo{
 		EnvironmentID:       "eid1",
 		ScaleBeforeRun:      1,
 		ActualScaleAfterRun: 2,
+		PlacementStrategy:   "spread-zone",
 	}
 
 	printer.PrintScalerRunInfo(runInfo)
 	// Output:
-	//ENVIRONMENT  CURRENT SCALE  DESIRED SCALE
-	//eid1         1              2
+	//ENVIRONMENT  CURRENT SCALE  DESIRED SCALE  PLACEMENT STRATEGY
+	//eid1         1              2              spread-zone
+}
+
+func ExampleTextPrinter_PrintScalerRuns() {
+	printer := &TextPrinter{}
+	runs := []*models.ScalerRun{
+		{
+			ScalerRunInfo: models.ScalerRunInfo{
+				ScaleBeforeRun:              3,
+				DesiredScaleAfterRun:        2,
+				ActualScaleAfterRun:         2,
+				TerminatedResourceProviders: []string{"i1"},
+			},
+			Time: time.Time{},
+		},
+		{
+			ScalerRunInfo: models.ScalerRunInfo{
+				ScaleBeforeRun:              3,
+				DesiredScaleAfterRun:        1,
+				ActualScaleAfterRun:         1,
+				TerminatedResourceProviders: []string{"i2"},
+			},
+			Time:  time.Time{},
+			Error: "some error",
+		},
+	}
+
+	printer.PrintScalerRuns(runs...)
+	// Output:
+	// TIME                 SCALE   DESIRED SCALE  NEW  ERROR       TERMINATED
+	// 0001-01-01 00:00:00  3 -> 2  2              0                i1
+	// 0001-01-01 00:00:00  3 -> 1  1              0    some error  i2
 }
 
 func ExampleTextPrintServices() {
@@ -433,6 +548,32 @@ func ExampleTextPrintServiceSummaries() {
 	// id2         svc2          eid2
 }
 
+func ExampleTextPrinter_PrintTagReconciliationReport() {
+	printer := &TextPrinter{}
+	report := &models.TagReconciliationReport{
+		DryRun:          true,
+		EntitiesChecked: 3,
+		Orphans: []models.EntityWithTags{
+			{
+				EntityType: "service",
+				EntityID:   "sid1",
+				Tags: models.Tags{
+					{EntityType: "service", EntityID: "sid1", Key: "name", Value: "svc1"},
+					{EntityType: "service", EntityID: "sid1", Key: "environment_id", Value: "eid1"},
+				},
+			},
+		},
+	}
+
+	printer.PrintTagReconciliationReport(report)
+	// Output:
+	//DRY RUN  ENTITIES CHECKED  ORPHANED ENTITIES  TAGS DELETED
+	//true     3                 1                  0
+	//
+	//ENTITY TYPE  ENTITY ID  TAGS
+	//service      sid1       name=svc1, environment_id=eid1
+}
+
 func ExampleTextPrintTasks() {
 	printer := &TextPrinter{}
 	tasks := []*models.Task{
@@ -491,3 +632,27 @@ func ExampleTextPrintTaskSummaries() {
 	// id1      tsk1       ename1
 	// id2      tsk2       eid2
 }
+
+func ExampleTextPrinter_PrintWorkflow() {
+	printer := &TextPrinter{}
+	workflow := &models.Workflow{
+		JobID:     "w1",
+		JobStatus: int64(types.InProgress),
+		OnFailure: "halt",
+		Nodes: []models.WorkflowNodeStatus{
+			{Name: "env", JobID: "j1", JobType: "create environment", Status: "completed"},
+			{Name: "svc", JobID: "j2", JobType: "create service", Status: "error", DependsOn: []string{"env"}, Error: "some error"},
+			{Name: "task", JobType: "create task", Status: "pending", DependsOn: []string{"env", "svc"}},
+		},
+	}
+
+	printer.PrintWorkflow(workflow)
+	// Output:
+	// WORKFLOW ID  STATUS       ON FAILURE
+	// w1           In Progress  halt
+	//
+	// NODE  JOB ID  TYPE                STATUS     DEPENDS ON  ERROR
+	// env   j1      Create Environment  Completed  -           -
+	// svc   j2      Create Service      Error      env         some error
+	// task  -       Create Task         Pending    env, svc    -
+}
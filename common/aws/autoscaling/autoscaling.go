@@ -19,6 +19,7 @@ type Provider interface {
 	DescribeAutoScalingGroup(name string) (*Group, error)
 	DescribeLaunchConfigurations(names []*string) ([]*LaunchConfiguration, error)
 	DescribeLaunchConfiguration(name string) (*LaunchConfiguration, error)
+	DescribeScalingActivities(name string, maxRecords int) ([]*Activity, error)
 	DeleteAutoScalingGroup(name *string) error
 	DeleteLaunchConfiguration(name *string) error
 	TerminateInstanceInAutoScalingGroup(instanceID string, decrement bool) (*Activity, error)
@@ -36,6 +37,7 @@ type AutoScalingInternal interface {
 	CreateAutoScalingGroup(input *autoscaling.CreateAutoScalingGroupInput) (*autoscaling.CreateAutoScalingGroupOutput, error)
 	DescribeAutoScalingGroups(input *autoscaling.DescribeAutoScalingGroupsInput) (*autoscaling.DescribeAutoScalingGroupsOutput, error)
 	DescribeLaunchConfigurations(input *autoscaling.DescribeLaunchConfigurationsInput) (*autoscaling.DescribeLaunchConfigurationsOutput, error)
+	DescribeScalingActivities(input *autoscaling.DescribeScalingActivitiesInput) (*autoscaling.DescribeScalingActivitiesOutput, error)
 	SetDesiredCapacity(input *autoscaling.SetDesiredCapacityInput) (*autoscaling.SetDesiredCapacityOutput, error)
 	UpdateAutoScalingGroup(input *autoscaling.UpdateAutoScalingGroupInput) (*autoscaling.UpdateAutoScalingGroupOutput, error)
 	DeleteAutoScalingGroup(input *autoscaling.DeleteAutoScalingGroupInput) (*autoscaling.DeleteAutoScalingGroupOutput, error)
@@ -276,6 +278,31 @@ func (this *AutoScaling) DescribeLaunchConfigurations(names []*string) ([]*Launc
 	return configs, nil
 }
 
+// DescribeScalingActivities returns up to maxRecords of the group's most recent scaling activities, newest first
+func (this *AutoScaling) DescribeScalingActivities(name string, maxRecords int) ([]*Activity, error) {
+	input := &autoscaling.DescribeScalingActivitiesInput{
+		AutoScalingGroupName: aws.String(name),
+		MaxRecords:           aws.Int64(int64(maxRecords)),
+	}
+
+	connection, err := this.Connect()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := connection.DescribeScalingActivities(input)
+	if err != nil {
+		return nil, err
+	}
+
+	activities := []*Activity{}
+	for _, a := range out.Activities {
+		activities = append(activities, &Activity{a})
+	}
+
+	return activities, nil
+}
+
 func (this *AutoScaling) UpdateAutoScalingGroupMaxSize(name string, size int) error {
 	input := &autoscaling.UpdateAutoScalingGroupInput{
 		AutoScalingGroupName: aws.String(name),
@@ -98,6 +98,15 @@ func (this *ProviderDecorator) DescribeLaunchConfiguration(p0 string) (v0 *Launc
 	err = this.Decorator("DescribeLaunchConfiguration", call)
 	return v0, err
 }
+func (this *ProviderDecorator) DescribeScalingActivities(p0 string, p1 int) (v0 []*Activity, err error) {
+	call := func() error {
+		var err error
+		v0, err = this.Inner.DescribeScalingActivities(p0, p1)
+		return err
+	}
+	err = this.Decorator("DescribeScalingActivities", call)
+	return v0, err
+}
 func (this *ProviderDecorator) DeleteAutoScalingGroup(p0 *string) (err error) {
 	call := func() error {
 		var err error
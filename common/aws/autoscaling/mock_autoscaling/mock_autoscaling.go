@@ -145,6 +145,19 @@ func (mr *MockProviderMockRecorder) DescribeLaunchConfigurations(arg0 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLaunchConfigurations", reflect.TypeOf((*MockProvider)(nil).DescribeLaunchConfigurations), arg0)
 }
 
+// DescribeScalingActivities mocks base method
+func (m *MockProvider) DescribeScalingActivities(arg0 string, arg1 int) ([]*autoscaling.Activity, error) {
+	ret := m.ctrl.Call(m, "DescribeScalingActivities", arg0, arg1)
+	ret0, _ := ret[0].([]*autoscaling.Activity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeScalingActivities indicates an expected call of DescribeScalingActivities
+func (mr *MockProviderMockRecorder) DescribeScalingActivities(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeScalingActivities", reflect.TypeOf((*MockProvider)(nil).DescribeScalingActivities), arg0, arg1)
+}
+
 // SetDesiredCapacity mocks base method
 func (m *MockProvider) SetDesiredCapacity(arg0 string, arg1 int) error {
 	ret := m.ctrl.Call(m, "SetDesiredCapacity", arg0, arg1)
@@ -90,6 +90,51 @@ var InstanceSizes = map[string]bytesize.Bytesize{
 	"d2.8xlarge":  244 * bytesize.GiB,
 }
 
+// InstanceCPUs holds the number of ECS CPU units of each instance type; ECS registers 1024 units per vCPU
+var InstanceCPUs = map[string]int{
+	"t2.nano":     1 * 1024,
+	"t2.micro":    1 * 1024,
+	"t2.small":    1 * 1024,
+	"t2.medium":   2 * 1024,
+	"t2.large":    2 * 1024,
+	"m4.large":    2 * 1024,
+	"m4.xlarge":   4 * 1024,
+	"m4.2xlarge":  8 * 1024,
+	"m4.4xlarge":  16 * 1024,
+	"m4.10xlarge": 40 * 1024,
+	"m3.medium":   1 * 1024,
+	"m3.large":    2 * 1024,
+	"m3.xlarge":   4 * 1024,
+	"m3.2xlarge":  8 * 1024,
+	"c4.large":    2 * 1024,
+	"c4.xlarge":   4 * 1024,
+	"c4.2xlarge":  8 * 1024,
+	"c4.4xlarge":  16 * 1024,
+	"c4.8xlarge":  36 * 1024,
+	"c3.large":    2 * 1024,
+	"c3.xlarge":   4 * 1024,
+	"c3.2xlarge":  8 * 1024,
+	"c3.4xlarge":  16 * 1024,
+	"c3.8xlarge":  32 * 1024,
+	"g2.2xlarge":  8 * 1024,
+	"g2.8xlarge":  32 * 1024,
+	"x1.32xlarge": 128 * 1024,
+	"r3.large":    2 * 1024,
+	"r3.xlarge":   4 * 1024,
+	"r3.2xlarge":  8 * 1024,
+	"r3.4xlarge":  16 * 1024,
+	"r3.8xlarge":  32 * 1024,
+	"i3.large":    2 * 1024,
+	"i3.xlarge":   4 * 1024,
+	"i3.2xlarge":  8 * 1024,
+	"i3.4xlarge":  16 * 1024,
+	"i3.8xlarge":  32 * 1024,
+	"d2.xlarge":   4 * 1024,
+	"d2.2xlarge":  8 * 1024,
+	"d2.4xlarge":  16 * 1024,
+	"d2.8xlarge":  36 * 1024,
+}
+
 type SecurityGroup struct {
 	*ec2.SecurityGroup
 }
@@ -134,6 +134,8 @@ func (this *S3) GetObjectToFile(bucket, key, path string, fileMode os.FileMode)
 	return ioutil.WriteFile(path, body, fileMode)
 }
 
+// ListObjects returns the key of every object in the bucket that starts with prefix.
+// S3 returns at most 1000 keys per call, so the listing is paged through until it is no longer truncated
 func (this *S3) ListObjects(bucket, prefix string) ([]string, error) {
 	input := &s3.ListObjectsInput{
 		Bucket: aws.String(bucket),
@@ -145,15 +147,27 @@ func (this *S3) ListObjects(bucket, prefix string) ([]string, error) {
 		return nil, err
 	}
 
-	resp, err := connection.ListObjects(input)
-	if err != nil {
-		return nil, err
-	}
-
 	keys := []string{}
-	for _, object := range resp.Contents {
-		keys = append(keys, *object.Key)
-	}
+	for {
+		resp, err := connection.ListObjects(input)
+		if err != nil {
+			return nil, err
+		}
 
-	return keys, nil
+		for _, object := range resp.Contents {
+			keys = append(keys, *object.Key)
+		}
+
+		if !aws.BoolValue(resp.IsTruncated) || len(resp.Contents) == 0 {
+			return keys, nil
+		}
+
+		// NextMarker is only set when a delimiter is used; otherwise the last key is the marker
+		marker := resp.NextMarker
+		if marker == nil {
+			marker = resp.Contents[len(resp.Contents)-1].Key
+		}
+
+		input.Marker = marker
+	}
 }
@@ -0,0 +1,61 @@
+package s3
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+// pagedS3 returns its keys in pages of pageSize, like S3 does with 1000 keys
+type pagedS3 struct {
+	s3Internal
+	keys     []string
+	pageSize int
+	calls    int
+}
+
+func (p *pagedS3) ListObjects(input *s3.ListObjectsInput) (*s3.ListObjectsOutput, error) {
+	p.calls++
+
+	start := 0
+	if marker := aws.StringValue(input.Marker); marker != "" {
+		for i, key := range p.keys {
+			if key == marker {
+				start = i + 1
+			}
+		}
+	}
+
+	end := start + p.pageSize
+	if end > len(p.keys) {
+		end = len(p.keys)
+	}
+
+	output := &s3.ListObjectsOutput{IsTruncated: aws.Bool(end < len(p.keys))}
+	for _, key := range p.keys[start:end] {
+		output.Contents = append(output.Contents, &s3.Object{Key: aws.String(key)})
+	}
+
+	return output, nil
+}
+
+func TestListObjectsPages(t *testing.T) {
+	keys := []string{}
+	for i := 0; i < 2500; i++ {
+		keys = append(keys, fmt.Sprintf("prefix/%04d", i))
+	}
+
+	connection := &pagedS3{keys: keys, pageSize: 1000}
+	provider := &S3{Connect: func() (s3Internal, error) { return connection, nil }}
+
+	result, err := provider.ListObjects("bucket", "prefix/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, result, keys)
+	testutils.AssertEqual(t, connection.calls, 3)
+}
@@ -3,42 +3,58 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // IMPORTANT!
 // The environment variables represented as constants here should
 // always line up with the environment variables in setup/container_definitions.json
 const (
-	AWS_ACCOUNT_ID            = "LAYER0_AWS_ACCOUNT_ID"
-	AWS_ACCESS_KEY_ID         = "LAYER0_AWS_ACCESS_KEY_ID"
-	AWS_SECRET_ACCESS_KEY     = "LAYER0_AWS_SECRET_ACCESS_KEY"
-	AWS_VPC_ID                = "LAYER0_AWS_VPC_ID"
-	AWS_PRIVATE_SUBNETS       = "LAYER0_AWS_PRIVATE_SUBNETS"
-	AWS_PUBLIC_SUBNETS        = "LAYER0_AWS_PUBLIC_SUBNETS"
-	AWS_ECS_ROLE              = "LAYER0_AWS_ECS_ROLE"
-	AWS_SSH_KEY_PAIR          = "LAYER0_AWS_SSH_KEY_PAIR"
-	AWS_S3_BUCKET             = "LAYER0_AWS_S3_BUCKET"
-	AWS_ECS_INSTANCE_PROFILE  = "LAYER0_AWS_ECS_INSTANCE_PROFILE"
-	AWS_DYNAMO_TAG_TABLE      = "LAYER0_AWS_DYNAMO_TAG_TABLE"
-	AWS_DYNAMO_JOB_TABLE      = "LAYER0_AWS_DYNAMO_JOB_TABLE"
-	JOB_ID                    = "LAYER0_JOB_ID"
-	AWS_LINUX_SERVICE_AMI     = "LAYER0_AWS_LINUX_SERVICE_AMI"
-	AWS_WINDOWS_SERVICE_AMI   = "LAYER0_AWS_WINDOWS_SERVICE_AMI"
-	AWS_REGION                = "LAYER0_AWS_REGION"
-	AUTH_TOKEN                = "LAYER0_AUTH_TOKEN"
-	API_ENDPOINT              = "LAYER0_API_ENDPOINT"
-	API_PORT                  = "LAYER0_API_PORT"
-	API_LOG_LEVEL             = "LAYER0_API_LOG_LEVEL"
-	PREFIX                    = "LAYER0_PREFIX"
-	RUNNER_LOG_LEVEL          = "LAYER0_RUNNER_LOG_LEVEL"
-	RUNNER_VERSION_TAG        = "LAYER0_RUNNER_VERSION_TAG"
-	SETUP_LOG_LEVEL           = "LAYER0_SETUP_LOG_LEVEL"
-	SKIP_SSL_VERIFY           = "LAYER0_SKIP_SSL_VERIFY"
-	SKIP_VERSION_VERIFY       = "LAYER0_SKIP_VERSION_VERIFY"
-	TEST_AWS_TAG_DYNAMO_TABLE = "LAYER0_TEST_AWS_TAG_DYNAMO_TABLE"
-	TEST_AWS_JOB_DYNAMO_TABLE = "LAYER0_TEST_AWS_JOB_DYNAMO_TABLE"
-	AWS_TIME_BETWEEN_REQUESTS = "LAYER0_AWS_TIME_BETWEEN_REQUESTS"
+	AWS_ACCOUNT_ID              = "LAYER0_AWS_ACCOUNT_ID"
+	AWS_ACCESS_KEY_ID           = "LAYER0_AWS_ACCESS_KEY_ID"
+	AWS_SECRET_ACCESS_KEY       = "LAYER0_AWS_SECRET_ACCESS_KEY"
+	AWS_VPC_ID                  = "LAYER0_AWS_VPC_ID"
+	AWS_PRIVATE_SUBNETS         = "LAYER0_AWS_PRIVATE_SUBNETS"
+	AWS_PUBLIC_SUBNETS          = "LAYER0_AWS_PUBLIC_SUBNETS"
+	AWS_ECS_ROLE                = "LAYER0_AWS_ECS_ROLE"
+	AWS_SSH_KEY_PAIR            = "LAYER0_AWS_SSH_KEY_PAIR"
+	AWS_S3_BUCKET               = "LAYER0_AWS_S3_BUCKET"
+	AWS_ECS_INSTANCE_PROFILE    = "LAYER0_AWS_ECS_INSTANCE_PROFILE"
+	AWS_DYNAMO_TAG_TABLE        = "LAYER0_AWS_DYNAMO_TAG_TABLE"
+	AWS_DYNAMO_JOB_TABLE        = "LAYER0_AWS_DYNAMO_JOB_TABLE"
+	AWS_DYNAMO_LEASE_TABLE      = "LAYER0_AWS_DYNAMO_LEASE_TABLE"
+	DB_DRIVER                   = "LAYER0_DB_DRIVER"
+	DB_DATA_SOURCE              = "LAYER0_DB_DATA_SOURCE"
+	JOB_ID                      = "LAYER0_JOB_ID"
+	JOB_EXECUTOR                = "LAYER0_JOB_EXECUTOR"
+	JOB_WORKERS                 = "LAYER0_JOB_WORKERS"
+	JOB_QUEUE_SIZE              = "LAYER0_JOB_QUEUE_SIZE"
+	JOB_RETENTION_COMPLETED     = "LAYER0_JOB_RETENTION_COMPLETED"
+	JOB_RETENTION_ERROR         = "LAYER0_JOB_RETENTION_ERROR"
+	JOB_RETENTION_CANCELLED     = "LAYER0_JOB_RETENTION_CANCELLED"
+	JOB_RETENTION_UNFINISHED    = "LAYER0_JOB_RETENTION_UNFINISHED"
+	TAG_JANITOR_DRY_RUN         = "LAYER0_TAG_JANITOR_DRY_RUN"
+	AWS_LINUX_SERVICE_AMI       = "LAYER0_AWS_LINUX_SERVICE_AMI"
+	AWS_WINDOWS_SERVICE_AMI     = "LAYER0_AWS_WINDOWS_SERVICE_AMI"
+	AWS_REGION                  = "LAYER0_AWS_REGION"
+	AUTH_TOKEN                  = "LAYER0_AUTH_TOKEN"
+	API_ENDPOINT                = "LAYER0_API_ENDPOINT"
+	API_PORT                    = "LAYER0_API_PORT"
+	API_LOG_LEVEL               = "LAYER0_API_LOG_LEVEL"
+	PREFIX                      = "LAYER0_PREFIX"
+	RUNNER_LOG_LEVEL            = "LAYER0_RUNNER_LOG_LEVEL"
+	RUNNER_VERSION_TAG          = "LAYER0_RUNNER_VERSION_TAG"
+	SETUP_LOG_LEVEL             = "LAYER0_SETUP_LOG_LEVEL"
+	SKIP_SSL_VERIFY             = "LAYER0_SKIP_SSL_VERIFY"
+	SKIP_VERSION_VERIFY         = "LAYER0_SKIP_VERSION_VERIFY"
+	TEST_AWS_TAG_DYNAMO_TABLE   = "LAYER0_TEST_AWS_TAG_DYNAMO_TABLE"
+	TEST_AWS_JOB_DYNAMO_TABLE   = "LAYER0_TEST_AWS_JOB_DYNAMO_TABLE"
+	TEST_AWS_LEASE_DYNAMO_TABLE = "LAYER0_TEST_AWS_LEASE_DYNAMO_TABLE"
+	TEST_DB_DRIVER              = "LAYER0_TEST_DB_DRIVER"
+	TEST_DB_DATA_SOURCE         = "LAYER0_TEST_DB_DATA_SOURCE"
+	AWS_TIME_BETWEEN_REQUESTS   = "LAYER0_AWS_TIME_BETWEEN_REQUESTS"
 )
 
 // defaults
@@ -49,6 +65,25 @@ const (
 	DEFAULT_API_PORT              = "9090"
 	DEFAULT_TIME_BETWEEN_REQUESTS = "10ms"
 	DEFAULT_MAX_RETRIES           = 999
+	DEFAULT_JOB_EXECUTOR          = JOB_EXECUTOR_ECS
+	DEFAULT_DB_DRIVER             = DB_DRIVER_DYNAMO
+	DEFAULT_JOB_WORKERS           = 4
+	DEFAULT_JOB_QUEUE_SIZE        = 100
+	DEFAULT_JOB_RETENTION_SHORT   = time.Hour * 24
+	DEFAULT_JOB_RETENTION_LONG    = time.Hour * 24 * 7
+)
+
+// job executors
+const (
+	JOB_EXECUTOR_ECS   = "ecs"
+	JOB_EXECUTOR_LOCAL = "local"
+)
+
+// tag and job store backends; any other driver is a database/sql driver
+// and requires LAYER0_DB_DATA_SOURCE, e.g. 'postgres' or 'mysql'. 'sqlite3' is only
+// compiled into test binaries built with the 'sqlite' tag
+const (
+	DB_DRIVER_DYNAMO = "dynamo"
 )
 
 // api resource tags
@@ -109,6 +144,24 @@ func getOr(key, defaultVal string) string {
 	return defaultVal
 }
 
+func getIntOr(key string, defaultVal int) int {
+	val, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultVal
+	}
+
+	return val
+}
+
+func getDurationOr(key string, defaultVal time.Duration) time.Duration {
+	val, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return defaultVal
+	}
+
+	return val
+}
+
 var apiVersion string
 
 func SetAPIVersion(version string) {
@@ -217,6 +270,31 @@ func TestDynamoJobTableName() string {
 	return get(TEST_AWS_JOB_DYNAMO_TABLE)
 }
 
+func DynamoLeaseTableName() string {
+	other := fmt.Sprintf("l0-%s-leases", Prefix())
+	return getOr(AWS_DYNAMO_LEASE_TABLE, other)
+}
+
+func TestDynamoLeaseTableName() string {
+	return get(TEST_AWS_LEASE_DYNAMO_TABLE)
+}
+
+func DBDriver() string {
+	return strings.ToLower(getOr(DB_DRIVER, DEFAULT_DB_DRIVER))
+}
+
+func DBDataSource() string {
+	return get(DB_DATA_SOURCE)
+}
+
+func TestDBDriver() string {
+	return get(TEST_DB_DRIVER)
+}
+
+func TestDBDataSource() string {
+	return get(TEST_DB_DATA_SOURCE)
+}
+
 func AWSTimeBetweenRequests() string {
 	return getOr(AWS_TIME_BETWEEN_REQUESTS, DEFAULT_TIME_BETWEEN_REQUESTS)
 }
@@ -254,3 +332,44 @@ func ShouldVerifyVersion() bool {
 
 	return true
 }
+
+// JobExecutor returns where job runners are executed: in their own ecs tasks ("ecs")
+// or by a worker pool inside the api ("local")
+func JobExecutor() string {
+	return strings.ToLower(getOr(JOB_EXECUTOR, DEFAULT_JOB_EXECUTOR))
+}
+
+func JobWorkers() int {
+	return getIntOr(JOB_WORKERS, DEFAULT_JOB_WORKERS)
+}
+
+func JobQueueSize() int {
+	return getIntOr(JOB_QUEUE_SIZE, DEFAULT_JOB_QUEUE_SIZE)
+}
+
+// JobRetentionCompleted is how long completed jobs are kept before they are archived
+func JobRetentionCompleted() time.Duration {
+	return getDurationOr(JOB_RETENTION_COMPLETED, DEFAULT_JOB_RETENTION_SHORT)
+}
+
+// JobRetentionError is how long failed jobs are kept before they are archived
+func JobRetentionError() time.Duration {
+	return getDurationOr(JOB_RETENTION_ERROR, DEFAULT_JOB_RETENTION_LONG)
+}
+
+// JobRetentionCancelled is how long cancelled jobs are kept before they are archived
+func JobRetentionCancelled() time.Duration {
+	return getDurationOr(JOB_RETENTION_CANCELLED, DEFAULT_JOB_RETENTION_SHORT)
+}
+
+// JobRetentionUnfinished is how long pending and in-progress jobs are kept before they are archived
+func JobRetentionUnfinished() time.Duration {
+	return getDurationOr(JOB_RETENTION_UNFINISHED, DEFAULT_JOB_RETENTION_LONG)
+}
+
+// TagJanitorDryRun reports whether the tag janitor only logs the tags of deleted entities
+// instead of deleting them
+func TagJanitorDryRun() bool {
+	val := strings.ToLower(getOr(TAG_JANITOR_DRY_RUN, ""))
+	return val == "1" || val == "true"
+}
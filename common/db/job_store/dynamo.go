@@ -1,6 +1,7 @@
 package job_store
 
 import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/guregu/dynamo"
 	"github.com/quintilesims/layer0/common/errors"
@@ -8,6 +9,13 @@ import (
 	"github.com/quintilesims/layer0/common/types"
 )
 
+// names of the job table's global secondary indexes; see setup/module/api/db.tf
+const (
+	JOB_STATUS_INDEX = "JobStatusIndex"
+	JOB_TYPE_INDEX   = "JobTypeIndex"
+	JOB_ENTITY_INDEX = "EntityIndex"
+)
+
 type DynamoJobStore struct {
 	table dynamo.Table
 }
@@ -55,6 +63,24 @@ func (d *DynamoJobStore) UpdateJobStatus(jobID string, status types.JobStatus) e
 	return nil
 }
 
+func (d *DynamoJobStore) TransitionJobStatus(jobID string, from, to types.JobStatus) (bool, error) {
+	// the condition also fails if the job doesn't exist, so the update never creates a job
+	err := d.table.Update("JobID", jobID).
+		Set("JobStatus", int64(to)).
+		If("'JobStatus' = ?", int64(from)).
+		Run()
+
+	if isConditionalCheckFailed(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 func (d *DynamoJobStore) SetJobMeta(jobID string, meta map[string]string) error {
 	if err := d.table.Update("JobID", jobID).Set("Meta", meta).Run(); err != nil {
 		return err
@@ -63,6 +89,33 @@ func (d *DynamoJobStore) SetJobMeta(jobID string, meta map[string]string) error
 	return nil
 }
 
+func (d *DynamoJobStore) SetJobSteps(jobID string, steps []models.JobStep) error {
+	if err := d.table.Update("JobID", jobID).Set("Steps", steps).Run(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d *DynamoJobStore) SetJobTaskID(jobID, taskID string) error {
+	if err := d.table.Update("JobID", jobID).Set("TaskID", taskID).Run(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d *DynamoJobStore) SetJobEntity(jobID, entityType, entityID string) error {
+	if err := d.table.Update("JobID", jobID).
+		Set("EntityType", entityType).
+		Set("EntityID", entityID).
+		Run(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (d *DynamoJobStore) SelectAll() ([]*models.Job, error) {
 	jobs := []*models.Job{}
 	if err := d.table.Scan().
@@ -90,3 +143,45 @@ func (d *DynamoJobStore) SelectByID(jobID string) (*models.Job, error) {
 
 	return job, nil
 }
+
+func (d *DynamoJobStore) SelectByStatus(status types.JobStatus) ([]*models.Job, error) {
+	jobs := []*models.Job{}
+	if err := d.table.Get("JobStatus", int64(status)).
+		Index(JOB_STATUS_INDEX).
+		All(&jobs); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (d *DynamoJobStore) SelectByType(jobType types.JobType) ([]*models.Job, error) {
+	jobs := []*models.Job{}
+	if err := d.table.Get("JobType", int64(jobType)).
+		Index(JOB_TYPE_INDEX).
+		All(&jobs); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (d *DynamoJobStore) SelectByEntity(entityType, entityID string) ([]*models.Job, error) {
+	jobs := []*models.Job{}
+	if err := d.table.Get("EntityType", entityType).
+		Range("EntityID", dynamo.Equal, entityID).
+		Index(JOB_ENTITY_INDEX).
+		All(&jobs); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func isConditionalCheckFailed(err error) bool {
+	if err, ok := err.(awserr.Error); ok && err.Code() == "ConditionalCheckFailedException" {
+		return true
+	}
+
+	return false
+}
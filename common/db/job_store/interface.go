@@ -11,6 +11,15 @@ type JobStore interface {
 	Insert(*models.Job) error
 	SelectAll() ([]*models.Job, error)
 	SelectByID(string) (*models.Job, error)
+	SelectByStatus(types.JobStatus) ([]*models.Job, error)
+	SelectByType(types.JobType) ([]*models.Job, error)
+	SelectByEntity(entityType, entityID string) ([]*models.Job, error)
 	UpdateJobStatus(string, types.JobStatus) error
+	// TransitionJobStatus sets the status of the job to 'to' only if its status is 'from'.
+	// It reports whether the status was changed
+	TransitionJobStatus(jobID string, from, to types.JobStatus) (bool, error)
 	SetJobMeta(string, map[string]string) error
+	SetJobSteps(string, []models.JobStep) error
+	SetJobTaskID(string, string) error
+	SetJobEntity(jobID, entityType, entityID string) error
 }
@@ -0,0 +1,346 @@
+package job_store
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/types"
+)
+
+// testJobStoreBehavior runs the same checks against each JobStore implementation;
+// newStore must return an empty store
+func testJobStoreBehavior(t *testing.T, newStore func(t *testing.T) JobStore) {
+	tests := map[string]func(*testing.T, JobStore){
+		"Insert":            testJobStoreInsert,
+		"Delete":            testJobStoreDelete,
+		"SelectAll":         testJobStoreSelectAll,
+		"SelectByID":        testJobStoreSelectByID,
+		"SelectByIDMissing": testJobStoreSelectByIDMissing,
+		"UpdateStatus":      testJobStoreUpdateStatus,
+		"TransitionStatus":  testJobStoreTransitionStatus,
+		"SetMeta":           testJobStoreSetMeta,
+		"SetSteps":          testJobStoreSetSteps,
+		"SetTaskID":         testJobStoreSetTaskID,
+		"SelectByStatus":    testJobStoreSelectByStatus,
+		"SelectByType":      testJobStoreSelectByType,
+		"SelectByEntity":    testJobStoreSelectByEntity,
+	}
+
+	for name, fn := range tests {
+		fn := fn
+		t.Run(name, func(t *testing.T) {
+			fn(t, newStore(t))
+		})
+	}
+}
+
+func TestMemoryJobStore(t *testing.T) {
+	testJobStoreBehavior(t, func(t *testing.T) JobStore {
+		return NewMemoryJobStore()
+	})
+}
+
+func testJobStoreInsert(t *testing.T, store JobStore) {
+	job := &models.Job{
+		JobID:       "1",
+		TaskID:      "t1",
+		JobStatus:   int64(types.Pending),
+		JobType:     int64(types.DeleteEnvironmentJob),
+		Request:     "e1",
+		TimeCreated: time.Date(2017, 1, 2, 3, 4, 5, 6, time.UTC),
+		EntityType:  "environment",
+		EntityID:    "e1",
+		ParentJobID: "p1",
+		Meta:        map[string]string{"alpha": "1"},
+		Steps:       []models.JobStep{{Name: "alpha", Status: int64(types.Completed)}},
+	}
+
+	if err := store.Insert(job); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := store.SelectByID(job.JobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.TimeCreated.Equal(job.TimeCreated) {
+		t.Fatalf("TimeCreated was '%v', expected '%v'", result.TimeCreated, job.TimeCreated)
+	}
+
+	result.TimeCreated = job.TimeCreated
+	if r, e := result, job; !reflect.DeepEqual(r, e) {
+		t.Fatalf("Result was %#v, expected %#v", r, e)
+	}
+}
+
+func testJobStoreDelete(t *testing.T, store JobStore) {
+	job := &models.Job{JobID: "1", JobType: int64(types.DeleteEnvironmentJob)}
+	if err := store.Insert(job); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Delete(job.JobID); err != nil {
+		t.Fatal(err)
+	}
+
+	assertJobDoesNotExist(t, store, job.JobID)
+}
+
+func testJobStoreSelectByIDMissing(t *testing.T, store JobStore) {
+	assertJobDoesNotExist(t, store, "1")
+}
+
+func assertJobDoesNotExist(t *testing.T, store JobStore, jobID string) {
+	_, err := store.SelectByID(jobID)
+	if serr, ok := err.(*errors.ServerError); !ok || serr.Code != errors.JobDoesNotExist {
+		t.Fatalf("Error was '%v', expected JobDoesNotExist", err)
+	}
+}
+
+func testJobStoreSelectAll(t *testing.T, store JobStore) {
+	jobs := []*models.Job{
+		{JobID: "1", JobType: int64(types.DeleteEnvironmentJob)},
+		{JobID: "2", JobType: int64(types.DeleteEnvironmentJob)},
+		{JobID: "3", JobType: int64(types.DeleteServiceJob)},
+		{JobID: "4", JobType: int64(types.DeleteLoadBalancerJob)},
+		{JobID: "5", JobType: int64(types.DeleteTaskJob)},
+	}
+
+	for _, job := range jobs {
+		if err := store.Insert(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := store.SelectAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r, e := len(result), len(jobs); r != e {
+		t.Fatalf("Result had %d jobs, expected %d", r, e)
+	}
+}
+
+func testJobStoreSelectByID(t *testing.T, store JobStore) {
+	jobs := []*models.Job{
+		{JobID: "1", JobType: int64(types.DeleteEnvironmentJob)},
+		{JobID: "2", JobType: int64(types.DeleteEnvironmentJob)},
+		{JobID: "3", JobType: int64(types.DeleteServiceJob)},
+		{JobID: "4", JobType: int64(types.DeleteLoadBalancerJob)},
+		{JobID: "5", JobType: int64(types.DeleteTaskJob)},
+	}
+
+	for _, job := range jobs {
+		if err := store.Insert(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := store.SelectByID(jobs[2].JobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r, e := result.JobID, jobs[2].JobID; r != e {
+		t.Fatalf("Result was %#v, expected %#v", r, e)
+	}
+}
+
+func testJobStoreUpdateStatus(t *testing.T, store JobStore) {
+	job := &models.Job{JobID: "1", JobStatus: int64(types.Pending)}
+	if err := store.Insert(job); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.UpdateJobStatus(job.JobID, types.InProgress); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := store.SelectByID(job.JobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r, e := types.JobStatus(result.JobStatus), types.InProgress; r != e {
+		t.Fatalf("Status was '%s', expected '%s'", r, e)
+	}
+}
+
+func testJobStoreTransitionStatus(t *testing.T, store JobStore) {
+	job := &models.Job{JobID: "1", JobStatus: int64(types.InProgress)}
+	if err := store.Insert(job); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := store.TransitionJobStatus(job.JobID, types.Pending, types.Cancelled)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if changed {
+		t.Fatalf("Status of an InProgress job was changed from Pending")
+	}
+
+	changed, err = store.TransitionJobStatus(job.JobID, types.InProgress, types.Completed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !changed {
+		t.Fatalf("Status of an InProgress job was not changed from InProgress")
+	}
+
+	result, err := store.SelectByID(job.JobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r, e := types.JobStatus(result.JobStatus), types.Completed; r != e {
+		t.Fatalf("Status was '%s', expected '%s'", r, e)
+	}
+}
+
+func testJobStoreSetMeta(t *testing.T, store JobStore) {
+	job := &models.Job{JobID: "1", Meta: map[string]string{"alpha": "1"}}
+	if err := store.Insert(job); err != nil {
+		t.Fatal(err)
+	}
+
+	meta := map[string]string{"beta": "2"}
+	if err := store.SetJobMeta(job.JobID, meta); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := store.SelectByID(job.JobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r, e := result.Meta, meta; !reflect.DeepEqual(r, e) {
+		t.Fatalf("Status was '%s', expected '%s'", r, e)
+	}
+
+}
+
+func testJobStoreSetSteps(t *testing.T, store JobStore) {
+	job := &models.Job{JobID: "1"}
+	if err := store.Insert(job); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []models.JobStep{
+		{Name: "alpha", Status: int64(types.Completed)},
+		{Name: "beta", Status: int64(types.Error), Error: "some error"},
+	}
+
+	if err := store.SetJobSteps(job.JobID, steps); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := store.SelectByID(job.JobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r, e := result.Steps, steps; !reflect.DeepEqual(r, e) {
+		t.Fatalf("Steps were '%v', expected '%v'", r, e)
+	}
+}
+
+func testJobStoreSetTaskID(t *testing.T, store JobStore) {
+	job := &models.Job{JobID: "1", TaskID: "t1"}
+	if err := store.Insert(job); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SetJobTaskID(job.JobID, "t2"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := store.SelectByID(job.JobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r, e := result.TaskID, "t2"; r != e {
+		t.Fatalf("TaskID was '%s', expected '%s'", r, e)
+	}
+}
+
+func testJobStoreSelectByStatus(t *testing.T, store JobStore) {
+	jobs := []*models.Job{
+		{JobID: "1", JobStatus: int64(types.Pending)},
+		{JobID: "2", JobStatus: int64(types.Error)},
+		{JobID: "3", JobStatus: int64(types.Error)},
+	}
+
+	for _, job := range jobs {
+		if err := store.Insert(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := store.SelectByStatus(types.Error)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r, e := len(result), 2; r != e {
+		t.Fatalf("Result had %d jobs, expected %d", r, e)
+	}
+}
+
+func testJobStoreSelectByType(t *testing.T, store JobStore) {
+	jobs := []*models.Job{
+		{JobID: "1", JobType: int64(types.DeleteEnvironmentJob)},
+		{JobID: "2", JobType: int64(types.DeleteServiceJob)},
+		{JobID: "3", JobType: int64(types.DeleteEnvironmentJob)},
+	}
+
+	for _, job := range jobs {
+		if err := store.Insert(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := store.SelectByType(types.DeleteServiceJob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r, e := len(result), 1; r != e {
+		t.Fatalf("Result had %d jobs, expected %d", r, e)
+	}
+}
+
+func testJobStoreSelectByEntity(t *testing.T, store JobStore) {
+	jobs := []*models.Job{
+		{JobID: "1", EntityType: "service", EntityID: "s1"},
+		{JobID: "2", EntityType: "service", EntityID: "s2"},
+		{JobID: "3"},
+	}
+
+	for _, job := range jobs {
+		if err := store.Insert(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := store.SetJobEntity("3", "service", "s1"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := store.SelectByEntity("service", "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r, e := len(result), 2; r != e {
+		t.Fatalf("Result had %d jobs, expected %d", r, e)
+	}
+}
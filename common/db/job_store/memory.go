@@ -1,14 +1,17 @@
 package job_store
 
 import (
-	"fmt"
+	"sync"
 
+	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/types"
 )
 
+// MemoryJobStore is safe for concurrent use; jobs are copied in and out of the store
 type MemoryJobStore struct {
-	jobs []*models.Job
+	jobs  []*models.Job
+	mutex sync.Mutex
 }
 
 func NewMemoryJobStore() *MemoryJobStore {
@@ -22,11 +25,17 @@ func (m *MemoryJobStore) Init() error {
 }
 
 func (m *MemoryJobStore) Insert(job *models.Job) error {
-	m.jobs = append(m.jobs, job)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.jobs = append(m.jobs, copyJob(job))
 	return nil
 }
 
 func (m *MemoryJobStore) Delete(jobID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	for i := 0; i < len(m.jobs); i++ {
 		if m.jobs[i].JobID == jobID {
 			m.jobs = append(m.jobs[:i], m.jobs[i+1:]...)
@@ -38,35 +47,117 @@ func (m *MemoryJobStore) Delete(jobID string) error {
 }
 
 func (m *MemoryJobStore) SelectAll() ([]*models.Job, error) {
-	return m.jobs, nil
+	return m.selectWhere(func(*models.Job) bool { return true }), nil
 }
 
 func (m *MemoryJobStore) SelectByID(jobID string) (*models.Job, error) {
+	var job *models.Job
+	err := m.update(jobID, func(j *models.Job) {
+		job = copyJob(j)
+	})
+
+	return job, err
+}
+
+func (m *MemoryJobStore) SelectByStatus(status types.JobStatus) ([]*models.Job, error) {
+	return m.selectWhere(func(job *models.Job) bool {
+		return job.JobStatus == int64(status)
+	}), nil
+}
+
+func (m *MemoryJobStore) SelectByType(jobType types.JobType) ([]*models.Job, error) {
+	return m.selectWhere(func(job *models.Job) bool {
+		return job.JobType == int64(jobType)
+	}), nil
+}
+
+func (m *MemoryJobStore) SelectByEntity(entityType, entityID string) ([]*models.Job, error) {
+	return m.selectWhere(func(job *models.Job) bool {
+		return job.EntityType == entityType && job.EntityID == entityID
+	}), nil
+}
+
+func (m *MemoryJobStore) selectWhere(match func(*models.Job) bool) []*models.Job {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	jobs := []*models.Job{}
+	for _, job := range m.jobs {
+		if match(job) {
+			jobs = append(jobs, copyJob(job))
+		}
+	}
+
+	return jobs
+}
+
+// update calls fn with the stored job while holding the store's lock
+func (m *MemoryJobStore) update(jobID string, fn func(*models.Job)) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	for _, job := range m.jobs {
 		if job.JobID == jobID {
-			return job, nil
+			fn(job)
+			return nil
 		}
 	}
 
-	return nil, fmt.Errorf("Job with id '%s' does not exist", jobID)
+	return errors.Newf(errors.JobDoesNotExist, "Job %s does not exist", jobID)
 }
 
-func (m *MemoryJobStore) UpdateJobStatus(jobID string, status types.JobStatus) error {
-	job, err := m.SelectByID(jobID)
-	if err != nil {
-		return err
+func copyJob(job *models.Job) *models.Job {
+	c := *job
+	if job.Meta != nil {
+		c.Meta = map[string]string{}
+		for k, v := range job.Meta {
+			c.Meta[k] = v
+		}
 	}
 
-	job.JobStatus = int64(status)
-	return nil
+	c.Steps = append([]models.JobStep(nil), job.Steps...)
+	return &c
+}
+
+func (m *MemoryJobStore) UpdateJobStatus(jobID string, status types.JobStatus) error {
+	return m.update(jobID, func(job *models.Job) {
+		job.JobStatus = int64(status)
+	})
+}
+
+func (m *MemoryJobStore) TransitionJobStatus(jobID string, from, to types.JobStatus) (bool, error) {
+	var changed bool
+	err := m.update(jobID, func(job *models.Job) {
+		if types.JobStatus(job.JobStatus) == from {
+			job.JobStatus = int64(to)
+			changed = true
+		}
+	})
+
+	return changed, err
 }
 
 func (m *MemoryJobStore) SetJobMeta(jobID string, meta map[string]string) error {
-	job, err := m.SelectByID(jobID)
-	if err != nil {
-		return err
-	}
+	return m.update(jobID, func(job *models.Job) {
+		job.Meta = meta
+	})
+}
 
-	job.Meta = meta
-	return nil
+func (m *MemoryJobStore) SetJobSteps(jobID string, steps []models.JobStep) error {
+	return m.update(jobID, func(job *models.Job) {
+		job.Steps = append([]models.JobStep{}, steps...)
+	})
+}
+
+func (m *MemoryJobStore) SetJobTaskID(jobID, taskID string) error {
+	return m.update(jobID, func(job *models.Job) {
+		job.TaskID = taskID
+	})
+}
+
+func (m *MemoryJobStore) SetJobEntity(jobID, entityType, entityID string) error {
+	return m.update(jobID, func(job *models.Job) {
+		job.EntityType = entityType
+		job.EntityID = entityID
+	})
 }
@@ -83,6 +83,19 @@ func (mr *MockJobStoreMockRecorder) SelectAll() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAll", reflect.TypeOf((*MockJobStore)(nil).SelectAll))
 }
 
+// SelectByEntity mocks base method
+func (m *MockJobStore) SelectByEntity(arg0, arg1 string) ([]*models.Job, error) {
+	ret := m.ctrl.Call(m, "SelectByEntity", arg0, arg1)
+	ret0, _ := ret[0].([]*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectByEntity indicates an expected call of SelectByEntity
+func (mr *MockJobStoreMockRecorder) SelectByEntity(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectByEntity", reflect.TypeOf((*MockJobStore)(nil).SelectByEntity), arg0, arg1)
+}
+
 // SelectByID mocks base method
 func (m *MockJobStore) SelectByID(arg0 string) (*models.Job, error) {
 	ret := m.ctrl.Call(m, "SelectByID", arg0)
@@ -96,6 +109,44 @@ func (mr *MockJobStoreMockRecorder) SelectByID(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectByID", reflect.TypeOf((*MockJobStore)(nil).SelectByID), arg0)
 }
 
+// SelectByStatus mocks base method
+func (m *MockJobStore) SelectByStatus(arg0 types.JobStatus) ([]*models.Job, error) {
+	ret := m.ctrl.Call(m, "SelectByStatus", arg0)
+	ret0, _ := ret[0].([]*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectByStatus indicates an expected call of SelectByStatus
+func (mr *MockJobStoreMockRecorder) SelectByStatus(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectByStatus", reflect.TypeOf((*MockJobStore)(nil).SelectByStatus), arg0)
+}
+
+// SelectByType mocks base method
+func (m *MockJobStore) SelectByType(arg0 types.JobType) ([]*models.Job, error) {
+	ret := m.ctrl.Call(m, "SelectByType", arg0)
+	ret0, _ := ret[0].([]*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectByType indicates an expected call of SelectByType
+func (mr *MockJobStoreMockRecorder) SelectByType(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectByType", reflect.TypeOf((*MockJobStore)(nil).SelectByType), arg0)
+}
+
+// SetJobEntity mocks base method
+func (m *MockJobStore) SetJobEntity(arg0, arg1, arg2 string) error {
+	ret := m.ctrl.Call(m, "SetJobEntity", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetJobEntity indicates an expected call of SetJobEntity
+func (mr *MockJobStoreMockRecorder) SetJobEntity(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetJobEntity", reflect.TypeOf((*MockJobStore)(nil).SetJobEntity), arg0, arg1, arg2)
+}
+
 // SetJobMeta mocks base method
 func (m *MockJobStore) SetJobMeta(arg0 string, arg1 map[string]string) error {
 	ret := m.ctrl.Call(m, "SetJobMeta", arg0, arg1)
@@ -108,6 +159,43 @@ func (mr *MockJobStoreMockRecorder) SetJobMeta(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetJobMeta", reflect.TypeOf((*MockJobStore)(nil).SetJobMeta), arg0, arg1)
 }
 
+// SetJobSteps mocks base method
+func (m *MockJobStore) SetJobSteps(arg0 string, arg1 []models.JobStep) error {
+	ret := m.ctrl.Call(m, "SetJobSteps", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetJobSteps indicates an expected call of SetJobSteps
+func (mr *MockJobStoreMockRecorder) SetJobSteps(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetJobSteps", reflect.TypeOf((*MockJobStore)(nil).SetJobSteps), arg0, arg1)
+}
+
+// SetJobTaskID mocks base method
+func (m *MockJobStore) SetJobTaskID(arg0, arg1 string) error {
+	ret := m.ctrl.Call(m, "SetJobTaskID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetJobTaskID indicates an expected call of SetJobTaskID
+func (mr *MockJobStoreMockRecorder) SetJobTaskID(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetJobTaskID", reflect.TypeOf((*MockJobStore)(nil).SetJobTaskID), arg0, arg1)
+}
+
+// TransitionJobStatus mocks base method
+func (m *MockJobStore) TransitionJobStatus(arg0 string, arg1, arg2 types.JobStatus) (bool, error) {
+	ret := m.ctrl.Call(m, "TransitionJobStatus", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransitionJobStatus indicates an expected call of TransitionJobStatus
+func (mr *MockJobStoreMockRecorder) TransitionJobStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionJobStatus", reflect.TypeOf((*MockJobStore)(nil).TransitionJobStatus), arg0, arg1, arg2)
+}
+
 // UpdateJobStatus mocks base method
 func (m *MockJobStore) UpdateJobStatus(arg0 string, arg1 types.JobStatus) error {
 	ret := m.ctrl.Call(m, "UpdateJobStatus", arg0, arg1)
@@ -0,0 +1,250 @@
+package job_store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/quintilesims/layer0/common/db/sqldb"
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/types"
+)
+
+const SQL_JOB_SCHEMA = "job_store"
+
+var sqlJobColumns = []string{
+	"job_id",
+	"task_id",
+	"job_status",
+	"job_type",
+	"request",
+	"time_created",
+	"entity_type",
+	"entity_id",
+	"parent_job_id",
+	"meta",
+	"steps",
+}
+
+func sqlJobMigrations(db *sqldb.DB) []sqldb.Migration {
+	return []sqldb.Migration{
+		{
+			Version:     1,
+			Description: "create jobs table",
+			Statements: []string{
+				`CREATE TABLE IF NOT EXISTS jobs (
+					job_id        VARCHAR(255) NOT NULL,
+					task_id       VARCHAR(255) NOT NULL,
+					job_status    BIGINT NOT NULL,
+					job_type      BIGINT NOT NULL,
+					request       ` + db.TextType() + ` NOT NULL,
+					time_created  VARCHAR(64) NOT NULL,
+					entity_type   VARCHAR(255) NOT NULL,
+					entity_id     VARCHAR(255) NOT NULL,
+					parent_job_id VARCHAR(255) NOT NULL,
+					meta          ` + db.TextType() + ` NOT NULL,
+					steps         ` + db.TextType() + ` NOT NULL,
+					PRIMARY KEY (job_id))`,
+				db.CreateIndex("jobs_status_index", "jobs", "job_status"),
+				db.CreateIndex("jobs_type_index", "jobs", "job_type"),
+				db.CreateIndex("jobs_entity_index", "jobs", "entity_type", "entity_id"),
+			},
+		},
+	}
+}
+
+// SQLJobStore stores a job's meta and steps as json
+type SQLJobStore struct {
+	db *sqldb.DB
+}
+
+func NewSQLJobStore(db *sqldb.DB) *SQLJobStore {
+	return &SQLJobStore{
+		db: db,
+	}
+}
+
+func (s *SQLJobStore) Init() error {
+	return sqldb.Migrate(s.db, SQL_JOB_SCHEMA, sqlJobMigrations(s.db))
+}
+
+func (s *SQLJobStore) Clear() error {
+	_, err := s.db.Exec("DELETE FROM jobs")
+	return err
+}
+
+func (s *SQLJobStore) Insert(job *models.Job) error {
+	meta, err := json.Marshal(job.Meta)
+	if err != nil {
+		return err
+	}
+
+	steps, err := json.Marshal(job.Steps)
+	if err != nil {
+		return err
+	}
+
+	query := s.db.Upsert("jobs", sqlJobColumns, []string{"job_id"})
+	_, err = s.db.Exec(query,
+		job.JobID,
+		job.TaskID,
+		job.JobStatus,
+		job.JobType,
+		job.Request,
+		job.TimeCreated.UTC().Format(time.RFC3339Nano),
+		job.EntityType,
+		job.EntityID,
+		job.ParentJobID,
+		string(meta),
+		string(steps))
+
+	return err
+}
+
+func (s *SQLJobStore) Delete(jobID string) error {
+	_, err := s.db.Exec("DELETE FROM jobs WHERE job_id = ?", jobID)
+	return err
+}
+
+func (s *SQLJobStore) UpdateJobStatus(jobID string, status types.JobStatus) error {
+	return s.update(jobID, "job_status", int64(status))
+}
+
+func (s *SQLJobStore) TransitionJobStatus(jobID string, from, to types.JobStatus) (bool, error) {
+	result, err := s.db.Exec("UPDATE jobs SET job_status = ? WHERE job_id = ? AND job_status = ?", int64(to), jobID, int64(from))
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (s *SQLJobStore) SetJobMeta(jobID string, meta map[string]string) error {
+	bytes, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	return s.update(jobID, "meta", string(bytes))
+}
+
+func (s *SQLJobStore) SetJobSteps(jobID string, steps []models.JobStep) error {
+	bytes, err := json.Marshal(steps)
+	if err != nil {
+		return err
+	}
+
+	return s.update(jobID, "steps", string(bytes))
+}
+
+func (s *SQLJobStore) SetJobTaskID(jobID, taskID string) error {
+	return s.update(jobID, "task_id", taskID)
+}
+
+func (s *SQLJobStore) SetJobEntity(jobID, entityType, entityID string) error {
+	_, err := s.db.Exec("UPDATE jobs SET entity_type = ?, entity_id = ? WHERE job_id = ?",
+		entityType,
+		entityID,
+		jobID)
+
+	return err
+}
+
+func (s *SQLJobStore) update(jobID, column string, value interface{}) error {
+	_, err := s.db.Exec("UPDATE jobs SET "+column+" = ? WHERE job_id = ?", value, jobID)
+	return err
+}
+
+func (s *SQLJobStore) SelectAll() ([]*models.Job, error) {
+	return s.selectJobs("")
+}
+
+func (s *SQLJobStore) SelectByID(jobID string) (*models.Job, error) {
+	jobs, err := s.selectJobs("WHERE job_id = ?", jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(jobs) == 0 {
+		return nil, errors.Newf(errors.JobDoesNotExist, "Job %s does not exist", jobID)
+	}
+
+	return jobs[0], nil
+}
+
+func (s *SQLJobStore) SelectByStatus(status types.JobStatus) ([]*models.Job, error) {
+	return s.selectJobs("WHERE job_status = ?", int64(status))
+}
+
+func (s *SQLJobStore) SelectByType(jobType types.JobType) ([]*models.Job, error) {
+	return s.selectJobs("WHERE job_type = ?", int64(jobType))
+}
+
+func (s *SQLJobStore) SelectByEntity(entityType, entityID string) ([]*models.Job, error) {
+	return s.selectJobs("WHERE entity_type = ? AND entity_id = ?", entityType, entityID)
+}
+
+func (s *SQLJobStore) selectJobs(where string, args ...interface{}) ([]*models.Job, error) {
+	query := "SELECT " + strings.Join(sqlJobColumns, ", ") + " FROM jobs " + where
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []*models.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+func scanJob(rows *sql.Rows) (*models.Job, error) {
+	var job models.Job
+	var timeCreated, meta, steps string
+
+	if err := rows.Scan(
+		&job.JobID,
+		&job.TaskID,
+		&job.JobStatus,
+		&job.JobType,
+		&job.Request,
+		&timeCreated,
+		&job.EntityType,
+		&job.EntityID,
+		&job.ParentJobID,
+		&meta,
+		&steps); err != nil {
+		return nil, err
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, timeCreated)
+	if err != nil {
+		return nil, err
+	}
+
+	job.TimeCreated = t
+
+	if err := json.Unmarshal([]byte(meta), &job.Meta); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(steps), &job.Steps); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
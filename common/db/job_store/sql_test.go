@@ -0,0 +1,37 @@
+package job_store
+
+import (
+	"testing"
+
+	"github.com/quintilesims/layer0/common/config"
+	"github.com/quintilesims/layer0/common/db/sqldb"
+)
+
+func NewTestSQLJobStore(t *testing.T) *SQLJobStore {
+	driver := config.TestDBDriver()
+	if driver == "" {
+		t.Skipf("Skipping test: %s not set", config.TEST_DB_DRIVER)
+	}
+
+	db, err := sqldb.Open(driver, config.TestDBDataSource())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewSQLJobStore(db)
+	if err := store.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Clear(); err != nil {
+		t.Fatal(err)
+	}
+
+	return store
+}
+
+func TestSQLJobStore(t *testing.T) {
+	testJobStoreBehavior(t, func(t *testing.T) JobStore {
+		return NewTestSQLJobStore(t)
+	})
+}
@@ -0,0 +1,95 @@
+package lease_store
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/guregu/dynamo"
+	"github.com/quintilesims/layer0/common/waitutils"
+)
+
+type DynamoLeaseSchema struct {
+	Name   string
+	Holder string
+	// Expires is stored as unix nanoseconds so it can be compared in condition expressions
+	Expires int64
+}
+
+type DynamoLeaseStore struct {
+	Clock waitutils.Clock
+	table dynamo.Table
+}
+
+func NewDynamoLeaseStore(session *session.Session, table string) *DynamoLeaseStore {
+	db := dynamo.New(session)
+
+	return &DynamoLeaseStore{
+		Clock: waitutils.RealClock{},
+		table: db.Table(table),
+	}
+}
+
+func (d *DynamoLeaseStore) Init() error {
+	return nil
+}
+
+func (d *DynamoLeaseStore) Clear() error {
+	var leases []DynamoLeaseSchema
+	if err := d.table.Scan().All(&leases); err != nil {
+		return err
+	}
+
+	for _, lease := range leases {
+		if err := d.table.Delete("Name", lease.Name).Run(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *DynamoLeaseStore) Acquire(name, holder string, duration time.Duration) (bool, error) {
+	now := d.Clock.Now()
+	lease := DynamoLeaseSchema{
+		Name:    name,
+		Holder:  holder,
+		Expires: now.Add(duration).UnixNano(),
+	}
+
+	// the write only succeeds if nobody holds the lease, we already hold it, or it has expired
+	err := d.table.Put(lease).
+		If("attribute_not_exists('Name') OR 'Holder' = ? OR 'Expires' < ?", holder, now.UnixNano()).
+		Run()
+
+	if isConditionalCheckFailed(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (d *DynamoLeaseStore) Release(name, holder string) error {
+	err := d.table.Delete("Name", name).
+		If("'Holder' = ?", holder).
+		Run()
+
+	// the lease is held by someone else, or nobody
+	if isConditionalCheckFailed(err) {
+		return nil
+	}
+
+	return err
+}
+
+func isConditionalCheckFailed(err error) bool {
+	if err, ok := err.(awserr.Error); ok && err.Code() == "ConditionalCheckFailedException" {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,41 @@
+package lease_store
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/quintilesims/layer0/common/config"
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+func NewTestLeaseStore(t *testing.T) *DynamoLeaseStore {
+	table := config.TestDynamoLeaseTableName()
+	if table == "" {
+		t.Skipf("Skipping test: %s not set", config.TEST_AWS_LEASE_DYNAMO_TABLE)
+	}
+
+	creds := credentials.NewStaticCredentials(config.AWSAccessKey(), config.AWSSecretKey(), "")
+	awsConfig := &aws.Config{
+		Credentials: creds,
+		Region:      aws.String(config.AWSRegion()),
+	}
+
+	session := session.New(awsConfig)
+	store := NewDynamoLeaseStore(session, table)
+
+	if err := store.Clear(); err != nil {
+		t.Fatal(err)
+	}
+
+	return store
+}
+
+func TestDynamoLeaseStore(t *testing.T) {
+	clock := &testutils.StubClock{}
+	store := NewTestLeaseStore(t)
+	store.Clock = clock
+
+	testLeaseStoreBehavior(t, store, clock)
+}
@@ -0,0 +1,15 @@
+package lease_store
+
+import (
+	"time"
+)
+
+// LeaseStore grants each named lease to a single holder at a time
+type LeaseStore interface {
+	Init() error
+	// Acquire takes the lease for the holder, or renews it if the holder already has it.
+	// It returns false if another holder has a lease that hasn't expired
+	Acquire(name, holder string, duration time.Duration) (bool, error)
+	// Release gives up the lease if the holder has it
+	Release(name, holder string) error
+}
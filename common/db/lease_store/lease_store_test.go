@@ -0,0 +1,62 @@
+package lease_store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+// testLeaseStoreBehavior runs the same checks against each LeaseStore implementation;
+// the clock must be the one used by the store
+func testLeaseStoreBehavior(t *testing.T, store LeaseStore, clock *testutils.StubClock) {
+	acquire := func(holder string) bool {
+		acquired, err := store.Acquire("leader", holder, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return acquired
+	}
+
+	testutils.AssertEqual(t, acquire("h1"), true)
+
+	// the holder can renew its lease, but nobody else can take it
+	testutils.AssertEqual(t, acquire("h1"), true)
+	testutils.AssertEqual(t, acquire("h2"), false)
+
+	// once the lease expires, it can be taken by another holder
+	clock.Sleep(time.Minute * 2)
+	testutils.AssertEqual(t, acquire("h2"), true)
+	testutils.AssertEqual(t, acquire("h1"), false)
+
+	// releasing a lease held by someone else does nothing
+	if err := store.Release("leader", "h1"); err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, acquire("h1"), false)
+
+	// once released, the lease can be taken right away
+	if err := store.Release("leader", "h2"); err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, acquire("h1"), true)
+
+	// leases with different names are independent
+	acquired, err := store.Acquire("other", "h2", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, acquired, true)
+}
+
+func TestMemoryLeaseStore(t *testing.T) {
+	clock := &testutils.StubClock{}
+	store := NewMemoryLeaseStore()
+	store.Clock = clock
+
+	testLeaseStoreBehavior(t, store, clock)
+}
@@ -0,0 +1,54 @@
+package lease_store
+
+import (
+	"sync"
+	"time"
+
+	"github.com/quintilesims/layer0/common/waitutils"
+)
+
+type memoryLease struct {
+	holder  string
+	expires time.Time
+}
+
+type MemoryLeaseStore struct {
+	Clock  waitutils.Clock
+	leases map[string]memoryLease
+	mutex  sync.Mutex
+}
+
+func NewMemoryLeaseStore() *MemoryLeaseStore {
+	return &MemoryLeaseStore{
+		Clock:  waitutils.RealClock{},
+		leases: map[string]memoryLease{},
+	}
+}
+
+func (m *MemoryLeaseStore) Init() error {
+	return nil
+}
+
+func (m *MemoryLeaseStore) Acquire(name, holder string, duration time.Duration) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := m.Clock.Now()
+	if lease, ok := m.leases[name]; ok && lease.holder != holder && now.Before(lease.expires) {
+		return false, nil
+	}
+
+	m.leases[name] = memoryLease{holder: holder, expires: now.Add(duration)}
+	return true, nil
+}
+
+func (m *MemoryLeaseStore) Release(name, holder string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if lease, ok := m.leases[name]; ok && lease.holder == holder {
+		delete(m.leases, name)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package lease_store
+
+import (
+	"time"
+
+	"github.com/quintilesims/layer0/common/db/sqldb"
+	"github.com/quintilesims/layer0/common/waitutils"
+)
+
+const SQL_LEASE_SCHEMA = "lease_store"
+
+var sqlLeaseMigrations = []sqldb.Migration{
+	{
+		Version:     1,
+		Description: "create leases table",
+		Statements: []string{
+			// expires_at is stored as unix nanoseconds, like the DynamoLeaseStore's Expires
+			`CREATE TABLE IF NOT EXISTS leases (
+				lease_name VARCHAR(255) NOT NULL,
+				holder     VARCHAR(255) NOT NULL,
+				expires_at BIGINT NOT NULL,
+				PRIMARY KEY (lease_name))`,
+		},
+	},
+}
+
+type SQLLeaseStore struct {
+	Clock waitutils.Clock
+	db    *sqldb.DB
+}
+
+func NewSQLLeaseStore(db *sqldb.DB) *SQLLeaseStore {
+	return &SQLLeaseStore{
+		Clock: waitutils.RealClock{},
+		db:    db,
+	}
+}
+
+func (s *SQLLeaseStore) Init() error {
+	return sqldb.Migrate(s.db, SQL_LEASE_SCHEMA, sqlLeaseMigrations)
+}
+
+func (s *SQLLeaseStore) Clear() error {
+	_, err := s.db.Exec("DELETE FROM leases")
+	return err
+}
+
+func (s *SQLLeaseStore) Acquire(name, holder string, duration time.Duration) (bool, error) {
+	now := s.Clock.Now()
+	expires := now.Add(duration).UnixNano()
+
+	var acquired bool
+	err := s.db.Transaction(func(tx *sqldb.Tx) error {
+		insert := s.db.InsertIgnore("leases", []string{"lease_name", "holder", "expires_at"}, []string{"lease_name"})
+		if _, err := tx.Exec(insert, name, holder, expires); err != nil {
+			return err
+		}
+
+		// the update only applies if the holder has the lease or the lease has expired.
+		// The rows it affects aren't used: mysql doesn't count rows that are updated to the same values
+		if _, err := tx.Exec("UPDATE leases SET holder = ?, expires_at = ? WHERE lease_name = ? AND (holder = ? OR expires_at <= ?)",
+			holder,
+			expires,
+			name,
+			holder,
+			now.UnixNano()); err != nil {
+			return err
+		}
+
+		var current string
+		if err := tx.QueryRow("SELECT holder FROM leases WHERE lease_name = ?", name).Scan(&current); err != nil {
+			return err
+		}
+
+		acquired = current == holder
+		return nil
+	})
+
+	return acquired, err
+}
+
+func (s *SQLLeaseStore) Release(name, holder string) error {
+	_, err := s.db.Exec("DELETE FROM leases WHERE lease_name = ? AND holder = ?", name, holder)
+	return err
+}
@@ -0,0 +1,40 @@
+package lease_store
+
+import (
+	"testing"
+
+	"github.com/quintilesims/layer0/common/config"
+	"github.com/quintilesims/layer0/common/db/sqldb"
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+func NewTestSQLLeaseStore(t *testing.T) *SQLLeaseStore {
+	driver := config.TestDBDriver()
+	if driver == "" {
+		t.Skipf("Skipping test: %s not set", config.TEST_DB_DRIVER)
+	}
+
+	db, err := sqldb.Open(driver, config.TestDBDataSource())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewSQLLeaseStore(db)
+	if err := store.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Clear(); err != nil {
+		t.Fatalf("Error clearing table: %v", err)
+	}
+
+	return store
+}
+
+func TestSQLLeaseStore(t *testing.T) {
+	clock := &testutils.StubClock{}
+	store := NewTestSQLLeaseStore(t)
+	store.Clock = clock
+
+	testLeaseStoreBehavior(t, store, clock)
+}
@@ -0,0 +1,87 @@
+package sqldb
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// Migration is one step of a store's schema; once applied, a migration must
+// never change, so schema changes are made by appending new migrations
+type Migration struct {
+	Version     int
+	Description string
+	Statements  []string
+}
+
+// Migrate applies the migrations of schema that have not been applied yet, in order.
+// Applied versions are tracked per schema in the schema_migrations table.
+// Note that mysql commits schema changes immediately, so a failed migration
+// may be partially applied there.
+func Migrate(d *DB, schema string, migrations []Migration) error {
+	if _, err := d.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		schema_name VARCHAR(255) NOT NULL,
+		version     INTEGER NOT NULL,
+		description VARCHAR(255) NOT NULL,
+		applied_at  VARCHAR(64) NOT NULL,
+		PRIMARY KEY (schema_name, version))`); err != nil {
+		return err
+	}
+
+	applied, err := appliedVersions(d, schema)
+	if err != nil {
+		return err
+	}
+
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			return fmt.Errorf("Migration %d of schema '%s' has version %d", i+1, schema, migration.Version)
+		}
+
+		if applied[migration.Version] {
+			continue
+		}
+
+		log.Infof("Applying migration %d of schema '%s': %s", migration.Version, schema, migration.Description)
+		if err := d.Transaction(func(tx *Tx) error {
+			for _, statement := range migration.Statements {
+				if _, err := tx.Exec(statement); err != nil {
+					return fmt.Errorf("Migration %d of schema '%s' failed: %v", migration.Version, schema, err)
+				}
+			}
+
+			_, err := tx.Exec("INSERT INTO schema_migrations (schema_name, version, description, applied_at) VALUES (?, ?, ?, ?)",
+				schema,
+				migration.Version,
+				migration.Description,
+				time.Now().UTC().Format(time.RFC3339))
+
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func appliedVersions(d *DB, schema string) (map[int]bool, error) {
+	rows, err := d.Query("SELECT version FROM schema_migrations WHERE schema_name = ?", schema)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]bool{}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+
+		applied[version] = true
+	}
+
+	return applied, rows.Err()
+}
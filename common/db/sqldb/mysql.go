@@ -0,0 +1,4 @@
+package sqldb
+
+// registers the 'mysql' driver, which is pure Go and so is linked into the release binaries
+import _ "github.com/go-sql-driver/mysql"
@@ -0,0 +1,4 @@
+package sqldb
+
+// registers the 'postgres' driver
+import _ "github.com/lib/pq"
@@ -0,0 +1,215 @@
+package sqldb
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// supported database/sql drivers; mysql and sqlite3 are only compiled in
+// when building with the 'mysql' or 'sqlite' build tags
+const (
+	POSTGRES = "postgres"
+	MYSQL    = "mysql"
+	SQLITE   = "sqlite3"
+)
+
+// DB wraps a database connection pool with the dialect of its driver.
+// Queries are written with '?' placeholders and rebound for the driver.
+type DB struct {
+	*sql.DB
+	Driver string
+}
+
+func Open(driver, dataSource string) (*DB, error) {
+	switch driver {
+	case POSTGRES, MYSQL, SQLITE:
+	default:
+		return nil, fmt.Errorf("Unsupported database driver '%s'", driver)
+	}
+
+	if !isRegistered(driver) {
+		return nil, fmt.Errorf("Database driver '%s' was not compiled into this binary", driver)
+	}
+
+	db, err := sql.Open(driver, dataSource)
+	if err != nil {
+		return nil, err
+	}
+
+	// sqlite only allows a single writer at a time
+	if driver == SQLITE {
+		db.SetMaxOpenConns(1)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &DB{DB: db, Driver: driver}, nil
+}
+
+func isRegistered(driver string) bool {
+	for _, d := range sql.Drivers() {
+		if d == driver {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return d.DB.Exec(rebind(d.Driver, query), args...)
+}
+
+func (d *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return d.DB.Query(rebind(d.Driver, query), args...)
+}
+
+func (d *DB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return d.DB.QueryRow(rebind(d.Driver, query), args...)
+}
+
+// Tx wraps a transaction with the dialect of the database it was started on
+type Tx struct {
+	*sql.Tx
+	driver string
+}
+
+func (t *Tx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return t.Tx.Exec(rebind(t.driver, query), args...)
+}
+
+func (t *Tx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return t.Tx.Query(rebind(t.driver, query), args...)
+}
+
+func (t *Tx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return t.Tx.QueryRow(rebind(t.driver, query), args...)
+}
+
+// Transaction runs fn in a transaction, which is committed if fn returns nil
+// and rolled back otherwise
+func (d *DB) Transaction(fn func(tx *Tx) error) error {
+	sqlTx, err := d.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(&Tx{Tx: sqlTx, driver: d.Driver}); err != nil {
+		sqlTx.Rollback()
+		return err
+	}
+
+	return sqlTx.Commit()
+}
+
+// TextType is the column type used for unbounded text
+func (d *DB) TextType() string {
+	if d.Driver == MYSQL {
+		return "MEDIUMTEXT"
+	}
+
+	return "TEXT"
+}
+
+// CreateIndex builds a statement that creates an index if it doesn't already exist;
+// mysql has no 'IF NOT EXISTS' for indexes, so the index is always created there
+func (d *DB) CreateIndex(name, table string, columns ...string) string {
+	ifNotExists := "IF NOT EXISTS "
+	if d.Driver == MYSQL {
+		ifNotExists = ""
+	}
+
+	return fmt.Sprintf("CREATE INDEX %s%s ON %s (%s)", ifNotExists, name, table, strings.Join(columns, ", "))
+}
+
+// Excluded refers to the value a conflicting insert tried to write to column
+func (d *DB) Excluded(column string) string {
+	if d.Driver == MYSQL {
+		return fmt.Sprintf("VALUES(%s)", column)
+	}
+
+	return fmt.Sprintf("excluded.%s", column)
+}
+
+// Upsert builds a statement that inserts a row into table, or applies the
+// assignments to the row that has the same keys. If no assignments are given,
+// every non-key column is overwritten with the inserted value.
+func (d *DB) Upsert(table string, columns, keys []string, assignments ...string) string {
+	if len(assignments) == 0 {
+		for _, column := range columns {
+			if !contains(keys, column) {
+				assignments = append(assignments, fmt.Sprintf("%s = %s", column, d.Excluded(column)))
+			}
+		}
+	}
+
+	insert := insertStatement("INSERT", table, columns)
+	if d.Driver == MYSQL {
+		return fmt.Sprintf("%s ON DUPLICATE KEY UPDATE %s", insert, strings.Join(assignments, ", "))
+	}
+
+	return fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s",
+		insert,
+		strings.Join(keys, ", "),
+		strings.Join(assignments, ", "))
+}
+
+// InsertIgnore builds a statement that inserts a row into table unless
+// a row with the same keys already exists
+func (d *DB) InsertIgnore(table string, columns, keys []string) string {
+	if d.Driver == MYSQL {
+		return insertStatement("INSERT IGNORE", table, columns)
+	}
+
+	return fmt.Sprintf("%s ON CONFLICT (%s) DO NOTHING", insertStatement("INSERT", table, columns), strings.Join(keys, ", "))
+}
+
+func insertStatement(verb, table string, columns []string) string {
+	placeholders := make([]string, len(columns))
+	for i := range columns {
+		placeholders[i] = "?"
+	}
+
+	return fmt.Sprintf("%s INTO %s (%s) VALUES (%s)",
+		verb,
+		table,
+		strings.Join(columns, ", "),
+		strings.Join(placeholders, ", "))
+}
+
+// rebind replaces '?' placeholders with the '$n' placeholders postgres expects
+func rebind(driver, query string) string {
+	if driver != POSTGRES {
+		return query
+	}
+
+	var b bytes.Buffer
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+package sqldb
+
+import (
+	"testing"
+
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+func TestRebind(t *testing.T) {
+	query := "SELECT a FROM t WHERE b = ? AND c = ?"
+
+	testutils.AssertEqual(t, rebind(POSTGRES, query), "SELECT a FROM t WHERE b = $1 AND c = $2")
+	testutils.AssertEqual(t, rebind(MYSQL, query), query)
+	testutils.AssertEqual(t, rebind(SQLITE, query), query)
+}
+
+func TestUpsert(t *testing.T) {
+	cases := map[string]string{
+		POSTGRES: "INSERT INTO t (k, a, b) VALUES (?, ?, ?) ON CONFLICT (k) DO UPDATE SET a = excluded.a, b = excluded.b",
+		SQLITE:   "INSERT INTO t (k, a, b) VALUES (?, ?, ?) ON CONFLICT (k) DO UPDATE SET a = excluded.a, b = excluded.b",
+		MYSQL:    "INSERT INTO t (k, a, b) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE a = VALUES(a), b = VALUES(b)",
+	}
+
+	for driver, expected := range cases {
+		db := &DB{Driver: driver}
+		testutils.AssertEqual(t, db.Upsert("t", []string{"k", "a", "b"}, []string{"k"}), expected)
+	}
+}
+
+func TestUpsertAssignments(t *testing.T) {
+	db := &DB{Driver: POSTGRES}
+	query := db.Upsert("t", []string{"k", "v"}, []string{"k"}, "v = t.v + 1")
+
+	testutils.AssertEqual(t, query, "INSERT INTO t (k, v) VALUES (?, ?) ON CONFLICT (k) DO UPDATE SET v = t.v + 1")
+}
+
+func TestInsertIgnore(t *testing.T) {
+	cases := map[string]string{
+		POSTGRES: "INSERT INTO t (k, v) VALUES (?, ?) ON CONFLICT (k) DO NOTHING",
+		SQLITE:   "INSERT INTO t (k, v) VALUES (?, ?) ON CONFLICT (k) DO NOTHING",
+		MYSQL:    "INSERT IGNORE INTO t (k, v) VALUES (?, ?)",
+	}
+
+	for driver, expected := range cases {
+		db := &DB{Driver: driver}
+		testutils.AssertEqual(t, db.InsertIgnore("t", []string{"k", "v"}, []string{"k"}), expected)
+	}
+}
+
+func TestOpenUnsupportedDriver(t *testing.T) {
+	if _, err := Open("oracle", ""); err == nil {
+		t.Fatal("Error was nil!")
+	}
+}
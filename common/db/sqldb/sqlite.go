@@ -0,0 +1,8 @@
+//go:build sqlite
+// +build sqlite
+
+package sqldb
+
+// registers the 'sqlite3' driver, which requires cgo. The released binaries are built without cgo,
+// so sqlite is only used by the sql store tests, e.g. 'make sqltest-sqlite' in common
+import _ "github.com/mattn/go-sqlite3"
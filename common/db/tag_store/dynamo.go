@@ -6,13 +6,19 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/guregu/dynamo"
+	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/selector"
 )
 
+// the number of times a tag write is attempted when it races with another writer
+const MAX_TAG_WRITE_ATTEMPTS = 5
+
 type DynamoTagSchema struct {
 	EntityType string
 	EntityID   string
 	Tags       map[string]string
+	Version    int64
 }
 
 func (s DynamoTagSchema) ToTags() models.Tags {
@@ -69,6 +75,12 @@ func (d *DynamoTagStore) Clear() error {
 }
 
 func (d *DynamoTagStore) Delete(entityType, entityID, key string) error {
+	return d.retryOnConflict(func() error {
+		return d.deleteKey(entityType, entityID, key)
+	})
+}
+
+func (d *DynamoTagStore) deleteKey(entityType, entityID, key string) error {
 	schema, err := d.selectByTypeAndID(entityType, entityID)
 	if err != nil {
 		return err
@@ -80,19 +92,27 @@ func (d *DynamoTagStore) Delete(entityType, entityID, key string) error {
 	}
 
 	delete(schema.Tags, key)
+	expr, args := versionCondition(schema.Version)
 
 	// update entry if it still has tags
 	if len(schema.Tags) > 0 {
-		return d.table.Update("EntityType", schema.EntityType).
+		err := d.table.Update("EntityType", schema.EntityType).
 			Range("EntityID", schema.EntityID).
 			Set("Tags", schema.Tags).
+			Set("Version", schema.Version+1).
+			If(expr, args...).
 			Run()
+
+		return conflictError(err, entityType, entityID)
 	}
 
 	// delete the entire entry if this was the last tag
-	return d.table.Delete("EntityType", schema.EntityType).
+	err = d.table.Delete("EntityType", schema.EntityType).
 		Range("EntityID", schema.EntityID).
+		If(expr, args...).
 		Run()
+
+	return conflictError(err, entityType, entityID)
 }
 
 func (d *DynamoTagStore) Insert(tag models.Tag) error {
@@ -100,11 +120,14 @@ func (d *DynamoTagStore) Insert(tag models.Tag) error {
 		EntityType: tag.EntityType,
 		EntityID:   tag.EntityID,
 		Tags:       map[string]string{tag.Key: tag.Value},
+		Version:    1,
 	}
 
 	if err := d.table.Put(schema).If("attribute_not_exists(EntityType)").Run(); err != nil {
-		if err, ok := err.(awserr.Error); ok && err.Code() == "ConditionalCheckFailedException" {
-			return d.insertKey(tag)
+		if isConditionalCheckFailed(err) {
+			return d.retryOnConflict(func() error {
+				return d.insertKey(tag)
+			})
 		}
 
 		return err
@@ -120,10 +143,121 @@ func (d *DynamoTagStore) insertKey(tag models.Tag) error {
 	}
 
 	schema.Tags[tag.Key] = tag.Value
-	return d.table.Update("EntityType", tag.EntityType).
+	expr, args := versionCondition(schema.Version)
+
+	err = d.table.Update("EntityType", tag.EntityType).
 		Range("EntityID", tag.EntityID).
 		Set("Tags", schema.Tags).
+		Set("Version", schema.Version+1).
+		If(expr, args...).
+		Run()
+
+	return conflictError(err, tag.EntityType, tag.EntityID)
+}
+
+func (d *DynamoTagStore) SelectVersion(entityType, entityID string) (int64, error) {
+	schema, err := d.selectByTypeAndID(entityType, entityID)
+	if err != nil {
+		if err.Error() == "dynamo: no item found" {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	return schema.Version, nil
+}
+
+func (d *DynamoTagStore) IncrementVersion(entityType, entityID string, expected int64) (int64, error) {
+	if entityType == "" {
+		return 0, fmt.Errorf("EntityType is required")
+	}
+
+	if entityID == "" {
+		return 0, fmt.Errorf("EntityID is required")
+	}
+
+	update := d.table.Update("EntityType", entityType).
+		Range("EntityID", entityID)
+
+	if expected == AnyVersion {
+		update = update.Add("Version", 1)
+	} else {
+		expr, args := versionCondition(expected)
+		update = update.Set("Version", expected+1).If(expr, args...)
+	}
+
+	var schema DynamoTagSchema
+	if err := update.Value(&schema); err != nil {
+		return 0, conflictError(err, entityType, entityID)
+	}
+
+	return schema.Version, nil
+}
+
+func (d *DynamoTagStore) RevertVersion(entityType, entityID string, version int64) error {
+	if entityType == "" {
+		return fmt.Errorf("EntityType is required")
+	}
+
+	if entityID == "" {
+		return fmt.Errorf("EntityID is required")
+	}
+
+	expr, args := versionCondition(version)
+	err := d.table.Update("EntityType", entityType).
+		Range("EntityID", entityID).
+		Set("Version", version-1).
+		If(expr, args...).
 		Run()
+
+	// another request modified the entity since, so its version is kept
+	if isConditionalCheckFailed(err) {
+		return nil
+	}
+
+	return err
+}
+
+// retryOnConflict re-runs a read-modify-write of an entity's tags if another
+// writer updated the same entity between the read and the conditional write
+func (d *DynamoTagStore) retryOnConflict(fn func() error) error {
+	var err error
+	for i := 0; i < MAX_TAG_WRITE_ATTEMPTS; i++ {
+		err = fn()
+		if serr, ok := err.(*errors.ServerError); !ok || serr.Code != errors.EntityConflict {
+			return err
+		}
+	}
+
+	return err
+}
+
+// versionCondition builds a condition expression that only succeeds if the
+// stored version matches the given version. Entries written before versions
+// were introduced have no Version attribute, which is treated as version 0.
+func versionCondition(version int64) (string, []interface{}) {
+	if version == 0 {
+		return "attribute_not_exists('Version') OR 'Version' = ?", []interface{}{version}
+	}
+
+	return "'Version' = ?", []interface{}{version}
+}
+
+func isConditionalCheckFailed(err error) bool {
+	if err, ok := err.(awserr.Error); ok && err.Code() == "ConditionalCheckFailedException" {
+		return true
+	}
+
+	return false
+}
+
+func conflictError(err error, entityType, entityID string) error {
+	if isConditionalCheckFailed(err) {
+		return errors.Newf(errors.EntityConflict, "%s '%s' was modified by another request", entityType, entityID)
+	}
+
+	return err
 }
 
 func (d *DynamoTagStore) SelectByTypeAndID(entityType, entityID string) (models.Tags, error) {
@@ -181,6 +315,28 @@ func (d *DynamoTagStore) SelectByType(entityType string) (models.Tags, error) {
 	return tags, nil
 }
 
+// SelectBySelector evaluates the selector against each entity in the entityType
+// partition, since the tags map of an entity can't be indexed
+func (d *DynamoTagStore) SelectBySelector(entityType string, sel selector.Selector) (models.Tags, error) {
+	schemas, err := d.selectByType(entityType)
+	if err != nil {
+		if err.Error() == "dynamo: no item found" {
+			return models.Tags{}, nil
+		}
+
+		return nil, err
+	}
+
+	tags := models.Tags{}
+	for _, schema := range schemas {
+		if sel.Matches(schema.Tags) {
+			tags = append(tags, schema.ToTags()...)
+		}
+	}
+
+	return tags, nil
+}
+
 func (d *DynamoTagStore) selectByType(entityType string) ([]*DynamoTagSchema, error) {
 	var schemas []*DynamoTagSchema
 
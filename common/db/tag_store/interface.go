@@ -2,12 +2,23 @@ package tag_store
 
 import (
 	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/selector"
 )
 
+// AnyVersion can be passed to IncrementVersion to skip the version check
+const AnyVersion int64 = -1
+
 type TagStore interface {
 	Init() error
 	Delete(entityType, entityID, key string) error
 	Insert(tag models.Tag) error
 	SelectByType(entityType string) (models.Tags, error)
 	SelectByTypeAndID(entityType, entityID string) (models.Tags, error)
+	// SelectBySelector returns every tag of the entities of entityType that match the selector
+	SelectBySelector(entityType string, sel selector.Selector) (models.Tags, error)
+	SelectVersion(entityType, entityID string) (int64, error)
+	IncrementVersion(entityType, entityID string, expected int64) (int64, error)
+	// RevertVersion undoes the IncrementVersion call that returned version.
+	// It does nothing if the entity was modified again since then
+	RevertVersion(entityType, entityID string, version int64) error
 }
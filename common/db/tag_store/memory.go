@@ -1,16 +1,20 @@
 package tag_store
 
 import (
+	"github.com/quintilesims/layer0/common/errors"
 	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/selector"
 )
 
 type MemoryTagStore struct {
-	tags models.Tags
+	tags     models.Tags
+	versions map[string]int64
 }
 
 func NewMemoryTagStore() *MemoryTagStore {
 	return &MemoryTagStore{
-		tags: models.Tags{},
+		tags:     models.Tags{},
+		versions: map[string]int64{},
 	}
 }
 
@@ -23,6 +27,7 @@ func (m *MemoryTagStore) Delete(entityType, entityID, key string) error {
 		tag := m.tags[i]
 		if tag.EntityType == entityType && tag.EntityID == entityID && tag.Key == key {
 			m.tags = append(m.tags[:i], m.tags[i+1:]...)
+			m.versions[versionKey(entityType, entityID)]++
 			i--
 		}
 	}
@@ -31,6 +36,16 @@ func (m *MemoryTagStore) Delete(entityType, entityID, key string) error {
 }
 
 func (m *MemoryTagStore) Insert(tag models.Tag) error {
+	m.versions[versionKey(tag.EntityType, tag.EntityID)]++
+
+	// like the other stores, an entity has one value per key
+	for i, t := range m.tags {
+		if t.EntityType == tag.EntityType && t.EntityID == tag.EntityID && t.Key == tag.Key {
+			m.tags[i] = tag
+			return nil
+		}
+	}
+
 	m.tags = append(m.tags, tag)
 	return nil
 }
@@ -42,3 +57,34 @@ func (m *MemoryTagStore) SelectByType(entityType string) (models.Tags, error) {
 func (m *MemoryTagStore) SelectByTypeAndID(entityType, entityID string) (models.Tags, error) {
 	return m.tags.WithType(entityType).WithID(entityID), nil
 }
+
+func (m *MemoryTagStore) SelectBySelector(entityType string, sel selector.Selector) (models.Tags, error) {
+	return sel.Filter(m.tags.WithType(entityType)), nil
+}
+
+func (m *MemoryTagStore) SelectVersion(entityType, entityID string) (int64, error) {
+	return m.versions[versionKey(entityType, entityID)], nil
+}
+
+func (m *MemoryTagStore) IncrementVersion(entityType, entityID string, expected int64) (int64, error) {
+	key := versionKey(entityType, entityID)
+	if expected != AnyVersion && m.versions[key] != expected {
+		return 0, errors.Newf(errors.EntityConflict, "%s '%s' was modified by another request", entityType, entityID)
+	}
+
+	m.versions[key]++
+	return m.versions[key], nil
+}
+
+func (m *MemoryTagStore) RevertVersion(entityType, entityID string, version int64) error {
+	key := versionKey(entityType, entityID)
+	if m.versions[key] == version {
+		m.versions[key]--
+	}
+
+	return nil
+}
+
+func versionKey(entityType, entityID string) string {
+	return entityType + "/" + entityID
+}
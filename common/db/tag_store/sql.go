@@ -0,0 +1,306 @@
+package tag_store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/quintilesims/layer0/common/db/sqldb"
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/selector"
+)
+
+const SQL_TAG_SCHEMA = "tag_store"
+
+func sqlTagMigrations(db *sqldb.DB) []sqldb.Migration {
+	return []sqldb.Migration{
+		{
+			Version:     1,
+			Description: "create tags and tag_versions tables",
+			Statements: []string{
+				`CREATE TABLE IF NOT EXISTS tags (
+					entity_type VARCHAR(255) NOT NULL,
+					entity_id   VARCHAR(255) NOT NULL,
+					tag_key     VARCHAR(255) NOT NULL,
+					tag_value   ` + db.TextType() + ` NOT NULL,
+					PRIMARY KEY (entity_type, entity_id, tag_key))`,
+				`CREATE TABLE IF NOT EXISTS tag_versions (
+					entity_type VARCHAR(255) NOT NULL,
+					entity_id   VARCHAR(255) NOT NULL,
+					version     BIGINT NOT NULL,
+					PRIMARY KEY (entity_type, entity_id))`,
+			},
+		},
+	}
+}
+
+// SQLTagStore keeps one value per tag key, like the DynamoTagStore.
+// Unlike the DynamoTagStore, an entity's version is kept after its last tag is deleted.
+type SQLTagStore struct {
+	db *sqldb.DB
+}
+
+func NewSQLTagStore(db *sqldb.DB) *SQLTagStore {
+	return &SQLTagStore{
+		db: db,
+	}
+}
+
+func (s *SQLTagStore) Init() error {
+	return sqldb.Migrate(s.db, SQL_TAG_SCHEMA, sqlTagMigrations(s.db))
+}
+
+func (s *SQLTagStore) Clear() error {
+	return s.db.Transaction(func(tx *sqldb.Tx) error {
+		if _, err := tx.Exec("DELETE FROM tags"); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec("DELETE FROM tag_versions")
+		return err
+	})
+}
+
+func (s *SQLTagStore) Delete(entityType, entityID, key string) error {
+	if err := validateEntity(entityType, entityID); err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *sqldb.Tx) error {
+		result, err := tx.Exec("DELETE FROM tags WHERE entity_type = ? AND entity_id = ? AND tag_key = ?",
+			entityType,
+			entityID,
+			key)
+		if err != nil {
+			return err
+		}
+
+		// do nothing if key doesn't exist
+		deleted, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if deleted == 0 {
+			return nil
+		}
+
+		return s.bumpVersion(tx, entityType, entityID)
+	})
+}
+
+func (s *SQLTagStore) Insert(tag models.Tag) error {
+	if err := validateEntity(tag.EntityType, tag.EntityID); err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *sqldb.Tx) error {
+		query := s.db.Upsert("tags",
+			[]string{"entity_type", "entity_id", "tag_key", "tag_value"},
+			[]string{"entity_type", "entity_id", "tag_key"})
+
+		if _, err := tx.Exec(query, tag.EntityType, tag.EntityID, tag.Key, tag.Value); err != nil {
+			return err
+		}
+
+		return s.bumpVersion(tx, tag.EntityType, tag.EntityID)
+	})
+}
+
+func (s *SQLTagStore) bumpVersion(tx *sqldb.Tx, entityType, entityID string) error {
+	query := s.db.Upsert("tag_versions",
+		[]string{"entity_type", "entity_id", "version"},
+		[]string{"entity_type", "entity_id"},
+		"version = tag_versions.version + 1")
+
+	_, err := tx.Exec(query, entityType, entityID, 1)
+	return err
+}
+
+func (s *SQLTagStore) SelectVersion(entityType, entityID string) (int64, error) {
+	var version int64
+	if err := s.db.QueryRow("SELECT version FROM tag_versions WHERE entity_type = ? AND entity_id = ?",
+		entityType,
+		entityID).Scan(&version); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	return version, nil
+}
+
+func (s *SQLTagStore) IncrementVersion(entityType, entityID string, expected int64) (int64, error) {
+	if err := validateEntity(entityType, entityID); err != nil {
+		return 0, err
+	}
+
+	var version int64
+	err := s.db.Transaction(func(tx *sqldb.Tx) error {
+		if expected == AnyVersion {
+			if err := s.bumpVersion(tx, entityType, entityID); err != nil {
+				return err
+			}
+
+			return tx.QueryRow("SELECT version FROM tag_versions WHERE entity_type = ? AND entity_id = ?",
+				entityType,
+				entityID).Scan(&version)
+		}
+
+		var result sql.Result
+		var err error
+		if expected == 0 {
+			query := s.db.InsertIgnore("tag_versions",
+				[]string{"entity_type", "entity_id", "version"},
+				[]string{"entity_type", "entity_id"})
+
+			result, err = tx.Exec(query, entityType, entityID, 1)
+		} else {
+			result, err = tx.Exec("UPDATE tag_versions SET version = ? WHERE entity_type = ? AND entity_id = ? AND version = ?",
+				expected+1,
+				entityType,
+				entityID,
+				expected)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		updated, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if updated == 0 {
+			return errors.Newf(errors.EntityConflict, "%s '%s' was modified by another request", entityType, entityID)
+		}
+
+		version = expected + 1
+		return nil
+	})
+
+	return version, err
+}
+
+func (s *SQLTagStore) RevertVersion(entityType, entityID string, version int64) error {
+	if err := validateEntity(entityType, entityID); err != nil {
+		return err
+	}
+
+	// entities without a row are at version 0, and IncrementVersion only inserts the row for them
+	if version == 1 {
+		_, err := s.db.Exec("DELETE FROM tag_versions WHERE entity_type = ? AND entity_id = ? AND version = ?",
+			entityType,
+			entityID,
+			version)
+
+		return err
+	}
+
+	_, err := s.db.Exec("UPDATE tag_versions SET version = ? WHERE entity_type = ? AND entity_id = ? AND version = ?",
+		version-1,
+		entityType,
+		entityID,
+		version)
+
+	return err
+}
+
+func (s *SQLTagStore) SelectByType(entityType string) (models.Tags, error) {
+	return s.selectTags("SELECT entity_type, entity_id, tag_key, tag_value FROM tags WHERE entity_type = ? ORDER BY entity_id, tag_key",
+		entityType)
+}
+
+func (s *SQLTagStore) SelectByTypeAndID(entityType, entityID string) (models.Tags, error) {
+	if err := validateEntity(entityType, entityID); err != nil {
+		return nil, err
+	}
+
+	return s.selectTags("SELECT entity_type, entity_id, tag_key, tag_value FROM tags WHERE entity_type = ? AND entity_id = ? ORDER BY tag_key",
+		entityType,
+		entityID)
+}
+
+func (s *SQLTagStore) SelectBySelector(entityType string, sel selector.Selector) (models.Tags, error) {
+	query := "SELECT t.entity_type, t.entity_id, t.tag_key, t.tag_value FROM tags t WHERE t.entity_type = ?"
+	args := []interface{}{entityType}
+
+	for _, requirement := range sel {
+		condition, conditionArgs := requirementCondition(requirement)
+		query += " AND " + condition
+		args = append(args, conditionArgs...)
+	}
+
+	return s.selectTags(query+" ORDER BY t.entity_id, t.tag_key", args...)
+}
+
+// requirementCondition builds a condition on the entity of the tag row 't'
+// by checking for a matching tag of the same entity
+func requirementCondition(requirement selector.Requirement) (string, []interface{}) {
+	subquery := "SELECT 1 FROM tags s WHERE s.entity_type = t.entity_type AND s.entity_id = t.entity_id AND s.tag_key = ?"
+	args := []interface{}{requirement.Key}
+
+	switch requirement.Operator {
+	case selector.Equals, selector.NotEquals, selector.In, selector.NotIn:
+		// no value can match an empty list
+		if len(requirement.Values) == 0 {
+			if requirement.Operator == selector.NotEquals || requirement.Operator == selector.NotIn {
+				return "1 = 1", nil
+			}
+
+			return "1 = 0", nil
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(requirement.Values)), ", ")
+		subquery += " AND s.tag_value IN (" + placeholders + ")"
+		for _, value := range requirement.Values {
+			args = append(args, value)
+		}
+	case selector.Exists, selector.DoesNotExist:
+	default:
+		return "1 = 0", nil
+	}
+
+	switch requirement.Operator {
+	case selector.NotEquals, selector.NotIn, selector.DoesNotExist:
+		return "NOT EXISTS (" + subquery + ")", args
+	default:
+		return "EXISTS (" + subquery + ")", args
+	}
+}
+
+func (s *SQLTagStore) selectTags(query string, args ...interface{}) (models.Tags, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := models.Tags{}
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.EntityType, &tag.EntityID, &tag.Key, &tag.Value); err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+func validateEntity(entityType, entityID string) error {
+	if entityType == "" {
+		return fmt.Errorf("EntityType is required")
+	}
+
+	if entityID == "" {
+		return fmt.Errorf("EntityID is required")
+	}
+
+	return nil
+}
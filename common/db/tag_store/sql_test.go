@@ -0,0 +1,37 @@
+package tag_store
+
+import (
+	"testing"
+
+	"github.com/quintilesims/layer0/common/config"
+	"github.com/quintilesims/layer0/common/db/sqldb"
+)
+
+func NewTestSQLTagStore(t *testing.T) *SQLTagStore {
+	driver := config.TestDBDriver()
+	if driver == "" {
+		t.Skipf("Skipping test: %s not set", config.TEST_DB_DRIVER)
+	}
+
+	db, err := sqldb.Open(driver, config.TestDBDataSource())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewSQLTagStore(db)
+	if err := store.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Clear(); err != nil {
+		t.Fatalf("Error clearing table: %v", err)
+	}
+
+	return store
+}
+
+func TestSQLTagStore(t *testing.T) {
+	testTagStoreBehavior(t, func(t *testing.T) TagStore {
+		return NewTestSQLTagStore(t)
+	})
+}
@@ -0,0 +1,433 @@
+package tag_store
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/selector"
+	"github.com/stretchr/testify/assert"
+)
+
+var TestTags = models.Tags{
+	{EntityID: "d1", EntityType: "deploy", Key: "name", Value: "dpl"},
+	{EntityID: "d1", EntityType: "deploy", Key: "version", Value: "1"},
+	{EntityID: "d2", EntityType: "deploy", Key: "name", Value: "dpl"},
+	{EntityID: "d2", EntityType: "deploy", Key: "version", Value: "2"},
+
+	{EntityID: "e1", EntityType: "environment", Key: "name", Value: "e1"},
+	{EntityID: "e2", EntityType: "environment", Key: "name", Value: "e2"},
+
+	{EntityID: "l1", EntityType: "load_balancer", Key: "name", Value: "lb1"},
+	{EntityID: "l1", EntityType: "load_balancer", Key: "environment_id", Value: "e1"},
+	{EntityID: "l2", EntityType: "load_balancer", Key: "name", Value: "lb2"},
+	{EntityID: "l2", EntityType: "load_balancer", Key: "environment_id", Value: "e2"},
+
+	{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc1"},
+	{EntityID: "s1", EntityType: "service", Key: "environment_id", Value: "e1"},
+	{EntityID: "s2", EntityType: "service", Key: "name", Value: "svc2"},
+	{EntityID: "s2", EntityType: "service", Key: "environment_id", Value: "e2"},
+
+	{EntityID: "t1", EntityType: "task", Key: "name", Value: "tsk1"},
+	{EntityID: "t1", EntityType: "task", Key: "environment_id", Value: "e1"},
+	{EntityID: "t2", EntityType: "task", Key: "name", Value: "tsk2"},
+	{EntityID: "t2", EntityType: "task", Key: "environment_id", Value: "e2"},
+}
+
+// testTagStoreBehavior runs the same checks against each TagStore implementation;
+// newStore must return an empty store
+func testTagStoreBehavior(t *testing.T, newStore func(t *testing.T) TagStore) {
+	tests := map[string]func(*testing.T, TagStore){
+		"Insert":                       testTagStoreInsert,
+		"InsertOverwritesKey":          testTagStoreInsertOverwritesKey,
+		"Delete":                       testTagStoreDelete,
+		"SelectByTypeAndID":            testTagStoreSelectByTypeAndID,
+		"SelectByType":                 testTagStoreSelectByType,
+		"IncrementVersion":             testTagStoreIncrementVersion,
+		"IncrementVersionAnyVersion":   testTagStoreIncrementVersionAnyVersion,
+		"IncrementVersionNewEntity":    testTagStoreIncrementVersionNewEntity,
+		"WritesIncrementVersion":       testTagStoreWritesIncrementVersion,
+		"RevertVersion":                testTagStoreRevertVersion,
+		"RevertVersionNewEntity":       testTagStoreRevertVersionNewEntity,
+		"DeleteMissingKeyKeepsVersion": testTagStoreDeleteMissingKeyKeepsVersion,
+		"SelectBySelector":             testTagStoreSelectBySelector,
+	}
+
+	for name, fn := range tests {
+		fn := fn
+		t.Run(name, func(t *testing.T) {
+			fn(t, newStore(t))
+		})
+	}
+}
+
+func TestMemoryTagStore(t *testing.T) {
+	testTagStoreBehavior(t, func(t *testing.T) TagStore {
+		return NewMemoryTagStore()
+	})
+}
+
+func insertTags(t *testing.T, store TagStore, tags ...models.Tag) {
+	for _, tag := range tags {
+		if err := store.Insert(tag); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func testTagStoreInsert(t *testing.T, store TagStore) {
+	tags := []models.Tag{
+		{EntityID: "e1", EntityType: "environment", Key: "name", Value: "env1"},
+		{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc1"},
+		{EntityID: "s1", EntityType: "service", Key: "environment_id", Value: "e1"},
+		{EntityID: "d1", EntityType: "deploy", Key: "name", Value: "dpl1"},
+		{EntityID: "d1", EntityType: "deploy", Key: "version", Value: "1"},
+	}
+
+	insertTags(t, store, tags...)
+
+	result, err := store.SelectByTypeAndID("service", "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, result, 2)
+	assert.Contains(t, result, tags[1])
+	assert.Contains(t, result, tags[2])
+}
+
+func testTagStoreInsertOverwritesKey(t *testing.T, store TagStore) {
+	insertTags(t, store,
+		models.Tag{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc1"},
+		models.Tag{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc2"})
+
+	result, err := store.SelectByTypeAndID("service", "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, models.Tags{{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc2"}}, result)
+}
+
+func testTagStoreDelete(t *testing.T, store TagStore) {
+	tags := []models.Tag{
+		{EntityID: "d1", EntityType: "deploy", Key: "name", Value: "dpl1"},
+		{EntityID: "d1", EntityType: "deploy", Key: "version", Value: "1"},
+	}
+
+	insertTags(t, store, tags...)
+
+	if err := store.Delete(tags[0].EntityType, tags[0].EntityID, tags[0].Key); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := store.SelectByTypeAndID(tags[1].EntityType, tags[1].EntityID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, models.Tags{tags[1]}, result)
+}
+
+func testTagStoreSelectByTypeAndID(t *testing.T, store TagStore) {
+	insertTags(t, store, TestTags...)
+
+	cases := []struct {
+		EntityType string
+		EntityID   string
+		Expected   models.Tags
+	}{
+		{
+			EntityType: "invalid",
+			EntityID:   "invalid",
+			Expected:   models.Tags{},
+		},
+		{
+			EntityType: "deploy",
+			EntityID:   "d1",
+			Expected:   TestTags[0:2],
+		},
+		{
+			EntityType: "environment",
+			EntityID:   "e1",
+			Expected:   TestTags[4:5],
+		},
+		{
+			EntityType: "load_balancer",
+			EntityID:   "l1",
+			Expected:   TestTags[6:8],
+		},
+		{
+			EntityType: "service",
+			EntityID:   "s1",
+			Expected:   TestTags[10:12],
+		},
+		{
+			EntityType: "task",
+			EntityID:   "t1",
+			Expected:   TestTags[14:16],
+		},
+	}
+
+	for query, c := range cases {
+		results, err := store.SelectByTypeAndID(c.EntityType, c.EntityID)
+		if err != nil {
+			t.Fatalf("Query %d: %v", query, err)
+		}
+
+		assert.Len(t, results, len(c.Expected))
+		for _, e := range c.Expected {
+			assert.Contains(t, results, e)
+		}
+	}
+}
+
+func testTagStoreSelectByType(t *testing.T, store TagStore) {
+	insertTags(t, store, TestTags...)
+
+	cases := []struct {
+		EntityType string
+		Expected   models.Tags
+	}{
+		{
+			EntityType: "invalid",
+			Expected:   models.Tags{},
+		},
+		{
+			EntityType: "deploy",
+			Expected:   TestTags[0:4],
+		},
+		{
+			EntityType: "environment",
+			Expected:   TestTags[4:6],
+		},
+		{
+			EntityType: "load_balancer",
+			Expected:   TestTags[6:10],
+		},
+		{
+			EntityType: "service",
+			Expected:   TestTags[10:14],
+		},
+		{
+			EntityType: "task",
+			Expected:   TestTags[14:18],
+		},
+	}
+
+	for query, c := range cases {
+		results, err := store.SelectByType(c.EntityType)
+		if err != nil {
+			t.Fatalf("Query %d: %v", query, err)
+		}
+
+		assert.Len(t, results, len(c.Expected))
+		for _, e := range c.Expected {
+			assert.Contains(t, results, e)
+		}
+	}
+}
+
+func testTagStoreIncrementVersion(t *testing.T, store TagStore) {
+	insertTags(t, store, models.Tag{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc1"})
+
+	version, err := store.SelectVersion("service", "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newVersion, err := store.IncrementVersion("service", "s1", version)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, version+1, newVersion)
+
+	if _, err := store.IncrementVersion("service", "s1", version); err == nil {
+		t.Fatal("Error was nil!")
+	}
+}
+
+func testTagStoreIncrementVersionAnyVersion(t *testing.T, store TagStore) {
+	insertTags(t, store, models.Tag{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc1"})
+
+	version, err := store.SelectVersion("service", "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newVersion, err := store.IncrementVersion("service", "s1", AnyVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, version+1, newVersion)
+}
+
+func testTagStoreIncrementVersionNewEntity(t *testing.T, store TagStore) {
+	version, err := store.SelectVersion("service", "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, int64(0), version)
+
+	newVersion, err := store.IncrementVersion("service", "s1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, int64(1), newVersion)
+
+	if _, err := store.IncrementVersion("service", "s1", 0); err == nil {
+		t.Fatal("Error was nil!")
+	}
+}
+
+func testTagStoreRevertVersion(t *testing.T, store TagStore) {
+	insertTags(t, store, models.Tag{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc1"})
+
+	version, err := store.SelectVersion("service", "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newVersion, err := store.IncrementVersion("service", "s1", version)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.RevertVersion("service", "s1", newVersion); err != nil {
+		t.Fatal(err)
+	}
+
+	// the same version can be used again after the revert
+	if _, err := store.IncrementVersion("service", "s1", version); err != nil {
+		t.Fatal(err)
+	}
+
+	// reverting an older version doesn't undo a later change
+	if _, err := store.IncrementVersion("service", "s1", AnyVersion); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.RevertVersion("service", "s1", newVersion); err != nil {
+		t.Fatal(err)
+	}
+
+	current, err := store.SelectVersion("service", "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, version+2, current)
+}
+
+func testTagStoreRevertVersionNewEntity(t *testing.T, store TagStore) {
+	newVersion, err := store.IncrementVersion("service", "s1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.RevertVersion("service", "s1", newVersion); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.IncrementVersion("service", "s1", 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testTagStoreWritesIncrementVersion(t *testing.T, store TagStore) {
+	selectVersion := func() int64 {
+		version, err := store.SelectVersion("service", "s1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return version
+	}
+
+	insertTags(t, store,
+		models.Tag{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc1"},
+		models.Tag{EntityID: "s1", EntityType: "service", Key: "environment_id", Value: "e1"})
+
+	before := selectVersion()
+	if err := store.Delete("service", "s1", "environment_id"); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, before+1, selectVersion())
+}
+
+func testTagStoreDeleteMissingKeyKeepsVersion(t *testing.T, store TagStore) {
+	insertTags(t, store, models.Tag{EntityID: "s1", EntityType: "service", Key: "name", Value: "svc1"})
+
+	before, err := store.SelectVersion("service", "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Delete("service", "s1", "missing"); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := store.SelectVersion("service", "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, before, after)
+}
+
+func testTagStoreSelectBySelector(t *testing.T, store TagStore) {
+	insertTags(t, store, TestTags...)
+	insertTags(t, store,
+		models.Tag{EntityID: "s1", EntityType: "service", Key: "team", Value: "payments"},
+		models.Tag{EntityID: "s2", EntityType: "service", Key: "team", Value: "search"},
+		models.Tag{EntityID: "s3", EntityType: "service", Key: "name", Value: "svc3"})
+
+	cases := map[string][]string{
+		"":                          {"s1", "s2", "s3"},
+		"team=payments":             {"s1"},
+		"team!=payments":            {"s2", "s3"},
+		"team in (payments,search)": {"s1", "s2"},
+		"team notin (search)":       {"s1", "s3"},
+		"team":                      {"s1", "s2"},
+		"!team":                     {"s3"},
+		"team,environment_id=e2":    {"s2"},
+		"team=other":                {},
+	}
+
+	for expression, expected := range cases {
+		sel, err := selector.Parse(expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		results, err := store.SelectBySelector("service", sel)
+		if err != nil {
+			t.Fatalf("%s: %v", expression, err)
+		}
+
+		ids := []string{}
+		for _, ewt := range results.GroupByEntity() {
+			ids = append(ids, ewt.EntityID)
+		}
+
+		sort.Strings(ids)
+		assert.Equal(t, expected, ids, expression)
+
+		// every tag of a matching entity is returned
+		expectedTags := models.Tags{}
+		for _, id := range expected {
+			for _, tag := range TestTags.WithType("service").WithID(id) {
+				expectedTags = append(expectedTags, tag)
+			}
+		}
+
+		for _, tag := range expectedTags {
+			assert.Contains(t, results, tag, expression)
+		}
+	}
+}
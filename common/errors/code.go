@@ -32,4 +32,12 @@ const (
 	LoadBalancerAttributeNotFound
 	ServiceDoesNotExist
 	TaskDoesNotExist
+	EntityConflict
+	JobNotCancellable
+	JobNotRetryable
+	InvalidWorkflow
+	InvalidPlacementStrategy
+	InvalidScalerSettings
+	InvalidSelector
+	InvalidBackup
 )
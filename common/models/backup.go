@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// Backup is a versioned archive of the layer0 state that isn't kept in AWS:
+// the tags that name and link every entity, the current jobs, and the api config.
+// The config is export-only: it comes from the api's environment, so restoring a backup
+// only checks that its prefix matches the api's
+type Backup struct {
+	Version    int       `json:"version"`
+	Created    time.Time `json:"created"`
+	APIVersion string    `json:"api_version"`
+	Config     APIConfig `json:"config"`
+	Tags       Tags      `json:"tags"`
+	Jobs       []*Job    `json:"jobs"`
+}
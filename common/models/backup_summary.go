@@ -0,0 +1,13 @@
+package models
+
+import (
+	"time"
+)
+
+type BackupSummary struct {
+	Key      string    `json:"key"`
+	Version  int       `json:"version"`
+	Created  time.Time `json:"created"`
+	TagCount int       `json:"tag_count"`
+	JobCount int       `json:"job_count"`
+}
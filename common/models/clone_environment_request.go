@@ -0,0 +1,14 @@
+package models
+
+// CloneEnvironmentRequest is the request of a CloneEnvironment job
+type CloneEnvironmentRequest struct {
+	SourceEnvironmentID string `json:"source_environment_id"`
+	EnvironmentName     string `json:"environment_name"`
+	// LoadBalancerNames and ServiceNames map the names of the source's load balancers
+	// and services to the names of their copies; entities that aren't listed keep their names
+	LoadBalancerNames map[string]string `json:"load_balancer_names"`
+	ServiceNames      map[string]string `json:"service_names"`
+	// Scale maps the names of the source's services to the desired count of their copies;
+	// services that aren't listed keep their desired count
+	Scale map[string]int64 `json:"scale"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// ConsistencyReport lists the differences between the tagged entities
+// and the AWS resources that back them
+type ConsistencyReport struct {
+	EntitiesChecked int                `json:"entities_checked"`
+	Issues          []ConsistencyIssue `json:"issues"`
+}
+
+type ConsistencyIssue struct {
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	Message    string `json:"message"`
+}
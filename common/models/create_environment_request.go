@@ -1,10 +1,12 @@
 package models
 
 type CreateEnvironmentRequest struct {
-	EnvironmentName  string `json:"environment_name"`
-	InstanceSize     string `json:"instance_size"`
-	UserDataTemplate []byte `json:"user_data_template"`
-	MinClusterCount  int    `json:"min_cluster_count"`
-	OperatingSystem  string `json:"operating_system"`
-	AMIID            string `json:"ami_id"`
+	EnvironmentName   string         `json:"environment_name"`
+	InstanceSize      string         `json:"instance_size"`
+	UserDataTemplate  []byte         `json:"user_data_template"`
+	MinClusterCount   int            `json:"min_cluster_count"`
+	OperatingSystem   string         `json:"operating_system"`
+	AMIID             string         `json:"ami_id"`
+	PlacementStrategy string         `json:"placement_strategy"`
+	ScalerSettings    ScalerSettings `json:"scaler_settings"`
 }
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"encoding/json"
+)
+
+type CreateWorkflowRequest struct {
+	// OnFailure is either "halt" (the default) or "continue"
+	OnFailure string         `json:"on_failure"`
+	Nodes     []WorkflowNode `json:"nodes"`
+}
+
+// WorkflowNode is a child job of a workflow. Strings in the request may reference
+// the meta of a node it depends on as ${node.key}, e.g. ${env.environment_id}
+type WorkflowNode struct {
+	Name      string          `json:"name"`
+	JobType   string          `json:"job_type"`
+	Request   json.RawMessage `json:"request"`
+	DependsOn []string        `json:"depends_on"`
+}
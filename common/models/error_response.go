@@ -0,0 +1,6 @@
+package models
+
+type ErrorResponse struct {
+	Error  ServerError `json:"error"`
+	Status int         `json:"status"`
+}
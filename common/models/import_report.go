@@ -0,0 +1,8 @@
+package models
+
+type ImportReport struct {
+	DryRun      bool             `json:"dry_run"`
+	Entities    []EntityWithTags `json:"entities"`
+	Warnings    []string         `json:"warnings"`
+	TagsWritten int              `json:"tags_written"`
+}
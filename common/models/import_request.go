@@ -0,0 +1,9 @@
+package models
+
+// ImportRequest writes the missing tags of layer0 resources, or only lists them if DryRun is set.
+// Entities are the entities of a previous dry run; if set, nothing is written
+// unless the import would still write exactly the same tags
+type ImportRequest struct {
+	DryRun   bool             `json:"dry_run"`
+	Entities []EntityWithTags `json:"entities"`
+}
@@ -11,5 +11,9 @@ type Job struct {
 	JobType     int64             `json:"job_type"`
 	Request     string            `json:"request"`
 	TimeCreated time.Time         `json:"time_created"`
+	EntityType  string            `json:"entity_type"`
+	EntityID    string            `json:"entity_id"`
+	ParentJobID string            `json:"parent_job_id"`
 	Meta        map[string]string `json:"meta"`
+	Steps       []JobStep         `json:"steps"`
 }
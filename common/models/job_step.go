@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+type JobStep struct {
+	Name         string    `json:"name"`
+	Status       int64     `json:"status"`
+	Attempts     int       `json:"attempts"`
+	Error        string    `json:"error"`
+	TimeStarted  time.Time `json:"time_started"`
+	TimeFinished time.Time `json:"time_finished"`
+}
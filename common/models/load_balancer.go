@@ -13,4 +13,5 @@ type LoadBalancer struct {
 	ServiceID        string      `json:"service_id"`
 	ServiceName      string      `json:"service_name"`
 	URL              string      `json:"url"`
+	Version          int64       `json:"version"`
 }
@@ -0,0 +1,17 @@
+package models
+
+// ManifestPlan lists the changes that converge an environment with its manifest
+type ManifestPlan struct {
+	EnvironmentName string           `json:"environment_name"`
+	Changes         []ManifestChange `json:"changes"`
+}
+
+type ManifestChange struct {
+	// Action is one of 'create', 'update' or 'delete'
+	Action     string `json:"action"`
+	EntityType string `json:"entity_type"`
+	EntityName string `json:"entity_name"`
+	EntityID   string `json:"entity_id"`
+	// Details describe the attributes and tags that change, e.g. 'scale: 1 => 3'
+	Details []string `json:"details"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// Resource is the shape of every entity returned by the v2 API.
+// Attributes holds the entity-specific model, e.g. a Service or Job.
+type Resource struct {
+	ID         string        `json:"id"`
+	Type       string        `json:"type"`
+	Name       string        `json:"name"`
+	Version    int64         `json:"version"`
+	Tags       []ResourceTag `json:"tags"`
+	Attributes interface{}   `json:"attributes"`
+}
+
+type ResourceTag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
@@ -2,6 +2,8 @@ package models
 
 type ResourceConsumer struct {
 	ID     string `json:"id"`
+	Group  string `json:"group"`
 	Memory string `json:"memory"`
+	CPU    int    `json:"cpu"`
 	Ports  []int  `json:"ports"`
 }
@@ -1,8 +1,10 @@
 package models
 
 type ResourceProvider struct {
-	ID              string `json:"id"`
-	InUse           bool   `json:"in_use"`
-	UsedPorts       []int  `json:"used_ports"`
-	AvailableMemory string `json:"available_memory"`
+	ID               string `json:"id"`
+	AvailabilityZone string `json:"availability_zone"`
+	InUse            bool   `json:"in_use"`
+	UsedPorts        []int  `json:"used_ports"`
+	AvailableMemory  string `json:"available_memory"`
+	AvailableCPU     int    `json:"available_cpu"`
 }
@@ -0,0 +1,21 @@
+package models
+
+type RestoreReport struct {
+	DryRun          bool              `json:"dry_run"`
+	TagsInserted    int               `json:"tags_inserted"`
+	TagsUnchanged   int               `json:"tags_unchanged"`
+	TagsOverwritten int               `json:"tags_overwritten"`
+	TagConflicts    []TagConflict     `json:"tag_conflicts"`
+	JobsInserted    int               `json:"jobs_inserted"`
+	JobsUnchanged   int               `json:"jobs_unchanged"`
+	Consistency     ConsistencyReport `json:"consistency"`
+}
+
+// TagConflict is a tag whose current value differs from its value in a backup
+type TagConflict struct {
+	EntityType   string `json:"entity_type"`
+	EntityID     string `json:"entity_id"`
+	Key          string `json:"key"`
+	CurrentValue string `json:"current_value"`
+	BackupValue  string `json:"backup_value"`
+}
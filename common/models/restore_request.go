@@ -0,0 +1,11 @@
+package models
+
+// RestoreRequest restores either the backup stored at Key in the layer0 bucket,
+// or the Backup sent with the request. Tags whose current value differs from the
+// backup are reported as conflicts, and only overwritten if Overwrite is set
+type RestoreRequest struct {
+	Key       string  `json:"key"`
+	Backup    *Backup `json:"backup"`
+	DryRun    bool    `json:"dry_run"`
+	Overwrite bool    `json:"overwrite"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// ScaleServiceJobRequest is the request of a ScaleService job
+type ScaleServiceJobRequest struct {
+	ServiceID    string `json:"service_id"`
+	DesiredCount int64  `json:"desired_count"`
+	Version      int64  `json:"version"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import (
+	"time"
+)
+
+// ScalerRun is the record of a single environment scaler run
+type ScalerRun struct {
+	ScalerRunInfo
+	Time  time.Time `json:"time"`
+	Error string    `json:"error,omitempty"`
+}
@@ -1,11 +1,16 @@
 package models
 
 type ScalerRunInfo struct {
-	EnvironmentID           string             `json:"environment_id"`
-	ScaleBeforeRun          int                `json:"scale_before_run"`
-	DesiredScaleAfterRun    int                `json:"desired_scale_after_run"`
-	ActualScaleAfterRun     int                `json:"actual_scale_after_run"`
-	UnusedResourceProviders int                `json:"unused_resource_providers"`
-	PendingResources        []ResourceConsumer `json:"pending_resources"`
-	ResourceProviders       []ResourceProvider `json:"resource_providers"`
+	EnvironmentID               string             `json:"environment_id"`
+	PlacementStrategy           string             `json:"placement_strategy"`
+	ScalerSettings              ScalerSettings     `json:"scaler_settings"`
+	ScaleBeforeRun              int                `json:"scale_before_run"`
+	DesiredScaleAfterRun        int                `json:"desired_scale_after_run"`
+	ActualScaleAfterRun         int                `json:"actual_scale_after_run"`
+	UnusedResourceProviders     int                `json:"unused_resource_providers"`
+	NewResourceProviders        int                `json:"new_resource_providers"`
+	TerminatedResourceProviders []string           `json:"terminated_resource_providers"`
+	PendingResources            []ResourceConsumer `json:"pending_resources"`
+	InitialResourceProviders    []ResourceProvider `json:"initial_resource_providers"`
+	ResourceProviders           []ResourceProvider `json:"resource_providers"`
 }
@@ -0,0 +1,14 @@
+package models
+
+// ScalerSettings control how the scaler changes the size of an environment.
+// Zero values mean no headroom, no scale-down cooldown, and no max cluster count
+type ScalerSettings struct {
+	// HeadroomInstances is the number of empty instances to keep in the environment
+	HeadroomInstances int `json:"headroom_instances"`
+	// HeadroomMemory is the amount of unused memory, in MiB, to keep across the environment's instances
+	HeadroomMemory int `json:"headroom_memory"`
+	// ScaleDownCooldown is the number of seconds to wait after scaling the environment before scaling it down
+	ScaleDownCooldown int `json:"scale_down_cooldown"`
+	// MaxClusterCount is the largest number of instances the scaler will run in the environment
+	MaxClusterCount int `json:"max_cluster_count"`
+}
@@ -0,0 +1,21 @@
+package models
+
+// ScalerSimulationRequest describes hypothetical changes to the resources in an environment
+type ScalerSimulationRequest struct {
+	Services []SimulatedService `json:"services"`
+	Tasks    []SimulatedTask    `json:"tasks"`
+}
+
+// SimulatedService scales an existing service, or adds a new one if ServiceID is empty.
+// If DeployID is empty, the service's current deploy is used
+type SimulatedService struct {
+	ServiceID    string `json:"service_id"`
+	DeployID     string `json:"deploy_id"`
+	DesiredCount int    `json:"desired_count"`
+}
+
+// SimulatedTask adds copies of a task to the environment
+type SimulatedTask struct {
+	DeployID string `json:"deploy_id"`
+	Copies   int    `json:"copies"`
+}
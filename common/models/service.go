@@ -11,4 +11,5 @@ type Service struct {
 	RunningCount     int64        `json:"running_count"`
 	ServiceID        string       `json:"service_id"`
 	ServiceName      string       `json:"service_name"`
+	Version          int64        `json:"version"`
 }
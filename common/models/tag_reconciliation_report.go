@@ -0,0 +1,8 @@
+package models
+
+type TagReconciliationReport struct {
+	DryRun          bool             `json:"dry_run"`
+	EntitiesChecked int              `json:"entities_checked"`
+	Orphans         []EntityWithTags `json:"orphans"`
+	TagsDeleted     int              `json:"tags_deleted"`
+}
@@ -1,5 +1,8 @@
 package models
 
+// UpdateEnvironmentRequest only changes the fields that are set
 type UpdateEnvironmentRequest struct {
-	MinClusterCount int `json:"min_cluster_count"`
+	MinClusterCount   *int            `json:"min_cluster_count,omitempty"`
+	PlacementStrategy string          `json:"placement_strategy,omitempty"`
+	ScalerSettings    *ScalerSettings `json:"scaler_settings,omitempty"`
 }
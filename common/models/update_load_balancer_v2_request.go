@@ -0,0 +1,8 @@
+package models
+
+type UpdateLoadBalancerV2Request struct {
+	CrossZone   *bool        `json:"cross_zone,omitempty"`
+	HealthCheck *HealthCheck `json:"health_check,omitempty"`
+	IdleTimeout *int         `json:"idle_timeout,omitempty"`
+	Ports       *[]Port      `json:"ports,omitempty"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// UpdateServiceJobRequest is the request of an UpdateService job
+type UpdateServiceJobRequest struct {
+	ServiceID string `json:"service_id"`
+	DeployID  string `json:"deploy_id"`
+	// DesiredCount scales the service after its deploy is updated, when set
+	DesiredCount *int64 `json:"desired_count,omitempty"`
+	Version      int64  `json:"version"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type UpdateServiceV2Request struct {
+	DeployID     *string `json:"deploy_id,omitempty"`
+	DesiredCount *int64  `json:"desired_count,omitempty"`
+}
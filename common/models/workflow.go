@@ -0,0 +1,17 @@
+package models
+
+type Workflow struct {
+	JobID     string               `json:"job_id"`
+	JobStatus int64                `json:"job_status"`
+	OnFailure string               `json:"on_failure"`
+	Nodes     []WorkflowNodeStatus `json:"nodes"`
+}
+
+type WorkflowNodeStatus struct {
+	Name      string   `json:"name"`
+	JobType   string   `json:"job_type"`
+	DependsOn []string `json:"depends_on"`
+	JobID     string   `json:"job_id"`
+	Status    string   `json:"status"`
+	Error     string   `json:"error"`
+}
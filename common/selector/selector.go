@@ -0,0 +1,329 @@
+package selector
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+)
+
+type Operator string
+
+const (
+	Equals       Operator = "="
+	NotEquals    Operator = "!="
+	In           Operator = "in"
+	NotIn        Operator = "notin"
+	Exists       Operator = "exists"
+	DoesNotExist Operator = "!"
+)
+
+// Requirement is a single condition on the value of one tag key.
+// As with kubernetes label selectors, the negative operators
+// also match entities that don't have the key at all.
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+// Matches reports whether the tag values, indexed by key, satisfy the requirement
+func (r Requirement) Matches(values map[string]string) bool {
+	value, ok := values[r.Key]
+
+	switch r.Operator {
+	case Equals, In:
+		return ok && contains(r.Values, value)
+	case NotEquals, NotIn:
+		return !ok || !contains(r.Values, value)
+	case Exists:
+		return ok
+	case DoesNotExist:
+		return !ok
+	default:
+		return false
+	}
+}
+
+func (r Requirement) String() string {
+	switch r.Operator {
+	case Equals, NotEquals:
+		return r.Key + string(r.Operator) + r.Values[0]
+	case In, NotIn:
+		return r.Key + " " + string(r.Operator) + " (" + strings.Join(r.Values, ",") + ")"
+	case DoesNotExist:
+		return "!" + r.Key
+	default:
+		return r.Key
+	}
+}
+
+// Selector matches entities that satisfy every one of its requirements;
+// an empty selector matches every entity
+type Selector []Requirement
+
+func (s Selector) Matches(values map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(values) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MatchesTags reports whether the tags of a single entity satisfy the selector
+func (s Selector) MatchesTags(tags models.Tags) bool {
+	values := map[string]string{}
+	for _, tag := range tags {
+		values[tag.Key] = tag.Value
+	}
+
+	return s.Matches(values)
+}
+
+// Filter returns the tags of the entities that satisfy the selector
+func (s Selector) Filter(tags models.Tags) models.Tags {
+	filtered := models.Tags{}
+	for _, ewt := range tags.GroupByEntity() {
+		if s.MatchesTags(ewt.Tags) {
+			filtered = append(filtered, ewt.Tags...)
+		}
+	}
+
+	return filtered
+}
+
+func (s Selector) String() string {
+	requirements := make([]string, len(s))
+	for i, r := range s {
+		requirements[i] = r.String()
+	}
+
+	return strings.Join(requirements, ",")
+}
+
+// IsSelector reports whether target uses selector syntax, rather than being
+// a plain entity name or id; a bare key is only treated as a name
+func IsSelector(target string) bool {
+	return strings.ContainsAny(target, "=!(") ||
+		strings.Contains(target, " in ") ||
+		strings.Contains(target, " notin ")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parse parses a comma-separated list of requirements, e.g.
+// 'team=payments,tier!=dev,region in (us,eu),!deprecated'.
+// The supported operators are '=', '==', '!=', 'in', 'notin',
+// 'KEY' (the key exists) and '!KEY' (the key doesn't exist).
+func Parse(expression string) (Selector, error) {
+	p := &parser{expression: expression, tokens: lex(expression)}
+	return p.parse()
+}
+
+type tokenKind int
+
+const (
+	identifierToken tokenKind = iota
+	equalsToken
+	notEqualsToken
+	notToken
+	openToken
+	closeToken
+	commaToken
+	endToken
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func (t token) String() string {
+	if t.kind == endToken {
+		return "end of selector"
+	}
+
+	return "'" + t.value + "'"
+}
+
+func lex(expression string) []token {
+	tokens := []token{}
+	for i := 0; i < len(expression); {
+		switch c := expression[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: commaToken, value: ","})
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: openToken, value: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: closeToken, value: ")"})
+			i++
+		case c == '=':
+			i++
+			if i < len(expression) && expression[i] == '=' {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: equalsToken, value: "="})
+		case c == '!':
+			i++
+			if i < len(expression) && expression[i] == '=' {
+				i++
+				tokens = append(tokens, token{kind: notEqualsToken, value: "!="})
+				continue
+			}
+
+			tokens = append(tokens, token{kind: notToken, value: "!"})
+		default:
+			start := i
+			for i < len(expression) && !strings.ContainsRune(" \t,()=!", rune(expression[i])) {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: identifierToken, value: expression[start:i]})
+		}
+	}
+
+	return append(tokens, token{kind: endToken})
+}
+
+type parser struct {
+	expression string
+	tokens     []token
+	position   int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.position]
+	if t.kind != endToken {
+		p.position++
+	}
+
+	return t
+}
+
+func (p *parser) errorf(format string, tokens ...interface{}) error {
+	return errors.Newf(errors.InvalidSelector, "Invalid selector '%s': %s", p.expression, fmt.Sprintf(format, tokens...))
+}
+
+func (p *parser) parse() (Selector, error) {
+	selector := Selector{}
+	if p.peek().kind == endToken {
+		return selector, nil
+	}
+
+	for {
+		requirement, err := p.parseRequirement()
+		if err != nil {
+			return nil, err
+		}
+
+		selector = append(selector, requirement)
+
+		switch t := p.next(); t.kind {
+		case endToken:
+			return selector, nil
+		case commaToken:
+		default:
+			return nil, p.errorf("expected ',' but found %s", t)
+		}
+	}
+}
+
+func (p *parser) parseRequirement() (Requirement, error) {
+	if p.peek().kind == notToken {
+		p.next()
+		key, err := p.parseKey()
+		if err != nil {
+			return Requirement{}, err
+		}
+
+		return Requirement{Key: key, Operator: DoesNotExist}, nil
+	}
+
+	key, err := p.parseKey()
+	if err != nil {
+		return Requirement{}, err
+	}
+
+	switch t := p.peek(); {
+	case t.kind == commaToken || t.kind == endToken:
+		return Requirement{Key: key, Operator: Exists}, nil
+	case t.kind == equalsToken || t.kind == notEqualsToken:
+		p.next()
+
+		// like kubernetes, an empty value is allowed
+		var value string
+		if p.peek().kind == identifierToken {
+			value = p.next().value
+		}
+
+		operator := Equals
+		if t.kind == notEqualsToken {
+			operator = NotEquals
+		}
+
+		return Requirement{Key: key, Operator: operator, Values: []string{value}}, nil
+	case t.kind == identifierToken && (t.value == string(In) || t.value == string(NotIn)):
+		p.next()
+		values, err := p.parseValues()
+		if err != nil {
+			return Requirement{}, err
+		}
+
+		return Requirement{Key: key, Operator: Operator(t.value), Values: values}, nil
+	default:
+		return Requirement{}, p.errorf("expected an operator after '%s' but found %s", key, t)
+	}
+}
+
+func (p *parser) parseKey() (string, error) {
+	t := p.next()
+	if t.kind != identifierToken {
+		return "", p.errorf("expected a key but found %s", t)
+	}
+
+	return t.value, nil
+}
+
+func (p *parser) parseValues() ([]string, error) {
+	if t := p.next(); t.kind != openToken {
+		return nil, p.errorf("expected '(' but found %s", t)
+	}
+
+	values := []string{}
+	for {
+		t := p.next()
+		if t.kind != identifierToken {
+			return nil, p.errorf("expected a value but found %s", t)
+		}
+
+		values = append(values, t.value)
+
+		switch t := p.next(); t.kind {
+		case closeToken:
+			return values, nil
+		case commaToken:
+		default:
+			return nil, p.errorf("expected ',' or ')' but found %s", t)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]Selector{
+		"": {},
+		"team=payments": {
+			{Key: "team", Operator: Equals, Values: []string{"payments"}},
+		},
+		"team==payments": {
+			{Key: "team", Operator: Equals, Values: []string{"payments"}},
+		},
+		"team=": {
+			{Key: "team", Operator: Equals, Values: []string{""}},
+		},
+		"team=payments,tier!=dev,region in (us, eu)": {
+			{Key: "team", Operator: Equals, Values: []string{"payments"}},
+			{Key: "tier", Operator: NotEquals, Values: []string{"dev"}},
+			{Key: "region", Operator: In, Values: []string{"us", "eu"}},
+		},
+		" region notin (us) , owner, !deprecated ": {
+			{Key: "region", Operator: NotIn, Values: []string{"us"}},
+			{Key: "owner", Operator: Exists},
+			{Key: "deprecated", Operator: DoesNotExist},
+		},
+	}
+
+	for expression, expected := range cases {
+		selector, err := Parse(expression)
+		if err != nil {
+			t.Fatalf("%s: %v", expression, err)
+		}
+
+		testutils.AssertEqual(t, selector, expected)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	expressions := []string{
+		"=payments",
+		"team payments",
+		"team=payments,",
+		"team=payments tier=dev",
+		"region in us",
+		"region in ()",
+		"region in (us",
+		"region in (us eu)",
+		"!",
+		"!team=payments",
+	}
+
+	for _, expression := range expressions {
+		_, err := Parse(expression)
+		if serr, ok := err.(*errors.ServerError); !ok || serr.Code != errors.InvalidSelector {
+			t.Errorf("%s: error was '%v', expected InvalidSelector", expression, err)
+		}
+	}
+}
+
+func TestSelectorMatches(t *testing.T) {
+	values := map[string]string{
+		"team":   "payments",
+		"tier":   "prod",
+		"region": "us",
+	}
+
+	cases := map[string]bool{
+		"":                        true,
+		"team=payments":           true,
+		"team=search":             false,
+		"team!=search":            true,
+		"owner!=bob":              true,
+		"region in (us,eu)":       true,
+		"region in (ap)":          false,
+		"region notin (us)":       false,
+		"owner notin (bob)":       true,
+		"team":                    true,
+		"owner":                   false,
+		"!owner":                  true,
+		"!team":                   false,
+		"team=payments,tier!=dev": true,
+		"team=payments,tier=dev":  false,
+	}
+
+	for expression, expected := range cases {
+		selector, err := Parse(expression)
+		if err != nil {
+			t.Fatalf("%s: %v", expression, err)
+		}
+
+		if r := selector.Matches(values); r != expected {
+			t.Errorf("%s: result was %t, expected %t", expression, r, expected)
+		}
+	}
+}
+
+func TestSelectorFilter(t *testing.T) {
+	tags := models.Tags{
+		{EntityType: "service", EntityID: "s1", Key: "team", Value: "payments"},
+		{EntityType: "service", EntityID: "s1", Key: "name", Value: "svc1"},
+		{EntityType: "service", EntityID: "s2", Key: "team", Value: "search"},
+		{EntityType: "service", EntityID: "s2", Key: "name", Value: "svc2"},
+	}
+
+	selector, err := Parse("team=payments")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, selector.Filter(tags), tags[0:2])
+}
+
+func TestSelectorString(t *testing.T) {
+	expression := "team=payments,tier!=dev,region in (us,eu),region notin (ap),owner,!deprecated"
+
+	selector, err := Parse(expression)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, selector.String(), expression)
+}
+
+func TestIsSelector(t *testing.T) {
+	cases := map[string]bool{
+		"svc1":                 false,
+		"svc*":                 false,
+		"team=payments":        true,
+		"tier!=dev":            true,
+		"!deprecated":          true,
+		"region in (us,eu)":    true,
+		"region notin (us,eu)": true,
+	}
+
+	for target, expected := range cases {
+		testutils.AssertEqual(t, IsSelector(target), expected)
+	}
+}
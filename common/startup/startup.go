@@ -1,6 +1,9 @@
 package startup
 
 import (
+	"fmt"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -19,9 +22,12 @@ import (
 	"github.com/quintilesims/layer0/common/aws/s3"
 	"github.com/quintilesims/layer0/common/config"
 	"github.com/quintilesims/layer0/common/db/job_store"
+	"github.com/quintilesims/layer0/common/db/lease_store"
+	"github.com/quintilesims/layer0/common/db/sqldb"
 	"github.com/quintilesims/layer0/common/db/tag_store"
 	"github.com/quintilesims/layer0/common/decorators"
 	"github.com/quintilesims/layer0/common/waitutils"
+	"github.com/quintilesims/layer0/runner/job"
 )
 
 func GetBackend(credProvider provider.CredProvider, region string) (*ecsbackend.ECSBackend, error) {
@@ -113,43 +119,164 @@ func GetLogic(backend *ecsbackend.ECSBackend) (*logic.Logic, error) {
 
 	lgc := logic.NewLogic(tagStore, jobStore, backend, nil)
 
+	// the executor is set before the other logic layers copy lgc
+	jobExecutor, err := getJobExecutor(lgc)
+	if err != nil {
+		return nil, err
+	}
+
+	lgc.JobExecutor = jobExecutor
+
+	jobArchive, err := getJobArchive()
+	if err != nil {
+		return nil, err
+	}
+
+	lgc.JobArchive = jobArchive
+
+	backupStore, err := getBackupStore()
+	if err != nil {
+		return nil, err
+	}
+
+	lgc.BackupStore = backupStore
+	lgc.ConsistencyChecker = logic.NewL0ConsistencyChecker(backend, backend.ECSEnvironmentManager.AutoScaling)
+
 	deployLogic := logic.NewL0DeployLogic(*lgc)
+	environmentLogic := logic.NewL0EnvironmentLogic(*lgc)
 	serviceLogic := logic.NewL0ServiceLogic(*lgc)
 	taskLogic := logic.NewL0TaskLogic(*lgc)
-	jobLogic := logic.NewL0JobLogic(*lgc, taskLogic, deployLogic)
+	jobLogic := logic.NewL0JobLogic(*lgc, taskLogic)
 
 	ecsResourceManager := ecsbackend.NewECSResourceManager(backend.ECSEnvironmentManager.ECS, backend.ECSEnvironmentManager.AutoScaling)
 	environmentResourceGetter := logic.NewEnvironmentResourceGetter(serviceLogic, taskLogic, deployLogic, jobLogic)
-	scaler := scheduler.NewL0EnvironmentScaler(environmentResourceGetter, ecsResourceManager)
+	scalerHistory, err := getScalerHistory()
+	if err != nil {
+		return nil, err
+	}
+
+	scaler := scheduler.NewL0EnvironmentScaler(environmentResourceGetter, ecsResourceManager, environmentLogic, environmentLogic, environmentLogic, scalerHistory)
 	lgc.Scaler = scaler
 
 	return lgc, nil
 }
 
-func getNewTagStore() (tag_store.TagStore, error) {
-	creds := credentials.NewStaticCredentials(config.AWSAccessKey(), config.AWSSecretKey(), "")
-	session := session.New(config.GetAWSConfig(creds, config.AWSRegion()))
-	if err := sessionTimeDelay(session); err != nil {
+func getJobExecutor(lgc *logic.Logic) (logic.JobExecutor, error) {
+	switch executor := config.JobExecutor(); executor {
+	case config.JOB_EXECUTOR_ECS:
+		return logic.NewECSJobExecutor(logic.NewL0TaskLogic(*lgc), logic.NewL0DeployLogic(*lgc)), nil
+	case config.JOB_EXECUTOR_LOCAL:
+		return logic.NewWorkerPoolJobExecutor(config.JobWorkers(), config.JobQueueSize(), job.NewRunFunc(lgc)), nil
+	default:
+		return nil, fmt.Errorf("Unknown job executor '%s'", executor)
+	}
+}
+
+func getJobArchive() (logic.JobArchive, error) {
+	s3Provider, err := s3.NewS3(config.NewConfigCredProvider(), config.AWSRegion())
+	if err != nil {
 		return nil, err
 	}
 
-	store := tag_store.NewDynamoTagStore(session, config.DynamoTagTableName())
+	return logic.NewS3JobArchive(s3Provider, config.AWSS3Bucket()), nil
+}
+
+func getBackupStore() (logic.BackupStore, error) {
+	s3Provider, err := s3.NewS3(config.NewConfigCredProvider(), config.AWSRegion())
+	if err != nil {
+		return nil, err
+	}
+
+	return logic.NewS3BackupStore(s3Provider, config.AWSS3Bucket()), nil
+}
+
+func getScalerHistory() (scheduler.ScalerHistory, error) {
+	s3Provider, err := s3.NewS3(config.NewConfigCredProvider(), config.AWSRegion())
+	if err != nil {
+		return nil, err
+	}
+
+	return scheduler.NewS3ScalerHistory(s3Provider, config.AWSS3Bucket()), nil
+}
+
+// GetLeaderElector returns an elector for this replica of the api, which keeps its lease
+// in the same database as the tags and jobs; each replica is identified by its hostname and start time
+func GetLeaderElector() (*logic.LeaderElector, error) {
+	var store lease_store.LeaseStore
+	if driver := config.DBDriver(); driver == config.DB_DRIVER_DYNAMO {
+		creds := credentials.NewStaticCredentials(config.AWSAccessKey(), config.AWSSecretKey(), "")
+		session := session.New(config.GetAWSConfig(creds, config.AWSRegion()))
+		if err := sessionTimeDelay(session); err != nil {
+			return nil, err
+		}
+
+		store = lease_store.NewDynamoLeaseStore(session, config.DynamoLeaseTableName())
+	} else {
+		db, err := getSQLDB(driver)
+		if err != nil {
+			return nil, err
+		}
+
+		store = lease_store.NewSQLLeaseStore(db)
+	}
 
 	if err := store.Init(); err != nil {
 		return nil, err
 	}
 
-	return store, nil
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+
+	holder := fmt.Sprintf("%s-%d", hostname, time.Now().UnixNano())
+	return logic.NewLeaderElector(store, holder), nil
 }
 
-func getNewJobStore() (job_store.JobStore, error) {
-	creds := credentials.NewStaticCredentials(config.AWSAccessKey(), config.AWSSecretKey(), "")
-	session := session.New(config.GetAWSConfig(creds, config.AWSRegion()))
-	if err := sessionTimeDelay(session); err != nil {
+func getNewTagStore() (tag_store.TagStore, error) {
+	var store tag_store.TagStore
+	if driver := config.DBDriver(); driver == config.DB_DRIVER_DYNAMO {
+		creds := credentials.NewStaticCredentials(config.AWSAccessKey(), config.AWSSecretKey(), "")
+		session := session.New(config.GetAWSConfig(creds, config.AWSRegion()))
+		if err := sessionTimeDelay(session); err != nil {
+			return nil, err
+		}
+
+		store = tag_store.NewDynamoTagStore(session, config.DynamoTagTableName())
+	} else {
+		db, err := getSQLDB(driver)
+		if err != nil {
+			return nil, err
+		}
+
+		store = tag_store.NewSQLTagStore(db)
+	}
+
+	if err := store.Init(); err != nil {
 		return nil, err
 	}
 
-	store := job_store.NewDynamoJobStore(session, config.DynamoJobTableName())
+	return store, nil
+}
+
+func getNewJobStore() (job_store.JobStore, error) {
+	var store job_store.JobStore
+	if driver := config.DBDriver(); driver == config.DB_DRIVER_DYNAMO {
+		creds := credentials.NewStaticCredentials(config.AWSAccessKey(), config.AWSSecretKey(), "")
+		session := session.New(config.GetAWSConfig(creds, config.AWSRegion()))
+		if err := sessionTimeDelay(session); err != nil {
+			return nil, err
+		}
+
+		store = job_store.NewDynamoJobStore(session, config.DynamoJobTableName())
+	} else {
+		db, err := getSQLDB(driver)
+		if err != nil {
+			return nil, err
+		}
+
+		store = job_store.NewSQLJobStore(db)
+	}
 
 	if err := store.Init(); err != nil {
 		return nil, err
@@ -158,6 +285,34 @@ func getNewJobStore() (job_store.JobStore, error) {
 	return store, nil
 }
 
+// the tag and job stores share a single connection pool
+var (
+	sqlDB      *sqldb.DB
+	sqlDBMutex sync.Mutex
+)
+
+func getSQLDB(driver string) (*sqldb.DB, error) {
+	sqlDBMutex.Lock()
+	defer sqlDBMutex.Unlock()
+
+	if sqlDB != nil {
+		return sqlDB, nil
+	}
+
+	dataSource := config.DBDataSource()
+	if dataSource == "" {
+		return nil, fmt.Errorf("%s must be set when %s is '%s'", config.DB_DATA_SOURCE, config.DB_DRIVER, driver)
+	}
+
+	db, err := sqldb.Open(driver, dataSource)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB = db
+	return sqlDB, nil
+}
+
 func sessionTimeDelay(session *session.Session) error {
 	delay, err := time.ParseDuration(config.AWSTimeBetweenRequests())
 	if err != nil {
@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"strings"
 )
 
 type JobStatus int64
@@ -11,6 +12,7 @@ const (
 	InProgress
 	Completed
 	Error
+	Cancelled
 )
 
 var jobStatusStrings = []string{
@@ -18,6 +20,7 @@ var jobStatusStrings = []string{
 	"in progress",
 	"completed",
 	"error",
+	"cancelled",
 }
 
 func (jobStatus JobStatus) String() string {
@@ -28,6 +31,17 @@ func (jobStatus JobStatus) String() string {
 	return jobStatusStrings[jobStatus-1]
 }
 
+// ParseJobStatus returns the status with the specified name, e.g. "in progress"
+func ParseJobStatus(s string) (JobStatus, error) {
+	for i, str := range jobStatusStrings {
+		if strings.EqualFold(str, s) {
+			return JobStatus(i + 1), nil
+		}
+	}
+
+	return 0, fmt.Errorf("Unknown job status '%s'", s)
+}
+
 type JobType int64
 
 const (
@@ -36,6 +50,13 @@ const (
 	DeleteLoadBalancerJob
 	DeleteTaskJob
 	CreateTaskJob
+	CreateEnvironmentJob
+	CreateLoadBalancerJob
+	UpdateServiceJob
+	ScaleServiceJob
+	CreateServiceJob
+	WorkflowJob
+	CloneEnvironmentJob
 )
 
 var jobTypeStrings = []string{
@@ -44,6 +65,13 @@ var jobTypeStrings = []string{
 	"delete load balancer",
 	"delete task",
 	"create task",
+	"create environment",
+	"create load balancer",
+	"update service",
+	"scale service",
+	"create service",
+	"workflow",
+	"clone environment",
 }
 
 func (jobType JobType) String() string {
@@ -53,3 +81,24 @@ func (jobType JobType) String() string {
 
 	return jobTypeStrings[jobType-1]
 }
+
+// ParseJobType returns the job type with the specified name, e.g. "delete service"
+func ParseJobType(s string) (JobType, error) {
+	for i, str := range jobTypeStrings {
+		if strings.EqualFold(str, s) {
+			return JobType(i + 1), nil
+		}
+	}
+
+	return 0, fmt.Errorf("Unknown job type '%s'", s)
+}
+
+// WorkflowFailurePolicy controls what a workflow does once one of its nodes fails
+type WorkflowFailurePolicy string
+
+const (
+	// WorkflowHalt stops starting new nodes; nodes that are already running are allowed to finish
+	WorkflowHalt WorkflowFailurePolicy = "halt"
+	// WorkflowContinue keeps running every node that doesn't depend on a failed node
+	WorkflowContinue WorkflowFailurePolicy = "continue"
+)
@@ -10,3 +10,7 @@ type Layer0Client struct {
 	API         client.Client
 	StopContext context.Context
 }
+
+// terraform owns the desired state of its resources,
+// so updates are applied regardless of the entity's current version
+const anyVersion = client.AnyVersion
@@ -5,6 +5,7 @@ import (
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
 )
 
 func resourceLayer0Environment() *schema.Resource {
@@ -49,6 +50,27 @@ func resourceLayer0Environment() *schema.Resource {
 				ForceNew: true,
 				Computed: true,
 			},
+			"placement_strategy": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"headroom_instances": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"headroom_memory": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"scale_down_cooldown": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"max_count": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
 			"cluster_count": {
 				Type:     schema.TypeInt,
 				Computed: true,
@@ -70,13 +92,20 @@ func resourceLayer0EnvironmentCreate(d *schema.ResourceData, meta interface{}) e
 	userData := d.Get("user_data").(string)
 	os := d.Get("os").(string)
 	ami := d.Get("ami").(string)
+	placementStrategy := d.Get("placement_strategy").(string)
+	scalerSettings := scalerSettingsFromResourceData(d)
 
-	environment, err := client.API.CreateEnvironment(name, size, minCount, []byte(userData), os, ami)
+	jobID, err := client.API.CreateEnvironment(name, size, minCount, []byte(userData), os, ami, placementStrategy, scalerSettings)
 	if err != nil {
 		return err
 	}
 
-	d.SetId(environment.EnvironmentID)
+	environmentID, err := waitForJobMeta(client, jobID, "environment_id")
+	if err != nil {
+		return err
+	}
+
+	d.SetId(environmentID)
 	return resourceLayer0EnvironmentRead(d, meta)
 }
 
@@ -101,6 +130,11 @@ func resourceLayer0EnvironmentRead(d *schema.ResourceData, meta interface{}) err
 	d.Set("security_group_id", environment.SecurityGroupID)
 	d.Set("os", environment.OperatingSystem)
 	d.Set("ami", environment.AMIID)
+	d.Set("placement_strategy", environment.PlacementStrategy)
+	d.Set("headroom_instances", environment.ScalerSettings.HeadroomInstances)
+	d.Set("headroom_memory", environment.ScalerSettings.HeadroomMemory)
+	d.Set("scale_down_cooldown", environment.ScalerSettings.ScaleDownCooldown)
+	d.Set("max_count", environment.ScalerSettings.MaxClusterCount)
 
 	return nil
 }
@@ -112,7 +146,23 @@ func resourceLayer0EnvironmentUpdate(d *schema.ResourceData, meta interface{}) e
 	if d.HasChange("min_count") {
 		minCount := d.Get("min_count").(int)
 
-		if _, err := client.API.UpdateEnvironment(environmentID, minCount); err != nil {
+		if _, err := client.API.UpdateEnvironment(environmentID, minCount, anyVersion); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("placement_strategy") {
+		placementStrategy := d.Get("placement_strategy").(string)
+
+		if _, err := client.API.UpdateEnvironmentPlacementStrategy(environmentID, placementStrategy, anyVersion); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("headroom_instances") || d.HasChange("headroom_memory") || d.HasChange("scale_down_cooldown") || d.HasChange("max_count") {
+		scalerSettings := scalerSettingsFromResourceData(d)
+
+		if _, err := client.API.UpdateEnvironmentScalerSettings(environmentID, scalerSettings, anyVersion); err != nil {
 			return err
 		}
 	}
@@ -120,6 +170,15 @@ func resourceLayer0EnvironmentUpdate(d *schema.ResourceData, meta interface{}) e
 	return resourceLayer0EnvironmentRead(d, meta)
 }
 
+func scalerSettingsFromResourceData(d *schema.ResourceData) models.ScalerSettings {
+	return models.ScalerSettings{
+		HeadroomInstances: d.Get("headroom_instances").(int),
+		HeadroomMemory:    d.Get("headroom_memory").(int),
+		ScaleDownCooldown: d.Get("scale_down_cooldown").(int),
+		MaxClusterCount:   d.Get("max_count").(int),
+	}
+}
+
 func resourceLayer0EnvironmentDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*Layer0Client)
 	environmentID := d.Id()
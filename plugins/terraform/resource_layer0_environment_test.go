@@ -14,8 +14,16 @@ func TestEnvironmentCreate_defaults(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockClient.EXPECT().
-		CreateEnvironment("test-env", "m3.medium", 0, []byte(""), "linux", "").
-		Return(&models.Environment{EnvironmentID: "eid"}, nil)
+		CreateEnvironment("test-env", "m3.medium", 0, []byte(""), "linux", "", "", models.ScalerSettings{}).
+		Return("jid", nil)
+
+	mockClient.EXPECT().
+		WaitForJob("jid", gomock.Any()).
+		Return(nil)
+
+	mockClient.EXPECT().
+		GetJob("jid").
+		Return(&models.Job{Meta: map[string]string{"environment_id": "eid"}}, nil)
 
 	mockClient.EXPECT().
 		GetEnvironment("eid").
@@ -26,7 +34,7 @@ func TestEnvironmentCreate_defaults(t *testing.T) {
 		"name": "test-env",
 	})
 
-	client := &Layer0Client{API: mockClient}
+	client := &Layer0Client{API: mockClient, StopContext: context.Background()}
 	if err := environmentResource.Create(d, client); err != nil {
 		t.Fatal(err)
 	}
@@ -36,9 +44,24 @@ func TestEnvironmentCreate_specifyOptional(t *testing.T) {
 	ctrl, mockClient, provider := setupUnitTest(t)
 	defer ctrl.Finish()
 
+	scalerSettings := models.ScalerSettings{
+		HeadroomInstances: 1,
+		HeadroomMemory:    512,
+		ScaleDownCooldown: 300,
+		MaxClusterCount:   4,
+	}
+
+	mockClient.EXPECT().
+		CreateEnvironment("test-env", "m3.large", 2, []byte("user data"), "windows", "ami_id", "spread-zone", scalerSettings).
+		Return("jid", nil)
+
 	mockClient.EXPECT().
-		CreateEnvironment("test-env", "m3.large", 2, []byte("user data"), "windows", "ami_id").
-		Return(&models.Environment{EnvironmentID: "eid"}, nil)
+		WaitForJob("jid", gomock.Any()).
+		Return(nil)
+
+	mockClient.EXPECT().
+		GetJob("jid").
+		Return(&models.Job{Meta: map[string]string{"environment_id": "eid"}}, nil)
 
 	mockClient.EXPECT().
 		GetEnvironment("eid").
@@ -46,15 +69,20 @@ func TestEnvironmentCreate_specifyOptional(t *testing.T) {
 
 	environmentResource := provider.ResourcesMap["layer0_environment"]
 	d := schema.TestResourceDataRaw(t, environmentResource.Schema, map[string]interface{}{
-		"name":      "test-env",
-		"size":      "m3.large",
-		"min_count": 2,
-		"user_data": "user data",
-		"os":        "windows",
-		"ami":       "ami_id",
+		"name":                "test-env",
+		"size":                "m3.large",
+		"min_count":           2,
+		"user_data":           "user data",
+		"os":                  "windows",
+		"ami":                 "ami_id",
+		"placement_strategy":  "spread-zone",
+		"headroom_instances":  1,
+		"headroom_memory":     512,
+		"scale_down_cooldown": 300,
+		"max_count":           4,
 	})
 
-	client := &Layer0Client{API: mockClient}
+	client := &Layer0Client{API: mockClient, StopContext: context.Background()}
 	if err := environmentResource.Create(d, client); err != nil {
 		t.Fatal(err)
 	}
@@ -84,15 +112,27 @@ func TestEnvironmentUpdate(t *testing.T) {
 
 	gomock.InOrder(
 		mockClient.EXPECT().
-			CreateEnvironment("test-env", "m3.medium", 0, []byte(""), "linux", "").
-			Return(&models.Environment{EnvironmentID: "eid"}, nil),
+			CreateEnvironment("test-env", "m3.medium", 0, []byte(""), "linux", "", "", models.ScalerSettings{}).
+			Return("jid", nil),
+
+		mockClient.EXPECT().
+			WaitForJob("jid", gomock.Any()).
+			Return(nil),
+
+		mockClient.EXPECT().
+			GetJob("jid").
+			Return(&models.Job{Meta: map[string]string{"environment_id": "eid"}}, nil),
 
 		mockClient.EXPECT().
 			GetEnvironment("eid").
 			Return(&models.Environment{EnvironmentID: "eid"}, nil),
 
 		mockClient.EXPECT().
-			UpdateEnvironment("eid", 3).
+			UpdateEnvironment("eid", 3, anyVersion).
+			Return(&models.Environment{EnvironmentID: "eid"}, nil),
+
+		mockClient.EXPECT().
+			UpdateEnvironmentScalerSettings("eid", models.ScalerSettings{MaxClusterCount: 5}, anyVersion).
 			Return(&models.Environment{EnvironmentID: "eid"}, nil),
 
 		mockClient.EXPECT().
@@ -108,11 +148,12 @@ func TestEnvironmentUpdate(t *testing.T) {
 	d2 := schema.TestResourceDataRaw(t, environmentResource.Schema, map[string]interface{}{
 		"name":      "test-env",
 		"min_count": 3,
+		"max_count": 5,
 	})
 
 	d2.SetId("eid")
 
-	client := &Layer0Client{API: mockClient}
+	client := &Layer0Client{API: mockClient, StopContext: context.Background()}
 	if err := environmentResource.Create(d1, client); err != nil {
 		t.Fatal(err)
 	}
@@ -136,12 +136,17 @@ func resourceLayer0LoadBalancerCreate(d *schema.ResourceData, meta interface{})
 		}
 	}
 
-	loadBalancer, err := client.API.CreateLoadBalancer(name, environmentID, *healthCheck, ports, !private, idleTimeout, crossZone)
+	jobID, err := client.API.CreateLoadBalancer(name, environmentID, *healthCheck, ports, !private, idleTimeout, crossZone)
 	if err != nil {
 		return err
 	}
 
-	d.SetId(loadBalancer.LoadBalancerID)
+	loadBalancerID, err := waitForJobMeta(client, jobID, "load_balancer_id")
+	if err != nil {
+		return err
+	}
+
+	d.SetId(loadBalancerID)
 	return resourceLayer0LoadBalancerRead(d, meta)
 }
 
@@ -179,7 +184,7 @@ func resourceLayer0LoadBalancerUpdate(d *schema.ResourceData, meta interface{})
 	if d.HasChange("port") {
 		ports := expandPorts(d.Get("port").(*schema.Set).List())
 
-		if _, err := client.API.UpdateLoadBalancerPorts(loadBalancerID, ports); err != nil {
+		if _, err := client.API.UpdateLoadBalancerPorts(loadBalancerID, ports, anyVersion); err != nil {
 			return err
 		}
 	}
@@ -187,7 +192,7 @@ func resourceLayer0LoadBalancerUpdate(d *schema.ResourceData, meta interface{})
 	if d.HasChange("health_check") {
 		healthCheck := expandHealthCheck(d.Get("health_check"))
 
-		if _, err := client.API.UpdateLoadBalancerHealthCheck(loadBalancerID, *healthCheck); err != nil {
+		if _, err := client.API.UpdateLoadBalancerHealthCheck(loadBalancerID, *healthCheck, anyVersion); err != nil {
 			return err
 		}
 	}
@@ -195,7 +200,7 @@ func resourceLayer0LoadBalancerUpdate(d *schema.ResourceData, meta interface{})
 	if d.HasChange("idle_timeout") {
 		idleTimeout := d.Get("idle_timeout").(int)
 
-		if _, err := client.API.UpdateLoadBalancerIdleTimeout(loadBalancerID, idleTimeout); err != nil {
+		if _, err := client.API.UpdateLoadBalancerIdleTimeout(loadBalancerID, idleTimeout, anyVersion); err != nil {
 			return err
 		}
 	}
@@ -203,7 +208,7 @@ func resourceLayer0LoadBalancerUpdate(d *schema.ResourceData, meta interface{})
 	if d.HasChange("cross_zone") {
 		crossZone := d.Get("cross_zone").(bool)
 
-		if _, err := client.API.UpdateLoadBalancerCrossZone(loadBalancerID, crossZone); err != nil {
+		if _, err := client.API.UpdateLoadBalancerCrossZone(loadBalancerID, crossZone, anyVersion); err != nil {
 			return err
 		}
 	}
@@ -23,7 +23,15 @@ func TestLoadBalancerCreate_defaults(t *testing.T) {
 
 	mockClient.EXPECT().
 		CreateLoadBalancer("test-lb", "test-env", models.HealthCheck{"TCP:80", 30, 5, 2, 2}, ports, true, 60, true).
-		Return(&models.LoadBalancer{LoadBalancerID: "lbid"}, nil)
+		Return("jid", nil)
+
+	mockClient.EXPECT().
+		WaitForJob("jid", gomock.Any()).
+		Return(nil)
+
+	mockClient.EXPECT().
+		GetJob("jid").
+		Return(&models.Job{Meta: map[string]string{"load_balancer_id": "lbid"}}, nil)
 
 	mockClient.EXPECT().
 		GetLoadBalancer("lbid").
@@ -36,7 +44,7 @@ func TestLoadBalancerCreate_defaults(t *testing.T) {
 		"port":        flattenPorts(ports),
 	})
 
-	client := &Layer0Client{API: mockClient}
+	client := &Layer0Client{API: mockClient, StopContext: context.Background()}
 	if err := loadBalancerResource.Create(d, client); err != nil {
 		t.Fatal(err)
 	}
@@ -68,7 +76,15 @@ func TestLoadBalancerCreate_specifyPorts(t *testing.T) {
 
 	mockClient.EXPECT().
 		CreateLoadBalancer("test-lb", "test-env", models.HealthCheck{"TCP:80", 30, 5, 2, 2}, ports, false, 60, true).
-		Return(&models.LoadBalancer{LoadBalancerID: "lbid"}, nil)
+		Return("jid", nil)
+
+	mockClient.EXPECT().
+		WaitForJob("jid", gomock.Any()).
+		Return(nil)
+
+	mockClient.EXPECT().
+		GetJob("jid").
+		Return(&models.Job{Meta: map[string]string{"load_balancer_id": "lbid"}}, nil)
 
 	mockClient.EXPECT().
 		GetLoadBalancer("lbid").
@@ -82,7 +98,7 @@ func TestLoadBalancerCreate_specifyPorts(t *testing.T) {
 		"private":     true,
 	})
 
-	client := &Layer0Client{API: mockClient}
+	client := &Layer0Client{API: mockClient, StopContext: context.Background()}
 	if err := loadBalancerResource.Create(d, client); err != nil {
 		t.Fatal(err)
 	}
@@ -94,7 +110,15 @@ func TestLoadBalancerCreate_specifyHealthCheck(t *testing.T) {
 
 	mockClient.EXPECT().
 		CreateLoadBalancer("test-lb", "test-env", models.HealthCheck{"HTTP:80/admin/healthcheck", 25, 10, 4, 3}, []models.Port{}, true, 60, true).
-		Return(&models.LoadBalancer{LoadBalancerID: "lbid"}, nil)
+		Return("jid", nil)
+
+	mockClient.EXPECT().
+		WaitForJob("jid", gomock.Any()).
+		Return(nil)
+
+	mockClient.EXPECT().
+		GetJob("jid").
+		Return(&models.Job{Meta: map[string]string{"load_balancer_id": "lbid"}}, nil)
 
 	mockClient.EXPECT().
 		GetLoadBalancer("lbid").
@@ -113,7 +137,7 @@ func TestLoadBalancerCreate_specifyHealthCheck(t *testing.T) {
 		}),
 	})
 
-	client := &Layer0Client{API: mockClient}
+	client := &Layer0Client{API: mockClient, StopContext: context.Background()}
 	if err := loadBalancerResource.Create(d, client); err != nil {
 		t.Fatal(err)
 	}
@@ -125,7 +149,15 @@ func TestLoadBalancerCreate_specifyCrossZone(t *testing.T) {
 
 	mockClient.EXPECT().
 		CreateLoadBalancer("test-lb", "test-env", models.HealthCheck{"TCP:80", 30, 5, 2, 2}, []models.Port{}, true, 60, false).
-		Return(&models.LoadBalancer{LoadBalancerID: "lbid"}, nil)
+		Return("jid", nil)
+
+	mockClient.EXPECT().
+		WaitForJob("jid", gomock.Any()).
+		Return(nil)
+
+	mockClient.EXPECT().
+		GetJob("jid").
+		Return(&models.Job{Meta: map[string]string{"load_balancer_id": "lbid"}}, nil)
 
 	mockClient.EXPECT().
 		GetLoadBalancer("lbid").
@@ -138,7 +170,7 @@ func TestLoadBalancerCreate_specifyCrossZone(t *testing.T) {
 		"cross_zone":  false,
 	})
 
-	client := &Layer0Client{API: mockClient}
+	client := &Layer0Client{API: mockClient, StopContext: context.Background()}
 	if err := loadBalancerResource.Create(d, client); err != nil {
 		t.Fatal(err)
 	}
@@ -169,23 +201,31 @@ func TestLoadBalancerUpdate_ports(t *testing.T) {
 	gomock.InOrder(
 		mockClient.EXPECT().
 			CreateLoadBalancer("test-lb", "test-env", models.HealthCheck{"TCP:80", 30, 5, 2, 2}, []models.Port{}, true, 60, true).
-			Return(&models.LoadBalancer{LoadBalancerID: "lbid"}, nil),
+			Return("jid", nil),
+
+		mockClient.EXPECT().
+			WaitForJob("jid", gomock.Any()).
+			Return(nil),
+
+		mockClient.EXPECT().
+			GetJob("jid").
+			Return(&models.Job{Meta: map[string]string{"load_balancer_id": "lbid"}}, nil),
 
 		mockClient.EXPECT().
 			GetLoadBalancer("lbid").
 			Return(&models.LoadBalancer{LoadBalancerID: "lbid"}, nil),
 
 		mockClient.EXPECT().
-			UpdateLoadBalancerPorts("lbid", []models.Port{{"", "", 80, 80, "http"}}).
+			UpdateLoadBalancerPorts("lbid", []models.Port{{"", "", 80, 80, "http"}}, anyVersion).
 			Return(&models.LoadBalancer{LoadBalancerID: "lbid"}, nil),
 
 		// The Idle Timeout is set when the load balancer is first created
 		mockClient.EXPECT().
-			UpdateLoadBalancerIdleTimeout("lbid", 60),
+			UpdateLoadBalancerIdleTimeout("lbid", 60, anyVersion),
 
 		// Cross-Zone Load Balancing is set when the load balancer is first created.
 		mockClient.EXPECT().
-			UpdateLoadBalancerCrossZone("lbid", true),
+			UpdateLoadBalancerCrossZone("lbid", true, anyVersion),
 
 		mockClient.EXPECT().
 			GetLoadBalancer("lbid").
@@ -212,7 +252,7 @@ func TestLoadBalancerUpdate_ports(t *testing.T) {
 
 	d2.SetId("lbid")
 
-	client := &Layer0Client{API: mockClient}
+	client := &Layer0Client{API: mockClient, StopContext: context.Background()}
 	if err := loadBalancerResource.Create(d1, client); err != nil {
 		t.Fatal(err)
 	}
@@ -229,23 +269,31 @@ func TestLoadBalancerUpdate_healthCheck(t *testing.T) {
 	gomock.InOrder(
 		mockClient.EXPECT().
 			CreateLoadBalancer("test-lb", "test-env", models.HealthCheck{"TCP:80", 30, 5, 2, 2}, []models.Port{}, true, 60, true).
-			Return(&models.LoadBalancer{LoadBalancerID: "lbid"}, nil),
+			Return("jid", nil),
+
+		mockClient.EXPECT().
+			WaitForJob("jid", gomock.Any()).
+			Return(nil),
+
+		mockClient.EXPECT().
+			GetJob("jid").
+			Return(&models.Job{Meta: map[string]string{"load_balancer_id": "lbid"}}, nil),
 
 		mockClient.EXPECT().
 			GetLoadBalancer("lbid").
 			Return(&models.LoadBalancer{LoadBalancerID: "lbid"}, nil),
 
 		mockClient.EXPECT().
-			UpdateLoadBalancerHealthCheck("lbid", models.HealthCheck{"HTTP:80/admin/healthcheck", 25, 10, 4, 3}).
+			UpdateLoadBalancerHealthCheck("lbid", models.HealthCheck{"HTTP:80/admin/healthcheck", 25, 10, 4, 3}, anyVersion).
 			Return(&models.LoadBalancer{LoadBalancerID: "lbid"}, nil),
 
 		// The Idle Timeout is set when the load balancer is first created
 		mockClient.EXPECT().
-			UpdateLoadBalancerIdleTimeout("lbid", 60),
+			UpdateLoadBalancerIdleTimeout("lbid", 60, anyVersion),
 
 		// Cross-Zone Load Balancing is set when the load balancer is first created.
 		mockClient.EXPECT().
-			UpdateLoadBalancerCrossZone("lbid", true),
+			UpdateLoadBalancerCrossZone("lbid", true, anyVersion),
 
 		mockClient.EXPECT().
 			GetLoadBalancer("lbid").
@@ -272,7 +320,7 @@ func TestLoadBalancerUpdate_healthCheck(t *testing.T) {
 
 	d2.SetId("lbid")
 
-	client := &Layer0Client{API: mockClient}
+	client := &Layer0Client{API: mockClient, StopContext: context.Background()}
 	if err := loadBalancerResource.Create(d1, client); err != nil {
 		t.Fatal(err)
 	}
@@ -289,19 +337,26 @@ func TestLoadBalancerUpdate_idleTimeout(t *testing.T) {
 	gomock.InOrder(
 		mockClient.EXPECT().
 			CreateLoadBalancer("test-lb", "test-env", models.HealthCheck{"TCP:80", 30, 5, 2, 2}, []models.Port{}, true, 95, true).
-			Return(&models.LoadBalancer{
-				LoadBalancerID: "lbid"}, nil),
+			Return("jid", nil),
+
+		mockClient.EXPECT().
+			WaitForJob("jid", gomock.Any()).
+			Return(nil),
+
+		mockClient.EXPECT().
+			GetJob("jid").
+			Return(&models.Job{Meta: map[string]string{"load_balancer_id": "lbid"}}, nil),
 
 		mockClient.EXPECT().
 			GetLoadBalancer("lbid").
 			Return(&models.LoadBalancer{LoadBalancerID: "lbid"}, nil),
 
 		mockClient.EXPECT().
-			UpdateLoadBalancerIdleTimeout("lbid", 120).
+			UpdateLoadBalancerIdleTimeout("lbid", 120, anyVersion).
 			Return(&models.LoadBalancer{LoadBalancerID: "lbid", IdleTimeout: 120}, nil),
 
 		mockClient.EXPECT().
-			UpdateLoadBalancerCrossZone("lbid", true),
+			UpdateLoadBalancerCrossZone("lbid", true, anyVersion),
 
 		mockClient.EXPECT().
 			GetLoadBalancer("lbid").
@@ -323,7 +378,7 @@ func TestLoadBalancerUpdate_idleTimeout(t *testing.T) {
 
 	d2.SetId("lbid")
 
-	client := &Layer0Client{API: mockClient}
+	client := &Layer0Client{API: mockClient, StopContext: context.Background()}
 	if err := loadBalancerResource.Create(d1, client); err != nil {
 		t.Fatal(err)
 	}
@@ -340,14 +395,22 @@ func TestLoadBalancerUpdate_crossZone(t *testing.T) {
 	gomock.InOrder(
 		mockClient.EXPECT().
 			CreateLoadBalancer("test-lb", "test-env", models.HealthCheck{"TCP:80", 30, 5, 2, 2}, []models.Port{}, true, 60, true).
-			Return(&models.LoadBalancer{LoadBalancerID: "lbid"}, nil),
+			Return("jid", nil),
+
+		mockClient.EXPECT().
+			WaitForJob("jid", gomock.Any()).
+			Return(nil),
+
+		mockClient.EXPECT().
+			GetJob("jid").
+			Return(&models.Job{Meta: map[string]string{"load_balancer_id": "lbid"}}, nil),
 
 		mockClient.EXPECT().
 			GetLoadBalancer("lbid").
 			Return(&models.LoadBalancer{LoadBalancerID: "lbid"}, nil),
 
 		mockClient.EXPECT().
-			UpdateLoadBalancerIdleTimeout("lbid", 60),
+			UpdateLoadBalancerIdleTimeout("lbid", 60, anyVersion),
 
 		mockClient.EXPECT().
 			GetLoadBalancer("lbid").
@@ -369,7 +432,7 @@ func TestLoadBalancerUpdate_crossZone(t *testing.T) {
 
 	d2.SetId("lbid")
 
-	client := &Layer0Client{API: mockClient}
+	client := &Layer0Client{API: mockClient, StopContext: context.Background()}
 	if err := loadBalancerResource.Create(d1, client); err != nil {
 		t.Fatal(err)
 	}
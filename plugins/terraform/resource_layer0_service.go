@@ -63,7 +63,12 @@ func resourceLayer0ServiceCreate(d *schema.ResourceData, meta interface{}) error
 	d.SetId(service.ServiceID)
 
 	if scale != 1 {
-		if _, err := client.API.ScaleService(service.ServiceID, scale); err != nil {
+		jobID, err := client.API.ScaleService(service.ServiceID, scale, service.Version)
+		if err != nil {
+			return err
+		}
+
+		if err := waitForJobWithContext(client, jobID); err != nil {
 			return err
 		}
 	}
@@ -111,7 +116,12 @@ func resourceLayer0ServiceUpdate(d *schema.ResourceData, meta interface{}) error
 	if d.HasChange("deploy") {
 		deployID := d.Get("deploy").(string)
 
-		if _, err := client.API.UpdateService(serviceID, deployID); err != nil {
+		jobID, err := client.API.UpdateService(serviceID, deployID, anyVersion)
+		if err != nil {
+			return err
+		}
+
+		if err := waitForJobWithContext(client, jobID); err != nil {
 			return err
 		}
 	}
@@ -119,7 +129,12 @@ func resourceLayer0ServiceUpdate(d *schema.ResourceData, meta interface{}) error
 	if d.HasChange("scale") {
 		scale := d.Get("scale").(int)
 
-		if _, err := client.API.ScaleService(serviceID, scale); err != nil {
+		jobID, err := client.API.ScaleService(serviceID, scale, anyVersion)
+		if err != nil {
+			return err
+		}
+
+		if err := waitForJobWithContext(client, jobID); err != nil {
 			return err
 		}
 	}
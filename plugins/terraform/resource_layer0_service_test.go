@@ -47,8 +47,12 @@ func TestServiceCreate_specifyOptional(t *testing.T) {
 		Return(&models.Service{ServiceID: "sid"}, nil)
 
 	mockClient.EXPECT().
-		ScaleService("sid", 2).
-		Return(&models.Service{ServiceID: "sid"}, nil)
+		ScaleService("sid", 2, int64(0)).
+		Return("jid", nil)
+
+	mockClient.EXPECT().
+		WaitForJob("jid", gomock.Any()).
+		Return(nil)
 
 	mockClient.EXPECT().
 		GetService("sid").
@@ -104,12 +108,20 @@ func TestServiceUpdate(t *testing.T) {
 		Return(&models.Service{}, nil)
 
 	mockClient.EXPECT().
-		UpdateService("sid", "test-dep2").
-		Return(&models.Service{ServiceID: "sid"}, nil)
+		UpdateService("sid", "test-dep2", anyVersion).
+		Return("jid", nil)
 
 	mockClient.EXPECT().
-		ScaleService("sid", 2).
-		Return(&models.Service{ServiceID: "sid"}, nil)
+		WaitForJob("jid", gomock.Any()).
+		Return(nil)
+
+	mockClient.EXPECT().
+		ScaleService("sid", 2, anyVersion).
+		Return("jid", nil)
+
+	mockClient.EXPECT().
+		WaitForJob("jid", gomock.Any()).
+		Return(nil)
 
 	mockClient.EXPECT().
 		WaitForDeployment("sid", gomock.Any()).
@@ -81,6 +81,26 @@ func waitForJobWithContext(client *Layer0Client, jobID string) error {
 	}
 }
 
+// waitForJobMeta waits for the job to complete and returns the specified value from the job's meta,
+// e.g. the id of the entity the job created
+func waitForJobMeta(client *Layer0Client, jobID, key string) (string, error) {
+	if err := waitForJobWithContext(client, jobID); err != nil {
+		return "", err
+	}
+
+	job, err := client.API.GetJob(jobID)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := job.Meta[key]
+	if !ok {
+		return "", fmt.Errorf("Job '%s' completed without setting '%s'", jobID, key)
+	}
+
+	return value, nil
+}
+
 func waitForDeploymentWithContext(client *Layer0Client, serviceID string) error {
 	result := make(chan error, 1)
 	go func() {
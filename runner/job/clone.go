@@ -0,0 +1,487 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/quintilesims/layer0/common/db/tag_store"
+	"github.com/quintilesims/layer0/common/models"
+)
+
+var CloneEnvironmentSteps = []Step{
+	{
+		Name:    "Create Environment",
+		Timeout: time.Minute * 15,
+		Action:  CloneEnvironment,
+	},
+	{
+		Name:    "Create Load Balancers",
+		Timeout: time.Minute * 15,
+		Action:  CloneEnvironmentLoadBalancers,
+	},
+	{
+		Name:    "Create Services",
+		Timeout: time.Minute * 15,
+		Action:  CloneEnvironmentServices,
+	},
+	{
+		Name:    "Scale Services",
+		Timeout: time.Minute * 10,
+		Action:  ScaleClonedServices,
+	},
+	{
+		Name:    "Copy Links and Tags",
+		Timeout: time.Minute * 10,
+		Action:  CopyEnvironmentLinksAndTags,
+	},
+}
+
+// clonedTagSkippedKeys are the keys of tags that reference the source's entities;
+// the copies get their own values for these keys when they are created, or none
+var clonedTagSkippedKeys = map[string]bool{
+	createdByJobTagKey: true,
+	"environment_id":   true,
+	"link":             true,
+	"load_balancer_id": true,
+}
+
+// cloneRun is a run of a CloneEnvironment job. The ids of the copies created by the job
+// are stored in the job's meta as '<entity type>:<source id>', so a job that is retried
+// does not create the same copy twice. Each copy is marked with the job's id when it is created,
+// so a copy that was created but not recorded before the job failed is adopted instead of
+// creating another. Entities that other callers created with the same names are never adopted
+type cloneRun struct {
+	context *JobContext
+	req     models.CloneEnvironmentRequest
+	meta    map[string]string
+}
+
+func loadCloneRun(context *JobContext) (*cloneRun, error) {
+	var req models.CloneEnvironmentRequest
+	if err := json.Unmarshal([]byte(context.Request()), &req); err != nil {
+		return nil, err
+	}
+
+	job, err := context.Logic.JobStore.SelectByID(context.jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := map[string]string{}
+	for key, val := range job.Meta {
+		meta[key] = val
+	}
+
+	return &cloneRun{
+		context: context,
+		req:     req,
+		meta:    meta,
+	}, nil
+}
+
+func cloneMetaKey(entityType, sourceID string) string {
+	return fmt.Sprintf("%s:%s", entityType, sourceID)
+}
+
+func (c *cloneRun) environmentID() (string, error) {
+	environmentID, ok := c.meta["environment_id"]
+	if !ok {
+		return "", fmt.Errorf("The copy of environment '%s' has not been created", c.req.SourceEnvironmentID)
+	}
+
+	return environmentID, nil
+}
+
+func (c *cloneRun) copyID(entityType, sourceID string) (string, bool) {
+	copyID, ok := c.meta[cloneMetaKey(entityType, sourceID)]
+	return copyID, ok
+}
+
+func (c *cloneRun) recordCopy(quit chan bool, entityType, sourceID, copyID string) error {
+	key := cloneMetaKey(entityType, sourceID)
+	if err := runAndRetry(quit, c.context.RetryPolicy, func() error {
+		return c.context.AddJobMeta(key, copyID)
+	}); err != nil {
+		return err
+	}
+
+	c.meta[key] = copyID
+	return nil
+}
+
+// existingCopy returns the id of the load balancer or service with the given name
+// that this job created in the new environment
+func (c *cloneRun) existingCopy(entityType, environmentID, name string) (string, bool, error) {
+	return c.context.CreatedEntityID(entityType, map[string]string{"name": name, "environment_id": environmentID})
+}
+
+func (c *cloneRun) markCopy(quit chan bool, entityType, copyID string) error {
+	return runAndRetry(quit, c.context.RetryPolicy, func() error {
+		return c.context.MarkCreatedEntity(entityType, copyID)
+	})
+}
+
+func (c *cloneRun) loadBalancerName(sourceName string) string {
+	if name, ok := c.req.LoadBalancerNames[sourceName]; ok {
+		return name
+	}
+
+	return sourceName
+}
+
+func (c *cloneRun) serviceName(sourceName string) string {
+	if name, ok := c.req.ServiceNames[sourceName]; ok {
+		return name
+	}
+
+	return sourceName
+}
+
+func (c *cloneRun) sourceLoadBalancers() ([]*models.LoadBalancer, error) {
+	summaries, err := c.context.LoadBalancerLogic.ListLoadBalancers()
+	if err != nil {
+		return nil, err
+	}
+
+	loadBalancers := []*models.LoadBalancer{}
+	for _, summary := range summaries {
+		if summary.EnvironmentID != c.req.SourceEnvironmentID {
+			continue
+		}
+
+		loadBalancer, err := c.context.LoadBalancerLogic.GetLoadBalancer(summary.LoadBalancerID)
+		if err != nil {
+			return nil, err
+		}
+
+		loadBalancers = append(loadBalancers, loadBalancer)
+	}
+
+	return loadBalancers, nil
+}
+
+func (c *cloneRun) sourceServices() ([]*models.Service, error) {
+	return c.context.ServiceLogic.GetEnvironmentServices(c.req.SourceEnvironmentID)
+}
+
+// CloneEnvironment creates an environment with the instance size, os, ami, placement strategy
+// and scaler settings of the source environment. The environment's min cluster count is not
+// copied; the scaler sizes the new environment for the services that are copied into it.
+// The later steps of the clone read the new environment's id from 'environment_id'
+func CloneEnvironment(quit chan bool, context *JobContext) error {
+	c, err := loadCloneRun(context)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.environmentID(); err == nil {
+		log.Infof("Environment '%s' has already been copied", c.req.SourceEnvironmentID)
+		return nil
+	}
+
+	source, err := context.EnvironmentLogic.GetEnvironment(c.req.SourceEnvironmentID)
+	if err != nil {
+		return err
+	}
+
+	req := models.CreateEnvironmentRequest{
+		EnvironmentName:   c.req.EnvironmentName,
+		InstanceSize:      source.InstanceSize,
+		OperatingSystem:   source.OperatingSystem,
+		AMIID:             source.AMIID,
+		PlacementStrategy: source.PlacementStrategy,
+		ScalerSettings:    source.ScalerSettings,
+	}
+
+	environmentID, err := createOrAdoptEnvironment(quit, context, req)
+	if err != nil {
+		return err
+	}
+
+	return runAndRetry(quit, context.RetryPolicy, func() error {
+		if err := context.SetJobEntity("environment", environmentID); err != nil {
+			return err
+		}
+
+		return context.AddJobMeta("environment_id", environmentID)
+	})
+}
+
+// CloneEnvironmentLoadBalancers copies each load balancer of the source environment,
+// with its ports, health check, idle timeout and cross zone setting, into the new environment
+func CloneEnvironmentLoadBalancers(quit chan bool, context *JobContext) error {
+	c, err := loadCloneRun(context)
+	if err != nil {
+		return err
+	}
+
+	environmentID, err := c.environmentID()
+	if err != nil {
+		return err
+	}
+
+	loadBalancers, err := c.sourceLoadBalancers()
+	if err != nil {
+		return err
+	}
+
+	for _, source := range loadBalancers {
+		if _, ok := c.copyID("load_balancer", source.LoadBalancerID); ok {
+			continue
+		}
+
+		req := models.CreateLoadBalancerRequest{
+			LoadBalancerName: c.loadBalancerName(source.LoadBalancerName),
+			EnvironmentID:    environmentID,
+			IsPublic:         source.IsPublic,
+			Ports:            source.Ports,
+			HealthCheck:      source.HealthCheck,
+			IdleTimeout:      source.IdleTimeout,
+			CrossZone:        source.CrossZone,
+		}
+
+		copyID, ok, err := c.existingCopy("load_balancer", environmentID, req.LoadBalancerName)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			log.Infof("Load balancer '%s' already exists as '%s'", req.LoadBalancerName, copyID)
+		} else {
+			log.Infof("Running Action: CloneLoadBalancer '%s' as '%s'", source.LoadBalancerID, req.LoadBalancerName)
+			loadBalancer, err := context.LoadBalancerLogic.CreateLoadBalancer(req)
+			if err != nil {
+				return err
+			}
+
+			copyID = loadBalancer.LoadBalancerID
+			if err := c.markCopy(quit, "load_balancer", copyID); err != nil {
+				return err
+			}
+		}
+
+		if err := c.recordCopy(quit, "load_balancer", source.LoadBalancerID, copyID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CloneEnvironmentServices copies each service of the source environment into the new environment.
+// Each copy runs the source's current deploy and uses the copy of the source's load balancer
+func CloneEnvironmentServices(quit chan bool, context *JobContext) error {
+	c, err := loadCloneRun(context)
+	if err != nil {
+		return err
+	}
+
+	environmentID, err := c.environmentID()
+	if err != nil {
+		return err
+	}
+
+	services, err := c.sourceServices()
+	if err != nil {
+		return err
+	}
+
+	for _, source := range services {
+		if _, ok := c.copyID("service", source.ServiceID); ok {
+			continue
+		}
+
+		deployID, err := currentDeployID(source)
+		if err != nil {
+			return err
+		}
+
+		var loadBalancerID string
+		if source.LoadBalancerID != "" {
+			copyID, ok := c.copyID("load_balancer", source.LoadBalancerID)
+			if !ok {
+				return fmt.Errorf("Load balancer '%s' of service '%s' has not been copied", source.LoadBalancerID, source.ServiceID)
+			}
+
+			loadBalancerID = copyID
+		}
+
+		req := models.CreateServiceRequest{
+			DeployID:       deployID,
+			EnvironmentID:  environmentID,
+			LoadBalancerID: loadBalancerID,
+			ServiceName:    c.serviceName(source.ServiceName),
+		}
+
+		copyID, ok, err := c.existingCopy("service", environmentID, req.ServiceName)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			log.Infof("Service '%s' already exists as '%s'", req.ServiceName, copyID)
+		} else {
+			log.Infof("Running Action: CloneService '%s' as '%s'", source.ServiceID, req.ServiceName)
+			service, err := context.ServiceLogic.CreateService(req)
+			if err != nil {
+				return err
+			}
+
+			copyID = service.ServiceID
+			if err := c.markCopy(quit, "service", copyID); err != nil {
+				return err
+			}
+		}
+
+		if err := c.recordCopy(quit, "service", source.ServiceID, copyID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// currentDeployID returns the deploy of the service's primary deployment
+func currentDeployID(service *models.Service) (string, error) {
+	for _, deployment := range service.Deployments {
+		if deployment.Status == "PRIMARY" {
+			return deployment.DeployID, nil
+		}
+	}
+
+	return "", fmt.Errorf("Service '%s' has no primary deployment", service.ServiceID)
+}
+
+// ScaleClonedServices scales each copied service to the desired count of its source,
+// or to the count in the request's scale overrides, and schedules a run of the scaler on the new environment
+func ScaleClonedServices(quit chan bool, context *JobContext) error {
+	c, err := loadCloneRun(context)
+	if err != nil {
+		return err
+	}
+
+	environmentID, err := c.environmentID()
+	if err != nil {
+		return err
+	}
+
+	services, err := c.sourceServices()
+	if err != nil {
+		return err
+	}
+
+	for _, source := range services {
+		serviceID, ok := c.copyID("service", source.ServiceID)
+		if !ok {
+			return fmt.Errorf("Service '%s' has not been copied", source.ServiceID)
+		}
+
+		desiredCount := source.DesiredCount
+		if count, ok := c.req.Scale[source.ServiceName]; ok {
+			desiredCount = count
+		}
+
+		if err := runAndRetry(quit, context.RetryPolicy, func() error {
+			log.Infof("Running Action: ScaleService on '%s' to %d", serviceID, desiredCount)
+			_, err := context.ServiceLogic.ScaleService(serviceID, int(desiredCount), tag_store.AnyVersion)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	// the leader makes the run, so it doesn't race the leader's own runs on the new environment
+	log.Infof("Running Action: Schedule a scaler run for environment '%s'", environmentID)
+	context.Logic.Scaler.ScheduleRun(environmentID, 0)
+
+	return nil
+}
+
+// CopyEnvironmentLinksAndTags links the new environment to the environments the source is linked to,
+// and copies the tags of the source's entities whose keys their copies don't have, e.g. user tags
+func CopyEnvironmentLinksAndTags(quit chan bool, context *JobContext) error {
+	c, err := loadCloneRun(context)
+	if err != nil {
+		return err
+	}
+
+	environmentID, err := c.environmentID()
+	if err != nil {
+		return err
+	}
+
+	source, err := context.EnvironmentLogic.GetEnvironment(c.req.SourceEnvironmentID)
+	if err != nil {
+		return err
+	}
+
+	environment, err := context.EnvironmentLogic.GetEnvironment(environmentID)
+	if err != nil {
+		return err
+	}
+
+	linked := map[string]bool{}
+	for _, link := range environment.Links {
+		linked[link] = true
+	}
+
+	for _, link := range source.Links {
+		if linked[link] {
+			continue
+		}
+
+		log.Infof("Running Action: CreateEnvironmentLink from '%s' to '%s'", environmentID, link)
+		if err := context.EnvironmentLogic.CreateEnvironmentLink(environmentID, link); err != nil {
+			return err
+		}
+	}
+
+	if err := c.copyTags(quit, "environment", c.req.SourceEnvironmentID, environmentID); err != nil {
+		return err
+	}
+
+	for key, copyID := range c.meta {
+		parts := strings.SplitN(key, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		if err := c.copyTags(quit, parts[0], parts[1], copyID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *cloneRun) copyTags(quit chan bool, entityType, sourceID, copyID string) error {
+	tagStore := c.context.Logic.TagStore
+
+	sourceTags, err := tagStore.SelectByTypeAndID(entityType, sourceID)
+	if err != nil {
+		return err
+	}
+
+	copyTags, err := tagStore.SelectByTypeAndID(entityType, copyID)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range sourceTags {
+		if clonedTagSkippedKeys[tag.Key] || len(copyTags.WithKey(tag.Key)) > 0 {
+			continue
+		}
+
+		tag.EntityID = copyID
+		if err := runAndRetry(quit, c.context.RetryPolicy, func() error {
+			return tagStore.Insert(tag)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,352 @@
+package job
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/quintilesims/layer0/api/backend/mock_backend"
+	"github.com/quintilesims/layer0/api/logic"
+	"github.com/quintilesims/layer0/api/scheduler/mock_scheduler"
+	"github.com/quintilesims/layer0/common/db/job_store"
+	"github.com/quintilesims/layer0/common/db/tag_store"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
+	"github.com/quintilesims/layer0/common/types"
+)
+
+var (
+	clonePorts       = []models.Port{{HostPort: 80, ContainerPort: 8080, Protocol: "http"}}
+	cloneHealthCheck = models.HealthCheck{Target: "HTTP:8080/health", Interval: 30}
+)
+
+// newCloneLogic returns logic for a source environment 'e1' named 'prod', which is linked to 'e9'.
+// It has the load balancer 'l1' named 'api', used by the service 's1' named 'api',
+// and the service 's2' named 'worker'
+func newCloneLogic(ctrl *gomock.Controller) (*logic.Logic, *mock_backend.MockBackend) {
+	mockBackend := mock_backend.NewMockBackend(ctrl)
+	mockScaler := mock_scheduler.NewMockEnvironmentScaler(ctrl)
+	mockScaler.EXPECT().
+		ScheduleRun(gomock.Any(), gomock.Any()).
+		AnyTimes()
+
+	tagStore := tag_store.NewMemoryTagStore()
+	for _, tag := range []models.Tag{
+		{EntityID: "e1", EntityType: "environment", Key: "name", Value: "prod"},
+		{EntityID: "e1", EntityType: "environment", Key: "os", Value: "linux"},
+		{EntityID: "e1", EntityType: "environment", Key: "placement_strategy", Value: "spread-zone"},
+		{EntityID: "e1", EntityType: "environment", Key: "link", Value: "e9"},
+		{EntityID: "e1", EntityType: "environment", Key: "team", Value: "payments"},
+		{EntityID: "e9", EntityType: "environment", Key: "link", Value: "e1"},
+		{EntityID: "l1", EntityType: "load_balancer", Key: "name", Value: "api"},
+		{EntityID: "l1", EntityType: "load_balancer", Key: "environment_id", Value: "e1"},
+		{EntityID: "l1", EntityType: "load_balancer", Key: "team", Value: "payments"},
+		{EntityID: "l9", EntityType: "load_balancer", Key: "name", Value: "other"},
+		{EntityID: "l9", EntityType: "load_balancer", Key: "environment_id", Value: "e9"},
+		{EntityID: "s1", EntityType: "service", Key: "name", Value: "api"},
+		{EntityID: "s1", EntityType: "service", Key: "environment_id", Value: "e1"},
+		{EntityID: "s1", EntityType: "service", Key: "load_balancer_id", Value: "l1"},
+		{EntityID: "s2", EntityType: "service", Key: "name", Value: "worker"},
+		{EntityID: "s2", EntityType: "service", Key: "environment_id", Value: "e1"},
+	} {
+		tagStore.Insert(tag)
+	}
+
+	mockBackend.EXPECT().
+		GetEnvironment("e1").
+		Return(&models.Environment{EnvironmentID: "e1", InstanceSize: "m3.large", AMIID: "ami"}, nil).
+		AnyTimes()
+
+	mockBackend.EXPECT().
+		ListLoadBalancers().
+		Return([]*models.LoadBalancer{{LoadBalancerID: "l1"}, {LoadBalancerID: "l9"}}, nil).
+		AnyTimes()
+
+	mockBackend.EXPECT().
+		GetLoadBalancer("l1").
+		Return(&models.LoadBalancer{
+			LoadBalancerID: "l1",
+			IsPublic:       true,
+			Ports:          clonePorts,
+			HealthCheck:    cloneHealthCheck,
+			IdleTimeout:    120,
+			CrossZone:      true,
+		}, nil).
+		AnyTimes()
+
+	mockBackend.EXPECT().
+		GetEnvironmentServices("e1").
+		Return([]*models.Service{
+			{
+				ServiceID:    "s1",
+				DesiredCount: 3,
+				Deployments: []models.Deployment{
+					{DeployID: "d0", Status: "ACTIVE"},
+					{DeployID: "d1", Status: "PRIMARY"},
+				},
+			},
+			{
+				ServiceID:    "s2",
+				DesiredCount: 2,
+				Deployments:  []models.Deployment{{DeployID: "d2", Status: "PRIMARY"}},
+			},
+		}, nil).
+		AnyTimes()
+
+	mockBackend.EXPECT().
+		GetEnvironment("e2").
+		Return(&models.Environment{EnvironmentID: "e2"}, nil).
+		AnyTimes()
+
+	lgc := logic.NewLogic(tagStore, job_store.NewMemoryJobStore(), mockBackend, mockScaler)
+	return lgc, mockBackend
+}
+
+func runCloneJob(t *testing.T, lgc *logic.Logic, req models.CloneEnvironmentRequest, meta map[string]string) (*models.Job, error) {
+	request, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lgc.JobStore.Insert(&models.Job{
+		JobID:   "c1",
+		JobType: int64(types.CloneEnvironmentJob),
+		Request: string(request),
+		Meta:    meta,
+	})
+
+	runner := NewJobRunner(lgc, "c1")
+	if err := runner.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	runErr := runner.Run()
+	job, err := lgc.JobStore.SelectByID("c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return job, runErr
+}
+
+func TestCloneEnvironment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lgc, mockBackend := newCloneLogic(ctrl)
+
+	gomock.InOrder(
+		mockBackend.EXPECT().
+			CreateEnvironment("staging", "m3.large", "linux", "ami", 0, []byte(nil)).
+			Return(&models.Environment{EnvironmentID: "e2"}, nil),
+		mockBackend.EXPECT().
+			CreateLoadBalancer("staging-api", "e2", true, clonePorts, cloneHealthCheck, 120, true).
+			Return(&models.LoadBalancer{LoadBalancerID: "l2", EnvironmentID: "e2"}, nil),
+		mockBackend.EXPECT().
+			CreateService("api", "e2", "d1", "l2").
+			Return(&models.Service{ServiceID: "s3"}, nil),
+		mockBackend.EXPECT().
+			CreateService("worker", "e2", "d2", "").
+			Return(&models.Service{ServiceID: "s4"}, nil),
+		mockBackend.EXPECT().
+			ScaleService("e2", "s3", 1).
+			Return(&models.Service{ServiceID: "s3"}, nil),
+		mockBackend.EXPECT().
+			ScaleService("e2", "s4", 2).
+			Return(&models.Service{ServiceID: "s4"}, nil),
+		mockBackend.EXPECT().
+			CreateEnvironmentLink("e2", "e9").
+			Return(nil),
+	)
+
+	req := models.CloneEnvironmentRequest{
+		SourceEnvironmentID: "e1",
+		EnvironmentName:     "staging",
+		LoadBalancerNames:   map[string]string{"api": "staging-api"},
+		Scale:               map[string]int64{"api": 1},
+	}
+
+	job, err := runCloneJob(t, lgc, req, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, job.JobStatus, int64(types.Completed))
+	testutils.AssertEqual(t, job.EntityType, "environment")
+	testutils.AssertEqual(t, job.EntityID, "e2")
+	testutils.AssertEqual(t, job.Meta, map[string]string{
+		"environment_id":   "e2",
+		"load_balancer:l1": "l2",
+		"service:s1":       "s3",
+		"service:s2":       "s4",
+	})
+
+	environmentTags, err := lgc.TagStore.SelectByTypeAndID("environment", "e2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, value := range map[string]string{
+		"name":               "staging",
+		"placement_strategy": "spread-zone",
+		"link":               "e9",
+		"team":               "payments",
+	} {
+		tag, ok := environmentTags.WithKey(key).First()
+		if !ok {
+			t.Fatalf("Environment tag '%s' was not written", key)
+		}
+
+		testutils.AssertEqual(t, tag.Value, value)
+	}
+
+	loadBalancerTags, err := lgc.TagStore.SelectByTypeAndID("load_balancer", "l2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(loadBalancerTags), 4)
+	testutils.AssertEqual(t, loadBalancerTags.WithKey(createdByJobTagKey)[0].Value, "c1")
+	testutils.AssertEqual(t, loadBalancerTags.WithKey("environment_id")[0].Value, "e2")
+	testutils.AssertEqual(t, loadBalancerTags.WithKey("team")[0].Value, "payments")
+}
+
+func TestCloneEnvironmentSkipsCopies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lgc, mockBackend := newCloneLogic(ctrl)
+
+	// the environment, load balancer and api service were copied by a previous run of the job
+	lgc.TagStore.Insert(models.Tag{EntityID: "e2", EntityType: "environment", Key: "name", Value: "staging"})
+	lgc.TagStore.Insert(models.Tag{EntityID: "l2", EntityType: "load_balancer", Key: "environment_id", Value: "e2"})
+	lgc.TagStore.Insert(models.Tag{EntityID: "s3", EntityType: "service", Key: "environment_id", Value: "e2"})
+
+	gomock.InOrder(
+		mockBackend.EXPECT().
+			CreateService("worker", "e2", "d2", "").
+			Return(&models.Service{ServiceID: "s4"}, nil),
+		mockBackend.EXPECT().
+			ScaleService("e2", "s3", 3).
+			Return(&models.Service{ServiceID: "s3"}, nil),
+		mockBackend.EXPECT().
+			ScaleService("e2", "s4", 2).
+			Return(&models.Service{ServiceID: "s4"}, nil),
+		mockBackend.EXPECT().
+			CreateEnvironmentLink("e2", "e9").
+			Return(nil),
+	)
+
+	req := models.CloneEnvironmentRequest{
+		SourceEnvironmentID: "e1",
+		EnvironmentName:     "staging",
+	}
+
+	meta := map[string]string{
+		"environment_id":   "e2",
+		"load_balancer:l1": "l2",
+		"service:s1":       "s3",
+	}
+
+	job, err := runCloneJob(t, lgc, req, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, job.JobStatus, int64(types.Completed))
+	testutils.AssertEqual(t, job.Meta["service:s2"], "s4")
+}
+
+func TestCloneEnvironmentAdoptsUnrecordedCopies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lgc, mockBackend := newCloneLogic(ctrl)
+
+	// a previous run of the job created the environment, load balancer and api service,
+	// but failed before it recorded them in the job's meta
+	for _, tag := range []models.Tag{
+		{EntityID: "e2", EntityType: "environment", Key: "name", Value: "staging"},
+		{EntityID: "e2", EntityType: "environment", Key: createdByJobTagKey, Value: "c1"},
+		{EntityID: "l2", EntityType: "load_balancer", Key: "name", Value: "api"},
+		{EntityID: "l2", EntityType: "load_balancer", Key: "environment_id", Value: "e2"},
+		{EntityID: "l2", EntityType: "load_balancer", Key: createdByJobTagKey, Value: "c1"},
+		{EntityID: "s3", EntityType: "service", Key: "name", Value: "api"},
+		{EntityID: "s3", EntityType: "service", Key: "environment_id", Value: "e2"},
+		{EntityID: "s3", EntityType: "service", Key: createdByJobTagKey, Value: "c1"},
+	} {
+		lgc.TagStore.Insert(tag)
+	}
+
+	gomock.InOrder(
+		mockBackend.EXPECT().
+			CreateService("worker", "e2", "d2", "").
+			Return(&models.Service{ServiceID: "s4"}, nil),
+		mockBackend.EXPECT().
+			ScaleService("e2", "s3", 3).
+			Return(&models.Service{ServiceID: "s3"}, nil),
+		mockBackend.EXPECT().
+			ScaleService("e2", "s4", 2).
+			Return(&models.Service{ServiceID: "s4"}, nil),
+		mockBackend.EXPECT().
+			CreateEnvironmentLink("e2", "e9").
+			Return(nil),
+	)
+
+	req := models.CloneEnvironmentRequest{
+		SourceEnvironmentID: "e1",
+		EnvironmentName:     "staging",
+	}
+
+	job, err := runCloneJob(t, lgc, req, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, job.JobStatus, int64(types.Completed))
+	testutils.AssertEqual(t, job.Meta, map[string]string{
+		"environment_id":   "e2",
+		"load_balancer:l1": "l2",
+		"service:s1":       "s3",
+		"service:s2":       "s4",
+	})
+}
+
+func TestCloneEnvironmentDoesNotAdoptAnotherCallersCopies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lgc, mockBackend := newCloneLogic(ctrl)
+
+	// a service named 'worker' was created in the new environment by another caller
+	for _, tag := range []models.Tag{
+		{EntityID: "s9", EntityType: "service", Key: "name", Value: "worker"},
+		{EntityID: "s9", EntityType: "service", Key: "environment_id", Value: "e2"},
+		{EntityID: "s9", EntityType: "service", Key: createdByJobTagKey, Value: "c0"},
+	} {
+		lgc.TagStore.Insert(tag)
+	}
+
+	mockBackend.EXPECT().
+		CreateService("api", "e2", "d1", "l2").
+		Return(&models.Service{ServiceID: "s3"}, nil)
+
+	req := models.CloneEnvironmentRequest{
+		SourceEnvironmentID: "e1",
+		EnvironmentName:     "staging",
+	}
+
+	meta := map[string]string{
+		"environment_id":   "e2",
+		"load_balancer:l1": "l2",
+	}
+
+	// the service can't be created with the same name, so the job fails rather than adopting it
+	job, err := runCloneJob(t, lgc, req, meta)
+	if err == nil {
+		t.Fatal("Error was nil!")
+	}
+
+	testutils.AssertEqual(t, job.JobStatus, int64(types.Error))
+	testutils.AssertEqual(t, job.Meta["service:s2"], "")
+}
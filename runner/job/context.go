@@ -2,8 +2,13 @@ package job
 
 import (
 	"github.com/quintilesims/layer0/api/logic"
+	"github.com/quintilesims/layer0/common/models"
 )
 
+// createdByJobTagKey is the key of the tag that marks the entities a job created.
+// A retried job only adopts entities that carry its own id, never another caller's
+const createdByJobTagKey = "created_by_job"
+
 type JobContext struct {
 	jobID             string
 	request           string
@@ -12,6 +17,8 @@ type JobContext struct {
 	ServiceLogic      logic.ServiceLogic
 	TaskLogic         logic.TaskLogic
 	EnvironmentLogic  logic.EnvironmentLogic
+	// RetryPolicy is the retry policy of the step being run
+	RetryPolicy RetryPolicy
 }
 
 func NewJobContext(jobID string, lgc *logic.Logic, request string) *JobContext {
@@ -23,6 +30,7 @@ func NewJobContext(jobID string, lgc *logic.Logic, request string) *JobContext {
 		ServiceLogic:      logic.NewL0ServiceLogic(*lgc),
 		TaskLogic:         logic.NewL0TaskLogic(*lgc),
 		EnvironmentLogic:  logic.NewL0EnvironmentLogic(*lgc),
+		RetryPolicy:       DefaultRetryPolicy,
 	}
 }
 
@@ -35,6 +43,7 @@ func (j *JobContext) CreateCopyWithNewRequest(request string) *JobContext {
 		ServiceLogic:      j.ServiceLogic,
 		TaskLogic:         j.TaskLogic,
 		EnvironmentLogic:  j.EnvironmentLogic,
+		RetryPolicy:       j.RetryPolicy,
 	}
 }
 
@@ -56,6 +65,53 @@ func (j *JobContext) AddJobMeta(key, val string) error {
 	return j.SetJobMeta(job.Meta)
 }
 
+// SetJobEntity records the entity the job targets, e.g. once the job has created it
+func (j *JobContext) SetJobEntity(entityType, entityID string) error {
+	return j.Logic.JobStore.SetJobEntity(j.jobID, entityType, entityID)
+}
+
 func (j *JobContext) Request() string {
 	return j.request
 }
+
+// JobMeta returns the value of the job's meta with the given key, if it is set
+func (j *JobContext) JobMeta(key string) (string, bool, error) {
+	job, err := j.Logic.JobStore.SelectByID(j.jobID)
+	if err != nil {
+		return "", false, err
+	}
+
+	val, ok := job.Meta[key]
+	return val, ok, nil
+}
+
+// MarkCreatedEntity tags an entity the job created with the job's id
+func (j *JobContext) MarkCreatedEntity(entityType, entityID string) error {
+	return j.Logic.TagStore.Insert(models.Tag{EntityType: entityType, EntityID: entityID, Key: createdByJobTagKey, Value: j.jobID})
+}
+
+// CreatedEntityID returns the id of an entity of the given type that was marked by this job
+// and has all of the given tags, if there is one
+func (j *JobContext) CreatedEntityID(entityType string, tags map[string]string) (string, bool, error) {
+	all, err := j.Logic.TagStore.SelectByType(entityType)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, marker := range all.WithKey(createdByJobTagKey).WithValue(j.jobID) {
+		entityTags := all.WithID(marker.EntityID)
+
+		match := true
+		for key, value := range tags {
+			if len(entityTags.WithKey(key).WithValue(value)) == 0 {
+				match = false
+			}
+		}
+
+		if match {
+			return marker.EntityID, true, nil
+		}
+	}
+
+	return "", false, nil
+}
@@ -1,11 +1,21 @@
 package job
 
 import (
+	"encoding/json"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/quintilesims/layer0/common/models"
 )
 
+var CreateEnvironmentSteps = []Step{
+	{
+		Name:    "Create Environment",
+		Timeout: time.Minute * 15,
+		Action:  CreateEnvironment,
+	},
+}
+
 var DeleteEnvironmentSteps = []Step{
 	{
 		Name:    "Delete Dependencies",
@@ -19,11 +29,67 @@ var DeleteEnvironmentSteps = []Step{
 	},
 }
 
+// CreateEnvironment is not retried since creating an environment is not idempotent.
+// The environment is marked with the job's id as soon as it is created, so if a previous run
+// of the job created it before it could be recorded, that environment is adopted instead of
+// creating another. The id of the new environment is stored in the job's meta as 'environment_id'
+func CreateEnvironment(quit chan bool, context *JobContext) error {
+	var req models.CreateEnvironmentRequest
+	if err := json.Unmarshal([]byte(context.Request()), &req); err != nil {
+		return err
+	}
+
+	environmentID, err := createOrAdoptEnvironment(quit, context, req)
+	if err != nil {
+		return err
+	}
+
+	return runAndRetry(quit, context.RetryPolicy, func() error {
+		if err := context.SetJobEntity("environment", environmentID); err != nil {
+			return err
+		}
+
+		return context.AddJobMeta("environment_id", environmentID)
+	})
+}
+
+// createOrAdoptEnvironment returns the id of the environment recorded in the job's meta,
+// or of the environment this job created but did not record, or creates the environment
+func createOrAdoptEnvironment(quit chan bool, context *JobContext, req models.CreateEnvironmentRequest) (string, error) {
+	if environmentID, ok, err := context.JobMeta("environment_id"); err != nil || ok {
+		return environmentID, err
+	}
+
+	environmentID, ok, err := context.CreatedEntityID("environment", map[string]string{"name": req.EnvironmentName})
+	if err != nil {
+		return "", err
+	}
+
+	if ok {
+		log.Infof("Environment '%s' was already created as '%s'", req.EnvironmentName, environmentID)
+		return environmentID, nil
+	}
+
+	log.Infof("Running Action: CreateEnvironment '%s'", req.EnvironmentName)
+	environment, err := context.EnvironmentLogic.CreateEnvironment(req)
+	if err != nil {
+		return "", err
+	}
+
+	if err := runAndRetry(quit, context.RetryPolicy, func() error {
+		return context.MarkCreatedEntity("environment", environment.EnvironmentID)
+	}); err != nil {
+		return "", err
+	}
+
+	return environment.EnvironmentID, nil
+}
+
 func DeleteEnvironment(quit chan bool, context *JobContext) error {
 	log.Infof("Running Action: DeleteEnvironment")
 	environmentID := context.Request()
 
-	return runAndRetry(quit, time.Second*10, func() error {
+	return runAndRetry(quit, context.RetryPolicy, func() error {
 		log.Infof("Running Action: DeleteEnvironment on '%s'", environmentID)
 		return context.EnvironmentLogic.DeleteEnvironment(environmentID)
 	})
@@ -0,0 +1,88 @@
+package job
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/quintilesims/layer0/api/logic"
+	"github.com/quintilesims/layer0/api/logic/mock_logic"
+	"github.com/quintilesims/layer0/common/db/job_store"
+	"github.com/quintilesims/layer0/common/db/tag_store"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+func newCreateEnvironmentContext(t *testing.T, tagStore tag_store.TagStore, jobStore job_store.JobStore) *JobContext {
+	request, err := json.Marshal(models.CreateEnvironmentRequest{EnvironmentName: "staging", OperatingSystem: "linux"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobStore.Insert(&models.Job{JobID: "j1"})
+	lgc := logic.NewLogic(tagStore, jobStore, nil, nil)
+	return NewJobContext("j1", lgc, string(request))
+}
+
+func TestCreateEnvironmentAdoptsMarkedEnvironment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tagStore := tag_store.NewMemoryTagStore()
+	tagStore.Insert(models.Tag{EntityID: "e1", EntityType: "environment", Key: "name", Value: "staging"})
+	tagStore.Insert(models.Tag{EntityID: "e1", EntityType: "environment", Key: createdByJobTagKey, Value: "j1"})
+
+	jobStore := job_store.NewMemoryJobStore()
+	context := newCreateEnvironmentContext(t, tagStore, jobStore)
+
+	// the environment is not expected to be created again
+	context.EnvironmentLogic = mock_logic.NewMockEnvironmentLogic(ctrl)
+
+	if err := CreateEnvironment(make(chan bool), context); err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := jobStore.SelectByID("j1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, job.Meta["environment_id"], "e1")
+}
+
+func TestCreateEnvironmentDoesNotAdoptAnotherCallersEnvironment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tagStore := tag_store.NewMemoryTagStore()
+	tagStore.Insert(models.Tag{EntityID: "e1", EntityType: "environment", Key: "name", Value: "staging"})
+	tagStore.Insert(models.Tag{EntityID: "e1", EntityType: "environment", Key: createdByJobTagKey, Value: "j0"})
+
+	jobStore := job_store.NewMemoryJobStore()
+	context := newCreateEnvironmentContext(t, tagStore, jobStore)
+
+	mockEnvironment := mock_logic.NewMockEnvironmentLogic(ctrl)
+	mockEnvironment.EXPECT().
+		CreateEnvironment(models.CreateEnvironmentRequest{EnvironmentName: "staging", OperatingSystem: "linux"}).
+		Return(&models.Environment{EnvironmentID: "e2"}, nil)
+
+	context.EnvironmentLogic = mockEnvironment
+
+	if err := CreateEnvironment(make(chan bool), context); err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := jobStore.SelectByID("j1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, job.Meta["environment_id"], "e2")
+
+	tags, err := tagStore.SelectByTypeAndID("environment", "e2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, len(tags.WithKey(createdByJobTagKey).WithValue("j1")), 1)
+}
@@ -1,11 +1,21 @@
 package job
 
 import (
+	"encoding/json"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/quintilesims/layer0/common/models"
 )
 
+var CreateLoadBalancerSteps = []Step{
+	{
+		Name:    "Create Load Balancer",
+		Timeout: time.Minute * 10,
+		Action:  CreateLoadBalancer,
+	},
+}
+
 var DeleteLoadBalancerSteps = []Step{
 	{
 		Name:    "Delete Load Balancer",
@@ -17,8 +27,59 @@ var DeleteLoadBalancerSteps = []Step{
 func DeleteLoadBalancer(quit chan bool, context *JobContext) error {
 	loadBalancerID := context.Request()
 
-	return runAndRetry(quit, time.Second*10, func() error {
+	return runAndRetry(quit, context.RetryPolicy, func() error {
 		log.Infof("Running Action: DeleteLoadBalancer on '%s'", loadBalancerID)
 		return context.LoadBalancerLogic.DeleteLoadBalancer(loadBalancerID)
 	})
 }
+
+// CreateLoadBalancer is not retried since creating a load balancer is not idempotent.
+// Like CreateEnvironment, a load balancer that a previous run of the job created but did not
+// record is adopted by the job's mark. The id of the new load balancer is stored in the job's meta as 'load_balancer_id'
+func CreateLoadBalancer(quit chan bool, context *JobContext) error {
+	var req models.CreateLoadBalancerRequest
+	if err := json.Unmarshal([]byte(context.Request()), &req); err != nil {
+		return err
+	}
+
+	loadBalancerID, ok, err := context.JobMeta("load_balancer_id")
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		log.Infof("Load balancer '%s' was already created as '%s'", req.LoadBalancerName, loadBalancerID)
+		return nil
+	}
+
+	tags := map[string]string{"name": req.LoadBalancerName, "environment_id": req.EnvironmentID}
+	loadBalancerID, ok, err = context.CreatedEntityID("load_balancer", tags)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		log.Infof("Load balancer '%s' was already created as '%s'", req.LoadBalancerName, loadBalancerID)
+	} else {
+		log.Infof("Running Action: CreateLoadBalancer '%s'", req.LoadBalancerName)
+		loadBalancer, err := context.LoadBalancerLogic.CreateLoadBalancer(req)
+		if err != nil {
+			return err
+		}
+
+		loadBalancerID = loadBalancer.LoadBalancerID
+		if err := runAndRetry(quit, context.RetryPolicy, func() error {
+			return context.MarkCreatedEntity("load_balancer", loadBalancerID)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return runAndRetry(quit, context.RetryPolicy, func() error {
+		if err := context.SetJobEntity("load_balancer", loadBalancerID); err != nil {
+			return err
+		}
+
+		return context.AddJobMeta("load_balancer_id", loadBalancerID)
+	})
+}
@@ -0,0 +1,68 @@
+package job
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/quintilesims/layer0/api/logic"
+	"github.com/quintilesims/layer0/api/logic/mock_logic"
+	"github.com/quintilesims/layer0/common/db/job_store"
+	"github.com/quintilesims/layer0/common/db/tag_store"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+func TestCreateLoadBalancerIsNotRepeated(t *testing.T) {
+	request, err := json.Marshal(models.CreateLoadBalancerRequest{LoadBalancerName: "api", EnvironmentID: "e1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		Meta map[string]string
+		Tags []models.Tag
+	}{
+		"Recorded in meta": {
+			Meta: map[string]string{"load_balancer_id": "l1"},
+		},
+		"Marked by the job": {
+			Tags: []models.Tag{
+				{EntityID: "l1", EntityType: "load_balancer", Key: "name", Value: "api"},
+				{EntityID: "l1", EntityType: "load_balancer", Key: "environment_id", Value: "e1"},
+				{EntityID: "l1", EntityType: "load_balancer", Key: createdByJobTagKey, Value: "j1"},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tagStore := tag_store.NewMemoryTagStore()
+			for _, tag := range tc.Tags {
+				tagStore.Insert(tag)
+			}
+
+			jobStore := job_store.NewMemoryJobStore()
+			jobStore.Insert(&models.Job{JobID: "j1", Meta: tc.Meta})
+
+			context := NewJobContext("j1", logic.NewLogic(tagStore, jobStore, nil, nil), string(request))
+
+			// the load balancer is not expected to be created again
+			context.LoadBalancerLogic = mock_logic.NewMockLoadBalancerLogic(ctrl)
+
+			if err := CreateLoadBalancer(make(chan bool), context); err != nil {
+				t.Fatal(err)
+			}
+
+			job, err := jobStore.SelectByID("j1")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertEqual(t, job.Meta["load_balancer_id"], "l1")
+		})
+	}
+}
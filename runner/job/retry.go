@@ -0,0 +1,154 @@
+package job
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/quintilesims/layer0/common/errors"
+)
+
+// RetryPolicy controls how the calls an action makes through runAndRetry are retried
+type RetryPolicy struct {
+	// MaxAttempts is the number of calls to make before giving up.
+	// Zero keeps retrying until the step is stopped, e.g. by its timeout
+	MaxAttempts int
+	// InitialDelay is the delay before the first retry. Each following delay
+	// is Multiplier times longer than the last, up to MaxDelay
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	// Jitter randomizes each delay by up to this fraction of it, e.g. 0.2 for +/- 20%
+	Jitter float64
+	// Retryable reports whether a call that failed with the error should be retried.
+	// If nil, every error is retried
+	Retryable func(error) bool
+}
+
+// DefaultRetryPolicy is used by steps that don't specify a RetryPolicy
+var DefaultRetryPolicy = RetryPolicy{
+	InitialDelay: time.Second * 2,
+	MaxDelay:     time.Second * 30,
+	Multiplier:   2,
+	Jitter:       0.2,
+	Retryable:    IsRetryable,
+}
+
+// IsRetryable returns false for errors that won't change on a retry:
+// invalid or missing input, missing entities and version conflicts.
+// Errors that have already been retried are not retried again
+func IsRetryable(err error) bool {
+	switch err := err.(type) {
+	case *RetryError:
+		return false
+	case *errors.ServerError:
+		switch err.Code {
+		case errors.InvalidJSON,
+			errors.InvalidDeployID,
+			errors.InvalidEnvironmentID,
+			errors.InvalidLoadBalancerID,
+			errors.InvalidServiceID,
+			errors.InvalidEntityType,
+			errors.MissingParameter,
+			errors.DeployDoesNotExist,
+			errors.EnvironmentDoesNotExist,
+			errors.LoadBalancerDoesNotExist,
+			errors.ServiceDoesNotExist,
+			errors.EntityConflict:
+			return false
+		}
+	}
+
+	return true
+}
+
+// delay returns how long to wait after the specified (1-based) attempt failed
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	delay := float64(p.InitialDelay) * math.Pow(p.Multiplier, float64(attempt-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (rand.Float64()*2 - 1)
+	}
+
+	return time.Duration(delay)
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable == nil {
+		return true
+	}
+
+	return p.Retryable(err)
+}
+
+// RetryError is returned when runAndRetry gives up. It holds the error from every attempt
+type RetryError struct {
+	Reason string
+	Errors []error
+}
+
+// Error lists each attempt's error. Consecutive attempts that failed
+// with the same error are grouped together, e.g. "attempts 2-5: throttled"
+func (e *RetryError) Error() string {
+	if len(e.Errors) == 0 {
+		return e.Reason
+	}
+
+	groups := []string{}
+	for start := 0; start < len(e.Errors); {
+		end := start
+		for end+1 < len(e.Errors) && e.Errors[end+1].Error() == e.Errors[start].Error() {
+			end++
+		}
+
+		attempts := fmt.Sprintf("attempt %d", start+1)
+		if end > start {
+			attempts = fmt.Sprintf("attempts %d-%d", start+1, end+1)
+		}
+
+		groups = append(groups, fmt.Sprintf("%s: %v", attempts, e.Errors[start]))
+		start = end + 1
+	}
+
+	return fmt.Sprintf("%s after %d attempt(s) (%s)", e.Reason, len(e.Errors), strings.Join(groups, "; "))
+}
+
+// runAndRetry calls fn until it succeeds, the policy gives up, or quit is closed
+func runAndRetry(quit chan bool, policy RetryPolicy, fn func() error) error {
+	errs := []error{}
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-quit:
+			return &RetryError{Reason: "Quit signalled", Errors: errs}
+		default:
+		}
+
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		log.Warningf("Attempt %d failed: %v", attempt, err)
+		errs = append(errs, err)
+
+		if !policy.retryable(err) {
+			return &RetryError{Reason: "Non-retryable error", Errors: errs}
+		}
+
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return &RetryError{Reason: "Giving up", Errors: errs}
+		}
+
+		select {
+		case <-time.After(policy.delay(attempt) * timeMultiplier):
+		case <-quit:
+			return &RetryError{Reason: "Quit signalled", Errors: errs}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package job
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quintilesims/layer0/api/logic"
+	"github.com/quintilesims/layer0/common/db/job_store"
+	"github.com/quintilesims/layer0/common/errors"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
+)
+
+func TestRunAndRetrySuccess(t *testing.T) {
+	calls := 0
+	fn := func() error {
+		calls++
+		if calls < 3 {
+			return fmt.Errorf("some error")
+		}
+
+		return nil
+	}
+
+	if err := runAndRetry(make(chan bool), RetryPolicy{MaxAttempts: 5}, fn); err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, calls, 3)
+}
+
+func TestRunAndRetryMaxAttempts(t *testing.T) {
+	calls := 0
+	fn := func() error {
+		calls++
+		return fmt.Errorf("error %d", calls)
+	}
+
+	err := runAndRetry(make(chan bool), RetryPolicy{MaxAttempts: 3}, fn)
+	retryErr, ok := err.(*RetryError)
+	if !ok {
+		t.Fatalf("Error was %#v, expected *RetryError", err)
+	}
+
+	testutils.AssertEqual(t, calls, 3)
+	testutils.AssertEqual(t, len(retryErr.Errors), 3)
+	testutils.AssertEqual(t, retryErr.Errors[0].Error(), "error 1")
+	testutils.AssertEqual(t, retryErr.Errors[2].Error(), "error 3")
+}
+
+func TestRunAndRetryNonRetryable(t *testing.T) {
+	calls := 0
+	fn := func() error {
+		calls++
+		return errors.Newf(errors.ServiceDoesNotExist, "service does not exist")
+	}
+
+	policy := RetryPolicy{Retryable: IsRetryable}
+	if err := runAndRetry(make(chan bool), policy, fn); err == nil {
+		t.Fatal("Error was nil!")
+	}
+
+	testutils.AssertEqual(t, calls, 1)
+}
+
+func TestRunAndRetryQuit(t *testing.T) {
+	quit := make(chan bool)
+	calls := 0
+	fn := func() error {
+		calls++
+		if calls == 2 {
+			close(quit)
+		}
+
+		return fmt.Errorf("some error")
+	}
+
+	err := runAndRetry(quit, RetryPolicy{}, fn)
+	retryErr, ok := err.(*RetryError)
+	if !ok {
+		t.Fatalf("Error was %#v, expected *RetryError", err)
+	}
+
+	testutils.AssertEqual(t, retryErr.Reason, "Quit signalled")
+	testutils.AssertEqual(t, len(retryErr.Errors), 2)
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{
+		InitialDelay: time.Second,
+		MaxDelay:     time.Second * 5,
+		Multiplier:   2,
+	}
+
+	testutils.AssertEqual(t, policy.delay(1), time.Second)
+	testutils.AssertEqual(t, policy.delay(2), time.Second*2)
+	testutils.AssertEqual(t, policy.delay(3), time.Second*4)
+	testutils.AssertEqual(t, policy.delay(4), time.Second*5)
+}
+
+func TestRetryPolicyDelayJitter(t *testing.T) {
+	policy := RetryPolicy{
+		InitialDelay: time.Second * 10,
+		Multiplier:   1,
+		Jitter:       0.5,
+	}
+
+	for i := 0; i < 100; i++ {
+		if delay := policy.delay(1); delay < time.Second*5 || delay > time.Second*15 {
+			t.Fatalf("Delay %v is outside of the jitter range", delay)
+		}
+	}
+}
+
+func TestRetryErrorGroupsAttempts(t *testing.T) {
+	err := &RetryError{
+		Reason: "Giving up",
+		Errors: []error{
+			fmt.Errorf("timeout"),
+			fmt.Errorf("throttled"),
+			fmt.Errorf("throttled"),
+			fmt.Errorf("throttled"),
+		},
+	}
+
+	expected := "Giving up after 4 attempt(s) (attempt 1: timeout; attempts 2-4: throttled)"
+	testutils.AssertEqual(t, err.Error(), expected)
+}
+
+func TestRunStepTimeoutReportsActionError(t *testing.T) {
+	runner := &JobRunner{
+		Steps: []Step{
+			{
+				Name:    "step",
+				Timeout: time.Millisecond * 10,
+				Action: func(quit chan bool, context *JobContext) error {
+					return runAndRetry(quit, context.RetryPolicy, func() error {
+						return fmt.Errorf("some error")
+					})
+				},
+			},
+		},
+		Logic:   logic.NewLogic(nil, job_store.NewMemoryJobStore(), nil, nil),
+		Context: &JobContext{},
+	}
+
+	runner.records = make([]models.JobStep, 1)
+	err := runner.runStep(0, runner.Context)
+	if err == nil {
+		t.Fatal("Error was nil!")
+	}
+
+	if !strings.Contains(err.Error(), "some error") {
+		t.Fatalf("Error '%v' does not contain the action's error", err)
+	}
+}
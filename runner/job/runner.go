@@ -2,6 +2,7 @@ package job
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
@@ -13,21 +14,59 @@ import (
 const (
 	JOB_LOAD_ATTEMPTS       = 10
 	JOB_LOAD_SLEEP_INTERVAL = time.Second * 5
+	JOB_STATUS_INTERVAL     = time.Second * 15
 )
 
 var timeMultiplier time.Duration = 1
 
+// ErrJobCancelled is returned by Run when the job is cancelled before it completes
+var ErrJobCancelled = fmt.Errorf("Job was cancelled")
+
 type JobRunner struct {
-	Logic   *logic.Logic
-	Context *JobContext
-	Steps   []Step
-	jobID   string
+	Logic      *logic.Logic
+	Context    *JobContext
+	Steps      []Step
+	jobID      string
+	cancelc    chan bool
+	cancelOnce sync.Once
+	// steps recorded by a previous run of the job
+	previous []models.JobStep
+	records  []models.JobStep
 }
 
 func NewJobRunner(logic *logic.Logic, jobID string) *JobRunner {
 	return &JobRunner{
-		jobID: jobID,
-		Logic: logic,
+		jobID:   jobID,
+		Logic:   logic,
+		cancelc: make(chan bool),
+	}
+}
+
+// Cancel stops the runner. The quit channel of the in-flight step is closed
+// and no further steps are run. It is safe to call Cancel more than once
+func (j *JobRunner) Cancel() {
+	j.cancelOnce.Do(func() { close(j.cancelc) })
+}
+
+// WatchStatus polls the job's status and cancels the runner once the job has been marked Cancelled
+func (j *JobRunner) WatchStatus(interval time.Duration) {
+	for {
+		select {
+		case <-j.cancelc:
+			return
+		case <-time.After(interval):
+			job, err := j.Logic.JobStore.SelectByID(j.jobID)
+			if err != nil {
+				log.Warningf("Failed to check status of job %s: %v", j.jobID, err)
+				continue
+			}
+
+			if types.JobStatus(job.JobStatus) == types.Cancelled {
+				log.Infof("Job '%s' was cancelled", j.jobID)
+				j.Cancel()
+				return
+			}
+		}
 	}
 }
 
@@ -54,11 +93,26 @@ func (j *JobRunner) Load() error {
 		j.Steps = DeleteTaskSteps
 	case types.CreateTaskJob:
 		j.Steps = CreateTaskSteps
+	case types.CreateEnvironmentJob:
+		j.Steps = CreateEnvironmentSteps
+	case types.CreateLoadBalancerJob:
+		j.Steps = CreateLoadBalancerSteps
+	case types.UpdateServiceJob:
+		j.Steps = UpdateServiceSteps
+	case types.ScaleServiceJob:
+		j.Steps = ScaleServiceSteps
+	case types.CreateServiceJob:
+		j.Steps = CreateServiceSteps
+	case types.WorkflowJob:
+		j.Steps = WorkflowSteps()
+	case types.CloneEnvironmentJob:
+		j.Steps = CloneEnvironmentSteps
 	default:
 		return fmt.Errorf("Unknown job type '%v'!", job.JobType)
 	}
 
 	j.Context = NewJobContext(j.jobID, j.Logic, job.Request)
+	j.previous = job.Steps
 	return nil
 }
 
@@ -81,14 +135,43 @@ func (j *JobRunner) tryLoadJob() (*models.Job, error) {
 }
 
 func (j *JobRunner) Run() error {
+	// a job that was cancelled while it was pending is not started
+	job, err := j.Logic.JobStore.SelectByID(j.jobID)
+	if err != nil {
+		return err
+	}
+
+	if types.JobStatus(job.JobStatus) == types.Cancelled {
+		log.Infof("Job '%s' was cancelled before it started", j.jobID)
+		return ErrJobCancelled
+	}
+
 	if err := j.MarkStatus(types.InProgress); err != nil {
 		return err
 	}
 
-	for _, step := range j.Steps {
+	j.initStepRecords()
+
+	for i, step := range j.Steps {
+		select {
+		case <-j.cancelc:
+			return j.markCancelled()
+		default:
+		}
+
+		if types.JobStatus(j.records[i].Status) == types.Completed {
+			log.Infof("Skipping completed step '%s'", step.Name)
+			continue
+		}
+
 		log.Infof("Running step '%s'", step.Name)
 
-		if err := j.runStep(step, j.Context); err != nil {
+		if err := j.runStep(i, j.Context); err != nil {
+			if err == ErrJobCancelled {
+				log.Infof("Cancelled during step '%s'", step.Name)
+				return j.markCancelled()
+			}
+
 			log.Errorf("Error on step '%s': %v", step.Name, err)
 
 			if err := j.MarkStatus(types.Error); err != nil {
@@ -99,10 +182,80 @@ func (j *JobRunner) Run() error {
 		}
 	}
 
-	return j.MarkStatus(types.Completed)
+	// the job may have been cancelled while its last step ran, so it is only
+	// marked Completed if it is still in progress
+	completed, err := j.Logic.JobStore.TransitionJobStatus(j.jobID, types.InProgress, types.Completed)
+	if err != nil {
+		return err
+	}
+
+	if !completed {
+		log.Infof("Job '%s' was cancelled before it completed", j.jobID)
+		return ErrJobCancelled
+	}
+
+	return nil
 }
 
-func (j *JobRunner) runStep(step Step, context *JobContext) error {
+// initStepRecords records each step as pending, except for steps that were completed
+// by a previous run of the job; those are skipped. Attempts and errors from previous runs are kept
+func (j *JobRunner) initStepRecords() {
+	previous := map[string]models.JobStep{}
+	for _, step := range j.previous {
+		previous[step.Name] = step
+	}
+
+	j.records = make([]models.JobStep, len(j.Steps))
+	for i, step := range j.Steps {
+		record := models.JobStep{
+			Name:   step.Name,
+			Status: int64(types.Pending),
+		}
+
+		if p, ok := previous[step.Name]; ok {
+			if types.JobStatus(p.Status) == types.Completed {
+				record = p
+			} else {
+				record.Attempts = p.Attempts
+				record.Error = p.Error
+			}
+		}
+
+		j.records[i] = record
+	}
+
+	j.saveStepRecords()
+}
+
+func (j *JobRunner) saveStepRecords() {
+	if err := j.Logic.JobStore.SetJobSteps(j.jobID, j.records); err != nil {
+		log.Errorf("Failed to record job steps: %v", err)
+	}
+}
+
+func (j *JobRunner) markCancelled() error {
+	if err := j.MarkStatus(types.Cancelled); err != nil {
+		log.Errorf("Failed to mark job status to Cancelled: %v", err)
+	}
+
+	return ErrJobCancelled
+}
+
+// runStep runs the i-th step and records its progress in the job
+func (j *JobRunner) runStep(i int, context *JobContext) error {
+	step := j.Steps[i]
+	record := &j.records[i]
+
+	record.Status = int64(types.InProgress)
+	record.Attempts++
+	record.TimeStarted = time.Now()
+	record.TimeFinished = time.Time{}
+	j.saveStepRecords()
+
+	if context != nil {
+		context.RetryPolicy = step.retryPolicy()
+	}
+
 	var err error
 	quitc := make(chan bool)
 	stepc := make(chan error)
@@ -112,9 +265,64 @@ func (j *JobRunner) runStep(step Step, context *JobContext) error {
 	case err = <-stepc:
 	case <-time.After(step.Timeout):
 		close(quitc)
-		<-stepc
 		err = fmt.Errorf("Timeout reached after %v", step.Timeout)
+
+		// report what the action was failing on when the timeout was reached
+		if actionErr := <-stepc; actionErr != nil {
+			err = fmt.Errorf("%v: %v", err, actionErr)
+		}
+	case <-j.cancelc:
+		close(quitc)
+		<-stepc
+		err = ErrJobCancelled
 	}
 
+	record.TimeFinished = time.Now()
+	switch {
+	case err == nil:
+		record.Status = int64(types.Completed)
+		record.Error = ""
+	case err == ErrJobCancelled:
+		record.Status = int64(types.Cancelled)
+	default:
+		record.Status = int64(types.Error)
+		record.Error = err.Error()
+	}
+
+	j.saveStepRecords()
 	return err
 }
+
+// NewRunFunc returns a logic.JobRunFunc that runs jobs inside the current process.
+// Closing the quit channel cancels the job's runner
+func NewRunFunc(lgc *logic.Logic) logic.JobRunFunc {
+	return func(jobID string, quit chan bool) error {
+		runner := NewJobRunner(lgc, jobID)
+		if err := runner.Load(); err != nil {
+			runner.MarkStatus(types.Error)
+			return err
+		}
+
+		done := make(chan bool)
+		defer close(done)
+
+		go func() {
+			select {
+			case <-quit:
+				runner.Cancel()
+			case <-done:
+			}
+		}()
+
+		if err := runner.Run(); err != nil {
+			if err == ErrJobCancelled {
+				return nil
+			}
+
+			runner.MarkStatus(types.Error)
+			return err
+		}
+
+		return nil
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/quintilesims/layer0/api/logic"
+	"github.com/quintilesims/layer0/common/db/job_store"
 	"github.com/quintilesims/layer0/common/db/job_store/mock_job_store"
 	"github.com/quintilesims/layer0/common/models"
 	"github.com/quintilesims/layer0/common/testutils"
@@ -19,6 +20,20 @@ func getStubbedLogic(ctrl *gomock.Controller) *logic.Logic {
 		UpdateJobStatus(gomock.Any(), gomock.Any()).
 		AnyTimes()
 
+	mockJobStore.EXPECT().
+		TransitionJobStatus(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(true, nil).
+		AnyTimes()
+
+	mockJobStore.EXPECT().
+		SetJobSteps(gomock.Any(), gomock.Any()).
+		AnyTimes()
+
+	mockJobStore.EXPECT().
+		SelectByID(gomock.Any()).
+		Return(&models.Job{}, nil).
+		AnyTimes()
+
 	return logic.NewLogic(nil, mockJobStore, nil, nil)
 }
 
@@ -101,6 +116,33 @@ func TestRunnerLoad(t *testing.T) {
 	testutils.RunTests(t, testCases)
 }
 
+func TestRunnerLoadJobTypes(t *testing.T) {
+	cases := map[types.JobType][]Step{
+		types.CreateEnvironmentJob:  CreateEnvironmentSteps,
+		types.CreateLoadBalancerJob: CreateLoadBalancerSteps,
+		types.UpdateServiceJob:      UpdateServiceSteps,
+		types.ScaleServiceJob:       ScaleServiceSteps,
+	}
+
+	for jobType, expected := range cases {
+		jobStore := job_store.NewMemoryJobStore()
+		jobStore.Insert(&models.Job{JobID: "some_job_id", JobType: int64(jobType)})
+
+		runner := NewJobRunner(logic.NewLogic(nil, jobStore, nil, nil), "some_job_id")
+		if err := runner.Load(); err != nil {
+			t.Fatalf("%s: %v", jobType.String(), err)
+		}
+
+		if len(runner.Steps) != len(expected) {
+			t.Fatalf("%s: expected %d steps, got %d", jobType.String(), len(expected), len(runner.Steps))
+		}
+
+		for i, step := range runner.Steps {
+			testutils.AssertEqual(t, step.Name, expected[i].Name)
+		}
+	}
+}
+
 func TestRunnerRun_StepExecution(t *testing.T) {
 	testCases := []testutils.TestCase{
 		{
@@ -158,6 +200,48 @@ func TestRunnerRun_StepExecution(t *testing.T) {
 				}
 			},
 		},
+		{
+			Name: "Should close quit channel and skip remaining steps when cancelled",
+			Setup: func(reporter *testutils.Reporter, ctrl *gomock.Controller) interface{} {
+				logic := getStubbedLogic(ctrl)
+				runner := NewJobRunner(logic, "")
+
+				runner.Steps = []Step{
+					{
+						Name:    "cancelled step",
+						Timeout: time.Second * 1,
+						Action: func(quit chan bool, c *JobContext) error {
+							runner.Cancel()
+
+							select {
+							case <-quit:
+								return nil
+							case <-time.After(time.Second * 1):
+								t.Errorf("quit channel was not closed")
+								return nil
+							}
+						},
+					},
+					{
+						Name:    "skipped step",
+						Timeout: time.Second * 1,
+						Action: func(chan bool, *JobContext) error {
+							t.Errorf("step ran after cancel")
+							return nil
+						},
+					},
+				}
+
+				return runner
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}) {
+				runner := target.(*JobRunner)
+
+				if err := runner.Run(); err != ErrJobCancelled {
+					reporter.Errorf("Expected ErrJobCancelled, got %v", err)
+				}
+			},
+		},
 		{
 			Name: "Should close quit channel after timeout",
 			Setup: func(reporter *testutils.Reporter, ctrl *gomock.Controller) interface{} {
@@ -198,6 +282,10 @@ func TestRunnerRun_JobStateManagement(t *testing.T) {
 			Name: "Should mark status to InProgress at start of Run",
 			Setup: func(reporter *testutils.Reporter, ctrl *gomock.Controller) interface{} {
 				mockJobStore := mock_job_store.NewMockJobStore(ctrl)
+				mockJobStore.EXPECT().SetJobSteps(gomock.Any(), gomock.Any()).AnyTimes()
+				mockJobStore.EXPECT().SelectByID("some_job_id").Return(&models.Job{}, nil).AnyTimes()
+
+				mockJobStore.EXPECT().TransitionJobStatus(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
 
 				gomock.InOrder(
 					mockJobStore.EXPECT().UpdateJobStatus("some_job_id", types.InProgress),
@@ -216,10 +304,12 @@ func TestRunnerRun_JobStateManagement(t *testing.T) {
 			Name: "Should mark status to Completed at end of Run without errors",
 			Setup: func(reporter *testutils.Reporter, ctrl *gomock.Controller) interface{} {
 				mockJobStore := mock_job_store.NewMockJobStore(ctrl)
+				mockJobStore.EXPECT().SetJobSteps(gomock.Any(), gomock.Any()).AnyTimes()
+				mockJobStore.EXPECT().SelectByID("some_job_id").Return(&models.Job{}, nil).AnyTimes()
 
 				gomock.InOrder(
-					mockJobStore.EXPECT().UpdateJobStatus(gomock.Any(), gomock.Not(types.Completed)).AnyTimes(),
-					mockJobStore.EXPECT().UpdateJobStatus("some_job_id", types.Completed),
+					mockJobStore.EXPECT().UpdateJobStatus("some_job_id", types.InProgress),
+					mockJobStore.EXPECT().TransitionJobStatus("some_job_id", types.InProgress, types.Completed).Return(true, nil),
 				)
 
 				mockLogic := logic.NewLogic(nil, mockJobStore, nil, nil)
@@ -234,6 +324,8 @@ func TestRunnerRun_JobStateManagement(t *testing.T) {
 			Name: "Should mark status to Error at the end of Run with errors",
 			Setup: func(reporter *testutils.Reporter, ctrl *gomock.Controller) interface{} {
 				mockJobStore := mock_job_store.NewMockJobStore(ctrl)
+				mockJobStore.EXPECT().SetJobSteps(gomock.Any(), gomock.Any()).AnyTimes()
+				mockJobStore.EXPECT().SelectByID("some_job_id").Return(&models.Job{}, nil).AnyTimes()
 
 				gomock.InOrder(
 					mockJobStore.EXPECT().UpdateJobStatus(gomock.Any(), gomock.Not(types.Error)).AnyTimes(),
@@ -251,7 +343,178 @@ func TestRunnerRun_JobStateManagement(t *testing.T) {
 				runner.Run()
 			},
 		},
+		{
+			Name: "Should mark status to Cancelled when cancelled",
+			Setup: func(reporter *testutils.Reporter, ctrl *gomock.Controller) interface{} {
+				mockJobStore := mock_job_store.NewMockJobStore(ctrl)
+				mockJobStore.EXPECT().SetJobSteps(gomock.Any(), gomock.Any()).AnyTimes()
+				mockJobStore.EXPECT().SelectByID("some_job_id").Return(&models.Job{}, nil).AnyTimes()
+
+				gomock.InOrder(
+					mockJobStore.EXPECT().UpdateJobStatus("some_job_id", types.InProgress),
+					mockJobStore.EXPECT().UpdateJobStatus("some_job_id", types.Cancelled),
+				)
+
+				mockLogic := logic.NewLogic(nil, mockJobStore, nil, nil)
+				runner := NewJobRunner(mockLogic, "some_job_id")
+
+				runner.Steps = []Step{stepWithError()}
+				runner.Cancel()
+				return runner
+			},
+			Run: func(reporter *testutils.Reporter, target interface{}) {
+				runner := target.(*JobRunner)
+				runner.Run()
+			},
+		},
 	}
 
 	testutils.RunTests(t, testCases)
 }
+
+func TestRunnerRun_CancelledWhilePending(t *testing.T) {
+	jobStore := job_store.NewMemoryJobStore()
+	jobStore.Insert(&models.Job{JobID: "some_job_id", JobStatus: int64(types.Cancelled)})
+
+	runner := NewJobRunner(logic.NewLogic(nil, jobStore, nil, nil), "some_job_id")
+	runner.Steps = []Step{
+		{
+			Name:   "step",
+			Action: func(chan bool, *JobContext) error { t.Fatal("Step was run"); return nil },
+		},
+	}
+
+	if err := runner.Run(); err != ErrJobCancelled {
+		t.Fatalf("Expected ErrJobCancelled, got: %v", err)
+	}
+
+	job, err := jobStore.SelectByID("some_job_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, job.JobStatus, int64(types.Cancelled))
+}
+
+func TestRunnerRun_CancelledDuringLastStep(t *testing.T) {
+	jobStore := job_store.NewMemoryJobStore()
+	jobStore.Insert(&models.Job{JobID: "some_job_id", JobStatus: int64(types.Pending)})
+
+	runner := NewJobRunner(logic.NewLogic(nil, jobStore, nil, nil), "some_job_id")
+	runner.Steps = []Step{
+		{
+			Name:    "step",
+			Timeout: time.Second,
+			Action: func(chan bool, *JobContext) error {
+				return jobStore.UpdateJobStatus("some_job_id", types.Cancelled)
+			},
+		},
+	}
+
+	if err := runner.Run(); err != ErrJobCancelled {
+		t.Fatalf("Expected ErrJobCancelled, got: %v", err)
+	}
+
+	job, err := jobStore.SelectByID("some_job_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, job.JobStatus, int64(types.Cancelled))
+}
+
+func TestRunnerWatchStatus(t *testing.T) {
+	jobStore := job_store.NewMemoryJobStore()
+	jobStore.Insert(&models.Job{JobID: "some_job_id", JobStatus: int64(types.Cancelled)})
+
+	runner := NewJobRunner(logic.NewLogic(nil, jobStore, nil, nil), "some_job_id")
+
+	done := make(chan bool)
+	go func() {
+		runner.WatchStatus(time.Millisecond)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("WatchStatus did not return after the job was cancelled")
+	}
+
+	select {
+	case <-runner.cancelc:
+	default:
+		t.Fatal("Runner was not cancelled")
+	}
+}
+
+func TestRunFuncLoadError(t *testing.T) {
+	jobStore := job_store.NewMemoryJobStore()
+	jobStore.Insert(&models.Job{JobID: "some_job_id", JobType: -1})
+
+	run := NewRunFunc(logic.NewLogic(nil, jobStore, nil, nil))
+	if err := run("some_job_id", make(chan bool)); err == nil {
+		t.Fatalf("Error was nil!")
+	}
+
+	job, err := jobStore.SelectByID("some_job_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, job.JobStatus, int64(types.Error))
+}
+
+func TestRunnerRun_StepRecords(t *testing.T) {
+	jobStore := job_store.NewMemoryJobStore()
+	jobStore.Insert(&models.Job{JobID: "some_job_id"})
+
+	runner := NewJobRunner(logic.NewLogic(nil, jobStore, nil, nil), "some_job_id")
+	runner.previous = []models.JobStep{
+		{Name: "step1", Status: int64(types.Completed), Attempts: 1},
+		{Name: "step2", Status: int64(types.Error), Attempts: 1, Error: "some error"},
+	}
+
+	runner.Steps = []Step{
+		{
+			Name:    "step1",
+			Timeout: time.Second * 1,
+			Action: func(chan bool, *JobContext) error {
+				t.Errorf("completed step was run again")
+				return nil
+			},
+		},
+		{
+			Name:    "step2",
+			Timeout: time.Second * 1,
+			Action:  func(chan bool, *JobContext) error { return nil },
+		},
+		stepWithError(),
+	}
+
+	if err := runner.Run(); err == nil {
+		t.Fatal("Error was nil!")
+	}
+
+	job, err := jobStore.SelectByID("some_job_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []models.JobStep{
+		{Name: "step1", Status: int64(types.Completed), Attempts: 1},
+		{Name: "step2", Status: int64(types.Completed), Attempts: 2},
+		{Name: "step with error", Status: int64(types.Error), Attempts: 1, Error: "some error"},
+	}
+
+	testutils.AssertEqual(t, len(job.Steps), len(expected))
+	for i, step := range job.Steps {
+		if i > 0 && (step.TimeStarted.IsZero() || step.TimeFinished.Before(step.TimeStarted)) {
+			t.Errorf("Step '%s' has invalid times: started %v, finished %v", step.Name, step.TimeStarted, step.TimeFinished)
+		}
+
+		step.TimeStarted = time.Time{}
+		step.TimeFinished = time.Time{}
+		testutils.AssertEqual(t, step, expected[i])
+	}
+}
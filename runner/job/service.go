@@ -1,11 +1,21 @@
 package job
 
 import (
+	"encoding/json"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/quintilesims/layer0/common/models"
 )
 
+var CreateServiceSteps = []Step{
+	{
+		Name:    "Create Service",
+		Timeout: time.Minute * 10,
+		Action:  CreateService,
+	},
+}
+
 var DeleteServiceSteps = []Step{
 	{
 		Name:    "Delete Service",
@@ -14,11 +24,127 @@ var DeleteServiceSteps = []Step{
 	},
 }
 
+var UpdateServiceSteps = []Step{
+	{
+		Name:    "Update Service",
+		Timeout: time.Minute * 10,
+		Action:  UpdateService,
+	},
+	{
+		Name:    "Scale Environment",
+		Timeout: time.Minute * 10,
+		Action:  ScaleServiceEnvironment,
+	},
+}
+
+var ScaleServiceSteps = []Step{
+	{
+		Name:    "Scale Service",
+		Timeout: time.Minute * 10,
+		Action:  ScaleService,
+	},
+	{
+		Name:    "Scale Environment",
+		Timeout: time.Minute * 10,
+		Action:  ScaleServiceEnvironment,
+	},
+}
+
+// CreateService is not retried since creating a service is not idempotent.
+// The id of the new service is stored in the job's meta as 'service_id'
+func CreateService(quit chan bool, context *JobContext) error {
+	var req models.CreateServiceRequest
+	if err := json.Unmarshal([]byte(context.Request()), &req); err != nil {
+		return err
+	}
+
+	log.Infof("Running Action: CreateService '%s'", req.ServiceName)
+	service, err := context.ServiceLogic.CreateService(req)
+	if err != nil {
+		return err
+	}
+
+	return runAndRetry(quit, context.RetryPolicy, func() error {
+		if err := context.SetJobEntity("service", service.ServiceID); err != nil {
+			return err
+		}
+
+		return context.AddJobMeta("service_id", service.ServiceID)
+	})
+}
+
 func DeleteService(quit chan bool, context *JobContext) error {
 	serviceID := context.Request()
 
-	return runAndRetry(quit, time.Second*10, func() error {
+	return runAndRetry(quit, context.RetryPolicy, func() error {
 		log.Infof("Running Action: DeleteService on '%s'", serviceID)
 		return context.ServiceLogic.DeleteService(serviceID)
 	})
 }
+
+// UpdateService is not retried: once the service's version has been incremented,
+// a second attempt would fail the version check
+func UpdateService(quit chan bool, context *JobContext) error {
+	var req models.UpdateServiceJobRequest
+	if err := json.Unmarshal([]byte(context.Request()), &req); err != nil {
+		return err
+	}
+
+	log.Infof("Running Action: UpdateService on '%s'", req.ServiceID)
+	updateServiceRequest := models.UpdateServiceRequest{DeployID: req.DeployID}
+	service, err := context.ServiceLogic.UpdateService(req.ServiceID, updateServiceRequest, req.Version)
+	if err != nil {
+		return err
+	}
+
+	if req.DesiredCount != nil {
+		log.Infof("Running Action: ScaleService on '%s'", req.ServiceID)
+		if _, err := context.ServiceLogic.ScaleService(req.ServiceID, int(*req.DesiredCount), service.Version); err != nil {
+			return err
+		}
+	}
+
+	return runAndRetry(quit, context.RetryPolicy, func() error {
+		return context.AddJobMeta("service_id", req.ServiceID)
+	})
+}
+
+// ScaleService is not retried for the same reason as UpdateService
+func ScaleService(quit chan bool, context *JobContext) error {
+	var req models.ScaleServiceJobRequest
+	if err := json.Unmarshal([]byte(context.Request()), &req); err != nil {
+		return err
+	}
+
+	log.Infof("Running Action: ScaleService on '%s'", req.ServiceID)
+	if _, err := context.ServiceLogic.ScaleService(req.ServiceID, int(req.DesiredCount), req.Version); err != nil {
+		return err
+	}
+
+	return runAndRetry(quit, context.RetryPolicy, func() error {
+		return context.AddJobMeta("service_id", req.ServiceID)
+	})
+}
+
+// ScaleServiceEnvironment schedules a run of the scaler on the service's environment right away,
+// instead of after the delay ServiceLogic schedules it with. The leader makes the run
+func ScaleServiceEnvironment(quit chan bool, context *JobContext) error {
+	// both UpdateServiceJobRequest and ScaleServiceJobRequest carry the service id
+	var req struct {
+		ServiceID string `json:"service_id"`
+	}
+
+	if err := json.Unmarshal([]byte(context.Request()), &req); err != nil {
+		return err
+	}
+
+	service, err := context.ServiceLogic.GetService(req.ServiceID)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Running Action: Schedule a scaler run for environment '%s'", service.EnvironmentID)
+	context.Logic.Scaler.ScheduleRun(service.EnvironmentID, 0)
+
+	return nil
+}
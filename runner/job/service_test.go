@@ -0,0 +1,52 @@
+package job
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/quintilesims/layer0/api/logic"
+	"github.com/quintilesims/layer0/api/logic/mock_logic"
+	"github.com/quintilesims/layer0/common/db/job_store"
+	"github.com/quintilesims/layer0/common/db/tag_store"
+	"github.com/quintilesims/layer0/common/models"
+)
+
+func TestUpdateServiceScalesWhenDesiredCountIsSet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock_logic.NewMockServiceLogic(ctrl)
+
+	// the scale is based on the version returned by the update
+	gomock.InOrder(
+		mockService.EXPECT().
+			UpdateService("s1", models.UpdateServiceRequest{DeployID: "d1"}, int64(4)).
+			Return(&models.Service{ServiceID: "s1", Version: 5}, nil),
+		mockService.EXPECT().
+			ScaleService("s1", 3, int64(5)).
+			Return(&models.Service{ServiceID: "s1", Version: 6}, nil),
+	)
+
+	desiredCount := int64(3)
+	request, err := json.Marshal(models.UpdateServiceJobRequest{
+		ServiceID:    "s1",
+		DeployID:     "d1",
+		DesiredCount: &desiredCount,
+		Version:      4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobStore := job_store.NewMemoryJobStore()
+	jobStore.Insert(&models.Job{JobID: "j1"})
+
+	lgc := logic.NewLogic(tag_store.NewMemoryTagStore(), jobStore, nil, nil)
+	context := NewJobContext("j1", lgc, string(request))
+	context.ServiceLogic = mockService
+
+	if err := UpdateService(make(chan bool), context); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,11 +1,9 @@
 package job
 
 import (
-	"fmt"
 	"sync"
 	"time"
 
-	log "github.com/Sirupsen/logrus"
 	"github.com/quintilesims/layer0/common/errors"
 )
 
@@ -15,6 +13,16 @@ type Step struct {
 	Name    string
 	Timeout time.Duration
 	Action  Action
+	// RetryPolicy is used by the action's calls to runAndRetry. If nil, DefaultRetryPolicy is used
+	RetryPolicy *RetryPolicy
+}
+
+func (s Step) retryPolicy() RetryPolicy {
+	if s.RetryPolicy == nil {
+		return DefaultRetryPolicy
+	}
+
+	return *s.RetryPolicy
 }
 
 // Fold takes a slice of Actions and runs them async
@@ -38,21 +46,3 @@ func Fold(actions ...Action) Action {
 		return errors.MultiError(errs)
 	}
 }
-
-func runAndRetry(quit chan bool, interval time.Duration, fn func() error) error {
-	for {
-		select {
-		default:
-			if err := fn(); err != nil {
-				// todo: track errors and return them when quit is called
-				log.Warning(err)
-				time.Sleep(interval)
-				continue
-			}
-
-			return nil
-		case <-quit:
-			return fmt.Errorf("Quit signalled")
-		}
-	}
-}
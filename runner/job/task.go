@@ -28,7 +28,7 @@ var CreateTaskSteps = []Step{
 func DeleteTask(quit chan bool, context *JobContext) error {
 	taskID := context.Request()
 
-	return runAndRetry(quit, time.Second*10, func() error {
+	return runAndRetry(quit, context.RetryPolicy, func() error {
 		log.Infof("Running Action: DeleteTask on '%s'", taskID)
 		return context.TaskLogic.DeleteTask(taskID)
 	})
@@ -40,7 +40,7 @@ func CreateTask(quit chan bool, context *JobContext) error {
 		return err
 	}
 
-	if err := runAndRetry(quit, time.Second*10, func() error {
+	if err := runAndRetry(quit, context.RetryPolicy, func() error {
 		log.Infof("Running Action: CreateTask '%s'", createTaskRequest.TaskName)
 		taskID, err := context.TaskLogic.CreateTask(createTaskRequest)
 		if err != nil {
@@ -48,7 +48,7 @@ func CreateTask(quit chan bool, context *JobContext) error {
 			return err
 		}
 
-		return runAndRetry(quit, time.Second*10, func() error {
+		return runAndRetry(quit, context.RetryPolicy, func() error {
 			key := fmt.Sprintf("task_id")
 			return context.AddJobMeta(key, taskID)
 		})
@@ -0,0 +1,313 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/quintilesims/layer0/api/logic"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/types"
+)
+
+// WorkflowSteps is a function rather than a variable like the steps of other jobs
+// since RunWorkflow loads the steps of its child jobs, which would be an initialization cycle
+func WorkflowSteps() []Step {
+	return []Step{
+		{
+			Name:    "Run Workflow",
+			Timeout: time.Hour * 2,
+			Action:  RunWorkflow,
+		},
+	}
+}
+
+// workflowResult is sent once the job of a workflow node has finished
+type workflowResult struct {
+	node string
+	meta map[string]string
+	err  error
+}
+
+// workflowRun tracks the nodes of a workflow as they are run
+type workflowRun struct {
+	context  *JobContext
+	jobLogic logic.JobLogic
+	nodes    []models.WorkflowNode
+	policy   types.WorkflowFailurePolicy
+	// status of each node that has started, finished or been skipped
+	status  map[string]types.JobStatus
+	errs    map[string]error
+	meta    map[string]map[string]string
+	runners map[string]*JobRunner
+	results chan workflowResult
+}
+
+// RunWorkflow runs each node of the workflow as a child job once the nodes it depends on have completed.
+// Nodes that are ready at the same time run concurrently.
+// The job created for each node is stored in the workflow job's meta under the node's name.
+// When a workflow is retried, nodes whose jobs completed in a previous run are not run again
+func RunWorkflow(quit chan bool, context *JobContext) error {
+	var req models.CreateWorkflowRequest
+	if err := json.Unmarshal([]byte(context.Request()), &req); err != nil {
+		return err
+	}
+
+	if err := logic.ValidateWorkflow(req); err != nil {
+		return err
+	}
+
+	policy, err := logic.WorkflowFailurePolicy(req)
+	if err != nil {
+		return err
+	}
+
+	w := &workflowRun{
+		context:  context,
+		jobLogic: logic.NewL0JobLogic(*context.Logic, context.TaskLogic),
+		nodes:    req.Nodes,
+		policy:   policy,
+		status:   map[string]types.JobStatus{},
+		errs:     map[string]error{},
+		meta:     map[string]map[string]string{},
+		runners:  map[string]*JobRunner{},
+		results:  make(chan workflowResult),
+	}
+
+	if err := w.loadCompletedNodes(); err != nil {
+		return err
+	}
+
+	return w.run(quit)
+}
+
+func (w *workflowRun) loadCompletedNodes() error {
+	job, err := w.context.Logic.JobStore.SelectByID(w.context.jobID)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range w.nodes {
+		childID, ok := job.Meta[node.Name]
+		if !ok {
+			continue
+		}
+
+		child, err := w.context.Logic.JobStore.SelectByID(childID)
+		if err != nil {
+			return err
+		}
+
+		if types.JobStatus(child.JobStatus) == types.Completed {
+			log.Infof("Skipping completed node '%s'", node.Name)
+			w.status[node.Name] = types.Completed
+			w.meta[node.Name] = child.Meta
+		}
+	}
+
+	return nil
+}
+
+func (w *workflowRun) run(quit chan bool) error {
+	running := 0
+	for {
+		for _, node := range w.nodes {
+			if !w.ready(node) {
+				continue
+			}
+
+			if err := w.start(quit, node); err != nil {
+				log.Errorf("Failed to start node '%s': %v", node.Name, err)
+				w.finish(node.Name, types.Error, err)
+				continue
+			}
+
+			running++
+		}
+
+		if running == 0 {
+			break
+		}
+
+		select {
+		case result := <-w.results:
+			running--
+			w.handleResult(result)
+		case <-quit:
+			log.Infof("Cancelling %d running node(s)", running)
+			for _, runner := range w.runners {
+				runner.Cancel()
+			}
+
+			for ; running > 0; running-- {
+				w.handleResult(<-w.results)
+			}
+
+			return fmt.Errorf("Workflow was stopped: %s", w.summary())
+		}
+	}
+
+	for _, node := range w.nodes {
+		if _, ok := w.status[node.Name]; !ok {
+			w.status[node.Name] = types.Cancelled
+		}
+	}
+
+	for _, node := range w.nodes {
+		if w.status[node.Name] != types.Completed {
+			return fmt.Errorf("Workflow did not complete: %s", w.summary())
+		}
+	}
+
+	return nil
+}
+
+// ready reports whether the node can be started. Nodes that depend on a node that
+// did not complete are skipped; after a failure, halting workflows skip every node that hasn't started
+func (w *workflowRun) ready(node models.WorkflowNode) bool {
+	if _, ok := w.status[node.Name]; ok {
+		return false
+	}
+
+	if w.policy == types.WorkflowHalt && w.failed() {
+		return false
+	}
+
+	for _, dependency := range node.DependsOn {
+		status, ok := w.status[dependency]
+		if !ok || status == types.InProgress {
+			return false
+		}
+
+		if status != types.Completed {
+			log.Infof("Skipping node '%s': node '%s' did not complete", node.Name, dependency)
+			w.status[node.Name] = types.Cancelled
+			return false
+		}
+	}
+
+	return true
+}
+
+func (w *workflowRun) failed() bool {
+	for _, status := range w.status {
+		if status == types.Error {
+			return true
+		}
+	}
+
+	return false
+}
+
+// start creates the node's child job and runs it in a new goroutine.
+// The result is sent to w.results once the child job has finished
+func (w *workflowRun) start(quit chan bool, node models.WorkflowNode) error {
+	jobType, err := types.ParseJobType(node.JobType)
+	if err != nil {
+		return err
+	}
+
+	raw, err := logic.ResolveWorkflowReferences(node.Request, w.meta)
+	if err != nil {
+		return err
+	}
+
+	request, err := logic.DecodeJobRequest(jobType, raw)
+	if err != nil {
+		return err
+	}
+
+	child, err := w.jobLogic.CreateChildJob(w.context.jobID, jobType, request)
+	if err != nil {
+		return err
+	}
+
+	if err := runAndRetry(quit, w.context.RetryPolicy, func() error {
+		return w.context.AddJobMeta(node.Name, child.JobID)
+	}); err != nil {
+		return err
+	}
+
+	log.Infof("Running node '%s' as job '%s'", node.Name, child.JobID)
+	runner := NewJobRunner(w.context.Logic, child.JobID)
+	w.runners[node.Name] = runner
+	w.status[node.Name] = types.InProgress
+
+	go func() {
+		err := runChildJob(runner)
+
+		var meta map[string]string
+		if job, e := w.context.Logic.JobStore.SelectByID(child.JobID); e == nil {
+			meta = job.Meta
+		}
+
+		w.results <- workflowResult{node: node.Name, meta: meta, err: err}
+	}()
+
+	return nil
+}
+
+// runChildJob runs the child job's steps. The runner also stops if the child job is cancelled directly
+func runChildJob(runner *JobRunner) error {
+	if err := runner.Load(); err != nil {
+		runner.MarkStatus(types.Error)
+		return err
+	}
+
+	go runner.WatchStatus(JOB_STATUS_INTERVAL)
+	defer runner.Cancel()
+
+	return runner.Run()
+}
+
+func (w *workflowRun) handleResult(result workflowResult) {
+	delete(w.runners, result.node)
+
+	switch result.err {
+	case nil:
+		log.Infof("Node '%s' completed", result.node)
+		w.meta[result.node] = result.meta
+		w.finish(result.node, types.Completed, nil)
+	case ErrJobCancelled:
+		log.Infof("Node '%s' was cancelled", result.node)
+		w.finish(result.node, types.Cancelled, nil)
+	default:
+		log.Errorf("Node '%s' failed: %v", result.node, result.err)
+		w.finish(result.node, types.Error, result.err)
+	}
+}
+
+func (w *workflowRun) finish(node string, status types.JobStatus, err error) {
+	w.status[node] = status
+	if err != nil {
+		w.errs[node] = err
+	}
+}
+
+// summary describes the nodes that did not complete, in the order they were defined
+func (w *workflowRun) summary() string {
+	failed := []string{}
+	skipped := []string{}
+	for _, node := range w.nodes {
+		switch w.status[node.Name] {
+		case types.Completed:
+		case types.Error:
+			failed = append(failed, fmt.Sprintf("'%s': %v", node.Name, w.errs[node.Name]))
+		default:
+			skipped = append(skipped, fmt.Sprintf("'%s'", node.Name))
+		}
+	}
+
+	parts := []string{}
+	if len(failed) > 0 {
+		parts = append(parts, fmt.Sprintf("failed %s", strings.Join(failed, "; ")))
+	}
+
+	if len(skipped) > 0 {
+		parts = append(parts, fmt.Sprintf("cancelled or skipped %s", strings.Join(skipped, ", ")))
+	}
+
+	return strings.Join(parts, "; ")
+}
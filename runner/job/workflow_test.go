@@ -0,0 +1,208 @@
+package job
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/quintilesims/layer0/api/backend/mock_backend"
+	"github.com/quintilesims/layer0/api/logic"
+	"github.com/quintilesims/layer0/api/scheduler/mock_scheduler"
+	"github.com/quintilesims/layer0/common/db/job_store"
+	"github.com/quintilesims/layer0/common/db/tag_store"
+	"github.com/quintilesims/layer0/common/models"
+	"github.com/quintilesims/layer0/common/testutils"
+	"github.com/quintilesims/layer0/common/types"
+)
+
+func newWorkflowLogic(ctrl *gomock.Controller) (*logic.Logic, *mock_backend.MockBackend) {
+	mockBackend := mock_backend.NewMockBackend(ctrl)
+	mockScaler := mock_scheduler.NewMockEnvironmentScaler(ctrl)
+	mockScaler.EXPECT().
+		ScheduleRun(gomock.Any(), gomock.Any()).
+		AnyTimes()
+
+	lgc := logic.NewLogic(tag_store.NewMemoryTagStore(), job_store.NewMemoryJobStore(), mockBackend, mockScaler)
+	return lgc, mockBackend
+}
+
+func runWorkflowJob(t *testing.T, lgc *logic.Logic, req models.CreateWorkflowRequest, meta map[string]string) (*models.Job, error) {
+	request, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lgc.JobStore.Insert(&models.Job{
+		JobID:   "w1",
+		JobType: int64(types.WorkflowJob),
+		Request: string(request),
+		Meta:    meta,
+	})
+
+	runner := NewJobRunner(lgc, "w1")
+	if err := runner.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	runErr := runner.Run()
+	job, err := lgc.JobStore.SelectByID("w1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return job, runErr
+}
+
+func serviceWorkflow() models.CreateWorkflowRequest {
+	return models.CreateWorkflowRequest{
+		Nodes: []models.WorkflowNode{
+			{
+				Name:    "svc",
+				JobType: "create service",
+				Request: json.RawMessage(`{"environment_id":"e1","deploy_id":"d1","service_name":"api"}`),
+			},
+			{
+				Name:      "del",
+				JobType:   "delete service",
+				Request:   json.RawMessage(`"${svc.service_id}"`),
+				DependsOn: []string{"svc"},
+			},
+		},
+	}
+}
+
+func TestRunWorkflow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lgc, mockBackend := newWorkflowLogic(ctrl)
+
+	gomock.InOrder(
+		mockBackend.EXPECT().
+			CreateService("api", "e1", "d1", "").
+			Return(&models.Service{ServiceID: "s1"}, nil),
+		mockBackend.EXPECT().
+			DeleteService("e1", "s1").
+			Return(nil),
+	)
+
+	job, err := runWorkflowJob(t, lgc, serviceWorkflow(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, job.JobStatus, int64(types.Completed))
+
+	for _, node := range []string{"svc", "del"} {
+		child, err := lgc.JobStore.SelectByID(job.Meta[node])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		testutils.AssertEqual(t, child.ParentJobID, "w1")
+		testutils.AssertEqual(t, child.JobStatus, int64(types.Completed))
+	}
+}
+
+func TestRunWorkflowSkipsCompletedNodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lgc, mockBackend := newWorkflowLogic(ctrl)
+	lgc.TagStore.Insert(models.Tag{EntityID: "s1", EntityType: "service", Key: "environment_id", Value: "e1"})
+	lgc.JobStore.Insert(&models.Job{
+		JobID:     "j1",
+		JobStatus: int64(types.Completed),
+		Meta:      map[string]string{"service_id": "s1"},
+	})
+
+	// the service was created by a previous run of the workflow
+	mockBackend.EXPECT().
+		DeleteService("e1", "s1").
+		Return(nil)
+
+	job, err := runWorkflowJob(t, lgc, serviceWorkflow(), map[string]string{"svc": "j1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, job.JobStatus, int64(types.Completed))
+	testutils.AssertEqual(t, job.Meta["svc"], "j1")
+}
+
+func TestRunWorkflowContinueOnFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lgc, mockBackend := newWorkflowLogic(ctrl)
+
+	mockBackend.EXPECT().
+		CreateService("api", "e1", "d1", "").
+		Return(&models.Service{ServiceID: "s1"}, nil)
+
+	req := models.CreateWorkflowRequest{
+		OnFailure: "continue",
+		Nodes: []models.WorkflowNode{
+			{
+				// fails since the request has no environment_id
+				Name:    "a",
+				JobType: "create service",
+				Request: json.RawMessage(`{"deploy_id":"d1","service_name":"api"}`),
+			},
+			{
+				Name:      "b",
+				JobType:   "delete service",
+				Request:   json.RawMessage(`"${a.service_id}"`),
+				DependsOn: []string{"a"},
+			},
+			{
+				Name:    "c",
+				JobType: "create service",
+				Request: json.RawMessage(`{"environment_id":"e1","deploy_id":"d1","service_name":"api"}`),
+			},
+		},
+	}
+
+	job, err := runWorkflowJob(t, lgc, req, nil)
+	if err == nil {
+		t.Fatal("Error was nil!")
+	}
+
+	if !strings.Contains(err.Error(), "failed 'a'") || !strings.Contains(err.Error(), "skipped 'b'") {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	testutils.AssertEqual(t, job.JobStatus, int64(types.Error))
+	testutils.AssertEqual(t, job.Meta["b"], "")
+
+	child, err := lgc.JobStore.SelectByID(job.Meta["c"])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertEqual(t, child.JobStatus, int64(types.Completed))
+}
+
+func TestWorkflowRunReady(t *testing.T) {
+	w := &workflowRun{
+		policy: types.WorkflowHalt,
+		status: map[string]types.JobStatus{
+			"a": types.Error,
+			"b": types.InProgress,
+		},
+	}
+
+	independent := models.WorkflowNode{Name: "c"}
+	waiting := models.WorkflowNode{Name: "d", DependsOn: []string{"b"}}
+	blocked := models.WorkflowNode{Name: "e", DependsOn: []string{"a"}}
+
+	// halting workflows don't start new nodes after a failure
+	testutils.AssertEqual(t, w.ready(independent), false)
+
+	w.policy = types.WorkflowContinue
+	testutils.AssertEqual(t, w.ready(independent), true)
+	testutils.AssertEqual(t, w.ready(waiting), false)
+	testutils.AssertEqual(t, w.ready(blocked), false)
+	testutils.AssertEqual(t, w.status["e"], types.Cancelled)
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/Sirupsen/logrus"
@@ -125,7 +127,23 @@ func Run(c *cli.Context) {
 		log.Fatal(err)
 	}
 
+	// stopping the runner's task (e.g. when the job is cancelled) sends SIGTERM
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	go func() {
+		sig := <-signals
+		log.Infof("Received %v, cancelling job", sig)
+		runner.Cancel()
+	}()
+
+	go runner.WatchStatus(job.JOB_STATUS_INTERVAL)
+
 	if err := runner.Run(); err != nil {
+		if err == job.ErrJobCancelled {
+			log.Info("Cancelled")
+			return
+		}
+
 		runner.MarkStatus(types.Error)
 		log.Fatal(err)
 	}
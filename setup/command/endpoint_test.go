@@ -68,6 +68,7 @@ func TestEndpointDev(t *testing.T) {
 			instance.OUTPUT_WINDOWS_SERVICE_AMI,
 			instance.OUTPUT_AWS_DYNAMO_TAG_TABLE,
 			instance.OUTPUT_AWS_DYNAMO_JOB_TABLE,
+			instance.OUTPUT_AWS_DYNAMO_LEASE_TABLE,
 			instance.OUTPUT_AWS_REGION,
 		}
 
@@ -19,5 +19,6 @@ const (
 	OUTPUT_WINDOWS_SERVICE_AMI         = "windows_service_ami"
 	OUTPUT_AWS_DYNAMO_TAG_TABLE        = "dynamo_tag_table"
 	OUTPUT_AWS_DYNAMO_JOB_TABLE        = "dynamo_job_table"
+	OUTPUT_AWS_DYNAMO_LEASE_TABLE      = "dynamo_lease_table"
 	OUTPUT_AWS_REGION                  = "region"
 )
@@ -21,6 +21,16 @@ resource "aws_s3_bucket" "mod" {
   region        = "${var.region}"
   force_destroy = true
   request_payer = "BucketOwner"
+
+  lifecycle_rule {
+    id      = "scaler_history"
+    prefix  = "scaler_history/"
+    enabled = true
+
+    expiration {
+      days = 30
+    }
+  }
 }
 
 resource "aws_s3_bucket_object" "dockercfg" {
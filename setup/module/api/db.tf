@@ -27,6 +27,65 @@ resource "aws_dynamodb_table" "jobs" {
     name = "JobID"
     type = "S"
   }
+
+  attribute {
+    name = "JobStatus"
+    type = "N"
+  }
+
+  attribute {
+    name = "JobType"
+    type = "N"
+  }
+
+  attribute {
+    name = "EntityType"
+    type = "S"
+  }
+
+  attribute {
+    name = "EntityID"
+    type = "S"
+  }
+
+  global_secondary_index {
+    name            = "JobStatusIndex"
+    hash_key        = "JobStatus"
+    read_capacity   = 5
+    write_capacity  = 10
+    projection_type = "ALL"
+  }
+
+  global_secondary_index {
+    name            = "JobTypeIndex"
+    hash_key        = "JobType"
+    read_capacity   = 5
+    write_capacity  = 10
+    projection_type = "ALL"
+  }
+
+  global_secondary_index {
+    name            = "EntityIndex"
+    hash_key        = "EntityType"
+    range_key       = "EntityID"
+    read_capacity   = 5
+    write_capacity  = 10
+    projection_type = "ALL"
+  }
+}
+
+# leases elect the api replica that runs the background workers
+resource "aws_dynamodb_table" "leases" {
+  name           = "l0-${var.name}-leases"
+  read_capacity  = 5
+  write_capacity = 5
+  hash_key       = "Name"
+  tags           = "${var.tags}"
+
+  attribute {
+    name = "Name"
+    type = "S"
+  }
 }
 
 resource "aws_appautoscaling_target" "tags_table_read_target" {
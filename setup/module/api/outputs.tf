@@ -53,3 +53,7 @@ output "dynamo_tag_table" {
 output "dynamo_job_table" {
   value = "${aws_dynamodb_table.jobs.id}"
 }
+
+output "dynamo_lease_table" {
+  value = "${aws_dynamodb_table.leases.id}"
+}
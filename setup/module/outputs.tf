@@ -66,6 +66,10 @@ output "dynamo_job_table" {
   value = "${module.api.dynamo_job_table}"
 }
 
+output "dynamo_lease_table" {
+  value = "${module.api.dynamo_lease_table}"
+}
+
 output "region" {
   value = "${var.region}"
 }
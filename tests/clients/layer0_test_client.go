@@ -1,10 +1,14 @@
 package clients
 
 import (
+	"time"
+
 	"github.com/quintilesims/layer0/cli/client"
 	"github.com/quintilesims/layer0/common/models"
 )
 
+const jobTimeout = time.Minute * 15
+
 type Tester interface {
 	Fatal(...interface{})
 }
@@ -34,12 +38,13 @@ func (l *Layer0TestClient) CreateTask(taskName, environmentID, deployID string,
 }
 
 func (l *Layer0TestClient) CreateEnvironment(name string) *models.Environment {
-	environment, err := l.Client.CreateEnvironment(name, "m3.medium", 0, nil, "linux", "")
+	jobID, err := l.Client.CreateEnvironment(name, "m3.medium", 0, nil, "linux", "", "", models.ScalerSettings{})
 	if err != nil {
 		l.T.Fatal(err)
 	}
 
-	return environment
+	job := l.waitForJob(jobID)
+	return l.GetEnvironment(job.Meta["environment_id"])
 }
 
 func (l *Layer0TestClient) CreateDeploy(name string, content []byte) *models.Deploy {
@@ -62,12 +67,13 @@ func (l *Layer0TestClient) CreateLoadBalancer(name, environmentID string) *model
 
 	ports := []models.Port{{HostPort: 80, ContainerPort: 80, Protocol: "http"}}
 
-	loadBalancer, err := l.Client.CreateLoadBalancer(name, environmentID, hc, ports, true)
+	jobID, err := l.Client.CreateLoadBalancer(name, environmentID, hc, ports, true, 60, true)
 	if err != nil {
 		l.T.Fatal(err)
 	}
 
-	return loadBalancer
+	job := l.waitForJob(jobID)
+	return l.GetLoadBalancer(job.Meta["load_balancer_id"])
 }
 
 func (l *Layer0TestClient) CreateService(name, environmentID, deployID, loadBalancerID string) *models.Service {
@@ -170,12 +176,13 @@ func (l *Layer0TestClient) ListTasks() []*models.TaskSummary {
 }
 
 func (l *Layer0TestClient) ScaleService(id string, scale int) *models.Service {
-	service, err := l.Client.ScaleService(id, scale)
+	jobID, err := l.Client.ScaleService(id, scale, client.AnyVersion)
 	if err != nil {
 		l.T.Fatal(err)
 	}
 
-	return service
+	l.waitForJob(jobID)
+	return l.GetService(id)
 }
 
 func (l *Layer0TestClient) CreateLink(id1, id2 string) {
@@ -189,3 +196,16 @@ func (l *Layer0TestClient) DeleteLink(id1, id2 string) {
 		l.T.Fatal(err)
 	}
 }
+
+func (l *Layer0TestClient) waitForJob(jobID string) *models.Job {
+	if err := l.Client.WaitForJob(jobID, jobTimeout); err != nil {
+		l.T.Fatal(err)
+	}
+
+	job, err := l.Client.GetJob(jobID)
+	if err != nil {
+		l.T.Fatal(err)
+	}
+
+	return job
+}